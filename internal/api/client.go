@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,6 +32,9 @@ type GeminiClientInterface interface {
 	SetModel(model models.Model)
 	IsClosed() bool
 
+	// Ping checks connectivity and authentication without sending a prompt
+	Ping(ctx context.Context) error
+
 	// Chat methods
 	StartChat(model ...models.Model) *ChatSession
 	StartChatWithOptions(opts ...ChatOption) *ChatSession
@@ -38,7 +42,7 @@ type GeminiClientInterface interface {
 	// Content generation
 	GenerateContent(prompt string, opts *GenerateOptions) (*models.ModelOutput, error)
 	UploadImage(filePath string) (*UploadedImage, error)
-	UploadFile(filePath string) (*UploadedFile, error)
+	UploadFile(filePath string, opts ...UploadOption) (*UploadedFile, error)
 	UploadText(content string, fileName string) (*UploadedFile, error)
 
 	// Image download
@@ -90,6 +94,13 @@ type GeminiClient struct {
 	idleTimer   *time.Timer   // Timer for auto-close
 	autoReInit  bool          // If true, auto re-initialize on next request after auto-close
 	initialized bool          // True after successful Init()
+	// Raw response retention (for debugging parsing issues)
+	retainRawResponse bool
+	// Retry of transient request failures
+	requestRetries int
+	requestBackoff time.Duration
+	// Resends of responses without content, inherited by chat sessions
+	retryOnEmpty int
 	// Injected dependencies for testing
 	refreshFunc  RefreshFunc
 	cookieLoader CookieLoader
@@ -123,6 +134,25 @@ func WithRefreshInterval(interval time.Duration) ClientOption {
 	}
 }
 
+// WithRequestRetry retries content generation up to retries times when it
+// fails transiently (network errors, HTTP 429 or 5xx). The wait before each
+// retry starts at backoff and doubles with every attempt.
+func WithRequestRetry(retries int, backoff time.Duration) ClientOption {
+	return func(c *GeminiClient) {
+		c.requestRetries = retries
+		c.requestBackoff = backoff
+	}
+}
+
+// WithRetryOnEmpty makes chat sessions started from the client resend a
+// prompt up to n times when the response has no text, thoughts or images.
+// n is capped at MaxRetryOnEmpty; 0 disables the retry.
+func WithRetryOnEmpty(n int) ClientOption {
+	return func(c *GeminiClient) {
+		c.retryOnEmpty = clampRetryOnEmpty(n)
+	}
+}
+
 // WithBrowserRefresh enables automatic cookie refresh from browser when auth fails
 // browserType can be "auto", "chrome", "firefox", "edge", "chromium", "opera"
 func WithBrowserRefresh(browserType browser.SupportedBrowser) ClientOption {
@@ -189,6 +219,15 @@ func WithAutoReInit(enabled bool) ClientOption {
 	}
 }
 
+// WithRawResponse makes the client keep the raw body of each generate
+// response on ModelOutput.RawResponse, bounded to MaxRawResponseSize.
+// This is useful when debugging response parsing.
+func WithRawResponse(enabled bool) ClientOption {
+	return func(c *GeminiClient) {
+		c.retainRawResponse = enabled
+	}
+}
+
 // CookieLoader is a function type for loading cookies (for dependency injection)
 type CookieLoader func() (*config.Cookies, error)
 
@@ -456,8 +495,9 @@ func (c *GeminiClient) StartChat(model ...models.Model) *ChatSession {
 	}
 
 	return &ChatSession{
-		client: c,
-		model:  m,
+		client:       c,
+		model:        m,
+		retryOnEmpty: c.retryOnEmpty,
 	}
 }
 
@@ -468,6 +508,61 @@ func (c *GeminiClient) IsBrowserRefreshEnabled() bool {
 	return c.browserRefresh
 }
 
+// ClientInfo describes the active account credentials and refresh settings.
+// Secret values are masked, so it is safe to display.
+type ClientInfo struct {
+	PSID               string        // Masked __Secure-1PSID, empty if unset
+	HasPSIDTS          bool          // Whether __Secure-1PSIDTS is set
+	HasAccessToken     bool          // Whether an access token was obtained
+	AutoRefresh        bool          // Background cookie rotation enabled
+	RefreshInterval    time.Duration // Cookie rotation interval
+	LastRefresh        time.Time     // Last successful rotation, zero if none
+	BrowserRefresh     bool          // Browser cookie refresh enabled
+	BrowserRefreshType string        // Browser used for refresh (e.g. "auto")
+	LastBrowserRefresh time.Time     // Last browser refresh, zero if none
+	RequestRetries     int           // Retries of transient request failures
+	RequestBackoff     time.Duration // Initial wait between retries
+}
+
+// ClientInfo returns a snapshot of the client's credentials and refresh
+// settings with secrets masked
+func (c *GeminiClient) ClientInfo() ClientInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info := ClientInfo{
+		HasAccessToken:     c.accessToken != "",
+		AutoRefresh:        c.autoRefresh,
+		RefreshInterval:    c.refreshInterval,
+		BrowserRefresh:     c.browserRefresh,
+		BrowserRefreshType: string(c.browserRefreshType),
+		LastBrowserRefresh: c.lastBrowserRefresh,
+		RequestRetries:     c.requestRetries,
+		RequestBackoff:     c.requestBackoff,
+	}
+	if c.cookies != nil {
+		psid, psidts := c.cookies.Snapshot()
+		info.PSID = MaskSecret(psid)
+		info.HasPSIDTS = psidts != ""
+	}
+	if c.rotator != nil {
+		info.LastRefresh = c.rotator.LastRotation()
+	}
+	return info
+}
+
+// MaskSecret masks a secret for display, keeping only the first and last
+// four characters of long values. Short values are fully masked.
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if len(secret) < 16 {
+		return strings.Repeat("*", 8)
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
+
 // attemptInitialAuth tries to authenticate the client by:
 // 1. First, trying to load cookies from disk (if cookies are nil)
 // 2. If that fails or cookies are invalid, trying browser extraction (if enabled)
@@ -640,8 +735,9 @@ func WithGemID(gemID string) ChatOption {
 // StartChatWithOptions cria uma nova sessão de chat com opções
 func (c *GeminiClient) StartChatWithOptions(opts ...ChatOption) *ChatSession {
 	session := &ChatSession{
-		client: c,
-		model:  c.GetModel(),
+		client:       c,
+		model:        c.GetModel(),
+		retryOnEmpty: c.retryOnEmpty,
 	}
 	for _, opt := range opts {
 		opt(session)
@@ -2163,3 +2163,53 @@ func TestGeminiClient_WithRefreshFunc_NilFunc(t *testing.T) {
 		t.Error("refreshFunc should be nil when set to nil")
 	}
 }
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string
+		want   string
+	}{
+		{"empty", "", ""},
+		{"short value fully masked", "abc123", "********"},
+		{"long value keeps ends", "g.a000abcdefghijklmnopqrstuvwxyz9876", "g.a0…9876"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskSecret(tt.secret); got != tt.want {
+				t.Errorf("MaskSecret(%q) = %q, want %q", tt.secret, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeminiClient_ClientInfo(t *testing.T) {
+	psid := "g.a000abcdefghijklmnopqrstuvwxyz9876"
+	client, err := NewClient(
+		&config.Cookies{Secure1PSID: psid, Secure1PSIDTS: "psidts-value"},
+		WithRefreshInterval(5*time.Minute),
+		WithBrowserRefresh(browser.BrowserChrome),
+	)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.accessToken = "token"
+
+	info := client.ClientInfo()
+	if info.PSID != "g.a0…9876" {
+		t.Errorf("PSID = %q, want masked value", info.PSID)
+	}
+	if !info.HasPSIDTS || !info.HasAccessToken {
+		t.Errorf("expected PSIDTS and token to be reported as set: %+v", info)
+	}
+	if !info.AutoRefresh || info.RefreshInterval != 5*time.Minute {
+		t.Errorf("unexpected refresh settings: %+v", info)
+	}
+	if !info.BrowserRefresh || info.BrowserRefreshType != string(browser.BrowserChrome) {
+		t.Errorf("unexpected browser refresh settings: %+v", info)
+	}
+	if !info.LastRefresh.IsZero() {
+		t.Errorf("LastRefresh = %v, want zero before any rotation", info.LastRefresh)
+	}
+}
@@ -1,8 +1,12 @@
 package api
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -25,6 +29,41 @@ type ImageDownloadOptions struct {
 	Filename string
 	// FullSize downloads the image at maximum resolution (only for GeneratedImage)
 	FullSize bool
+	// SkipUnsupported skips images whose content type is not a standard
+	// format (PNG, JPEG, GIF or WebP) instead of saving them. Skipped images
+	// are reported with a *SkippedImagesError.
+	SkipUnsupported bool
+}
+
+// supportedImageTypes are the standard image content types, with the file
+// extension used when saving them
+var supportedImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// SkippedImage describes an image that was not saved because its content
+// type is not a standard image format
+type SkippedImage struct {
+	URL         string
+	ContentType string
+	Reason      string
+}
+
+// SkippedImagesError reports images skipped by a download with
+// SkipUnsupported set. Images that were saved are returned alongside it.
+type SkippedImagesError struct {
+	Skipped []SkippedImage
+}
+
+// Error implements the error interface
+func (e *SkippedImagesError) Error() string {
+	if len(e.Skipped) == 1 {
+		return fmt.Sprintf("skipped image '%s': %s", e.Skipped[0].URL, e.Skipped[0].Reason)
+	}
+	return fmt.Sprintf("skipped %d images with unsupported types", len(e.Skipped))
 }
 
 // DefaultDownloadOptions returns the default download options
@@ -98,10 +137,22 @@ func (c *GeminiClient) downloadImageURL(url, title string, opts ImageDownloadOpt
 		return "", apierrors.NewDownloadErrorWithStatus(url, resp.StatusCode)
 	}
 
-	// Validate content type
-	contentType := resp.Header.Get("Content-Type")
-	if !strings.Contains(contentType, "image") {
-		return "", apierrors.NewDownloadError("response is not an image: "+contentType, url)
+	// Validate content type, sniffing the data when the header is missing,
+	// generic or non-standard
+	body := bufio.NewReader(resp.Body)
+	head, _ := body.Peek(512)
+	contentType, supported := resolveImageContentType(resp.Header.Get("Content-Type"), head)
+	if !supported {
+		if !strings.Contains(contentType, "image") {
+			return "", apierrors.NewDownloadError("response is not an image: "+contentType, url)
+		}
+		if opts.SkipUnsupported {
+			return "", &SkippedImagesError{Skipped: []SkippedImage{{
+				URL:         url,
+				ContentType: contentType,
+				Reason:      "unsupported image type " + contentType,
+			}}}
+		}
 	}
 
 	// Determine filename
@@ -126,7 +177,7 @@ func (c *GeminiClient) downloadImageURL(url, title string, opts ImageDownloadOpt
 		}
 	}()
 
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
+	if _, err := io.Copy(tmpFile, body); err != nil {
 		return "", apierrors.NewDownloadError("failed to save file: "+err.Error(), url)
 	}
 	if err := tmpFile.Close(); err != nil {
@@ -148,6 +199,48 @@ func (c *GeminiClient) downloadImageURL(url, title string, opts ImageDownloadOpt
 	return absPath, nil
 }
 
+// resolveImageContentType determines the type of a downloaded image from its
+// Content-Type header and first bytes. A standard type in the header is used
+// as-is; otherwise the data is sniffed, so mislabelled standard images (e.g.
+// served as application/octet-stream) are still recognized. supported is
+// false if neither names a standard image type, in which case the header
+// media type is returned.
+func resolveImageContentType(header string, head []byte) (contentType string, supported bool) {
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(header))
+	}
+	if _, ok := supportedImageTypes[mediaType]; ok {
+		return mediaType, true
+	}
+	if sniffed := http.DetectContentType(head); supportedImageTypes[sniffed] != "" {
+		return sniffed, true
+	}
+	return mediaType, false
+}
+
+// finishImageDownloads combines the outcome of downloading several images.
+// The last error is returned only if nothing was saved; skipped images are
+// reported with a *SkippedImagesError alongside the saved paths.
+func finishImageDownloads(paths []string, skipped []SkippedImage, lastError error) ([]string, error) {
+	if len(paths) == 0 && lastError != nil {
+		return nil, lastError
+	}
+	if len(skipped) > 0 {
+		return paths, &SkippedImagesError{Skipped: skipped}
+	}
+	return paths, nil
+}
+
+// skippedImages returns the images an error reports as skipped, if any
+func skippedImages(err error) ([]SkippedImage, bool) {
+	var skipErr *SkippedImagesError
+	if errors.As(err, &skipErr) {
+		return skipErr.Skipped, true
+	}
+	return nil, false
+}
+
 // generateFilename creates a filename based on URL, title, and content type
 func generateFilename(url, title, contentType string) string {
 	// Determine extension from content type
@@ -198,7 +291,9 @@ type DownloadResult struct {
 }
 
 // DownloadAllImages downloads all images from a ModelOutput
-// Returns a slice of paths for successfully downloaded images
+// Returns a slice of paths for successfully downloaded images. With
+// opts.SkipUnsupported, skipped images are reported with a
+// *SkippedImagesError alongside the paths.
 func (c *GeminiClient) DownloadAllImages(output *models.ModelOutput, opts ImageDownloadOptions) ([]string, error) {
 	if output == nil {
 		return nil, nil
@@ -210,6 +305,7 @@ func (c *GeminiClient) DownloadAllImages(output *models.ModelOutput, opts ImageD
 	}
 
 	var paths []string
+	var skipped []SkippedImage
 	var lastError error
 
 	// Download web images
@@ -221,6 +317,10 @@ func (c *GeminiClient) DownloadAllImages(output *models.ModelOutput, opts ImageD
 		}
 
 		path, err := c.DownloadImage(img, imgOpts)
+		if skip, ok := skippedImages(err); ok {
+			skipped = append(skipped, skip...)
+			continue
+		}
 		if err != nil {
 			lastError = err
 			continue
@@ -241,6 +341,10 @@ func (c *GeminiClient) DownloadAllImages(output *models.ModelOutput, opts ImageD
 		}
 
 		path, err := c.DownloadGeneratedImage(img, imgOpts)
+		if skip, ok := skippedImages(err); ok {
+			skipped = append(skipped, skip...)
+			continue
+		}
 		if err != nil {
 			lastError = err
 			continue
@@ -254,15 +358,13 @@ func (c *GeminiClient) DownloadAllImages(output *models.ModelOutput, opts ImageD
 	}
 
 	// Return paths even if some failed, report last error
-	if len(paths) == 0 && lastError != nil {
-		return nil, lastError
-	}
-
-	return paths, nil
+	return finishImageDownloads(paths, skipped, lastError)
 }
 
 // DownloadSelectedImages downloads specific images by their indices
 // indices refers to the combined list (WebImages first, then GeneratedImages)
+// With opts.SkipUnsupported, skipped images are reported with a
+// *SkippedImagesError alongside the paths.
 func (c *GeminiClient) DownloadSelectedImages(output *models.ModelOutput, indices []int, opts ImageDownloadOptions) ([]string, error) {
 	if output == nil {
 		return nil, nil
@@ -277,6 +379,7 @@ func (c *GeminiClient) DownloadSelectedImages(output *models.ModelOutput, indice
 	totalImages := webCount + len(candidate.GeneratedImages)
 
 	var paths []string
+	var skipped []SkippedImage
 	var lastError error
 
 	for _, idx := range indices {
@@ -296,6 +399,10 @@ func (c *GeminiClient) DownloadSelectedImages(output *models.ModelOutput, indice
 			path, err = c.DownloadGeneratedImage(candidate.GeneratedImages[genIdx], opts)
 		}
 
+		if skip, ok := skippedImages(err); ok {
+			skipped = append(skipped, skip...)
+			continue
+		}
 		if err != nil {
 			lastError = err
 			continue
@@ -303,9 +410,5 @@ func (c *GeminiClient) DownloadSelectedImages(output *models.ModelOutput, indice
 		paths = append(paths, path)
 	}
 
-	if len(paths) == 0 && lastError != nil {
-		return nil, lastError
-	}
-
-	return paths, nil
+	return finishImageDownloads(paths, skipped, lastError)
 }
@@ -1250,3 +1250,154 @@ func TestDownload_DifferentImageFormats(t *testing.T) {
 		})
 	}
 }
+
+// ============================================================================
+// Content type handling
+// ============================================================================
+
+// contentTypeMockClient serves each URL with the given content type and data
+func contentTypeMockClient(responses map[string]struct {
+	contentType string
+	data        []byte
+}) *DynamicMockHttpClient {
+	return &DynamicMockHttpClient{
+		DoFunc: func(req *fhttp.Request) (*fhttp.Response, error) {
+			resp := responses[req.URL.String()]
+			header := make(fhttp.Header)
+			header.Set("Content-Type", resp.contentType)
+			return &fhttp.Response{
+				StatusCode: 200,
+				Body:       NewMockResponseBody(resp.data),
+				Header:     header,
+			}, nil
+		},
+	}
+}
+
+func TestDownloadImage_SkipUnsupported(t *testing.T) {
+	tempDir := t.TempDir()
+	client := createTestDownloadClient(t, contentTypeMockClient(map[string]struct {
+		contentType string
+		data        []byte
+	}{
+		"http://example.com/photo": {"image/heic", []byte("\x00\x00\x00\x18ftypheic")},
+	}))
+	defer client.Close()
+
+	opts := ImageDownloadOptions{Directory: tempDir, SkipUnsupported: true}
+	path, err := client.DownloadImage(models.WebImage{URL: "http://example.com/photo"}, opts)
+
+	var skipErr *SkippedImagesError
+	if !errors.As(err, &skipErr) {
+		t.Fatalf("DownloadImage() error = %v, want *SkippedImagesError", err)
+	}
+	if path != "" {
+		t.Errorf("DownloadImage() path = %q, want empty", path)
+	}
+	if len(skipErr.Skipped) != 1 {
+		t.Fatalf("skipped %d images, want 1", len(skipErr.Skipped))
+	}
+	skipped := skipErr.Skipped[0]
+	if skipped.URL != "http://example.com/photo" || skipped.ContentType != "image/heic" ||
+		!strings.Contains(skipped.Reason, "image/heic") {
+		t.Errorf("unexpected skipped image: %+v", skipped)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("skipped image should not be written, found %d file(s)", len(entries))
+	}
+}
+
+func TestDownloadImage_UnsupportedSavedWithoutSkip(t *testing.T) {
+	tempDir := t.TempDir()
+	client := createTestDownloadClient(t, contentTypeMockClient(map[string]struct {
+		contentType string
+		data        []byte
+	}{
+		"http://example.com/photo": {"image/heic", []byte("heic data")},
+	}))
+	defer client.Close()
+
+	path, err := client.DownloadImage(models.WebImage{URL: "http://example.com/photo"}, ImageDownloadOptions{Directory: tempDir})
+	if err != nil {
+		t.Fatalf("DownloadImage() error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("image should be saved when SkipUnsupported is off: %v", err)
+	}
+}
+
+func TestDownloadImage_SniffsMislabelledImage(t *testing.T) {
+	tempDir := t.TempDir()
+	client := createTestDownloadClient(t, contentTypeMockClient(map[string]struct {
+		contentType string
+		data        []byte
+	}{
+		"http://example.com/photo": {"application/octet-stream", minimalPNG},
+	}))
+	defer client.Close()
+
+	opts := ImageDownloadOptions{Directory: tempDir, SkipUnsupported: true}
+	path, err := client.DownloadImage(models.WebImage{URL: "http://example.com/photo"}, opts)
+	if err != nil {
+		t.Fatalf("DownloadImage() error = %v", err)
+	}
+	if !strings.HasSuffix(path, ".png") {
+		t.Errorf("DownloadImage() path = %q, want .png from sniffed content", path)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if len(content) != len(minimalPNG) {
+		t.Errorf("File size = %d, want %d", len(content), len(minimalPNG))
+	}
+}
+
+func TestDownloadSelectedImages_SkipUnsupported(t *testing.T) {
+	tempDir := t.TempDir()
+	client := createTestDownloadClient(t, contentTypeMockClient(map[string]struct {
+		contentType string
+		data        []byte
+	}{
+		"http://example.com/ok":   {"image/png", minimalPNG},
+		"http://example.com/tiff": {"image/tiff", []byte("II*\x00")},
+	}))
+	defer client.Close()
+
+	output := &models.ModelOutput{
+		Candidates: []models.Candidate{{
+			WebImages: []models.WebImage{
+				{URL: "http://example.com/ok"},
+				{URL: "http://example.com/tiff"},
+			},
+		}},
+	}
+
+	opts := ImageDownloadOptions{Directory: tempDir, SkipUnsupported: true}
+	paths, err := client.DownloadSelectedImages(output, []int{0, 1}, opts)
+
+	var skipErr *SkippedImagesError
+	if !errors.As(err, &skipErr) {
+		t.Fatalf("DownloadSelectedImages() error = %v, want *SkippedImagesError", err)
+	}
+	if len(paths) != 1 {
+		t.Errorf("DownloadSelectedImages() returned %d paths, want 1", len(paths))
+	}
+	if len(skipErr.Skipped) != 1 || skipErr.Skipped[0].URL != "http://example.com/tiff" ||
+		!strings.Contains(skipErr.Skipped[0].Reason, "image/tiff") {
+		t.Errorf("unexpected skipped images: %+v", skipErr.Skipped)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the supported image on disk, found %d file(s)", len(entries))
+	}
+}
@@ -3,10 +3,14 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/url"
+	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	http "github.com/bogdanfinn/fhttp"
 	"github.com/tidwall/gjson"
@@ -21,8 +25,20 @@ type GenerateOptions struct {
 	Metadata []string        // [cid, rid, rcid] for chat context
 	Files    []*UploadedFile // Files to include in the prompt (images, text, etc.)
 	GemID    string          // ID do gem a usar (server-side persona)
+
+	// IncludeThoughts requests (true) or suppresses (false) the thinking
+	// output. Nil leaves the model default. Only sent for models that
+	// support thoughts, and only when ThoughtsFieldEnabled.
+	IncludeThoughts *bool
 }
 
+// retrySleep waits between request retries (replaced in tests)
+var retrySleep = time.Sleep
+
+// errStreamInterrupted reports that the response stream was cut off before
+// the end-of-stream marker arrived (e.g. a dropped connection).
+var errStreamInterrupted = errors.New("response stream interrupted")
+
 // GenerateContent sends a prompt to Gemini and returns the response
 func (c *GeminiClient) GenerateContent(prompt string, opts *GenerateOptions) (*models.ModelOutput, error) {
 	// Ensure client is running (may re-init if auto-closed)
@@ -35,6 +51,21 @@ func (c *GeminiClient) GenerateContent(prompt string, opts *GenerateOptions) (*m
 
 	result, err := c.doGenerateContent(prompt, opts)
 
+	// A stream that dropped mid-way is retried once from the start. The body
+	// is buffered before parsing, so no partial content has reached the
+	// caller yet and restarting cannot duplicate output.
+	if err != nil && errors.Is(err, errStreamInterrupted) {
+		result, err = c.doGenerateContent(prompt, opts)
+	}
+
+	// Transient failures are retried with exponential backoff if configured
+	backoff := c.requestBackoff
+	for attempt := 0; err != nil && attempt < c.requestRetries && isRetryableError(err); attempt++ {
+		retrySleep(backoff)
+		backoff *= 2
+		result, err = c.doGenerateContent(prompt, opts)
+	}
+
 	// If auth error and browser refresh is enabled, try to refresh and retry
 	if err != nil && c.IsBrowserRefreshEnabled() && isAuthError(err) {
 		// Use injected refresh function if available (for testing)
@@ -64,6 +95,17 @@ func isAuthError(err error) bool {
 	return apierrors.IsAuthError(err)
 }
 
+// isRetryableError reports whether a failed request may succeed if sent
+// again: network errors, timeouts, rate limiting (429) and server errors.
+// Usage limits and auth errors are not retried.
+func isRetryableError(err error) bool {
+	if apierrors.IsNetworkError(err) || apierrors.IsTimeoutError(err) {
+		return true
+	}
+	status := apierrors.GetHTTPStatus(err)
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
 // doGenerateContent performs the actual content generation request
 func (c *GeminiClient) doGenerateContent(prompt string, opts *GenerateOptions) (*models.ModelOutput, error) {
 	if prompt == "" {
@@ -78,6 +120,7 @@ func (c *GeminiClient) doGenerateContent(prompt string, opts *GenerateOptions) (
 	var metadata []string
 	var files []*UploadedFile
 	var gemID string
+	var includeThoughts *bool
 
 	if opts != nil {
 		if opts.Model.Name != "" {
@@ -86,10 +129,14 @@ func (c *GeminiClient) doGenerateContent(prompt string, opts *GenerateOptions) (
 		metadata = opts.Metadata
 		files = opts.Files
 		gemID = opts.GemID
+		includeThoughts = opts.IncludeThoughts
+	}
+	if !model.SupportsThoughts || !ThoughtsFieldEnabled() {
+		includeThoughts = nil
 	}
 
 	// Build the request payload
-	payload, err := buildPayloadWithGem(prompt, metadata, files, gemID)
+	payload, err := buildPayloadWithOptions(prompt, metadata, files, gemID, includeThoughts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build payload: %w", err)
 	}
@@ -172,11 +219,35 @@ func (c *GeminiClient) doGenerateContent(prompt string, opts *GenerateOptions) (
 			}
 		}
 		if err != nil {
+			if err != io.EOF {
+				return nil, apierrors.NewNetworkErrorWithEndpoint(
+					"generate content",
+					models.EndpointGenerate,
+					fmt.Errorf("%w after %d bytes: %w", errStreamInterrupted, len(body), err),
+				)
+			}
 			break
 		}
 	}
 
-	return parseResponse(body, model.Name)
+	output, err := parseResponse(body, model.Name)
+	if err == nil && c.retainRawResponse {
+		output.RawResponse = boundRawResponse(body)
+	}
+	return output, err
+}
+
+// MaxRawResponseSize bounds the raw response body retained by
+// WithRawResponse.
+const MaxRawResponseSize = 256 * 1024
+
+// boundRawResponse returns body as a string, keeping at most
+// MaxRawResponseSize bytes and noting how much was dropped.
+func boundRawResponse(body []byte) string {
+	if len(body) <= MaxRawResponseSize {
+		return string(body)
+	}
+	return fmt.Sprintf("%s\n... [truncated %d bytes]", body[:MaxRawResponseSize], len(body)-MaxRawResponseSize)
 }
 
 // buildPayload creates the f.req payload for the generate request
@@ -191,9 +262,46 @@ func buildPayloadWithImages(prompt string, metadata []string, images []*Uploaded
 	return buildPayloadWithGem(prompt, metadata, images, "")
 }
 
+// Positions of optional fields in the inner f.req payload
+const (
+	// payloadThoughtsIndex is where the thinking output toggle (1 = on,
+	// 0 = off) is believed to go. Unlike the gem ID, it does not come from
+	// the Python Gemini-API implementation and has not been checked against
+	// a request captured from the web app, so it is only sent when
+	// ThoughtsFieldEnabled reports true.
+	payloadThoughtsIndex = 17
+	payloadGemIndex      = 19 // Gem ID, as in the Python Gemini-API implementation
+)
+
+// ThoughtsFieldEnvVar enables sending the unconfirmed thinking output
+// toggle (see payloadThoughtsIndex) when set to "1"
+const ThoughtsFieldEnvVar = "GEMINIWEB_EXPERIMENTAL_THOUGHTS"
+
+// ThoughtsFieldEnabled reports whether GenerateOptions.IncludeThoughts is
+// sent to the server. Until the payload position is confirmed it is off
+// unless GEMINIWEB_EXPERIMENTAL_THOUGHTS=1.
+func ThoughtsFieldEnabled() bool {
+	return os.Getenv(ThoughtsFieldEnvVar) == "1"
+}
+
+// setPayloadField sets inner[index], padding with nulls as needed
+func setPayloadField(inner []interface{}, index int, value interface{}) []interface{} {
+	for len(inner) <= index {
+		inner = append(inner, nil)
+	}
+	inner[index] = value
+	return inner
+}
+
 // buildPayloadWithGem creates the f.req payload including file references and gem
 // Based on the Python Gemini-API implementation
 func buildPayloadWithGem(prompt string, metadata []string, files []*UploadedFile, gemID string) (string, error) {
+	return buildPayloadWithOptions(prompt, metadata, files, gemID, nil)
+}
+
+// buildPayloadWithOptions creates the f.req payload including file
+// references, gem and the thinking output toggle (omitted when nil)
+func buildPayloadWithOptions(prompt string, metadata []string, files []*UploadedFile, gemID string, includeThoughts *bool) (string, error) {
 	// Inner payload structure depends on whether files are included
 	var inner []interface{}
 
@@ -227,13 +335,18 @@ func buildPayloadWithGem(prompt string, metadata []string, files []*UploadedFile
 		}
 	}
 
+	if includeThoughts != nil {
+		flag := 0
+		if *includeThoughts {
+			flag = 1
+		}
+		inner = setPayloadField(inner, payloadThoughtsIndex, flag)
+	}
+
 	// Add gem_id if provided
-	// Format: 16 nulls followed by gem_id (position 19 total)
+	// Format: nulls followed by gem_id (position 19 total)
 	if gemID != "" {
-		for i := 0; i < 16; i++ {
-			inner = append(inner, nil)
-		}
-		inner = append(inner, gemID)
+		inner = setPayloadField(inner, payloadGemIndex, gemID)
 	}
 
 	innerJSON, err := json.Marshal(inner)
@@ -436,6 +549,8 @@ func parseResponse(body []byte, modelName string) (*models.ModelOutput, error) {
 			Thoughts:        thoughts,
 			WebImages:       webImages,
 			GeneratedImages: generatedImages,
+			Sources:         parseSources(candValue.Get(PathCandSources)),
+			Parts:           models.ParseParts(text, generatedImages),
 		})
 		return true
 	})
@@ -454,6 +569,31 @@ func parseResponse(body []byte, modelName string) (*models.ModelOutput, error) {
 	}, nil
 }
 
+// parseSources extracts grounding/citation sources from a candidate,
+// skipping entries without a URL and duplicates of an earlier URL.
+func parseSources(list gjson.Result) []models.Source {
+	if !list.IsArray() {
+		return nil
+	}
+
+	var sources []models.Source
+	seen := make(map[string]bool)
+	list.ForEach(func(_, srcValue gjson.Result) bool {
+		srcURL := srcValue.Get(PathSourceURL).String()
+		if srcURL == "" || seen[srcURL] {
+			return true
+		}
+		seen[srcURL] = true
+		sources = append(sources, models.Source{
+			Title:   srcValue.Get(PathSourceTitle).String(),
+			URL:     srcURL,
+			Snippet: srcValue.Get(PathSourceSnippet).String(),
+		})
+		return true
+	})
+	return sources
+}
+
 // handleErrorCode converts API error codes to appropriate errors
 // using the centralized error handling function
 func handleErrorCode(code models.ErrorCode, modelName string) error {
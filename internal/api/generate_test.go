@@ -1,8 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	fhttp "github.com/bogdanfinn/fhttp"
 	"github.com/tidwall/gjson"
@@ -158,6 +164,79 @@ func TestParseResponse(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "response with citation sources",
+			// Candidate: ["rcid", ["text"], [[source, ...]]] with source: [url, title, snippet]
+			body: makeBody(`[null,["cid","rid","rcid"],null,null,[["rcid",["Go 1.24 is out"],` +
+				`[[["https://go.dev/blog/go1.24","Go 1.24 is released","The Go team is happy to announce..."],` +
+				`["https://go.dev/doc/go1.24","Go 1.24 Release Notes"],` +
+				`["https://go.dev/blog/go1.24","duplicate"],` +
+				`[null,"missing url"]]]]]]`),
+			modelName: "gemini-2.5-flash",
+			check: func(t *testing.T, output *models.ModelOutput) {
+				sources := output.Sources()
+				if len(sources) != 2 {
+					t.Fatalf("expected 2 sources, got %d: %+v", len(sources), sources)
+				}
+				want := models.Source{
+					Title:   "Go 1.24 is released",
+					URL:     "https://go.dev/blog/go1.24",
+					Snippet: "The Go team is happy to announce...",
+				}
+				if sources[0] != want {
+					t.Errorf("sources[0] = %+v, want %+v", sources[0], want)
+				}
+				if sources[1].URL != "https://go.dev/doc/go1.24" || sources[1].Snippet != "" {
+					t.Errorf("sources[1] = %+v", sources[1])
+				}
+			},
+		},
+		{
+			name:      "response without citation sources",
+			body:      makeBody(`[null,["cid","rid","rcid"],null,null,[["rcid",["No citations"]]]]`),
+			modelName: "gemini-2.5-flash",
+			check: func(t *testing.T, output *models.ModelOutput) {
+				if sources := output.Sources(); sources != nil {
+					t.Errorf("expected no sources, got %+v", sources)
+				}
+			},
+		},
+		{
+			name: "response with interleaved parts",
+			// Candidate text interleaves a data URI image and a generated image
+			// placeholder; generated images live at candidate[12][7][0]
+			body: makeBody(`[null,["cid","rid","rcid"],null,null,[["rcid",` +
+				`["Chart:\n![chart](data:image/png;base64,AQID)\nGenerated: http://googleusercontent.com/image_generation_content/0\nEnd"],` +
+				`null,null,null,null,null,null,null,null,null,null,` +
+				`[null,null,null,null,null,null,null,[[` +
+				`[[null,null,null,[null,null,null,"https://lh3.example.com/gen.png"]],null,null,[null,null,null,null,null,["a cat"],"1"]]` +
+				`]]]]]]`),
+			modelName: "gemini-2.5-flash",
+			check: func(t *testing.T, output *models.ModelOutput) {
+				parts := output.Parts()
+				wantTypes := []models.PartType{models.PartText, models.PartInlineData, models.PartText, models.PartImage, models.PartText}
+				if len(parts) != len(wantTypes) {
+					t.Fatalf("got %d parts, want %d: %+v", len(parts), len(wantTypes), parts)
+				}
+				for i, want := range wantTypes {
+					if parts[i].Type != want {
+						t.Errorf("parts[%d].Type = %s, want %s", i, parts[i].Type, want)
+					}
+				}
+				if parts[0].Text != "Chart:\n" || parts[4].Text != "\nEnd" {
+					t.Errorf("unexpected text parts: %q, %q", parts[0].Text, parts[4].Text)
+				}
+				if parts[1].MIME != "image/png" || string(parts[1].Data) != "\x01\x02\x03" {
+					t.Errorf("inline part = %+v", parts[1])
+				}
+				if parts[3].URL != "https://lh3.example.com/gen.png" || parts[3].Text != "[Generated Image 1]" {
+					t.Errorf("image part = %+v", parts[3])
+				}
+				if !strings.Contains(output.Text(), "data:image/png;base64,AQID") {
+					t.Error("Text() should still return the flattened text")
+				}
+			},
+		},
 		{
 			name:      "error code 1037 - usage limit exceeded",
 			body:      []byte(`[6, 1037]`),
@@ -1026,3 +1105,338 @@ func TestGenerateContent_WithCustomModel(t *testing.T) {
 		}
 	})
 }
+
+// interruptedBody returns data and then fails with err, simulating a
+// connection that drops mid-stream.
+type interruptedBody struct {
+	data []byte
+	err  error
+}
+
+func (b *interruptedBody) Read(p []byte) (int, error) {
+	if len(b.data) == 0 {
+		return 0, b.err
+	}
+	n := copy(p, b.data)
+	b.data = b.data[n:]
+	return n, nil
+}
+
+func (b *interruptedBody) Close() error { return nil }
+
+// TestGenerateContent_StreamInterrupted tests retry behavior when the
+// response stream drops before completing
+func TestGenerateContent_StreamInterrupted(t *testing.T) {
+	validCookies := &config.Cookies{
+		Secure1PSID:   "test_psid",
+		Secure1PSIDTS: "test_psidts",
+	}
+	successBody := `[[null, null, "[null,[\"cid\",\"rid\",\"rcid\"],null,null,[[\"rcid\",[\"after retry\"]]]]"]]`
+
+	newClient := func(doFunc func(req *fhttp.Request) (*fhttp.Response, error)) *GeminiClient {
+		return &GeminiClient{
+			httpClient:  &DynamicMockHttpClient{DoFunc: doFunc},
+			cookies:     validCookies,
+			model:       models.Model25Flash,
+			accessToken: "test_token",
+		}
+	}
+	interrupted := func() *fhttp.Response {
+		return &fhttp.Response{
+			StatusCode: 200,
+			Body:       &interruptedBody{data: []byte(`)]}'` + "\n123\n[[\"wrb.fr\""), err: io.ErrUnexpectedEOF},
+			Header:     make(fhttp.Header),
+		}
+	}
+
+	t.Run("retries once when the stream drops", func(t *testing.T) {
+		requests := 0
+		client := newClient(func(req *fhttp.Request) (*fhttp.Response, error) {
+			requests++
+			if requests == 1 {
+				return interrupted(), nil
+			}
+			return &fhttp.Response{
+				StatusCode: 200,
+				Body:       NewMockResponseBody([]byte(successBody)),
+				Header:     make(fhttp.Header),
+			}, nil
+		})
+
+		got, err := client.GenerateContent("test prompt", nil)
+		if err != nil {
+			t.Fatalf("GenerateContent() unexpected error: %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("requests = %d, want 2", requests)
+		}
+		if got.Text() != "after retry" {
+			t.Errorf("Text() = %q, want %q", got.Text(), "after retry")
+		}
+	})
+
+	t.Run("gives up after one retry", func(t *testing.T) {
+		requests := 0
+		client := newClient(func(req *fhttp.Request) (*fhttp.Response, error) {
+			requests++
+			return interrupted(), nil
+		})
+
+		got, err := client.GenerateContent("test prompt", nil)
+		if err == nil || got != nil {
+			t.Fatalf("expected error, got output=%v err=%v", got, err)
+		}
+		if !apierrors.IsNetworkError(err) || !errors.Is(err, errStreamInterrupted) {
+			t.Errorf("expected interrupted network error, got %v", err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Error("error should wrap the underlying read error")
+		}
+		if requests != 2 {
+			t.Errorf("requests = %d, want 2", requests)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		requests := 0
+		client := newClient(func(req *fhttp.Request) (*fhttp.Response, error) {
+			requests++
+			return &fhttp.Response{
+				StatusCode: 500,
+				Body:       NewMockResponseBody([]byte("server error")),
+				Header:     make(fhttp.Header),
+			}, nil
+		})
+
+		if _, err := client.GenerateContent("test prompt", nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if requests != 1 {
+			t.Errorf("requests = %d, want 1", requests)
+		}
+	})
+}
+
+// TestGenerateContent_RawResponse tests that the raw body is retained only
+// when WithRawResponse is enabled
+func TestGenerateContent_RawResponse(t *testing.T) {
+	body := `[[null, null, "[null,[\"cid\",\"rid\",\"rcid\"],null,null,[[\"rcid\",[\"raw\"]]]]"]]`
+
+	generate := func(t *testing.T, retain bool) *models.ModelOutput {
+		t.Helper()
+		client := &GeminiClient{
+			httpClient: &DynamicMockHttpClient{DoFunc: func(req *fhttp.Request) (*fhttp.Response, error) {
+				return &fhttp.Response{
+					StatusCode: 200,
+					Body:       NewMockResponseBody([]byte(body)),
+					Header:     make(fhttp.Header),
+				}, nil
+			}},
+			cookies:     &config.Cookies{Secure1PSID: "test_psid"},
+			model:       models.Model25Flash,
+			accessToken: "test_token",
+		}
+		WithRawResponse(retain)(client)
+
+		output, err := client.GenerateContent("test prompt", nil)
+		if err != nil {
+			t.Fatalf("GenerateContent() unexpected error: %v", err)
+		}
+		return output
+	}
+
+	t.Run("retained when enabled", func(t *testing.T) {
+		if got := generate(t, true).RawResponse; got != body {
+			t.Errorf("RawResponse = %q, want %q", got, body)
+		}
+	})
+
+	t.Run("not retained by default", func(t *testing.T) {
+		if got := generate(t, false).RawResponse; got != "" {
+			t.Errorf("RawResponse = %q, want empty", got)
+		}
+	})
+
+	t.Run("bounded", func(t *testing.T) {
+		large := bytes.Repeat([]byte("x"), MaxRawResponseSize+10)
+		got := boundRawResponse(large)
+		if !strings.HasPrefix(got, string(large[:MaxRawResponseSize])) || !strings.HasSuffix(got, "[truncated 10 bytes]") {
+			t.Errorf("boundRawResponse() did not truncate as expected (len %d)", len(got))
+		}
+	})
+}
+
+func TestGenerateContent_RequestRetry(t *testing.T) {
+	body := `[[null, null, "[null,[\"cid\",\"rid\",\"rcid\"],null,null,[[\"rcid\",[\"ok\"]]]]"]]`
+
+	var sleeps []time.Duration
+	origSleep := retrySleep
+	retrySleep = func(d time.Duration) { sleeps = append(sleeps, d) }
+	t.Cleanup(func() { retrySleep = origSleep })
+
+	newClient := func(statuses ...int) (*GeminiClient, *int) {
+		calls := 0
+		client := &GeminiClient{
+			httpClient: &DynamicMockHttpClient{DoFunc: func(req *fhttp.Request) (*fhttp.Response, error) {
+				status := 200
+				if calls < len(statuses) {
+					status = statuses[calls]
+				}
+				calls++
+				return &fhttp.Response{
+					StatusCode: status,
+					Body:       NewMockResponseBody([]byte(body)),
+					Header:     make(fhttp.Header),
+				}, nil
+			}},
+			cookies:     &config.Cookies{Secure1PSID: "test_psid"},
+			model:       models.Model25Flash,
+			accessToken: "test_token",
+		}
+		return client, &calls
+	}
+
+	t.Run("retries server errors with backoff", func(t *testing.T) {
+		sleeps = nil
+		client, calls := newClient(503, 500)
+		WithRequestRetry(3, 100*time.Millisecond)(client)
+
+		output, err := client.GenerateContent("test prompt", nil)
+		if err != nil {
+			t.Fatalf("GenerateContent() unexpected error: %v", err)
+		}
+		if output.Text() != "ok" {
+			t.Errorf("Text() = %q, want %q", output.Text(), "ok")
+		}
+		if *calls != 3 {
+			t.Errorf("requests = %d, want 3", *calls)
+		}
+		want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+		if len(sleeps) != len(want) || sleeps[0] != want[0] || sleeps[1] != want[1] {
+			t.Errorf("backoff waits = %v, want %v", sleeps, want)
+		}
+	})
+
+	t.Run("gives up after configured retries", func(t *testing.T) {
+		client, calls := newClient(429, 429, 429, 429)
+		WithRequestRetry(2, 0)(client)
+
+		if _, err := client.GenerateContent("test prompt", nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if *calls != 3 {
+			t.Errorf("requests = %d, want 3", *calls)
+		}
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		client, calls := newClient(400)
+		WithRequestRetry(3, 0)(client)
+
+		if _, err := client.GenerateContent("test prompt", nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if *calls != 1 {
+			t.Errorf("requests = %d, want 1", *calls)
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client, calls := newClient(503)
+
+		if _, err := client.GenerateContent("test prompt", nil); err == nil {
+			t.Fatal("expected error")
+		}
+		if *calls != 1 {
+			t.Errorf("requests = %d, want 1", *calls)
+		}
+	})
+}
+
+// TestGenerateContent_IncludeThoughts tests that the thinking toggle is
+// sent only for models that support it
+func TestGenerateContent_IncludeThoughts(t *testing.T) {
+	body := `[[null, null, "[null,[\"cid\",\"rid\",\"rcid\"],null,null,[[\"rcid\",[\"ok\"]]]]"]]`
+	on, off := true, false
+
+	// sentThoughts returns the thinking toggle in the request payload, if any
+	sentThoughts := func(t *testing.T, model models.Model, include *bool) (gjson.Result, bool) {
+		t.Helper()
+		var form string
+		client := &GeminiClient{
+			httpClient: &DynamicMockHttpClient{DoFunc: func(req *fhttp.Request) (*fhttp.Response, error) {
+				data, _ := io.ReadAll(req.Body)
+				form = string(data)
+				return &fhttp.Response{
+					StatusCode: 200,
+					Body:       NewMockResponseBody([]byte(body)),
+					Header:     make(fhttp.Header),
+				}, nil
+			}},
+			cookies:     &config.Cookies{Secure1PSID: "test_psid"},
+			model:       models.ModelFast,
+			accessToken: "test_token",
+		}
+
+		opts := &GenerateOptions{Model: model, IncludeThoughts: include}
+		if _, err := client.GenerateContent("test prompt", opts); err != nil {
+			t.Fatalf("GenerateContent() unexpected error: %v", err)
+		}
+
+		values, err := url.ParseQuery(form)
+		if err != nil {
+			t.Fatalf("invalid form body: %v", err)
+		}
+		inner := gjson.Parse(gjson.Get(values.Get("f.req"), "1").String())
+		flag := inner.Get(strconv.Itoa(payloadThoughtsIndex))
+		return flag, flag.Exists() && flag.Type != gjson.Null
+	}
+
+	t.Setenv(ThoughtsFieldEnvVar, "1")
+
+	t.Run("sent for supporting models", func(t *testing.T) {
+		if flag, ok := sentThoughts(t, models.ModelThinking, &on); !ok || flag.Int() != 1 {
+			t.Errorf("thoughts flag = %v, want 1", flag)
+		}
+		if flag, ok := sentThoughts(t, models.ModelThinking, &off); !ok || flag.Int() != 0 {
+			t.Errorf("thoughts flag = %v, want 0", flag)
+		}
+	})
+
+	t.Run("omitted for other models", func(t *testing.T) {
+		if flag, ok := sentThoughts(t, models.ModelFast, &off); ok {
+			t.Errorf("thoughts flag should not be sent for fast, got %v", flag)
+		}
+	})
+
+	t.Run("omitted when unset", func(t *testing.T) {
+		if flag, ok := sentThoughts(t, models.ModelThinking, nil); ok {
+			t.Errorf("thoughts flag should not be sent by default, got %v", flag)
+		}
+	})
+
+	t.Run("omitted unless enabled", func(t *testing.T) {
+		t.Setenv(ThoughtsFieldEnvVar, "")
+		if flag, ok := sentThoughts(t, models.ModelThinking, &on); ok {
+			t.Errorf("thoughts flag should not be sent without %s=1, got %v", ThoughtsFieldEnvVar, flag)
+		}
+	})
+}
+
+func TestBuildPayloadWithOptions_GemAndThoughts(t *testing.T) {
+	off := false
+	payload, err := buildPayloadWithOptions("hi", nil, nil, "gem-1", &off)
+	if err != nil {
+		t.Fatalf("buildPayloadWithOptions() error: %v", err)
+	}
+	inner := gjson.Parse(gjson.Get(payload, "1").String())
+	if got := inner.Get(strconv.Itoa(payloadGemIndex)).String(); got != "gem-1" {
+		t.Errorf("gem ID = %q, want gem-1", got)
+	}
+	if got := inner.Get(strconv.Itoa(payloadThoughtsIndex)); got.Type != gjson.Number || got.Int() != 0 {
+		t.Errorf("thoughts flag = %v, want 0", got)
+	}
+	if n := len(inner.Array()); n != payloadGemIndex+1 {
+		t.Errorf("inner payload has %d fields, want %d", n, payloadGemIndex+1)
+	}
+}
@@ -16,6 +16,7 @@ type MockGeminiClient struct {
 	Cookies               *config.Cookies
 	Model                 models.Model
 	IsClosedVal           bool
+	PingErr               error
 	IsAutoCloseEnabledVal bool
 	ChatSession           *ChatSession
 	GenerateContentVal    *models.ModelOutput
@@ -37,9 +38,11 @@ type MockGeminiClient struct {
 	GemErr                error
 	BatchResponseVal      []BatchResponse
 	BatchResponseErr      error
+	ClientInfoVal         ClientInfo
 
 	// Call counters/recorders
 	InitCalled            bool
+	PingCalled            bool
 	CloseCalled           bool
 	GenerateContentCalled bool
 	LastPrompt            string
@@ -57,6 +60,11 @@ func (m *MockGeminiClient) Close() {
 	m.CloseCalled = true
 }
 
+func (m *MockGeminiClient) Ping(ctx context.Context) error {
+	m.PingCalled = true
+	return m.PingErr
+}
+
 func (m *MockGeminiClient) GetAccessToken() string {
 	return m.AccessToken
 }
@@ -105,7 +113,7 @@ func (m *MockGeminiClient) UploadImage(filePath string) (*UploadedImage, error)
 	return m.UploadImageVal, m.UploadImageErr
 }
 
-func (m *MockGeminiClient) UploadFile(filePath string) (*UploadedFile, error) {
+func (m *MockGeminiClient) UploadFile(filePath string, opts ...UploadOption) (*UploadedFile, error) {
 
 	return m.UploadFileVal, m.UploadFileErr
 
@@ -146,6 +154,10 @@ func (m *MockGeminiClient) IsAutoCloseEnabled() bool {
 	return m.IsAutoCloseEnabledVal
 }
 
+func (m *MockGeminiClient) ClientInfo() ClientInfo {
+	return m.ClientInfoVal
+}
+
 func (m *MockGeminiClient) FetchGems(includeHidden bool) (*models.GemJar, error) {
 	return m.GemsJar, m.GemsErr
 }
@@ -27,6 +27,12 @@ const (
 	PathCandThoughts  = "37.0.0"
 	PathCandWebImages = "12.1"
 	PathCandGenImages = "12.7.0"
+	PathCandSources   = "2.0" // Grounding/citation sources
+
+	// Source paths (relative to source object)
+	PathSourceURL     = "0"
+	PathSourceTitle   = "1"
+	PathSourceSnippet = "2"
 
 	// Web image paths (relative to web image object)
 	PathWebImgURL   = "0.0.0"
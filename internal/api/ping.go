@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	http "github.com/bogdanfinn/fhttp"
+
+	apierrors "github.com/diogo/geminiweb/internal/errors"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// maxPingBodyBytes caps how much of the app page Ping reads while looking
+// for the access token
+const maxPingBodyBytes = 4 << 20
+
+// Ping checks connectivity and authentication by loading the Gemini app
+// page with the client's cookies, without starting a conversation.
+//
+// Failures are classified with the apierrors types: transport failures are
+// a *NetworkError (or *TimeoutError when ctx expired), a rejected or
+// signed-out session is an *AuthError, a redirect to Google's block page is
+// a *BlockedError, and other HTTP failures are an *APIError.
+func (c *GeminiClient) Ping(ctx context.Context) error {
+	if c.IsClosed() {
+		return apierrors.NewGeminiError("ping", "client is closed")
+	}
+	cookies := c.GetCookies()
+	if cookies == nil {
+		return apierrors.NewAuthErrorWithEndpoint("no cookies configured", models.EndpointInit)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, models.EndpointInit, nil)
+	if err != nil {
+		return apierrors.NewGeminiErrorWithCause("create ping request", err)
+	}
+	for key, value := range models.DefaultHeaders() {
+		req.Header.Set(key, value)
+	}
+	psid, psidts := cookies.Snapshot()
+	req.AddCookie(&http.Cookie{Name: "__Secure-1PSID", Value: psid})
+	if psidts != "" {
+		req.AddCookie(&http.Cookie{Name: "__Secure-1PSIDTS", Value: psidts})
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return apierrors.NewTimeoutErrorWithEndpoint(models.EndpointInit, err)
+		}
+		return apierrors.NewNetworkErrorWithEndpoint("ping", models.EndpointInit, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		authErr := apierrors.NewAuthErrorWithEndpoint(
+			fmt.Sprintf("session rejected (status: %d)", resp.StatusCode),
+			models.EndpointInit,
+		)
+		authErr.HTTPStatus = resp.StatusCode
+		return authErr
+	case resp.StatusCode >= 300 && resp.StatusCode < 400:
+		location := resp.Header.Get("Location")
+		if strings.Contains(location, "/sorry/") {
+			return apierrors.NewBlockedError("Google has temporarily blocked access (too many requests)")
+		}
+		authErr := apierrors.NewAuthErrorWithEndpoint(
+			fmt.Sprintf("redirected to %s; cookies may be expired", location),
+			models.EndpointInit,
+		)
+		authErr.HTTPStatus = resp.StatusCode
+		return authErr
+	case resp.StatusCode != http.StatusOK:
+		return apierrors.NewAPIError(resp.StatusCode, models.EndpointInit, "ping failed")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPingBodyBytes))
+	if err != nil {
+		return apierrors.NewNetworkErrorWithEndpoint("ping", models.EndpointInit, err)
+	}
+	// The page only carries an access token for a signed-in session
+	if !snlm0ePattern.Match(body) {
+		return apierrors.NewAuthErrorWithEndpoint("not signed in; cookies may be expired", models.EndpointInit)
+	}
+	return nil
+}
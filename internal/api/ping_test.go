@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	fhttp "github.com/bogdanfinn/fhttp"
+
+	"github.com/diogo/geminiweb/internal/config"
+	apierrors "github.com/diogo/geminiweb/internal/errors"
+)
+
+func newPingTestClient(do func(req *fhttp.Request) (*fhttp.Response, error)) *GeminiClient {
+	return &GeminiClient{
+		httpClient: &DynamicMockHttpClient{DoFunc: do},
+		cookies:    &config.Cookies{Secure1PSID: "test_psid", Secure1PSIDTS: "test_psidts"},
+	}
+}
+
+func pingResponse(status int, body string, header fhttp.Header) func(req *fhttp.Request) (*fhttp.Response, error) {
+	return func(req *fhttp.Request) (*fhttp.Response, error) {
+		if header == nil {
+			header = make(fhttp.Header)
+		}
+		return &fhttp.Response{StatusCode: status, Body: NewMockResponseBody([]byte(body)), Header: header}, nil
+	}
+}
+
+func TestPing_Success(t *testing.T) {
+	var sentCookie string
+	client := newPingTestClient(func(req *fhttp.Request) (*fhttp.Response, error) {
+		if c, err := req.Cookie("__Secure-1PSID"); err == nil {
+			sentCookie = c.Value
+		}
+		return pingResponse(200, `<script>{"SNlM0e":"token123"}</script>`, nil)(req)
+	})
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+	if sentCookie != "test_psid" {
+		t.Errorf("Ping should authenticate with the session cookie, got %q", sentCookie)
+	}
+}
+
+func TestPing_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name  string
+		do    func(req *fhttp.Request) (*fhttp.Response, error)
+		check func(error) bool
+	}{
+		{"401", pingResponse(401, "", nil), apierrors.IsAuthError},
+		{"403", pingResponse(403, "", nil), apierrors.IsAuthError},
+		{"signed out page", pingResponse(200, "<html>sign in</html>", nil), apierrors.IsAuthError},
+		{"sign-in redirect", pingResponse(302, "", fhttp.Header{"Location": {"https://accounts.google.com/ServiceLogin"}}), apierrors.IsAuthError},
+		{"network error", func(req *fhttp.Request) (*fhttp.Response, error) {
+			return nil, errors.New("dial tcp: connection refused")
+		}, apierrors.IsNetworkError},
+		{"rate limited", pingResponse(429, "", nil), apierrors.IsRateLimitError},
+		{"blocked", pingResponse(302, "", fhttp.Header{"Location": {"https://www.google.com/sorry/index"}}), func(err error) bool {
+			var blocked *apierrors.BlockedError
+			return errors.As(err, &blocked)
+		}},
+		{"server error", pingResponse(500, "", nil), func(err error) bool {
+			return apierrors.GetHTTPStatus(err) == 500 && !apierrors.IsAuthError(err)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newPingTestClient(tt.do).Ping(context.Background())
+			if err == nil || !tt.check(err) {
+				t.Errorf("Ping() error = %v (%T), wrong classification", err, err)
+			}
+		})
+	}
+}
+
+func TestPing_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	client := newPingTestClient(func(req *fhttp.Request) (*fhttp.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	if err := client.Ping(ctx); !apierrors.IsTimeoutError(err) {
+		t.Errorf("Ping() error = %v, want a timeout error", err)
+	}
+}
+
+func TestPing_ClosedOrNoCookies(t *testing.T) {
+	closed := newPingTestClient(pingResponse(200, "", nil))
+	closed.closed = true
+	if err := closed.Ping(context.Background()); err == nil {
+		t.Error("Ping on a closed client should fail")
+	}
+
+	noCookies := &GeminiClient{httpClient: &DynamicMockHttpClient{}}
+	if err := noCookies.Ping(context.Background()); !apierrors.IsAuthError(err) {
+		t.Errorf("Ping without cookies error = %v, want an auth error", err)
+	}
+}
@@ -95,6 +95,8 @@ type CookieRotator struct {
 	running  bool
 	mu       sync.Mutex
 	onError  RotatorErrorCallback // Optional callback for rotation errors
+
+	lastRotation time.Time // Time of the last successful rotation
 }
 
 // RotatorOption configures the CookieRotator
@@ -159,6 +161,9 @@ func (r *CookieRotator) Start() {
 				if newToken != "" {
 					cookies.Update1PSIDTS(newToken)
 				}
+				r.mu.Lock()
+				r.lastRotation = time.Now()
+				r.mu.Unlock()
 			case <-stopCh:
 				return
 			}
@@ -178,3 +183,11 @@ func (r *CookieRotator) Stop() {
 		r.running = false
 	}
 }
+
+// LastRotation returns the time of the last successful rotation, or the
+// zero time if none has happened yet
+func (r *CookieRotator) LastRotation() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastRotation
+}
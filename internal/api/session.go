@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"sync"
 
 	"github.com/diogo/geminiweb/internal/models"
@@ -9,11 +10,27 @@ import (
 // ChatSession maintains conversation context across messages
 type ChatSession struct {
 	client     GeminiClientInterface
-	mu         sync.RWMutex // Protects metadata, lastOutput, gemID, model
+	mu         sync.RWMutex // Protects metadata, lastOutput, gemID, model, includeThoughts
 	model      models.Model
 	metadata   []string // [cid, rid, rcid]
 	lastOutput *models.ModelOutput
 	gemID      string // ID do gem associado à sessão (server-side persona)
+
+	// includeThoughts is the session default for GenerateOptions.IncludeThoughts
+	includeThoughts *bool
+
+	// retryOnEmpty is how many times a prompt is resent when the response
+	// has no content
+	retryOnEmpty int
+}
+
+// MaxRetryOnEmpty caps how many times a session resends a prompt whose
+// response came back empty.
+const MaxRetryOnEmpty = 5
+
+// clampRetryOnEmpty limits n to the range [0, MaxRetryOnEmpty]
+func clampRetryOnEmpty(n int) int {
+	return max(0, min(n, MaxRetryOnEmpty))
 }
 
 // copyMetadata creates a copy of the metadata slice to avoid races
@@ -29,6 +46,13 @@ func copyMetadata(m []string) []string {
 // SendMessage sends a message in the chat session and updates context
 // files is optional - pass nil when no files are attached
 func (s *ChatSession) SendMessage(prompt string, files []*UploadedFile) (*models.ModelOutput, error) {
+	return s.SendMessageContext(context.Background(), prompt, files)
+}
+
+// SendMessageContext is SendMessage with a context. The context is checked
+// before each send, so it stops the resends of empty responses (see
+// SetRetryOnEmpty); a request already in flight runs to completion.
+func (s *ChatSession) SendMessageContext(ctx context.Context, prompt string, files []*UploadedFile) (*models.ModelOutput, error) {
 	// Read current state with read lock
 	s.mu.RLock()
 	opts := &GenerateOptions{
@@ -36,11 +60,27 @@ func (s *ChatSession) SendMessage(prompt string, files []*UploadedFile) (*models
 		Metadata: copyMetadata(s.metadata), // Copy to avoid race
 		GemID:    s.gemID,
 		Files:    files,
+
+		IncludeThoughts: s.includeThoughts,
 	}
+	retries := s.retryOnEmpty
 	s.mu.RUnlock()
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// GenerateContent is thread-safe, no lock needed
 	output, err := s.client.GenerateContent(prompt, opts)
+
+	// Responses without content are resent a bounded number of times; the
+	// last one is returned as is if every attempt comes back empty
+	for attempt := 0; err == nil && attempt < retries && output.IsEmpty(); attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		output, err = s.client.GenerateContent(prompt, opts)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -173,3 +213,35 @@ func (s *ChatSession) GetGemID() string {
 	defer s.mu.RUnlock()
 	return s.gemID
 }
+
+// SetIncludeThoughts sets whether messages request (true) or suppress
+// (false) the thinking output; nil restores the model default
+func (s *ChatSession) SetIncludeThoughts(include *bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.includeThoughts = include
+}
+
+// IncludeThoughts returns the session's thinking output setting (nil for
+// the model default)
+func (s *ChatSession) IncludeThoughts() *bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.includeThoughts
+}
+
+// SetRetryOnEmpty sets how many times a prompt is resent when the response
+// has no text, thoughts or images. n is capped at MaxRetryOnEmpty; 0
+// disables the retry.
+func (s *ChatSession) SetRetryOnEmpty(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retryOnEmpty = clampRetryOnEmpty(n)
+}
+
+// RetryOnEmpty returns how many times an empty response is retried
+func (s *ChatSession) RetryOnEmpty() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.retryOnEmpty
+}
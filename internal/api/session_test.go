@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -575,3 +576,132 @@ func TestChatSession_SetGem(t *testing.T) {
 		}
 	})
 }
+
+func TestChatSession_IncludeThoughts(t *testing.T) {
+	session := &ChatSession{model: models.ModelThinking}
+	if session.IncludeThoughts() != nil {
+		t.Error("IncludeThoughts() should default to nil (model default)")
+	}
+
+	off := false
+	session.SetIncludeThoughts(&off)
+	if got := session.IncludeThoughts(); got == nil || *got {
+		t.Errorf("IncludeThoughts() = %v, want false", got)
+	}
+
+	session.SetIncludeThoughts(nil)
+	if session.IncludeThoughts() != nil {
+		t.Error("SetIncludeThoughts(nil) should restore the model default")
+	}
+}
+
+// sequenceGenerateClient returns its outputs in order from GenerateContent
+type sequenceGenerateClient struct {
+	MockGeminiClient
+	outputs []*models.ModelOutput
+	calls   int
+	onCall  func(call int)
+}
+
+func (c *sequenceGenerateClient) GenerateContent(prompt string, opts *GenerateOptions) (*models.ModelOutput, error) {
+	c.calls++
+	if c.onCall != nil {
+		c.onCall(c.calls)
+	}
+	output := c.outputs[min(c.calls, len(c.outputs))-1]
+	return output, nil
+}
+
+func textOutput(text string) *models.ModelOutput {
+	return &models.ModelOutput{
+		Metadata:   []string{"cid", "rid"},
+		Candidates: []models.Candidate{{RCID: "rc", Text: text}},
+	}
+}
+
+func TestChatSession_RetryOnEmpty(t *testing.T) {
+	t.Run("retries once and returns the non-empty result", func(t *testing.T) {
+		client := &sequenceGenerateClient{outputs: []*models.ModelOutput{textOutput(""), textOutput("hello")}}
+		session := &ChatSession{client: client, model: models.Model25Flash}
+		session.SetRetryOnEmpty(3)
+
+		output, err := session.SendMessage("hi", nil)
+		if err != nil {
+			t.Fatalf("SendMessage() error = %v", err)
+		}
+		if client.calls != 2 {
+			t.Errorf("GenerateContent called %d times, want 2", client.calls)
+		}
+		if output.Text() != "hello" || session.LastOutput() != output {
+			t.Errorf("expected the non-empty output, got %q", output.Text())
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client := &sequenceGenerateClient{outputs: []*models.ModelOutput{textOutput(""), textOutput("hello")}}
+		session := &ChatSession{client: client, model: models.Model25Flash}
+
+		output, err := session.SendMessage("hi", nil)
+		if err != nil {
+			t.Fatalf("SendMessage() error = %v", err)
+		}
+		if client.calls != 1 || output.Text() != "" {
+			t.Errorf("calls = %d, text = %q; want a single call", client.calls, output.Text())
+		}
+	})
+
+	t.Run("stops after the limit", func(t *testing.T) {
+		client := &sequenceGenerateClient{outputs: []*models.ModelOutput{textOutput("")}}
+		session := &ChatSession{client: client, model: models.Model25Flash}
+		session.SetRetryOnEmpty(100)
+		if session.RetryOnEmpty() != MaxRetryOnEmpty {
+			t.Errorf("RetryOnEmpty() = %d, want the cap %d", session.RetryOnEmpty(), MaxRetryOnEmpty)
+		}
+
+		output, err := session.SendMessage("hi", nil)
+		if err != nil {
+			t.Fatalf("SendMessage() error = %v", err)
+		}
+		if client.calls != MaxRetryOnEmpty+1 {
+			t.Errorf("GenerateContent called %d times, want %d", client.calls, MaxRetryOnEmpty+1)
+		}
+		if !output.IsEmpty() {
+			t.Error("the last empty output should be returned")
+		}
+	})
+
+	t.Run("respects context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		client := &sequenceGenerateClient{
+			outputs: []*models.ModelOutput{textOutput("")},
+			onCall:  func(int) { cancel() },
+		}
+		session := &ChatSession{client: client, model: models.Model25Flash}
+		session.SetRetryOnEmpty(3)
+
+		_, err := session.SendMessageContext(ctx, "hi", nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("SendMessageContext() error = %v, want context.Canceled", err)
+		}
+		if client.calls != 1 {
+			t.Errorf("GenerateContent called %d times, want 1", client.calls)
+		}
+		if session.LastOutput() != nil {
+			t.Error("a cancelled send should not update the session")
+		}
+	})
+
+	t.Run("inherited from the client", func(t *testing.T) {
+		client, err := NewClient(nil, WithRetryOnEmpty(2))
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		if got := client.StartChat().RetryOnEmpty(); got != 2 {
+			t.Errorf("StartChat().RetryOnEmpty() = %d, want 2", got)
+		}
+		if got := client.StartChatWithOptions().RetryOnEmpty(); got != 2 {
+			t.Errorf("StartChatWithOptions().RetryOnEmpty() = %d, want 2", got)
+		}
+	})
+}
@@ -2,10 +2,12 @@ package api
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"mime/multipart"
+	"net/textproto"
 	"os"
 	"path/filepath"
 	"strings"
@@ -46,6 +48,10 @@ func SupportedTextTypes() []string {
 	}
 }
 
+// octetStreamMIMEType is the generic type used when a file's type is
+// unknown or rejected
+const octetStreamMIMEType = "application/octet-stream"
+
 // UploadedFile represents an uploaded file ready for use in prompts
 // This can be an image or text file - the API treats them similarly
 type UploadedFile struct {
@@ -53,6 +59,24 @@ type UploadedFile struct {
 	FileName   string
 	MIMEType   string
 	Size       int64
+	LocalPath  string // Path on disk for files uploaded with UploadFile (empty otherwise)
+	Warning    string // Set when the file was uploaded as application/octet-stream instead of its detected type
+}
+
+// UploadOption configures a single file upload
+type UploadOption func(*uploadConfig)
+
+// uploadConfig holds per-upload settings
+type uploadConfig struct {
+	mimeType string
+}
+
+// WithMIMEType sets the MIME type sent for the file instead of detecting it
+// from the extension. An explicit type is sent as-is, with no fallback.
+func WithMIMEType(mimeType string) UploadOption {
+	return func(c *uploadConfig) {
+		c.mimeType = strings.TrimSpace(mimeType)
+	}
 }
 
 // UploadedImage represents an uploaded image ready for use in prompts
@@ -71,19 +95,29 @@ func NewFileUploader(client *GeminiClient) *FileUploader {
 	}
 }
 
-// UploadFile uploads any supported file from disk (images or text)
-func (u *FileUploader) UploadFile(filePath string) (*UploadedFile, error) {
+// UploadFile uploads any supported file from disk (images or text).
+//
+// The MIME type is detected from the extension unless WithMIMEType is given.
+// A detected type that is not supported, or that the server rejects, is
+// replaced with application/octet-stream and reported in the result's
+// Warning instead of failing the upload.
+func (u *FileUploader) UploadFile(filePath string, opts ...UploadOption) (*UploadedFile, error) {
+	var cfg uploadConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
 	// Get file info
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to stat file: %w", err)
 	}
 
-	// Detect MIME type
-	ext := filepath.Ext(filePath)
-	mimeType := mime.TypeByExtension(ext)
+	mimeType, warning := cfg.mimeType, ""
 	if mimeType == "" {
-		mimeType = "application/octet-stream"
+		mimeType, warning = u.detectMIMEType(filePath)
 	}
 
 	// Determine max size based on file type
@@ -107,9 +141,50 @@ func (u *FileUploader) UploadFile(filePath string) (*UploadedFile, error) {
 		}
 	}()
 
-	return u.uploadStream(file, filepath.Base(filePath), mimeType, fileInfo.Size())
+	fileName := filepath.Base(filePath)
+	uploaded, err := u.uploadStream(file, fileName, mimeType, fileInfo.Size())
+	if err != nil && cfg.mimeType == "" && mimeType != octetStreamMIMEType && isTypeRejection(err) {
+		// Retry once with the generic type
+		if _, seekErr := file.Seek(0, io.SeekStart); seekErr == nil {
+			warning = fmt.Sprintf("server rejected type %s, uploaded as %s", mimeType, octetStreamMIMEType)
+			uploaded, err = u.uploadStream(file, fileName, octetStreamMIMEType, fileInfo.Size())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	uploaded.LocalPath = filePath
+	uploaded.Warning = warning
+	return uploaded, nil
+}
+
+// detectMIMEType guesses a file's MIME type from its extension. Types that
+// are neither supported images nor text fall back to
+// application/octet-stream, with a warning naming the detected type.
+func (u *FileUploader) detectMIMEType(filePath string) (mimeType, warning string) {
+	mimeType = mime.TypeByExtension(filepath.Ext(filePath))
+	if mimeType == "" {
+		return octetStreamMIMEType, ""
+	}
+	if u.isImageType(mimeType) || u.isTextType(mimeType) || strings.HasPrefix(mimeType, "text/") {
+		return mimeType, ""
+	}
+	return octetStreamMIMEType, fmt.Sprintf("unsupported type %s, uploaded as %s", mimeType, octetStreamMIMEType)
+}
+
+// isTypeRejection reports whether an upload failed because the server did
+// not accept the file's type
+func isTypeRejection(err error) bool {
+	var uploadErr *apierrors.UploadError
+	if !errors.As(err, &uploadErr) {
+		return false
+	}
+	return uploadErr.HTTPStatus == 400 || uploadErr.HTTPStatus == 415
 }
 
+// quoteEscaper escapes a filename for a Content-Disposition header
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
 // UploadText uploads text content as a file
 func (u *FileUploader) UploadText(content string, fileName string) (*UploadedFile, error) {
 	if fileName == "" {
@@ -147,8 +222,11 @@ func (u *FileUploader) uploadStream(
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
-	// Add file field
-	part, err := writer.CreateFormFile("file", fileName)
+	// Add file field, declaring the file's MIME type
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, quoteEscaper.Replace(fileName)))
+	header.Set("Content-Type", mimeType)
+	part, err := writer.CreatePart(header)
 	if err != nil {
 		return nil, apierrors.NewUploadError(fileName, fmt.Sprintf("failed to create form file: %v", err))
 	}
@@ -413,7 +491,7 @@ func (c *GeminiClient) UploadImageFromReader(
 }
 
 // UploadFile is a convenience method on GeminiClient for uploading any file
-func (c *GeminiClient) UploadFile(filePath string) (*UploadedFile, error) {
+func (c *GeminiClient) UploadFile(filePath string, opts ...UploadOption) (*UploadedFile, error) {
 	// Ensure client is running (may re-init if auto-closed)
 	if err := c.ensureRunning(); err != nil {
 		return nil, err
@@ -422,7 +500,7 @@ func (c *GeminiClient) UploadFile(filePath string) (*UploadedFile, error) {
 	c.resetIdleTimer()
 
 	uploader := NewFileUploader(c)
-	return uploader.UploadFile(filePath)
+	return uploader.UploadFile(filePath, opts...)
 }
 
 // UploadText is a convenience method for uploading text content as a file
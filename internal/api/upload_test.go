@@ -2,6 +2,8 @@ package api
 
 import (
 	"bytes"
+	"mime"
+	"mime/multipart"
 	"os"
 	"path/filepath"
 	"strings"
@@ -996,3 +998,130 @@ func TestGeminiClient_ConvenienceMethods(t *testing.T) {
 	_ = client.UploadImage
 	_ = client.UploadImageFromReader
 }
+
+// uploadPartType returns the Content-Type declared for the file part of an
+// upload request
+func uploadPartType(t *testing.T, req *fhttp.Request) string {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid request content type: %v", err)
+	}
+	part, err := multipart.NewReader(req.Body, params["boundary"]).NextPart()
+	if err != nil {
+		t.Fatalf("failed to read file part: %v", err)
+	}
+	return part.Header.Get("Content-Type")
+}
+
+func TestFileUploader_UploadFile_MIMEType(t *testing.T) {
+	newUploader := func(t *testing.T, statuses []int, partTypes *[]string) *FileUploader {
+		client := &GeminiClient{httpClient: &DynamicMockHttpClient{
+			DoFunc: func(req *fhttp.Request) (*fhttp.Response, error) {
+				*partTypes = append(*partTypes, uploadPartType(t, req))
+				status := statuses[min(len(*partTypes), len(statuses))-1]
+				return &fhttp.Response{
+					StatusCode: status,
+					Body:       NewMockResponseBody([]byte("/contrib_service/ttl_1d/abc")),
+					Header:     make(fhttp.Header),
+				}, nil
+			},
+		}}
+		return NewFileUploader(client)
+	}
+	writeFile := func(t *testing.T, name string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("override is honored", func(t *testing.T) {
+		var partTypes []string
+		uploader := newUploader(t, []int{200}, &partTypes)
+
+		// .ts is ambiguous: TypeScript source or an MPEG transport stream
+		uploaded, err := uploader.UploadFile(writeFile(t, "main.ts"), WithMIMEType("text/plain"))
+		if err != nil {
+			t.Fatalf("UploadFile() error = %v", err)
+		}
+		if uploaded.MIMEType != "text/plain" || partTypes[0] != "text/plain" {
+			t.Errorf("MIMEType = %q, sent %q, want text/plain", uploaded.MIMEType, partTypes[0])
+		}
+		if uploaded.Warning != "" {
+			t.Errorf("unexpected warning: %q", uploaded.Warning)
+		}
+	})
+
+	t.Run("supported detected type is sent", func(t *testing.T) {
+		var partTypes []string
+		uploader := newUploader(t, []int{200}, &partTypes)
+
+		uploaded, err := uploader.UploadFile(writeFile(t, "photo.png"))
+		if err != nil {
+			t.Fatalf("UploadFile() error = %v", err)
+		}
+		if uploaded.MIMEType != "image/png" || partTypes[0] != "image/png" || uploaded.Warning != "" {
+			t.Errorf("uploaded = %+v, sent %q", uploaded, partTypes[0])
+		}
+	})
+
+	t.Run("unsupported detected type falls back to octet-stream", func(t *testing.T) {
+		var partTypes []string
+		uploader := newUploader(t, []int{200}, &partTypes)
+
+		uploaded, err := uploader.UploadFile(writeFile(t, "report.pdf"))
+		if err != nil {
+			t.Fatalf("UploadFile() error = %v", err)
+		}
+		if uploaded.MIMEType != "application/octet-stream" || partTypes[0] != "application/octet-stream" {
+			t.Errorf("MIMEType = %q, sent %q, want octet-stream", uploaded.MIMEType, partTypes[0])
+		}
+		if !strings.Contains(uploaded.Warning, "application/pdf") {
+			t.Errorf("Warning = %q, want detected type mentioned", uploaded.Warning)
+		}
+	})
+
+	t.Run("rejected type is retried as octet-stream", func(t *testing.T) {
+		var partTypes []string
+		uploader := newUploader(t, []int{415, 200}, &partTypes)
+
+		uploaded, err := uploader.UploadFile(writeFile(t, "data.json"))
+		if err != nil {
+			t.Fatalf("UploadFile() error = %v", err)
+		}
+		if len(partTypes) != 2 || partTypes[0] != "application/json" || partTypes[1] != "application/octet-stream" {
+			t.Errorf("sent types = %v, want [application/json application/octet-stream]", partTypes)
+		}
+		if uploaded.MIMEType != "application/octet-stream" || !strings.Contains(uploaded.Warning, "rejected") {
+			t.Errorf("uploaded = %+v", uploaded)
+		}
+	})
+
+	t.Run("explicit type is not retried", func(t *testing.T) {
+		var partTypes []string
+		uploader := newUploader(t, []int{415}, &partTypes)
+
+		_, err := uploader.UploadFile(writeFile(t, "data.json"), WithMIMEType("application/x-custom"))
+		if err == nil {
+			t.Fatal("expected rejection error")
+		}
+		if len(partTypes) != 1 {
+			t.Errorf("sent %d requests, want 1", len(partTypes))
+		}
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		var partTypes []string
+		uploader := newUploader(t, []int{500}, &partTypes)
+
+		if _, err := uploader.UploadFile(writeFile(t, "data.json")); err == nil {
+			t.Fatal("expected error")
+		}
+		if len(partTypes) != 1 {
+			t.Errorf("sent %d requests, want 1", len(partTypes))
+		}
+	})
+}
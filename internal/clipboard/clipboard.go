@@ -0,0 +1,99 @@
+// Package clipboard provides system clipboard access that fails cleanly
+// where no clipboard is available, such as headless or SSH sessions.
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/atotto/clipboard"
+)
+
+// ErrUnavailable is returned when the system has no usable clipboard.
+var ErrUnavailable = errors.New("clipboard not available")
+
+// Backend is a clipboard implementation.
+type Backend interface {
+	// Available reports whether the clipboard can be used.
+	Available() bool
+	// WriteAll replaces the clipboard content.
+	WriteAll(text string) error
+	// ReadAll returns the clipboard content.
+	ReadAll() (string, error)
+}
+
+var (
+	mu      sync.RWMutex
+	backend Backend = systemBackend{}
+)
+
+// SetBackend replaces the clipboard backend and returns a function that
+// restores the previous one. It is meant for tests.
+func SetBackend(b Backend) (restore func()) {
+	mu.Lock()
+	defer mu.Unlock()
+	prev := backend
+	backend = b
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		backend = prev
+	}
+}
+
+func current() Backend {
+	mu.RLock()
+	defer mu.RUnlock()
+	return backend
+}
+
+// Available reports whether the system clipboard can be used.
+func Available() bool {
+	return current().Available()
+}
+
+// Copy writes text to the clipboard. It returns ErrUnavailable without
+// trying when there is no clipboard.
+func Copy(text string) error {
+	b := current()
+	if !b.Available() {
+		return ErrUnavailable
+	}
+	return b.WriteAll(text)
+}
+
+// Paste returns the clipboard content. It returns ErrUnavailable without
+// trying when there is no clipboard.
+func Paste() (string, error) {
+	b := current()
+	if !b.Available() {
+		return "", ErrUnavailable
+	}
+	return b.ReadAll()
+}
+
+// systemBackend uses the platform clipboard utilities.
+type systemBackend struct{}
+
+// Available reports whether a clipboard utility is installed and, on X11
+// and Wayland systems, whether there is a display session to talk to.
+func (systemBackend) Available() bool {
+	if clipboard.Unsupported {
+		return false
+	}
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return true
+	}
+	for _, env := range []string{"DISPLAY", "WAYLAND_DISPLAY", "TERMUX_VERSION", "WSL_DISTRO_NAME"} {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (systemBackend) WriteAll(text string) error { return clipboard.WriteAll(text) }
+func (systemBackend) ReadAll() (string, error)   { return clipboard.ReadAll() }
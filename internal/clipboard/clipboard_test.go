@@ -0,0 +1,79 @@
+package clipboard
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeBackend records clipboard writes
+type fakeBackend struct {
+	available bool
+	content   string
+	writes    int
+	reads     int
+}
+
+func (b *fakeBackend) Available() bool { return b.available }
+
+func (b *fakeBackend) WriteAll(text string) error {
+	b.writes++
+	b.content = text
+	return nil
+}
+
+func (b *fakeBackend) ReadAll() (string, error) {
+	b.reads++
+	return b.content, nil
+}
+
+func TestCopy_Unavailable(t *testing.T) {
+	fake := &fakeBackend{}
+	t.Cleanup(SetBackend(fake))
+
+	if Available() {
+		t.Error("Available() = true, want false")
+	}
+	if err := Copy("hello"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Copy() error = %v, want ErrUnavailable", err)
+	}
+	if _, err := Paste(); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Paste() error = %v, want ErrUnavailable", err)
+	}
+	if fake.writes != 0 || fake.reads != 0 {
+		t.Error("the backend should not be called without a clipboard")
+	}
+	if ErrUnavailable.Error() != "clipboard not available" {
+		t.Errorf("unexpected message %q", ErrUnavailable.Error())
+	}
+}
+
+func TestCopy_Available(t *testing.T) {
+	fake := &fakeBackend{available: true}
+	t.Cleanup(SetBackend(fake))
+
+	if err := Copy("hello"); err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+	if fake.writes != 1 || fake.content != "hello" {
+		t.Errorf("backend writes = %d, content = %q", fake.writes, fake.content)
+	}
+	got, err := Paste()
+	if err != nil || got != "hello" {
+		t.Errorf("Paste() = %q, %v", got, err)
+	}
+}
+
+func TestSetBackend_Restore(t *testing.T) {
+	first := &fakeBackend{available: true}
+	restoreFirst := SetBackend(first)
+	restoreSecond := SetBackend(&fakeBackend{})
+
+	restoreSecond()
+	if current() != first {
+		t.Error("restore should bring back the previous backend")
+	}
+	restoreFirst()
+	if _, ok := current().(systemBackend); !ok {
+		t.Errorf("backend = %T, want the system backend", current())
+	}
+}
@@ -42,6 +42,8 @@ HISTORY:
   By default, a history selector lets you resume previous conversations
   or start a new one. Use --new to skip the selector and start fresh.
   Conversations are automatically saved to ~/.geminiweb/history/
+  Set "resume_last": true in the config to skip the selector and
+  continue the most recently updated conversation.
 
 INITIAL PROMPT FROM FILE:
   Use --file to start the chat with content from a file:
@@ -63,7 +65,8 @@ GEMS (Server-side Personas):
     geminiweb chat --gem "Code Helper"
     geminiweb chat -g code
 
-  During chat, type /gems to switch gems without leaving the chat.
+  During chat, type /gems to switch gems without leaving the chat, and
+  /gem last (or Alt+G) to switch back to the previous gem.
   The active gem is shown in the header.
 
 LOCAL PERSONAS:
@@ -82,7 +85,7 @@ LOCAL PERSONAS:
 	cmd.Flags().StringVarP(&chatGemFlag, "gem", "g", "", "Use a gem (by ID or name) - server-side persona")
 	cmd.Flags().BoolVarP(&chatNewFlag, "new", "n", false, "Start a new conversation (skip history selector)")
 	cmd.Flags().StringVarP(&chatPersonaFlag, "persona", "p", "", "Use a local persona (system prompt)")
-	cmd.Flags().StringVarP(&chatFileFlag, "file", "f", "", "Read initial prompt from file")
+	cmd.Flags().StringVarP(&chatFileFlag, "file", "f", "", "Read initial prompt from file (supports {{date}}, {{cwd}}, {{env.NAME}})")
 
 	return cmd
 }
@@ -100,6 +103,7 @@ const maxFileSize = 1 * 1024 * 1024
 func runChat(deps *Dependencies) error {
 	modelName := getModel()
 	model := models.ModelFromName(modelName)
+	cfg, _ := config.LoadConfig()
 
 	// Determine TUI and Client implementations
 	var tuiImpl TUIInterface = &DefaultTUI{}
@@ -145,9 +149,14 @@ func runChat(deps *Dependencies) error {
 		return fmt.Errorf("failed to initialize history: %w", err)
 	}
 
-	// Select conversation (new or existing)
+	// Select conversation (new, most recent or chosen in the selector)
 	var selectedConv *history.Conversation
-	if !chatNewFlag {
+	if cfg.ResumeLast && !chatNewFlag {
+		selectedConv, err = tui.MostRecentConversation(store)
+		if err != nil {
+			return fmt.Errorf("failed to resume last conversation: %w", err)
+		}
+	} else if !chatNewFlag {
 		result, err := tuiImpl.RunHistorySelector(store, modelName)
 		if err != nil {
 			return fmt.Errorf("history selector error: %w", err)
@@ -165,14 +174,13 @@ func runChat(deps *Dependencies) error {
 	if deps != nil && deps.Client != nil {
 		client = deps.Client
 	} else {
-		// Load config for auto-close settings
-		cfg, _ := config.LoadConfig()
-
 		// Build client options
 		clientOpts := []api.ClientOption{
 			api.WithModel(model),
 			api.WithAutoRefresh(true),
+			api.WithRawResponse(true), // For /raw-response
 		}
+		clientOpts = append(clientOpts, requestClientOptions(cfg)...)
 
 		// Add browser refresh if enabled (also enables silent auto-login fallback)
 		if browserType, enabled := getBrowserRefresh(); enabled {
@@ -236,6 +244,17 @@ func runChat(deps *Dependencies) error {
 		}
 	}
 
+	// Restore the conversation's gem unless one was given explicitly, in
+	// which case the conversation is saved with it
+	if resolvedGem.ID == "" && selectedConv.GemID != "" {
+		resolvedGem = ResolvedGem{ID: selectedConv.GemID, Name: selectedConv.GemName}
+	} else if resolvedGem.ID != "" && resolvedGem.ID != selectedConv.GemID {
+		if err := store.UpdateGem(selectedConv.ID, resolvedGem.ID, resolvedGem.Name); err != nil {
+			return fmt.Errorf("failed to save gem: %w", err)
+		}
+		selectedConv.GemID, selectedConv.GemName = resolvedGem.ID, resolvedGem.Name
+	}
+
 	// Create session with conversation context
 	session := createChatSessionWithConversation(client, resolvedGem.ID, model, selectedConv)
 
@@ -260,3 +279,21 @@ func createChatSessionWithConversation(client api.GeminiClientInterface, gemID s
 
 	return session
 }
+
+// requestClientOptions maps the retry and refresh interval config settings
+// to client options. Zero values keep the client defaults.
+func requestClientOptions(cfg config.Config) []api.ClientOption {
+	var opts []api.ClientOption
+	if cfg.RequestRetries > 0 {
+		backoff := time.Duration(cfg.RequestBackoffMs) * time.Millisecond
+		opts = append(opts, api.WithRequestRetry(cfg.RequestRetries, backoff))
+	}
+	if cfg.RefreshIntervalMinutes > 0 {
+		interval := time.Duration(cfg.RefreshIntervalMinutes) * time.Minute
+		opts = append(opts, api.WithRefreshInterval(interval))
+	}
+	if cfg.RetryOnEmpty > 0 {
+		opts = append(opts, api.WithRetryOnEmpty(cfg.RetryOnEmpty))
+	}
+	return opts
+}
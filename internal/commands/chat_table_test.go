@@ -5,8 +5,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/config"
 	"github.com/diogo/geminiweb/internal/history"
 	"github.com/diogo/geminiweb/internal/models"
 	"github.com/diogo/geminiweb/internal/tui"
@@ -120,3 +122,103 @@ func TestRunChat_Table(t *testing.T) {
 		})
 	}
 }
+
+func TestRunChat_ResumeLast(t *testing.T) {
+	oldNewFlag := chatNewFlag
+	oldFileFlag := chatFileFlag
+	oldGemFlag := chatGemFlag
+	oldPersonaFlag := chatPersonaFlag
+	defer func() {
+		chatNewFlag = oldNewFlag
+		chatFileFlag = oldFileFlag
+		chatGemFlag = oldGemFlag
+		chatPersonaFlag = oldPersonaFlag
+	}()
+	chatNewFlag, chatFileFlag, chatGemFlag, chatPersonaFlag = false, "", "", ""
+
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.ResumeLast = true
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	store, err := history.DefaultStore()
+	if err != nil {
+		t.Fatalf("DefaultStore() error = %v", err)
+	}
+	older, _ := store.CreateConversation("gemini-2.5-flash")
+	newer, _ := store.CreateConversation("gemini-2.5-flash")
+	_ = store.AddMessage(newer.ID, "user", "hi", "")
+	// Writing to the older one makes it the most recent
+	time.Sleep(10 * time.Millisecond)
+	_ = store.AddMessage(older.ID, "user", "latest", "")
+
+	mockTUI := &mockTUI{}
+	if err := runChat(&Dependencies{Client: &mockGeminiClient{}, TUI: mockTUI}); err != nil {
+		t.Fatalf("runChat() error = %v", err)
+	}
+	if mockTUI.historyCalled {
+		t.Error("the history selector should be skipped")
+	}
+	if mockTUI.chatConv == nil || mockTUI.chatConv.ID != older.ID {
+		t.Errorf("chat opened %+v, want the most recent conversation %s", mockTUI.chatConv, older.ID)
+	}
+}
+
+func TestRunChat_SavesGemWithConversation(t *testing.T) {
+	oldNewFlag := chatNewFlag
+	oldFileFlag := chatFileFlag
+	oldGemFlag := chatGemFlag
+	oldPersonaFlag := chatPersonaFlag
+	defer func() {
+		chatNewFlag = oldNewFlag
+		chatFileFlag = oldFileFlag
+		chatGemFlag = oldGemFlag
+		chatPersonaFlag = oldPersonaFlag
+	}()
+
+	client := &mockGeminiClient{
+		fetchGemsFunc: func(includeHidden bool) (*models.GemJar, error) {
+			jar := models.GemJar{"gem-1": {ID: "gem-1", Name: "Coder"}}
+			return &jar, nil
+		},
+	}
+
+	t.Run("new conversation", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		chatNewFlag, chatFileFlag, chatGemFlag, chatPersonaFlag = true, "", "Coder", ""
+
+		mockTUI := &mockTUI{}
+		if err := runChat(&Dependencies{Client: client, TUI: mockTUI}); err != nil {
+			t.Fatalf("runChat() error = %v", err)
+		}
+		store, _ := history.DefaultStore()
+		saved, err := store.GetConversation(mockTUI.chatConv.ID)
+		if err != nil || saved.GemID != "gem-1" || saved.GemName != "Coder" {
+			t.Errorf("stored conversation = %+v (%v), want gem-1/Coder", saved, err)
+		}
+	})
+
+	t.Run("gem flag overrides a resumed conversation's gem", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		chatNewFlag, chatFileFlag, chatGemFlag, chatPersonaFlag = false, "", "gem-1", ""
+		cfg := config.DefaultConfig()
+		cfg.ResumeLast = true
+		if err := config.SaveConfig(cfg); err != nil {
+			t.Fatalf("SaveConfig() error = %v", err)
+		}
+		store, _ := history.DefaultStore()
+		conv, _ := store.CreateConversation("gemini-2.5-flash")
+		_ = store.UpdateGem(conv.ID, "gem-old", "Writer")
+
+		mockTUI := &mockTUI{}
+		if err := runChat(&Dependencies{Client: client, TUI: mockTUI}); err != nil {
+			t.Fatalf("runChat() error = %v", err)
+		}
+		saved, err := store.GetConversation(conv.ID)
+		if err != nil || saved.GemID != "gem-1" || saved.GemName != "Coder" {
+			t.Errorf("stored conversation = %+v (%v), want gem-1/Coder", saved, err)
+		}
+	})
+}
@@ -5,7 +5,10 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/config"
 	"github.com/diogo/geminiweb/internal/history"
 	"github.com/diogo/geminiweb/internal/models"
 )
@@ -263,3 +266,70 @@ func TestCreateChatSessionWithConversation_WithEmptyGemID(t *testing.T) {
 		t.Error("Expected non-nil session with empty gem ID")
 	}
 }
+
+func TestRequestClientOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		wantOpts    int
+		wantRetries int
+		wantBackoff time.Duration
+		wantRefresh time.Duration
+		wantEmpty   int
+	}{
+		{"defaults add no options", config.Config{}, 0, 0, 0, 9 * time.Minute, 0},
+		{
+			name:        "retries with backoff",
+			cfg:         config.Config{RequestRetries: 3, RequestBackoffMs: 250},
+			wantOpts:    1,
+			wantRetries: 3,
+			wantBackoff: 250 * time.Millisecond,
+			wantRefresh: 9 * time.Minute,
+		},
+		{
+			name:        "refresh interval",
+			cfg:         config.Config{RefreshIntervalMinutes: 20},
+			wantOpts:    1,
+			wantRefresh: 20 * time.Minute,
+		},
+		{
+			name:        "backoff ignored without retries",
+			cfg:         config.Config{RequestBackoffMs: 250},
+			wantRefresh: 9 * time.Minute,
+		},
+		{
+			name:        "retry on empty",
+			cfg:         config.Config{RetryOnEmpty: 2},
+			wantOpts:    1,
+			wantRefresh: 9 * time.Minute,
+			wantEmpty:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := requestClientOptions(tt.cfg)
+			if len(opts) != tt.wantOpts {
+				t.Fatalf("got %d options, want %d", len(opts), tt.wantOpts)
+			}
+
+			client, err := api.NewClient(nil, opts...)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			info := client.ClientInfo()
+			if info.RequestRetries != tt.wantRetries {
+				t.Errorf("RequestRetries = %d, want %d", info.RequestRetries, tt.wantRetries)
+			}
+			if info.RequestBackoff != tt.wantBackoff {
+				t.Errorf("RequestBackoff = %v, want %v", info.RequestBackoff, tt.wantBackoff)
+			}
+			if info.RefreshInterval != tt.wantRefresh {
+				t.Errorf("RefreshInterval = %v, want %v", info.RefreshInterval, tt.wantRefresh)
+			}
+			if got := client.StartChat().RetryOnEmpty(); got != tt.wantEmpty {
+				t.Errorf("session RetryOnEmpty = %d, want %d", got, tt.wantEmpty)
+			}
+		})
+	}
+}
@@ -18,6 +18,10 @@ type mockTUI struct {
 	runChatErr    error
 	historyRes    tui.HistorySelectorResult
 	historyErr    error
+
+	// Recorded calls
+	historyCalled bool
+	chatConv      *history.Conversation
 }
 
 func (m *mockTUI) RunGemsTUI(client api.GeminiClientInterface, includeHidden bool) (tui.GemsTUIResult, error) {
@@ -29,10 +33,12 @@ func (m *mockTUI) RunChatWithSession(client api.GeminiClientInterface, session t
 }
 
 func (m *mockTUI) RunHistorySelector(store tui.HistoryStore, modelName string) (tui.HistorySelectorResult, error) {
+	m.historyCalled = true
 	return m.historyRes, m.historyErr
 }
 
 func (m *mockTUI) RunChatWithInitialPrompt(client api.GeminiClientInterface, session tui.ChatSessionInterface, modelName string, conv *history.Conversation, store tui.HistoryStoreInterface, gemName string, persona *config.Persona, initialPrompt string) error {
+	m.chatConv = conv
 	return m.runChatErr
 }
 
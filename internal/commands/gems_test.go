@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -18,13 +19,14 @@ type mockGeminiClientForGems struct {
 	*mockGeminiClient
 }
 
-func (m *mockGeminiClientForGems) Init() error                 { return nil }
-func (m *mockGeminiClientForGems) Close()                      {}
-func (m *mockGeminiClientForGems) GetAccessToken() string      { return "test" }
-func (m *mockGeminiClientForGems) GetCookies() *config.Cookies { return &config.Cookies{} }
-func (m *mockGeminiClientForGems) GetModel() models.Model      { return models.ModelFast }
-func (m *mockGeminiClientForGems) SetModel(model models.Model) {}
-func (m *mockGeminiClientForGems) IsClosed() bool              { return m.closed }
+func (m *mockGeminiClientForGems) Init() error                    { return nil }
+func (m *mockGeminiClientForGems) Ping(ctx context.Context) error { return nil }
+func (m *mockGeminiClientForGems) Close()                         {}
+func (m *mockGeminiClientForGems) GetAccessToken() string         { return "test" }
+func (m *mockGeminiClientForGems) GetCookies() *config.Cookies    { return &config.Cookies{} }
+func (m *mockGeminiClientForGems) GetModel() models.Model         { return models.ModelFast }
+func (m *mockGeminiClientForGems) SetModel(model models.Model)    {}
+func (m *mockGeminiClientForGems) IsClosed() bool                 { return m.closed }
 func (m *mockGeminiClientForGems) StartChat(model ...models.Model) *api.ChatSession {
 	return &api.ChatSession{}
 }
@@ -37,7 +39,7 @@ func (m *mockGeminiClientForGems) GenerateContent(prompt string, opts *api.Gener
 func (m *mockGeminiClientForGems) UploadImage(filePath string) (*api.UploadedImage, error) {
 	return nil, nil
 }
-func (m *mockGeminiClientForGems) UploadFile(filePath string) (*api.UploadedFile, error) {
+func (m *mockGeminiClientForGems) UploadFile(filePath string, opts ...api.UploadOption) (*api.UploadedFile, error) {
 	return nil, nil
 }
 func (m *mockGeminiClientForGems) DownloadImage(img models.WebImage, opts api.ImageDownloadOptions) (string, error) {
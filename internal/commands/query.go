@@ -1,17 +1,18 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/term"
 
 	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/clipboard"
 	"github.com/diogo/geminiweb/internal/config"
 	apierrors "github.com/diogo/geminiweb/internal/errors"
 	"github.com/diogo/geminiweb/internal/models"
@@ -441,7 +442,12 @@ func runQuery(deps *Dependencies, prompt string, rawOutput bool) error {
 
 	// Copy to clipboard if enabled in config (cfg was loaded at function start)
 	if cfg.CopyToClipboard {
-		if err := clipboard.WriteAll(text); err != nil {
+		if err := clipboard.Copy(text); errors.Is(err, clipboard.ErrUnavailable) {
+			warnMsg := lipgloss.NewStyle().Foreground(lipgloss.Color("#f7768e")).Render(
+				"⚠ Clipboard not available; skipped copying",
+			)
+			fmt.Fprintln(os.Stderr, warnMsg)
+		} else if err != nil {
 			// Log warning but don't fail
 			warnMsg := lipgloss.NewStyle().Foreground(lipgloss.Color("#f7768e")).Render(
 				fmt.Sprintf("⚠ Failed to copy to clipboard: %v", err),
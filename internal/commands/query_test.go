@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -44,6 +45,7 @@ func (m *mockGeminiClient) Init() error {
 	}
 	return nil
 }
+func (m *mockGeminiClient) Ping(ctx context.Context) error { return nil }
 
 func (m *mockGeminiClient) Close() {}
 
@@ -126,7 +128,7 @@ func (m *mockGeminiClient) UploadImage(filePath string) (*api.UploadedImage, err
 	return nil, nil
 }
 
-func (m *mockGeminiClient) UploadFile(filePath string) (*api.UploadedFile, error) {
+func (m *mockGeminiClient) UploadFile(filePath string, opts ...api.UploadOption) (*api.UploadedFile, error) {
 	return nil, nil
 }
 
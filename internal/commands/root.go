@@ -2,14 +2,17 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/diogo/geminiweb/internal/browser"
 	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/models"
 	"github.com/diogo/geminiweb/internal/tui"
 )
 
@@ -23,6 +26,7 @@ var (
 	gemFlag            string
 	saveImagesFlag     string
 	personaFlag        string
+	profileFlag        string
 
 	// Version info (set at build time)
 	Version   = "0.1.0"
@@ -52,8 +56,17 @@ Examples:
   cat prompt.md | geminiweb             Read prompt from stdin
   geminiweb "Hello" -o response.md      Save response to file
   geminiweb --gem "Code Helper" "prompt" Use a gem (server-side persona)
-  geminiweb --persona coder "prompt"    Use a local persona (system prompt)`,
+  geminiweb --persona coder "prompt"    Use a local persona (system prompt)
+  geminiweb --profile work chat         Use the "work" profile's account and settings`,
 		Args: cobra.MaximumNArgs(1),
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := selectProfile()
+			if errors.Is(err, config.ErrUnknownProfile) {
+				return err
+			}
+			warnConfigProblems(cmd.ErrOrStderr(), cfg, err)
+			return validateModelFlag()
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Check for version flag
 			if v, _ := cmd.Flags().GetBool("version"); v {
@@ -102,7 +115,9 @@ Examples:
 	}
 
 	// Global flags
-	cmd.PersistentFlags().StringVarP(&modelFlag, "model", "m", "", "Model to use (e.g., gemini-2.5-flash)")
+	cmd.PersistentFlags().StringVarP(&modelFlag, "model", "m", "", "Model to use (fast, pro, thinking or an alias such as flash)")
+	cmd.PersistentFlags().StringVar(&profileFlag, "profile", "",
+		"Use a named profile from the config (default $"+config.ProfileEnvVar+")")
 	cmd.PersistentFlags().StringVar(&browserRefreshFlag, "browser-refresh", "",
 		"Auto-refresh cookies from browser on auth failure (auto, chrome, firefox, edge, chromium, opera)")
 	cmd.Flags().StringVarP(&outputFlag, "output", "o", "", "Save response to file")
@@ -137,6 +152,43 @@ func Execute() {
 	}
 }
 
+// selectProfile selects the --profile profile, or GEMINIWEB_PROFILE's,
+// for every later config load and returns the config it selects. The error
+// wraps config.ErrUnknownProfile for a profile the config does not define.
+func selectProfile() (config.Config, error) {
+	config.SetProfile(profileFlag)
+	return config.LoadConfig()
+}
+
+// validateModelFlag rejects a --model value that is not a known model name
+// or alias
+func validateModelFlag() error {
+	if modelFlag == "" {
+		return nil
+	}
+	if _, ok := models.Lookup(modelFlag); ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(models.AllModels()))
+	for _, model := range models.AllModels() {
+		names = append(names, model.Name)
+	}
+	return fmt.Errorf("unknown model %q (available: %s)", modelFlag, strings.Join(names, ", "))
+}
+
+// warnConfigProblems prints the problems found in cfg, loaded with loadErr,
+// which would otherwise be ignored silently, without stopping the command
+func warnConfigProblems(w io.Writer, cfg config.Config, loadErr error) {
+	if loadErr != nil {
+		fmt.Fprintf(w, "Warning: %v; using default settings\n", loadErr)
+		return
+	}
+	for _, warning := range config.Validate(cfg) {
+		fmt.Fprintf(w, "Warning: config %s\n", warning)
+	}
+}
+
 // getModel returns the model to use (from flag or config)
 func getModel() string {
 	if modelFlag != "" {
@@ -5,9 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"github.com/diogo/geminiweb/internal/config"
 )
 
 func TestRootCommand_Help(t *testing.T) {
@@ -573,3 +577,64 @@ func TestExecuteWrapper(t *testing.T) {
 		_ = executeFunc // Use the variable to confirm assignment succeeded
 	})
 }
+
+func TestValidateModelFlag(t *testing.T) {
+	oldModelFlag := modelFlag
+	defer func() { modelFlag = oldModelFlag }()
+
+	for _, name := range []string{"", "fast", "flash", "2.5-flash", "PRO", "thinking"} {
+		modelFlag = name
+		if err := validateModelFlag(); err != nil {
+			t.Errorf("validateModelFlag(%q) unexpected error: %v", name, err)
+		}
+	}
+
+	modelFlag = "ultra"
+	err := validateModelFlag()
+	if err == nil {
+		t.Fatal("expected an error for an unknown model")
+	}
+	if err.Error() != `unknown model "ultra" (available: fast, pro, thinking)` {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// warnLoadedConfig loads the config as the root command does and prints
+// its problems
+func warnLoadedConfig(w io.Writer) {
+	cfg, err := config.LoadConfig()
+	warnConfigProblems(w, cfg, err)
+}
+
+func TestWarnConfigProblems(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".geminiweb"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(home, ".geminiweb", "config.json")
+
+	var out strings.Builder
+	warnLoadedConfig(&out)
+	if out.Len() != 0 {
+		t.Errorf("missing config should print nothing, got %q", out.String())
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"default_model": "gpt-4", "tui_theme": "nord"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	warnLoadedConfig(&out)
+	if got := out.String(); !strings.Contains(got, `Warning: config default_model: unknown model "gpt-4"`) || strings.Contains(got, "tui_theme") {
+		t.Errorf("unexpected warnings: %q", got)
+	}
+
+	if err := os.WriteFile(configPath, []byte(`{"default_model": `), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	warnLoadedConfig(&out)
+	if got := out.String(); !strings.Contains(got, "failed to parse config file") || !strings.Contains(got, "using default settings") {
+		t.Errorf("unexpected warning for a broken config: %q", got)
+	}
+}
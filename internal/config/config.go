@@ -40,6 +40,132 @@ type Config struct {
 	TUITheme         string         `json:"tui_theme,omitempty"`    // TUI color theme
 	DownloadDir      string         `json:"download_dir,omitempty"` // Directory for saving images
 	Markdown         MarkdownConfig `json:"markdown,omitempty"`
+	// AttachmentNote prepends a short note listing attached files (with a
+	// snippet of small text files) to prompts sent with attachments.
+	AttachmentNote bool `json:"attachment_note,omitempty"`
+	// ToolPlanReview shows multiple tool calls from one response together as a
+	// plan to approve or deny before any of them runs.
+	ToolPlanReview bool `json:"tool_plan_review,omitempty"`
+	// AlwaysTrustedTools lists tools (e.g. read-only ones like list_dir) that
+	// never prompt for confirmation, regardless of RequiresConfirmation.
+	AlwaysTrustedTools []string `json:"always_trusted_tools,omitempty"`
+	// ShowTimestamps renders a relative time next to each chat message.
+	ShowTimestamps bool `json:"show_timestamps,omitempty"`
+	// StoreThoughts persists the model's thoughts to conversation history.
+	// When false, thoughts are still shown during the session but saved
+	// messages have an empty thoughts field. Default is true.
+	StoreThoughts bool `json:"store_thoughts"`
+	// RequestRetries is how many times a transiently failed request (network
+	// error, HTTP 429 or 5xx) is retried in chat. 0 disables retries.
+	RequestRetries int `json:"request_retries,omitempty"`
+	// RequestBackoffMs is the wait before the first retry, in milliseconds.
+	// It doubles with each further retry.
+	RequestBackoffMs int `json:"request_backoff_ms,omitempty"`
+	// RetryOnEmpty is how many times a prompt is resent in chat when the
+	// response comes back without any content. 0 disables the retry.
+	RetryOnEmpty int `json:"retry_on_empty,omitempty"`
+	// RefreshIntervalMinutes overrides the cookie rotation interval in chat.
+	// 0 keeps the client default (9 minutes).
+	RefreshIntervalMinutes int `json:"refresh_interval_minutes,omitempty"`
+	// MaxToolCallsPerTurn caps how many tool calls from one response are
+	// executed; the rest are skipped with a notice. 0 means no limit.
+	MaxToolCallsPerTurn int `json:"max_tool_calls_per_turn,omitempty"`
+	// PromptPrefix and PromptSuffix wrap every prompt typed in chat with
+	// standing instructions (e.g. "always cite sources"). They are sent to
+	// the model but not shown in the conversation.
+	PromptPrefix string `json:"prompt_prefix,omitempty"`
+	PromptSuffix string `json:"prompt_suffix,omitempty"`
+	// StreamToolOutput shows the output of streaming tools (e.g. bash) in
+	// chat as it is produced instead of when the tool finishes.
+	StreamToolOutput bool `json:"stream_tool_output,omitempty"`
+	// ListTools offers the list_tools tool, so the model can discover the
+	// other tools it can call.
+	ListTools bool `json:"list_tools,omitempty"`
+	// NoEmoji replaces the emoji used in the TUI with ASCII labels such as
+	// "[attach]" and "[thinking]", for screen readers and plain terminals.
+	NoEmoji bool `json:"no_emoji,omitempty"`
+	// MaxContentWidth caps the width of the chat panels, in columns, so text
+	// stays readable on very wide terminals; the panels are centered. 0
+	// uses the full terminal width.
+	MaxContentWidth int `json:"max_content_width,omitempty"`
+	// AttachURLHosts lists the hosts /attach-url downloads from, redirects
+	// included; a host also allows its subdomains and "*" allows any host.
+	// Empty disables /attach-url.
+	AttachURLHosts []string `json:"attach_url_hosts,omitempty"`
+	// Snippets are saved prompt templates, by name, inserted into the chat
+	// input with /snippet <name>.
+	Snippets map[string]string `json:"snippets,omitempty"`
+	// OutputBudget caps the characters of replies received in one chat
+	// session. Nearing it shows a warning in the status bar; once it is
+	// used up, sending asks for confirmation. 0 disables the budget.
+	OutputBudget int `json:"output_budget,omitempty"`
+	// CostPer1KTokens is the price of 1000 reply tokens, in dollars, used
+	// by /cost to estimate what each reply cost. 0 shows only the token
+	// estimate.
+	CostPer1KTokens float64 `json:"cost_per_1k_tokens,omitempty"`
+	// RetryWordDiff shows, under a reply regenerated with /retry, a
+	// word-level diff against the reply it replaced.
+	RetryWordDiff bool `json:"retry_word_diff,omitempty"`
+	// CompactMessages renders chat messages without bubble borders, using
+	// only colored labels and indentation, so narrow terminals keep more
+	// columns for text.
+	CompactMessages bool `json:"compact_messages,omitempty"`
+	// StructuredToolResults re-sends the results of a turn's tool calls to
+	// the model as one JSON array (tool name, args, output, error, exit
+	// code) instead of a text block per result.
+	StructuredToolResults bool `json:"structured_tool_results,omitempty"`
+	// ResumeLast makes chat skip the history selector and continue the most
+	// recently updated conversation (a new one when there are none).
+	// --new still starts a fresh conversation.
+	ResumeLast bool `json:"resume_last,omitempty"`
+	// ConfirmDestructive asks for confirmation before /clear and /delete
+	// discard attachments or a conversation
+	ConfirmDestructive bool `json:"confirm_destructive"`
+	// SendKey chooses the key that sends a chat message: SendKeyEnter
+	// (Enter sends, \ + Enter inserts a newline) or SendKeyCtrlEnter (Enter
+	// inserts a newline, Ctrl+Enter sends). Empty means SendKeyEnter.
+	SendKey string `json:"send_key,omitempty"`
+	// Profiles are named settings for other accounts, selected with
+	// --profile or GEMINIWEB_PROFILE (see LoadProfile).
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// ActiveProfile is the name of the profile LoadProfile applied, if any.
+	// It is not saved.
+	ActiveProfile string `json:"-"`
+}
+
+// Values for Config.SendKey
+const (
+	SendKeyEnter     = "enter"
+	SendKeyCtrlEnter = "ctrl+enter"
+)
+
+// Upper bounds for the request retry and cookie refresh settings
+const (
+	MaxRequestRetries         = 10
+	MaxRequestBackoffMs       = 60000 // 1 minute
+	MaxRetryOnEmpty           = 5
+	MaxRefreshIntervalMinutes = 60
+)
+
+// clampClientSettings keeps the request retry and refresh interval settings
+// within sane ranges. Negative values fall back to 0 (the default) and
+// values above the upper bounds are capped.
+func (c *Config) clampClientSettings() {
+	c.RequestRetries = clampInt(c.RequestRetries, 0, MaxRequestRetries)
+	c.RequestBackoffMs = clampInt(c.RequestBackoffMs, 0, MaxRequestBackoffMs)
+	c.RetryOnEmpty = clampInt(c.RetryOnEmpty, 0, MaxRetryOnEmpty)
+	c.RefreshIntervalMinutes = clampInt(c.RefreshIntervalMinutes, 0, MaxRefreshIntervalMinutes)
+}
+
+// clampInt limits v to the range [lo, hi]
+func clampInt(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
 }
 
 // DefaultMarkdownConfig returns the default markdown configuration
@@ -57,16 +183,18 @@ func DefaultMarkdownConfig() MarkdownConfig {
 func DefaultConfig() Config {
 	homeDir, _ := os.UserHomeDir()
 	return Config{
-		DefaultModel:     "fast",
-		AutoClose:        true,
-		CloseDelay:       300, // 5 minutes
-		AutoReInit:       true,
-		Verbose:          false,
-		CopyToClipboard:  false,
-		AutoApproveTools: false,
-		TUITheme:         "tokyonight",
-		DownloadDir:      filepath.Join(homeDir, ".geminiweb", "images"),
-		Markdown:         DefaultMarkdownConfig(),
+		DefaultModel:       "fast",
+		AutoClose:          true,
+		CloseDelay:         300, // 5 minutes
+		AutoReInit:         true,
+		Verbose:            false,
+		CopyToClipboard:    false,
+		AutoApproveTools:   false,
+		TUITheme:           "tokyonight",
+		DownloadDir:        filepath.Join(homeDir, ".geminiweb", "images"),
+		Markdown:           DefaultMarkdownConfig(),
+		StoreThoughts:      true,
+		ConfirmDestructive: true,
 	}
 }
 
@@ -105,12 +233,20 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(configDir, "config.json"), nil
 }
 
-// GetCookiesPath returns the path to the cookies file
+// GetCookiesPath returns the path to the cookies file: the selected
+// profile's cookies_path if it sets one, else cookies.json in the config
+// directory
 func GetCookiesPath() (string, error) {
 	configDir, err := GetConfigDir()
 	if err != nil {
 		return "", err
 	}
+	if profile, ok := selectedProfileSettings(); ok && profile.CookiesPath != "" {
+		if filepath.IsAbs(profile.CookiesPath) {
+			return profile.CookiesPath, nil
+		}
+		return filepath.Join(configDir, profile.CookiesPath), nil
+	}
 	return filepath.Join(configDir, "cookies.json"), nil
 }
 
@@ -133,8 +269,15 @@ func GetDownloadDir(cfg Config) (string, error) {
 	return dir, nil
 }
 
-// LoadConfig loads the configuration from disk
+// LoadConfig loads the configuration from disk, with the selected profile
+// (see SelectedProfile) applied
 func LoadConfig() (Config, error) {
+	return LoadProfile(SelectedProfile())
+}
+
+// loadConfigFile loads the configuration from disk without applying a
+// profile
+func loadConfigFile() (Config, error) {
 	cfg := DefaultConfig()
 
 	configPath, err := GetConfigPath()
@@ -153,12 +296,18 @@ func LoadConfig() (Config, error) {
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return DefaultConfig(), fmt.Errorf("failed to parse config file: %w", err)
 	}
+	cfg.clampClientSettings()
 
 	return cfg, nil
 }
 
-// SaveConfig saves the configuration to disk
+// SaveConfig saves the configuration to disk. Settings the active profile
+// overrides are saved to the profile.
 func SaveConfig(cfg Config) error {
+	if cfg.ActiveProfile != "" {
+		cfg = unapplyProfile(cfg)
+	}
+
 	configDir, err := EnsureConfigDir()
 	if err != nil {
 		return err
@@ -245,6 +245,64 @@ func TestLoadConfig_WithExistingFile(t *testing.T) {
 	}
 }
 
+func TestLoadConfig_ClampsClientSettings(t *testing.T) {
+	tests := []struct {
+		name                                  string
+		json                                  string
+		wantRetries, wantBackoff, wantRefresh int
+	}{
+		{"valid values kept", `{"request_retries": 3, "request_backoff_ms": 500, "refresh_interval_minutes": 15}`, 3, 500, 15},
+		{"negative values reset", `{"request_retries": -1, "request_backoff_ms": -100, "refresh_interval_minutes": -5}`, 0, 0, 0},
+		{"large values capped", `{"request_retries": 100, "request_backoff_ms": 999999, "refresh_interval_minutes": 1440}`, MaxRequestRetries, MaxRequestBackoffMs, MaxRefreshIntervalMinutes},
+		{"unset values default", `{}`, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("HOME", tmpDir)
+
+			configDir := filepath.Join(tmpDir, ".geminiweb")
+			_ = os.MkdirAll(configDir, 0o755)
+			if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(tt.json), 0o644); err != nil {
+				t.Fatalf("Failed to write config file: %v", err)
+			}
+
+			cfg, err := LoadConfig()
+			if err != nil {
+				t.Fatalf("LoadConfig() returned error: %v", err)
+			}
+			if cfg.RequestRetries != tt.wantRetries {
+				t.Errorf("RequestRetries = %d, want %d", cfg.RequestRetries, tt.wantRetries)
+			}
+			if cfg.RequestBackoffMs != tt.wantBackoff {
+				t.Errorf("RequestBackoffMs = %d, want %d", cfg.RequestBackoffMs, tt.wantBackoff)
+			}
+			if cfg.RefreshIntervalMinutes != tt.wantRefresh {
+				t.Errorf("RefreshIntervalMinutes = %d, want %d", cfg.RefreshIntervalMinutes, tt.wantRefresh)
+			}
+		})
+	}
+
+	t.Run("retry on empty is capped", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		t.Setenv("HOME", tmpDir)
+		configDir := filepath.Join(tmpDir, ".geminiweb")
+		_ = os.MkdirAll(configDir, 0o755)
+		if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"retry_on_empty": 50}`), 0o644); err != nil {
+			t.Fatalf("Failed to write config file: %v", err)
+		}
+
+		cfg, err := LoadConfig()
+		if err != nil {
+			t.Fatalf("LoadConfig() returned error: %v", err)
+		}
+		if cfg.RetryOnEmpty != MaxRetryOnEmpty {
+			t.Errorf("RetryOnEmpty = %d, want %d", cfg.RetryOnEmpty, MaxRetryOnEmpty)
+		}
+	})
+}
+
 func TestLoadConfig_InvalidJSON(t *testing.T) {
 	tmpDir := t.TempDir()
 	oldHome := os.Getenv("HOME")
@@ -406,4 +464,10 @@ func TestDefaultConfig_AllFields(t *testing.T) {
 	if cfg.Markdown.Style != "dark" {
 		t.Errorf("Markdown.Style = %q, want 'dark'", cfg.Markdown.Style)
 	}
+	if !cfg.StoreThoughts {
+		t.Error("StoreThoughts should be true")
+	}
+	if !cfg.ConfirmDestructive {
+		t.Error("ConfirmDestructive should be true")
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 )
 
@@ -107,12 +108,16 @@ func parseCookies(data []byte) (*Cookies, error) {
 
 // SaveCookies saves cookies to the cookies file
 func SaveCookies(cookies *Cookies) error {
-	configDir, err := EnsureConfigDir()
+	if _, err := EnsureConfigDir(); err != nil {
+		return err
+	}
+	cookiesPath, err := GetCookiesPath()
 	if err != nil {
 		return err
 	}
-
-	cookiesPath := configDir + "/cookies.json"
+	if err := os.MkdirAll(filepath.Dir(cookiesPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create cookies directory: %w", err)
+	}
 
 	// Save in list format for compatibility
 	listFormat := []CookieListItem{
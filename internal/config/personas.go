@@ -47,9 +47,13 @@ func DefaultPersonas() []Persona {
 				"- bash: Executes shell commands (requires confirmation)\n" +
 				"  Args: {\"command\": \"string\"}\n" +
 				"- file_read: Reads file contents\n" +
-				"  Args: {\"path\": \"string\", \"lines\": number (optional)}\n" +
+				"  Args: {\"path\": \"string\", \"lines\": number (optional), \"start_line\": number (optional), \"end_line\": number (optional)}\n" +
 				"- file_write: Writes file contents (requires confirmation)\n" +
 				"  Args: {\"path\": \"string\", \"content\": \"string\"}\n" +
+				"- file_edit: Replaces text in a file and returns a diff (requires confirmation)\n" +
+				"  Args: {\"path\": \"string\", \"search\": \"string\", \"replace\": \"string\", \"type\": \"literal|regex\" (optional), \"count\": number (optional), \"dry_run\": bool (optional)}\n" +
+				"- multi_file_write: Writes several files at once, rolling back on failure (requires confirmation)\n" +
+				"  Args: {\"files\": [{\"path\": \"string\", \"content\": \"string\", \"append\": bool (optional)}]}\n" +
 				"- search: Searches files\n" +
 				"  Args: {\"pattern\": \"string\", \"path\": \"string (optional)\", \"type\": \"regex|literal\"}\n\n" +
 				"Guidelines:\n" +
@@ -273,8 +277,13 @@ func SetDefaultPersona(name string) error {
 	return SavePersonas(config)
 }
 
-// GetDefaultPersona returns the default persona
+// GetDefaultPersona returns the default persona: the selected profile's
+// persona if it sets one, else the default from personas.json
 func GetDefaultPersona() (*Persona, error) {
+	if profile, ok := selectedProfileSettings(); ok && profile.Persona != "" {
+		return GetPersona(profile.Persona)
+	}
+
 	config, err := LoadPersonas()
 	if err != nil {
 		return nil, err
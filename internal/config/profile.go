@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// ProfileEnvVar is the environment variable that selects a profile when
+// none is set with SetProfile (the --profile flag)
+const ProfileEnvVar = "GEMINIWEB_PROFILE"
+
+// ErrUnknownProfile is returned by LoadProfile for a profile the config
+// does not define
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// Profile is a named set of settings for one account, such as "work" or
+// "personal", stored under "profiles" in the config file. Fields that are
+// set override the top-level config; empty ones keep it.
+type Profile struct {
+	// CookiesPath is the profile's cookies file. A relative path is
+	// relative to the config directory.
+	CookiesPath  string `json:"cookies_path,omitempty"`
+	DefaultModel string `json:"default_model,omitempty"`
+	TUITheme     string `json:"tui_theme,omitempty"`
+	// Persona is the local persona used by default, instead of the default
+	// set in personas.json.
+	Persona string `json:"persona,omitempty"`
+}
+
+// selectedProfile is the profile set with SetProfile
+var selectedProfile string
+
+// SetProfile selects the profile LoadConfig applies, overriding
+// GEMINIWEB_PROFILE. An empty name leaves the choice to the variable.
+func SetProfile(name string) {
+	selectedProfile = strings.TrimSpace(name)
+}
+
+// SelectedProfile returns the name of the selected profile: the one set
+// with SetProfile, else GEMINIWEB_PROFILE. Empty means no profile.
+func SelectedProfile() string {
+	if selectedProfile != "" {
+		return selectedProfile
+	}
+	return strings.TrimSpace(os.Getenv(ProfileEnvVar))
+}
+
+// LoadProfile loads the configuration from disk with the named profile
+// applied. An empty name loads the top-level configuration. A name the
+// config does not define returns ErrUnknownProfile along with the
+// top-level configuration.
+func LoadProfile(name string) (Config, error) {
+	cfg, err := loadConfigFile()
+	if err != nil || name == "" {
+		return cfg, err
+	}
+
+	profile, ok := cfg.Profiles[name]
+	if !ok {
+		defined := "none"
+		if len(cfg.Profiles) > 0 {
+			defined = strings.Join(slices.Sorted(maps.Keys(cfg.Profiles)), ", ")
+		}
+		return cfg, fmt.Errorf("%w %q (defined: %s)", ErrUnknownProfile, name, defined)
+	}
+
+	cfg.ActiveProfile = name
+	if profile.DefaultModel != "" {
+		cfg.DefaultModel = profile.DefaultModel
+	}
+	if profile.TUITheme != "" {
+		cfg.TUITheme = profile.TUITheme
+	}
+	return cfg, nil
+}
+
+// selectedProfileSettings returns the settings of the selected profile, if
+// one is selected and defined
+func selectedProfileSettings() (Profile, bool) {
+	name := SelectedProfile()
+	if name == "" {
+		return Profile{}, false
+	}
+	cfg, err := loadConfigFile()
+	if err != nil {
+		return Profile{}, false
+	}
+	profile, ok := cfg.Profiles[name]
+	return profile, ok
+}
+
+// unapplyProfile reverses LoadProfile before saving: the settings the
+// active profile overrides go back into the profile, and the top-level
+// values on disk are kept, so editing a profile leaves the others alone.
+func unapplyProfile(cfg Config) Config {
+	profile, ok := cfg.Profiles[cfg.ActiveProfile]
+	if !ok {
+		return cfg
+	}
+	base, err := loadConfigFile()
+	if err != nil {
+		base = DefaultConfig()
+	}
+
+	if profile.DefaultModel != "" {
+		profile.DefaultModel, cfg.DefaultModel = cfg.DefaultModel, base.DefaultModel
+	}
+	if profile.TUITheme != "" {
+		profile.TUITheme, cfg.TUITheme = cfg.TUITheme, base.TUITheme
+	}
+	cfg.Profiles = maps.Clone(cfg.Profiles)
+	cfg.Profiles[cfg.ActiveProfile] = profile
+	return cfg
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProfileConfig writes a config with "work" and "personal" profiles to
+// a temporary HOME and returns the config directory
+func writeProfileConfig(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv(ProfileEnvVar, "")
+	t.Cleanup(func() { SetProfile("") })
+
+	configDir := filepath.Join(tmpDir, ".geminiweb")
+	if err := os.MkdirAll(configDir, 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.Profiles = map[string]Profile{
+		"work": {
+			CookiesPath:  "work-cookies.json",
+			DefaultModel: "pro",
+			TUITheme:     "nord",
+			Persona:      "coder",
+		},
+		"personal": {CookiesPath: "/tmp/personal-cookies.json"},
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return configDir
+}
+
+func TestLoadProfile_SelectsProfile(t *testing.T) {
+	configDir := writeProfileConfig(t)
+
+	cfg, err := LoadProfile("work")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.ActiveProfile != "work" || cfg.DefaultModel != "pro" || cfg.TUITheme != "nord" {
+		t.Errorf("profile not applied: active=%q model=%q theme=%q", cfg.ActiveProfile, cfg.DefaultModel, cfg.TUITheme)
+	}
+
+	SetProfile("work")
+	path, err := GetCookiesPath()
+	if err != nil {
+		t.Fatalf("GetCookiesPath() error = %v", err)
+	}
+	if want := filepath.Join(configDir, "work-cookies.json"); path != want {
+		t.Errorf("GetCookiesPath() = %q, want %q", path, want)
+	}
+
+	SetProfile("personal")
+	if path, _ := GetCookiesPath(); path != "/tmp/personal-cookies.json" {
+		t.Errorf("absolute cookies_path: GetCookiesPath() = %q", path)
+	}
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.ActiveProfile != "personal" || cfg.DefaultModel != "fast" {
+		t.Errorf("unset profile fields should keep the top-level config, got model %q", cfg.DefaultModel)
+	}
+}
+
+func TestLoadProfile_DefaultsWithoutProfile(t *testing.T) {
+	configDir := writeProfileConfig(t)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.ActiveProfile != "" || cfg.DefaultModel != "fast" {
+		t.Errorf("no profile selected: active=%q model=%q", cfg.ActiveProfile, cfg.DefaultModel)
+	}
+	if path, _ := GetCookiesPath(); path != filepath.Join(configDir, "cookies.json") {
+		t.Errorf("GetCookiesPath() = %q, want the default cookies file", path)
+	}
+}
+
+func TestLoadProfile_Unknown(t *testing.T) {
+	writeProfileConfig(t)
+
+	_, err := LoadProfile("missing")
+	if !errors.Is(err, ErrUnknownProfile) {
+		t.Fatalf("LoadProfile() error = %v, want ErrUnknownProfile", err)
+	}
+	if !strings.Contains(err.Error(), "personal, work") {
+		t.Errorf("error should list the defined profiles: %v", err)
+	}
+
+	t.Setenv(ProfileEnvVar, "missing")
+	if _, err := LoadConfig(); !errors.Is(err, ErrUnknownProfile) {
+		t.Errorf("LoadConfig() with unknown %s: error = %v", ProfileEnvVar, err)
+	}
+}
+
+func TestSelectedProfile(t *testing.T) {
+	t.Cleanup(func() { SetProfile("") })
+
+	t.Setenv(ProfileEnvVar, "personal")
+	SetProfile("")
+	if got := SelectedProfile(); got != "personal" {
+		t.Errorf("SelectedProfile() = %q, want the environment variable", got)
+	}
+	SetProfile("work")
+	if got := SelectedProfile(); got != "work" {
+		t.Errorf("SelectedProfile() = %q, want the flag to win", got)
+	}
+}
+
+func TestSaveConfig_KeepsProfileOverrides(t *testing.T) {
+	writeProfileConfig(t)
+
+	cfg, err := LoadProfile("work")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	cfg.TUITheme = "dracula"
+	if err := SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	base, err := LoadProfile("")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if base.TUITheme == "dracula" || base.DefaultModel != "fast" {
+		t.Errorf("profile settings leaked into the top level: theme=%q model=%q", base.TUITheme, base.DefaultModel)
+	}
+	if got := base.Profiles["work"].TUITheme; got != "dracula" {
+		t.Errorf("profile theme = %q, want dracula", got)
+	}
+}
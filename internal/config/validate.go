@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// tuiThemes are the TUI theme names known to the render package, which
+// imports config and so cannot be asked directly
+var tuiThemes = []string{"tokyonight", "catppuccin", "nord", "dracula", "high-contrast"}
+
+// markdownStyles are the built-in markdown styles; any other style must be
+// the path to a JSON theme file
+var markdownStyles = []string{"dark", "light", "dracula", "notty", "ascii", "tokyonight", "catppuccin"}
+
+// chatShortcuts are the chat's fixed shortcuts and what they do, which the
+// send key must not take over. They are listed here for the same reason as
+// tuiThemes.
+var chatShortcuts = map[string]string{
+	"ctrl+c":       "interrupt",
+	"esc":          "quit",
+	"ctrl+g":       "gems",
+	"alt+g":        "previous gem",
+	"ctrl+l":       "clear",
+	"ctrl+up":      "previous message",
+	"ctrl+down":    "next message",
+	"ctrl+o":       "pager",
+	"ctrl+f":       "focus mode",
+	"ctrl+e":       "export",
+	"ctrl+shift+c": "copy all",
+	"alt+c":        "copy all",
+	"alt+C":        "copy all as plain text",
+	"alt+r":        "rerun tool",
+}
+
+// Warning describes a setting that is invalid and will be ignored or fail
+// later. Field is the JSON name of the setting.
+type Warning struct {
+	Field   string
+	Message string
+}
+
+// String formats the warning as "field: message"
+func (w Warning) String() string {
+	return w.Field + ": " + w.Message
+}
+
+// Validate checks cfg for settings that load fine but do not work: unknown
+// theme and model names, a send key that is unknown or conflicts with a
+// chat shortcut, a markdown style file that cannot be read and a download
+// directory that cannot be written. It never fails; a clean config returns
+// no warnings.
+func Validate(cfg Config) []Warning {
+	var warnings []Warning
+	warn := func(field, format string, args ...any) {
+		warnings = append(warnings, Warning{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if cfg.TUITheme != "" && !slices.Contains(tuiThemes, cfg.TUITheme) {
+		warn("tui_theme", "unknown theme %q (available: %s)", cfg.TUITheme, strings.Join(tuiThemes, ", "))
+	}
+
+	if cfg.DefaultModel != "" {
+		if _, ok := models.Lookup(cfg.DefaultModel); !ok {
+			warn("default_model", "unknown model %q (available: %s)", cfg.DefaultModel, strings.Join(AvailableModels(), ", "))
+		}
+	}
+
+	switch key := cfg.SendKey; {
+	case key == "" || key == SendKeyEnter || key == SendKeyCtrlEnter:
+	case chatShortcuts[key] != "":
+		warn("send_key", "%q conflicts with the %s shortcut (available: %s, %s)", key, chatShortcuts[key], SendKeyEnter, SendKeyCtrlEnter)
+	default:
+		warn("send_key", "unknown send key %q (available: %s, %s)", key, SendKeyEnter, SendKeyCtrlEnter)
+	}
+
+	if style := cfg.Markdown.Style; style != "" && !slices.Contains(markdownStyles, style) {
+		if info, err := os.Stat(style); err != nil || info.IsDir() {
+			warn("markdown.style", "%q is neither a built-in style (%s) nor a theme file", style, strings.Join(markdownStyles, ", "))
+		}
+	}
+
+	if cfg.DownloadDir != "" {
+		if err := checkWritableDir(cfg.DownloadDir); err != nil {
+			warn("download_dir", "%v", err)
+		}
+	}
+
+	return warnings
+}
+
+// checkWritableDir reports why files cannot be saved under dir. A missing
+// dir is fine as long as it can be created, i.e. its nearest existing
+// parent is a writable directory. It only checks permissions, so starting
+// up never writes to the directory.
+func checkWritableDir(dir string) error {
+	path := filepath.Clean(dir)
+	for {
+		info, err := os.Stat(path)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", path)
+			}
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return fmt.Errorf("cannot access %s: %w", dir, err)
+		}
+		path = parent
+	}
+
+	if !canWrite(path) {
+		return fmt.Errorf("%s is not writable", path)
+	}
+	return nil
+}
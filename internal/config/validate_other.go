@@ -0,0 +1,12 @@
+//go:build !unix
+
+package config
+
+import "os"
+
+// canWrite reports whether dir is writable by its owner, which is as much
+// as the file mode tells where access(2) is not available.
+func canWrite(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.Mode().Perm()&0o200 != 0
+}
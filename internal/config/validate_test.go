@@ -0,0 +1,124 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// validTestConfig returns the default config with a download directory
+// inside a temp dir
+func validTestConfig(t *testing.T) Config {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.DownloadDir = filepath.Join(t.TempDir(), "images")
+	return cfg
+}
+
+func TestValidate_CleanConfig(t *testing.T) {
+	if warnings := Validate(validTestConfig(t)); len(warnings) != 0 {
+		t.Errorf("Validate() = %v, want no warnings", warnings)
+	}
+}
+
+func TestValidate_Aliases(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.DefaultModel = "Flash"
+	cfg.TUITheme = ""
+	cfg.Markdown.Style = "tokyonight"
+	cfg.SendKey = SendKeyCtrlEnter
+
+	if warnings := Validate(cfg); len(warnings) != 0 {
+		t.Errorf("Validate() = %v, want no warnings", warnings)
+	}
+}
+
+func TestValidate_Misconfigurations(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	themeFile := filepath.Join(dir, "theme.json")
+	if err := os.WriteFile(themeFile, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		field  string
+		want   string
+	}{
+		{"unknown theme", func(c *Config) { c.TUITheme = "tokyo-night" }, "tui_theme", `unknown theme "tokyo-night"`},
+		{"unknown model", func(c *Config) { c.DefaultModel = "gpt-4" }, "default_model", `unknown model "gpt-4"`},
+		{"unknown send key", func(c *Config) { c.SendKey = "shift+enter" }, "send_key", `unknown send key "shift+enter"`},
+		{"send key conflicts with a shortcut", func(c *Config) { c.SendKey = "ctrl+e" }, "send_key", `"ctrl+e" conflicts with the export shortcut`},
+		{"missing style file", func(c *Config) { c.Markdown.Style = filepath.Join(dir, "missing.json") }, "markdown.style", "neither a built-in style"},
+		{"style is a directory", func(c *Config) { c.Markdown.Style = dir }, "markdown.style", "neither a built-in style"},
+		{"download dir is a file", func(c *Config) { c.DownloadDir = file }, "download_dir", "is not a directory"},
+		{"download dir under a file", func(c *Config) { c.DownloadDir = filepath.Join(file, "images") }, "download_dir", "is not a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig(t)
+			cfg.Markdown.Style = themeFile
+			tt.modify(&cfg)
+
+			warnings := Validate(cfg)
+			if len(warnings) != 1 {
+				t.Fatalf("Validate() = %v, want one warning", warnings)
+			}
+			if warnings[0].Field != tt.field || !strings.Contains(warnings[0].Message, tt.want) {
+				t.Errorf("warning = %q, want field %q with %q", warnings[0], tt.field, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_ReadOnlyDownloadDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	dir := filepath.Join(t.TempDir(), "readonly")
+	if err := os.Mkdir(dir, 0o500); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0o700) })
+
+	cfg := validTestConfig(t)
+	cfg.DownloadDir = filepath.Join(dir, "images")
+
+	warnings := Validate(cfg)
+	if len(warnings) != 1 || warnings[0].Field != "download_dir" || !strings.Contains(warnings[0].Message, "not writable") {
+		t.Errorf("Validate() = %v, want a download_dir writability warning", warnings)
+	}
+}
+
+func TestValidate_DoesNotWriteToDownloadDir(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.DownloadDir = t.TempDir()
+
+	if warnings := Validate(cfg); len(warnings) != 0 {
+		t.Fatalf("Validate() = %v, want no warnings", warnings)
+	}
+	entries, err := os.ReadDir(cfg.DownloadDir)
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Validate() left %v in the download dir (err %v)", entries, err)
+	}
+}
+
+func TestValidate_CollectsAllWarnings(t *testing.T) {
+	cfg := validTestConfig(t)
+	cfg.TUITheme = "nope"
+	cfg.DefaultModel = "nope"
+
+	warnings := Validate(cfg)
+	if len(warnings) != 2 {
+		t.Fatalf("Validate() = %v, want two warnings", warnings)
+	}
+	if got := warnings[0].String(); !strings.HasPrefix(got, "tui_theme: unknown theme") {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -757,6 +757,21 @@ func IsRateLimitError(err error) bool {
 	return errors.As(err, &usageErr)
 }
 
+// IsModelUnavailableError checks if the API rejected the request because the
+// selected model is unknown, deprecated or otherwise unavailable
+func IsModelUnavailableError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var modelErr *ModelError
+	if errors.As(err, &modelErr) {
+		return modelErr.Code == ErrCodeModelHeaderInvalid
+	}
+
+	return GetErrorCode(err) == ErrCodeModelHeaderInvalid
+}
+
 // GetHTTPStatus extracts the HTTP status code from an error, if available
 func GetHTTPStatus(err error) int {
 	if err == nil {
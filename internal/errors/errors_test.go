@@ -597,6 +597,30 @@ func TestIsRateLimitError(t *testing.T) {
 	}
 }
 
+func TestIsModelUnavailableError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"header invalid", NewModelErrorWithCode(ErrCodeModelHeaderInvalid), true},
+		{"from HandleErrorCode", HandleErrorCode(ErrCodeModelHeaderInvalid, "/generate", "gemini-old"), true},
+		{"wrapped", fmt.Errorf("send: %w", NewModelErrorWithCode(ErrCodeModelHeaderInvalid)), true},
+		{"model inconsistent", NewModelErrorWithCode(ErrCodeModelInconsistent), false},
+		{"usage limit", NewUsageLimitError("model"), false},
+		{"other error", errors.New("other"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsModelUnavailableError(tt.err); got != tt.expected {
+				t.Errorf("IsModelUnavailableError() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestGetHTTPStatus(t *testing.T) {
 	tests := []struct {
 		name     string
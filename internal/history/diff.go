@@ -0,0 +1,157 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogo/geminiweb/internal/textdiff"
+)
+
+// MessageChangeKind classifies a message in a conversation diff
+type MessageChangeKind string
+
+const (
+	MessageUnchanged MessageChangeKind = "unchanged"
+	MessageAdded     MessageChangeKind = "added"   // Only in the second conversation
+	MessageRemoved   MessageChangeKind = "removed" // Only in the first conversation
+	MessageChanged   MessageChangeKind = "changed" // Same position and role, different content
+)
+
+// MessageDiff describes one aligned message pair in a conversation diff.
+// IndexA and IndexB are -1 when the message is missing on that side.
+type MessageDiff struct {
+	Kind   MessageChangeKind
+	Role   string
+	IndexA int
+	IndexB int
+}
+
+// ConversationDiff is the result of comparing two conversations
+type ConversationDiff struct {
+	A        *Conversation
+	B        *Conversation
+	Messages []MessageDiff
+	Unified  string // Unified diff of the Markdown renderings
+}
+
+// HasChanges reports whether the conversations' messages differ
+func (d *ConversationDiff) HasChanges() bool {
+	for _, m := range d.Messages {
+		if m.Kind != MessageUnchanged {
+			return true
+		}
+	}
+	return false
+}
+
+// diffContextLines is the number of unchanged lines shown around changes
+const diffContextLines = 3
+
+// diffTooLarge replaces the unified diff of conversations with more
+// differing lines than textdiff.MaxLines
+const diffTooLarge = "Conversations too large to diff line by line."
+
+// DiffConversations compares two conversations message by message and
+// produces a unified diff of their Markdown renderings. Timestamps are left
+// out of the renderings so only content differences show up.
+func DiffConversations(a, b *Conversation) *ConversationDiff {
+	unified, err := textdiff.Unified(
+		fmt.Sprintf("a/%s (%s)", a.Title, a.ID),
+		fmt.Sprintf("b/%s (%s)", b.Title, b.ID),
+		strings.Split(markdownForDiff(a), "\n"),
+		strings.Split(markdownForDiff(b), "\n"),
+		diffContextLines,
+	)
+	if err != nil {
+		unified = diffTooLarge
+	}
+	return &ConversationDiff{
+		A:        a,
+		B:        b,
+		Messages: diffMessages(a.Messages, b.Messages),
+		Unified:  unified,
+	}
+}
+
+// markdownForDiff renders a conversation's messages as Markdown without
+// timestamps or thoughts
+func markdownForDiff(conv *Conversation) string {
+	var sb strings.Builder
+	for i, msg := range conv.Messages {
+		if i > 0 {
+			sb.WriteString("\n---\n\n")
+		}
+		sb.WriteString("## ")
+		sb.WriteString(roleTitle(msg.Role))
+		sb.WriteString("\n\n")
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// roleTitle returns the Markdown heading used for a message role
+func roleTitle(role string) string {
+	switch role {
+	case "assistant":
+		return "Assistant"
+	case "tool":
+		return "Tool"
+	}
+	return "User"
+}
+
+// diffMessages aligns two message lists. Runs of removed messages followed
+// by added ones with the same role are reported as changed.
+func diffMessages(a, b []Message) []MessageDiff {
+	keyOf := func(m Message) string { return m.Role + "\x00" + m.Content }
+	keysA := make([]string, len(a))
+	for i, m := range a {
+		keysA[i] = keyOf(m)
+	}
+	keysB := make([]string, len(b))
+	for i, m := range b {
+		keysB[i] = keyOf(m)
+	}
+
+	var result []MessageDiff
+	var removed, added []int
+	flush := func() {
+		n := 0
+		for n < len(removed) && n < len(added) && a[removed[n]].Role == b[added[n]].Role {
+			result = append(result, MessageDiff{Kind: MessageChanged, Role: a[removed[n]].Role, IndexA: removed[n], IndexB: added[n]})
+			n++
+		}
+		for _, i := range removed[n:] {
+			result = append(result, MessageDiff{Kind: MessageRemoved, Role: a[i].Role, IndexA: i, IndexB: -1})
+		}
+		for _, j := range added[n:] {
+			result = append(result, MessageDiff{Kind: MessageAdded, Role: b[j].Role, IndexA: -1, IndexB: j})
+		}
+		removed, added = nil, nil
+	}
+
+	ops, err := textdiff.Lines(keysA, keysB)
+	if err != nil {
+		// Too many messages to align: compare them by position
+		for i := range a {
+			removed = append(removed, i)
+		}
+		for j := range b {
+			added = append(added, j)
+		}
+	}
+	for _, op := range ops {
+		switch op.Kind {
+		case textdiff.Equal:
+			flush()
+			result = append(result, MessageDiff{Kind: MessageUnchanged, Role: a[op.A].Role, IndexA: op.A, IndexB: op.B})
+		case textdiff.Delete:
+			removed = append(removed, op.A)
+		case textdiff.Insert:
+			added = append(added, op.B)
+		}
+	}
+	flush()
+	return result
+}
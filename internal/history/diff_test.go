@@ -0,0 +1,118 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func newDiffConversation(id, title string, messages ...Message) *Conversation {
+	return &Conversation{ID: id, Title: title, Messages: messages}
+}
+
+func TestDiffConversations_SharedPrefix(t *testing.T) {
+	a := newDiffConversation("conv-a", "Run A",
+		Message{Role: "user", Content: "Write a haiku about Go"},
+		Message{Role: "assistant", Content: "Gophers dig deep\nchannels hum softly"},
+		Message{Role: "user", Content: "Make it rhyme"},
+		Message{Role: "assistant", Content: "Old answer"},
+	)
+	b := newDiffConversation("conv-b", "Run B",
+		Message{Role: "user", Content: "Write a haiku about Go"},
+		Message{Role: "assistant", Content: "Gophers dig deep\nchannels hum softly"},
+		Message{Role: "user", Content: "Make it rhyme"},
+		Message{Role: "assistant", Content: "New answer"},
+		Message{Role: "user", Content: "Thanks"},
+	)
+
+	diff := DiffConversations(a, b)
+	if !diff.HasChanges() {
+		t.Fatal("HasChanges() = false, want true")
+	}
+
+	want := []MessageDiff{
+		{Kind: MessageUnchanged, Role: "user", IndexA: 0, IndexB: 0},
+		{Kind: MessageUnchanged, Role: "assistant", IndexA: 1, IndexB: 1},
+		{Kind: MessageUnchanged, Role: "user", IndexA: 2, IndexB: 2},
+		{Kind: MessageChanged, Role: "assistant", IndexA: 3, IndexB: 3},
+		{Kind: MessageAdded, Role: "user", IndexA: -1, IndexB: 4},
+	}
+	if len(diff.Messages) != len(want) {
+		t.Fatalf("got %d message diffs, want %d: %+v", len(diff.Messages), len(want), diff.Messages)
+	}
+	for i := range want {
+		if diff.Messages[i] != want[i] {
+			t.Errorf("Messages[%d] = %+v, want %+v", i, diff.Messages[i], want[i])
+		}
+	}
+
+	for _, line := range []string{
+		"--- a/Run A (conv-a)",
+		"+++ b/Run B (conv-b)",
+		"-Old answer",
+		"+New answer",
+		"+Thanks",
+	} {
+		if !strings.Contains(diff.Unified, line+"\n") {
+			t.Errorf("unified diff missing line %q:\n%s", line, diff.Unified)
+		}
+	}
+	if strings.Contains(diff.Unified, "Write a haiku") {
+		t.Error("unchanged lines far from the change should not be in the diff")
+	}
+}
+
+func TestDiffConversations_RemovedMessage(t *testing.T) {
+	a := newDiffConversation("a", "A",
+		Message{Role: "user", Content: "one"},
+		Message{Role: "tool", Content: "tool output"},
+		Message{Role: "assistant", Content: "two"},
+	)
+	b := newDiffConversation("b", "B",
+		Message{Role: "user", Content: "one"},
+		Message{Role: "assistant", Content: "two"},
+	)
+
+	diff := DiffConversations(a, b)
+	var kinds []MessageChangeKind
+	for _, m := range diff.Messages {
+		kinds = append(kinds, m.Kind)
+	}
+	want := []MessageChangeKind{MessageUnchanged, MessageRemoved, MessageUnchanged}
+	if len(kinds) != len(want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("kinds = %v, want %v", kinds, want)
+			break
+		}
+	}
+}
+
+func TestDiffConversations_Identical(t *testing.T) {
+	msgs := []Message{{Role: "user", Content: "hi"}, {Role: "assistant", Content: "hello"}}
+	diff := DiffConversations(newDiffConversation("a", "A", msgs...), newDiffConversation("b", "B", msgs...))
+
+	if diff.HasChanges() {
+		t.Error("HasChanges() = true, want false")
+	}
+	if diff.Unified != "" {
+		t.Errorf("Unified = %q, want empty", diff.Unified)
+	}
+}
+
+func TestDiffConversations_TooLarge(t *testing.T) {
+	var a, b []Message
+	for i := 0; i < 10001; i++ {
+		a = append(a, Message{Role: "user", Content: fmt.Sprint("a", i)})
+		b = append(b, Message{Role: "user", Content: fmt.Sprint("b", i)})
+	}
+	diff := DiffConversations(newDiffConversation("conv-a", "A", a...), newDiffConversation("conv-b", "B", b...))
+	if diff.Unified != diffTooLarge {
+		t.Errorf("Unified = %.80q, want the too large notice", diff.Unified)
+	}
+	if len(diff.Messages) == 0 {
+		t.Error("expected per-message differences")
+	}
+}
@@ -33,6 +33,13 @@ type Conversation struct {
 	RID  string `json:"rid,omitempty"`
 	RCID string `json:"rcid,omitempty"`
 
+	// Gem applied to the conversation, restored when it is resumed
+	GemID   string `json:"gem_id,omitempty"`
+	GemName string `json:"gem_name,omitempty"`
+
+	// Archived conversations are kept but hidden from the default history list
+	Archived bool `json:"archived,omitempty"`
+
 	// Computed fields (populated from HistoryMeta, not saved in conversation JSON)
 	IsFavorite bool `json:"-"` // Populated by ListConversations
 	OrderIndex int  `json:"-"` // Position in list (0-based, populated by ListConversations)
@@ -102,6 +109,28 @@ func (s *Store) GetConversation(id string) (*Conversation, error) {
 	return s.loadConversation(id)
 }
 
+// GetMessagesBefore returns the last limit messages of a conversation
+// stored before the given time, oldest first (limit <= 0 returns all of
+// them)
+func (s *Store) GetMessagesBefore(id string, before time.Time, limit int) ([]Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	conv, err := s.loadConversation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	end := sort.Search(len(conv.Messages), func(i int) bool {
+		return !conv.Messages[i].Timestamp.Before(before)
+	})
+	start := 0
+	if limit > 0 && end-limit > start {
+		start = end - limit
+	}
+	return conv.Messages[start:end], nil
+}
+
 // ListConversations returns all conversations ordered by meta.json
 // If no meta.json exists, falls back to sorting by UpdatedAt descending
 // Populates computed fields IsFavorite and OrderIndex
@@ -260,6 +289,76 @@ func (s *Store) UpdateMetadata(id, cid, rid, rcid string) error {
 	return s.saveConversation(conv)
 }
 
+// ReplaceLastMessage replaces the last message of a conversation, which
+// must have the given role, keeping its place in the conversation
+func (s *Store) ReplaceLastMessage(id, role, content, thoughts string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, err := s.loadConversation(id)
+	if err != nil {
+		return err
+	}
+
+	last := len(conv.Messages) - 1
+	if last < 0 || conv.Messages[last].Role != role {
+		return fmt.Errorf("last message of conversation %s is not a %s message", id, role)
+	}
+	conv.Messages[last] = Message{
+		Role:      role,
+		Content:   content,
+		Thoughts:  thoughts,
+		Timestamp: time.Now(),
+	}
+	conv.UpdatedAt = time.Now()
+
+	return s.saveConversation(conv)
+}
+
+// UpdateGem records the gem used by a conversation.
+// Pass empty values to clear it.
+func (s *Store) UpdateGem(id, gemID, gemName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, err := s.loadConversation(id)
+	if err != nil {
+		return err
+	}
+
+	conv.GemID = gemID
+	conv.GemName = gemName
+	conv.UpdatedAt = time.Now()
+
+	return s.saveConversation(conv)
+}
+
+// Archive hides a conversation from the default history list without
+// deleting it
+func (s *Store) Archive(id string) error {
+	return s.setArchived(id, true)
+}
+
+// Unarchive returns an archived conversation to the default history list
+func (s *Store) Unarchive(id string) error {
+	return s.setArchived(id, false)
+}
+
+// setArchived updates the archived flag. UpdatedAt is left alone so
+// archiving does not reorder the history.
+func (s *Store) setArchived(id string, archived bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conv, err := s.loadConversation(id)
+	if err != nil {
+		return err
+	}
+
+	conv.Archived = archived
+	return s.saveConversation(conv)
+}
+
 // DeleteConversation removes a conversation
 func (s *Store) DeleteConversation(id string) error {
 	s.mu.Lock()
@@ -155,6 +155,75 @@ func TestStore_AddMessage_WithThoughts(t *testing.T) {
 	}
 }
 
+func TestStore_GetMessagesBefore(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewStore(tmpDir)
+
+	conv, _ := store.CreateConversation("test-model")
+	for _, content := range []string{"m0", "m1", "m2", "m3", "m4"} {
+		if err := store.AddMessage(conv.ID, "user", content, ""); err != nil {
+			t.Fatalf("AddMessage failed: %v", err)
+		}
+	}
+	stored, _ := store.GetConversation(conv.ID)
+	at := func(i int) time.Time { return stored.Messages[i].Timestamp }
+
+	tests := []struct {
+		name   string
+		before time.Time
+		limit  int
+		want   []string
+	}{
+		{"page", at(3), 2, []string{"m1", "m2"}},
+		{"limit past start", at(2), 10, []string{"m0", "m1"}},
+		{"no limit", at(4), 0, []string{"m0", "m1", "m2", "m3"}},
+		{"after the last message", at(4).Add(time.Second), 1, []string{"m4"}},
+		{"before the first message", at(0), 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs, err := store.GetMessagesBefore(conv.ID, tt.before, tt.limit)
+			if err != nil {
+				t.Fatalf("GetMessagesBefore failed: %v", err)
+			}
+			if len(msgs) != len(tt.want) {
+				t.Fatalf("got %d messages, want %d", len(msgs), len(tt.want))
+			}
+			for i, msg := range msgs {
+				if msg.Content != tt.want[i] {
+					t.Errorf("message %d = %s, want %s", i, msg.Content, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := store.GetMessagesBefore("missing", time.Now(), 1); err == nil {
+		t.Error("expected error for unknown conversation")
+	}
+}
+
+func TestStore_ReplaceLastMessage(t *testing.T) {
+	store, _ := NewStore(t.TempDir())
+	conv, _ := store.CreateConversation("test-model")
+	_ = store.AddMessage(conv.ID, "user", "question", "")
+	_ = store.AddMessage(conv.ID, "assistant", "first answer", "")
+
+	if err := store.ReplaceLastMessage(conv.ID, "assistant", "second answer", "thinking"); err != nil {
+		t.Fatalf("ReplaceLastMessage failed: %v", err)
+	}
+	updated, _ := store.GetConversation(conv.ID)
+	if len(updated.Messages) != 2 {
+		t.Fatalf("got %d messages, want 2", len(updated.Messages))
+	}
+	if got := updated.Messages[1]; got.Content != "second answer" || got.Thoughts != "thinking" {
+		t.Errorf("last message = %+v, want the replacement", got)
+	}
+
+	if err := store.ReplaceLastMessage(conv.ID, "user", "other", ""); err == nil {
+		t.Error("expected error when the last message has another role")
+	}
+}
+
 func TestStore_UpdateMetadata(t *testing.T) {
 	tmpDir := t.TempDir()
 	store, _ := NewStore(tmpDir)
@@ -178,6 +247,74 @@ func TestStore_UpdateMetadata(t *testing.T) {
 	}
 }
 
+func TestStore_UpdateGem(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewStore(tmpDir)
+
+	conv, _ := store.CreateConversation("test-model")
+
+	if err := store.UpdateGem(conv.ID, "gem-123", "Coder"); err != nil {
+		t.Fatalf("UpdateGem failed: %v", err)
+	}
+
+	updated, _ := store.GetConversation(conv.ID)
+	if updated.GemID != "gem-123" {
+		t.Errorf("GemID = %s, want gem-123", updated.GemID)
+	}
+	if updated.GemName != "Coder" {
+		t.Errorf("GemName = %s, want Coder", updated.GemName)
+	}
+
+	if err := store.UpdateGem(conv.ID, "", ""); err != nil {
+		t.Fatalf("UpdateGem (clear) failed: %v", err)
+	}
+	updated, _ = store.GetConversation(conv.ID)
+	if updated.GemID != "" || updated.GemName != "" {
+		t.Errorf("gem should be cleared, got %q/%q", updated.GemID, updated.GemName)
+	}
+
+	if err := store.UpdateGem("nonexistent", "gem", "Gem"); err == nil {
+		t.Error("expected error for nonexistent conversation")
+	}
+}
+
+func TestStore_ArchiveRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewStore(tmpDir)
+
+	conv, _ := store.CreateConversation("test-model")
+	before, _ := store.GetConversation(conv.ID)
+
+	if err := store.Archive(conv.ID); err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	archived, _ := store.GetConversation(conv.ID)
+	if !archived.Archived {
+		t.Error("conversation should be archived")
+	}
+	if !archived.UpdatedAt.Equal(before.UpdatedAt) {
+		t.Error("archiving should not change UpdatedAt")
+	}
+
+	// Archived conversations are still listed; callers decide to hide them
+	list, _ := store.ListConversations()
+	if len(list) != 1 || !list[0].Archived {
+		t.Errorf("ListConversations should include the archived flag, got %+v", list)
+	}
+
+	if err := store.Unarchive(conv.ID); err != nil {
+		t.Fatalf("Unarchive failed: %v", err)
+	}
+	restored, _ := store.GetConversation(conv.ID)
+	if restored.Archived {
+		t.Error("conversation should no longer be archived")
+	}
+
+	if err := store.Archive("nonexistent"); err == nil {
+		t.Error("expected error for nonexistent conversation")
+	}
+}
+
 func TestStore_DeleteConversation(t *testing.T) {
 	tmpDir := t.TempDir()
 	store, _ := NewStore(tmpDir)
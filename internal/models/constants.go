@@ -1,7 +1,11 @@
 // Package models contains data types and constants for the Gemini Web API.
 package models
 
-import apierrors "github.com/diogo/geminiweb/internal/errors"
+import (
+	"strings"
+
+	apierrors "github.com/diogo/geminiweb/internal/errors"
+)
 
 // Endpoints for Gemini Web API
 const (
@@ -32,6 +36,10 @@ const (
 type Model struct {
 	Name   string
 	Header map[string]string
+
+	// SupportsThoughts reports whether requests to the model can ask for
+	// or suppress its thinking output
+	SupportsThoughts bool
 }
 
 // Available models
@@ -64,6 +72,7 @@ var (
 		Header: map[string]string{
 			"x-goog-ext-525001261-jspb": `[1,null,null,null,"e051ce1aa80aa576",null,null,0,[4],null,null,2]`,
 		},
+		SupportsThoughts: true,
 	}
 
 	// DefaultModel is the recommended default
@@ -86,18 +95,38 @@ func AllModels() []Model {
 	return []Model{ModelFast, ModelPro, ModelThinking}
 }
 
-// ModelFromName returns a Model by its name
+// modelAliases maps lowercase canonical names and aliases to models
+var modelAliases = map[string]Model{
+	"unspecified": ModelUnspecified,
+
+	"fast":             ModelFast,
+	"flash":            ModelFast,
+	"2.5-flash":        ModelFast,
+	"gemini-2.5-flash": ModelFast, // Legacy name
+
+	"pro":            ModelPro,
+	"3.0-pro":        ModelPro,
+	"gemini-3.0-pro": ModelPro, // Legacy name
+
+	"thinking": ModelThinking,
+	"think":    ModelThinking,
+}
+
+// Lookup returns the model with the given canonical name or alias
+// (e.g. "flash", "2.5-flash"). Names are case-insensitive; surrounding
+// whitespace is ignored. It reports false for unknown names.
+func Lookup(name string) (Model, bool) {
+	model, ok := modelAliases[strings.ToLower(strings.TrimSpace(name))]
+	return model, ok
+}
+
+// ModelFromName returns a Model by its name or alias, or ModelUnspecified
+// for unknown names
 func ModelFromName(name string) Model {
-	switch name {
-	case "fast", "gemini-2.5-flash": // gemini-2.5-flash is legacy alias
-		return ModelFast
-	case "pro", "gemini-3.0-pro": // gemini-3.0-pro is legacy alias
-		return ModelPro
-	case "thinking":
-		return ModelThinking
-	default:
-		return ModelUnspecified
+	if model, ok := Lookup(name); ok {
+		return model
 	}
+	return ModelUnspecified
 }
 
 // ErrorCode represents known API error codes
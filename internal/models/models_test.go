@@ -438,3 +438,41 @@ func TestUploadHeaders(t *testing.T) {
 		t.Error("Missing required header: Push-ID")
 	}
 }
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   Model
+		wantOK bool
+	}{
+		// Canonical names
+		{"fast", ModelFast, true},
+		{"pro", ModelPro, true},
+		{"thinking", ModelThinking, true},
+		{"unspecified", ModelUnspecified, true},
+		// Aliases
+		{"flash", ModelFast, true},
+		{"2.5-flash", ModelFast, true},
+		{"gemini-2.5-flash", ModelFast, true},
+		{"3.0-pro", ModelPro, true},
+		{"gemini-3.0-pro", ModelPro, true},
+		{"think", ModelThinking, true},
+		// Case and whitespace are ignored
+		{"FLASH", ModelFast, true},
+		{"  Pro ", ModelPro, true},
+		{"Gemini-2.5-Flash", ModelFast, true},
+		// Unknown names
+		{"gemini-2.5-pro", Model{}, false},
+		{"ultra", Model{}, false},
+		{"", Model{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Lookup(tt.name)
+			if ok != tt.wantOK || got.Name != tt.want.Name {
+				t.Errorf("Lookup(%q) = (%q, %v), want (%q, %v)", tt.name, got.Name, ok, tt.want.Name, tt.wantOK)
+			}
+		})
+	}
+}
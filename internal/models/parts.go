@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PartType identifies the kind of content in a response Part
+type PartType string
+
+const (
+	PartText       PartType = "text"        // Plain response text
+	PartInlineData PartType = "inline_data" // Data embedded in the response (e.g., a base64 image)
+	PartImage      PartType = "image"       // Reference to a generated image
+)
+
+// Part is one piece of a response, in the order it appears.
+// Text is set for text parts; Data and MIME for inline data parts; URL and
+// Text (the image title) for image parts.
+type Part struct {
+	Type PartType
+	Text string
+	Data []byte
+	MIME string
+	URL  string
+}
+
+// inlinePartPattern matches content interleaved with response text:
+// base64 data URIs, optionally wrapped in a markdown image, and generated
+// image placeholders that refer to the candidate's generated images by index.
+var inlinePartPattern = regexp.MustCompile(
+	`!\[[^\]]*\]\((data:[\w.+-]+/[\w.+-]+;base64,[A-Za-z0-9+/=]+)\)` +
+		`|data:[\w.+-]+/[\w.+-]+;base64,[A-Za-z0-9+/=]+` +
+		`|http://googleusercontent\.com/image_generation_content/(\d+)`,
+)
+
+// ParseParts splits response text into its text, inline data and image
+// parts. Data URIs that fail to decode are kept as text, and placeholders
+// without a matching generated image are dropped.
+func ParseParts(text string, generated []GeneratedImage) []Part {
+	var parts []Part
+	addText := func(s string) {
+		if strings.TrimSpace(s) == "" {
+			return
+		}
+		// Merge with a preceding text part (e.g., after an undecodable URI)
+		if n := len(parts); n > 0 && parts[n-1].Type == PartText {
+			parts[n-1].Text += s
+			return
+		}
+		parts = append(parts, Part{Type: PartText, Text: s})
+	}
+
+	last := 0
+	for _, loc := range inlinePartPattern.FindAllStringSubmatchIndex(text, -1) {
+		addText(text[last:loc[0]])
+		last = loc[1]
+		match := text[loc[0]:loc[1]]
+
+		switch {
+		case loc[4] >= 0: // Generated image placeholder
+			idx, _ := strconv.Atoi(text[loc[4]:loc[5]])
+			if idx < len(generated) {
+				parts = append(parts, Part{Type: PartImage, Text: generated[idx].Title, URL: generated[idx].URL})
+			}
+		default: // Data URI, bare or in a markdown image
+			uri := match
+			if loc[2] >= 0 {
+				uri = text[loc[2]:loc[3]]
+			}
+			part, ok := parseDataURI(uri)
+			if !ok {
+				addText(match)
+				continue
+			}
+			parts = append(parts, part)
+		}
+	}
+	addText(text[last:])
+	return parts
+}
+
+// parseDataURI decodes a "data:<mime>;base64,<data>" URI
+func parseDataURI(uri string) (Part, bool) {
+	header, encoded, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return Part{}, false
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Part{}, false
+	}
+	return Part{Type: PartInlineData, Data: data, MIME: strings.TrimSuffix(header, ";base64")}, true
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseParts(t *testing.T) {
+	generated := []GeneratedImage{
+		{URL: "https://example.com/gen0.png", Title: "[Generated Image 1]"},
+	}
+
+	t.Run("interleaved parts", func(t *testing.T) {
+		text := "Here is the chart:\n![chart](data:image/png;base64,iVBORw0KGgo=)\n" +
+			"and the raw bytes data:application/octet-stream;base64,AQID then an image:\n" +
+			"http://googleusercontent.com/image_generation_content/0\nDone."
+
+		parts := ParseParts(text, generated)
+
+		want := []Part{
+			{Type: PartText, Text: "Here is the chart:\n"},
+			{Type: PartInlineData, MIME: "image/png", Data: []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}},
+			{Type: PartText, Text: "\nand the raw bytes "},
+			{Type: PartInlineData, MIME: "application/octet-stream", Data: []byte{1, 2, 3}},
+			{Type: PartText, Text: " then an image:\n"},
+			{Type: PartImage, Text: "[Generated Image 1]", URL: "https://example.com/gen0.png"},
+			{Type: PartText, Text: "\nDone."},
+		}
+		if len(parts) != len(want) {
+			t.Fatalf("got %d parts, want %d: %+v", len(parts), len(want), parts)
+		}
+		for i := range want {
+			got := parts[i]
+			if got.Type != want[i].Type || got.Text != want[i].Text || got.MIME != want[i].MIME ||
+				got.URL != want[i].URL || !bytes.Equal(got.Data, want[i].Data) {
+				t.Errorf("parts[%d] = %+v, want %+v", i, got, want[i])
+			}
+		}
+	})
+
+	t.Run("plain text is a single part", func(t *testing.T) {
+		parts := ParseParts("just text", nil)
+		if len(parts) != 1 || parts[0].Type != PartText || parts[0].Text != "just text" {
+			t.Errorf("parts = %+v", parts)
+		}
+	})
+
+	t.Run("invalid data uri stays text", func(t *testing.T) {
+		parts := ParseParts("bad data:image/png;base64,abcde here", nil)
+		if len(parts) != 1 || parts[0].Text != "bad data:image/png;base64,abcde here" {
+			t.Errorf("parts = %+v", parts)
+		}
+	})
+
+	t.Run("placeholder without generated image is dropped", func(t *testing.T) {
+		parts := ParseParts("see http://googleusercontent.com/image_generation_content/3", generated)
+		if len(parts) != 1 || parts[0].Type != PartText || parts[0].Text != "see " {
+			t.Errorf("parts = %+v", parts)
+		}
+	})
+}
+
+func TestModelOutput_Parts(t *testing.T) {
+	if parts := (&ModelOutput{}).Parts(); parts != nil {
+		t.Errorf("Parts() on empty output = %+v, want nil", parts)
+	}
+
+	output := &ModelOutput{Candidates: []Candidate{{RCID: "r", Text: "hello"}}}
+	parts := output.Parts()
+	if len(parts) != 1 || parts[0].Type != PartText || parts[0].Text != "hello" {
+		t.Errorf("Parts() without parsed parts = %+v", parts)
+	}
+
+	output.Candidates[0].Parts = []Part{{Type: PartInlineData, MIME: "image/png"}}
+	if parts := output.Parts(); len(parts) != 1 || parts[0].Type != PartInlineData {
+		t.Errorf("Parts() = %+v, want parsed parts", parts)
+	}
+}
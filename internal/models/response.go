@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // Candidate represents a single response candidate from Gemini
 type Candidate struct {
 	RCID            string
@@ -7,6 +9,8 @@ type Candidate struct {
 	Thoughts        string // Only populated for thinking models
 	WebImages       []WebImage
 	GeneratedImages []GeneratedImage
+	Sources         []Source // Grounding/citation sources, if any
+	Parts           []Part   // Text split into text, inline data and image parts
 }
 
 // WebImage represents an image from web search results
@@ -16,6 +20,13 @@ type WebImage struct {
 	Alt   string
 }
 
+// Source represents a grounding/citation source cited by a response
+type Source struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
 // GeneratedImage represents an AI-generated image
 type GeneratedImage struct {
 	URL   string
@@ -29,6 +40,10 @@ type ModelOutput struct {
 	Candidates          []Candidate
 	Chosen              int  // Index of selected candidate
 	IsExtensionResponse bool // True if response came from an extension (@Gmail, @YouTube, etc.)
+
+	// RawResponse is the raw API response body (bounded), populated only
+	// when the client is configured to retain it
+	RawResponse string
 }
 
 // Text returns the chosen candidate's text
@@ -53,6 +68,22 @@ func (m *ModelOutput) Thoughts() string {
 	return m.Candidates[m.Chosen].Thoughts
 }
 
+// IsEmpty reports whether the output has no usable content: the chosen
+// candidate has no text, thoughts or images (or there is no candidate)
+func (m *ModelOutput) IsEmpty() bool {
+	if m == nil {
+		return true
+	}
+	candidate := m.ChosenCandidate()
+	if candidate == nil {
+		return true
+	}
+	return strings.TrimSpace(candidate.Text) == "" &&
+		strings.TrimSpace(candidate.Thoughts) == "" &&
+		len(candidate.WebImages) == 0 &&
+		len(candidate.GeneratedImages) == 0
+}
+
 // RCID returns the chosen candidate's RCID
 func (m *ModelOutput) RCID() string {
 	if len(m.Candidates) == 0 {
@@ -93,6 +124,31 @@ func (m *ModelOutput) Images() []WebImage {
 	return images
 }
 
+// Sources returns the chosen candidate's grounding/citation sources
+func (m *ModelOutput) Sources() []Source {
+	if candidate := m.ChosenCandidate(); candidate != nil {
+		return candidate.Sources
+	}
+	return nil
+}
+
+// Parts returns the chosen candidate's content in order, with inline data
+// and generated images separated from the text. Candidates without parsed
+// parts yield their text as a single part.
+func (m *ModelOutput) Parts() []Part {
+	candidate := m.ChosenCandidate()
+	if candidate == nil {
+		return nil
+	}
+	if candidate.Parts != nil {
+		return candidate.Parts
+	}
+	if candidate.Text == "" {
+		return nil
+	}
+	return []Part{{Type: PartText, Text: candidate.Text}}
+}
+
 // CID returns the conversation ID from metadata
 func (m *ModelOutput) CID() string {
 	if len(m.Metadata) > 0 {
@@ -261,6 +261,26 @@ func TestModelOutput_Images_EmptyCandidates(t *testing.T) {
 	}
 }
 
+func TestModelOutput_Sources(t *testing.T) {
+	output := &ModelOutput{
+		Candidates: []Candidate{
+			{Sources: []Source{{URL: "https://example.com/a"}}},
+			{Sources: []Source{{URL: "https://example.com/b"}, {URL: "https://example.com/c"}}},
+		},
+		Chosen: 1,
+	}
+
+	sources := output.Sources()
+	if len(sources) != 2 || sources[0].URL != "https://example.com/b" {
+		t.Errorf("Sources() = %+v, want chosen candidate's sources", sources)
+	}
+
+	empty := &ModelOutput{}
+	if sources := empty.Sources(); sources != nil {
+		t.Errorf("Sources() should return nil for empty candidates, got %v", sources)
+	}
+}
+
 // CID and RID tests are in models_test.go
 
 // ============================================================================
@@ -355,3 +375,32 @@ func TestGeneratedImageToWebImage(t *testing.T) {
 		t.Errorf("Converted WebImage.Alt = %s, want %s", web.Alt, gen.Alt)
 	}
 }
+
+// ============================================================================
+// IsEmpty Tests
+// ============================================================================
+
+func TestModelOutput_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *ModelOutput
+		want   bool
+	}{
+		{"nil output", nil, true},
+		{"no candidates", &ModelOutput{}, true},
+		{"blank text", &ModelOutput{Candidates: []Candidate{{RCID: "rc1", Text: "  \n"}}}, true},
+		{"text", &ModelOutput{Candidates: []Candidate{{Text: "hi"}}}, false},
+		{"thoughts only", &ModelOutput{Candidates: []Candidate{{Thoughts: "hmm"}}}, false},
+		{"web image only", &ModelOutput{Candidates: []Candidate{{WebImages: []WebImage{{URL: "u"}}}}}, false},
+		{"generated image only", &ModelOutput{Candidates: []Candidate{{GeneratedImages: []GeneratedImage{{URL: "u"}}}}}, false},
+		{"chosen candidate is empty", &ModelOutput{Candidates: []Candidate{{Text: "a"}, {}}, Chosen: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.output.IsEmpty(); got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Diff colorizes a unified diff for terminal display using the active TUI
+// theme: added lines in the secondary color, removed lines in the error
+// color, hunk headers in the accent color and file headers in bold text.
+// Lines are wrapped to width; a width of zero or less leaves them as is.
+// Colors are dropped when standard output is not a terminal.
+func Diff(unified string, width int) string {
+	return renderDiff(lipgloss.DefaultRenderer(), unified, width)
+}
+
+// IsUnifiedDiff reports whether s looks like a unified diff: it holds a
+// hunk header, or a pair of file headers.
+func IsUnifiedDiff(s string) bool {
+	var from, to bool
+	for _, line := range strings.Split(s, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@ "):
+			return true
+		case strings.HasPrefix(line, "--- "):
+			from = true
+		case strings.HasPrefix(line, "+++ "):
+			to = from
+		}
+	}
+	return from && to
+}
+
+// renderDiff colorizes a unified diff with the given renderer, whose color
+// profile decides whether any styling is emitted.
+func renderDiff(r *lipgloss.Renderer, unified string, width int) string {
+	theme := GetTUITheme()
+	line := r.NewStyle()
+	if width > 0 {
+		line = line.Width(width)
+	}
+	header := line.Foreground(theme.Text).Bold(true)
+	hunk := line.Foreground(theme.Accent)
+	added := line.Foreground(theme.Secondary)
+	removed := line.Foreground(theme.Error)
+
+	lines := strings.Split(strings.TrimSuffix(unified, "\n"), "\n")
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+			out[i] = header.Render(l)
+		case strings.HasPrefix(l, "@@"):
+			out[i] = hunk.Render(l)
+		case strings.HasPrefix(l, "+"):
+			out[i] = added.Render(l)
+		case strings.HasPrefix(l, "-"):
+			out[i] = removed.Render(l)
+		default:
+			out[i] = line.Render(l)
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,120 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const testUnifiedDiff = `--- a/notes.txt
++++ b/notes.txt
+@@ -1,2 +1,2 @@
+ unchanged
+-old line
++new line`
+
+// colorRenderer returns a renderer that emits true color styling
+func colorRenderer() *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(&bytes.Buffer{})
+	r.SetColorProfile(termenv.TrueColor)
+	return r
+}
+
+// styled renders text with a foreground color the way renderDiff does
+func styled(r *lipgloss.Renderer, color lipgloss.Color, text string) string {
+	return r.NewStyle().Foreground(color).Render(text)
+}
+
+func TestRenderDiff_Colors(t *testing.T) {
+	r := colorRenderer()
+	theme := GetTUITheme()
+	lines := strings.Split(renderDiff(r, testUnifiedDiff, 0), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want 6:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"file header", lines[0], r.NewStyle().Foreground(theme.Text).Bold(true).Render("--- a/notes.txt")},
+		{"hunk header", lines[2], styled(r, theme.Accent, "@@ -1,2 +1,2 @@")},
+		{"context", lines[3], " unchanged"},
+		{"removed", lines[4], styled(r, theme.Error, "-old line")},
+		{"added", lines[5], styled(r, theme.Secondary, "+new line")},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+	for _, i := range []int{0, 2, 4, 5} {
+		if !strings.Contains(lines[i], "\x1b[") {
+			t.Errorf("line %d should be styled: %q", i, lines[i])
+		}
+	}
+}
+
+func TestRenderDiff_FollowsTheme(t *testing.T) {
+	original := GetTUITheme().Name
+	t.Cleanup(func() { SetTUITheme(original) })
+
+	r := colorRenderer()
+	SetTUITheme("nord")
+	nord := renderDiff(r, "+added", 0)
+	SetTUITheme("catppuccin")
+	catppuccin := renderDiff(r, "+added", 0)
+
+	if nord == catppuccin {
+		t.Error("added lines should use the active theme's color")
+	}
+	if catppuccin != styled(r, CatppuccinMochaTheme.Secondary, "+added") {
+		t.Errorf("added line = %q", catppuccin)
+	}
+}
+
+func TestRenderDiff_NoColorWithoutTTY(t *testing.T) {
+	r := lipgloss.NewRenderer(&bytes.Buffer{})
+	got := renderDiff(r, testUnifiedDiff, 0)
+	if got != testUnifiedDiff {
+		t.Errorf("renderDiff() without a terminal = %q, want the diff unchanged", got)
+	}
+}
+
+func TestRenderDiff_Wraps(t *testing.T) {
+	r := lipgloss.NewRenderer(&bytes.Buffer{})
+	got := renderDiff(r, "+"+strings.Repeat("x", 25), 10)
+
+	lines := strings.Split(got, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3: %q", len(lines), got)
+	}
+	for _, line := range lines {
+		if lipgloss.Width(line) != 10 {
+			t.Errorf("line %q is %d wide, want 10", line, lipgloss.Width(line))
+		}
+	}
+}
+
+func TestIsUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{testUnifiedDiff, true},
+		{"@@ -1 +1 @@\n-a\n+b", true},
+		{"--- a/x\n+++ b/x", true},
+		{"Write 2 file(s):\n  - a.txt (write)", false},
+		{"- item\n+ item", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsUnifiedDiff(tt.s); got != tt.want {
+			t.Errorf("IsUnifiedDiff(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
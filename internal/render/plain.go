@@ -0,0 +1,144 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+)
+
+// plainEscapeBase offsets escaped ASCII characters into the Unicode
+// private use area while inline markup is stripped.
+const plainEscapeBase = 0xE000
+
+var (
+	plainHeadingRe   = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)(\s+#+)?\s*$`)
+	plainQuoteRe     = regexp.MustCompile(`^(\s*)>\s?`)
+	plainBulletRe    = regexp.MustCompile(`^(\s*)[*+](\s+)`)
+	plainTableSepRe  = regexp.MustCompile(`^\s*\|(\s*:?-+:?\s*\|)+\s*$`)
+	plainImageRe     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	plainLinkRe      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(\s+"[^"]*")?\)`)
+	plainAutolinkRe  = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	plainHTMLTagRe   = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9]*(\s[^<>]*)?/?>`)
+	plainStrongRe    = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	plainStrikeRe    = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	plainEmStarRe    = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+	plainEmUnderRe   = regexp.MustCompile(`(^|[^\w])_(\S(?:[^_]*?\S)?)_([^\w]|$)`)
+	plainEscapeRe    = regexp.MustCompile(`\\([\\` + "`" + `*_{}\[\]()#+\-.!|~<>])`)
+	plainCodeSpanRe  = regexp.MustCompile("(`+)(.+?)(`+)")
+	plainFenceOpenRe = regexp.MustCompile("^\\s{0,3}(```+|~~~+)")
+)
+
+// MarkdownToPlain converts markdown to readable plain text.
+// Headings, emphasis, links, quotes and HTML tags lose their markup, and
+// code fences are removed while their content is kept verbatim. List
+// markers and indentation are preserved so lists keep their structure.
+func MarkdownToPlain(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+
+	fence := ""
+	for _, line := range lines {
+		if fence != "" {
+			if strings.HasPrefix(strings.TrimSpace(line), fence) {
+				fence = ""
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+		if match := plainFenceOpenRe.FindStringSubmatch(line); match != nil {
+			fence = match[1]
+			continue
+		}
+
+		if isMarkdownRule(line) || plainTableSepRe.MatchString(line) {
+			continue
+		}
+		if match := plainHeadingRe.FindStringSubmatch(line); match != nil {
+			line = match[1]
+		}
+		line = plainQuoteRe.ReplaceAllString(line, "$1")
+		line = plainBulletRe.ReplaceAllString(line, "$1-$2")
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "|") && strings.HasSuffix(trimmed, "|") && len(trimmed) > 1 {
+			cells := strings.Split(trimmed[1:len(trimmed)-1], "|")
+			for i, cell := range cells {
+				cells[i] = strings.TrimSpace(cell)
+			}
+			line = strings.Join(cells, "  ")
+		}
+
+		line = plainInline(line)
+
+		// Outside code, keep at most one blank line in a row. Lines that
+		// held only markup (e.g. an HTML tag) count as blank.
+		if strings.TrimSpace(line) == "" {
+			if len(out) > 0 && out[len(out)-1] != "" {
+				out = append(out, "")
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+// isMarkdownRule reports whether line is a thematic break or a setext
+// heading underline: three or more of the same -, *, _ or = character.
+func isMarkdownRule(line string) bool {
+	trimmed := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	if len(trimmed) < 3 || !strings.ContainsRune("-*_=", rune(trimmed[0])) {
+		return false
+	}
+	return strings.Count(trimmed, trimmed[:1]) == len(trimmed)
+}
+
+// plainInline strips inline markdown from a line, leaving the content of
+// code spans untouched.
+func plainInline(line string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range plainCodeSpanRe.FindAllStringSubmatchIndex(line, -1) {
+		// Only matching backtick runs delimit a code span
+		if loc[3]-loc[2] != loc[7]-loc[6] {
+			continue
+		}
+		b.WriteString(plainText(line[last:loc[0]]))
+		b.WriteString(strings.TrimSpace(line[loc[4]:loc[5]]))
+		last = loc[1]
+	}
+	b.WriteString(plainText(line[last:]))
+	return b.String()
+}
+
+// plainText strips inline markdown from text that holds no code spans.
+func plainText(s string) string {
+	// Hide escaped characters from the markup patterns, restoring them
+	// without the backslash at the end
+	s = plainEscapeRe.ReplaceAllStringFunc(s, func(esc string) string {
+		return string(plainEscapeBase + rune(esc[1]))
+	})
+
+	s = plainImageRe.ReplaceAllString(s, "$1")
+	s = plainLinkRe.ReplaceAllStringFunc(s, func(link string) string {
+		match := plainLinkRe.FindStringSubmatch(link)
+		if match[1] == match[2] {
+			return match[1]
+		}
+		return match[1] + " (" + match[2] + ")"
+	})
+	s = plainAutolinkRe.ReplaceAllString(s, "$1")
+	s = plainHTMLTagRe.ReplaceAllString(s, "")
+	s = plainStrongRe.ReplaceAllString(s, "$2")
+	s = plainStrikeRe.ReplaceAllString(s, "$1")
+	s = plainEmStarRe.ReplaceAllString(s, "$1")
+	// Adjacent matches share a boundary character, so a second pass
+	// catches the ones the first skipped
+	s = plainEmUnderRe.ReplaceAllString(s, "$1$2$3")
+	s = plainEmUnderRe.ReplaceAllString(s, "$1$2$3")
+	return strings.Map(func(r rune) rune {
+		if r > plainEscapeBase && r < plainEscapeBase+0x80 {
+			return r - plainEscapeBase
+		}
+		return r
+	}, s)
+}
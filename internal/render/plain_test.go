@@ -0,0 +1,51 @@
+package render
+
+import "testing"
+
+func TestMarkdownToPlain(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"heading", "# Title\n\n## Section ##\ntext", "Title\n\nSection\ntext"},
+		{"setext heading", "Title\n=====\n\nSub\n---", "Title\n\nSub"},
+		{"emphasis", "some **bold**, *italic*, __strong__, _em_ and ~~gone~~ text", "some bold, italic, strong, em and gone text"},
+		{"snake case kept", "call my_func_name with 2 * 3 * 4", "call my_func_name with 2 * 3 * 4"},
+		{"inline code", "run `go test ./...` and `**not bold**`", "run go test ./... and **not bold**"},
+		{"links and images", "see [the docs](https://example.com) ![logo](logo.png) <https://go.dev>", "see the docs (https://example.com) logo https://go.dev"},
+		{"link with same text", "[https://go.dev](https://go.dev)", "https://go.dev"},
+		{"blockquote", "> quoted **text**\n> more", "quoted text\nmore"},
+		{"html tags", "<details>\n<summary>Thinking</summary>\n\nhidden\n</details>", "Thinking\n\nhidden"},
+		{"escapes", `not \*emphasis\* \# here`, "not *emphasis* # here"},
+		{"horizontal rule", "one\n\n---\n\ntwo\n\n* * *\nthree", "one\n\ntwo\n\nthree"},
+		{"table", "| Name | Age |\n|------|:---:|\n| Ada  | 36  |", "Name  Age\nAda  36"},
+		{"blank lines collapse", "a\n\n\n\nb\n\n", "a\n\nb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarkdownToPlain(tt.input); got != tt.want {
+				t.Errorf("MarkdownToPlain(%q) =\n%q\nwant\n%q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownToPlain_PreservesListStructure(t *testing.T) {
+	input := "Steps:\n\n* first **item**\n+ second\n  - nested `code`\n1. one\n2. two\n- [ ] todo"
+	want := "Steps:\n\n- first item\n- second\n  - nested code\n1. one\n2. two\n- [ ] todo"
+
+	if got := MarkdownToPlain(input); got != want {
+		t.Errorf("MarkdownToPlain() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestMarkdownToPlain_PreservesCodeContent(t *testing.T) {
+	input := "Example:\n\n```go\nfunc main() {\n\n\n\t// **not bold** # not a heading\n\tfmt.Println(\"*\")\n}\n```\n\n~~~\n> raw\n~~~\nafter"
+	want := "Example:\n\nfunc main() {\n\n\n\t// **not bold** # not a heading\n\tfmt.Println(\"*\")\n}\n\n> raw\nafter"
+
+	if got := MarkdownToPlain(input); got != want {
+		t.Errorf("MarkdownToPlain() =\n%q\nwant\n%q", got, want)
+	}
+}
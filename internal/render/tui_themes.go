@@ -109,6 +109,27 @@ var (
 		TextDim:  lipgloss.Color("#6272a4"),
 		TextMute: lipgloss.Color("#44475a"),
 	}
+
+	// HighContrastTheme is an accessibility theme with maximum contrast
+	// between text and background for low-vision users
+	HighContrastTheme = TUITheme{
+		Name:        "high-contrast",
+		Description: "High Contrast - Bright text on black for accessibility",
+
+		Background: lipgloss.Color("#000000"),
+		Surface:    lipgloss.Color("#000000"),
+		Border:     lipgloss.Color("#ffffff"),
+
+		Primary:   lipgloss.Color("#ffff00"), // Yellow
+		Secondary: lipgloss.Color("#00ff00"), // Green
+		Accent:    lipgloss.Color("#00ffff"), // Cyan
+		Warning:   lipgloss.Color("#ffaf00"), // Orange
+		Error:     lipgloss.Color("#ff5f5f"), // Red
+
+		Text:     lipgloss.Color("#ffffff"),
+		TextDim:  lipgloss.Color("#e0e0e0"),
+		TextMute: lipgloss.Color("#c0c0c0"),
+	}
 )
 
 // currentTUITheme holds the currently active TUI theme
@@ -140,6 +161,8 @@ func GetTUIThemeByName(name string) (TUITheme, bool) {
 		return NordTheme, true
 	case "dracula":
 		return DraculaTheme, true
+	case "high-contrast":
+		return HighContrastTheme, true
 	default:
 		return TUITheme{}, false
 	}
@@ -152,6 +175,7 @@ func AvailableTUIThemes() []TUITheme {
 		CatppuccinMochaTheme,
 		NordTheme,
 		DraculaTheme,
+		HighContrastTheme,
 	}
 }
 
@@ -2,6 +2,8 @@ package render
 
 import (
 	"testing"
+
+	"github.com/diogo/geminiweb/internal/config"
 )
 
 func TestTUITheme_Structure(t *testing.T) {
@@ -216,6 +218,16 @@ func TestTUIThemeNames(t *testing.T) {
 	})
 }
 
+func TestHighContrastTheme(t *testing.T) {
+	theme, ok := GetTUIThemeByName("high-contrast")
+	if !ok {
+		t.Fatal("high-contrast theme should be available")
+	}
+	if theme.Background != "#000000" || theme.Text != "#ffffff" {
+		t.Errorf("high-contrast theme should use white text on black, got %s on %s", theme.Text, theme.Background)
+	}
+}
+
 func TestThemeColors_AreValidHex(t *testing.T) {
 	themes := AvailableTUIThemes()
 
@@ -255,3 +267,16 @@ func TestThemeColors_AreValidHex(t *testing.T) {
 		})
 	}
 }
+
+func TestTUIThemes_KnownToConfigValidate(t *testing.T) {
+	for _, name := range TUIThemeNames() {
+		cfg := config.DefaultConfig()
+		cfg.TUITheme = name
+		cfg.DownloadDir = t.TempDir()
+		for _, w := range config.Validate(cfg) {
+			if w.Field == "tui_theme" {
+				t.Errorf("config.Validate rejects theme %q: %s", name, w)
+			}
+		}
+	}
+}
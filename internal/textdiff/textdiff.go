@@ -0,0 +1,267 @@
+// Package textdiff computes line diffs and formats them as unified diffs.
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// MaxLines bounds the lines compared by Lines and Unified, after the lines
+// both sides share at the start and end are set aside. Larger inputs return
+// ErrTooLarge instead of spending seconds of CPU on a diff nobody reads.
+const MaxLines = 20000
+
+// ErrTooLarge is returned for inputs with more than MaxLines differing
+// lines.
+var ErrTooLarge = errors.New("too large to diff")
+
+// Kinds of Op
+const (
+	Equal  byte = ' '
+	Delete byte = '-'
+	Insert byte = '+'
+)
+
+// Op is one step of an edit script from a to b. A and B index the line in
+// each input, and are -1 for the side the line is missing from.
+type Op struct {
+	Kind byte
+	A    int
+	B    int
+}
+
+// Lines computes a shortest edit script from a to b with Myers' linear
+// space algorithm, so memory stays proportional to the input size. Within
+// a change, deletions come before insertions.
+func Lines(a, b []string) ([]Op, error) {
+	// Compare small integers instead of strings
+	ids := make(map[string]int, len(a))
+	intern := func(lines []string) []int {
+		out := make([]int, len(lines))
+		for i, line := range lines {
+			id, ok := ids[line]
+			if !ok {
+				id = len(ids)
+				ids[line] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+
+	d := &differ{a: intern(a), b: intern(b)}
+	d.keepA = make([]bool, len(a))
+	d.keepB = make([]bool, len(b))
+	aLo, aHi, bLo, bHi := d.trim(0, len(a), 0, len(b))
+	if (aHi-aLo)+(bHi-bLo) > MaxLines {
+		return nil, ErrTooLarge
+	}
+	size := 2*((aHi-aLo+bHi-bLo+1)/2) + 3
+	d.vf = make([]int, size)
+	d.vb = make([]int, size)
+	d.compare(aLo, aHi, bLo, bHi)
+
+	ops := make([]Op, 0, max(len(a), len(b)))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && !d.keepA[i]:
+			ops = append(ops, Op{Kind: Delete, A: i, B: -1})
+			i++
+		case j < len(b) && !d.keepB[j]:
+			ops = append(ops, Op{Kind: Insert, A: -1, B: j})
+			j++
+		default:
+			ops = append(ops, Op{Kind: Equal, A: i, B: j})
+			i++
+			j++
+		}
+	}
+	return ops, nil
+}
+
+// differ marks the lines of a and b that belong to a longest common
+// subsequence; the others are deleted or inserted
+type differ struct {
+	a, b         []int
+	keepA, keepB []bool
+	vf, vb       []int // Furthest reaching x per diagonal, forward and backward
+}
+
+// trim marks the common prefix and suffix of a[aLo:aHi] and b[bLo:bHi] as
+// kept and returns the bounds of what is left
+func (d *differ) trim(aLo, aHi, bLo, bHi int) (int, int, int, int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.keepA[aLo], d.keepB[bLo] = true, true
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+		d.keepA[aHi], d.keepB[bHi] = true, true
+	}
+	return aLo, aHi, bLo, bHi
+}
+
+// compare marks the common subsequence of a[aLo:aHi] and b[bLo:bHi] by
+// splitting at the middle snake of an optimal path
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	aLo, aHi, bLo, bHi = d.trim(aLo, aHi, bLo, bHi)
+	if aLo == aHi || bLo == bHi {
+		return
+	}
+	x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+	for i := 0; x+i < u; i++ {
+		d.keepA[x+i], d.keepB[y+i] = true, true
+	}
+	d.compare(aLo, x, bLo, y)
+	d.compare(u, aHi, v, bHi)
+}
+
+// middleSnake finds the middle snake of a shortest edit path between
+// a[aLo:aHi] and b[bLo:bHi], searching from both ends at once, and returns
+// its start (x, y) and end (u, v)
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	maxD := (n + m + 1) / 2
+	off := maxD + 1
+	vf, vb := d.vf, d.vb
+	vf[off+1], vb[off+1] = 0, 0
+
+	for depth := 0; depth <= maxD; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			xs := vf[off+k-1] + 1
+			if k == -depth || (k != depth && vf[off+k-1] < vf[off+k+1]) {
+				xs = vf[off+k+1]
+			}
+			ys := xs - k
+			xe, ye := xs, ys
+			for xe < n && ye < m && d.a[aLo+xe] == d.b[bLo+ye] {
+				xe++
+				ye++
+			}
+			vf[off+k] = xe
+			if odd && k >= delta-(depth-1) && k <= delta+(depth-1) && xe+vb[off+delta-k] >= n {
+				return aLo + xs, bLo + ys, aLo + xe, bLo + ye
+			}
+		}
+
+		for k := -depth; k <= depth; k += 2 {
+			xs := vb[off+k-1] + 1
+			if k == -depth || (k != depth && vb[off+k-1] < vb[off+k+1]) {
+				xs = vb[off+k+1]
+			}
+			ys := xs - k
+			xe, ye := xs, ys
+			for xe < n && ye < m && d.a[aHi-1-xe] == d.b[bHi-1-ye] {
+				xe++
+				ye++
+			}
+			vb[off+k] = xe
+			if !odd && delta-k >= -depth && delta-k <= depth && xe+vf[off+delta-k] >= n {
+				return aHi - xe, bHi - ye, aHi - xs, bHi - ys
+			}
+		}
+	}
+	// Unreachable: the searches meet by depth maxD
+	return aLo, bLo, aLo, bLo
+}
+
+// Unified formats the differences between a and b as a unified diff with
+// contextLines of context around each change, labelling the sides nameA
+// and nameB. It returns "" if they are equal, and ErrTooLarge if Lines
+// does.
+func Unified(nameA, nameB string, a, b []string, contextLines int) (string, error) {
+	ops, err := Lines(a, b)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	lineA, lineB, counted := 0, 0, 0
+	for start := 0; start < len(ops); {
+		// Find the next change
+		for start < len(ops) && ops[start].Kind == Equal {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+
+		// Extend the hunk until a run of unchanged lines longer than
+		// twice the context separates it from the next change
+		hunkStart := max(start-contextLines, 0)
+		end := start
+		for end < len(ops) {
+			if ops[end].Kind != Equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].Kind == Equal {
+				run++
+			}
+			if run == len(ops) || run-end > 2*contextLines {
+				end = min(end+contextLines, len(ops))
+				break
+			}
+			end = run
+		}
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		countA, countB := countSides(ops[counted:hunkStart])
+		lineA, lineB, counted = lineA+countA, lineB+countB, hunkStart
+		writeHunk(&sb, lineA, lineB, ops[hunkStart:end], a, b)
+		start = end
+	}
+	return sb.String(), nil
+}
+
+// writeHunk writes the hunk ops with its @@ header; lineA and lineB count
+// the lines of each side before it
+func writeHunk(sb *strings.Builder, lineA, lineB int, ops []Op, a, b []string) {
+	countA, countB := countSides(ops)
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(lineA, countA), hunkRange(lineB, countB))
+
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			sb.WriteString(" " + a[op.A] + "\n")
+		case Delete:
+			sb.WriteString("-" + a[op.A] + "\n")
+		case Insert:
+			sb.WriteString("+" + b[op.B] + "\n")
+		}
+	}
+}
+
+// countSides returns how many lines of a and of b the ops cover
+func countSides(ops []Op) (countA, countB int) {
+	for _, op := range ops {
+		if op.A >= 0 {
+			countA++
+		}
+		if op.B >= 0 {
+			countB++
+		}
+	}
+	return countA, countB
+}
+
+// hunkRange formats the 1-based "start,count" range of one side of a hunk.
+// An empty range refers to the line before it, as in GNU diff.
+func hunkRange(before, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,158 @@
+package textdiff
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// lcsLength is the quadratic reference the edit scripts are checked against
+func lcsLength(a, b []string) int {
+	prev := make([]int, len(b)+1)
+	for i := range a {
+		cur := make([]int, len(b)+1)
+		for j := range b {
+			if a[i] == b[j] {
+				cur[j+1] = prev[j] + 1
+			} else {
+				cur[j+1] = max(prev[j+1], cur[j])
+			}
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// checkScript verifies ops turns a into b and keeps a longest common
+// subsequence
+func checkScript(t *testing.T, a, b []string, ops []Op) {
+	t.Helper()
+	var gotA, gotB []string
+	equal := 0
+	for _, op := range ops {
+		switch op.Kind {
+		case Equal:
+			if a[op.A] != b[op.B] {
+				t.Fatalf("equal op pairs %q with %q", a[op.A], b[op.B])
+			}
+			gotA = append(gotA, a[op.A])
+			gotB = append(gotB, b[op.B])
+			equal++
+		case Delete:
+			gotA = append(gotA, a[op.A])
+		case Insert:
+			gotB = append(gotB, b[op.B])
+		}
+	}
+	if strings.Join(gotA, "\n") != strings.Join(a, "\n") || strings.Join(gotB, "\n") != strings.Join(b, "\n") {
+		t.Fatalf("ops do not cover the inputs in order: %v", ops)
+	}
+	if want := lcsLength(a, b); equal != want {
+		t.Fatalf("script keeps %d lines, want the LCS length %d\na=%q\nb=%q", equal, want, a, b)
+	}
+}
+
+func TestLines_ShortestScript(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"", ""},
+		{"a", ""},
+		{"", "a b"},
+		{"a b c", "a b c"},
+		{"a b c a b b a", "c b a b a c"},
+		{"x a b c", "a b c y"},
+		{"a b c d e", "e d c b a"},
+	}
+	for _, tt := range tests {
+		a, b := strings.Fields(tt.a), strings.Fields(tt.b)
+		ops, err := Lines(a, b)
+		if err != nil {
+			t.Fatalf("Lines(%q, %q) error = %v", tt.a, tt.b, err)
+		}
+		checkScript(t, a, b, ops)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	random := func() []string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = string(rune('a' + rng.Intn(4)))
+		}
+		return lines
+	}
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		ops, err := Lines(a, b)
+		if err != nil {
+			t.Fatalf("Lines() error = %v", err)
+		}
+		checkScript(t, a, b, ops)
+	}
+}
+
+func TestLines_TooLarge(t *testing.T) {
+	a := make([]string, MaxLines)
+	b := make([]string, MaxLines)
+	for i := range a {
+		a[i] = fmt.Sprint("a", i)
+		b[i] = fmt.Sprint("b", i)
+	}
+	if _, err := Lines(a, b); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Lines() error = %v, want ErrTooLarge", err)
+	}
+
+	// Shared lines do not count towards the limit
+	b = append(append([]string(nil), a...), "extra")
+	ops, err := Lines(a, b)
+	if err != nil {
+		t.Fatalf("Lines() error = %v", err)
+	}
+	if last := ops[len(ops)-1]; last.Kind != Insert || len(ops) != len(b) {
+		t.Errorf("unexpected script ending in %+v", last)
+	}
+}
+
+func TestUnified_HunkHeaders(t *testing.T) {
+	var a, b []string
+	for i := 1; i <= 15; i++ {
+		a = append(a, fmt.Sprint(i))
+		b = append(b, fmt.Sprint(i))
+	}
+	b[4] = "five"
+	b = append(b, "16")
+
+	got, err := Unified("a", "b", a, b, 3)
+	if err != nil {
+		t.Fatalf("Unified() error = %v", err)
+	}
+	want := "--- a\n+++ b\n" +
+		"@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n" +
+		"@@ -13,3 +13,4 @@\n 13\n 14\n 15\n+16\n"
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+	if got, _ := Unified("a", "b", a, a, 3); got != "" {
+		t.Errorf("equal input should give an empty diff, got %q", got)
+	}
+}
+
+// BenchmarkUnified_ScatteredEdits diffs a large file with an edit every
+// few lines, the worst case for the old quadratic table
+func BenchmarkUnified_ScatteredEdits(b *testing.B) {
+	before := make([]string, 8000)
+	after := make([]string, len(before))
+	for i := range before {
+		before[i] = fmt.Sprintf("line %d", i)
+		after[i] = before[i]
+		if i%5 == 0 {
+			after[i] = fmt.Sprintf("edited %d", i)
+		}
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Unified("f", "f", before, after, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+// ArchiveHistoryStore is implemented by history stores that can archive
+// conversations, hiding them from the default /history list.
+type ArchiveHistoryStore interface {
+	Archive(id string) error
+	Unarchive(id string) error
+}
+
+// hasArchived reports whether any of the conversations is archived
+func hasArchived(conversations []*history.Conversation) bool {
+	for _, conv := range conversations {
+		if conv.Archived {
+			return true
+		}
+	}
+	return false
+}
+
+// handleArchiveCommand handles /archive and /unarchive for the current
+// conversation
+func (m Model) handleArchiveCommand(archive bool) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if m.conversation == nil {
+		m.err = fmt.Errorf("no saved conversation to archive")
+		return m, nil
+	}
+	store, ok := m.historyStore.(ArchiveHistoryStore)
+	if !ok {
+		m.err = fmt.Errorf("archiving is not supported by this history store")
+		return m, nil
+	}
+
+	if archive {
+		if err := store.Archive(m.conversation.ID); err != nil {
+			m.err = fmt.Errorf("failed to archive conversation: %w", err)
+			return m, nil
+		}
+		m.conversation.Archived = true
+		m.err = fmt.Errorf("✓ Archived %q (Tab in /history shows archived chats)", m.conversation.Title)
+		return m, nil
+	}
+
+	if err := store.Unarchive(m.conversation.ID); err != nil {
+		m.err = fmt.Errorf("failed to unarchive conversation: %w", err)
+		return m, nil
+	}
+	m.conversation.Archived = false
+	m.err = fmt.Errorf("✓ Unarchived %q", m.conversation.Title)
+	return m, nil
+}
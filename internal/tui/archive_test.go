@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+func newArchiveTestModel(t *testing.T) (Model, *history.Store) {
+	t.Helper()
+	store, err := history.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	conv, _ := store.CreateConversation("gemini-2.5-flash")
+	_ = store.AddMessage(conv.ID, "user", "Old chat", "")
+	conv, _ = store.GetConversation(conv.ID)
+
+	return newTestModel(withHistory(store, conv), func(m *Model) { m.fullHistoryStore = store }), store
+}
+
+func TestArchiveCommand_RoundTrip(t *testing.T) {
+	m, store := newArchiveTestModel(t)
+	id := m.conversation.ID
+
+	updated, _ := m.handleArchiveCommand(true)
+	m = updated.(Model)
+	if m.err == nil || !strings.HasPrefix(m.err.Error(), "✓ Archived \"Old chat\"") {
+		t.Errorf("unexpected feedback: %v", m.err)
+	}
+	if conv, _ := store.GetConversation(id); !conv.Archived || !m.conversation.Archived {
+		t.Fatal("conversation should be archived in the store and the model")
+	}
+
+	updated, _ = m.handleArchiveCommand(false)
+	m = updated.(Model)
+	if conv, _ := store.GetConversation(id); conv.Archived || m.conversation.Archived {
+		t.Error("conversation should be unarchived")
+	}
+	if m.err == nil || m.err.Error() != "✓ Unarchived \"Old chat\"" {
+		t.Errorf("unexpected feedback: %v", m.err)
+	}
+}
+
+func TestArchiveCommand_Errors(t *testing.T) {
+	m := Model{textarea: textarea.New()}
+	updated, _ := m.handleArchiveCommand(true)
+	if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "no saved conversation") {
+		t.Errorf("expected an error without a conversation, got %v", err)
+	}
+
+	m.conversation = &history.Conversation{ID: "c1"}
+	m.historyStore = &mockHistoryStoreForModel{}
+	updated, _ = m.handleArchiveCommand(true)
+	if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "not supported") {
+		t.Errorf("expected an unsupported store error, got %v", err)
+	}
+}
+
+func TestHistorySelector_HidesArchivedByDefault(t *testing.T) {
+	m, _ := newArchiveTestModel(t)
+	m.selectingHistory = true
+	m.historyList = []*history.Conversation{
+		{ID: "a", Title: "Active chat", Model: "fast"},
+		{ID: "b", Title: "Archived chat", Model: "fast", Archived: true},
+	}
+
+	filtered := m.filteredHistory()
+	if len(filtered) != 1 || filtered[0].ID != "a" {
+		t.Fatalf("archived conversations should be hidden, got %d", len(filtered))
+	}
+	view := m.renderHistorySelector()
+	if strings.Contains(view, "Archived chat") || !strings.Contains(view, "Show archived") {
+		t.Errorf("unexpected selector view:\n%s", view)
+	}
+
+	updated, _ := m.updateHistorySelection(tea.KeyMsg{Type: tea.KeyTab})
+	m = updated.(Model)
+	if filtered := m.filteredHistory(); len(filtered) != 2 {
+		t.Fatalf("Tab should show archived conversations, got %d", len(filtered))
+	}
+	view = ansiPattern.ReplaceAllString(m.renderHistorySelector(), "")
+	if !strings.Contains(view, "Archived chat") || !strings.Contains(view, "[archived]") {
+		t.Errorf("archived conversation should be listed and marked:\n%s", view)
+	}
+
+	// The filter applies to archived conversations too
+	m.historyFilter = "archived"
+	if filtered := m.filteredHistory(); len(filtered) != 1 || filtered[0].ID != "b" {
+		t.Errorf("filter should match the archived chat, got %v", filtered)
+	}
+
+	updated, _ = m.updateHistorySelection(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).historyArchived {
+		t.Error("closing the selector should reset the archived toggle")
+	}
+}
+
+func TestHistorySelector_AllArchived(t *testing.T) {
+	m, _ := newArchiveTestModel(t)
+	m.selectingHistory = true
+	m.historyList = []*history.Conversation{{ID: "b", Title: "Archived chat", Archived: true}}
+
+	view := m.renderHistorySelector()
+	if !strings.Contains(view, "All conversations are archived") {
+		t.Errorf("expected a hint when every conversation is archived:\n%s", view)
+	}
+}
@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// argToken is a single shell-like word from a command's arguments.
+type argToken struct {
+	text   string
+	quoted bool // Any part of the token was quoted; never treated as a flag
+}
+
+// tokenizeArgs splits args into words, honouring single and double quotes
+// so paths containing spaces can be passed, e.g. `"my chat.md"`. Inside
+// double quotes, \" and \\ are escapes; elsewhere backslashes are literal so
+// Windows paths work unquoted. An unterminated quote runs to the end.
+func tokenizeArgs(args string) []argToken {
+	var tokens []argToken
+	var current strings.Builder
+	inToken, quoted := false, false
+	var quote rune
+
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, argToken{text: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		inToken, quoted = false, false
+	}
+
+	runes := []rune(args)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			switch {
+			case r == quote:
+				quote = 0
+			case r == '\\' && quote == '"' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\'):
+				i++
+				current.WriteRune(runes[i])
+			default:
+				current.WriteRune(r)
+			}
+		case (r == '"' || r == '\'') && !inToken:
+			// Quotes only open at the start of a word, so apostrophes
+			// inside words (e.g. "John's") stay literal
+			quote = r
+			inToken, quoted = true, true
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// parseFlags splits slash-command arguments into positional arguments and
+// flags, so commands handle `-f json`, `--format=json` and quoted paths
+// consistently.
+//
+// Rules:
+//   - "-x value" and "--name value" take the next word as the value, unless
+//     it is another flag or missing, in which case the value is ""
+//   - flags named in boolFlags never take a value; the next word stays
+//     positional
+//   - "--name=value" and "-x=value" set the value inline
+//   - quoted words are always positional, even if they start with "-"
+//   - "--" ends flag parsing; the remaining words are positional
+//   - if a flag is repeated, the last value wins
+//
+// Flag names are stored without leading dashes. Callers should check for
+// unknown names with unknownFlags.
+func parseFlags(args string, boolFlags ...string) (positional []string, flags map[string]string) {
+	flags = make(map[string]string)
+	tokens := tokenizeArgs(args)
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.quoted || !isFlagToken(tok.text) {
+			positional = append(positional, tok.text)
+			continue
+		}
+
+		if tok.text == "--" {
+			for _, rest := range tokens[i+1:] {
+				positional = append(positional, rest.text)
+			}
+			break
+		}
+
+		name := strings.TrimLeft(tok.text, "-")
+		if eq := strings.IndexByte(name, '='); eq >= 0 {
+			flags[name[:eq]] = name[eq+1:]
+			continue
+		}
+
+		value := ""
+		if slices.Contains(boolFlags, name) {
+			flags[name] = value
+			continue
+		}
+		if i+1 < len(tokens) && (tokens[i+1].quoted || !isFlagToken(tokens[i+1].text)) {
+			value = tokens[i+1].text
+			i++
+		}
+		flags[name] = value
+	}
+
+	return positional, flags
+}
+
+// isFlagToken reports whether an unquoted word looks like a flag.
+// A lone "-" is positional (commonly meaning stdin/stdout).
+func isFlagToken(s string) bool {
+	return len(s) > 1 && s[0] == '-'
+}
+
+// unknownFlags returns the sorted names of flags not in known.
+func unknownFlags(flags map[string]string, known ...string) []string {
+	allowed := make(map[string]bool, len(known))
+	for _, k := range known {
+		allowed[k] = true
+	}
+
+	var unknown []string
+	for name := range flags {
+		if !allowed[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// flagDisplayName formats a flag name the way a user would type it.
+func flagDisplayName(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// checkUnknownFlags returns an error naming the first unknown flag, if any.
+func checkUnknownFlags(flags map[string]string, known ...string) error {
+	if unknown := unknownFlags(flags, known...); len(unknown) > 0 {
+		return fmt.Errorf("unknown flag: %s", flagDisplayName(unknown[0]))
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           string
+		wantPositional []string
+		wantFlags      map[string]string
+	}{
+		{
+			name:           "positional only",
+			args:           "chat.md",
+			wantPositional: []string{"chat.md"},
+			wantFlags:      map[string]string{},
+		},
+		{
+			name:           "short flag with value",
+			args:           "chat -f json",
+			wantPositional: []string{"chat"},
+			wantFlags:      map[string]string{"f": "json"},
+		},
+		{
+			name:           "long flag with inline value",
+			args:           "--format=json chat",
+			wantPositional: []string{"chat"},
+			wantFlags:      map[string]string{"format": "json"},
+		},
+		{
+			name:           "double quoted path with spaces",
+			args:           `"my chat.md" -o out`,
+			wantPositional: []string{"my chat.md"},
+			wantFlags:      map[string]string{"o": "out"},
+		},
+		{
+			name:           "single quoted flag value",
+			args:           `-o '/tmp/My Exports/chat.md'`,
+			wantPositional: nil,
+			wantFlags:      map[string]string{"o": "/tmp/My Exports/chat.md"},
+		},
+		{
+			name:           "escaped quote inside double quotes",
+			args:           `"say \"hi\".md"`,
+			wantPositional: []string{`say "hi".md`},
+			wantFlags:      map[string]string{},
+		},
+		{
+			name:           "apostrophe inside word is literal",
+			args:           "John's chat.md",
+			wantPositional: []string{"John's", "chat.md"},
+			wantFlags:      map[string]string{},
+		},
+		{
+			name:           "backslashes are literal outside quotes",
+			args:           `C:\Users\me\chat.md`,
+			wantPositional: []string{`C:\Users\me\chat.md`},
+			wantFlags:      map[string]string{},
+		},
+		{
+			name:           "quoted dash word is positional",
+			args:           `"-weird.md"`,
+			wantPositional: []string{"-weird.md"},
+			wantFlags:      map[string]string{},
+		},
+		{
+			name:           "repeated flag keeps last value",
+			args:           "-f md chat -f json",
+			wantPositional: []string{"chat"},
+			wantFlags:      map[string]string{"f": "json"},
+		},
+		{
+			name:           "flag followed by flag has empty value",
+			args:           "--thoughts -f json",
+			wantPositional: nil,
+			wantFlags:      map[string]string{"thoughts": "", "f": "json"},
+		},
+		{
+			name:           "trailing flag without value",
+			args:           "chat -f",
+			wantPositional: []string{"chat"},
+			wantFlags:      map[string]string{"f": ""},
+		},
+		{
+			name:           "double dash ends flags",
+			args:           "-f json -- -not-a-flag",
+			wantPositional: []string{"-not-a-flag"},
+			wantFlags:      map[string]string{"f": "json"},
+		},
+		{
+			name:           "lone dash is positional",
+			args:           "-",
+			wantPositional: []string{"-"},
+			wantFlags:      map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positional, flags := parseFlags(tt.args)
+			if !reflect.DeepEqual(positional, tt.wantPositional) {
+				t.Errorf("positional = %q, want %q", positional, tt.wantPositional)
+			}
+			if !reflect.DeepEqual(flags, tt.wantFlags) {
+				t.Errorf("flags = %v, want %v", flags, tt.wantFlags)
+			}
+		})
+	}
+}
+
+func TestUnknownFlags(t *testing.T) {
+	_, flags := parseFlags("chat -f json -x 1 --verbose")
+
+	got := unknownFlags(flags, "f")
+	want := []string{"verbose", "x"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unknownFlags() = %v, want %v", got, want)
+	}
+
+	if err := checkUnknownFlags(flags, "f", "verbose"); err == nil || err.Error() != "unknown flag: -x" {
+		t.Errorf("checkUnknownFlags() = %v, want unknown flag: -x", err)
+	}
+	if err := checkUnknownFlags(flags, "f", "x", "verbose"); err != nil {
+		t.Errorf("checkUnknownFlags() unexpected error: %v", err)
+	}
+}
+
+func TestParseExportArgs_FlagParser(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       string
+		wantPath   string
+		wantFormat string
+		wantErr    string
+	}{
+		{"quoted path with spaces", `"my chats/today.json"`, "my chats/today.json", "json", ""},
+		{"quoted path and flag", `'notes on go' -f json`, "notes on go.json", "json", ""},
+		{"unknown flag is reported", "chat -x md", "", "", "unknown flag: -x"},
+		{"flag without value", "chat -f", "", "", "flag -f requires a value (json or md)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, format, _, err := parseExportArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseExportArgs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExportArgs() unexpected error: %v", err)
+			}
+			if path != tt.wantPath || format != tt.wantFormat {
+				t.Errorf("parseExportArgs() = (%q, %q), want (%q, %q)", path, format, tt.wantPath, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestParseFlags_BoolFlags(t *testing.T) {
+	positional, flags := parseFlags("-m chat.md -f md", "m")
+	if !reflect.DeepEqual(positional, []string{"chat.md"}) {
+		t.Errorf("positional = %q, want [chat.md]", positional)
+	}
+	if v, ok := flags["m"]; !ok || v != "" {
+		t.Errorf("flags[m] = %q, %v; want set without a value", v, ok)
+	}
+	if flags["f"] != "md" {
+		t.Errorf("flags[f] = %q, want md", flags["f"])
+	}
+}
+
+func TestParseExportArgs_FrontMatter(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            string
+		wantPath        string
+		wantFrontMatter bool
+		wantErr         string
+	}{
+		{"short flag before path", "-m chat", "chat.md", true, ""},
+		{"short flag after path", "chat.md -m", "chat.md", true, ""},
+		{"long flag", "--with-frontmatter notes", "notes.md", true, ""},
+		{"with explicit format", "chat -m -f md", "chat.md", true, ""},
+		{"without flag", "chat", "chat.md", false, ""},
+		{"json is rejected", "chat.json -m", "", false, "front matter requires markdown format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, format, frontMatter, err := parseExportArgs(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("parseExportArgs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseExportArgs() unexpected error: %v", err)
+			}
+			if path != tt.wantPath || format != "markdown" || frontMatter != tt.wantFrontMatter {
+				t.Errorf("parseExportArgs() = (%q, %q, %v), want (%q, markdown, %v)",
+					path, format, frontMatter, tt.wantPath, tt.wantFrontMatter)
+			}
+		})
+	}
+}
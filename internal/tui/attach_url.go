@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+// attachURLTimeout bounds how long an /attach-url download may take
+const attachURLTimeout = 60 * time.Second
+
+// attachURLMaxBytes caps the size of a resource downloaded by /attach-url;
+// it matches the upload limit for non-image files
+const attachURLMaxBytes = api.MaxFileSize
+
+// attachURLAnyHost in attach_url_hosts allows downloads from any host
+const attachURLAnyHost = "*"
+
+// attachURLMaxRedirects caps the redirects followed by an /attach-url
+// download, like the default HTTP client
+const attachURLMaxRedirects = 10
+
+// httpDoer sends HTTP requests; *http.Client implements it
+type httpDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// urlAttachedMsg is sent when an /attach-url download and upload completes
+type urlAttachedMsg struct {
+	url  string
+	file *api.UploadedFile
+	err  error
+}
+
+// handleAttachURLCommand handles the /attach-url <url> command
+func (m Model) handleAttachURLCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	rawURL := strings.TrimSpace(args)
+	if rawURL == "" || strings.ContainsAny(rawURL, " \t") {
+		m.err = fmt.Errorf("usage: /attach-url <url>")
+		return m, nil
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		m.err = fmt.Errorf("invalid URL %q: only http and https URLs can be attached", rawURL)
+		return m, nil
+	}
+	if len(m.attachURLHosts) == 0 {
+		m.err = fmt.Errorf("/attach-url is off - list the hosts to allow in attach_url_hosts (%q allows any)", attachURLAnyHost)
+		return m, nil
+	}
+	if !hostAllowed(target.Hostname(), m.attachURLHosts) {
+		m.err = fmt.Errorf("host %s is not in attach_url_hosts", target.Hostname())
+		return m, nil
+	}
+	if m.client == nil {
+		m.err = fmt.Errorf("client not available for file upload")
+		return m, nil
+	}
+
+	m.err = nil
+	return m, m.attachURL(target)
+}
+
+// hostAllowed reports whether host is allowed by the allowlist. A listed
+// host also allows its subdomains, "*" allows any host and an empty list
+// allows none.
+func hostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.Trim(strings.TrimSpace(entry), "."))
+		if entry == "" {
+			continue
+		}
+		if entry == attachURLAnyHost || host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// attachURL creates a command that downloads target to a temporary file,
+// uploads it and removes the temporary file again
+func (m Model) attachURL(target *url.URL) tea.Cmd {
+	client := m.client
+	fetcher := m.urlFetcher
+	if fetcher == nil {
+		fetcher = newAttachURLClient(m.attachURLHosts)
+	}
+
+	jobs := m.jobs
+
+	return func() tea.Msg {
+		ctx, done := jobs.start(jobLabel("Attach", target.String()))
+		defer done()
+
+		dir, err := os.MkdirTemp("", "geminiweb-attach-")
+		if err != nil {
+			return urlAttachedMsg{url: target.String(), err: fmt.Errorf("download failed: %w", err)}
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		// Keep the remote file name so the MIME type can be detected
+		localPath := filepath.Join(dir, attachmentNameFromURL(target))
+		if err := downloadToFile(ctx, fetcher, target.String(), localPath, attachURLMaxBytes); err != nil {
+			return urlAttachedMsg{url: target.String(), err: fmt.Errorf("download failed: %w", err)}
+		}
+
+		file, err := client.UploadFile(localPath)
+		if ctx.Err() != nil {
+			return urlAttachedMsg{url: target.String(), err: errJobCancelled}
+		}
+		if err != nil {
+			return urlAttachedMsg{url: target.String(), err: fmt.Errorf("upload failed: %w", err)}
+		}
+		// The temporary copy is removed once the command returns
+		file.LocalPath = ""
+		return urlAttachedMsg{url: target.String(), file: file}
+	}
+}
+
+// newAttachURLClient returns the HTTP client for /attach-url downloads,
+// which only follows redirects to allowed hosts
+func newAttachURLClient(allowed []string) *http.Client {
+	return &http.Client{
+		Timeout: attachURLTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= attachURLMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", attachURLMaxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errors.New("redirect to a URL that is not http or https")
+			}
+			if !hostAllowed(req.URL.Hostname(), allowed) {
+				return fmt.Errorf("redirect to host %s is not in attach_url_hosts", req.URL.Hostname())
+			}
+			return nil
+		},
+	}
+}
+
+// attachmentNameFromURL returns the file name to upload a URL's content
+// under, falling back to "download" when the path has none
+func attachmentNameFromURL(target *url.URL) string {
+	name := path.Base(target.Path)
+	if name == "." || name == "/" || name == "" {
+		return "download"
+	}
+	return name
+}
+
+// downloadToFile fetches rawURL into dst, failing when the response is not
+// successful or the body is larger than maxBytes
+func downloadToFile(ctx context.Context, fetcher httpDoer, rawURL, dst string, maxBytes int64) error {
+	ctx, cancel := context.WithTimeout(ctx, attachURLTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := fetcher.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	if resp.ContentLength > maxBytes {
+		return fmt.Errorf("resource size (%d bytes) exceeds maximum (%d bytes)", resp.ContentLength, maxBytes)
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	// Read one byte past the limit to detect bodies without a length
+	n, err := io.Copy(f, io.LimitReader(resp.Body, maxBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n > maxBytes {
+		return fmt.Errorf("resource exceeds maximum size (%d bytes)", maxBytes)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+// mockURLFetcher serves a canned response for every request
+type mockURLFetcher struct {
+	body          string
+	status        int
+	contentLength int64
+	err           error
+	requested     string
+}
+
+func (f *mockURLFetcher) Do(req *http.Request) (*http.Response, error) {
+	f.requested = req.URL.String()
+	if f.err != nil {
+		return nil, f.err
+	}
+	status := f.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	length := f.contentLength
+	if length == 0 {
+		length = int64(len(f.body))
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        http.StatusText(status),
+		ContentLength: length,
+		Body:          io.NopCloser(strings.NewReader(f.body)),
+	}, nil
+}
+
+// contentCapturingClient records the content of the file being uploaded,
+// since the temporary download is removed afterwards
+type contentCapturingClient struct {
+	*mockGeminiClientWithUpload
+	content string
+}
+
+func (c *contentCapturingClient) UploadFile(filePath string, opts ...api.UploadOption) (*api.UploadedFile, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	c.content = string(data)
+	return c.mockGeminiClientWithUpload.UploadFile(filePath, opts...)
+}
+
+func newAttachURLTestModel(client api.GeminiClientInterface, fetcher httpDoer) Model {
+	return newTestModel(func(m *Model) {
+		m.client = client
+		m.urlFetcher = fetcher
+		m.attachURLHosts = []string{"example.com"}
+	})
+}
+
+func TestAttachURL_DownloadsAndUploads(t *testing.T) {
+	client := &contentCapturingClient{mockGeminiClientWithUpload: &mockGeminiClientWithUpload{
+		uploadFileResult: &api.UploadedFile{FileName: "notes.txt", ResourceID: "r1"},
+	}}
+	fetcher := &mockURLFetcher{body: "remote notes"}
+	m := newAttachURLTestModel(client, fetcher)
+
+	updated, cmd := m.handleAttachURLCommand("https://example.com/docs/notes.txt")
+	m = updated.(Model)
+	if m.err != nil || cmd == nil {
+		t.Fatalf("expected a download command, got err=%v", m.err)
+	}
+
+	msg := cmd()
+	if fetcher.requested != "https://example.com/docs/notes.txt" {
+		t.Errorf("fetched %q", fetcher.requested)
+	}
+	if client.content != "remote notes" {
+		t.Errorf("uploaded content = %q, want the downloaded body", client.content)
+	}
+	if filepath.Base(client.uploadFilePath) != "notes.txt" {
+		t.Errorf("upload should keep the remote file name, got %q", client.uploadFilePath)
+	}
+	if _, err := os.Stat(filepath.Dir(client.uploadFilePath)); !os.IsNotExist(err) {
+		t.Errorf("temporary download should be removed, stat err = %v", err)
+	}
+
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+	if len(m.attachments) != 1 || m.attachments[0].ResourceID != "r1" {
+		t.Fatalf("attachment not added: %+v", m.attachments)
+	}
+	if m.attachments[0].LocalPath != "" {
+		t.Errorf("LocalPath should not point at the removed download")
+	}
+	if m.err == nil || !strings.HasPrefix(m.err.Error(), "✓ Attached notes.txt") {
+		t.Errorf("unexpected feedback: %v", m.err)
+	}
+}
+
+func TestAttachURL_RejectsOversizedDownloads(t *testing.T) {
+	client := &mockGeminiClientWithUpload{uploadFileResult: &api.UploadedFile{FileName: "big.bin"}}
+	fetcher := &mockURLFetcher{body: "small", contentLength: attachURLMaxBytes + 1}
+	m := newAttachURLTestModel(client, fetcher)
+
+	_, cmd := m.handleAttachURLCommand("https://example.com/big.bin")
+	msg := cmd().(urlAttachedMsg)
+	if msg.err == nil || !strings.HasPrefix(msg.err.Error(), "download failed:") {
+		t.Fatalf("expected a download error, got %v", msg.err)
+	}
+	if client.uploadFileCalled {
+		t.Error("an oversized download should not be uploaded")
+	}
+
+	// Bodies without a declared length are cut off at the limit
+	dst := filepath.Join(t.TempDir(), "out")
+	err := downloadToFile(context.Background(), &mockURLFetcher{body: "0123456789", contentLength: -1}, "https://example.com/x", dst, 4)
+	if err == nil || !strings.Contains(err.Error(), "exceeds maximum") {
+		t.Errorf("expected a size error, got %v", err)
+	}
+}
+
+func TestAttachURL_DistinguishesErrors(t *testing.T) {
+	client := &mockGeminiClientWithUpload{uploadFileErr: errors.New("quota exceeded")}
+	m := newAttachURLTestModel(client, &mockURLFetcher{err: errors.New("connection refused")})
+	_, cmd := m.handleAttachURLCommand("https://example.com/a.txt")
+	updated, _ := m.Update(cmd())
+	if err := updated.(Model).err; err == nil || err.Error() != "download failed: connection refused" {
+		t.Errorf("unexpected download error: %v", err)
+	}
+
+	m = newAttachURLTestModel(client, &mockURLFetcher{status: http.StatusNotFound})
+	_, cmd = m.handleAttachURLCommand("https://example.com/a.txt")
+	if msg := cmd().(urlAttachedMsg); msg.err == nil || !strings.Contains(msg.err.Error(), "Not Found") {
+		t.Errorf("expected the HTTP status in the error, got %v", msg.err)
+	}
+
+	m = newAttachURLTestModel(client, &mockURLFetcher{body: "data"})
+	_, cmd = m.handleAttachURLCommand("https://example.com/a.txt")
+	updated, _ = m.Update(cmd())
+	m = updated.(Model)
+	if m.err == nil || m.err.Error() != "upload failed: quota exceeded" {
+		t.Errorf("unexpected upload error: %v", m.err)
+	}
+	if len(m.attachments) != 0 {
+		t.Error("failed uploads should not be attached")
+	}
+}
+
+func TestAttachURL_Validation(t *testing.T) {
+	m := newAttachURLTestModel(&mockGeminiClientWithUpload{}, &mockURLFetcher{})
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", "usage: /attach-url <url>"},
+		{"ftp://example.com/a", "only http and https"},
+		{"not a url", "usage: /attach-url <url>"},
+		{"https://evil.com/a", "host evil.com is not in attach_url_hosts"},
+	}
+	for _, tt := range tests {
+		updated, cmd := m.handleAttachURLCommand(tt.args)
+		err := updated.(Model).err
+		if cmd != nil || err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("args %q: got err=%v cmd=%v, want %q", tt.args, err, cmd != nil, tt.want)
+		}
+	}
+
+	if _, cmd := m.handleAttachURLCommand("https://docs.example.com/a"); cmd == nil {
+		t.Error("subdomains of an allowed host should be accepted")
+	}
+
+	m.attachURLHosts = nil
+	updated, cmd := m.handleAttachURLCommand("https://example.com/a")
+	if err := updated.(Model).err; cmd != nil || err == nil || !strings.Contains(err.Error(), "/attach-url is off") {
+		t.Errorf("without allowed hosts: got err=%v cmd=%v, want /attach-url off", err, cmd != nil)
+	}
+}
+
+func TestAttachURLClient_ChecksRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "secret")
+	}))
+	defer target.Close()
+	// The same server under another host name
+	targetURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, targetURL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	dst := filepath.Join(t.TempDir(), "download")
+	err := downloadToFile(context.Background(), newAttachURLClient([]string{"127.0.0.1"}), origin.URL, dst, 1024)
+	if err == nil || !strings.Contains(err.Error(), "redirect to host localhost is not in attach_url_hosts") {
+		t.Errorf("redirect to a host not allowed: err = %v", err)
+	}
+
+	if err := downloadToFile(context.Background(), newAttachURLClient([]string{"127.0.0.1", "localhost"}), origin.URL, dst, 1024); err != nil {
+		t.Fatalf("redirect to an allowed host: err = %v", err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "secret" {
+		t.Errorf("downloaded %q, want the redirect target", data)
+	}
+}
+
+func TestHostAllowed(t *testing.T) {
+	tests := []struct {
+		host    string
+		allowed []string
+		want    bool
+	}{
+		{"anything.org", nil, false},
+		{"anything.org", []string{"*"}, true},
+		{"example.com", []string{"Example.com"}, true},
+		{"cdn.example.com", []string{"example.com"}, true},
+		{"badexample.com", []string{"example.com"}, false},
+		{"example.com", []string{"other.com", " "}, false},
+	}
+	for _, tt := range tests {
+		if got := hostAllowed(tt.host, tt.allowed); got != tt.want {
+			t.Errorf("hostAllowed(%q, %v) = %v, want %v", tt.host, tt.allowed, got, tt.want)
+		}
+	}
+}
+
+func TestAttachmentNameFromURL(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://example.com/a/report.pdf": "report.pdf",
+		"https://example.com/":             "download",
+		"https://example.com":              "download",
+	} {
+		u, _ := url.Parse(raw)
+		if got := attachmentNameFromURL(u); got != want {
+			t.Errorf("attachmentNameFromURL(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+const (
+	// attachmentSnippetMaxFileSize is the largest text file that gets a snippet.
+	attachmentSnippetMaxFileSize = 32 * 1024
+
+	// attachmentSnippetMaxBytes is the maximum length of a single snippet.
+	attachmentSnippetMaxBytes = 1024
+
+	// attachmentNoteMaxSnippetBytes caps the combined size of all snippets.
+	attachmentNoteMaxSnippetBytes = 4 * 1024
+)
+
+// textMIMETypes lists non-"text/" MIME types that are safe to preview.
+var textMIMETypes = map[string]bool{
+	"application/json":       true,
+	"application/xml":        true,
+	"application/javascript": true,
+	"application/x-yaml":     true,
+	"application/yaml":       true,
+	"application/toml":       true,
+	"application/x-sh":       true,
+}
+
+// buildAttachmentNote returns a short note listing the attached files, with
+// a snippet of small text files read from disk. Large, binary or remote-only
+// files are listed by name and size only. Returns "" if there are no files.
+func buildAttachmentNote(files []*api.UploadedFile) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[Attached files - please take them into account]\n")
+
+	snippetBudget := attachmentNoteMaxSnippetBytes
+	for _, file := range files {
+		if file == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "- %s (%s, %d bytes)", file.FileName, attachmentMIMEType(file), file.Size)
+
+		snippet, reason := attachmentSnippet(file, snippetBudget)
+		switch {
+		case snippet != "":
+			snippetBudget -= len(snippet)
+			sb.WriteString(":\n```\n")
+			sb.WriteString(snippet)
+			if !strings.HasSuffix(snippet, "\n") {
+				sb.WriteString("\n")
+			}
+			sb.WriteString("```\n")
+		case reason != "":
+			fmt.Fprintf(&sb, " - %s\n", reason)
+		default:
+			sb.WriteString("\n")
+		}
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// withAttachmentNote prepends the attachment note to prompt.
+func withAttachmentNote(prompt string, files []*api.UploadedFile) string {
+	note := buildAttachmentNote(files)
+	if note == "" {
+		return prompt
+	}
+	return note + "\n\n" + prompt
+}
+
+// attachmentSnippet reads a preview of a text attachment.
+// When no snippet is produced, reason briefly explains why (or is empty for
+// files that are simply not text, such as images).
+func attachmentSnippet(file *api.UploadedFile, budget int) (snippet, reason string) {
+	if !isTextMIMEType(attachmentMIMEType(file)) || file.LocalPath == "" {
+		return "", ""
+	}
+	if file.Size > attachmentSnippetMaxFileSize {
+		return "", "too large for a preview"
+	}
+	if budget <= 0 {
+		return "", "preview omitted"
+	}
+
+	f, err := os.Open(file.LocalPath)
+	if err != nil {
+		return "", ""
+	}
+	defer func() { _ = f.Close() }()
+
+	limit := attachmentSnippetMaxBytes
+	if budget < limit {
+		limit = budget
+	}
+	data, err := io.ReadAll(io.LimitReader(f, int64(limit)+1))
+	if err != nil {
+		return "", ""
+	}
+
+	truncated := len(data) > limit
+	if truncated {
+		data = data[:limit]
+		// Don't cut a multi-byte character in half
+		for i := 1; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return "", "binary content"
+	}
+
+	snippet = string(data)
+	if truncated {
+		snippet += "\n..."
+	}
+	return snippet, ""
+}
+
+// attachmentMIMEType returns the attachment's MIME type without parameters.
+func attachmentMIMEType(file *api.UploadedFile) string {
+	mimeType := file.MIMEType
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	mimeType = strings.TrimSpace(mimeType)
+	if mimeType == "" {
+		return "application/octet-stream"
+	}
+	return mimeType
+}
+
+// isTextMIMEType reports whether a MIME type is a text format worth previewing.
+func isTextMIMEType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || textMIMETypes[mimeType]
+}
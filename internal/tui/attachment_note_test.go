@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+func writeAttachment(t *testing.T, name string, data []byte, mimeType string) *api.UploadedFile {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return &api.UploadedFile{
+		ResourceID: "res-" + name,
+		FileName:   name,
+		MIMEType:   mimeType,
+		Size:       int64(len(data)),
+		LocalPath:  path,
+	}
+}
+
+func TestBuildAttachmentNote(t *testing.T) {
+	t.Run("includes snippet for small text files", func(t *testing.T) {
+		file := writeAttachment(t, "notes.txt", []byte("line one\nline two\n"), "text/plain; charset=utf-8")
+		note := buildAttachmentNote([]*api.UploadedFile{file})
+
+		if !strings.Contains(note, "- notes.txt (text/plain, 18 bytes)") {
+			t.Errorf("expected file entry, got:\n%s", note)
+		}
+		if !strings.Contains(note, "```\nline one\nline two\n```") {
+			t.Errorf("expected snippet, got:\n%s", note)
+		}
+	})
+
+	t.Run("truncates long snippets", func(t *testing.T) {
+		file := writeAttachment(t, "long.md", []byte(strings.Repeat("z", attachmentSnippetMaxBytes*2)), "text/markdown")
+		note := buildAttachmentNote([]*api.UploadedFile{file})
+
+		if !strings.Contains(note, "\n...\n```") {
+			t.Errorf("expected truncation marker, got:\n%s", note)
+		}
+		if strings.Count(note, "z") != attachmentSnippetMaxBytes {
+			t.Errorf("snippet length = %d, want %d", strings.Count(note, "z"), attachmentSnippetMaxBytes)
+		}
+	})
+
+	t.Run("summarizes large text files", func(t *testing.T) {
+		data := []byte(strings.Repeat("secret\n", attachmentSnippetMaxFileSize/7+1))
+		file := writeAttachment(t, "big.log", data, "text/plain")
+		note := buildAttachmentNote([]*api.UploadedFile{file})
+
+		if !strings.Contains(note, "big.log") || !strings.Contains(note, "too large for a preview") {
+			t.Errorf("expected size summary, got:\n%s", note)
+		}
+		if strings.Contains(note, "secret") {
+			t.Error("large file content should not be included")
+		}
+	})
+
+	t.Run("skips binary content", func(t *testing.T) {
+		file := writeAttachment(t, "data.txt", []byte("abc\x00\x01\x02"), "text/plain")
+		note := buildAttachmentNote([]*api.UploadedFile{file})
+
+		if !strings.Contains(note, "binary content") || strings.Contains(note, "```") {
+			t.Errorf("expected binary summary, got:\n%s", note)
+		}
+	})
+
+	t.Run("lists images without snippet", func(t *testing.T) {
+		file := writeAttachment(t, "photo.png", []byte("\x89PNG\r\n"), "image/png")
+		note := buildAttachmentNote([]*api.UploadedFile{file})
+
+		if !strings.Contains(note, "- photo.png (image/png, 6 bytes)") || strings.Contains(note, "```") {
+			t.Errorf("unexpected note:\n%s", note)
+		}
+	})
+
+	t.Run("empty without files", func(t *testing.T) {
+		if note := buildAttachmentNote(nil); note != "" {
+			t.Errorf("expected empty note, got %q", note)
+		}
+	})
+}
+
+func TestSendMessageWithAttachments_AttachmentNote(t *testing.T) {
+	file := writeAttachment(t, "main.go", []byte("package main\n"), "text/x-go")
+
+	send := func(enabled bool) string {
+		var received string
+		session := &mockChatSession{
+			sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+				received = prompt
+				return &models.ModelOutput{Candidates: []models.Candidate{{Text: "ok"}}}, nil
+			},
+		}
+		m := Model{
+			session:        session,
+			attachments:    []*api.UploadedFile{file},
+			attachmentNote: enabled,
+		}
+		m.sendMessageWithAttachments("review this")()
+		return received
+	}
+
+	t.Run("prepends note when enabled", func(t *testing.T) {
+		prompt := send(true)
+		if !strings.HasPrefix(prompt, "[Attached files") {
+			t.Errorf("expected note prefix, got:\n%s", prompt)
+		}
+		if !strings.Contains(prompt, "package main") || !strings.HasSuffix(prompt, "\n\nreview this") {
+			t.Errorf("unexpected prompt:\n%s", prompt)
+		}
+	})
+
+	t.Run("sends prompt unchanged when disabled", func(t *testing.T) {
+		if prompt := send(false); prompt != "review this" {
+			t.Errorf("prompt = %q, want unchanged", prompt)
+		}
+	})
+}
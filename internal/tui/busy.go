@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"errors"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// sendJobKind labels the jobs that send a prompt to the model
+const sendJobKind = "Send"
+
+// errBusy rejects a send started while another one is in flight
+var errBusy = errors.New("wait for the current response to finish")
+
+// isBusy reports whether a new send must wait: a reply or tool run is
+// pending, or a send whose wait was dismissed with Esc is still in flight.
+// Every path that sends a prompt checks it, so sends never overlap.
+func (m Model) isBusy() bool {
+	return m.loading || m.jobs.sending()
+}
+
+// sendToModel sends a prompt and files through session, listed in /jobs
+// until the session returns
+func sendToModel(jobs *jobRegistry, session ChatSessionInterface, prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+	done := jobs.startSend(jobLabel(sendJobKind, prompt))
+	defer done()
+	return session.SendMessage(prompt, files)
+}
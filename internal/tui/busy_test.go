@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// newBlockingSession returns a session that counts sends and holds each
+// one until release is closed
+func newBlockingSession() (*mockChatSession, *atomic.Int32, chan struct{}) {
+	var sends atomic.Int32
+	release := make(chan struct{})
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			sends.Add(1)
+			<-release
+			return &models.ModelOutput{Candidates: []models.Candidate{{Text: "reply"}}}, nil
+		},
+	}
+	return session, &sends, release
+}
+
+func newBusyTestModel(session ChatSessionInterface) Model {
+	return newTestModel(withSession(session), withInput(""), withMessages(
+		chatMessage{role: "user", content: "question"},
+		chatMessage{role: "assistant", content: "answer"},
+	))
+}
+
+// waitFor polls cond until it holds
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestBusy_QuickSuccessiveSends(t *testing.T) {
+	m := newBusyTestModel(&mockChatSession{})
+
+	m, first := typeAndEnter(m, "one")
+	if first == nil || !m.loading {
+		t.Fatal("the first send should start")
+	}
+	m, _ = typeAndEnter(m, "two")
+
+	users := 0
+	for _, msg := range m.messages {
+		if msg.role == "user" {
+			users++
+		}
+	}
+	if users != 2 || m.messages[len(m.messages)-1].content != "one" {
+		t.Errorf("only the first send should proceed, messages = %+v", m.messages)
+	}
+}
+
+func TestBusy_SendAfterEscWaitsForReply(t *testing.T) {
+	session, sends, release := newBlockingSession()
+	m := newBusyTestModel(session)
+
+	m, _ = typeAndEnter(m, "one")
+	// Run the send like the runtime would, and dismiss the wait with Esc
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.sendMessage("one")()
+	}()
+	waitFor(t, func() bool { return sends.Load() == 1 })
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.loading {
+		t.Fatal("esc should stop waiting")
+	}
+
+	m, cmd := typeAndEnter(m, "two")
+	if !errors.Is(m.err, errBusy) {
+		t.Errorf("second send: err = %v, want errBusy", m.err)
+	}
+	if cmd != nil || m.messages[len(m.messages)-1].content != "one" {
+		t.Error("the second send should not proceed")
+	}
+	if m.textarea.Value() != "two" {
+		t.Errorf("rejected input should be kept, got %q", m.textarea.Value())
+	}
+
+	close(release)
+	wg.Wait()
+	if sends.Load() != 1 {
+		t.Errorf("sends = %d, want 1", sends.Load())
+	}
+
+	// Once the reply is in, sending works again
+	m, cmd = typeAndEnter(m, "two")
+	if cmd == nil || m.messages[len(m.messages)-1].content != "two" {
+		t.Error("send should proceed after the first one finished")
+	}
+}
+
+func TestBusy_CommandsRejectedWhileSendInFlight(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(Model) (tea.Model, tea.Cmd)
+	}{
+		{"retry", func(m Model) (tea.Model, tea.Cmd) { return m.handleRetryCommand("") }},
+		{"continue", func(m Model) (tea.Model, tea.Cmd) { return m.handleContinueCommand("") }},
+		{"try", func(m Model) (tea.Model, tea.Cmd) { return m.handleTryCommand("pro") }},
+		{"initial prompt", func(m Model) (tea.Model, tea.Cmd) { return m.Update(initialPromptMsg{prompt: "hi"}) }},
+		{"model fallback", func(m Model) (tea.Model, tea.Cmd) {
+			m.modelFallback = &modelFallbackOffer{prompt: "hi"}
+			return m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &mockChatSession{}
+			m := newBusyTestModel(session)
+			done := m.jobs.startSend(jobLabel(sendJobKind, "question"))
+			defer done()
+
+			updated, cmd := tt.run(m)
+			um := updated.(Model)
+			if !errors.Is(um.err, errBusy) || um.loading {
+				t.Errorf("err = %v, loading = %v, want errBusy", um.err, um.loading)
+			}
+			if cmd != nil {
+				cmd()
+			}
+			if session.sendMessageCalled {
+				t.Error("nothing should be sent")
+			}
+		})
+	}
+}
+
+func TestBusy_OtherJobsDoNotBlockSends(t *testing.T) {
+	m := newBusyTestModel(&mockChatSession{})
+	_, done := m.jobs.start(jobLabel("Download", "2 image(s)"))
+	defer done()
+
+	if m.isBusy() {
+		t.Error("a download should not block sending")
+	}
+	m, cmd := typeAndEnter(m, "hello")
+	if cmd == nil || !m.loading {
+		t.Error("send should proceed")
+	}
+}
+
+func TestBusy_SendInFlightUntilSessionReturns(t *testing.T) {
+	session, sends, release := newBlockingSession()
+	m := newBusyTestModel(session)
+
+	result := make(chan tea.Msg, 1)
+	go func() { result <- m.sendPrompt("one", nil)() }()
+	waitFor(t, func() bool { return sends.Load() == 1 })
+
+	// The request cannot be aborted, so /jobs cannot drop it either
+	if m.jobs.cancel(m.jobs.list()[0].id) {
+		t.Error("a send should not be cancellable")
+	}
+	if !m.isBusy() {
+		t.Error("the request is still in flight and should block sends")
+	}
+
+	close(release)
+	if _, ok := (<-result).(responseMsg); !ok {
+		t.Error("the send should deliver its reply")
+	}
+	if m.isBusy() {
+		t.Error("sends should be allowed once the reply is in")
+	}
+}
+
+func TestBusy_InitialPromptKeptWhileBusy(t *testing.T) {
+	m := newBusyTestModel(&mockChatSession{})
+	m.loading = true
+
+	updated, cmd := m.Update(initialPromptMsg{prompt: "summarize notes.md"})
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("nothing should be sent while busy")
+	}
+	if !errors.Is(m.err, errBusy) || !strings.Contains(m.err.Error(), "initial prompt") {
+		t.Errorf("err = %v, want the initial prompt reported as not sent", m.err)
+	}
+	if m.textarea.Value() != "summarize notes.md" {
+		t.Errorf("input = %q, want the initial prompt kept", m.textarea.Value())
+	}
+}
@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/clipboard"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// fakeClipboard is a clipboard backend that records writes
+type fakeClipboard struct {
+	available bool
+	copied    []string
+}
+
+func (c *fakeClipboard) Available() bool { return c.available }
+
+func (c *fakeClipboard) WriteAll(text string) error {
+	c.copied = append(c.copied, text)
+	return nil
+}
+
+func (c *fakeClipboard) ReadAll() (string, error) { return "", nil }
+
+func useFakeClipboard(t *testing.T, available bool) *fakeClipboard {
+	t.Helper()
+	fake := &fakeClipboard{available: available}
+	t.Cleanup(clipboard.SetBackend(fake))
+	return fake
+}
+
+func TestCopyAll_ClipboardUnavailable(t *testing.T) {
+	fake := useFakeClipboard(t, false)
+	m := Model{
+		messages: []chatMessage{{role: "user", content: "hi"}},
+		textarea: textarea.New(),
+	}
+
+	updated, _ := m.handleCopyAllCommand("")
+	typed := updated.(Model)
+	if typed.err == nil || typed.err.Error() != "clipboard not available" {
+		t.Errorf("err = %v, want clipboard not available", typed.err)
+	}
+	if len(fake.copied) != 0 {
+		t.Errorf("nothing should be written, got %q", fake.copied)
+	}
+}
+
+func TestCopyAll_ClipboardAvailable(t *testing.T) {
+	fake := useFakeClipboard(t, true)
+	m := Model{
+		messages: []chatMessage{{role: "user", content: "hi"}},
+		textarea: textarea.New(),
+	}
+
+	updated, _ := m.handleCopyAllCommand("")
+	typed := updated.(Model)
+	if typed.err == nil || typed.err.Error() != "✓ Copied 1 message(s) to clipboard" {
+		t.Errorf("err = %v", typed.err)
+	}
+	if len(fake.copied) != 1 {
+		t.Fatalf("expected one clipboard write, got %d", len(fake.copied))
+	}
+}
+
+func TestGemsModel_CopyID_ClipboardUnavailable(t *testing.T) {
+	fake := useFakeClipboard(t, false)
+	m := NewGemsModel(createMockClient(), false)
+	m.view = gemsViewDetails
+	m.selectedGem = &models.Gem{ID: "gem-123", Name: "Coder"}
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if got := updated.(GemsModel).feedback; got != "Clipboard not available" {
+		t.Errorf("feedback = %q, want Clipboard not available", got)
+	}
+	if len(fake.copied) != 0 {
+		t.Errorf("nothing should be written, got %q", fake.copied)
+	}
+
+	fake.available = true
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if got := updated.(GemsModel).feedback; got != "Copied ID: gem-123" {
+		t.Errorf("feedback = %q", got)
+	}
+	if len(fake.copied) != 1 || fake.copied[0] != "gem-123" {
+		t.Errorf("copied = %q, want [gem-123]", fake.copied)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/models"
 	"github.com/diogo/geminiweb/internal/render"
 )
 
@@ -32,6 +33,7 @@ const (
 	menuAutoReInit
 	menuCopyToClipboard
 	menuAutoApproveTools
+	menuNoEmoji
 	menuTheme    // Markdown theme
 	menuTUITheme // TUI color theme
 	menuExit
@@ -80,11 +82,14 @@ func NewConfigModel() ConfigModel {
 		cookiesExist = true
 	}
 
-	// Find current model index
+	// Find current model index, matching aliases such as "flash"
 	modelCursor := 0
-	models := config.AvailableModels()
-	for i, m := range models {
-		if m == cfg.DefaultModel {
+	current := cfg.DefaultModel
+	if model, ok := models.Lookup(current); ok {
+		current = model.Name
+	}
+	for i, name := range config.AvailableModels() {
+		if name == current {
 			modelCursor = i
 			break
 		}
@@ -118,11 +123,12 @@ func NewConfigModel() ConfigModel {
 		}
 	}
 
-	// Apply the configured TUI theme at startup
+	// Apply the configured TUI theme and emoji mode at startup
 	if currentTUITheme != "" {
 		render.SetTUITheme(currentTUITheme)
 		UpdateTheme()
 	}
+	SetNoEmoji(cfg.NoEmoji)
 
 	return ConfigModel{
 		config:          cfg,
@@ -322,6 +328,21 @@ func (m ConfigModel) handleSelect() (tea.Model, tea.Cmd) {
 			}
 			return m, clearFeedback(m.feedbackTimeout)
 
+		case menuNoEmoji:
+			m.config.NoEmoji = !m.config.NoEmoji
+			// Apply immediately, like the TUI theme
+			SetNoEmoji(m.config.NoEmoji)
+			if err := config.SaveConfig(m.config); err != nil {
+				m.feedback = fmt.Sprintf("Error: %v", err)
+			} else {
+				state := "disabled"
+				if m.config.NoEmoji {
+					state = "enabled"
+				}
+				m.feedback = fmt.Sprintf("No emoji %s", state)
+			}
+			return m, clearFeedback(m.feedbackTimeout)
+
 		case menuTheme:
 			m.view = viewThemeSelect
 			return m, nil
@@ -392,14 +413,14 @@ func (m ConfigModel) View() string {
 	// ═══════════════════════════════════════════════════════════════
 	// HEADER
 	// ═══════════════════════════════════════════════════════════════
-	headerContent := configTitleStyle.Render("✦ Configuration")
+	headerContent := configTitleStyle.Render(icon("✦", "*") + " Configuration")
 	header := configHeaderStyle.Width(contentWidth).Render(headerContent)
 	sections = append(sections, header)
 
 	// ═══════════════════════════════════════════════════════════════
 	// PATHS PANEL
 	// ═══════════════════════════════════════════════════════════════
-	pathsTitle := configSectionTitleStyle.Render("📁 Paths")
+	pathsTitle := configSectionTitleStyle.Render(icon("📁", "[paths]") + " Paths")
 
 	configPath := configPathStyle.Render(m.configDir + "/config.json")
 	cookiesPath := configPathStyle.Render(m.cookiesPath)
@@ -463,7 +484,7 @@ func (m ConfigModel) View() string {
 
 // renderMainMenu renders the main settings menu
 func (m ConfigModel) renderMainMenu(width int) string {
-	title := configSectionTitleStyle.Render("⚙ Settings")
+	title := configSectionTitleStyle.Render(icon("⚙", "[settings]") + " Settings")
 
 	var items []string
 
@@ -572,6 +593,21 @@ func (m ConfigModel) renderMainMenu(width int) string {
 		autoApproveValue,
 	))
 
+	// No Emoji
+	cursor = "  "
+	style = configMenuItemStyle
+	if m.cursor == menuNoEmoji {
+		cursor = configCursorStyle.Render("▸ ")
+		style = configMenuSelectedStyle
+	}
+	noEmojiValue := m.renderBoolValue(m.config.NoEmoji)
+	items = append(items, fmt.Sprintf("%s%s%s%s",
+		cursor,
+		style.Render("No Emoji"),
+		strings.Repeat(" ", 12),
+		noEmojiValue,
+	))
+
 	// Markdown Theme
 	cursor = "  "
 	style = configMenuItemStyle
@@ -629,7 +665,7 @@ func (m ConfigModel) renderMainMenu(width int) string {
 
 // renderModelSelect renders the model selection sub-menu
 func (m ConfigModel) renderModelSelect(width int) string {
-	title := configSectionTitleStyle.Render("🤖 Select Model")
+	title := configSectionTitleStyle.Render(icon("🤖", "[model]") + " Select Model")
 
 	models := config.AvailableModels()
 	var items []string
@@ -657,7 +693,7 @@ func (m ConfigModel) renderModelSelect(width int) string {
 
 // renderThemeSelect renders the markdown theme selection sub-menu
 func (m ConfigModel) renderThemeSelect(width int) string {
-	title := configSectionTitleStyle.Render("🎨 Select Markdown Theme")
+	title := configSectionTitleStyle.Render(icon("🎨", "[theme]") + " Select Markdown Theme")
 
 	themes := render.AvailableThemes()
 	var items []string
@@ -692,7 +728,7 @@ func (m ConfigModel) renderThemeSelect(width int) string {
 
 // renderTUIThemeSelect renders the TUI color theme selection sub-menu
 func (m ConfigModel) renderTUIThemeSelect(width int) string {
-	title := configSectionTitleStyle.Render("🎨 Select TUI Theme")
+	title := configSectionTitleStyle.Render(icon("🎨", "[theme]") + " Select TUI Theme")
 
 	themes := render.AvailableTUIThemes()
 	var items []string
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -328,6 +329,27 @@ func TestConfigModel_Update_Enter(t *testing.T) {
 		}
 	})
 
+	t.Run("on no emoji", func(t *testing.T) {
+		t.Setenv("HOME", t.TempDir())
+		t.Cleanup(func() { SetNoEmoji(false) })
+		m := NewConfigModel()
+		m.cursor = menuNoEmoji
+
+		updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		typedModel := updatedModel.(ConfigModel)
+		if !typedModel.config.NoEmoji {
+			t.Fatal("NoEmoji should be toggled")
+		}
+		// Applied without a restart
+		if !noEmoji {
+			t.Error("no-emoji mode should take effect as soon as it is saved")
+		}
+		typedModel.ready, typedModel.width, typedModel.height = true, 80, 30
+		if view := typedModel.View(); strings.Contains(view, "⚙") || !strings.Contains(view, "No Emoji") {
+			t.Error("config screen should switch to ASCII labels and list the setting")
+		}
+	})
+
 	t.Run("on exit", func(t *testing.T) {
 		m := NewConfigModel()
 		m.cursor = menuExit
@@ -710,17 +732,20 @@ func TestConfigModel_MenuConstants(t *testing.T) {
 	if menuAutoApproveTools != 6 {
 		t.Errorf("Expected menuAutoApproveTools to be 6, got %d", menuAutoApproveTools)
 	}
-	if menuTheme != 7 {
-		t.Errorf("Expected menuTheme to be 7, got %d", menuTheme)
+	if menuNoEmoji != 7 {
+		t.Errorf("Expected menuNoEmoji to be 7, got %d", menuNoEmoji)
 	}
-	if menuTUITheme != 8 {
-		t.Errorf("Expected menuTUITheme to be 8, got %d", menuTUITheme)
+	if menuTheme != 8 {
+		t.Errorf("Expected menuTheme to be 8, got %d", menuTheme)
 	}
-	if menuExit != 9 {
-		t.Errorf("Expected menuExit to be 9, got %d", menuExit)
+	if menuTUITheme != 9 {
+		t.Errorf("Expected menuTUITheme to be 9, got %d", menuTUITheme)
 	}
-	if menuItemCount != 10 {
-		t.Errorf("Expected menuItemCount to be 10, got %d", menuItemCount)
+	if menuExit != 10 {
+		t.Errorf("Expected menuExit to be 10, got %d", menuExit)
+	}
+	if menuItemCount != 11 {
+		t.Errorf("Expected menuItemCount to be 11, got %d", menuItemCount)
 	}
 }
 
@@ -917,3 +942,17 @@ func TestConfigModel_viewTUIThemeSelect_Constant(t *testing.T) {
 		t.Errorf("Expected viewTUIThemeSelect to be 3, got %d", viewTUIThemeSelect)
 	}
 }
+
+func TestNewConfigModel_ModelAlias(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DefaultModel = "Flash"
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error: %v", err)
+	}
+
+	m := NewConfigModel()
+	if got := config.AvailableModels()[m.modelCursor]; got != "fast" {
+		t.Errorf("model cursor points at %q, want the model behind the alias (fast)", got)
+	}
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleClearCommand handles the /clear command, which discards all
+// attachments
+func (m Model) handleClearCommand() (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+	if len(m.attachments) == 0 {
+		// Nothing to lose, so nothing to confirm
+		m.err = nil
+		return m, nil
+	}
+	return m.confirmDestructiveCommand("clear")
+}
+
+// handleDeleteCommand handles the /delete command, which removes the
+// current conversation from history and starts a new one
+func (m Model) handleDeleteCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /delete")
+		return m, nil
+	}
+	if m.fullHistoryStore == nil {
+		m.err = m.historyUnavailable()
+		return m, nil
+	}
+	if m.conversation == nil {
+		m.err = fmt.Errorf("no active conversation to delete")
+		return m, nil
+	}
+	return m.confirmDestructiveCommand("delete")
+}
+
+// confirmDestructiveCommand runs a destructive command right away when
+// confirmation is off, and asks for it otherwise
+func (m Model) confirmDestructiveCommand(command string) (tea.Model, tea.Cmd) {
+	if !m.confirmDestructive || m.skipDestructiveAsk {
+		return m.runDestructiveCommand(command)
+	}
+	m.pendingDestructiveCmd = command
+	m.err = nil
+	return m, nil
+}
+
+// runDestructiveCommand performs a confirmed /clear or /delete
+func (m Model) runDestructiveCommand(command string) (tea.Model, tea.Cmd) {
+	switch command {
+	case "clear":
+		m.attachments = nil
+		m.err = nil
+		return m, nil
+
+	case "delete":
+		conv := m.conversation
+		if conv == nil || m.fullHistoryStore == nil {
+			return m, nil
+		}
+		if err := m.fullHistoryStore.DeleteConversation(conv.ID); err != nil {
+			m.err = fmt.Errorf("failed to delete conversation: %w", err)
+			return m, nil
+		}
+		m.conversation = nil
+		updated, cmd := m.startNewConversation()
+		if um, ok := updated.(Model); ok && um.err == nil {
+			um.err = fmt.Errorf("✓ Deleted conversation '%s'", truncateTitle(destructiveTitle(conv.Title), 30))
+			return um, cmd
+		}
+		return updated, cmd
+	}
+	return m, nil
+}
+
+// destructiveTitle names a conversation in confirmation prompts
+func destructiveTitle(title string) string {
+	if title == "" {
+		return "Untitled"
+	}
+	return title
+}
+
+// updateDestructiveConfirmation handles input while a destructive command
+// awaits confirmation
+func (m Model) updateDestructiveConfirmation(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		command := m.pendingDestructiveCmd
+		switch msg.String() {
+		case "ctrl+c":
+			return m.handleInterrupt()
+
+		case "y", "Y":
+			m.pendingDestructiveCmd = ""
+			return m.runDestructiveCommand(command)
+
+		case "a", "A":
+			m.pendingDestructiveCmd = ""
+			m.skipDestructiveAsk = true
+			return m.runDestructiveCommand(command)
+
+		case "n", "N", "esc":
+			m.pendingDestructiveCmd = ""
+			m.err = fmt.Errorf("/%s cancelled", command)
+		}
+	}
+
+	return m, nil
+}
+
+// renderDestructiveConfirmation renders the prompt shown before /clear or
+// /delete
+func (m Model) renderDestructiveConfirmation() string {
+	var content strings.Builder
+
+	switch m.pendingDestructiveCmd {
+	case "clear":
+		content.WriteString("Clear attachments\n\n")
+		content.WriteString(fmt.Sprintf("Remove %d attachment(s) from the next message?", len(m.attachments)))
+	case "delete":
+		content.WriteString("Delete conversation\n\n")
+		title := ""
+		if m.conversation != nil {
+			title = m.conversation.Title
+		}
+		content.WriteString(fmt.Sprintf("Delete '%s' from history? This cannot be undone.", destructiveTitle(title)))
+	}
+
+	content.WriteString("\n\nConfirm? (y)es / (n)o / (a)lways this session")
+
+	return m.placeConfirmation(content.String())
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+func newDestructiveTestModel(store *mockFullHistoryStore) Model {
+	return newTestModel(withMessages(chatMessage{role: "user", content: "hi"}), func(m *Model) {
+		m.fullHistoryStore = store
+		m.conversation = &history.Conversation{ID: "conv-1", Title: "Old chat"}
+		m.attachments = []*api.UploadedFile{{FileName: "notes.txt"}}
+		m.confirmDestructive = true
+	})
+}
+
+func pressKey(t *testing.T, m Model, key string) Model {
+	t.Helper()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	return updated.(Model)
+}
+
+func TestDeleteCommand_ConfirmsBeforeDeleting(t *testing.T) {
+	store := &mockFullHistoryStore{createConversation: &history.Conversation{ID: "conv-2"}}
+	m := runCommand(newDestructiveTestModel(store), "/delete")
+
+	if m.pendingDestructiveCmd != "delete" {
+		t.Fatalf("pendingDestructiveCmd = %q, want delete", m.pendingDestructiveCmd)
+	}
+	if len(store.deletedIDs) != 0 {
+		t.Fatalf("deleted before confirmation: %v", store.deletedIDs)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Delete 'Old chat' from history?") || !strings.Contains(view, "(a)lways this session") {
+		t.Errorf("confirmation prompt not shown:\n%s", view)
+	}
+
+	m = pressKey(t, m, "y")
+	if len(store.deletedIDs) != 1 || store.deletedIDs[0] != "conv-1" {
+		t.Fatalf("deletedIDs = %v, want [conv-1]", store.deletedIDs)
+	}
+	if m.pendingDestructiveCmd != "" || m.conversation == nil || m.conversation.ID != "conv-2" || len(m.messages) != 0 {
+		t.Errorf("expected a fresh conversation after delete, got %+v", m.conversation)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "Deleted conversation 'Old chat'") {
+		t.Errorf("feedback = %v", m.err)
+	}
+}
+
+func TestDeleteCommand_DeclineKeepsConversation(t *testing.T) {
+	for _, key := range []string{"n", "esc"} {
+		store := &mockFullHistoryStore{}
+		m := runCommand(newDestructiveTestModel(store), "/delete")
+
+		var updated tea.Model
+		if key == "esc" {
+			updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		} else {
+			updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		}
+		m = updated.(Model)
+
+		if len(store.deletedIDs) != 0 || m.conversation.ID != "conv-1" || m.pendingDestructiveCmd != "" {
+			t.Errorf("%s: conversation should be kept, deleted %v", key, store.deletedIDs)
+		}
+	}
+}
+
+func TestClearCommand_Confirmation(t *testing.T) {
+	m := runCommand(newDestructiveTestModel(&mockFullHistoryStore{}), "/clear")
+	if m.pendingDestructiveCmd != "clear" || len(m.attachments) != 1 {
+		t.Fatalf("/clear should ask first, pending %q", m.pendingDestructiveCmd)
+	}
+	if view := m.View(); !strings.Contains(view, "Remove 1 attachment(s)") {
+		t.Errorf("confirmation prompt not shown:\n%s", view)
+	}
+
+	m = pressKey(t, m, "y")
+	if len(m.attachments) != 0 {
+		t.Errorf("attachments should be cleared, got %d", len(m.attachments))
+	}
+
+	// Nothing to clear: no prompt
+	m = runCommand(m, "/clear")
+	if m.pendingDestructiveCmd != "" {
+		t.Error("/clear without attachments should not ask")
+	}
+}
+
+func TestDestructiveConfirmation_DontAskAgain(t *testing.T) {
+	store := &mockFullHistoryStore{}
+	m := runCommand(newDestructiveTestModel(store), "/clear")
+	m = pressKey(t, m, "a")
+	if len(m.attachments) != 0 || !m.skipDestructiveAsk {
+		t.Fatal("always should confirm and skip later prompts")
+	}
+
+	m.conversation = &history.Conversation{ID: "conv-3"}
+	m = runCommand(m, "/delete")
+	if m.pendingDestructiveCmd != "" || len(store.deletedIDs) != 1 || store.deletedIDs[0] != "conv-3" {
+		t.Errorf("delete should run without asking, deleted %v", store.deletedIDs)
+	}
+}
+
+func TestDestructiveCommands_ConfirmationDisabled(t *testing.T) {
+	store := &mockFullHistoryStore{}
+	m := newDestructiveTestModel(store)
+	m.confirmDestructive = false
+
+	m = runCommand(m, "/clear")
+	if m.pendingDestructiveCmd != "" || len(m.attachments) != 0 {
+		t.Error("/clear should run immediately with confirmation off")
+	}
+
+	m = runCommand(m, "/delete")
+	if m.pendingDestructiveCmd != "" || len(store.deletedIDs) != 1 || store.deletedIDs[0] != "conv-1" {
+		t.Errorf("/delete should run immediately with confirmation off, deleted %v", store.deletedIDs)
+	}
+}
+
+func TestDeleteCommand_Errors(t *testing.T) {
+	m := newDestructiveTestModel(&mockFullHistoryStore{})
+	m.conversation = nil
+	m = runCommand(m, "/delete")
+	if m.err == nil || m.err.Error() != "no active conversation to delete" || m.pendingDestructiveCmd != "" {
+		t.Errorf("err = %v", m.err)
+	}
+
+	m = newDestructiveTestModel(&mockFullHistoryStore{})
+	m.fullHistoryStore = nil
+	m = runCommand(m, "/delete")
+	if m.err == nil || m.err.Error() != "history not available" {
+		t.Errorf("err = %v", m.err)
+	}
+
+	m = runCommand(newDestructiveTestModel(&mockFullHistoryStore{}), "/delete now")
+	if m.err == nil || m.err.Error() != "usage: /delete" {
+		t.Errorf("err = %v", m.err)
+	}
+}
@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// continueContextRunes is how much of the cut-off reply is quoted in the
+// continue prompt so the model can pick up mid-sentence
+const continueContextRunes = 200
+
+// truncatedReplyRunes is the length from which a reply stopping
+// mid-sentence is taken to have hit the reply length limit; shorter ones
+// usually just end informally
+const truncatedReplyRunes = 2000
+
+// continueHint is shown when a reply looks cut off
+const continueHint = "response may be cut off - type /continue to resume it"
+
+// handleContinueCommand handles the /continue command, which asks the model
+// to resume its last reply where it was cut off. The continuation is
+// appended to that reply rather than shown as a new message.
+func (m Model) handleContinueCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /continue")
+		return m, nil
+	}
+	if m.session == nil {
+		m.err = fmt.Errorf("no active session")
+		return m, nil
+	}
+	if m.isBusy() {
+		m.err = errBusy
+		return m, nil
+	}
+	if len(m.messages) == 0 || m.messages[len(m.messages)-1].role != "assistant" {
+		m.err = fmt.Errorf("nothing to continue - the last message is not a reply")
+		return m, nil
+	}
+
+	prompt := continuePrompt(m.messages[len(m.messages)-1].content)
+	cmd := m.beginSend(func(m *Model) tea.Cmd {
+		m.loading = true
+		m.err = nil
+		m.animationFrame = 0
+		return tea.Batch(m.sendContinuation(prompt), animationTick())
+	})
+	return m, cmd
+}
+
+// continuePrompt builds the prompt asking the model to resume a reply,
+// quoting the end of the reply so it knows exactly where it stopped
+func continuePrompt(reply string) string {
+	tail := []rune(strings.TrimSpace(reply))
+	if len(tail) > continueContextRunes {
+		tail = tail[len(tail)-continueContextRunes:]
+	}
+	return "Your previous response was cut off. It ended with:\n\n" +
+		string(tail) +
+		"\n\nContinue exactly where it left off. Do not repeat anything you already wrote and do not add an introduction."
+}
+
+// sendContinuation sends a continue prompt; the reply is merged into the
+// last assistant message
+func (m Model) sendContinuation(prompt string) tea.Cmd {
+	session := m.session
+	jobs := m.jobs
+	return func() tea.Msg {
+		output, err := sendToModel(jobs, session, prompt, nil)
+		if err != nil {
+			return errMsg{err: fmt.Errorf("continue: %w", err)}
+		}
+		return responseMsg{output: output, continuation: true}
+	}
+}
+
+// mergeContinuation appends a continuation reply to the message it continues
+func mergeContinuation(prev, next chatMessage) chatMessage {
+	prev.content = joinContinuation(prev.content, next.content)
+	if next.thoughts != "" {
+		if prev.thoughts != "" {
+			prev.thoughts += "\n\n"
+		}
+		prev.thoughts += next.thoughts
+	}
+	prev.images = append(prev.images, next.images...)
+	prev.sources = append(prev.sources, next.sources...)
+	return prev
+}
+
+// joinContinuation joins a cut-off reply and its continuation, adding a
+// space only when neither side already has whitespace at the seam
+func joinContinuation(prev, next string) string {
+	if prev == "" || next == "" {
+		return prev + next
+	}
+	last := []rune(prev)[len([]rune(prev))-1]
+	first := []rune(next)[0]
+	if unicode.IsSpace(last) || unicode.IsSpace(first) {
+		return prev + next
+	}
+	return prev + " " + next
+}
+
+// looksTruncated reports whether a reply appears to have been cut off: it
+// leaves a code block open, or it is long enough to have hit the length
+// limit and its last line stops mid-sentence.
+func looksTruncated(text string) bool {
+	text = strings.TrimRightFunc(text, unicode.IsSpace)
+	if text == "" {
+		return false
+	}
+
+	lines := strings.Split(text, "\n")
+	fences := 0
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			fences++
+		}
+	}
+	if fences%2 == 1 {
+		return true
+	}
+	if utf8.RuneCountInString(text) < truncatedReplyRunes {
+		return false
+	}
+
+	lastLine := strings.TrimSpace(lines[len(lines)-1])
+
+	// Structured lines (headings, tables, list items) often end without
+	// punctuation; only judge prose
+	if strings.HasPrefix(lastLine, "#") || strings.HasPrefix(lastLine, "|") ||
+		strings.HasPrefix(lastLine, "- ") || strings.HasPrefix(lastLine, "* ") {
+		return false
+	}
+
+	runes := []rune(lastLine)
+	last := runes[len(runes)-1]
+	return unicode.IsLetter(last) || last == ','
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+func TestHandleContinueCommand(t *testing.T) {
+	t.Run("sends continue prompt and appends the continuation", func(t *testing.T) {
+		var sent string
+		session := &mockChatSession{
+			sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+				sent = prompt
+				return &models.ModelOutput{Candidates: []models.Candidate{{Text: "and that is the end."}}}, nil
+			},
+		}
+		m := newTestModel(withSession(session), withMessages(
+			chatMessage{role: "user", content: "tell me a story"},
+			chatMessage{role: "assistant", content: "Once upon a time there was a dragon who"},
+		))
+
+		updated, cmd := m.handleContinueCommand("")
+		m = updated.(Model)
+		if !m.loading || cmd == nil {
+			t.Fatal("expected a continue request to be sent")
+		}
+
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok || len(batch) == 0 {
+			t.Fatal("expected a batch command")
+		}
+		msg, ok := batch[0]().(responseMsg)
+		if !ok || !msg.continuation {
+			t.Fatalf("expected a continuation response, got %#v", msg)
+		}
+		if !strings.Contains(sent, "cut off") || !strings.Contains(sent, "there was a dragon who") {
+			t.Errorf("continue prompt should quote the end of the reply, got %q", sent)
+		}
+
+		updated, _ = m.Update(msg)
+		m = updated.(Model)
+		if len(m.messages) != 2 {
+			t.Fatalf("continuation should be appended to the reply, got %d messages", len(m.messages))
+		}
+		want := "Once upon a time there was a dragon who and that is the end."
+		if m.messages[1].content != want {
+			t.Errorf("merged reply = %q, want %q", m.messages[1].content, want)
+		}
+		if m.err != nil {
+			t.Errorf("completed reply should not suggest /continue, got %v", m.err)
+		}
+	})
+
+	t.Run("requires a reply to continue", func(t *testing.T) {
+		m := newTestModel(withSession(&mockChatSession{}), withMessages(chatMessage{role: "user", content: "hi"}))
+
+		updated, cmd := m.handleContinueCommand("")
+		if cmd != nil {
+			t.Error("expected no request without a reply to continue")
+		}
+		if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "nothing to continue") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("normal reply is not merged", func(t *testing.T) {
+		m := newTestModel(withSession(&mockChatSession{}), withMessages(chatMessage{role: "assistant", content: "First."}))
+
+		updated, _ := m.Update(responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: "Second."}}}})
+		if got := len(updated.(Model).messages); got != 2 {
+			t.Errorf("expected a separate message, got %d messages", got)
+		}
+	})
+}
+
+func TestHandleContinueCommand_SavesMergedReply(t *testing.T) {
+	store, err := history.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	conv, _ := store.CreateConversation("test-model")
+	_ = store.AddMessage(conv.ID, "user", "tell me a story", "")
+	_ = store.AddMessage(conv.ID, "assistant", "Once upon a time", "")
+
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			return &models.ModelOutput{Candidates: []models.Candidate{{Text: "there was a dragon."}}}, nil
+		},
+	}
+	m := newTestModel(withSession(session), withMessages(
+		chatMessage{role: "user", content: "tell me a story"},
+		chatMessage{role: "assistant", content: "Once upon a time"},
+	))
+	m.conversation = conv
+	m.historyStore = store
+
+	updated, cmd := m.handleContinueCommand("")
+	m = updated.(Model)
+	batch := cmd().(tea.BatchMsg)
+	updated, _ = m.Update(batch[0]())
+	m = updated.(Model)
+
+	stored, _ := store.GetConversation(conv.ID)
+	if len(stored.Messages) != 2 || stored.Messages[1].Content != m.messages[1].content {
+		t.Errorf("stored messages = %+v, want the reply saved as displayed: %q", stored.Messages, m.messages[1].content)
+	}
+}
+
+func TestModel_ResponseMsgSuggestsContinue(t *testing.T) {
+	m := newTestModel(withSession(&mockChatSession{}))
+
+	updated, _ := m.Update(responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: "Here is the code:\n```go\nfunc main() {"}}}})
+	if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "/continue") {
+		t.Errorf("expected a /continue hint for a truncated reply, got %v", err)
+	}
+}
+
+func TestLooksTruncated(t *testing.T) {
+	long := strings.Repeat("word ", truncatedReplyRunes/5) + "\n\n"
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"complete sentence", long + "That is all.", false},
+		{"question", long + "Anything else?", false},
+		{"mid sentence", long + "The next step is to", true},
+		{"trailing comma", long + "First, second,", true},
+		{"short mid sentence", "Let me know if you need anything else", false},
+		{"open code block", "```python\nprint('hi')", true},
+		{"closed code block", "```python\nprint('hi')\n```", false},
+		{"inline backticks", "Use ```code``` here", false},
+		{"list item", long + "Options:\n- first\n- second", false},
+		{"heading", long + "## Summary", false},
+		{"empty", "  ", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksTruncated(tt.text); got != tt.want {
+				t.Errorf("looksTruncated(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
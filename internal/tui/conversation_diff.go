@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/render"
+)
+
+// handleDiffCommand handles the /diff <convIdA> <convIdB> command, which
+// compares two saved conversations in a scrollable overlay.
+func (m Model) handleDiffCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	ids := strings.Fields(args)
+	if len(ids) != 2 {
+		m.err = fmt.Errorf("usage: /diff <convIdA> <convIdB>")
+		return m, nil
+	}
+	if m.fullHistoryStore == nil {
+		m.err = m.historyUnavailable()
+		return m, nil
+	}
+
+	a, err := m.fullHistoryStore.GetConversation(ids[0])
+	if err != nil {
+		m.err = fmt.Errorf("diff: %w", err)
+		return m, nil
+	}
+	b, err := m.fullHistoryStore.GetConversation(ids[1])
+	if err != nil {
+		m.err = fmt.Errorf("diff: %w", err)
+		return m, nil
+	}
+
+	diff := history.DiffConversations(a, b)
+	width, height := m.overlayViewportSize()
+
+	m.diffViewport = viewport.New(width, height)
+	m.diffViewport.SetContent(renderConversationDiff(diff, width))
+	m.diffTitle = fmt.Sprintf("Diff: %s ↔ %s", a.Title, b.Title)
+	m.showingDiff = true
+	m.err = nil
+	return m, nil
+}
+
+// renderConversationDiff renders a summary of changed messages followed by
+// the colored unified diff
+func renderConversationDiff(diff *history.ConversationDiff, width int) string {
+	if !diff.HasChanges() {
+		return hintStyle.Render("Conversations have identical messages.")
+	}
+
+	var sb strings.Builder
+	for _, msg := range diff.Messages {
+		switch msg.Kind {
+		case history.MessageChanged:
+			sb.WriteString(diffChangeStyle.Render(fmt.Sprintf("~ message %d → %d (%s) changed", msg.IndexA+1, msg.IndexB+1, msg.Role)))
+		case history.MessageRemoved:
+			sb.WriteString(diffRemoveStyle.Render(fmt.Sprintf("- message %d (%s) removed", msg.IndexA+1, msg.Role)))
+		case history.MessageAdded:
+			sb.WriteString(diffAddStyle.Render(fmt.Sprintf("+ message %d (%s) added", msg.IndexB+1, msg.Role)))
+		default:
+			continue
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(render.Diff(diff.Unified, width))
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// updateDiffView handles input while the conversation diff overlay is shown
+func (m Model) updateDiffView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.diffViewport.Width, m.diffViewport.Height = m.overlayViewportSize()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.handleInterrupt()
+		case "esc", "q":
+			m.showingDiff = false
+			m.diffViewport = viewport.Model{}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.diffViewport, cmd = m.diffViewport.Update(msg)
+	return m, cmd
+}
+
+// renderDiffView renders the conversation diff overlay
+func (m Model) renderDiffView() string {
+	var content strings.Builder
+
+	content.WriteString(titleStyle.Render(m.diffTitle))
+	content.WriteString("\n\n")
+	content.WriteString(m.diffViewport.View())
+	content.WriteString("\n\n")
+	content.WriteString(hintStyle.Render(fmt.Sprintf("↑/↓/pgup/pgdn: scroll • esc/q: close • %3.f%%", m.diffViewport.ScrollPercent()*100)))
+
+	panel := messagesAreaStyle.Width(m.diffViewport.Width + 4).Render(content.String())
+	if m.width > 0 && m.height > 0 {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
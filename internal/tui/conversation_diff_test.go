@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/render"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+func TestDiffCommand(t *testing.T) {
+	store, err := history.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	convA, _ := store.CreateConversation("gemini-2.5-flash")
+	convB, _ := store.CreateConversation("gemini-2.5-flash")
+	for _, id := range []string{convA.ID, convB.ID} {
+		_ = store.AddMessage(id, "user", "Summarize the report", "")
+	}
+	_ = store.AddMessage(convA.ID, "assistant", "Revenue grew 10%", "")
+	_ = store.AddMessage(convB.ID, "assistant", "Revenue grew 12%", "")
+
+	newModel := func() Model {
+		return Model{
+			ready:            true,
+			textarea:         textarea.New(),
+			viewport:         viewport.New(80, 20),
+			fullHistoryStore: store,
+			width:            120,
+			height:           40,
+		}
+	}
+
+	t.Run("shows changed messages and unified diff", func(t *testing.T) {
+		updated, _ := newModel().handleDiffCommand(convA.ID + " " + convB.ID)
+		m := updated.(Model)
+		if !m.showingDiff {
+			t.Fatalf("expected diff overlay, err = %v", m.err)
+		}
+
+		view := m.View()
+		for _, want := range []string{"message 2 → 2 (assistant) changed", "-Revenue grew 10%", "+Revenue grew 12%"} {
+			if !strings.Contains(view, want) {
+				t.Errorf("overlay missing %q", want)
+			}
+		}
+
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if updated.(Model).showingDiff {
+			t.Error("esc should close the overlay")
+		}
+	})
+
+	t.Run("missing conversation", func(t *testing.T) {
+		updated, _ := newModel().handleDiffCommand(convA.ID + " conv-missing")
+		m := updated.(Model)
+		if m.showingDiff || m.err == nil || !strings.Contains(m.err.Error(), "not found") {
+			t.Errorf("expected not found error, got %v", m.err)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		updated, _ := newModel().handleDiffCommand(convA.ID)
+		if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "usage: /diff") {
+			t.Errorf("expected usage error, got %v", err)
+		}
+	})
+
+	t.Run("history not available", func(t *testing.T) {
+		m := newModel()
+		m.fullHistoryStore = nil
+		updated, _ := m.handleDiffCommand("a b")
+		if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "history not available") {
+			t.Errorf("expected error, got %v", err)
+		}
+	})
+}
+
+// diffPreviewTool summarizes its confirmation with a unified diff
+type diffPreviewTool struct {
+	recordingTool
+	summary string
+}
+
+func (t *diffPreviewTool) ConfirmationSummary(args map[string]any) string {
+	return t.summary
+}
+
+func TestToolConfirmation_RendersDiffSummary(t *testing.T) {
+	summary := "--- a/notes.txt\n+++ b/notes.txt\n@@ -1 +1 @@\n-old line\n+new line"
+	registry := toolexec.NewRegistry()
+	var executed []string
+	if err := registry.Register(&diffPreviewTool{recordingTool{name: "write_file", executed: &executed}, summary}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	m := Model{
+		ready:           true,
+		width:           100,
+		height:          40,
+		toolRegistry:    registry,
+		confirmingTool:  true,
+		toolConfirmCall: &toolexec.ToolCall{Name: "write_file"},
+	}
+
+	view := m.View()
+	// Tests run without a terminal, so the diff is laid out but not colored
+	for _, line := range strings.Split(render.Diff(summary, m.width-10), "\n") {
+		if !strings.Contains(view, line) {
+			t.Errorf("confirmation is missing rendered diff line %q:\n%s", line, view)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// charsPerToken is the average number of characters in a token
+const charsPerToken = 4
+
+// estimateTokens approximates the tokens in text at about four characters
+// per token, which is close enough for budgeting without the model's
+// vocabulary
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// replyTokens estimates the tokens of a reply, thoughts included since
+// they are generated output too
+func replyTokens(msg chatMessage) int {
+	return estimateTokens(msg.content) + estimateTokens(msg.thoughts)
+}
+
+// costNote returns the annotation shown under a reply when /cost is on,
+// e.g. "~120 tokens · ~$0.0024"
+func (m Model) costNote(msg chatMessage) string {
+	tokens := replyTokens(msg)
+	note := fmt.Sprintf("~%d tokens", tokens)
+	if m.costPer1KTokens > 0 {
+		note += fmt.Sprintf(" · ~$%.4f", float64(tokens)*m.costPer1KTokens/1000)
+	}
+	return note
+}
+
+// handleCostCommand handles the /cost [on|off] command, which toggles the
+// token and cost estimate shown under each reply
+func (m Model) handleCostCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		m.showCost = !m.showCost
+	case "on":
+		m.showCost = true
+	case "off":
+		m.showCost = false
+	default:
+		m.err = fmt.Errorf("usage: /cost [on|off]")
+		return m, nil
+	}
+
+	m.updateViewport()
+	if m.showCost {
+		m.err = fmt.Errorf("✓ Reply token estimates shown")
+	} else {
+		m.err = fmt.Errorf("✓ Reply token estimates hidden")
+	}
+	return m, nil
+}
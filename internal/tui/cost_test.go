@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"abcd", 1},
+		{"hello world!", 3},
+		{"héllo", 2},
+	}
+	for _, tt := range tests {
+		if got := estimateTokens(tt.text); got != tt.want {
+			t.Errorf("estimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCostNote(t *testing.T) {
+	msg := chatMessage{role: "assistant", content: "hello world!", thoughts: "hmm!"}
+
+	if got := (Model{}).costNote(msg); got != "~4 tokens" {
+		t.Errorf("costNote() = %q, want ~4 tokens", got)
+	}
+	if got := (Model{costPer1KTokens: 2}).costNote(msg); got != "~4 tokens · ~$0.0080" {
+		t.Errorf("costNote() with cost = %q", got)
+	}
+}
+
+func TestUpdateViewportCost(t *testing.T) {
+	newModel := func(show bool) Model {
+		return Model{
+			ready:           true,
+			textarea:        textarea.New(),
+			viewport:        viewport.New(96, 30),
+			showCost:        show,
+			costPer1KTokens: 1.5,
+			messages: []chatMessage{
+				{role: "user", content: "how are you today"},
+				{role: "assistant", content: "fine thanks"},
+				{role: "assistant", content: "one two three four five six seven eight"},
+			},
+		}
+	}
+
+	m := newModel(true)
+	m.updateViewport()
+	content := m.viewport.View()
+	for _, want := range []string{"~3 tokens · ~$0.0045", "~10 tokens · ~$0.0150"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("viewport should contain %q:\n%s", want, content)
+		}
+	}
+	if strings.Count(content, "tokens") != 2 {
+		t.Errorf("only assistant messages should be annotated:\n%s", content)
+	}
+
+	m = newModel(false)
+	m.updateViewport()
+	if strings.Contains(m.viewport.View(), "tokens") {
+		t.Error("viewport should not show estimates when /cost is off")
+	}
+}
+
+func TestCostCommand(t *testing.T) {
+	m := Model{textarea: textarea.New(), viewport: viewport.New(80, 20)}
+
+	updated, _ := m.handleCostCommand("")
+	m = updated.(Model)
+	if !m.showCost {
+		t.Error("/cost should toggle estimates on")
+	}
+
+	updated, _ = m.handleCostCommand("off")
+	m = updated.(Model)
+	if m.showCost {
+		t.Error("/cost off should hide estimates")
+	}
+
+	updated, _ = m.handleCostCommand("maybe")
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.err.Error(), "usage") {
+		t.Errorf("expected usage error, got %v", m.err)
+	}
+}
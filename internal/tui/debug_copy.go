@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// debugInfo assembles the identifiers of the current conversation for a
+// support or debugging report. Nothing is masked: the CID/RID/RCID let
+// anyone holding the account's cookies resume the conversation.
+func (m Model) debugInfo() string {
+	orNone := func(s string) string {
+		if s == "" {
+			return "(none)"
+		}
+		return s
+	}
+
+	conversationID, title := "", ""
+	if m.conversation != nil {
+		conversationID, title = m.conversation.ID, m.conversation.Title
+	}
+
+	model := m.modelName
+	var cid, rid, rcid, gemID string
+	if m.session != nil {
+		if name := m.session.GetModel().Name; name != "" {
+			model = name
+		}
+		cid, rid, rcid = m.session.CID(), m.session.RID(), m.session.RCID()
+		gemID = m.session.GetGemID()
+	}
+
+	gem := orNone(m.activeGemName)
+	if gemID != "" {
+		gem += " (" + gemID + ")"
+	}
+
+	lines := []string{
+		"geminiweb debug info (sensitive: can be used to resume this conversation)",
+		"conversation: " + orNone(conversationID),
+		"title: " + orNone(title),
+		"cid: " + orNone(cid),
+		"rid: " + orNone(rid),
+		"rcid: " + orNone(rcid),
+		"model: " + orNone(model),
+		"gem: " + gem,
+		fmt.Sprintf("messages: %d", len(m.messages)),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleDebugCopyCommand handles the /debug-copy command, which copies the
+// conversation and session identifiers to the clipboard
+func (m Model) handleDebugCopyCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /debug-copy")
+		return m, nil
+	}
+
+	if err := writeClipboard(m.debugInfo()); err != nil {
+		m.err = clipboardError(err)
+		return m, nil
+	}
+	m.err = fmt.Errorf("✓ Copied debug info to clipboard (contains conversation IDs; share with care)")
+	return m, nil
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+
+	"github.com/diogo/geminiweb/internal/clipboard"
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+func TestDebugInfo(t *testing.T) {
+	t.Run("includes conversation and session identifiers", func(t *testing.T) {
+		m := Model{
+			conversation: &history.Conversation{ID: "conv-42", Title: "Build failure"},
+			session: &mockChatSessionWithMetadata{
+				mockChatSession: mockChatSession{gemID: "gem-7"},
+				cid:             "c_abc",
+				rid:             "r_def",
+				rcid:            "rc_ghi",
+			},
+			activeGemName: "Code Reviewer",
+			messages:      []chatMessage{{role: "user", content: "hi"}, {role: "assistant", content: "hello"}},
+		}
+
+		got := m.debugInfo()
+		for _, want := range []string{
+			"sensitive",
+			"conversation: conv-42",
+			"title: Build failure",
+			"cid: c_abc",
+			"rid: r_def",
+			"rcid: rc_ghi",
+			"model: " + models.Model25Flash.Name,
+			"gem: Code Reviewer (gem-7)",
+			"messages: 2",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("debug info missing %q:\n%s", want, got)
+			}
+		}
+	})
+
+	t.Run("handles a missing conversation and session", func(t *testing.T) {
+		m := Model{modelName: "gemini-2.5-pro"}
+
+		got := m.debugInfo()
+		for _, want := range []string{
+			"conversation: (none)",
+			"cid: (none)",
+			"rid: (none)",
+			"rcid: (none)",
+			"model: gemini-2.5-pro",
+			"gem: (none)",
+			"messages: 0",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("debug info missing %q:\n%s", want, got)
+			}
+		}
+	})
+}
+
+func TestDebugCopyCommand(t *testing.T) {
+	var copied string
+	orig := writeClipboard
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+	t.Cleanup(func() { writeClipboard = orig })
+
+	m := Model{conversation: &history.Conversation{ID: "conv-42"}, textarea: textarea.New()}
+	updated, _ := m.handleDebugCopyCommand("")
+	m = updated.(Model)
+
+	if !strings.Contains(copied, "conversation: conv-42") {
+		t.Errorf("copied text = %q, want the conversation ID", copied)
+	}
+	if m.err == nil || !strings.HasPrefix(m.err.Error(), "✓") || !strings.Contains(m.err.Error(), "share with care") {
+		t.Errorf("status = %v, want a success note about sensitivity", m.err)
+	}
+
+	t.Run("reports a missing clipboard", func(t *testing.T) {
+		writeClipboard = func(string) error { return clipboard.ErrUnavailable }
+		updated, _ := Model{textarea: textarea.New()}.handleDebugCopyCommand("")
+		if err := updated.(Model).err; err == nil || err.Error() != "clipboard not available" {
+			t.Errorf("status = %v, want clipboard not available", err)
+		}
+	})
+
+	t.Run("rejects arguments", func(t *testing.T) {
+		copied = ""
+		updated, _ := Model{textarea: textarea.New()}.handleDebugCopyCommand("now")
+		if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "usage") {
+			t.Errorf("status = %v, want usage", err)
+		}
+		if copied != "" {
+			t.Error("nothing should be copied on a usage error")
+		}
+	})
+}
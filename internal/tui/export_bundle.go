@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// Layout of a session bundle directory
+const (
+	bundleTranscriptName = "transcript.md"
+	bundleManifestName   = "manifest.json"
+	bundleAttachmentsDir = "attachments"
+	bundleImagesDir      = "images"
+)
+
+// Bundle item types recorded in the manifest
+const (
+	bundleItemTranscript = "transcript"
+	bundleItemAttachment = "attachment"
+	bundleItemImage      = "image"
+)
+
+// bundleItem is one file in a session bundle. Path is relative to the
+// bundle directory; Error is set instead when the item could not be written.
+type bundleItem struct {
+	Type   string `json:"type"`
+	Path   string `json:"path,omitempty"`
+	Source string `json:"source,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// bundleManifest describes the contents of a session bundle (manifest.json)
+type bundleManifest struct {
+	Title     string       `json:"title"`
+	CreatedAt time.Time    `json:"created_at"`
+	Items     []bundleItem `json:"items"`
+}
+
+// bundleResultMsg is sent when a session bundle has been written
+type bundleResultMsg struct {
+	dir     string
+	written int
+	failed  int
+	err     error
+}
+
+// handleBundleCommand handles the /bundle [dir] command, which archives the
+// session as a directory holding the markdown transcript, copies of the
+// attachments sent, the response images and a manifest.json.
+func (m Model) handleBundleCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if len(m.messages) == 0 {
+		m.err = fmt.Errorf("no conversation to bundle")
+		return m, nil
+	}
+
+	title := "Conversation"
+	if m.conversation != nil && m.conversation.Title != "" {
+		title = m.conversation.Title
+	}
+
+	dir := strings.TrimSpace(args)
+	if dir == "" {
+		if m.conversation != nil && m.conversation.Title != "" {
+			dir = sanitizeFilename(m.conversation.Title) + "_bundle"
+		} else {
+			dir = fmt.Sprintf("conversation_%s_bundle", time.Now().Format("20060102_150405"))
+		}
+	}
+
+	absDir, err := validateExportPath(dir)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.err = nil
+	return m, exportBundle(m.client, m.messages, m.sentAttachments, title, absDir)
+}
+
+// exportBundle creates a tea.Cmd that writes a session bundle to dir.
+// Each transcript, attachment and image is written independently; failures
+// are recorded in the manifest and do not abort the rest of the bundle.
+func exportBundle(client api.GeminiClientInterface, messages []chatMessage, attachments []*api.UploadedFile, title, dir string) tea.Cmd {
+	return func() tea.Msg {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return bundleResultMsg{err: fmt.Errorf("bundle failed: %w", err)}
+		}
+
+		manifest := bundleManifest{Title: title, CreatedAt: time.Now()}
+
+		// Transcript
+		item := bundleItem{Type: bundleItemTranscript, Path: bundleTranscriptName}
+		result := exportFromMemory(messages, title, "markdown", filepath.Join(dir, bundleTranscriptName), "")()
+		if res, ok := result.(exportResultMsg); ok && res.err != nil {
+			item.Error = res.err.Error()
+		}
+		manifest.Items = append(manifest.Items, item)
+
+		// Attachments
+		for _, file := range attachments {
+			manifest.Items = append(manifest.Items, bundleAttachment(file, dir))
+		}
+
+		// Images, downloaded one at a time so each failure is recorded
+		for _, msg := range messages {
+			for _, img := range msg.images {
+				manifest.Items = append(manifest.Items, bundleImage(client, img, dir))
+			}
+		}
+
+		data, err := jsonMarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return bundleResultMsg{err: fmt.Errorf("manifest marshal failed: %w", err)}
+		}
+		if err := os.WriteFile(filepath.Join(dir, bundleManifestName), data, 0o644); err != nil {
+			return bundleResultMsg{err: fmt.Errorf("manifest write failed: %w", err)}
+		}
+
+		res := bundleResultMsg{dir: dir}
+		for _, item := range manifest.Items {
+			if item.Error != "" {
+				res.failed++
+			} else {
+				res.written++
+			}
+		}
+		return res
+	}
+}
+
+// bundleAttachment copies a sent attachment into the bundle
+func bundleAttachment(file *api.UploadedFile, dir string) bundleItem {
+	item := bundleItem{Type: bundleItemAttachment, Source: file.LocalPath}
+	if file.LocalPath == "" {
+		item.Source = file.FileName
+		item.Error = "no local copy of attachment"
+		return item
+	}
+
+	item.Path = filepath.Join(bundleAttachmentsDir, filepath.Base(file.LocalPath))
+	if err := copyFile(file.LocalPath, filepath.Join(dir, item.Path)); err != nil {
+		item.Error = err.Error()
+	}
+	return item
+}
+
+// bundleImage downloads a response image into the bundle
+func bundleImage(client api.GeminiClientInterface, img models.WebImage, dir string) bundleItem {
+	item := bundleItem{Type: bundleItemImage, Source: img.URL}
+	if client == nil {
+		item.Error = "client not available"
+		return item
+	}
+
+	output := &models.ModelOutput{Candidates: []models.Candidate{{WebImages: []models.WebImage{img}}}}
+	paths, err := client.DownloadAllImages(output, api.ImageDownloadOptions{
+		Directory: filepath.Join(dir, bundleImagesDir),
+	})
+	switch {
+	case err != nil:
+		item.Error = err.Error()
+	case len(paths) == 0:
+		item.Error = "image was not downloaded"
+	default:
+		item.Path = paths[0]
+		if rel, err := filepath.Rel(dir, paths[0]); err == nil {
+			item.Path = rel
+		}
+	}
+	return item
+}
+
+// copyFile copies src to dst, creating dst's parent directory. A copy
+// that fails part way, including when dst cannot be flushed on close, is
+// removed rather than left truncated.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// bundleDownloadClient downloads images by writing a placeholder file,
+// failing for URLs listed in fail
+type bundleDownloadClient struct {
+	*api.MockGeminiClient
+	fail map[string]bool
+}
+
+func (c *bundleDownloadClient) DownloadAllImages(output *models.ModelOutput, opts api.ImageDownloadOptions) ([]string, error) {
+	var paths []string
+	for _, img := range output.Images() {
+		if c.fail[img.URL] {
+			return nil, errors.New("download failed: 403")
+		}
+		if err := os.MkdirAll(opts.Directory, 0o755); err != nil {
+			return nil, err
+		}
+		path := filepath.Join(opts.Directory, filepath.Base(img.URL)+".png")
+		if err := os.WriteFile(path, []byte("png"), 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func newBundleTestModel(client api.GeminiClientInterface) Model {
+	return newTestModel(withMessages(
+		chatMessage{role: "user", content: "draw two cats"},
+		chatMessage{role: "assistant", content: "Here they are", images: []models.WebImage{
+			{URL: "https://example.com/cat1", Title: "cat 1"},
+			{URL: "https://example.com/cat2", Title: "cat 2"},
+		}},
+		chatMessage{role: "user", content: "and a dog"},
+		chatMessage{role: "assistant", content: "Here it is", images: []models.WebImage{
+			{URL: "https://example.com/dog", Title: "dog"},
+		}},
+	), func(m *Model) { m.client = client })
+}
+
+func runBundleCommand(t *testing.T, m Model, dir string) (bundleResultMsg, bundleManifest) {
+	t.Helper()
+	_, cmd := m.handleBundleCommand(dir)
+	if cmd == nil {
+		t.Fatal("expected a bundle command")
+	}
+	result, ok := cmd().(bundleResultMsg)
+	if !ok {
+		t.Fatalf("expected bundleResultMsg, got %T", cmd())
+	}
+	if result.err != nil {
+		t.Fatalf("bundle failed: %v", result.err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, bundleManifestName))
+	if err != nil {
+		t.Fatalf("reading manifest: %v", err)
+	}
+	var manifest bundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("parsing manifest: %v", err)
+	}
+	return result, manifest
+}
+
+func itemsOfType(manifest bundleManifest, typ string) []bundleItem {
+	var items []bundleItem
+	for _, item := range manifest.Items {
+		if item.Type == typ {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func TestHandleBundleCommand(t *testing.T) {
+	t.Run("manifest lists transcript, attachments and images", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		attachment := filepath.Join(tmpDir, "notes.txt")
+		if err := os.WriteFile(attachment, []byte("notes"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		dir := filepath.Join(tmpDir, "bundle")
+
+		m := newBundleTestModel(&bundleDownloadClient{MockGeminiClient: &api.MockGeminiClient{}})
+		m.sentAttachments = []*api.UploadedFile{{FileName: "notes.txt", LocalPath: attachment}}
+		result, manifest := runBundleCommand(t, m, dir)
+
+		if result.written != 5 || result.failed != 0 {
+			t.Errorf("written=%d failed=%d, want 5 and 0", result.written, result.failed)
+		}
+
+		transcripts := itemsOfType(manifest, bundleItemTranscript)
+		if len(transcripts) != 1 || transcripts[0].Path != bundleTranscriptName || transcripts[0].Error != "" {
+			t.Fatalf("unexpected transcript items: %+v", transcripts)
+		}
+		transcript, err := os.ReadFile(filepath.Join(dir, bundleTranscriptName))
+		if err != nil || !strings.Contains(string(transcript), "draw two cats") {
+			t.Errorf("transcript not written correctly: %v", err)
+		}
+
+		attachments := itemsOfType(manifest, bundleItemAttachment)
+		if len(attachments) != 1 || attachments[0].Error != "" {
+			t.Fatalf("unexpected attachment items: %+v", attachments)
+		}
+		if data, err := os.ReadFile(filepath.Join(dir, attachments[0].Path)); err != nil || string(data) != "notes" {
+			t.Errorf("attachment not copied: %v", err)
+		}
+
+		images := itemsOfType(manifest, bundleItemImage)
+		if len(images) != 3 {
+			t.Fatalf("manifest lists %d images, want 3", len(images))
+		}
+		for _, img := range images {
+			if img.Error != "" || !strings.HasPrefix(img.Path, bundleImagesDir+string(filepath.Separator)) {
+				t.Errorf("unexpected image item: %+v", img)
+			}
+			if _, err := os.Stat(filepath.Join(dir, img.Path)); err != nil {
+				t.Errorf("image %s missing: %v", img.Path, err)
+			}
+		}
+	})
+
+	t.Run("download failure is recorded without aborting", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "bundle")
+		client := &bundleDownloadClient{
+			MockGeminiClient: &api.MockGeminiClient{},
+			fail:             map[string]bool{"https://example.com/cat2": true},
+		}
+		m := newBundleTestModel(client)
+		m.sentAttachments = []*api.UploadedFile{{FileName: "pasted.txt"}}
+		result, manifest := runBundleCommand(t, m, dir)
+
+		if result.written != 3 || result.failed != 2 {
+			t.Errorf("written=%d failed=%d, want 3 and 2", result.written, result.failed)
+		}
+
+		images := itemsOfType(manifest, bundleItemImage)
+		if len(images) != 3 {
+			t.Fatalf("manifest lists %d images, want 3", len(images))
+		}
+		for _, img := range images {
+			failed := img.Source == "https://example.com/cat2"
+			if failed != (img.Error != "") {
+				t.Errorf("unexpected image item: %+v", img)
+			}
+		}
+
+		attachments := itemsOfType(manifest, bundleItemAttachment)
+		if len(attachments) != 1 || attachments[0].Error == "" {
+			t.Errorf("expected attachment without local copy to be recorded as failed: %+v", attachments)
+		}
+	})
+
+	t.Run("feedback summarizes failures", func(t *testing.T) {
+		m := newBundleTestModel(nil)
+		updated, _ := m.Update(bundleResultMsg{dir: "/tmp/b", written: 2, failed: 1})
+		got := updated.(Model).err
+		if got == nil || !strings.Contains(got.Error(), "✓ Bundle written to /tmp/b") || !strings.Contains(got.Error(), "1 failed") {
+			t.Errorf("unexpected feedback: %v", got)
+		}
+	})
+
+	t.Run("no conversation", func(t *testing.T) {
+		m := Model{textarea: textarea.New()}
+		updated, cmd := m.handleBundleCommand("")
+		if cmd != nil {
+			t.Error("expected no command without messages")
+		}
+		if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "no conversation") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestCopyFile_RemovesFailedCopy(t *testing.T) {
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "out", "copy.bin")
+
+	// Reading a directory fails after the destination has been created
+	if err := copyFile(dir, dst); err == nil {
+		t.Fatal("copyFile() of a directory should fail")
+	}
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		t.Errorf("failed copy should be removed, stat error = %v", err)
+	}
+
+	src := filepath.Join(dir, "src.txt")
+	if err := os.WriteFile(src, []byte("data"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := copyFile(src, dst); err != nil {
+		t.Fatalf("copyFile() error = %v", err)
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "data" {
+		t.Errorf("copied %q, want %q", data, "data")
+	}
+}
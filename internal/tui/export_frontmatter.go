@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// frontMatterTag is always included in the tags of exported front matter
+const frontMatterTag = "geminiweb"
+
+// exportFrontMatter is the YAML front matter prepended by /export -m, so the
+// markdown drops straight into static-site generators and gists
+type exportFrontMatter struct {
+	title string
+	model string
+	date  time.Time
+	tags  []string
+}
+
+// exportFrontMatter returns the front matter describing the current
+// conversation under the given title
+func (m Model) exportFrontMatter(title string) exportFrontMatter {
+	fm := exportFrontMatter{
+		title: title,
+		model: m.modelName,
+		date:  time.Now(),
+		tags:  []string{frontMatterTag},
+	}
+	if m.conversation != nil {
+		if m.conversation.Model != "" {
+			fm.model = m.conversation.Model
+		}
+		if !m.conversation.CreatedAt.IsZero() {
+			fm.date = m.conversation.CreatedAt
+		}
+	}
+	if m.activeGemName != "" {
+		fm.tags = append(fm.tags, m.activeGemName)
+	}
+	return fm
+}
+
+// render returns the front matter as a "---" delimited YAML block. Strings
+// are written as double-quoted scalars, whose escapes YAML shares with Go,
+// so titles containing quotes, colons or newlines stay valid.
+func (fm exportFrontMatter) render() string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	b.WriteString("title: " + strconv.Quote(fm.title) + "\n")
+	b.WriteString("model: " + strconv.Quote(fm.model) + "\n")
+	b.WriteString("date: " + fm.date.Format(time.RFC3339) + "\n")
+
+	tags := make([]string, len(fm.tags))
+	for i, tag := range fm.tags {
+		tags[i] = strconv.Quote(tag)
+	}
+	b.WriteString("tags: [" + strings.Join(tags, ", ") + "]\n")
+	b.WriteString("---\n\n")
+	return b.String()
+}
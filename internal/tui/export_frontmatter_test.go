@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+// parseFrontMatter splits a document into its front matter fields and body,
+// failing the test if the block is not well-formed
+func parseFrontMatter(t *testing.T, doc string) (map[string]string, string) {
+	t.Helper()
+	if !strings.HasPrefix(doc, "---\n") {
+		t.Fatalf("document does not start with front matter:\n%s", doc)
+	}
+	end := strings.Index(doc[4:], "\n---\n")
+	if end < 0 {
+		t.Fatalf("front matter is not closed:\n%s", doc)
+	}
+
+	fields := make(map[string]string)
+	for _, line := range strings.Split(doc[4:4+end], "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok || key == "" || strings.ContainsAny(key, " \"") {
+			t.Fatalf("invalid front matter line %q", line)
+		}
+		fields[key] = value
+	}
+	return fields, doc[4+end+5:]
+}
+
+func TestExportFrontMatter_Render(t *testing.T) {
+	fm := exportFrontMatter{
+		title: "Re: \"quotes\" & colons: a\nnewline \\ slash",
+		model: "pro",
+		date:  time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC),
+		tags:  []string{"geminiweb", "Code Helper"},
+	}
+
+	fields, body := parseFrontMatter(t, fm.render())
+	title, err := strconv.Unquote(fields["title"])
+	if err != nil || title != fm.title {
+		t.Errorf("title = %s, does not round-trip (%v)", fields["title"], err)
+	}
+	if strings.Contains(fields["title"], "\n") {
+		t.Error("title must stay on one line")
+	}
+	if fields["model"] != `"pro"` {
+		t.Errorf("model = %s", fields["model"])
+	}
+	if fields["date"] != "2024-05-06T07:08:09Z" {
+		t.Errorf("date = %s", fields["date"])
+	}
+	if fields["tags"] != `["geminiweb", "Code Helper"]` {
+		t.Errorf("tags = %s", fields["tags"])
+	}
+	if body != "\n" {
+		t.Errorf("front matter should end with a blank line, got %q", body)
+	}
+}
+
+func TestModel_ExportFrontMatter(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := Model{
+		modelName:     "fast",
+		activeGemName: "Coder",
+		conversation:  &history.Conversation{Title: "Chat", Model: "pro", CreatedAt: created},
+	}
+
+	fm := m.exportFrontMatter("Chat")
+	if fm.title != "Chat" || fm.model != "pro" || !fm.date.Equal(created) {
+		t.Errorf("unexpected front matter: %+v", fm)
+	}
+	if len(fm.tags) != 2 || fm.tags[0] != frontMatterTag || fm.tags[1] != "Coder" {
+		t.Errorf("tags = %v", fm.tags)
+	}
+
+	m.conversation = nil
+	if fm := m.exportFrontMatter("Conversation"); fm.model != "fast" || fm.date.IsZero() {
+		t.Errorf("unsaved conversation should use the current model and time: %+v", fm)
+	}
+}
+
+func TestExport_WithFrontMatter(t *testing.T) {
+	messages := []chatMessage{
+		{role: "user", content: "Hello"},
+		{role: "assistant", content: "Hi there!"},
+	}
+	m := Model{modelName: "fast"}
+	frontMatter := m.exportFrontMatter(`My "chat"`).render()
+
+	t.Run("from memory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "chat.md")
+		result := exportFromMemory(messages, `My "chat"`, "markdown", path, frontMatter)().(exportResultMsg)
+		if result.err != nil {
+			t.Fatalf("unexpected error: %v", result.err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fields, body := parseFrontMatter(t, string(data))
+		if fields["title"] != `"My \"chat\""` {
+			t.Errorf("title = %s", fields["title"])
+		}
+		if !strings.HasPrefix(body, "\n# My \"chat\"\n") || !strings.Contains(body, "Hi there!") {
+			t.Errorf("content should follow the front matter, got:\n%s", body)
+		}
+		if result.size != int64(len(data)) {
+			t.Errorf("size = %d, want %d", result.size, len(data))
+		}
+	})
+
+	t.Run("from store", func(t *testing.T) {
+		store := &mockFullHistoryStoreWithExport{
+			ExportToMarkdownFunc: func(id string) (string, error) {
+				return "# Stored\n\nHello\n", nil
+			},
+		}
+		path := filepath.Join(t.TempDir(), "chat.md")
+		result := exportCommand(store, "conv-1", "markdown", path, frontMatter)().(exportResultMsg)
+		if result.err != nil {
+			t.Fatalf("unexpected error: %v", result.err)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, body := parseFrontMatter(t, string(data))
+		if body != "\n# Stored\n\nHello\n" {
+			t.Errorf("body = %q", body)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Heights of the chat panels around the viewport
+const (
+	headerHeight  = 4 // Header panel with border
+	inputHeight   = 7 // Input panel with border (includes multi-line textarea)
+	statusHeight  = 1 // Status bar
+	layoutPadding = 2 // Extra spacing
+
+	minViewportHeight = 5
+)
+
+// viewportHeight returns the height left for the viewport in the current
+// terminal. Focus mode hands the header and status bar rows to it.
+func (m Model) viewportHeight() int {
+	height := m.height - inputHeight - layoutPadding
+	if !m.focusMode {
+		height -= headerHeight + statusHeight
+	}
+	if height < minViewportHeight {
+		height = minViewportHeight
+	}
+	return height
+}
+
+// toggleFocusMode switches focus mode and resizes the viewport to match.
+func (m Model) toggleFocusMode() Model {
+	m.focusMode = !m.focusMode
+	if m.ready {
+		m.viewport.Height = m.viewportHeight()
+		m.updateViewport()
+	}
+	if m.focusMode {
+		m.err = fmt.Errorf("✓ Focus mode on (Ctrl+F or /focus to exit)")
+	} else {
+		m.err = fmt.Errorf("✓ Focus mode off")
+	}
+	return m
+}
+
+// handleFocusCommand handles the /focus command, which toggles focus mode.
+func (m Model) handleFocusCommand() (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+	return m.toggleFocusMode(), nil
+}
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newFocusTestModel(t *testing.T) Model {
+	t.Helper()
+	m := newTestModel(func(m *Model) { m.modelName = "fast" })
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+	m = updated.(Model)
+	m.messages = []chatMessage{{role: "user", content: "hello"}}
+	return m
+}
+
+func TestFocusMode_ViewportHeight(t *testing.T) {
+	m := newFocusTestModel(t)
+	normal := m.viewport.Height
+
+	m = m.toggleFocusMode()
+	if !m.focusMode {
+		t.Fatal("focus mode should be on")
+	}
+	if got, want := m.viewport.Height, normal+headerHeight+statusHeight; got != want {
+		t.Errorf("viewport height in focus mode = %d, want %d", got, want)
+	}
+
+	// A resize keeps the focus mode layout
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+	if got, want := m.viewport.Height, 30-inputHeight-layoutPadding; got != want {
+		t.Errorf("viewport height after resize = %d, want %d", got, want)
+	}
+
+	m = m.toggleFocusMode()
+	if got, want := m.viewport.Height, 30-inputHeight-layoutPadding-headerHeight-statusHeight; got != want {
+		t.Errorf("viewport height after leaving focus mode = %d, want %d", got, want)
+	}
+}
+
+func TestFocusMode_HidesHeaderAndStatusBar(t *testing.T) {
+	m := newFocusTestModel(t)
+	m.err = nil
+
+	view := ansiPattern.ReplaceAllString(m.View(), "")
+	if !strings.Contains(view, "Gemini Chat") {
+		t.Fatalf("header should be rendered normally:\n%s", view)
+	}
+	const status = "^E Export"
+	if !strings.Contains(view, status) {
+		t.Fatalf("status bar should be rendered normally:\n%s", view)
+	}
+
+	m = m.toggleFocusMode()
+	m.err = nil
+	view = ansiPattern.ReplaceAllString(m.View(), "")
+	if strings.Contains(view, "Gemini Chat") {
+		t.Errorf("header should be hidden in focus mode:\n%s", view)
+	}
+	if strings.Contains(view, status) {
+		t.Errorf("status bar should be hidden in focus mode:\n%s", view)
+	}
+	if !strings.Contains(view, "hello") {
+		t.Errorf("messages should still be rendered:\n%s", view)
+	}
+}
+
+func TestFocusMode_Toggles(t *testing.T) {
+	m := newFocusTestModel(t)
+	m.textarea.SetValue("/focus")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.focusMode || m.textarea.Value() != "" {
+		t.Fatalf("/focus should enable focus mode and clear the input (focus=%v, input=%q)", m.focusMode, m.textarea.Value())
+	}
+
+	m.textarea.SetValue("draft")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlF})
+	m = updated.(Model)
+	if m.focusMode {
+		t.Error("ctrl+f should turn focus mode off")
+	}
+	if m.textarea.Value() != "draft" {
+		t.Errorf("ctrl+f should keep the input, got %q", m.textarea.Value())
+	}
+}
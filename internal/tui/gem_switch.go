@@ -0,0 +1,71 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// setActiveGem applies a gem to the session, remembering the gem it
+// replaces for /gem last. An empty gemID clears the gem.
+func (m *Model) setActiveGem(gemID, gemName string) {
+	currentID := ""
+	if m.session != nil {
+		currentID = m.session.GetGemID()
+	}
+	if currentID != gemID || m.activeGemName != gemName {
+		m.previousGemID = currentID
+		m.previousGemName = m.activeGemName
+		m.hasPreviousGem = true
+	}
+
+	if m.session != nil {
+		m.session.SetGem(gemID)
+	}
+	m.activeGemName = gemName
+}
+
+// handleGemCommand handles /gem and /gems, which open the gem selector,
+// and /gem last, which switches back to the previously active gem
+func (m Model) handleGemCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.EqualFold(strings.TrimSpace(args), "last") {
+		return m.restorePreviousGem()
+	}
+
+	m.selectingGem = true
+	m.gemsLoading = true
+	m.gemsCursor = 0
+	m.gemsFilter = ""
+	return m, m.loadGemsForChat()
+}
+
+// restorePreviousGem re-applies the gem that was active before the current
+// one (or clears the gem if there was none), so repeating it toggles
+// between the two
+func (m Model) restorePreviousGem() (tea.Model, tea.Cmd) {
+	if !m.hasPreviousGem {
+		m.err = fmt.Errorf("no previous gem - select one with /gems first")
+		return m, nil
+	}
+	if m.session == nil {
+		m.err = fmt.Errorf("session not available")
+		return m, nil
+	}
+
+	gemID, gemName := m.previousGemID, m.previousGemName
+	m.setActiveGem(gemID, gemName)
+	if err := m.saveGemToHistory(gemID, gemName); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	if gemID == "" {
+		m.err = fmt.Errorf("✓ Gem cleared")
+	} else {
+		m.err = fmt.Errorf("✓ Switched back to gem %s", gemName)
+	}
+	return m, nil
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// selectGem picks a gem in the selector like a user would
+func selectGem(t *testing.T, m Model, gem *models.Gem) Model {
+	t.Helper()
+	m.selectingGem = true
+	m.gemsList = []*models.Gem{gem}
+	m.gemsCursor = 0
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model)
+}
+
+func TestGemLast_RestoresPreviousGem(t *testing.T) {
+	session := &mockChatSession{}
+	m := newTestModel(withSession(session))
+
+	m = selectGem(t, m, &models.Gem{ID: "gem-a", Name: "Coder"})
+	m = selectGem(t, m, &models.Gem{ID: "gem-b", Name: "Writer"})
+	if session.gemID != "gem-b" || m.activeGemName != "Writer" {
+		t.Fatalf("active gem = %q (%s), want gem-b", session.gemID, m.activeGemName)
+	}
+
+	m = runCommand(m, "/gem last")
+	if session.gemID != "gem-a" || m.activeGemName != "Coder" {
+		t.Errorf("/gem last: active gem = %q (%s), want gem-a (Coder)", session.gemID, m.activeGemName)
+	}
+	if m.selectingGem {
+		t.Error("/gem last should not open the selector")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "Switched back to gem Coder") {
+		t.Errorf("feedback = %v", m.err)
+	}
+
+	// Repeating it toggles between the two
+	m = runCommand(m, "/gem last")
+	if session.gemID != "gem-b" || m.activeGemName != "Writer" {
+		t.Errorf("second /gem last: active gem = %q (%s), want gem-b", session.gemID, m.activeGemName)
+	}
+}
+
+func TestGemLast_RestoresNoGem(t *testing.T) {
+	session := &mockChatSession{}
+	m := newTestModel(withSession(session))
+
+	m = selectGem(t, m, &models.Gem{ID: "gem-a", Name: "Coder"})
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}, Alt: true})
+	m = updated.(Model)
+
+	if session.gemID != "" || m.activeGemName != "" {
+		t.Errorf("alt+g: active gem = %q (%s), want none", session.gemID, m.activeGemName)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "Gem cleared") {
+		t.Errorf("feedback = %v", m.err)
+	}
+}
+
+func TestGemLast_NothingRecorded(t *testing.T) {
+	session := &mockChatSession{gemID: "gem-a"}
+	m := newTestModel(withSession(session))
+	m.activeGemName = "Coder"
+
+	m = runCommand(m, "/gem last")
+	if session.gemID != "gem-a" {
+		t.Errorf("gem should be unchanged, got %q", session.gemID)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "no previous gem") {
+		t.Errorf("expected a no previous gem error, got %v", m.err)
+	}
+}
+
+func TestGemLast_SavesToHistory(t *testing.T) {
+	session := &mockChatSession{}
+	store := &mockHistoryStoreWithGem{}
+	m := newTestModel(withSession(session))
+	m.historyStore = store
+	m.conversation = &history.Conversation{ID: "conv-1"}
+
+	m = selectGem(t, m, &models.Gem{ID: "gem-a", Name: "Coder"})
+	m = selectGem(t, m, &models.Gem{ID: "gem-b", Name: "Writer"})
+	m = runCommand(m, "/gem last")
+
+	if m.conversation.GemID != "gem-a" {
+		t.Errorf("conversation gem = %q, want gem-a", m.conversation.GemID)
+	}
+	last := store.updateGemCalls[len(store.updateGemCalls)-1]
+	if last.gemID != "gem-a" || last.gemName != "Coder" {
+		t.Errorf("last UpdateGem call = %+v, want gem-a", last)
+	}
+}
+
+func TestGemLast_TracksConversationSwitch(t *testing.T) {
+	session := &mockChatSession{}
+	m := newTestModel(withSession(session))
+	m = selectGem(t, m, &models.Gem{ID: "gem-a", Name: "Coder"})
+
+	updated, _ := m.switchConversation(&history.Conversation{ID: "c1", GemID: "gem-b", GemName: "Writer"})
+	m = updated.(Model)
+	m = runCommand(m, "/gem last")
+
+	if session.gemID != "gem-a" {
+		t.Errorf("/gem last after switching conversations: gem = %q, want gem-a", session.gemID)
+	}
+}
+
+func TestGemCommand_OpensSelector(t *testing.T) {
+	m := newTestModel(withSession(&mockChatSession{}))
+	m = runCommand(m, "/gem")
+	if !m.selectingGem || !m.gemsLoading {
+		t.Error("/gem without arguments should open the selector")
+	}
+}
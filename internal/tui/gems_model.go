@@ -1,18 +1,19 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/clipboard"
 	"github.com/diogo/geminiweb/internal/models"
 	"github.com/diogo/geminiweb/internal/render"
 )
@@ -698,8 +699,10 @@ func (m GemsModel) copyIDToClipboard() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	err := clipboard.WriteAll(m.selectedGem.ID)
-	if err != nil {
+	err := clipboard.Copy(m.selectedGem.ID)
+	if errors.Is(err, clipboard.ErrUnavailable) {
+		m.feedback = "Clipboard not available"
+	} else if err != nil {
 		m.feedback = fmt.Sprintf("Failed to copy: %v", err)
 	} else {
 		m.feedback = fmt.Sprintf("Copied ID: %s", truncate(m.selectedGem.ID, 30))
@@ -782,13 +785,13 @@ func (m GemsModel) renderHeader(width int) string {
 	var title string
 	switch m.view {
 	case gemsViewCreate:
-		title = configTitleStyle.Render("✦ Create New Gem")
+		title = configTitleStyle.Render(icon("✦", "*") + " Create New Gem")
 	case gemsViewEdit:
-		title = configTitleStyle.Render("✦ Edit Gem")
+		title = configTitleStyle.Render(icon("✦", "*") + " Edit Gem")
 	case gemsViewDelete:
-		title = configTitleStyle.Render("✦ Delete Gem")
+		title = configTitleStyle.Render(icon("✦", "*") + " Delete Gem")
 	default:
-		title = configTitleStyle.Render("✦ Gemini Gems")
+		title = configTitleStyle.Render(icon("✦", "*") + " Gemini Gems")
 	}
 
 	subtitle := ""
@@ -806,14 +809,14 @@ func (m GemsModel) renderHeader(width int) string {
 
 // renderSearchBar renders the search input bar
 func (m GemsModel) renderSearchBar(width int) string {
-	searchLabel := inputLabelStyle.Render("🔍 ")
+	searchLabel := inputLabelStyle.Render(icon("🔍", "[search]") + " ")
 	searchContent := lipgloss.JoinHorizontal(lipgloss.Center, searchLabel, m.searchInput.View())
 	return inputPanelStyle.Width(width).Render(searchContent)
 }
 
 // renderListView renders the gems list view
 func (m GemsModel) renderListView(width int) string {
-	title := configSectionTitleStyle.Render("📦 Gems")
+	title := configSectionTitleStyle.Render(icon("📦", "[gem]") + " Gems")
 
 	if len(m.filteredGems) == 0 {
 		noGems := hintStyle.Render("No gems found. Press 'n' to create one.")
@@ -892,7 +895,7 @@ func (m GemsModel) renderDetailsView(width int) string {
 	}
 
 	gem := m.selectedGem
-	title := configSectionTitleStyle.Render("📋 Gem Details")
+	title := configSectionTitleStyle.Render(icon("📋", "[details]") + " Gem Details")
 
 	// Type indicator
 	gemType := configEnabledStyle.Render("custom")
@@ -914,7 +917,7 @@ func (m GemsModel) renderDetailsView(width int) string {
 	// Prompt (rendered as markdown if possible)
 	if gem.Prompt != "" {
 		details = append(details, "")
-		details = append(details, configSectionTitleStyle.Render("📝 Prompt"))
+		details = append(details, configSectionTitleStyle.Render(icon("📝", "[prompt]")+" Prompt"))
 
 		promptWidth := width - 8
 		if promptWidth < 40 {
@@ -937,7 +940,7 @@ func (m GemsModel) renderDetailsView(width int) string {
 
 // renderCreateView renders the create gem form
 func (m GemsModel) renderCreateView(width int) string {
-	return m.renderFormView(width, "✨ New Gem", "Fill in the details for your new gem:")
+	return m.renderFormView(width, icon("✨", "[new]")+" New Gem", "Fill in the details for your new gem:")
 }
 
 // renderEditView renders the edit gem form
@@ -946,7 +949,7 @@ func (m GemsModel) renderEditView(width int) string {
 	if m.selectedGem != nil {
 		gemName = m.selectedGem.Name
 	}
-	return m.renderFormView(width, "✏️  Edit Gem", fmt.Sprintf("Editing gem: %s", gemName))
+	return m.renderFormView(width, icon("✏️", "[edit]")+"  Edit Gem", fmt.Sprintf("Editing gem: %s", gemName))
 }
 
 // renderFormView renders the create/edit form
@@ -1006,7 +1009,7 @@ func (m GemsModel) renderDeleteView(width int) string {
 		return configPanelStyle.Width(width).Render("No gem selected")
 	}
 
-	title := configSectionTitleStyle.Render("⚠️  Confirm Deletion")
+	title := configSectionTitleStyle.Render(icon("⚠️", "[!]") + "  Confirm Deletion")
 	warning := errorStyle.Render("This action cannot be undone!")
 
 	var lines []string
@@ -1112,12 +1115,13 @@ func (m GemsModel) renderStatusBar(width int) string {
 
 // Helper functions
 
-// sortGems sorts gems by name (custom first, then system)
+// sortGems returns a copy of gems sorted by name (custom first, then system).
+// Names compare case-insensitively; gems with equal keys keep their order.
 func sortGems(gems []*models.Gem) []*models.Gem {
 	sorted := make([]*models.Gem, len(gems))
 	copy(sorted, gems)
 
-	sort.Slice(sorted, func(i, j int) bool {
+	sort.SliceStable(sorted, func(i, j int) bool {
 		// Custom gems before system gems
 		if sorted[i].Predefined != sorted[j].Predefined {
 			return !sorted[i].Predefined
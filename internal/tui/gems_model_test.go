@@ -1,7 +1,9 @@
 package tui
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,13 +32,14 @@ func (m *mockGemsClient) FetchGems(includeHidden bool) (*models.GemJar, error) {
 }
 
 // Implement other required methods (unused in these tests)
-func (m *mockGemsClient) Init() error                 { return nil }
-func (m *mockGemsClient) Close()                      {}
-func (m *mockGemsClient) GetAccessToken() string      { return "" }
-func (m *mockGemsClient) GetCookies() *config.Cookies { return nil }
-func (m *mockGemsClient) GetModel() models.Model      { return models.Model{} }
-func (m *mockGemsClient) SetModel(model models.Model) {}
-func (m *mockGemsClient) IsClosed() bool              { return false }
+func (m *mockGemsClient) Init() error                    { return nil }
+func (m *mockGemsClient) Ping(ctx context.Context) error { return nil }
+func (m *mockGemsClient) Close()                         {}
+func (m *mockGemsClient) GetAccessToken() string         { return "" }
+func (m *mockGemsClient) GetCookies() *config.Cookies    { return nil }
+func (m *mockGemsClient) GetModel() models.Model         { return models.Model{} }
+func (m *mockGemsClient) SetModel(model models.Model)    {}
+func (m *mockGemsClient) IsClosed() bool                 { return false }
 func (m *mockGemsClient) StartChat(model ...models.Model) *api.ChatSession {
 	return nil
 }
@@ -49,7 +52,7 @@ func (m *mockGemsClient) GenerateContent(prompt string, opts *api.GenerateOption
 func (m *mockGemsClient) UploadImage(filePath string) (*api.UploadedImage, error) {
 	return nil, nil
 }
-func (m *mockGemsClient) UploadFile(filePath string) (*api.UploadedFile, error) {
+func (m *mockGemsClient) UploadFile(filePath string, opts ...api.UploadOption) (*api.UploadedFile, error) {
 	return nil, nil
 }
 func (m *mockGemsClient) UploadText(content string, fileName string) (*api.UploadedFile, error) {
@@ -1258,6 +1261,33 @@ func TestSortGems_MixedTypes(t *testing.T) {
 	}
 }
 
+func TestSortGems_CaseInsensitiveAndStable(t *testing.T) {
+	gems := []*models.Gem{
+		{ID: "s1", Name: "beta", Predefined: true},
+		{ID: "c1", Name: "Same", Predefined: false},
+		{ID: "c2", Name: "alpha", Predefined: false},
+		{ID: "c3", Name: "same", Predefined: false},
+		{ID: "s2", Name: "Alpha", Predefined: true},
+		{ID: "c4", Name: "Same", Predefined: false},
+	}
+
+	sorted := sortGems(gems)
+
+	var ids []string
+	for _, gem := range sorted {
+		ids = append(ids, gem.ID)
+	}
+	// Equal names (ignoring case) keep their input order: c1, c3, c4
+	want := []string{"c2", "c1", "c3", "c4", "s2", "s1"}
+	if strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("sortGems() order = %v, want %v", ids, want)
+	}
+
+	if gems[0].ID != "s1" {
+		t.Error("sortGems() should not modify its input")
+	}
+}
+
 // TestGemsModel_resetForm tests the resetForm method
 func TestGemsModel_resetForm(t *testing.T) {
 	client := createMockClient()
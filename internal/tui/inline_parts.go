@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// responseDisplayText returns a response's text for the conversation.
+// Inline data embedded in the text, such as base64 images, is replaced with
+// a short placeholder so it does not flood the viewport; responses without
+// inline data are returned unchanged.
+func responseDisplayText(output *models.ModelOutput) string {
+	parts := output.Parts()
+	hasInline := false
+	for _, part := range parts {
+		if part.Type == models.PartInlineData {
+			hasInline = true
+			break
+		}
+	}
+	if !hasInline {
+		return output.Text()
+	}
+
+	var sb strings.Builder
+	for _, part := range parts {
+		switch part.Type {
+		case models.PartInlineData:
+			fmt.Fprintf(&sb, "`[inline %s, %s]`", part.MIME, formatByteSize(len(part.Data)))
+		default:
+			// Text, or the title of a generated image
+			sb.WriteString(part.Text)
+		}
+	}
+	return sb.String()
+}
+
+// formatByteSize formats a byte count as B, KB or MB
+func formatByteSize(n int) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+}
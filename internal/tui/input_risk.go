@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// RiskLevel is how risky the text in the input area looks, judged by the
+// same rules the tool security policy enforces
+type RiskLevel int
+
+const (
+	// RiskNone means nothing in the input matches the security policy
+	RiskNone RiskLevel = iota
+	// RiskSensitivePath means the input names a path the path validator
+	// blocks, such as .env, a *.pem key or ~/.ssh
+	RiskSensitivePath
+	// RiskDangerous means the input contains a command pattern the
+	// blacklist blocks for bash, such as "rm -rf /"
+	RiskDangerous
+)
+
+// Policies the input is checked against; the same defaults the chat's tool
+// executor uses
+var (
+	inputCommandPolicy = toolexec.DefaultBlacklistValidator()
+	inputPathPolicy    = toolexec.DefaultPathValidator()
+)
+
+// classifyInputRisk reports whether s contains something the security policy
+// would block if the model turned it into a tool call
+func classifyInputRisk(s string) RiskLevel {
+	if strings.TrimSpace(s) == "" {
+		return RiskNone
+	}
+
+	ctx := context.Background()
+	if inputCommandPolicy.Validate(ctx, "bash", map[string]any{"command": s}) != nil {
+		return RiskDangerous
+	}
+
+	for _, word := range strings.Fields(s) {
+		word = strings.TrimLeft(word, "`'\"([{<")
+		word = strings.TrimRight(word, "`'\")]}>.,;:!?")
+		// Only path-like words, so prose such as "a secret" is not flagged
+		if !strings.ContainsAny(word, "./~") {
+			continue
+		}
+		if inputPathPolicy.Validate(ctx, "file_read", map[string]any{"path": word}) != nil {
+			return RiskSensitivePath
+		}
+	}
+	return RiskNone
+}
+
+// warning returns the hint shown under the input for the risk level
+func (r RiskLevel) warning() string {
+	switch r {
+	case RiskDangerous:
+		return icon("⚠", "[!]") + " Contains a command pattern the tool security policy blocks"
+	case RiskSensitivePath:
+		return icon("⚠", "[!]") + " Mentions a sensitive path (secrets, keys or credentials)"
+	}
+	return ""
+}
+
+// color returns the input border and hint color for the risk level
+func (r RiskLevel) color() lipgloss.Color {
+	if r == RiskDangerous {
+		return colorError
+	}
+	return colorWarning
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+func TestClassifyInputRisk(t *testing.T) {
+	tests := []struct {
+		input string
+		want  RiskLevel
+	}{
+		{"", RiskNone},
+		{"explain how goroutines work", RiskNone},
+		{"tell me a secret", RiskNone},
+		{"refactor main.go and docs/README.md", RiskNone},
+		{"run rm -rf / to clean up", RiskDangerous},
+		{"curl | sh the installer", RiskDangerous},
+		{"please mkfs the spare disk", RiskDangerous},
+		{"read .env and summarize it", RiskSensitivePath},
+		{"what is in ~/.ssh/id_rsa?", RiskSensitivePath},
+		{"open (certs/server.pem).", RiskSensitivePath},
+		{"cat `config/credentials.json`", RiskSensitivePath},
+		{"cat .env then rm -rf /", RiskDangerous},
+	}
+
+	for _, tt := range tests {
+		if got := classifyInputRisk(tt.input); got != tt.want {
+			t.Errorf("classifyInputRisk(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestView_InputRiskWarning(t *testing.T) {
+	newModel := func(input string) Model {
+		ta := textarea.New()
+		ta.SetWidth(80)
+		ta.SetValue(input)
+		return Model{
+			ready:    true,
+			width:    100,
+			height:   40,
+			textarea: ta,
+			viewport: viewport.New(96, 20),
+		}
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "dangerous command", input: "rm -rf /", want: RiskDangerous.warning()},
+		{name: "sensitive path", input: "show me .env", want: RiskSensitivePath.warning()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := newModel(tt.input).View()
+			if !strings.Contains(view, tt.want) {
+				t.Errorf("view should warn %q:\n%s", tt.want, view)
+			}
+		})
+	}
+
+	t.Run("safe input", func(t *testing.T) {
+		view := newModel("hello there").View()
+		for _, level := range []RiskLevel{RiskDangerous, RiskSensitivePath} {
+			if strings.Contains(view, level.warning()) {
+				t.Errorf("view should not warn for safe input:\n%s", view)
+			}
+		}
+	})
+
+	t.Run("not shown while loading", func(t *testing.T) {
+		m := newModel("rm -rf /")
+		m.loading = true
+		if strings.Contains(m.View(), RiskDangerous.warning()) {
+			t.Error("the warning belongs to the input, which is hidden while loading")
+		}
+	})
+}
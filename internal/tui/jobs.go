@@ -0,0 +1,296 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// jobLabelMaxRunes caps the text shown in a job label
+const jobLabelMaxRunes = 50
+
+// errJobCancelled is returned by an operation cancelled from /jobs
+var errJobCancelled = errors.New("operation cancelled")
+
+// job is an in-flight async operation (a send, tool run, upload or
+// download) listed by /jobs
+type job struct {
+	id      int
+	label   string
+	started time.Time
+	cancel  context.CancelFunc // nil for jobs that cannot be cancelled
+}
+
+// jobRegistry tracks in-flight operations. It is a pointer shared by all
+// copies of the Model, since operations start and finish in commands
+// running on other goroutines. A nil registry tracks nothing.
+type jobRegistry struct {
+	mu     sync.Mutex
+	nextID int
+	jobs   []*job
+	sends  int // Sends to the model in flight (see startSend)
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{}
+}
+
+// start registers an operation and returns its context, cancelled from
+// /jobs, and a function to call when the operation finishes
+func (r *jobRegistry) start(label string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if r == nil {
+		return ctx, cancel
+	}
+
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	r.jobs = append(r.jobs, &job{id: id, label: label, started: time.Now(), cancel: cancel})
+	r.mu.Unlock()
+
+	return ctx, func() {
+		r.remove(id)
+		cancel()
+	}
+}
+
+// startSend registers a send to the model, which cannot be cancelled
+// since the session has no way to abort a request. The send counts as in
+// flight until the returned function is called.
+func (r *jobRegistry) startSend(label string) func() {
+	if r == nil {
+		return func() {}
+	}
+
+	r.mu.Lock()
+	r.nextID++
+	id := r.nextID
+	r.jobs = append(r.jobs, &job{id: id, label: label, started: time.Now()})
+	r.sends++
+	r.mu.Unlock()
+
+	return func() {
+		if r.remove(id) == nil {
+			return
+		}
+		r.mu.Lock()
+		r.sends--
+		r.mu.Unlock()
+	}
+}
+
+// remove drops a job from the registry, returning it if it was there
+func (r *jobRegistry) remove(id int) *job {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, j := range r.jobs {
+		if j.id == id {
+			r.jobs = append(r.jobs[:i], r.jobs[i+1:]...)
+			return j
+		}
+	}
+	return nil
+}
+
+// cancel cancels a job and removes it, reporting whether it was running
+// and could be cancelled
+func (r *jobRegistry) cancel(id int) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	var j *job
+	for i, candidate := range r.jobs {
+		if candidate.id == id && candidate.cancel != nil {
+			j = candidate
+			r.jobs = append(r.jobs[:i], r.jobs[i+1:]...)
+			break
+		}
+	}
+	r.mu.Unlock()
+
+	if j == nil {
+		return false
+	}
+	j.cancel()
+	return true
+}
+
+// list returns the running jobs, oldest first
+func (r *jobRegistry) list() []job {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]job, len(r.jobs))
+	for i, j := range r.jobs {
+		jobs[i] = *j
+	}
+	return jobs
+}
+
+// sending reports whether a send to the model is in flight
+func (r *jobRegistry) sending() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sends > 0
+}
+
+// runJob runs fn as a job labelled label and returns its result. When the
+// job is cancelled before fn returns, runJob returns errJobCancelled right
+// away; fn gets the job's context so it can stop early, and its late
+// result is discarded otherwise.
+func runJob[T any](jobs *jobRegistry, label string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, done := jobs.start(label)
+	defer done()
+
+	type result struct {
+		value T
+		err   error
+	}
+	results := make(chan result, 1)
+	go func() {
+		value, err := fn(ctx)
+		results <- result{value, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, errJobCancelled
+	}
+}
+
+// jobLabel shortens text to one line for a job label
+func jobLabel(kind, text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if short := truncateRunes(text, jobLabelMaxRunes); short != text {
+		text = short + "…"
+	}
+	return kind + ": " + text
+}
+
+// handleJobsCommand handles the /jobs command, which lists the in-flight
+// operations in an overlay where they can be cancelled
+func (m Model) handleJobsCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /jobs")
+		return m, nil
+	}
+
+	m.showingJobs = true
+	m.jobsCursor = 0
+	m.err = nil
+	return m, nil
+}
+
+// updateJobs handles input while the jobs overlay is shown. Other messages
+// (responses, tool results) go through the regular Update so operations
+// finishing in the background are not lost.
+func (m Model) updateJobs(msg tea.Msg) (tea.Model, tea.Cmd) {
+	jobs := m.jobs.list()
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.handleInterrupt()
+		case "esc", "q":
+			m.showingJobs = false
+			return m, nil
+		case "up", "k":
+			if m.jobsCursor > 0 {
+				m.jobsCursor--
+			}
+		case "down", "j":
+			if m.jobsCursor < len(jobs)-1 {
+				m.jobsCursor++
+			}
+		case "enter", "x", "delete":
+			if len(jobs) == 0 {
+				return m, nil
+			}
+			selected := jobs[min(m.jobsCursor, len(jobs)-1)]
+			if selected.cancel == nil {
+				m.err = fmt.Errorf("%s can't be cancelled - it runs until the server replies", selected.label)
+				return m, nil
+			}
+			if m.jobs.cancel(selected.id) {
+				m.err = fmt.Errorf("✓ Cancelled %s", selected.label)
+			}
+			m.jobsCursor = max(0, min(m.jobsCursor, len(jobs)-2))
+		}
+		return m, nil
+	}
+
+	m.showingJobs = false
+	updated, cmd := m.Update(msg)
+	if um, ok := updated.(Model); ok {
+		um.showingJobs = true
+		um.jobsCursor = max(0, min(um.jobsCursor, len(um.jobs.list())-1))
+		return um, cmd
+	}
+	return updated, cmd
+}
+
+// renderJobs renders the jobs overlay
+func (m Model) renderJobs() string {
+	width := m.width - 12
+	if width < 40 {
+		width = 40
+	}
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render("Running operations"))
+	content.WriteString("\n\n")
+
+	jobs := m.jobs.list()
+	if len(jobs) == 0 {
+		content.WriteString(hintStyle.Render("No operations in progress"))
+	}
+	for i, j := range jobs {
+		elapsed := time.Since(j.started).Round(time.Second)
+		line := fmt.Sprintf("%s  %s", j.label, hintStyle.Render(elapsed.String()))
+		if j.cancel == nil {
+			line += hintStyle.Render("  (can't cancel)")
+		}
+		if i == m.jobsCursor {
+			content.WriteString(configValueStyle.Render("▸ ") + line)
+		} else {
+			content.WriteString("  " + line)
+		}
+		content.WriteString("\n")
+	}
+
+	content.WriteString("\n")
+	content.WriteString(hintStyle.Render("↑/↓: select • enter/x: cancel • esc/q: close"))
+
+	if m.err != nil {
+		content.WriteString("\n\n")
+		content.WriteString(m.formatError(m.err))
+	}
+
+	panel := messagesAreaStyle.Width(width).Render(content.String())
+	if m.width > 0 && m.height > 0 {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
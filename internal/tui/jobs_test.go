@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+func TestJobRegistry_StartAndDone(t *testing.T) {
+	jobs := newJobRegistry()
+	ctx, done := jobs.start("Send: hello")
+
+	list := jobs.list()
+	if len(list) != 1 || list[0].label != "Send: hello" {
+		t.Fatalf("list() = %+v, want one job", list)
+	}
+
+	done()
+	if len(jobs.list()) != 0 {
+		t.Error("done should remove the job")
+	}
+	if ctx.Err() == nil {
+		t.Error("done should release the job context")
+	}
+}
+
+func TestJobRegistry_NilTracksNothing(t *testing.T) {
+	var jobs *jobRegistry
+	ctx, done := jobs.start("Send: hello")
+	defer done()
+
+	if jobs.list() != nil || jobs.cancel(1) {
+		t.Error("a nil registry should track nothing")
+	}
+	if ctx.Err() != nil {
+		t.Error("the context should still be usable")
+	}
+}
+
+func TestRunJob_Cancelled(t *testing.T) {
+	jobs := newJobRegistry()
+	release := make(chan struct{})
+	defer close(release)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := runJob(jobs, "Upload: big.zip", func(context.Context) (string, error) {
+			<-release
+			return "late", nil
+		})
+		result <- err
+	}()
+
+	var list []job
+	for deadline := time.Now().Add(2 * time.Second); len(list) == 0; list = jobs.list() {
+		if time.Now().After(deadline) {
+			t.Fatal("the job was never registered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if !jobs.cancel(list[0].id) {
+		t.Fatal("cancel() should find the running job")
+	}
+	select {
+	case err := <-result:
+		if !errors.Is(err, errJobCancelled) {
+			t.Errorf("runJob() error = %v, want errJobCancelled", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("runJob did not return after cancel")
+	}
+}
+
+func TestJobsOverlay_ListsJobs(t *testing.T) {
+	m := newTestModel()
+	_, doneSend := m.jobs.start(jobLabel("Send", "explain\nthe   code"))
+	defer doneSend()
+	_, doneTool := m.jobs.start(jobLabel("Tool", "bash"))
+	defer doneTool()
+
+	updated, _ := m.handleJobsCommand("")
+	m = updated.(Model)
+	if !m.showingJobs {
+		t.Fatal("/jobs should open the overlay")
+	}
+
+	view := ansiPattern.ReplaceAllString(m.View(), "")
+	for _, want := range []string{"Running operations", "Send: explain the code", "Tool: bash"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("overlay is missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestJobsOverlay_Empty(t *testing.T) {
+	m := newTestModel()
+	updated, _ := m.handleJobsCommand("")
+	view := ansiPattern.ReplaceAllString(updated.(Model).View(), "")
+	if !strings.Contains(view, "No operations in progress") {
+		t.Errorf("expected empty state:\n%s", view)
+	}
+}
+
+func TestJobsOverlay_CancelSelected(t *testing.T) {
+	m := newTestModel()
+	firstCtx, doneFirst := m.jobs.start("Send: first")
+	defer doneFirst()
+	secondCtx, doneSecond := m.jobs.start("Upload: second")
+	defer doneSecond()
+
+	updated, _ := m.handleJobsCommand("")
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if secondCtx.Err() == nil {
+		t.Error("the selected job should be cancelled")
+	}
+	if firstCtx.Err() != nil {
+		t.Error("other jobs should keep running")
+	}
+	list := m.jobs.list()
+	if len(list) != 1 || list[0].label != "Send: first" {
+		t.Errorf("remaining jobs = %+v, want only the first", list)
+	}
+	if m.jobsCursor != 0 {
+		t.Errorf("cursor = %d, want 0 after removing the last row", m.jobsCursor)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "Cancelled Upload: second") {
+		t.Errorf("expected cancel feedback, got %v", m.err)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).showingJobs {
+		t.Error("esc should close the overlay")
+	}
+}
+
+func TestJobsOverlay_PassesOtherMessagesThrough(t *testing.T) {
+	m := newTestModel()
+	m.loading = true
+	updated, _ := m.handleJobsCommand("")
+	m = updated.(Model)
+
+	updated, _ = m.Update(errMsg{err: errJobCancelled, prompt: "hello"})
+	m = updated.(Model)
+	if m.loading {
+		t.Error("a finished send should be handled while the overlay is open")
+	}
+	if !m.showingJobs {
+		t.Error("the overlay should stay open")
+	}
+}
+
+func TestSendPrompt_RegistersJob(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	m := newTestModel()
+	m.session = &mockChatSession{
+		sendMessageFunc: func(string, []*api.UploadedFile) (*models.ModelOutput, error) {
+			close(started)
+			<-release
+			return &models.ModelOutput{}, nil
+		},
+	}
+
+	result := make(chan tea.Msg, 1)
+	go func() { result <- m.sendPrompt("hello there", nil)() }()
+	<-started
+
+	list := m.jobs.list()
+	if len(list) != 1 || list[0].label != "Send: hello there" {
+		t.Fatalf("jobs = %+v, want the send", list)
+	}
+	release <- struct{}{}
+	if _, ok := (<-result).(responseMsg); !ok {
+		t.Error("expected the reply")
+	}
+	if len(m.jobs.list()) != 0 {
+		t.Error("the send should be removed once it returns")
+	}
+}
+
+func TestJobsOverlay_SendCannotBeCancelled(t *testing.T) {
+	m := newTestModel()
+	done := m.jobs.startSend("Send: question")
+	defer done()
+
+	updated, _ := m.handleJobsCommand("")
+	m = updated.(Model)
+	if view := ansiPattern.ReplaceAllString(m.View(), ""); !strings.Contains(view, "(can't cancel)") {
+		t.Errorf("the send should be marked as not cancellable:\n%s", view)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.err.Error(), "can't be cancelled") {
+		t.Errorf("expected the cancel to be refused, got %v", m.err)
+	}
+	if len(m.jobs.list()) != 1 || !m.jobs.sending() {
+		t.Error("the send should still be listed and in flight")
+	}
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// extractJSON returns the JSON document contained in s when the whole
+// message is a single JSON object or array, optionally wrapped in a
+// ```json code fence. The second return value is false for prose or
+// mixed content.
+func extractJSON(s string) (string, bool) {
+	trimmed := strings.TrimSpace(s)
+
+	// Accept a message that consists of exactly one fenced code block
+	if strings.HasPrefix(trimmed, "```") && strings.HasSuffix(trimmed, "```") && len(trimmed) > 6 {
+		body := strings.TrimSuffix(trimmed[3:], "```")
+		firstLine, rest, found := strings.Cut(body, "\n")
+		if !found || strings.Contains(rest, "```") {
+			return "", false
+		}
+		lang := strings.ToLower(strings.TrimSpace(firstLine))
+		if lang != "" && lang != "json" {
+			return "", false
+		}
+		trimmed = strings.TrimSpace(rest)
+	}
+
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return "", false
+	}
+	if !json.Valid([]byte(trimmed)) {
+		return "", false
+	}
+	return trimmed, true
+}
+
+// looksLikeJSON reports whether s is entirely a JSON object or array.
+// Scalars such as "42" or "true" are treated as prose.
+func looksLikeJSON(s string) bool {
+	_, ok := extractJSON(s)
+	return ok
+}
+
+// prettyJSON re-indents a JSON message with two spaces.
+// json.Indent works on the raw bytes, so key order is preserved exactly
+// as the model produced it.
+func prettyJSON(s string) (string, bool) {
+	raw, ok := extractJSON(s)
+	if !ok {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(raw), "", "  "); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// highlightJSON applies syntax coloring to pretty-printed JSON.
+// The input must be valid JSON; string contents are never reformatted.
+func highlightJSON(s string) string {
+	var sb strings.Builder
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '"':
+			end := scanJSONString(s, i)
+			token := s[i:end]
+			// A string followed by ':' is an object key
+			rest := strings.TrimLeft(s[end:], " \t")
+			if strings.HasPrefix(rest, ":") {
+				sb.WriteString(jsonKeyStyle.Render(token))
+			} else {
+				sb.WriteString(jsonStringStyle.Render(token))
+			}
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+			sb.WriteString(jsonNumberStyle.Render(s[i:end]))
+			i = end
+		case c == 't' || c == 'f' || c == 'n':
+			end := i + 1
+			for end < len(s) && s[end] >= 'a' && s[end] <= 'z' {
+				end++
+			}
+			sb.WriteString(jsonLiteralStyle.Render(s[i:end]))
+			i = end
+		case strings.IndexByte("{}[],:", c) >= 0:
+			sb.WriteString(jsonPunctStyle.Render(string(c)))
+			i++
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// scanJSONString returns the index just past the closing quote of the
+// JSON string starting at s[start].
+func scanJSONString(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// renderJSONMessage renders an assistant message that is pure JSON.
+// Returns false if the message should go through markdown rendering instead.
+func renderJSONMessage(content string, width int) (string, bool) {
+	pretty, ok := prettyJSON(content)
+	if !ok {
+		return "", false
+	}
+	return lipgloss.NewStyle().Width(width).Render(highlightJSON(pretty)), true
+}
+
+// handleCopyJSONCommand handles the /copy-json command.
+// It copies the most recent assistant message that is valid JSON, pretty-printed.
+func (m Model) handleCopyJSONCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /copy-json")
+		return m, nil
+	}
+
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := m.messages[i]
+		if msg.role != "assistant" {
+			continue
+		}
+		pretty, ok := prettyJSON(msg.content)
+		if !ok {
+			continue
+		}
+		if err := writeClipboard(pretty); err != nil {
+			m.err = clipboardError(err)
+			return m, nil
+		}
+		m.err = fmt.Errorf("✓ Copied JSON (%d bytes) to clipboard", len(pretty))
+		return m, nil
+	}
+
+	m.err = fmt.Errorf("no JSON response to copy")
+	return m, nil
+}
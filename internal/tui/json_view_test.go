@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+)
+
+func TestLooksLikeJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"object", `{"a": 1, "b": [true, null]}`, true},
+		{"array", `[1, 2, 3]`, true},
+		{"surrounding whitespace", "\n  {\"a\": \"b\"}\n", true},
+		{"fenced json", "```json\n{\"a\": 1}\n```", true},
+		{"fenced without language", "```\n[1]\n```", true},
+		{"prose", "Here is the answer: 42", false},
+		{"prose with json inside", "Result:\n```json\n{\"a\": 1}\n```\nDone.", false},
+		{"markdown list", "- item\n- item", false},
+		{"invalid json", `{"a": 1,}`, false},
+		{"scalar number", `42`, false},
+		{"scalar string", `"hello"`, false},
+		{"fenced other language", "```go\n{}\n```", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksLikeJSON(tt.input); got != tt.want {
+				t.Errorf("looksLikeJSON(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	input := `{"zeta":1,"alpha":{"nested":[1,2,{"k":"v"}]},"mid":"text with \"quotes\""}`
+
+	pretty, ok := prettyJSON(input)
+	if !ok {
+		t.Fatal("prettyJSON() returned false for valid JSON")
+	}
+	if !json.Valid([]byte(pretty)) {
+		t.Fatalf("pretty output is not valid JSON:\n%s", pretty)
+	}
+	if !strings.Contains(pretty, "\n  \"alpha\": {") {
+		t.Errorf("expected two-space indentation, got:\n%s", pretty)
+	}
+
+	// Key order must match the original document
+	zeta := strings.Index(pretty, `"zeta"`)
+	alpha := strings.Index(pretty, `"alpha"`)
+	mid := strings.Index(pretty, `"mid"`)
+	if !(zeta < alpha && alpha < mid) {
+		t.Errorf("key order not preserved:\n%s", pretty)
+	}
+
+	if _, ok := prettyJSON("not json"); ok {
+		t.Error("prettyJSON() should return false for prose")
+	}
+}
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestHighlightJSON_PreservesText(t *testing.T) {
+	pretty, ok := prettyJSON(`{"key":"value: with colon","n":-1.5e3,"ok":false,"none":null}`)
+	if !ok {
+		t.Fatal("prettyJSON() returned false for valid JSON")
+	}
+
+	highlighted := highlightJSON(pretty)
+	if stripped := ansiPattern.ReplaceAllString(highlighted, ""); stripped != pretty {
+		t.Errorf("highlighting changed the text:\ngot:\n%s\nwant:\n%s", stripped, pretty)
+	}
+}
+
+func TestModel_CopyJSONCommand(t *testing.T) {
+	stubClipboard := func(t *testing.T) *string {
+		t.Helper()
+		var copied string
+		orig := writeClipboard
+		writeClipboard = func(text string) error {
+			copied = text
+			return nil
+		}
+		t.Cleanup(func() { writeClipboard = orig })
+		return &copied
+	}
+
+	t.Run("copies latest JSON response", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{
+			messages: []chatMessage{
+				{role: "assistant", content: `{"old":true}`},
+				{role: "user", content: `{"from":"user"}`},
+				{role: "assistant", content: "```json\n{\"b\":2,\"a\":1}\n```"},
+				{role: "assistant", content: "Some prose afterwards"},
+			},
+			textarea: textarea.New(),
+		}
+		updated, _ := m.handleCopyJSONCommand("")
+		typed := updated.(Model)
+
+		want := "{\n  \"b\": 2,\n  \"a\": 1\n}"
+		if *copied != want {
+			t.Errorf("copied = %q, want %q", *copied, want)
+		}
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "✓ Copied JSON") {
+			t.Errorf("expected success feedback, got %v", typed.err)
+		}
+	})
+
+	t.Run("no JSON response", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{
+			messages: []chatMessage{{role: "assistant", content: "Just prose"}},
+			textarea: textarea.New(),
+		}
+		updated, _ := m.handleCopyJSONCommand("")
+		typed := updated.(Model)
+
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "no JSON response") {
+			t.Errorf("expected no-JSON error, got %v", typed.err)
+		}
+		if *copied != "" {
+			t.Error("clipboard should not be written")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// longLineThreshold is the line length, in bytes, above which a user
+	// message is collapsed to a summary instead of being wrapped and rendered
+	longLineThreshold = 2000
+
+	// longInputPreviewLen is the number of characters of a collapsed
+	// message shown before its summary
+	longInputPreviewLen = 200
+)
+
+// isLongInput reports whether content has a line too long to render in a
+// bubble without stalling the UI
+func isLongInput(content string) bool {
+	for len(content) > longLineThreshold {
+		end := strings.IndexByte(content, '\n')
+		if end < 0 {
+			return true
+		}
+		if end > longLineThreshold {
+			return true
+		}
+		content = content[end+1:]
+	}
+	return false
+}
+
+// longInputSummary returns the collapsed rendering of a long user message: a
+// short single-line preview followed by its size and how to expand it
+func longInputSummary(content string) string {
+	preview := strings.Join(strings.Fields(truncateRunes(content, longInputPreviewLen)), " ")
+	summary := fmt.Sprintf("[long input, %d chars — expand with /expand]", utf8.RuneCountInString(content))
+	return preview + "…\n" + summary
+}
+
+// truncateRunes returns the first n runes of s
+func truncateRunes(s string, n int) string {
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
+
+// hardWrap breaks lines of s longer than width runes. Unlike word wrapping,
+// it is linear in the length of s, so it stays fast on huge single lines.
+func hardWrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + len(s)/width)
+	col := 0
+	for _, r := range s {
+		if r == '\n' {
+			col = 0
+		} else {
+			if col == width {
+				b.WriteByte('\n')
+				col = 0
+			}
+			col++
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// handleExpandCommand handles the /expand command, which shows the most
+// recent collapsed long user message in full in a scrollable overlay.
+func (m Model) handleExpandCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /expand")
+		return m, nil
+	}
+
+	content := ""
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].role == "user" && isLongInput(m.messages[i].content) {
+			content = m.messages[i].content
+			break
+		}
+	}
+	if content == "" {
+		m.err = fmt.Errorf("no long input to expand")
+		return m, nil
+	}
+
+	width, height := m.overlayViewportSize()
+	m.rawViewport = viewport.New(width, height)
+	m.rawViewport.SetContent(hardWrap(content, width))
+	m.rawTitle = fmt.Sprintf("Long input (%d chars)", utf8.RuneCountInString(content))
+	m.showingRaw = true
+	m.err = nil
+	return m, nil
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIsLongInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"short", "hello", false},
+		{"many short lines", strings.Repeat("line of text\n", 1000), false},
+		{"single long line", strings.Repeat("x", longLineThreshold+1), true},
+		{"long last line", "intro\n" + strings.Repeat("x", longLineThreshold+1), true},
+		{"at threshold", strings.Repeat("x", longLineThreshold), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLongInput(tt.content); got != tt.want {
+				t.Errorf("isLongInput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHardWrap(t *testing.T) {
+	if got := hardWrap("abcdefg\nhi", 3); got != "abc\ndef\ng\nhi" {
+		t.Errorf("hardWrap() = %q", got)
+	}
+	if got := hardWrap("héllo", 2); got != "hé\nll\no" {
+		t.Errorf("hardWrap() should count runes, got %q", got)
+	}
+}
+
+func newLongInputModel(content string) Model {
+	return newTestModel(withViewport(96, 30), withMessages(
+		chatMessage{role: "user", content: content},
+		chatMessage{role: "assistant", content: "Got it"},
+	))
+}
+
+func TestUpdateViewport_LongSingleLineInput(t *testing.T) {
+	huge := "START" + strings.Repeat("a", 51229)
+	m := newLongInputModel(huge)
+
+	start := time.Now()
+	m.updateViewport()
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("rendering took %v", elapsed)
+	}
+
+	view := ansiPattern.ReplaceAllString(m.viewport.View(), "")
+	if !strings.Contains(view, "[long input, 51234 chars") || !strings.Contains(view, "/expand]") {
+		t.Errorf("expected a long input summary, got:\n%s", view)
+	}
+	if !strings.Contains(view, "START") {
+		t.Errorf("summary should include a preview of the input:\n%s", view)
+	}
+	if strings.Count(view, "a") > 1000 {
+		t.Error("viewport should not render the full input")
+	}
+}
+
+func TestExpandCommand(t *testing.T) {
+	t.Run("shows the full input in the overlay", func(t *testing.T) {
+		huge := strings.Repeat("b", 5000) + "END"
+		m := newLongInputModel(huge)
+
+		expanded, _ := m.handleExpandCommand("")
+		m = expanded.(Model)
+		if !m.showingRaw {
+			t.Fatal("expected the long input overlay")
+		}
+		if !strings.Contains(m.View(), "Long input (5003 chars)") {
+			t.Error("overlay should show the input size")
+		}
+
+		m.rawViewport.GotoBottom()
+		if !strings.Contains(m.View(), "END") {
+			t.Error("overlay should hold the end of the input")
+		}
+
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		m = updated.(Model)
+		if m.showingRaw || m.rawTitle != "" {
+			t.Error("esc should close the overlay")
+		}
+	})
+
+	t.Run("no long input", func(t *testing.T) {
+		m := newLongInputModel("short")
+		updated, _ := m.handleExpandCommand("")
+		m = updated.(Model)
+		if m.showingRaw || m.err == nil || !strings.Contains(m.err.Error(), "no long input") {
+			t.Errorf("expected an error, got %v", m.err)
+		}
+	})
+
+	t.Run("rejects arguments", func(t *testing.T) {
+		m := newLongInputModel(strings.Repeat("c", 5000))
+		updated, _ := m.handleExpandCommand("2")
+		m = updated.(Model)
+		if m.showingRaw || m.err == nil || !strings.Contains(m.err.Error(), "usage") {
+			t.Errorf("expected a usage error, got %v", m.err)
+		}
+	})
+}
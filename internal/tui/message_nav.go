@@ -0,0 +1,29 @@
+package tui
+
+// jumpToUserMessage scrolls the viewport to the start of the previous
+// (direction < 0) or next (direction > 0) user message relative to the
+// current scroll position. It does nothing when there is no such message.
+func (m *Model) jumpToUserMessage(direction int) {
+	current := m.viewport.YOffset
+	target := -1
+
+	if direction < 0 {
+		for i := len(m.userMessageOffsets) - 1; i >= 0; i-- {
+			if m.userMessageOffsets[i] < current {
+				target = m.userMessageOffsets[i]
+				break
+			}
+		}
+	} else {
+		for _, offset := range m.userMessageOffsets {
+			if offset > current {
+				target = offset
+				break
+			}
+		}
+	}
+
+	if target >= 0 {
+		m.viewport.SetYOffset(target)
+	}
+}
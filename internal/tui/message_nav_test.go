@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newMessageNavTestModel() Model {
+	m := newTestModel(withViewport(80, 5), func(m *Model) { m.width = 84 })
+	for i := 0; i < 3; i++ {
+		m.messages = append(m.messages,
+			chatMessage{role: "user", content: "question"},
+			chatMessage{role: "assistant", content: strings.Repeat("answer line\n\n", 6)},
+		)
+	}
+	m.updateViewport()
+	return m
+}
+
+func TestUpdateViewport_RecordsUserMessageOffsets(t *testing.T) {
+	m := newMessageNavTestModel()
+
+	if len(m.userMessageOffsets) != 3 {
+		t.Fatalf("recorded %d offsets, want 3", len(m.userMessageOffsets))
+	}
+
+	for i, offset := range m.userMessageOffsets {
+		m.viewport.SetYOffset(offset)
+		firstLine := strings.Split(ansiPattern.ReplaceAllString(m.viewport.View(), ""), "\n")[0]
+		if !strings.Contains(firstLine, "You") {
+			t.Errorf("offset %d = %d does not point at a user label: %q", i, offset, firstLine)
+		}
+		if i > 0 && offset <= m.userMessageOffsets[i-1] {
+			t.Errorf("offsets not increasing: %v", m.userMessageOffsets)
+		}
+	}
+}
+
+func TestModel_Update_JumpBetweenUserMessages(t *testing.T) {
+	m := newMessageNavTestModel()
+	offsets := m.userMessageOffsets
+	ctrlUp := tea.KeyMsg{Type: tea.KeyCtrlUp}
+	ctrlDown := tea.KeyMsg{Type: tea.KeyCtrlDown}
+
+	m.viewport.GotoBottom()
+	for i := len(offsets) - 1; i >= 0; i-- {
+		updated, _ := m.Update(ctrlUp)
+		m = updated.(Model)
+		if m.viewport.YOffset != offsets[i] {
+			t.Fatalf("after ctrl+up YOffset = %d, want %d", m.viewport.YOffset, offsets[i])
+		}
+	}
+
+	// Already at the first user message: stays put
+	updated, _ := m.Update(ctrlUp)
+	m = updated.(Model)
+	if m.viewport.YOffset != offsets[0] {
+		t.Errorf("ctrl+up at first message moved to %d", m.viewport.YOffset)
+	}
+
+	for i := 1; i < len(offsets); i++ {
+		updated, _ := m.Update(ctrlDown)
+		m = updated.(Model)
+		if m.viewport.YOffset != offsets[i] {
+			t.Fatalf("after ctrl+down YOffset = %d, want %d", m.viewport.YOffset, offsets[i])
+		}
+	}
+}
+
+func TestModel_Update_JumpDisabledInSelectionModes(t *testing.T) {
+	m := newMessageNavTestModel()
+	m.viewport.GotoBottom()
+	bottom := m.viewport.YOffset
+	m.showingDiff = true
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlUp})
+	if got := updated.(Model).viewport.YOffset; got != bottom {
+		t.Errorf("ctrl+up in diff overlay moved the chat viewport to %d", got)
+	}
+}
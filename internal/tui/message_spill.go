@@ -0,0 +1,128 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+// defaultMessageCap is how many messages a persisted chat keeps in memory
+// for rendering; older ones stay in the history store
+const defaultMessageCap = 200
+
+// messagePageSize is how many spilled messages are loaded back at a time
+// when scrolling up past the oldest message in memory
+const messagePageSize = 50
+
+// defaultResumeMessages is how many recent messages are shown when
+// switching to a conversation the server can resume from its metadata;
+// older ones load when scrolling up
+const defaultResumeMessages = 20
+
+// canSpillMessages reports whether older messages may be dropped from
+// memory, which requires them to be persisted in a history store that can
+// load them back
+func (m *Model) canSpillMessages() bool {
+	_, paged := m.historyStore.(PagedHistoryStore)
+	return m.messageCap > 0 && paged && m.conversation != nil
+}
+
+// spillMessages drops the oldest messages beyond the cap from memory.
+// It only runs while the view follows the end of the chat, so messages
+// loaded back by scrolling up stay visible until the user returns.
+func (m *Model) spillMessages() {
+	if !m.canSpillMessages() || len(m.messages) <= m.messageCap || !m.viewport.AtBottom() {
+		return
+	}
+
+	drop := len(m.messages) - m.messageCap
+	m.messages = append([]chatMessage(nil), m.messages[drop:]...)
+	m.spilledBefore = m.messages[0].timestamp
+	m.shiftToolStream(-drop)
+}
+
+// shiftToolStream moves the streamed tool message's index by delta after
+// messages are dropped from or added to the start of m.messages
+func (m *Model) shiftToolStream(delta int) {
+	if m.toolStream == nil {
+		return
+	}
+	m.toolStream.index += delta
+	if m.toolStream.index < 0 {
+		m.toolStream = nil
+	}
+}
+
+// loadConversationMessages replaces the chat messages with those of a
+// stored conversation, keeping only the most recent ones in memory
+func (m *Model) loadConversationMessages(msgs []history.Message) {
+	m.loadRecentMessages(msgs, m.messageCap)
+}
+
+// loadRecentMessages replaces the chat messages with the last limit
+// messages of a stored conversation, leaving the rest spilled in the store
+func (m *Model) loadRecentMessages(msgs []history.Message, limit int) {
+	m.spilledBefore = time.Time{}
+	if m.canSpillMessages() && limit > 0 && len(msgs) > limit {
+		msgs = msgs[len(msgs)-limit:]
+		m.spilledBefore = msgs[0].Timestamp
+	}
+	m.messages = chatMessagesFromHistory(msgs)
+}
+
+// hasSpilledMessages reports whether older messages were dropped from
+// memory and can be loaded back from the store
+func (m Model) hasSpilledMessages() bool {
+	return !m.spilledBefore.IsZero()
+}
+
+// spilledMessagesHint marks where older, spilled history begins
+func (m Model) spilledMessagesHint() string {
+	return hintStyle.Render("↑ earlier messages · scroll up to load")
+}
+
+// loadOlderMessages fetches the page of spilled messages preceding those in
+// memory from the store, keeping the viewport on the same line. Pages are
+// found by timestamp rather than position, since not every message in
+// memory is stored.
+func (m *Model) loadOlderMessages() {
+	store, ok := m.historyStore.(PagedHistoryStore)
+	if !m.hasSpilledMessages() || !ok || m.conversation == nil {
+		return
+	}
+
+	// One extra message tells whether older ones remain
+	stored, err := store.GetMessagesBefore(m.conversation.ID, m.spilledBefore, messagePageSize+1)
+	if err != nil {
+		m.err = fmt.Errorf("failed to load older messages: %w", err)
+		return
+	}
+	m.spilledBefore = time.Time{}
+	if len(stored) > messagePageSize {
+		stored = stored[1:]
+		m.spilledBefore = stored[0].Timestamp
+	}
+
+	m.messages = append(chatMessagesFromHistory(stored), m.messages...)
+	m.shiftToolStream(len(stored))
+
+	lines := m.viewport.TotalLineCount()
+	yOffset := m.viewport.YOffset
+	m.updateViewport()
+	m.viewport.SetYOffset(yOffset + m.viewport.TotalLineCount() - lines)
+}
+
+// chatMessagesFromHistory converts stored messages to chat messages
+func chatMessagesFromHistory(msgs []history.Message) []chatMessage {
+	messages := make([]chatMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		messages = append(messages, chatMessage{
+			role:      msg.Role,
+			content:   msg.Content,
+			thoughts:  msg.Thoughts,
+			timestamp: msg.Timestamp,
+		})
+	}
+	return messages
+}
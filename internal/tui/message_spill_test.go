@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// storedTestMessages returns n stored messages a second apart
+func storedTestMessages(n int) []history.Message {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	msgs := make([]history.Message, n)
+	for i := range msgs {
+		msgs[i] = history.Message{Role: "user", Content: fmt.Sprintf("message %d", i), Timestamp: start.Add(time.Duration(i) * time.Second)}
+	}
+	return msgs
+}
+
+func newSpillTestModel(store *mockHistoryStoreForModel, n, limit int) Model {
+	store.storedMessages = append(store.storedMessages, storedTestMessages(n)...)
+	return newTestModel(
+		withViewport(80, 5),
+		withHistory(store, &history.Conversation{ID: "conv-1"}),
+		withMessages(chatMessagesFromHistory(store.storedMessages)...),
+		func(m *Model) { m.messageCap = limit },
+	)
+}
+
+func TestSpillMessages_DropsOldestBeyondCap(t *testing.T) {
+	store := &mockHistoryStoreForModel{}
+	m := newSpillTestModel(store, 4, 4)
+	m.updateViewport()
+	m.viewport.GotoBottom()
+
+	updated, _ := m.Update(responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: "message 4."}}}})
+	m = updated.(Model)
+	store.storedMessages = append(store.storedMessages, history.Message{Role: "assistant", Content: "message 4."})
+
+	if len(m.messages) != 4 {
+		t.Fatalf("expected 4 messages in memory, got %d", len(m.messages))
+	}
+	if !m.spilledBefore.Equal(m.messages[0].timestamp) {
+		t.Errorf("spilledBefore = %v, want the oldest kept message's time", m.spilledBefore)
+	}
+	if m.messages[0].content != "message 1" || m.messages[3].content != "message 4." {
+		t.Errorf("expected the most recent messages to be kept, got %q..%q", m.messages[0].content, m.messages[3].content)
+	}
+}
+
+func TestSpillMessages_KeepsAllWithoutStore(t *testing.T) {
+	m := newSpillTestModel(&mockHistoryStoreForModel{}, 6, 4)
+	m.historyStore = nil
+	m.updateViewport()
+
+	if len(m.messages) != 6 || m.hasSpilledMessages() {
+		t.Errorf("unpersisted messages must not be dropped, got %d in memory", len(m.messages))
+	}
+}
+
+// unpagedHistoryStore hides a store's optional interfaces, leaving only
+// HistoryStoreInterface
+type unpagedHistoryStore struct {
+	HistoryStoreInterface
+}
+
+func TestSpillMessages_KeepsAllWithoutPagedStore(t *testing.T) {
+	store := &mockHistoryStoreForModel{}
+	m := newSpillTestModel(store, 6, 4)
+	m.historyStore = unpagedHistoryStore{store}
+	m.updateViewport()
+	m.viewport.GotoBottom()
+
+	updated, _ := m.Update(responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: "reply"}}}})
+	m = updated.(Model)
+
+	if len(m.messages) != 7 || m.hasSpilledMessages() {
+		t.Errorf("messages a store cannot page back must not be dropped, got %d in memory", len(m.messages))
+	}
+}
+
+func TestLoadOlderMessages_OnScrollUp(t *testing.T) {
+	store := &mockHistoryStoreForModel{}
+	m := newSpillTestModel(store, 60, 4)
+	m.updateViewport()
+	if len(m.messages) != 4 || m.messages[0].content != "message 56" {
+		t.Fatalf("expected messages 56-59 in memory, got %d starting at %q", len(m.messages), m.messages[0].content)
+	}
+
+	m.viewport.GotoTop()
+	updated, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = updated.(Model)
+
+	if len(store.getMessagesCalls) != 1 {
+		t.Fatalf("expected one store fetch, got %d", len(store.getMessagesCalls))
+	}
+	call := store.getMessagesCalls[0]
+	if call.id != "conv-1" || !call.before.Equal(store.storedMessages[56].Timestamp) || call.limit != messagePageSize+1 {
+		t.Errorf("GetMessagesBefore(%q, %v, %d), want message 56's time and limit %d", call.id, call.before, call.limit, messagePageSize+1)
+	}
+	first := 56 - messagePageSize
+	if len(m.messages) != 4+messagePageSize || !m.spilledBefore.Equal(store.storedMessages[first].Timestamp) {
+		t.Fatalf("got %d in memory, spilled before %v, after loading a page", len(m.messages), m.spilledBefore)
+	}
+	if m.messages[0].content != fmt.Sprintf("message %d", first) || m.messages[messagePageSize].content != "message 56" {
+		t.Errorf("older messages should be prepended in order, got %q then %q", m.messages[0].content, m.messages[messagePageSize].content)
+	}
+	if m.viewport.AtTop() {
+		t.Error("viewport should stay on the previously visible line")
+	}
+
+	// The last page is shorter than a full page
+	m.viewport.GotoTop()
+	updated, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.hasSpilledMessages() || len(m.messages) != 60 || m.messages[0].content != "message 0" {
+		t.Errorf("expected the whole conversation in memory, got %d starting at %q", len(m.messages), m.messages[0].content)
+	}
+}
+
+func TestLoadOlderMessages_UnstoredMessagesInMemory(t *testing.T) {
+	store := &mockHistoryStoreForModel{}
+	m := newSpillTestModel(store, 10, 4)
+	// A notice shown in the chat but never saved
+	notice := chatMessage{role: "tool", content: "notice", timestamp: m.messages[0].timestamp.Add(time.Millisecond)}
+	m.messages = append([]chatMessage{m.messages[0], notice}, m.messages[1:]...)
+	m.updateViewport()
+	if m.messages[0].content != "message 6" {
+		t.Fatalf("expected messages 6-9 after the spill, got %q first", m.messages[0].content)
+	}
+
+	m.viewport.GotoTop()
+	updated, _ := m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = updated.(Model)
+
+	var got []string
+	for _, msg := range m.messages {
+		got = append(got, msg.content)
+	}
+	want := "message 0,message 1,message 2,message 3,message 4,message 5,message 6,message 7,message 8,message 9"
+	if strings.Join(got, ",") != want {
+		t.Errorf("messages = %v, want each stored message once", got)
+	}
+}
+
+func TestSpillMessages_ShiftsToolStream(t *testing.T) {
+	store := &mockHistoryStoreForModel{}
+	m := newSpillTestModel(store, 4, 4)
+	m.updateViewport()
+	m.viewport.GotoBottom()
+
+	call := toolexec.ToolCall{Name: "bash", Args: map[string]any{"command": "ls"}}
+	stream := make(chan tea.Msg, 1)
+	m.handleToolOutputChunk(toolOutputChunkMsg{call: call, chunk: []byte("a\n"), stream: stream})
+	m.updateViewport()
+	if len(m.messages) != 4 || m.toolStream == nil || m.toolStream.index != 3 {
+		t.Fatalf("expected the tool message last after the spill, got %d messages and %+v", len(m.messages), m.toolStream)
+	}
+
+	m.handleToolOutputChunk(toolOutputChunkMsg{call: call, chunk: []byte("b\n"), stream: stream})
+	if got := m.messages[3].content; !strings.HasSuffix(got, "Output:\na\nb") {
+		t.Errorf("tool message = %q, want both chunks", got)
+	}
+	if got := m.messages[2].content; got != "message 3" {
+		t.Errorf("message before the tool message changed to %q", got)
+	}
+
+	// Loading older messages shifts it the other way
+	m.loadOlderMessages()
+	if len(m.messages) != 5 || m.toolStream == nil || m.toolStream.index != 4 {
+		t.Errorf("expected the tool stream to follow its message, got %+v in %d messages", m.toolStream, len(m.messages))
+	}
+}
+
+func TestNewChatModelWithConversation_LoadsRecentMessages(t *testing.T) {
+	store := &mockHistoryStoreForModel{}
+	conv := &history.Conversation{ID: "conv-1", Messages: storedTestMessages(defaultMessageCap + 10)}
+
+	m := NewChatModelWithConversation(nil, &mockChatSession{}, "test-model", conv, store)
+
+	if len(m.messages) != defaultMessageCap || !m.spilledBefore.Equal(conv.Messages[10].Timestamp) {
+		t.Fatalf("got %d in memory, spilled before %v", len(m.messages), m.spilledBefore)
+	}
+	if m.messages[0].content != "message 10" {
+		t.Errorf("first message in memory = %q, want message 10", m.messages[0].content)
+	}
+}
+
+func TestSwitchConversation_ResumableLoadsRecentMessages(t *testing.T) {
+	store := &mockFullHistoryStore{}
+	m := newSpillTestModel(&store.mockHistoryStoreForModel, 0, defaultMessageCap)
+	m.fullHistoryStore = store
+	m.historyStore = store
+	m.resumeMessages = 5
+	session := &mockChatSession{}
+	m.session = session
+
+	conv := &history.Conversation{ID: "conv-2", CID: "c_1", RID: "r_1", RCID: "rc_1", Messages: storedTestMessages(30)}
+	store.storedMessages = conv.Messages
+
+	updated, _ := m.switchConversation(conv)
+	m = updated.(Model)
+
+	if got := session.metadata; len(got) != 3 || got[0] != "c_1" || got[1] != "r_1" || got[2] != "rc_1" {
+		t.Errorf("session metadata = %v, want [c_1 r_1 rc_1]", got)
+	}
+	if len(m.messages) != 5 || !m.hasSpilledMessages() {
+		t.Fatalf("got %d in memory, want 5 and older ones spilled", len(m.messages))
+	}
+	if m.messages[0].content != "message 25" {
+		t.Errorf("first message in memory = %q, want message 25", m.messages[0].content)
+	}
+	if stored, _ := store.GetMessagesBefore(conv.ID, time.Now(), 0); len(stored) != 30 {
+		t.Errorf("store should still hold all 30 messages, got %d", len(stored))
+	}
+	m.viewport.GotoTop()
+	if view := ansiPattern.ReplaceAllString(m.viewport.View(), ""); !strings.Contains(view, "earlier messages · scroll up to load") {
+		t.Errorf("expected a hint for older history:\n%s", view)
+	}
+
+	// Scrolling up loads the older history from the store
+	updated, _ = m.Update(tea.MouseMsg{Button: tea.MouseButtonWheelUp, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if len(m.messages) != 30 || m.hasSpilledMessages() {
+		t.Errorf("got %d in memory after scrolling up, want all 30", len(m.messages))
+	}
+}
+
+func TestSwitchConversation_WithoutMetadataLoadsUpToCap(t *testing.T) {
+	store := &mockFullHistoryStore{}
+	m := newSpillTestModel(&store.mockHistoryStoreForModel, 0, defaultMessageCap)
+	m.historyStore = store
+	m.resumeMessages = 5
+	session := &mockChatSession{}
+	m.session = session
+
+	conv := &history.Conversation{ID: "conv-2"}
+	for i := 0; i < 30; i++ {
+		conv.Messages = append(conv.Messages, history.Message{Role: "user", Content: fmt.Sprintf("message %d", i)})
+	}
+
+	updated, _ := m.switchConversation(conv)
+	m = updated.(Model)
+
+	if len(m.messages) != 30 || m.hasSpilledMessages() {
+		t.Errorf("got %d in memory, want all 30 loaded", len(m.messages))
+	}
+	if session.metadata != nil {
+		t.Errorf("session metadata should be untouched, got %v", session.metadata)
+	}
+	m.viewport.GotoTop()
+	if strings.Contains(m.viewport.View(), "scroll up to load") {
+		t.Error("no hint expected without spilled messages")
+	}
+}
@@ -3,9 +3,11 @@ package tui
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +19,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/clipboard"
 	"github.com/diogo/geminiweb/internal/config"
 	apierrors "github.com/diogo/geminiweb/internal/errors"
 	"github.com/diogo/geminiweb/internal/history"
@@ -32,9 +35,20 @@ type animationTickMsg time.Time
 type (
 	responseMsg struct {
 		output *models.ModelOutput
+		// model names the alternate model that produced the reply (for /try)
+		model string
+		// continuation marks a reply to /continue, which is appended to the
+		// last assistant message
+		continuation bool
+		// regenerated marks a reply to /retry, which replaces the last
+		// assistant message
+		regenerated bool
 	}
 	errMsg struct {
 		err error
+		// The request that failed, if it was a send (for retrying)
+		prompt string
+		files  []*api.UploadedFile
 	}
 	toolExecutionMsg struct {
 		call   toolexec.ToolCall
@@ -60,9 +74,10 @@ type (
 	}
 	// downloadImagesResultMsg is sent when image download completes
 	downloadImagesResultMsg struct {
-		paths []string // Paths to downloaded images
-		count int      // Number of images downloaded
-		err   error    // Error, if any
+		paths   []string           // Paths to downloaded images
+		count   int                // Number of images downloaded
+		skipped []api.SkippedImage // Images skipped for unsupported types
+		err     error              // Error, if any
 	}
 	// initialPromptMsg is sent when an initial prompt from file needs to be processed
 	initialPromptMsg struct {
@@ -108,6 +123,25 @@ type FullHistoryStore interface {
 	ExportToJSON(id string) ([]byte, error)
 }
 
+// GemHistoryStore is implemented by history stores that can record the
+// gem used by a conversation so it can be restored on resume.
+type GemHistoryStore interface {
+	UpdateGem(id, gemID, gemName string) error
+}
+
+// PagedHistoryStore is implemented by history stores that can load a page
+// of messages stored before a time, so older messages can be dropped from
+// memory and loaded back when scrolling up.
+type PagedHistoryStore interface {
+	GetMessagesBefore(id string, before time.Time, limit int) ([]history.Message, error)
+}
+
+// ReplacingHistoryStore is implemented by history stores that can replace
+// the last stored message, so a /retry keeps only the regenerated reply.
+type ReplacingHistoryStore interface {
+	ReplaceLastMessage(id, role, content, thoughts string) error
+}
+
 // Model represents the TUI state
 type Model struct {
 	client    api.GeminiClientInterface
@@ -119,8 +153,21 @@ type Model struct {
 	textarea textarea.Model
 	spinner  spinner.Model
 
+	// renderCache memoizes rendered assistant messages across viewport updates
+	renderCache *messageRenderCache
+
+	// userMessageOffsets are the viewport lines where each user message
+	// starts, recorded by updateViewport (for Ctrl+Up/Ctrl+Down)
+	userMessageOffsets []int
+	// messageOffsets are the viewport lines where every message starts
+	// (for selecting a message to open in the pager)
+	messageOffsets []int
+
 	// State
 	messages       []chatMessage
+	messageCap     int       // Max messages kept in memory when persisted (0 = no limit)
+	spilledBefore  time.Time // Messages stored before this were dropped from memory (zero = none)
+	resumeMessages int       // Messages shown when switching to a resumable conversation (0 = messageCap)
 	loading        bool
 	ready          bool
 	err            error
@@ -130,10 +177,96 @@ type Model struct {
 	toolRegistry     toolexec.Registry
 	toolExecutor     toolexec.Executor
 	pendingToolCalls []toolexec.ToolCall
-	toolResultBlocks []string
+	toolResults      []*toolexec.ToolCallResult // Results of this turn's calls, re-sent together
 	confirmingTool   bool
 	toolConfirmCall  *toolexec.ToolCall
 	autoApproveTools bool
+	lastToolCall     *toolexec.ToolCall // Last executed call, for /rerun-tool
+	rerunningTool    bool               // The running call is a /rerun-tool, not sent to the model
+	trustedTools     map[string]bool    // Tools that never prompt for confirmation
+	maxToolCalls     int                // Max tool calls executed per response (0 = no limit)
+	// jsonToolResults re-sends tool results as one JSON array
+	// instead of a text block per result
+	jsonToolResults bool
+
+	// attachmentNote prepends a note describing attached files to prompts
+	attachmentNote bool
+
+	// ctrlEnterSends makes Ctrl+Enter send and Enter insert a newline
+	ctrlEnterSends bool
+
+	// lastSend is the last prompt sent from the input, to ignore duplicates
+	lastSend lastSend
+
+	// Cumulative reply characters this session against the configured
+	// budget (0 = no budget); going over it needs confirmation
+	outputBudget         int
+	outputUsed           int
+	outputBudgetOverride bool                 // Going over the budget was confirmed
+	heldSend             func(*Model) tea.Cmd // A send waiting for budget confirmation
+
+	// retryWordDiff shows a word diff against the replaced reply after /retry
+	retryWordDiff bool
+
+	// Per-reply token and cost estimates (/cost)
+	showCost        bool
+	costPer1KTokens float64 // From config; 0 = tokens only
+
+	// Confirmation before /clear and /delete discard work
+	confirmDestructive    bool   // Ask before destructive commands (from config)
+	skipDestructiveAsk    bool   // "Don't ask again" was chosen this session
+	pendingDestructiveCmd string // Command awaiting confirmation ("" = none)
+
+	// snippets are saved prompt templates inserted with /snippet <name>
+	snippets map[string]string
+
+	// attachURLHosts limits /attach-url downloads to these hosts (empty = none)
+	attachURLHosts []string
+	// urlFetcher downloads /attach-url resources (nil = default HTTP client)
+	urlFetcher httpDoer
+
+	// Standing instructions wrapped around user prompts (not displayed)
+	promptPrefix string
+	promptSuffix string
+
+	// showTimestamps renders a relative time next to each message label
+	showTimestamps bool
+
+	// compactMessages renders messages without bubble borders (/compact)
+	compactMessages bool
+
+	// maxContentWidth caps the width of the chat panels (0 = full width)
+	maxContentWidth int
+
+	// focusMode hides the header and status bar so the viewport gets
+	// their height
+	focusMode bool
+
+	// streamToolOutput shows streaming tool output live in the tool bubble
+	streamToolOutput bool
+	// toolStream is the tool message being streamed into, if any
+	toolStream *toolStreamState
+
+	// Debounced viewport updates (see requestViewportUpdate)
+	viewportDirty       bool
+	viewportTickPending bool
+	// viewportRenderer re-renders a debounced viewport (nil = render and
+	// scroll to the bottom)
+	viewportRenderer func(*Model)
+
+	// discardThoughts keeps thoughts out of saved history (display only)
+	discardThoughts bool
+
+	// thoughtsOff suppresses thinking output: not requested, shown or saved
+	thoughtsOff bool
+
+	// Tool plan review state (batch approval of multiple tool calls)
+	toolPlanReview bool
+	reviewingPlan  bool
+	planCalls      []toolexec.ToolCall
+	planSelected   []bool
+	planCursor     int
+	planDecisions  []bool // Per-call decisions for pendingToolCalls from an approved plan
 
 	// Gem selection state
 	selectingGem  bool
@@ -141,7 +274,15 @@ type Model struct {
 	gemsCursor    int
 	gemsLoading   bool
 	gemsFilter    string
-	activeGemName string // Name of currently active gem
+	activeGemName string        // Name of currently active gem
+	cachedGems    []*models.Gem // Last successfully fetched gems
+	gemsFetchErr  error         // Fetch failure while showing cachedGems
+
+	// Gem that was active before the current one (for /gem last);
+	// hasPreviousGem tells "no gem" apart from nothing recorded yet
+	previousGemID   string
+	previousGemName string
+	hasPreviousGem  bool
 
 	// History/conversation state
 	conversation *history.Conversation // Current conversation (nil for unsaved)
@@ -153,10 +294,14 @@ type Model struct {
 	historyCursor    int
 	historyLoading   bool
 	historyFilter    string
+	historyArchived  bool             // Show archived conversations in the list
 	fullHistoryStore FullHistoryStore // Full store interface for /history command
 
 	// File attachments (for /file and /image commands)
 	attachments []*api.UploadedFile
+	// sentAttachments are attachments already sent in this conversation
+	// (for /bundle)
+	sentAttachments []*api.UploadedFile
 
 	// Image download state (for /save command)
 	selectingImages bool
@@ -164,6 +309,36 @@ type Model struct {
 	lastOutput      *models.ModelOutput // Store last response for image access
 	downloadDir     string              // Directory for saving images
 
+	// Raw response overlay (for /raw-response command)
+	showingRaw  bool
+	rawViewport viewport.Model
+	rawTitle    string // Overlay title; also used by /expand
+
+	// Pending offer to retry with the fallback model after the selected
+	// model was rejected as unavailable
+	modelFallback *modelFallbackOffer
+
+	// Conversation diff overlay (for /diff command)
+	showingDiff  bool
+	diffViewport viewport.Model
+	diffTitle    string
+
+	// Message pager (Ctrl+O selects a message, Enter opens it)
+	selectingMessage bool
+	messageCursor    int
+	showingPager     bool
+	pager            pagerModel
+
+	// In-flight async operations, listed and cancelled with /jobs
+	jobs        *jobRegistry
+	showingJobs bool
+	jobsCursor  int
+
+	// Settings overlay (for /settings command)
+	showingSettings  bool
+	settingsCursor   int
+	settingsFeedback string
+
 	// Extension state
 	detectedExtension models.Extension // Extension detected in prompt (e.g., @Gmail)
 
@@ -173,6 +348,9 @@ type Model struct {
 	// Initial prompt to send automatically on start
 	initialPrompt string
 
+	// quitting is set once Ctrl+C started saving before exit
+	quitting bool
+
 	// Dimensions
 	width  int
 	height int
@@ -184,19 +362,40 @@ type chatMessage struct {
 	content  string
 	thoughts string
 	images   []models.WebImage // Images from ModelOutput (for assistant messages)
+	sources  []models.Source   // Citation sources from ModelOutput (for assistant messages)
+	format   string            // Output format of a tool result (for tool messages)
+	model    string            // Alternate model that produced the reply (for /try)
+	changes  []Segment         // Word diff against the reply this one replaced via /retry
+
+	// toolCall is the structured call and result (for tool messages run in
+	// this session), included in JSON exports
+	toolCall *toolexec.ToolCallResult
+
+	// streaming is set while a tool message is receiving live output, and
+	// duration is how long the tool ran once it finished (streamed tools)
+	streaming bool
+	duration  time.Duration
+
+	// timestamp is when the message was added (or stored, for loaded history)
+	timestamp time.Time
+
+	// attachments are the files sent with a user message in this session,
+	// sent again by /retry
+	attachments []*api.UploadedFile
 }
 
 // createTextarea creates and configures a textarea for multi-line input
 // Enter sends the message, \ + Enter inserts a newline (line continuation)
 func createTextarea() textarea.Model {
 	ta := textarea.New()
-	ta.Placeholder = "Type your message... (\\ + Enter for newline)"
+	ta.Placeholder = inputPlaceholder(config.SendKeyEnter)
 	ta.CharLimit = 4000
 	ta.ShowLineNumbers = false
 	ta.SetHeight(3) // Multi-line input support
 	ta.Focus()
 
-	// Disable default InsertNewline binding - we handle newlines via \ + Enter in Update()
+	// Disable default InsertNewline binding - we handle newlines in Update()
+	// according to the send key
 	ta.KeyMap.InsertNewline = key.NewBinding(
 		key.WithKeys(""), // Disabled - handled manually
 	)
@@ -219,35 +418,75 @@ func NewChatModel(client api.GeminiClientInterface, modelName string) Model {
 	s.Spinner = spinner.Points
 	s.Style = loadingStyle
 
-	toolRegistry := defaultToolRegistry()
-	toolExecutor := defaultToolExecutor(toolRegistry)
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		cfg = config.DefaultConfig()
 	}
 
-	return Model{
-		client:           client,
-		session:          client.StartChat(),
-		modelName:        modelName,
-		textarea:         ta,
-		spinner:          s,
-		messages:         []chatMessage{},
-		toolRegistry:     toolRegistry,
-		toolExecutor:     toolExecutor,
-		autoApproveTools: cfg.AutoApproveTools,
-	}
+	m := Model{
+		client:         client,
+		session:        client.StartChat(),
+		modelName:      modelName,
+		textarea:       ta,
+		spinner:        s,
+		messages:       []chatMessage{},
+		messageCap:     defaultMessageCap,
+		resumeMessages: defaultResumeMessages,
+		jobs:           newJobRegistry(),
+	}
+	applyConfig(&m, cfg)
+
+	return m
 }
 
-func defaultToolRegistry() toolexec.Registry {
-	return toolexec.NewRegistryWithOptions(
+// applyConfig sets up the model's tooling and the settings that come from
+// cfg. The constructors call it after creating the model.
+func applyConfig(m *Model, cfg config.Config) {
+	SetNoEmoji(cfg.NoEmoji)
+	m.textarea.Placeholder = inputPlaceholder(cfg.SendKey)
+
+	m.toolRegistry = defaultToolRegistry(cfg)
+	m.toolExecutor = defaultToolExecutor(m.toolRegistry)
+	m.autoApproveTools = cfg.AutoApproveTools
+	m.attachmentNote = cfg.AttachmentNote
+	m.toolPlanReview = cfg.ToolPlanReview
+	m.trustedTools = trustedToolSet(cfg.AlwaysTrustedTools)
+	m.showTimestamps = cfg.ShowTimestamps
+	m.compactMessages = cfg.CompactMessages
+	m.discardThoughts = !cfg.StoreThoughts
+	m.maxToolCalls = cfg.MaxToolCallsPerTurn
+	m.promptPrefix = cfg.PromptPrefix
+	m.promptSuffix = cfg.PromptSuffix
+	m.streamToolOutput = cfg.StreamToolOutput
+	m.maxContentWidth = cfg.MaxContentWidth
+	m.attachURLHosts = cfg.AttachURLHosts
+	m.snippets = cfg.Snippets
+	m.confirmDestructive = cfg.ConfirmDestructive
+	m.retryWordDiff = cfg.RetryWordDiff
+	m.jsonToolResults = cfg.StructuredToolResults
+	m.outputBudget = cfg.OutputBudget
+	m.costPer1KTokens = cfg.CostPer1KTokens
+	m.ctrlEnterSends = cfg.SendKey == config.SendKeyCtrlEnter
+}
+
+// defaultToolRegistry returns the registry of built-in tools, with
+// list_tools included when the config enables it
+func defaultToolRegistry(cfg config.Config) toolexec.Registry {
+	opts := []toolexec.RegistryOption{
 		toolexec.WithTools(
 			toolexec.NewBashTool(),
 			toolexec.NewFileReadTool(),
 			toolexec.NewFileWriteTool(),
+			toolexec.NewEditTool(),
+			toolexec.NewMultiFileWriteTool(),
 			toolexec.NewSearchTool(),
+			toolexec.NewCalcTool(),
 		),
-	)
+	}
+	if cfg.ListTools {
+		opts = append(opts, toolexec.WithListToolsTool())
+	}
+	return toolexec.NewRegistryWithOptions(opts...)
 }
 
 func defaultToolExecutor(registry toolexec.Registry) toolexec.Executor {
@@ -258,13 +497,16 @@ func defaultToolExecutor(registry toolexec.Registry) toolexec.Executor {
 	)
 }
 
-func (m *Model) ensureTooling() {
-	if m.toolRegistry == nil {
-		m.toolRegistry = defaultToolRegistry()
-	}
-	if m.toolExecutor == nil {
-		m.toolExecutor = defaultToolExecutor(m.toolRegistry)
+// trustedToolSet converts the configured list of always-trusted tools
+// into a set, ignoring blank names.
+func trustedToolSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			set[name] = true
+		}
 	}
+	return set
 }
 
 // Init initializes the model
@@ -274,6 +516,11 @@ func (m Model) Init() tea.Cmd {
 		m.spinner.Tick,
 	}
 
+	// Check connectivity and auth up front
+	if m.client != nil {
+		cmds = append(cmds, m.preflight())
+	}
+
 	// If there's an initial prompt, send it automatically
 	if m.initialPrompt != "" {
 		cmds = append(cmds, m.sendInitialPrompt())
@@ -294,11 +541,75 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 	var cmd tea.Cmd
 
+	// Pending state was saved after Ctrl+C; overlays must not swallow this
+	if _, ok := msg.(savedBeforeQuitMsg); ok {
+		return m, tea.Quit
+	}
+	// Likewise a debounce window must always close, or updates would stall
+	if _, ok := msg.(viewportTickMsg); ok {
+		m.flushViewportUpdate()
+		return m, nil
+	}
+
+	// Handle model fallback offer (answered with y/n)
+	if m.modelFallback != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateModelFallback(keyMsg)
+		}
+	}
+
+	// Handle a send held by the output budget (answered with y/n)
+	if m.heldSend != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateOutputBudget(keyMsg)
+		}
+	}
+
 	// Handle tool confirmation mode
 	if m.confirmingTool {
 		return m.updateToolConfirmation(msg)
 	}
 
+	// Handle destructive command confirmation
+	if m.pendingDestructiveCmd != "" {
+		return m.updateDestructiveConfirmation(msg)
+	}
+
+	// Handle tool plan review mode
+	if m.reviewingPlan {
+		return m.updateToolPlan(msg)
+	}
+
+	// Handle raw response overlay
+	if m.showingRaw {
+		return m.updateRawResponse(msg)
+	}
+
+	// Handle jobs overlay
+	if m.showingJobs {
+		return m.updateJobs(msg)
+	}
+
+	// Handle conversation diff overlay
+	if m.showingDiff {
+		return m.updateDiffView(msg)
+	}
+
+	// Handle message pager
+	if m.showingPager {
+		return m.updatePager(msg)
+	}
+
+	// Handle selecting a message for the pager
+	if m.selectingMessage {
+		return m.updateMessageSelection(msg)
+	}
+
+	// Handle settings overlay
+	if m.showingSettings {
+		return m.updateSettings(msg)
+	}
+
 	// Handle gem selection mode
 	if m.selectingGem {
 		return m.updateGemSelection(msg)
@@ -319,18 +630,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Calculate component heights
-		headerHeight := 4 // Header panel with border
-		inputHeight := 7  // Input panel with border (includes multi-line textarea)
-		statusHeight := 1 // Status bar
-		padding := 2      // Extra spacing
-
-		vpHeight := m.height - headerHeight - inputHeight - statusHeight - padding
-		if vpHeight < 5 {
-			vpHeight = 5
-		}
-
-		contentWidth := m.width - 4
+		vpHeight := m.viewportHeight()
+		contentWidth := m.contentWidth()
 
 		// Initialize viewport on first size message
 		if !m.ready {
@@ -345,9 +646,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateViewport()
 
 	case tea.KeyMsg:
-		switch msg.String() {
+		switch m.inputKey(msg.String()) {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.handleInterrupt()
 
 		case "esc":
 			if m.loading {
@@ -365,16 +666,62 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.gemsFilter = ""
 			return m, m.loadGemsForChat()
 
+		case "alt+g":
+			// Shortcut to switch back to the previous gem (same as /gem last)
+			return m.restorePreviousGem()
+
+		case "ctrl+l":
+			// Dismiss the current error/feedback and redraw the screen
+			m.err = nil
+			return m, tea.ClearScreen
+
+		case "ctrl+up":
+			// Jump to the previous user message
+			m.jumpToUserMessage(-1)
+			return m, nil
+
+		case "ctrl+down":
+			// Jump to the next user message
+			m.jumpToUserMessage(1)
+			return m, nil
+
+		case "ctrl+o":
+			// Select a message to open in the full-screen pager
+			return m.handleOpenPager()
+
+		case "ctrl+f":
+			// Shortcut to toggle focus mode (same as /focus)
+			return m.toggleFocusMode(), nil
+
 		case "ctrl+e":
 			// Shortcut to export conversation (same as /export without args)
 			return m.handleExportCommand("")
 
+		case "ctrl+shift+c", "alt+c":
+			// Shortcut to copy the conversation as markdown (same as /copy-all).
+			// Most terminals send Ctrl+Shift+C as plain Ctrl+C, so Alt+C is the
+			// portable binding.
+			return m.handleCopyAllCommand("")
+
+		case "alt+C":
+			// Alt+Shift+C copies the conversation as plain text
+			// (same as /copy-all --plain)
+			return m.handleCopyAllCommand("--plain")
+
+		case "alt+r":
+			// Shortcut to re-run the last tool call (same as /rerun-tool)
+			return m.handleRerunToolCommand("")
+
+		case inputNewlineKey:
+			m.textarea.InsertString("\n")
+			return m, nil
+
 		case "enter":
 			if !m.loading {
 				rawInput := m.textarea.Value()
 
 				// Check for line continuation: if line ends with \, insert newline instead of sending
-				if strings.HasSuffix(rawInput, "\\") {
+				if !m.ctrlEnterSends && strings.HasSuffix(rawInput, "\\") {
 					// Remove the trailing backslash and insert a newline
 					m.textarea.SetValue(strings.TrimSuffix(rawInput, "\\") + "\n")
 					// Move cursor to end
@@ -397,16 +744,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						return m, tea.Quit
 
 					case "gems", "gem":
-						m.textarea.Reset()
-						m.selectingGem = true
-						m.gemsLoading = true
-						m.gemsCursor = 0
-						m.gemsFilter = ""
-						return m, m.loadGemsForChat()
+						return m.handleGemCommand(parsed.Args)
 
 					case "history", "hist":
 						if m.fullHistoryStore == nil {
-							m.err = fmt.Errorf("history not available")
+							m.err = m.historyUnavailable()
 							return m, nil
 						}
 						m.textarea.Reset()
@@ -414,12 +756,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.historyLoading = true
 						m.historyCursor = 0
 						m.historyFilter = ""
+						m.historyArchived = false
 						return m, m.loadHistoryForChat()
 
+					case "archive":
+						return m.handleArchiveCommand(true)
+
+					case "unarchive":
+						return m.handleArchiveCommand(false)
+
 					case "manage":
 						// Open full history manager
 						if m.fullHistoryStore == nil {
-							m.err = fmt.Errorf("history not available")
+							m.err = m.historyUnavailable()
 							return m, nil
 						}
 						m.textarea.Reset()
@@ -438,7 +787,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					case "favorite", "fav":
 						// Toggle favorite status of current conversation
 						if m.fullHistoryStore == nil {
-							m.err = fmt.Errorf("history not available")
+							m.err = m.historyUnavailable()
 							return m, nil
 						}
 						if m.conversation == nil {
@@ -462,19 +811,90 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					case "file":
 						return m.handleFileCommand(parsed.Args)
 
+					case "attach-url":
+						return m.handleAttachURLCommand(parsed.Args)
+
 					case "image":
 						return m.handleImageCommand(parsed.Args)
 
 					case "clear":
-						// Clear all attachments
-						m.attachments = nil
-						m.textarea.Reset()
-						m.err = nil
-						return m, nil
+						return m.handleClearCommand()
+
+					case "delete":
+						return m.handleDeleteCommand(parsed.Args)
 
 					case "export":
 						return m.handleExportCommand(parsed.Args)
 
+					case "copy-all":
+						return m.handleCopyAllCommand(parsed.Args)
+
+					case "copy-json":
+						return m.handleCopyJSONCommand(parsed.Args)
+
+					case "plan":
+						return m.handlePlanCommand(parsed.Args)
+
+					case "raw-response":
+						return m.handleRawResponseCommand(parsed.Args)
+
+					case "preview":
+						return m.handlePreviewCommand(parsed.Args)
+
+					case "expand":
+						return m.handleExpandCommand(parsed.Args)
+
+					case "timestamps":
+						return m.handleTimestampsCommand(parsed.Args)
+
+					case "compact":
+						return m.handleCompactCommand(parsed.Args)
+
+					case "thoughts":
+						return m.handleThoughtsCommand(parsed.Args)
+
+					case "cost":
+						return m.handleCostCommand(parsed.Args)
+
+					case "whoami":
+						return m.handleWhoamiCommand(parsed.Args)
+
+					case "debug-copy":
+						return m.handleDebugCopyCommand(parsed.Args)
+
+					case "rerun-tool":
+						return m.handleRerunToolCommand(parsed.Args)
+
+					case "diff":
+						return m.handleDiffCommand(parsed.Args)
+
+					case "try":
+						return m.handleTryCommand(parsed.Args)
+
+					case "retry":
+						return m.handleRetryCommand(parsed.Args)
+
+					case "bundle":
+						return m.handleBundleCommand(parsed.Args)
+
+					case "settings":
+						return m.handleSettingsCommand(parsed.Args)
+
+					case "continue":
+						return m.handleContinueCommand(parsed.Args)
+
+					case "save-config":
+						return m.handleSaveConfigCommand(parsed.Args)
+
+					case "snippet":
+						return m.handleSnippetCommand(parsed.Args)
+
+					case "focus":
+						return m.handleFocusCommand()
+
+					case "jobs":
+						return m.handleJobsCommand(parsed.Args)
+
 					case "save", "download":
 						return m.handleSaveCommand(parsed.Args)
 
@@ -504,10 +924,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, tea.Quit
 				}
 
+				// A reply dismissed with Esc may still be on its way; keep
+				// the input so it can be sent once that finishes
+				if m.isBusy() {
+					m.err = errBusy
+					return m, nil
+				}
+
+				// A flaky terminal can deliver the same submission twice
+				now := time.Now()
+				if m.isDuplicateSend(input, now) {
+					m.textarea.Reset()
+					m.err = errDuplicateSend
+					return m, nil
+				}
+
+				// Hold the send once the output budget is used up
+				if m.holdForOutputBudget(resendMsg(msg)) {
+					return m, nil
+				}
+
 				// Add user message
 				m.messages = append(m.messages, chatMessage{
-					role:    "user",
-					content: input,
+					role:        "user",
+					content:     input,
+					timestamp:   time.Now(),
+					attachments: m.attachments,
 				})
 				m.updateViewport()
 				m.viewport.GotoBottom()
@@ -531,8 +973,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				// Send message with attachments
 				cmd = m.sendMessageWithAttachments(userMsg)
+				m.recordSend(input, now)
 
 				// Clear attachments after sending
+				m.sentAttachments = append(m.sentAttachments, m.attachments...)
 				m.attachments = nil
 
 				return m, tea.Batch(
@@ -544,13 +988,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case gemsLoadedForChatMsg:
-		m.gemsLoading = false
-		if msg.err != nil {
-			m.selectingGem = false
-			m.err = msg.err
-		} else {
-			m.gemsList = msg.gems
-		}
+		m.applyLoadedGems(msg)
 
 	case historyLoadedForChatMsg:
 		m.historyLoading = false
@@ -569,6 +1007,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.attachments = append(m.attachments, msg.file)
 			// Show success feedback (could use a toast/notification style)
 			m.err = nil
+			if msg.file.Warning != "" {
+				m.err = fmt.Errorf("%s: %s", msg.file.FileName, msg.file.Warning)
+			}
+		}
+
+	case preflightMsg:
+		m.handlePreflight(msg)
+
+	case urlAttachedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			m.attachments = append(m.attachments, msg.file)
+			m.err = fmt.Errorf("✓ Attached %s from %s", msg.file.FileName, msg.url)
+			if msg.file.Warning != "" {
+				m.err = fmt.Errorf("%s: %s", msg.file.FileName, msg.file.Warning)
+			}
 		}
 
 	case exportResultMsg:
@@ -583,30 +1038,47 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = fmt.Errorf("%s", feedback)
 		}
 
+	case bundleResultMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		} else {
+			feedback := fmt.Sprintf("✓ Bundle written to %s (%d item(s)", msg.dir, msg.written)
+			if msg.failed > 0 {
+				feedback += fmt.Sprintf(", %d failed - see manifest.json", msg.failed)
+			}
+			m.err = fmt.Errorf("%s)", feedback)
+		}
+
 	case downloadImagesResultMsg:
 		if msg.err != nil {
 			m.err = msg.err
 		} else if msg.count > 0 {
-			m.err = fmt.Errorf("✓ Downloaded %d image(s) to %s", msg.count, m.imageSelector.TargetDir())
+			m.err = fmt.Errorf("✓ Downloaded %d image(s) to %s%s", msg.count, m.imageSelector.TargetDir(), skippedImagesNote(msg.skipped))
+		} else if len(msg.skipped) > 0 {
+			m.err = fmt.Errorf("no images were downloaded%s", skippedImagesNote(msg.skipped))
 		} else {
 			m.err = fmt.Errorf("no images were downloaded")
 		}
 
+	case toolOutputChunkMsg:
+		cmds = append(cmds, m.handleToolOutputChunk(msg), m.requestViewportUpdate())
+
 	case toolExecutionMsg:
-		cmd = m.handleToolResult(msg.call, msg.result)
-		if cmd != nil {
+		if cmd = m.handleToolResult(msg.call, msg.result); cmd != nil {
 			cmds = append(cmds, cmd)
-			if m.loading {
-				cmds = append(cmds, animationTick())
-			}
 		}
 
 	case responseMsg:
 		m.loading = false
+		m.restartDuplicateWindow(time.Now())
 		m.lastOutput = msg.output // Store for /save command
-		responseText := msg.output.Text()
+		responseText := responseDisplayText(msg.output)
 		thoughts := msg.output.Thoughts()
+		if m.thoughtsOff {
+			thoughts = ""
+		}
 		images := msg.output.Images()
+		sources := msg.output.Sources()
 		toolCalls, cleanText := toolexec.ExtractToolCallsLenient(responseText)
 		displayText := responseText
 		if len(toolCalls) > 0 {
@@ -614,38 +1086,71 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 		if strings.TrimSpace(displayText) != "" || thoughts != "" || len(images) > 0 {
-			m.messages = append(m.messages, chatMessage{
-				role:     "assistant",
-				content:  displayText,
-				thoughts: thoughts,
-				images:   images,
-			})
+			reply := chatMessage{
+				role:      "assistant",
+				content:   displayText,
+				thoughts:  thoughts,
+				images:    images,
+				sources:   sources,
+				model:     msg.model,
+				timestamp: time.Now(),
+			}
+			// A continuation or regeneration updates the reply it follows
+			last := len(m.messages) - 1
+			updatesLast := (msg.continuation || msg.regenerated) && last >= 0 && m.messages[last].role == "assistant"
+			switch {
+			case updatesLast && msg.continuation:
+				m.messages[last] = mergeContinuation(m.messages[last], reply)
+			case updatesLast:
+				m.messages[last] = m.replaceRegenerated(m.messages[last], reply)
+			default:
+				m.messages = append(m.messages, reply)
+			}
+			shown := m.messages[len(m.messages)-1]
+			m.trackOutput(reply)
+			if len(toolCalls) == 0 && looksTruncated(shown.content) {
+				m.err = fmt.Errorf("%s", continueHint)
+			}
 			m.updateViewport()
 			m.viewport.GotoBottom()
 
-			// Auto-save assistant message to history
-			m.saveMessageToHistory("assistant", displayText, thoughts)
+			// Auto-save the assistant message to history as displayed,
+			// replacing the stored reply it updates
+			if updatesLast {
+				m.replaceMessageInHistory("assistant", shown.content, shown.thoughts)
+			} else {
+				m.saveMessageToHistory("assistant", shown.content, shown.thoughts)
+			}
 		}
 
 		// Update conversation metadata for session resumption
 		m.saveMetadataToHistory()
 
 		if len(toolCalls) > 0 {
-			m.ensureTooling()
-			m.pendingToolCalls = toolCalls
-			m.toolResultBlocks = nil
-			cmd = m.startNextToolCall()
-			if cmd != nil {
-				cmds = append(cmds, cmd)
-				if m.loading {
-					cmds = append(cmds, animationTick())
+			toolCalls = m.limitToolCalls(toolCalls)
+			m.planDecisions = nil
+			if m.shouldReviewToolPlan(toolCalls) {
+				// Review the whole batch before anything runs
+				m.pendingToolCalls = nil
+				m.startToolPlanReview(toolCalls)
+			} else {
+				m.pendingToolCalls = toolCalls
+				m.toolResults = nil
+				cmd = m.startNextToolCall()
+				if cmd != nil {
+					cmds = append(cmds, cmd)
+					if m.loading {
+						cmds = append(cmds, animationTick())
+					}
 				}
 			}
 		}
 
 	case errMsg:
 		m.loading = false
+		m.restartDuplicateWindow(time.Now())
 		m.err = msg.err
+		m.offerModelFallback(msg)
 
 	case spinner.TickMsg:
 		if m.loading {
@@ -662,17 +1167,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case initialPromptMsg:
 		// Process initial prompt from file as if user typed it
 		prompt := msg.prompt
+		if m.isBusy() {
+			// Keep the prompt in the input to send once the reply is in
+			m.textarea.SetValue(prompt)
+			m.err = fmt.Errorf("initial prompt not sent: %w", errBusy)
+			return m, nil
+		}
+		if m.holdForOutputBudget(resendMsg(msg)) {
+			return m, nil
+		}
 
 		// Apply persona system prompt if set
 		finalPrompt := prompt
 		if m.persona != nil && m.persona.SystemPrompt != "" {
 			finalPrompt = config.FormatSystemPrompt(m.persona, prompt)
 		}
+		finalPrompt = m.wrapPrompt(finalPrompt)
 
 		// Add user message to chat
 		m.messages = append(m.messages, chatMessage{
-			role:    "user",
-			content: prompt, // Show original prompt, not with system prompt
+			role:      "user",
+			content:   prompt, // Show original prompt, not with system prompt
+			timestamp: time.Now(),
 		})
 
 		// Save to history if available
@@ -700,6 +1216,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	m.viewport, cmd = m.viewport.Update(msg)
 	cmds = append(cmds, cmd)
 
+	// Scrolling up past the oldest message in memory loads spilled ones
+	switch msg.(type) {
+	case tea.KeyMsg, tea.MouseMsg:
+		if m.hasSpilledMessages() && m.viewport.AtTop() {
+			m.loadOlderMessages()
+		}
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -713,6 +1237,34 @@ func (m Model) View() string {
 		return m.renderToolConfirmation()
 	}
 
+	if m.pendingDestructiveCmd != "" {
+		return m.renderDestructiveConfirmation()
+	}
+
+	if m.reviewingPlan {
+		return m.renderToolPlan()
+	}
+
+	if m.showingRaw {
+		return m.renderRawResponse()
+	}
+
+	if m.showingJobs {
+		return m.renderJobs()
+	}
+
+	if m.showingDiff {
+		return m.renderDiffView()
+	}
+
+	if m.showingPager {
+		return m.pager.View()
+	}
+
+	if m.showingSettings {
+		return m.renderSettings()
+	}
+
 	// If selecting gem, show the gem selector overlay
 	if m.selectingGem {
 		return m.renderGemSelector()
@@ -729,26 +1281,14 @@ func (m Model) View() string {
 	}
 
 	var sections []string
-	contentWidth := m.width - 4
+	contentWidth := m.contentWidth()
 
 	// ═══════════════════════════════════════════════════════════════
 	// HEADER
 	// ═══════════════════════════════════════════════════════════════
-	headerParts := []string{
-		titleStyle.Render("✦ Gemini Chat"),
-		hintStyle.Render("  •  "),
-		subtitleStyle.Render(m.modelName),
+	if !m.focusMode {
+		sections = append(sections, m.renderHeader(contentWidth))
 	}
-	// Show active gem if set
-	if m.activeGemName != "" {
-		headerParts = append(headerParts,
-			hintStyle.Render("  •  "),
-			configValueStyle.Render("📦 "+m.activeGemName),
-		)
-	}
-	headerContent := lipgloss.JoinHorizontal(lipgloss.Center, headerParts...)
-	header := headerStyle.Width(contentWidth).Render(headerContent)
-	sections = append(sections, header)
 
 	// ═══════════════════════════════════════════════════════════════
 	// MESSAGES AREA
@@ -771,14 +1311,17 @@ func (m Model) View() string {
 	// INPUT AREA
 	// ═══════════════════════════════════════════════════════════════
 	var inputContent string
-	if m.loading {
+	panelStyle := inputPanelStyle
+	if m.selectingMessage {
+		inputContent = hintStyle.Render(fmt.Sprintf("Message %d/%d · ↑/↓: select • enter: open in pager • esc: cancel", m.messageCursor+1, len(m.messages)))
+	} else if m.loading {
 		// Use colorful animated loading indicator
 		inputContent = m.renderLoadingAnimation()
 	} else {
 		// Build label with attachment indicator
 		label := "You"
 		if len(m.attachments) > 0 {
-			attachmentInfo := fmt.Sprintf(" 📎 %d file", len(m.attachments))
+			attachmentInfo := fmt.Sprintf(" %s %d file", icon("📎", "[attach]"), len(m.attachments))
 			if len(m.attachments) > 1 {
 				attachmentInfo += "s"
 			}
@@ -790,16 +1333,27 @@ func (m Model) View() string {
 			inputLabelStyle.Render(label),
 			m.textarea.View(),
 		)
+
+		// Warn about input the tool security policy would block
+		if risk := classifyInputRisk(m.textarea.Value()); risk != RiskNone {
+			panelStyle = panelStyle.BorderForeground(risk.color())
+			inputContent = lipgloss.JoinVertical(
+				lipgloss.Left,
+				inputContent,
+				lipgloss.NewStyle().Foreground(risk.color()).Render(risk.warning()),
+			)
+		}
 	}
 
-	inputPanel := inputPanelStyle.Width(contentWidth).Render(inputContent)
+	inputPanel := panelStyle.Width(contentWidth).Render(inputContent)
 	sections = append(sections, inputPanel)
 
 	// ═══════════════════════════════════════════════════════════════
 	// STATUS BAR
 	// ═══════════════════════════════════════════════════════════════
-	statusBar := m.renderStatusBar(contentWidth)
-	sections = append(sections, statusBar)
+	if !m.focusMode {
+		sections = append(sections, m.renderStatusBar(contentWidth))
+	}
 
 	// ═══════════════════════════════════════════════════════════════
 	// ERROR DISPLAY
@@ -809,15 +1363,43 @@ func (m Model) View() string {
 		sections = append(sections, errorDisplay)
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	view := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	if contentWidth < m.width-4 {
+		// Capped panels are centered in the wider terminal
+		view = lipgloss.PlaceHorizontal(m.width, lipgloss.Center, view)
+	}
+	return view
 }
 
-func (m Model) renderToolConfirmation() string {
-	width := m.width - 8
-	if width < 40 {
-		width = 40
+// contentWidth returns the width of the chat panels: the terminal width
+// less the borders, capped at maxContentWidth when set
+func (m Model) contentWidth() int {
+	width := m.width - 4
+	if m.maxContentWidth > 0 && width > m.maxContentWidth {
+		width = m.maxContentWidth
+	}
+	return width
+}
+
+// renderHeader renders the header panel with the model and active gem
+func (m Model) renderHeader(contentWidth int) string {
+	headerParts := []string{
+		titleStyle.Render(icon("✦", "*") + " Gemini Chat"),
+		hintStyle.Render("  •  "),
+		subtitleStyle.Render(m.modelName),
+	}
+	// Show active gem if set
+	if m.activeGemName != "" {
+		headerParts = append(headerParts,
+			hintStyle.Render("  •  "),
+			configValueStyle.Render(icon("📦", "[gem]")+" "+m.activeGemName),
+		)
 	}
+	headerContent := lipgloss.JoinHorizontal(lipgloss.Center, headerParts...)
+	return headerStyle.Width(contentWidth).Render(headerContent)
+}
 
+func (m Model) renderToolConfirmation() string {
 	var content strings.Builder
 	content.WriteString("Tool execution requested\n\n")
 
@@ -833,6 +1415,20 @@ func (m Model) renderToolConfirmation() string {
 			content.WriteString(call.Reason)
 		}
 
+		if m.toolRegistry != nil {
+			if tool, err := m.toolRegistry.Get(call.Name); err == nil {
+				if summarizer, ok := tool.(toolexec.ConfirmationSummarizer); ok {
+					summary := summarizer.ConfirmationSummary(call.Args)
+					if render.IsUnifiedDiff(summary) {
+						// Previews of file changes read better colorized
+						summary = render.Diff(summary, max(m.width-10, 38))
+					}
+					content.WriteString("\n")
+					content.WriteString(summary)
+				}
+			}
+		}
+
 		if len(call.Args) > 0 {
 			if data, err := json.MarshalIndent(call.Args, "", "  "); err == nil {
 				content.WriteString("\nArgs:\n")
@@ -843,7 +1439,17 @@ func (m Model) renderToolConfirmation() string {
 
 	content.WriteString("\n\nConfirm execution? (y/n)")
 
-	panel := messagesAreaStyle.Width(width).Render(content.String())
+	return m.placeConfirmation(content.String())
+}
+
+// placeConfirmation renders a confirmation prompt panel centered on screen
+func (m Model) placeConfirmation(content string) string {
+	width := m.width - 8
+	if width < 40 {
+		width = 40
+	}
+
+	panel := messagesAreaStyle.Width(width).Render(content)
 	if m.width > 0 && m.height > 0 {
 		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
 	}
@@ -855,14 +1461,14 @@ func (m Model) renderWelcome() string {
 	width := m.viewport.Width - 4
 	height := m.viewport.Height
 
-	icon := welcomeIconStyle.Width(width).Render("✦")
+	logo := welcomeIconStyle.Width(width).Render(icon("✦", "*"))
 	title := welcomeTitleStyle.Width(width).Render("Welcome to Gemini Chat")
 	subtitle := welcomeStyle.Width(width).Render("Start a conversation by typing a message below")
 
 	content := lipgloss.JoinVertical(
 		lipgloss.Center,
 		"",
-		icon,
+		logo,
 		"",
 		title,
 		"",
@@ -925,20 +1531,32 @@ func (m Model) renderLoadingAnimation() string {
 
 // renderStatusBar renders the bottom status bar with shortcuts
 func (m Model) renderStatusBar(width int) string {
+	sendKey, newlineKey := "Enter", "\\+Enter"
+	if m.ctrlEnterSends {
+		sendKey, newlineKey = "^Enter", "Enter"
+	}
 	shortcuts := []struct {
 		key  string
 		desc string
 	}{
-		{"Enter", "Send"},
-		{"\\+Enter", "Newline"},
+		{sendKey, "Send"},
+		{newlineKey, "Newline"},
 		{"^E", "Export"},
 		{"^G", "Gems"},
+		{"^L", "Clear"},
 		{"Esc", "Quit"},
 		{"↑↓", "Scroll"},
+		{"^↑↓", "Turns"},
+		{"^O", "Pager"},
 	}
 
 	var items []string
 
+	// Warn when the output budget is nearly used up
+	if warning := m.outputBudgetWarning(); warning != "" {
+		items = append(items, statusWarnStyle.Render(warning))
+	}
+
 	// Show extension indicator if one was detected
 	if m.detectedExtension != "" {
 		extIndicator := lipgloss.NewStyle().
@@ -963,33 +1581,84 @@ func (m Model) renderStatusBar(width int) string {
 
 // sendMessage creates a command to send a message to the API
 func (m Model) sendMessage(prompt string) tea.Cmd {
+	return m.sendPrompt(prompt, nil)
+}
+
+// sendPrompt creates a command that sends a final prompt and files as-is.
+// A failed send reports the request so it can be retried.
+func (m Model) sendPrompt(prompt string, files []*api.UploadedFile) tea.Cmd {
+	session := m.session
+	jobs := m.jobs
 	return func() tea.Msg {
-		output, err := m.session.SendMessage(prompt, nil)
+		output, err := sendToModel(jobs, session, prompt, files)
 		if err != nil {
-			return errMsg{err: err}
+			return errMsg{err: err, prompt: prompt, files: files}
 		}
 		return responseMsg{output: output}
 	}
 }
 
+// wrapPrompt adds the configured prompt prefix and suffix around a user
+// prompt. Tool results sent back to the model are not wrapped.
+func (m Model) wrapPrompt(prompt string) string {
+	if m.promptPrefix != "" {
+		prompt = m.promptPrefix + "\n\n" + prompt
+	}
+	if m.promptSuffix != "" {
+		prompt = prompt + "\n\n" + m.promptSuffix
+	}
+	return prompt
+}
+
+// assemblePrompt builds the prompt sent for user input with the given
+// attachments (see /preview)
+func (m Model) assemblePrompt(prompt string, attachments []*api.UploadedFile) string {
+	// Describe attachments in the prompt if enabled, so they are not ignored
+	finalPrompt := prompt
+	if m.attachmentNote {
+		finalPrompt = withAttachmentNote(finalPrompt, attachments)
+	}
+
+	// Apply persona system prompt if set
+	if m.persona != nil && m.persona.SystemPrompt != "" {
+		finalPrompt = config.FormatSystemPrompt(m.persona, finalPrompt)
+	}
+	return m.wrapPrompt(finalPrompt)
+}
+
 // sendMessageWithAttachments creates a command to send a message with file attachments
 func (m Model) sendMessageWithAttachments(prompt string) tea.Cmd {
 	// Capture attachments in closure (they will be cleared after this returns)
 	attachments := m.attachments
 
-	// Apply persona system prompt if set
-	finalPrompt := prompt
-	if m.persona != nil && m.persona.SystemPrompt != "" {
-		finalPrompt = config.FormatSystemPrompt(m.persona, prompt)
+	return m.sendPrompt(m.assemblePrompt(prompt, attachments), attachments)
+}
+
+// limitToolCalls caps the tool calls from one response at maxToolCalls and
+// appends a notice to the conversation listing the skipped calls.
+func (m *Model) limitToolCalls(calls []toolexec.ToolCall) []toolexec.ToolCall {
+	if m.maxToolCalls <= 0 || len(calls) <= m.maxToolCalls {
+		return calls
 	}
 
-	return func() tea.Msg {
-		output, err := m.session.SendMessage(finalPrompt, attachments)
-		if err != nil {
-			return errMsg{err: err}
-		}
-		return responseMsg{output: output}
+	skipped := calls[m.maxToolCalls:]
+	names := make([]string, len(skipped))
+	for i, call := range skipped {
+		names[i] = call.Name
 	}
+	notice := fmt.Sprintf("Skipped %d tool call(s) over the limit of %d per turn: %s",
+		len(skipped), m.maxToolCalls, strings.Join(names, ", "))
+
+	m.messages = append(m.messages, chatMessage{
+		role:      "tool",
+		content:   notice,
+		timestamp: time.Now(),
+	})
+	m.updateViewport()
+	m.viewport.GotoBottom()
+	m.saveMessageToHistory("tool", notice, "")
+
+	return calls[:m.maxToolCalls]
 }
 
 func (m *Model) startNextToolCall() tea.Cmd {
@@ -997,11 +1666,26 @@ func (m *Model) startNextToolCall() tea.Cmd {
 		return nil
 	}
 
-	m.ensureTooling()
-
 	call := m.pendingToolCalls[0]
 	m.pendingToolCalls = m.pendingToolCalls[1:]
 
+	// Calls from a reviewed plan were already approved or denied
+	planned, approved := false, false
+	if len(m.planDecisions) > 0 {
+		planned, approved = true, m.planDecisions[0]
+		m.planDecisions = m.planDecisions[1:]
+	}
+
+	// Tooling comes from the constructors and is never created here, so
+	// tool calls already running in commands always see the same registry
+	if m.toolRegistry == nil || m.toolExecutor == nil {
+		err := toolexec.NewExecutionError(call.Name, "tool executor not configured")
+		result := toolexec.NewErrorResult(call.Name, err).WithTiming(time.Now(), time.Now())
+		return func() tea.Msg {
+			return toolExecutionMsg{call: call, result: result}
+		}
+	}
+
 	tool, err := m.toolRegistry.Get(call.Name)
 	if err != nil {
 		result := toolexec.NewErrorResult(call.Name, err).WithTiming(time.Now(), time.Now())
@@ -1010,7 +1694,15 @@ func (m *Model) startNextToolCall() tea.Cmd {
 		}
 	}
 
-	if tool.RequiresConfirmation(call.Args) && !m.autoApproveTools {
+	if planned && !approved {
+		result := toolexec.NewErrorResult(call.Name, toolexec.NewUserDeniedError(call.Name)).
+			WithTiming(time.Now(), time.Now())
+		return func() tea.Msg {
+			return toolExecutionMsg{call: call, result: result}
+		}
+	}
+
+	if !planned && !m.trustedTools[call.Name] && tool.RequiresConfirmation(call.Args) && !m.autoApproveTools {
 		m.confirmingTool = true
 		m.toolConfirmCall = &call
 		m.loading = false
@@ -1023,8 +1715,14 @@ func (m *Model) startNextToolCall() tea.Cmd {
 }
 
 func (m Model) executeToolCall(call toolexec.ToolCall) tea.Cmd {
+	// The command runs on another goroutine, so it only uses what is
+	// captured here and never reads the Model
 	registry := m.toolRegistry
 	executor := m.toolExecutor
+	jobs := m.jobs
+	if m.streamToolOutput && executor != nil && m.isStreamingTool(call.Name) {
+		return m.executeToolCallStreaming(call)
+	}
 
 	return func() tea.Msg {
 		if registry == nil || executor == nil {
@@ -1035,7 +1733,9 @@ func (m Model) executeToolCall(call toolexec.ToolCall) tea.Cmd {
 
 		input := call.ToInput()
 		start := time.Now()
-		output, err := executor.Execute(context.Background(), call.Name, input)
+		output, err := runJob(jobs, jobLabel("Tool", call.Name), func(ctx context.Context) (*toolexec.Output, error) {
+			return executor.Execute(ctx, call.Name, input)
+		})
 		end := time.Now()
 
 		result := toolexec.NewResult(call.Name, output, err).WithTiming(start, end)
@@ -1050,35 +1750,113 @@ func (m *Model) handleToolResult(call toolexec.ToolCall, result *toolexec.Result
 	if result.ToolName == "" {
 		result.ToolName = call.Name
 	}
+	if !toolexec.IsUserDeniedError(result.Error) {
+		m.lastToolCall = &call
+	}
 
 	toolMessage := formatToolMessage(call, result)
-	if strings.TrimSpace(toolMessage) != "" {
+	structured := toolexec.NewStructuredToolCallResult(call, result)
+	if m.finishToolStream(toolMessage, result, structured) {
+		// Streamed output already has a message; it was finalized in place
+		m.updateViewport()
+		m.viewport.GotoBottom()
+		m.saveMessageToHistory("tool", toolMessage, "")
+	} else if strings.TrimSpace(toolMessage) != "" {
 		m.messages = append(m.messages, chatMessage{
-			role:    "tool",
-			content: toolMessage,
+			role:      "tool",
+			content:   toolMessage,
+			format:    toolOutputFormat(result),
+			toolCall:  structured,
+			timestamp: time.Now(),
 		})
 		m.updateViewport()
 		m.viewport.GotoBottom()
 		m.saveMessageToHistory("tool", toolMessage, "")
 	}
 
-	resultBlock := toolexec.NewToolCallResult(result).FormatAsBlock()
-	m.toolResultBlocks = append(m.toolResultBlocks, resultBlock)
+	if m.rerunningTool {
+		// A rerun only refreshes the result for the user
+		m.rerunningTool = false
+		m.loading = false
+		return nil
+	}
+
+	if m.jsonToolResults {
+		m.toolResults = append(m.toolResults, structured)
+	} else {
+		m.toolResults = append(m.toolResults, toolexec.NewToolCallResult(result))
+	}
 
 	if len(m.pendingToolCalls) > 0 {
-		return m.startNextToolCall()
+		cmd := m.startNextToolCall()
+		if cmd != nil && m.loading {
+			cmd = tea.Batch(cmd, animationTick())
+		}
+		return cmd
 	}
 
-	if len(m.toolResultBlocks) == 0 {
+	if len(m.toolResults) == 0 {
 		return nil
 	}
 
-	payload := strings.Join(m.toolResultBlocks, "\n")
-	m.toolResultBlocks = nil
-	m.loading = true
-	m.animationFrame = 0
+	payload := toolResultPayload(m.toolResults, m.jsonToolResults)
+	m.toolResults = nil
+	return m.beginSend(func(m *Model) tea.Cmd {
+		m.loading = true
+		m.animationFrame = 0
+		return tea.Batch(m.sendMessage(payload), animationTick())
+	})
+}
+
+// toolResultPayload formats the results of a turn's tool calls for the
+// model: a ```result block per result, or one JSON array when structured
+func toolResultPayload(results []*toolexec.ToolCallResult, structured bool) string {
+	if structured {
+		return toolexec.FormatResultsAsJSON(results)
+	}
+	blocks := make([]string, len(results))
+	for i, result := range results {
+		blocks[i] = result.FormatAsBlock()
+	}
+	return strings.Join(blocks, "\n")
+}
+
+// toolOutputFormat returns the format a tool declared for its output
+func toolOutputFormat(result *toolexec.Result) string {
+	if result == nil || result.Output == nil {
+		return ""
+	}
+	return result.Output.Metadata[toolexec.OutputFormatKey]
+}
+
+// truncationNotice describes how much of a truncated tool output was
+// dropped, falling back to a bare marker when the tool did not record it
+func truncationNotice(output *toolexec.Output) string {
+	droppedBytes, err := strconv.Atoi(output.Metadata[toolexec.DroppedBytesKey])
+	if err != nil || droppedBytes <= 0 {
+		return "[output truncated]"
+	}
+
+	notice := "[output truncated: " + compactByteSize(droppedBytes)
+	if lines, err := strconv.Atoi(output.Metadata[toolexec.DroppedLinesKey]); err == nil && lines > 0 {
+		notice += fmt.Sprintf(" / %d line", lines)
+		if lines > 1 {
+			notice += "s"
+		}
+	}
+	return notice + " omitted]"
+}
 
-	return m.sendMessage(payload)
+// compactByteSize formats a byte count as B, KB or MB without spaces,
+// rounding KB to whole units
+func compactByteSize(n int) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%dB", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%dKB", (n+512)/1024)
+	}
+	return fmt.Sprintf("%.1fMB", float64(n)/(1024*1024))
 }
 
 func formatToolMessage(call toolexec.ToolCall, result *toolexec.Result) string {
@@ -1101,13 +1879,21 @@ func formatToolMessage(call toolexec.ToolCall, result *toolexec.Result) string {
 
 	var outputText string
 	if result != nil && result.Output != nil {
-		if len(result.Output.Data) > 0 {
+		// Only search output is read as results, so another tool's
+		// "results" field is shown as it is
+		var results []toolexec.SearchResult
+		if call.Name == toolexec.SearchToolName {
+			results, _ = toolexec.ParseSearchResults(result.Output)
+		}
+		if len(results) > 0 {
+			outputText = formatSearchResults(results)
+		} else if len(result.Output.Data) > 0 {
 			outputText = string(result.Output.Data)
 		} else if result.Output.Message != "" {
 			outputText = result.Output.Message
 		}
 		if result.Output.Truncated {
-			outputText = strings.TrimRight(outputText, "\n") + "\n[output truncated]"
+			outputText = strings.TrimRight(outputText, "\n") + "\n" + truncationNotice(result.Output)
 		}
 	}
 
@@ -1127,9 +1913,10 @@ func formatToolMessage(call toolexec.ToolCall, result *toolexec.Result) string {
 }
 
 // sendInitialPrompt creates a command to send the initial prompt from file
-// This is called automatically on Init() when initialPrompt is set
+// This is called automatically on Init() when initialPrompt is set.
+// Template variables such as {{date}} are expanded before sending.
 func (m *Model) sendInitialPrompt() tea.Cmd {
-	prompt := m.initialPrompt
+	prompt := expandPromptTemplate(m.initialPrompt)
 	m.initialPrompt = "" // Clear to prevent re-sending
 
 	return func() tea.Msg {
@@ -1184,7 +1971,7 @@ func (m Model) handleImageCommand(path string) (tea.Model, tea.Cmd) {
 	return m.handleFileCommand(path)
 }
 
-// handleExportCommand handles the /export <path> [-f format] command
+// handleExportCommand handles the /export <path> [-f format] [-m] command
 func (m Model) handleExportCommand(args string) (tea.Model, tea.Cmd) {
 	// If no args given and we have a conversation with title, use that as default filename
 	if strings.TrimSpace(args) == "" {
@@ -1199,7 +1986,7 @@ func (m Model) handleExportCommand(args string) (tea.Model, tea.Cmd) {
 	}
 
 	// Parse arguments
-	path, format, err := parseExportArgs(args)
+	path, format, withFrontMatter, err := parseExportArgs(args)
 	if err != nil {
 		m.err = err
 		return m, nil
@@ -1215,7 +2002,11 @@ func (m Model) handleExportCommand(args string) (tea.Model, tea.Cmd) {
 	// Check for conversation to export
 	if m.conversation != nil && m.conversation.ID != "" && m.fullHistoryStore != nil {
 		// Export from store (persisted conversation)
-		return m, exportCommand(m.fullHistoryStore, m.conversation.ID, format, absPath)
+		frontMatter := ""
+		if withFrontMatter {
+			frontMatter = m.exportFrontMatter(m.conversation.Title).render()
+		}
+		return m, exportCommand(m.fullHistoryStore, m.conversation.ID, format, absPath, frontMatter)
 	}
 
 	// Check for in-memory messages
@@ -1227,9 +2018,17 @@ func (m Model) handleExportCommand(args string) (tea.Model, tea.Cmd) {
 		} else {
 			title = "Conversation"
 		}
-		return m, exportFromMemory(m.messages, title, format, absPath)
+		frontMatter := ""
+		if withFrontMatter {
+			frontMatter = m.exportFrontMatter(title).render()
+		}
+		return m, exportFromMemory(m.messages, title, format, absPath, frontMatter)
 	}
 
+	if m.historyStore != nil && m.fullHistoryStore == nil {
+		m.err = m.historyUnavailable()
+		return m, nil
+	}
 	m.err = fmt.Errorf("no conversation to export")
 	return m, nil
 }
@@ -1279,11 +2078,23 @@ func (m Model) downloadSelectedImages(indices []int, targetDir string) tea.Cmd {
 		}
 
 		opts := api.ImageDownloadOptions{
-			Directory: targetDir,
-			FullSize:  true,
+			Directory:       targetDir,
+			FullSize:        true,
+			SkipUnsupported: true,
 		}
 
-		paths, err := m.client.DownloadSelectedImages(m.lastOutput, indices, opts)
+		label := jobLabel("Download", fmt.Sprintf("%d image(s)", len(indices)))
+		paths, err := runJob(m.jobs, label, func(context.Context) ([]string, error) {
+			return m.client.DownloadSelectedImages(m.lastOutput, indices, opts)
+		})
+		var skipErr *api.SkippedImagesError
+		if errors.As(err, &skipErr) {
+			return downloadImagesResultMsg{
+				paths:   paths,
+				count:   len(paths),
+				skipped: skipErr.Skipped,
+			}
+		}
 		if err != nil {
 			return downloadImagesResultMsg{err: err}
 		}
@@ -1295,10 +2106,28 @@ func (m Model) downloadSelectedImages(indices []int, targetDir string) tea.Cmd {
 	}
 }
 
+// skippedImagesNote describes images skipped by a download, for feedback
+func skippedImagesNote(skipped []api.SkippedImage) string {
+	if len(skipped) == 0 {
+		return ""
+	}
+	reasons := make([]string, 0, len(skipped))
+	seen := make(map[string]bool)
+	for _, img := range skipped {
+		if !seen[img.Reason] {
+			seen[img.Reason] = true
+			reasons = append(reasons, img.Reason)
+		}
+	}
+	return fmt.Sprintf(" (%d skipped: %s)", len(skipped), strings.Join(reasons, ", "))
+}
+
 // uploadFile creates a command to upload a file
 func (m Model) uploadFile(path string) tea.Cmd {
 	return func() tea.Msg {
-		file, err := m.client.UploadFile(path)
+		file, err := runJob(m.jobs, jobLabel("Upload", filepath.Base(path)), func(context.Context) (*api.UploadedFile, error) {
+			return m.client.UploadFile(path)
+		})
 		if err != nil {
 			return fileUploadedMsg{err: err}
 		}
@@ -1307,42 +2136,44 @@ func (m Model) uploadFile(path string) tea.Cmd {
 }
 
 // parseExportArgs parses /export command arguments
-// Returns path, format, and error
+// Returns path, format, whether to prepend YAML front matter, and error
 // Examples:
 //   - "/export chat.md" -> path="chat.md", format="markdown"
 //   - "/export chat.json" -> path="chat.json", format="json"
 //   - "/export chat" -> path="chat.md", format="markdown" (default)
 //   - "/export chat -f json" -> path="chat.json", format="json"
-func parseExportArgs(args string) (path, format string, err error) {
+//   - "/export "my chat.md"" -> path="my chat.md", format="markdown"
+//   - "/export chat -m" -> path="chat.md", format="markdown", frontMatter=true
+func parseExportArgs(args string) (path, format string, frontMatter bool, err error) {
 	args = strings.TrimSpace(args)
 	if args == "" {
-		return "", "", fmt.Errorf("usage: /export <path> [-f json|md]")
+		return "", "", false, fmt.Errorf("usage: /export <path> [-f json|md] [-m]")
 	}
 
-	parts := strings.Fields(args)
-	format = "markdown" // default
+	pathParts, flags := parseFlags(args, "m", "with-frontmatter")
+	if err := checkUnknownFlags(flags, "f", "m", "with-frontmatter"); err != nil {
+		return "", "", false, err
+	}
+	_, short := flags["m"]
+	_, long := flags["with-frontmatter"]
+	frontMatter = short || long
 
-	// Parse flags
-	var pathParts []string
-	for i := 0; i < len(parts); i++ {
-		if parts[i] == "-f" && i+1 < len(parts) {
-			f := strings.ToLower(parts[i+1])
-			switch f {
-			case "json":
-				format = "json"
-			case "md", "markdown":
-				format = "markdown"
-			default:
-				return "", "", fmt.Errorf("unknown format: %s (use json or md)", f)
-			}
-			i++ // skip format value
-		} else {
-			pathParts = append(pathParts, parts[i])
+	format = "markdown" // default
+	if f, ok := flags["f"]; ok {
+		switch strings.ToLower(f) {
+		case "json":
+			format = "json"
+		case "md", "markdown":
+			format = "markdown"
+		case "":
+			return "", "", false, fmt.Errorf("flag -f requires a value (json or md)")
+		default:
+			return "", "", false, fmt.Errorf("unknown format: %s (use json or md)", strings.ToLower(f))
 		}
 	}
 
 	if len(pathParts) == 0 {
-		return "", "", fmt.Errorf("missing filename")
+		return "", "", false, fmt.Errorf("missing filename")
 	}
 
 	path = strings.Join(pathParts, " ")
@@ -1363,7 +2194,11 @@ func parseExportArgs(args string) (path, format string, err error) {
 		}
 	}
 
-	return path, format, nil
+	if frontMatter && format != "markdown" {
+		return "", "", false, fmt.Errorf("front matter requires markdown format")
+	}
+
+	return path, format, frontMatter, nil
 }
 
 // validateExportPath validates and expands an export path
@@ -1419,8 +2254,9 @@ func sanitizeFilename(title string) string {
 	return result
 }
 
-// exportCommand creates a tea.Cmd that exports a conversation from store
-func exportCommand(store FullHistoryStore, convID, format, path string) tea.Cmd {
+// exportCommand creates a tea.Cmd that exports a conversation from store.
+// A non-empty frontMatter is prepended to markdown exports.
+func exportCommand(store FullHistoryStore, convID, format, path, frontMatter string) tea.Cmd {
 	return func() tea.Msg {
 		// Check if file exists (for overwrite flag)
 		overwrite := false
@@ -1436,7 +2272,7 @@ func exportCommand(store FullHistoryStore, convID, format, path string) tea.Cmd
 		} else {
 			var md string
 			md, err = store.ExportToMarkdown(convID)
-			data = []byte(md)
+			data = []byte(frontMatter + md)
 		}
 
 		if err != nil {
@@ -1457,8 +2293,9 @@ func exportCommand(store FullHistoryStore, convID, format, path string) tea.Cmd
 	}
 }
 
-// exportFromMemory creates a tea.Cmd that exports in-memory messages
-func exportFromMemory(messages []chatMessage, title, format, path string) tea.Cmd {
+// exportFromMemory creates a tea.Cmd that exports in-memory messages.
+// A non-empty frontMatter is prepended to markdown exports.
+func exportFromMemory(messages []chatMessage, title, format, path, frontMatter string) tea.Cmd {
 	return func() tea.Msg {
 		// Check if file exists (for overwrite flag)
 		overwrite := false
@@ -1475,6 +2312,9 @@ func exportFromMemory(messages []chatMessage, title, format, path string) tea.Cm
 				Content   string `json:"content"`
 				Thoughts  string `json:"thoughts,omitempty"`
 				Timestamp string `json:"timestamp,omitempty"`
+
+				// ToolCall is the structured call and result of a tool message
+				ToolCall *toolexec.ToolCallResult `json:"tool_call,omitempty"`
 			}
 			type exportData struct {
 				Title    string          `json:"title"`
@@ -1484,8 +2324,9 @@ func exportFromMemory(messages []chatMessage, title, format, path string) tea.Cm
 			export := exportData{Title: title}
 			for _, msg := range messages {
 				export.Messages = append(export.Messages, exportMessage{
-					Role:    msg.role,
-					Content: msg.content,
+					Role:     msg.role,
+					Content:  msg.content,
+					ToolCall: msg.toolCall,
 				})
 			}
 
@@ -1495,31 +2336,7 @@ func exportFromMemory(messages []chatMessage, title, format, path string) tea.Cm
 				return exportResultMsg{err: fmt.Errorf("json marshal failed: %w", err)}
 			}
 		} else {
-			// Build markdown for in-memory export
-			var md strings.Builder
-			if title != "" {
-				md.WriteString("# ")
-				md.WriteString(title)
-				md.WriteString("\n\n")
-			}
-
-			for i, msg := range messages {
-				if i > 0 {
-					md.WriteString("\n---\n\n")
-				}
-				switch msg.role {
-				case "user":
-					md.WriteString("**User:**\n\n")
-				case "tool":
-					md.WriteString("**Tool:**\n\n")
-				default:
-					md.WriteString("**Gemini:**\n\n")
-				}
-				md.WriteString(msg.content)
-				md.WriteString("\n")
-			}
-
-			data = []byte(md.String())
+			data = []byte(frontMatter + buildMemoryMarkdown(messages, title, false))
 		}
 
 		// Write to file
@@ -1536,21 +2353,161 @@ func exportFromMemory(messages []chatMessage, title, format, path string) tea.Cm
 	}
 }
 
+// buildMemoryMarkdown renders in-memory messages as markdown.
+// This is the format used by /export for unsaved conversations and by /copy-all.
+// Thoughts are included as collapsible <details> blocks when includeThoughts is set.
+func buildMemoryMarkdown(messages []chatMessage, title string, includeThoughts bool) string {
+	var md strings.Builder
+	if title != "" {
+		md.WriteString("# ")
+		md.WriteString(title)
+		md.WriteString("\n\n")
+	}
+
+	for i, msg := range messages {
+		if i > 0 {
+			md.WriteString("\n---\n\n")
+		}
+		switch msg.role {
+		case "user":
+			md.WriteString("**User:**\n\n")
+		case "tool":
+			md.WriteString("**Tool:**\n\n")
+		default:
+			md.WriteString("**Gemini:**\n\n")
+		}
+		if includeThoughts && msg.thoughts != "" {
+			md.WriteString("<details>\n<summary>💭 Thinking</summary>\n\n")
+			md.WriteString(msg.thoughts)
+			md.WriteString("\n\n</details>\n\n")
+		}
+		md.WriteString(msg.content)
+		md.WriteString("\n")
+	}
+
+	return md.String()
+}
+
+// writeClipboard writes text to the system clipboard, failing with
+// clipboard.ErrUnavailable when there is none.
+// It is a variable so tests can replace it.
+var writeClipboard = clipboard.Copy
+
+// readClipboard reads text from the system clipboard.
+// It is a variable so tests can replace it.
+var readClipboard = clipboard.Paste
+
+// clipboardError describes a failed copy, with a plain message when the
+// session has no clipboard (e.g. over SSH)
+func clipboardError(err error) error {
+	if errors.Is(err, clipboard.ErrUnavailable) {
+		return fmt.Errorf("clipboard not available")
+	}
+	return fmt.Errorf("failed to copy to clipboard: %w", err)
+}
+
+// handleCopyAllCommand handles the /copy-all [--thoughts] [--plain] command.
+// It copies the whole conversation to the clipboard as markdown, or as
+// plain text with the markdown formatting stripped when --plain is set.
+func (m Model) handleCopyAllCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	includeThoughts := false
+	plain := false
+	for _, arg := range strings.Fields(args) {
+		switch arg {
+		case "-t", "--thoughts":
+			includeThoughts = true
+		case "-p", "--plain":
+			plain = true
+		default:
+			m.err = fmt.Errorf("usage: /copy-all [--thoughts] [--plain]")
+			return m, nil
+		}
+	}
+
+	if len(m.messages) == 0 {
+		m.err = fmt.Errorf("nothing to copy - conversation is empty")
+		return m, nil
+	}
+
+	title := "Conversation"
+	if m.conversation != nil && m.conversation.Title != "" {
+		title = m.conversation.Title
+	}
+
+	text := buildMemoryMarkdown(m.messages, title, includeThoughts)
+	if plain {
+		text = render.MarkdownToPlain(text)
+	}
+	if err := writeClipboard(text); err != nil {
+		m.err = clipboardError(err)
+		return m, nil
+	}
+
+	if plain {
+		m.err = fmt.Errorf("✓ Copied %d message(s) to clipboard as plain text", len(m.messages))
+		return m, nil
+	}
+	m.err = fmt.Errorf("✓ Copied %d message(s) to clipboard", len(m.messages))
+	return m, nil
+}
+
 // jsonMarshalIndent is a helper to marshal JSON with indentation
 // Note: We use gjson for reading JSON but encoding/json for writing
 func jsonMarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
 	return json.MarshalIndent(v, prefix, indent)
 }
 
-// saveMessageToHistory saves a message to the history store if available
+// saveGemToHistory records the selected gem on the current conversation
+// if the history store supports it, so resuming it restores the gem
+func (m *Model) saveGemToHistory(gemID, gemName string) error {
+	if m.conversation == nil {
+		return nil
+	}
+	m.conversation.GemID = gemID
+	m.conversation.GemName = gemName
+
+	store, ok := m.historyStore.(GemHistoryStore)
+	if !ok {
+		return nil
+	}
+	if err := store.UpdateGem(m.conversation.ID, gemID, gemName); err != nil {
+		return fmt.Errorf("failed to save gem: %w", err)
+	}
+	return nil
+}
+
+// saveMessageToHistory saves a message to the history store if available.
+// Thoughts are dropped when the config disables storing them.
 func (m *Model) saveMessageToHistory(role, content, thoughts string) {
 	if m.historyStore == nil || m.conversation == nil {
 		return
 	}
+	if m.discardThoughts {
+		thoughts = ""
+	}
 	// Errors are logged but not exposed to user (best-effort persistence)
 	_ = m.historyStore.AddMessage(m.conversation.ID, role, content, thoughts)
 }
 
+// replaceMessageInHistory replaces the last stored message with role, if
+// the history store supports it, and saves a new message otherwise
+func (m *Model) replaceMessageInHistory(role, content, thoughts string) {
+	store, ok := m.historyStore.(ReplacingHistoryStore)
+	if !ok || m.conversation == nil {
+		m.saveMessageToHistory(role, content, thoughts)
+		return
+	}
+	if m.discardThoughts {
+		thoughts = ""
+	}
+	if err := store.ReplaceLastMessage(m.conversation.ID, role, content, thoughts); err != nil {
+		// The stored conversation does not end with the old reply
+		m.saveMessageToHistory(role, content, thoughts)
+	}
+}
+
 // saveMetadataToHistory saves session metadata for conversation resumption
 func (m *Model) saveMetadataToHistory() {
 	if m.historyStore == nil || m.conversation == nil || m.session == nil {
@@ -1566,57 +2523,121 @@ func (m *Model) saveMetadataToHistory() {
 
 // updateViewport refreshes the viewport content with styled messages
 func (m *Model) updateViewport() {
+	m.viewportDirty = false
+	m.spillMessages()
+
 	var content strings.Builder
 	bubbleWidth := m.viewport.Width - 6
+	contentWidth := bubbleWidth - 4
+	userStyle, assistantStyle, toolStyle := userBubbleStyle, assistantBubbleStyle, toolBubbleStyle
+	if m.compactMessages {
+		// Without borders and margins the text only gives up its indent
+		bubbleWidth = m.viewport.Width
+		contentWidth = bubbleWidth - compactIndent
+		userStyle = compactBubble(userStyle)
+		assistantStyle = compactBubble(assistantStyle)
+		toolStyle = compactBubble(toolStyle)
+	}
+
+	if m.renderCache == nil {
+		m.renderCache = newMessageRenderCache()
+	}
+	m.renderCache.begin(contentWidth)
+	defer m.renderCache.end()
+
+	if m.hasSpilledMessages() {
+		content.WriteString(m.spilledMessagesHint() + "\n\n")
+	}
+
+	// currentLine counts the lines written so far, scanning only what was
+	// added since the last call so long chats are not rescanned per message
+	lines, counted := 0, 0
+	currentLine := func() int {
+		lines += strings.Count(content.String()[counted:], "\n")
+		counted = content.Len()
+		return lines
+	}
 
+	m.userMessageOffsets = nil
+	m.messageOffsets = nil
 	for i, msg := range m.messages {
 		if i > 0 {
 			content.WriteString("\n")
 		}
+		m.messageOffsets = append(m.messageOffsets, currentLine())
+		if m.selectingMessage && i == m.messageCursor {
+			content.WriteString(configValueStyle.Render("▸ selected") + "\n")
+		}
 
 		switch msg.role {
 		case "user":
-			// User message
-			label := userLabelStyle.Render("⬤ You")
-			bubble := userBubbleStyle.Width(bubbleWidth).Render(msg.content)
+			// User message; record the line it starts on for turn navigation
+			m.userMessageOffsets = append(m.userMessageOffsets, currentLine())
+			label := m.messageLabel(userLabelStyle.Render(icon("⬤", ">")+" You"), msg)
+			body := msg.content
+			if isLongInput(body) {
+				// Wrapping a huge single line would stall rendering
+				body = longInputSummary(body)
+			}
+			bubble := userStyle.Width(bubbleWidth).Render(body)
 			content.WriteString(label + "\n" + bubble)
 
 		case "tool":
 			// Tool message
-			label := toolLabelStyle.Render("Tool")
-			bubble := toolBubbleStyle.Width(bubbleWidth).Render(msg.content)
+			label := m.messageLabel(toolLabelStyle.Render("Tool"+toolStatusLabel(msg)), msg)
+			body := msg.content
+			if msg.format != "" {
+				body = m.renderCache.toolBody(msg.content, msg.format, contentWidth)
+			}
+			bubble := toolStyle.Width(bubbleWidth).Render(body)
 			content.WriteString(label + "\n" + bubble)
 
 		default:
 			// Assistant message
-			label := assistantLabelStyle.Render("✦ Gemini")
+			name := icon("✦", "*") + " Gemini"
+			if msg.model != "" {
+				name += " (" + msg.model + ")"
+			}
+			label := m.messageLabel(assistantLabelStyle.Render(name), msg)
 
-			// Render thoughts if present
-			if msg.thoughts != "" {
-				thoughtsContent := thoughtsStyle.Width(bubbleWidth - 4).Render(
-					"💭 " + msg.thoughts,
+			// Render thoughts if present and enabled
+			if msg.thoughts != "" && !m.thoughtsOff {
+				thoughtsContent := thoughtsStyle.Width(contentWidth).Render(
+					icon("💭", "[thinking]") + " " + msg.thoughts,
 				)
 				content.WriteString(label + "\n" + thoughtsContent + "\n")
 			} else {
 				content.WriteString(label + "\n")
 			}
 
-			// Render markdown content
-			rendered, err := render.MarkdownWithWidth(msg.content, bubbleWidth-4)
-			if err != nil {
-				rendered = msg.content
-			}
-			// Trim trailing newlines from glamour
-			rendered = strings.TrimRight(rendered, "\n")
+			// Render content (cached per message and width)
+			rendered := m.renderCache.assistantBody(msg.content, contentWidth)
 
-			bubble := assistantBubbleStyle.Width(bubbleWidth).Render(rendered)
+			bubble := assistantStyle.Width(bubbleWidth).Render(rendered)
 			content.WriteString(bubble)
 
 			// Render images if present
 			if len(msg.images) > 0 {
-				imagesContent := renderImageLinks(msg.images, bubbleWidth-4)
+				imagesContent := renderImageLinks(msg.images, contentWidth)
 				content.WriteString("\n" + imagesContent)
 			}
+
+			// Render citation sources if present
+			if len(msg.sources) > 0 {
+				sourcesContent := renderSourceLinks(msg.sources, contentWidth)
+				content.WriteString("\n" + sourcesContent)
+			}
+
+			// Render what changed from the reply this one regenerated
+			if len(msg.changes) > 0 {
+				content.WriteString("\n" + hintStyle.Render("Changes from previous reply:"))
+				content.WriteString("\n" + renderWordDiff(msg.changes, contentWidth))
+			}
+
+			// Render the estimated size and cost of the reply
+			if m.showCost {
+				content.WriteString("\n" + hintStyle.Render(m.costNote(msg)))
+			}
 		}
 		content.WriteString("\n")
 	}
@@ -1624,12 +2645,73 @@ func (m *Model) updateViewport() {
 	m.viewport.SetContent(content.String())
 }
 
+// messageLabel appends the message's relative time to its rendered label
+// when timestamps are enabled.
+func (m Model) messageLabel(label string, msg chatMessage) string {
+	if !m.showTimestamps || msg.timestamp.IsZero() {
+		return label
+	}
+	return label + " " + timestampStyle.Render(formatTimeAgo(msg.timestamp))
+}
+
+// handleTimestampsCommand handles the /timestamps [on|off] command, which
+// toggles showing when each message occurred.
+func (m Model) handleTimestampsCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		m.showTimestamps = !m.showTimestamps
+	case "on":
+		m.showTimestamps = true
+	case "off":
+		m.showTimestamps = false
+	default:
+		m.err = fmt.Errorf("usage: /timestamps [on|off]")
+		return m, nil
+	}
+
+	m.updateViewport()
+	if m.showTimestamps {
+		m.err = fmt.Errorf("✓ Message timestamps shown")
+	} else {
+		m.err = fmt.Errorf("✓ Message timestamps hidden")
+	}
+	return m, nil
+}
+
+// handleCompactCommand handles the /compact [on|off] command, which toggles
+// rendering messages without bubble borders.
+func (m Model) handleCompactCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		m.compactMessages = !m.compactMessages
+	case "on":
+		m.compactMessages = true
+	case "off":
+		m.compactMessages = false
+	default:
+		m.err = fmt.Errorf("usage: /compact [on|off]")
+		return m, nil
+	}
+
+	m.updateViewport()
+	if m.compactMessages {
+		m.err = fmt.Errorf("✓ Compact messages enabled")
+	} else {
+		m.err = fmt.Errorf("✓ Compact messages disabled")
+	}
+	return m, nil
+}
+
 // renderImageLinks renders image URLs in a styled format
 func renderImageLinks(images []models.WebImage, width int) string {
 	var sb strings.Builder
 
 	// Header
-	header := imageSectionHeaderStyle.Render(fmt.Sprintf("🖼 Images (%d)", len(images)))
+	header := imageSectionHeaderStyle.Render(fmt.Sprintf("%s Images (%d)", icon("🖼", "[images]"), len(images)))
 	sb.WriteString(header)
 	sb.WriteString("\n")
 
@@ -1664,6 +2746,47 @@ func renderImageLinks(images []models.WebImage, width int) string {
 	return imageSectionStyle.Width(width).Render(sb.String())
 }
 
+// renderSourceLinks renders citation sources in the same style as image links
+func renderSourceLinks(sources []models.Source, width int) string {
+	var sb strings.Builder
+
+	// Header
+	header := imageSectionHeaderStyle.Render(fmt.Sprintf("%s Sources (%d)", icon("🔗", "[sources]"), len(sources)))
+	sb.WriteString(header)
+	sb.WriteString("\n")
+
+	maxTitleLen := width - 10
+	if maxTitleLen < 20 {
+		maxTitleLen = 20
+	}
+
+	for i, src := range sources {
+		// Use title if available, otherwise "Source N"
+		title := src.Title
+		if title == "" {
+			title = fmt.Sprintf("Source %d", i+1)
+		}
+		if len(title) > maxTitleLen {
+			title = title[:maxTitleLen-3] + "..."
+		}
+
+		// Format: N. [Title] URL
+		titlePart := imageTitleStyle.Render("[" + title + "]")
+		urlPart := imageLinkStyle.Render(src.URL)
+		sb.WriteString(fmt.Sprintf("  %d. %s %s\n", i+1, titlePart, urlPart))
+
+		if snippet := strings.Join(strings.Fields(src.Snippet), " "); snippet != "" {
+			if len(snippet) > maxTitleLen {
+				snippet = snippet[:maxTitleLen-3] + "..."
+			}
+			sb.WriteString("     " + hintStyle.Render(snippet) + "\n")
+		}
+	}
+
+	// Wrap in section style
+	return imageSectionStyle.Width(width).Render(sb.String())
+}
+
 // formatError formats an error with structured error details for display
 func (m Model) formatError(err error) string {
 	if err == nil {
@@ -1673,7 +2796,7 @@ func (m Model) formatError(err error) string {
 	var sb strings.Builder
 
 	// Main error message
-	sb.WriteString(errorStyle.Render(fmt.Sprintf("⚠ Error: %v", err)))
+	sb.WriteString(errorStyle.Render(fmt.Sprintf("%s Error: %v", icon("⚠", "[!]"), err)))
 
 	// Add structured error details
 	detailStyle := lipgloss.NewStyle().Foreground(colorTextDim).PaddingLeft(2)
@@ -1693,16 +2816,16 @@ func (m Model) formatError(err error) string {
 	switch {
 	case apierrors.IsAuthError(err):
 		sb.WriteString("\n")
-		sb.WriteString(hintStyle.Render("💡 Try 'geminiweb auto-login' to refresh your session"))
+		sb.WriteString(hintStyle.Render(icon("💡", "[hint]") + " Try 'geminiweb auto-login' to refresh your session"))
 	case apierrors.IsRateLimitError(err):
 		sb.WriteString("\n")
-		sb.WriteString(hintStyle.Render("💡 Usage limit reached. Try again later or use a different model"))
+		sb.WriteString(hintStyle.Render(icon("💡", "[hint]") + " Usage limit reached. Try again later or use a different model"))
 	case apierrors.IsNetworkError(err):
 		sb.WriteString("\n")
-		sb.WriteString(hintStyle.Render("💡 Check your internet connection"))
+		sb.WriteString(hintStyle.Render(icon("💡", "[hint]") + " Check your internet connection"))
 	case apierrors.IsTimeoutError(err):
 		sb.WriteString("\n")
-		sb.WriteString(hintStyle.Render("💡 Request timed out. Try again"))
+		sb.WriteString(hintStyle.Render(icon("💡", "[hint]") + " Request timed out. Try again"))
 	}
 
 	return sb.String()
@@ -1712,26 +2835,14 @@ func (m Model) formatError(err error) string {
 func RunChat(client api.GeminiClientInterface, modelName string) error {
 	m := NewChatModel(client, modelName)
 
-	p := tea.NewProgram(
-		m,
-		tea.WithAltScreen(),
-	)
-
-	_, err := p.Run()
-	return err
+	return runChatProgram(m)
 }
 
 // RunChatWithSession starts the chat TUI with a pre-configured session
 func RunChatWithSession(client api.GeminiClientInterface, session ChatSessionInterface, modelName string) error {
 	m := NewChatModelWithSession(client, session, modelName)
 
-	p := tea.NewProgram(
-		m,
-		tea.WithAltScreen(),
-	)
-
-	_, err := p.Run()
-	return err
+	return runChatProgram(m)
 }
 
 // NewChatModelWithSession creates a new chat TUI model with a pre-configured session
@@ -1743,24 +2854,25 @@ func NewChatModelWithSession(client api.GeminiClientInterface, session ChatSessi
 	s.Spinner = spinner.Points
 	s.Style = loadingStyle
 
-	toolRegistry := defaultToolRegistry()
-	toolExecutor := defaultToolExecutor(toolRegistry)
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		cfg = config.DefaultConfig()
 	}
 
-	return Model{
-		client:           client,
-		session:          session,
-		modelName:        modelName,
-		textarea:         ta,
-		spinner:          s,
-		messages:         []chatMessage{},
-		toolRegistry:     toolRegistry,
-		toolExecutor:     toolExecutor,
-		autoApproveTools: cfg.AutoApproveTools,
-	}
+	m := Model{
+		client:         client,
+		session:        session,
+		modelName:      modelName,
+		textarea:       ta,
+		spinner:        s,
+		messages:       []chatMessage{},
+		messageCap:     defaultMessageCap,
+		resumeMessages: defaultResumeMessages,
+		jobs:           newJobRegistry(),
+	}
+	applyConfig(&m, cfg)
+
+	return m
 }
 
 // RunChatWithConversation starts the chat TUI with a pre-configured session and conversation
@@ -1795,13 +2907,7 @@ func RunChatWithInitialPrompt(
 	m.persona = persona
 	m.initialPrompt = initialPrompt
 
-	p := tea.NewProgram(
-		m,
-		tea.WithAltScreen(),
-	)
-
-	_, err := p.Run()
-	return err
+	return runChatProgram(m)
 }
 
 // NewChatModelWithConversation creates a new chat TUI model with a conversation for persistence
@@ -1813,37 +2919,28 @@ func NewChatModelWithConversation(client api.GeminiClientInterface, session Chat
 	s.Spinner = spinner.Points
 	s.Style = loadingStyle
 
-	toolRegistry := defaultToolRegistry()
-	toolExecutor := defaultToolExecutor(toolRegistry)
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		cfg = config.DefaultConfig()
 	}
 
+	m := Model{
+		client:         client,
+		session:        session,
+		modelName:      modelName,
+		textarea:       ta,
+		spinner:        s,
+		conversation:   conv,
+		historyStore:   store,
+		messageCap:     defaultMessageCap,
+		resumeMessages: defaultResumeMessages,
+		jobs:           newJobRegistry(),
+	}
+	applyConfig(&m, cfg)
+
 	// Load existing messages from conversation
-	var messages []chatMessage
 	if conv != nil {
-		for _, msg := range conv.Messages {
-			messages = append(messages, chatMessage{
-				role:     msg.Role,
-				content:  msg.Content,
-				thoughts: msg.Thoughts,
-			})
-		}
-	}
-
-	m := Model{
-		client:           client,
-		session:          session,
-		modelName:        modelName,
-		textarea:         ta,
-		spinner:          s,
-		messages:         messages,
-		conversation:     conv,
-		historyStore:     store,
-		toolRegistry:     toolRegistry,
-		toolExecutor:     toolExecutor,
-		autoApproveTools: cfg.AutoApproveTools,
+		m.loadConversationMessages(conv.Messages)
 	}
 
 	// Check if store implements FullHistoryStore for /history command
@@ -1867,27 +2964,28 @@ func (m Model) loadGemsForChat() tea.Cmd {
 		}
 
 		// Sort gems: custom first, then by name
-		gems := jar.Values()
-		sortedGems := make([]*models.Gem, len(gems))
-		copy(sortedGems, gems)
-
-		// Sort: custom gems first, then alphabetically by name
-		for i := 0; i < len(sortedGems)-1; i++ {
-			for j := i + 1; j < len(sortedGems); j++ {
-				// Custom gems before system gems
-				if sortedGems[i].Predefined && !sortedGems[j].Predefined {
-					sortedGems[i], sortedGems[j] = sortedGems[j], sortedGems[i]
-				} else if sortedGems[i].Predefined == sortedGems[j].Predefined {
-					// Alphabetically by name
-					if strings.ToLower(sortedGems[i].Name) > strings.ToLower(sortedGems[j].Name) {
-						sortedGems[i], sortedGems[j] = sortedGems[j], sortedGems[i]
-					}
-				}
-			}
-		}
+		return gemsLoadedForChatMsg{gems: sortGems(jar.Values())}
+	}
+}
 
-		return gemsLoadedForChatMsg{gems: sortedGems}
+// applyLoadedGems shows fetched gems in the selector, caching them. When
+// the fetch fails, the last cached gems are shown instead, with a notice;
+// without a cache the selector closes with the error.
+func (m *Model) applyLoadedGems(msg gemsLoadedForChatMsg) {
+	m.gemsLoading = false
+	m.gemsFetchErr = nil
+	if msg.err == nil {
+		m.gemsList = msg.gems
+		m.cachedGems = msg.gems
+		return
+	}
+	if len(m.cachedGems) == 0 {
+		m.selectingGem = false
+		m.err = msg.err
+		return
 	}
+	m.gemsList = m.cachedGems
+	m.gemsFetchErr = msg.err
 }
 
 // updateToolConfirmation handles input when confirming tool execution
@@ -1900,7 +2998,7 @@ func (m Model) updateToolConfirmation(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.handleInterrupt()
 
 		case "y", "Y":
 			if m.toolConfirmCall == nil {
@@ -1944,18 +3042,12 @@ func (m Model) updateGemSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 
 	case gemsLoadedForChatMsg:
-		m.gemsLoading = false
-		if msg.err != nil {
-			m.selectingGem = false
-			m.err = msg.err
-		} else {
-			m.gemsList = msg.gems
-		}
+		m.applyLoadedGems(msg)
 
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.handleInterrupt()
 
 		case "esc":
 			// Cancel gem selection
@@ -1984,8 +3076,10 @@ func (m Model) updateGemSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 			filtered := m.filteredGems()
 			if len(filtered) > 0 && m.gemsCursor < len(filtered) {
 				selectedGem := filtered[m.gemsCursor]
-				m.session.SetGem(selectedGem.ID)
-				m.activeGemName = selectedGem.Name
+				m.setActiveGem(selectedGem.ID, selectedGem.Name)
+				if err := m.saveGemToHistory(selectedGem.ID, selectedGem.Name); err != nil {
+					m.err = err
+				}
 				m.selectingGem = false
 				m.gemsList = nil
 				m.gemsCursor = 0
@@ -2040,7 +3134,7 @@ func (m Model) renderGemSelector() string {
 	var content strings.Builder
 
 	// Header
-	title := configTitleStyle.Render("📦 Select a Gem")
+	title := configTitleStyle.Render(icon("📦", "[gem]") + " Select a Gem")
 	if m.activeGemName != "" {
 		title += hintStyle.Render(fmt.Sprintf("  (current: %s)", m.activeGemName))
 	}
@@ -2049,11 +3143,16 @@ func (m Model) renderGemSelector() string {
 
 	// Filter input
 	if m.gemsFilter != "" {
-		filterLine := inputLabelStyle.Render("🔍 ") + m.gemsFilter + "_"
+		filterLine := inputLabelStyle.Render(icon("🔍", "[search]")+" ") + m.gemsFilter + "_"
 		content.WriteString(filterLine)
 		content.WriteString("\n\n")
 	}
 
+	if m.gemsFetchErr != nil && !m.gemsLoading {
+		content.WriteString(hintStyle.Render(fmt.Sprintf("  Showing cached gems (refresh failed: %v)", m.gemsFetchErr)))
+		content.WriteString("\n\n")
+	}
+
 	if m.gemsLoading {
 		content.WriteString(loadingStyle.Render("  Loading gems..."))
 	} else if len(m.gemsList) == 0 {
@@ -2142,6 +3241,16 @@ func (m Model) renderGemSelector() string {
 	return boxStyle.Render(content.String())
 }
 
+// historyUnavailable returns the error for a command that needs the full
+// history store. A narrow store still saves messages, so that case gets its
+// own message rather than suggesting history is off.
+func (m Model) historyUnavailable() error {
+	if m.historyStore != nil {
+		return fmt.Errorf("history browsing requires a full store; message persistence is active")
+	}
+	return fmt.Errorf("history not available")
+}
+
 // loadHistoryForChat returns a command that loads conversations from the history store
 func (m Model) loadHistoryForChat() tea.Cmd {
 	return func() tea.Msg {
@@ -2177,7 +3286,7 @@ func (m Model) updateHistorySelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.handleInterrupt()
 
 		case "esc":
 			// Cancel history selection
@@ -2185,6 +3294,7 @@ func (m Model) updateHistorySelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.historyList = nil
 			m.historyCursor = 0
 			m.historyFilter = ""
+			m.historyArchived = false
 
 		case "up", "k":
 			totalItems := len(m.filteredHistory()) + 1 // +1 for "New Conversation"
@@ -2217,6 +3327,10 @@ func (m Model) updateHistorySelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m.switchConversation(filtered[convIdx])
 			}
 
+		case "tab":
+			m.historyArchived = !m.historyArchived
+			m.historyCursor = 0
+
 		case "backspace":
 			if len(m.historyFilter) > 0 {
 				m.historyFilter = m.historyFilter[:len(m.historyFilter)-1]
@@ -2277,15 +3391,19 @@ func (m Model) updateImageSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// filteredHistory returns the history list filtered by historyFilter
+// filteredHistory returns the history list filtered by historyFilter.
+// Archived conversations are left out unless historyArchived is set.
 func (m Model) filteredHistory() []*history.Conversation {
-	if m.historyFilter == "" {
+	if m.historyFilter == "" && (m.historyArchived || !hasArchived(m.historyList)) {
 		return m.historyList
 	}
 
 	filter := strings.ToLower(m.historyFilter)
 	var filtered []*history.Conversation
 	for _, conv := range m.historyList {
+		if conv.Archived && !m.historyArchived {
+			continue
+		}
 		if strings.Contains(strings.ToLower(conv.Title), filter) ||
 			strings.Contains(strings.ToLower(conv.Model), filter) {
 			filtered = append(filtered, conv)
@@ -2304,7 +3422,7 @@ func (m Model) renderHistorySelector() string {
 	var content strings.Builder
 
 	// Header
-	title := configTitleStyle.Render("📚 Select Conversation")
+	title := configTitleStyle.Render(icon("📚", "[history]") + " Select Conversation")
 	if m.conversation != nil {
 		title += hintStyle.Render(fmt.Sprintf("  (current: %s)", m.conversation.Title))
 	}
@@ -2313,7 +3431,7 @@ func (m Model) renderHistorySelector() string {
 
 	// Filter input
 	if m.historyFilter != "" {
-		filterLine := inputLabelStyle.Render("🔍 ") + m.historyFilter + "_"
+		filterLine := inputLabelStyle.Render(icon("🔍", "[search]")+" ") + m.historyFilter + "_"
 		content.WriteString(filterLine)
 		content.WriteString("\n\n")
 	}
@@ -2335,6 +3453,8 @@ func (m Model) renderHistorySelector() string {
 
 		if len(filtered) == 0 && len(m.historyList) == 0 {
 			content.WriteString(hintStyle.Render("  No saved conversations"))
+		} else if len(filtered) == 0 && m.historyFilter == "" {
+			content.WriteString(hintStyle.Render("  All conversations are archived"))
 		} else if len(filtered) == 0 {
 			content.WriteString(hintStyle.Render("  No conversations match filter"))
 		} else {
@@ -2379,6 +3499,9 @@ func (m Model) renderHistorySelector() string {
 					modelInfo,
 					hintStyle.Render(" - "+timeStr),
 				)
+				if conv.Archived {
+					line += " " + configDisabledStyle.Render("[archived]")
+				}
 
 				content.WriteString(line)
 				content.WriteString("\n")
@@ -2398,8 +3521,13 @@ func (m Model) renderHistorySelector() string {
 	shortcuts := []string{
 		statusKeyStyle.Render("↑↓") + statusDescStyle.Render(" Navigate"),
 		statusKeyStyle.Render("Enter") + statusDescStyle.Render(" Select"),
-		statusKeyStyle.Render("Esc") + statusDescStyle.Render(" Cancel"),
 	}
+	if m.historyArchived {
+		shortcuts = append(shortcuts, statusKeyStyle.Render("Tab")+statusDescStyle.Render(" Hide archived"))
+	} else {
+		shortcuts = append(shortcuts, statusKeyStyle.Render("Tab")+statusDescStyle.Render(" Show archived"))
+	}
+	shortcuts = append(shortcuts, statusKeyStyle.Render("Esc")+statusDescStyle.Render(" Cancel"))
 	statusBar := strings.Join(shortcuts, "  │  ")
 	content.WriteString(statusBar)
 
@@ -2495,25 +3623,33 @@ func (m Model) switchConversation(conv *history.Conversation) (tea.Model, tea.Cm
 	m.historyList = nil
 	m.historyCursor = 0
 	m.historyFilter = ""
+	m.historyArchived = false
 
 	// Set the new conversation
 	m.conversation = conv
 
-	// Load messages from the conversation
-	m.messages = make([]chatMessage, 0, len(conv.Messages))
-	for _, msg := range conv.Messages {
-		m.messages = append(m.messages, chatMessage{
-			role:     msg.Role,
-			content:  msg.Content,
-			thoughts: msg.Thoughts,
-		})
+	// Load messages from the conversation. One the server can resume from
+	// its metadata only needs the latest messages for display.
+	m.sentAttachments = nil
+	resumable := conv.CID != "" || conv.RID != "" || conv.RCID != ""
+	if resumable && m.resumeMessages > 0 && m.resumeMessages < m.messageCap {
+		m.loadRecentMessages(conv.Messages, m.resumeMessages)
+	} else {
+		m.loadConversationMessages(conv.Messages)
 	}
 
 	// Update session metadata for resumption
-	if m.session != nil && (conv.CID != "" || conv.RID != "" || conv.RCID != "") {
+	if m.session != nil && resumable {
 		m.session.SetMetadata(conv.CID, conv.RID, conv.RCID)
 	}
 
+	// Restore the conversation's gem (or clear the previous one)
+	gemName := conv.GemName
+	if conv.GemID != "" && gemName == "" {
+		gemName = conv.GemID
+	}
+	m.setActiveGem(conv.GemID, gemName)
+
 	// Update viewport with new messages
 	m.updateViewport()
 	m.viewport.GotoBottom()
@@ -2528,12 +3664,19 @@ func (m Model) startNewConversation() (tea.Model, tea.Cmd) {
 	m.historyList = nil
 	m.historyCursor = 0
 	m.historyFilter = ""
+	m.historyArchived = false
 
 	// Create new conversation if store is available
 	if m.fullHistoryStore != nil {
 		newConv, err := m.fullHistoryStore.CreateConversation(m.modelName)
 		if err == nil {
 			m.conversation = newConv
+			// The active gem carries over to the new conversation
+			if m.session != nil && m.session.GetGemID() != "" {
+				if err := m.saveGemToHistory(m.session.GetGemID(), m.activeGemName); err != nil {
+					m.err = err
+				}
+			}
 		} else {
 			m.err = fmt.Errorf("failed to create conversation: %w", err)
 		}
@@ -2541,6 +3684,8 @@ func (m Model) startNewConversation() (tea.Model, tea.Cmd) {
 
 	// Clear messages
 	m.messages = []chatMessage{}
+	m.spilledBefore = time.Time{}
+	m.sentAttachments = nil
 
 	// Reset session metadata
 	if m.session != nil {
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	apierrors "github.com/diogo/geminiweb/internal/errors"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// fallbackModel is the model offered when the selected one is unavailable.
+// It sends no model header, so the server picks its current default.
+var fallbackModel = models.ModelUnspecified
+
+// modelFallbackOffer is a pending offer to retry a failed send with the
+// fallback model
+type modelFallbackOffer struct {
+	prompt string
+	files  []*api.UploadedFile
+}
+
+// offerModelFallback checks whether a failed send was rejected because the
+// selected model is unavailable and, if so, offers to switch to the
+// fallback model and retry.
+func (m *Model) offerModelFallback(msg errMsg) {
+	if msg.prompt == "" || m.session == nil || !apierrors.IsModelUnavailableError(msg.err) {
+		return
+	}
+
+	current := m.session.GetModel().Name
+	if current == fallbackModel.Name {
+		m.err = fmt.Errorf("model unavailable: %w", msg.err)
+		return
+	}
+
+	m.modelFallback = &modelFallbackOffer{prompt: msg.prompt, files: msg.files}
+	m.err = fmt.Errorf("model %q is unavailable or deprecated - switch to the server default model and retry? (y/n)", current)
+}
+
+// updateModelFallback handles the y/n answer to a model fallback offer
+func (m Model) updateModelFallback(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.handleInterrupt()
+
+	case "y", "Y", "enter":
+		offer := m.modelFallback
+		m.modelFallback = nil
+		if m.isBusy() {
+			m.err = errBusy
+			return m, nil
+		}
+		m.session.SetModel(fallbackModel)
+		m.modelName = fallbackModel.Name
+		cmd := m.beginSend(func(m *Model) tea.Cmd {
+			m.err = nil
+			m.loading = true
+			m.animationFrame = 0
+			return tea.Batch(
+				m.sendPrompt(offer.prompt, offer.files),
+				animationTick(),
+			)
+		})
+		return m, cmd
+
+	case "n", "N", "esc":
+		m.modelFallback = nil
+		m.err = fmt.Errorf("model unavailable - restart with --model to pick another")
+		return m, nil
+	}
+
+	return m, nil
+}
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	apierrors "github.com/diogo/geminiweb/internal/errors"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// modelTrackingSession is a mockChatSession that remembers its model
+type modelTrackingSession struct {
+	mockChatSession
+	model models.Model
+}
+
+func (s *modelTrackingSession) GetModel() models.Model      { return s.model }
+func (s *modelTrackingSession) SetModel(model models.Model) { s.model = model }
+
+func unavailableModelErr(prompt string) errMsg {
+	err := apierrors.HandleErrorCode(apierrors.ErrCodeModelHeaderInvalid, "/generate", "gemini-old")
+	return errMsg{err: err, prompt: prompt}
+}
+
+func TestModelFallback(t *testing.T) {
+	t.Run("offers fallback and retries with default model on accept", func(t *testing.T) {
+		var sentModel models.Model
+		var sentPrompt string
+		session := &modelTrackingSession{model: models.Model25Flash}
+		session.sendMessageFunc = func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			sentModel = session.model
+			sentPrompt = prompt
+			return &models.ModelOutput{Candidates: []models.Candidate{{Text: "ok"}}}, nil
+		}
+		m := newTestModel(withSession(session), func(m *Model) { m.modelName = session.model.Name })
+
+		updated, _ := m.Update(unavailableModelErr("hello"))
+		m = updated.(Model)
+		if m.modelFallback == nil {
+			t.Fatal("expected a model fallback offer")
+		}
+		if m.err == nil || !strings.Contains(m.err.Error(), "(y/n)") || !strings.Contains(m.err.Error(), models.Model25Flash.Name) {
+			t.Errorf("unexpected offer message: %v", m.err)
+		}
+
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+		m = updated.(Model)
+		if m.modelFallback != nil {
+			t.Error("offer should be cleared after accepting")
+		}
+		if !m.loading {
+			t.Error("expected loading after accepting")
+		}
+		if m.modelName != models.ModelUnspecified.Name {
+			t.Errorf("modelName = %q, want %q", m.modelName, models.ModelUnspecified.Name)
+		}
+		if cmd == nil {
+			t.Fatal("expected a retry command")
+		}
+
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok || len(batch) == 0 {
+			t.Fatalf("expected a batch command, got %T", cmd())
+		}
+		if _, ok := batch[0]().(responseMsg); !ok {
+			t.Fatal("expected retried send to produce a response")
+		}
+		if sentPrompt != "hello" {
+			t.Errorf("retried prompt = %q, want %q", sentPrompt, "hello")
+		}
+		if sentModel.Name != models.ModelUnspecified.Name {
+			t.Errorf("retried with model %q, want %q", sentModel.Name, models.ModelUnspecified.Name)
+		}
+	})
+
+	t.Run("declining keeps model and clears offer", func(t *testing.T) {
+		session := &modelTrackingSession{model: models.Model25Flash}
+		m := newTestModel(withSession(session), func(m *Model) { m.modelName = session.model.Name })
+
+		updated, _ := m.Update(unavailableModelErr("hello"))
+		updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		m = updated.(Model)
+		if cmd != nil {
+			t.Error("declining should not send anything")
+		}
+		if m.modelFallback != nil {
+			t.Error("offer should be cleared after declining")
+		}
+		if session.model.Name != models.Model25Flash.Name || session.sendMessageCalled {
+			t.Error("declining should not change the model or re-send")
+		}
+		if m.err == nil || !strings.Contains(m.err.Error(), "--model") {
+			t.Errorf("expected hint to pick another model, got %v", m.err)
+		}
+	})
+
+	t.Run("no offer for other errors", func(t *testing.T) {
+		m := newTestModel(withSession(&modelTrackingSession{model: models.Model25Flash}), func(m *Model) { m.modelName = models.Model25Flash.Name })
+
+		updated, _ := m.Update(errMsg{err: errors.New("network down"), prompt: "hello"})
+		if updated.(Model).modelFallback != nil {
+			t.Error("unexpected offer for a non-model error")
+		}
+	})
+
+	t.Run("no offer when already on fallback model", func(t *testing.T) {
+		m := newTestModel(withSession(&modelTrackingSession{model: models.ModelUnspecified}), func(m *Model) { m.modelName = models.ModelUnspecified.Name })
+
+		updated, _ := m.Update(unavailableModelErr("hello"))
+		m = updated.(Model)
+		if m.modelFallback != nil {
+			t.Error("unexpected offer when already on the fallback model")
+		}
+		if m.err == nil || !strings.Contains(m.err.Error(), "model unavailable") {
+			t.Errorf("expected model unavailable error, got %v", m.err)
+		}
+	})
+}
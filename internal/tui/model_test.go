@@ -1,8 +1,11 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -11,6 +14,7 @@ import (
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/diogo/geminiweb/internal/api"
 	"github.com/diogo/geminiweb/internal/config"
@@ -476,6 +480,7 @@ type mockChatSession struct {
 	sendMessageFunc   func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error)
 	sendMessageCalled bool
 	gemID             string
+	metadata          []string
 }
 
 func (m *mockChatSession) SendMessage(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
@@ -486,10 +491,12 @@ func (m *mockChatSession) SendMessage(prompt string, files []*api.UploadedFile)
 	return nil, nil
 }
 
-func (m *mockChatSession) SetMetadata(cid, rid, rcid string) {}
+func (m *mockChatSession) SetMetadata(cid, rid, rcid string) {
+	m.metadata = []string{cid, rid, rcid}
+}
 
 func (m *mockChatSession) GetMetadata() []string {
-	return nil
+	return m.metadata
 }
 
 func (m *mockChatSession) CID() string {
@@ -521,7 +528,101 @@ func (m *mockChatSession) ChooseCandidate(index int) error {
 func (m *mockChatSession) SetGem(gemID string) { m.gemID = gemID }
 
 func (m *mockChatSession) GetGemID() string {
-	return ""
+	return m.gemID
+}
+
+// replyingSession returns a session that answers every prompt with reply,
+// recording the prompts in sent if it is not nil
+func replyingSession(reply string, sent *[]string) *mockChatSession {
+	return &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			if sent != nil {
+				*sent = append(*sent, prompt)
+			}
+			return &models.ModelOutput{Candidates: []models.Candidate{{Text: reply}}}, nil
+		},
+	}
+}
+
+// testModelOption customizes the Model built by newTestModel
+type testModelOption func(*Model)
+
+// newTestModel returns a ready 100x40 chat with an 80x20 viewport, an empty
+// textarea and a job registry, changed by opts
+func newTestModel(opts ...testModelOption) Model {
+	m := Model{
+		ready:    true,
+		width:    100,
+		height:   40,
+		textarea: textarea.New(),
+		viewport: viewport.New(80, 20),
+		jobs:     newJobRegistry(),
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return m
+}
+
+// withSession sets the chat session
+func withSession(session ChatSessionInterface) testModelOption {
+	return func(m *Model) { m.session = session }
+}
+
+// withMessages sets the chat messages
+func withMessages(messages ...chatMessage) testModelOption {
+	return func(m *Model) { m.messages = messages }
+}
+
+// withViewport resizes the viewport
+func withViewport(width, height int) testModelOption {
+	return func(m *Model) { m.viewport = viewport.New(width, height) }
+}
+
+// withInput focuses the textarea and types text into it
+func withInput(text string) testModelOption {
+	return func(m *Model) {
+		m.textarea.SetWidth(80)
+		m.textarea.Focus()
+		m.textarea.SetValue(text)
+	}
+}
+
+// withHistory saves the chat to conv in store
+func withHistory(store HistoryStoreInterface, conv *history.Conversation) testModelOption {
+	return func(m *Model) {
+		m.historyStore = store
+		m.conversation = conv
+	}
+}
+
+// withTools gives the chat a registry of just tools, run without security
+// policies
+func withTools(t *testing.T, tools ...toolexec.Tool) testModelOption {
+	t.Helper()
+	registry := toolexec.NewRegistry()
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	return func(m *Model) {
+		m.toolRegistry = registry
+		m.toolExecutor = toolexec.NewExecutor(registry)
+	}
+}
+
+// typeAndEnter sets the input and presses Enter
+func typeAndEnter(m Model, input string) (Model, tea.Cmd) {
+	m.textarea.SetValue(input)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model), cmd
+}
+
+// runCommand types a slash command and presses Enter
+func runCommand(m Model, command string) Model {
+	m, _ = typeAndEnter(m, command)
+	return m
 }
 
 func TestNewChatModel(t *testing.T) {
@@ -915,6 +1016,69 @@ func TestModel_GemsLoadedForChatMsg_Error(t *testing.T) {
 	}
 }
 
+func TestModel_GemSelector_CachedFallback(t *testing.T) {
+	client := createMockClient()
+	m := Model{
+		ready:    true,
+		client:   client,
+		width:    100,
+		height:   40,
+		textarea: textarea.New(),
+	}
+
+	// openSelector runs /gems and delivers the fetch result
+	openSelector := func(m Model) Model {
+		t.Helper()
+		m.textarea.SetValue("/gems")
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("expected a command loading gems")
+		}
+		updated, _ = updated.(Model).Update(cmd())
+		return updated.(Model)
+	}
+
+	m = openSelector(m)
+	if !m.selectingGem || len(m.gemsList) != 3 || len(m.cachedGems) != 3 {
+		t.Fatalf("first fetch should show and cache 3 gems, got %d shown and %d cached", len(m.gemsList), len(m.cachedGems))
+	}
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+
+	client.fetchErr = fmt.Errorf("network unreachable")
+	m = openSelector(m)
+	if !m.selectingGem {
+		t.Fatalf("selector should stay open on fetch failure, err = %v", m.err)
+	}
+	if len(m.gemsList) != 3 || m.gemsList[0].Name != m.cachedGems[0].Name {
+		t.Errorf("selector should show the cached gems, got %v", m.gemsList)
+	}
+	view := m.renderGemSelector()
+	for _, want := range []string{"Showing cached gems (refresh failed: network unreachable)", "Test Gem One", "System Gem"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("selector missing %q:\n%s", want, view)
+		}
+	}
+
+	// A later successful fetch clears the notice
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	client.fetchErr = nil
+	m = openSelector(updated.(Model))
+	if m.gemsFetchErr != nil || strings.Contains(m.renderGemSelector(), "cached gems") {
+		t.Error("notice should clear after a successful fetch")
+	}
+}
+
+func TestModel_GemSelector_FetchErrorWithoutCache(t *testing.T) {
+	m := Model{selectingGem: true, gemsLoading: true}
+	updated, _ := m.Update(gemsLoadedForChatMsg{err: fmt.Errorf("network unreachable")})
+	m = updated.(Model)
+
+	if m.selectingGem || m.err == nil || m.err.Error() != "network unreachable" {
+		t.Errorf("selector should close with the error, got selecting=%v err=%v", m.selectingGem, m.err)
+	}
+}
+
 func TestModel_RenderGemSelector_Empty(t *testing.T) {
 	model := Model{
 		width:        80,
@@ -932,6 +1096,11 @@ func TestModel_RenderGemSelector_Empty(t *testing.T) {
 	if !strings.Contains(view, "Select a Gem") {
 		t.Error("View should contain title")
 	}
+
+	// Tab only toggles archived conversations in the history selector
+	if strings.Contains(view, "archived") {
+		t.Error("gem selector should not offer the archived toggle")
+	}
 }
 
 func TestModel_RenderGemSelector_WithGems(t *testing.T) {
@@ -989,9 +1158,15 @@ type mockHistoryStoreForModel struct {
 	addMessageCalls     []struct{ id, role, content, thoughts string }
 	updateMetadataCalls []struct{ id, cid, rid, rcid string }
 	updateTitleCalls    []struct{ id, title string }
-	addMessageErr       error
-	updateMetadataErr   error
-	updateTitleErr      error
+	getMessagesCalls    []struct {
+		id     string
+		before time.Time
+		limit  int
+	}
+	storedMessages    []history.Message
+	addMessageErr     error
+	updateMetadataErr error
+	updateTitleErr    error
 }
 
 func (m *mockHistoryStoreForModel) AddMessage(id, role, content, thoughts string) error {
@@ -999,6 +1174,23 @@ func (m *mockHistoryStoreForModel) AddMessage(id, role, content, thoughts string
 	return m.addMessageErr
 }
 
+func (m *mockHistoryStoreForModel) GetMessagesBefore(id string, before time.Time, limit int) ([]history.Message, error) {
+	m.getMessagesCalls = append(m.getMessagesCalls, struct {
+		id     string
+		before time.Time
+		limit  int
+	}{id, before, limit})
+	end := 0
+	for end < len(m.storedMessages) && m.storedMessages[end].Timestamp.Before(before) {
+		end++
+	}
+	start := 0
+	if limit > 0 && end-limit > start {
+		start = end - limit
+	}
+	return m.storedMessages[start:end], nil
+}
+
 func (m *mockHistoryStoreForModel) UpdateMetadata(id, cid, rid, rcid string) error {
 	m.updateMetadataCalls = append(m.updateMetadataCalls, struct{ id, cid, rid, rcid string }{id, cid, rid, rcid})
 	return m.updateMetadataErr
@@ -1009,6 +1201,38 @@ func (m *mockHistoryStoreForModel) UpdateTitle(id, title string) error {
 	return m.updateTitleErr
 }
 
+type mockHistoryStoreWithGem struct {
+	mockHistoryStoreForModel
+	updateGemCalls []struct{ id, gemID, gemName string }
+	updateGemErr   error
+}
+
+func (m *mockHistoryStoreWithGem) UpdateGem(id, gemID, gemName string) error {
+	m.updateGemCalls = append(m.updateGemCalls, struct{ id, gemID, gemName string }{id, gemID, gemName})
+	return m.updateGemErr
+}
+
+func TestApplyConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SendKey = config.SendKeyCtrlEnter
+	cfg.StoreThoughts = false
+	cfg.AlwaysTrustedTools = []string{" calc ", ""}
+	cfg.MaxToolCallsPerTurn = 3
+
+	m := Model{textarea: createTextarea()}
+	applyConfig(&m, cfg)
+
+	if m.toolRegistry == nil || m.toolExecutor == nil {
+		t.Error("applyConfig should create the tooling")
+	}
+	if !m.ctrlEnterSends || m.textarea.Placeholder != inputPlaceholder(config.SendKeyCtrlEnter) {
+		t.Errorf("send key not applied: ctrlEnterSends=%v placeholder=%q", m.ctrlEnterSends, m.textarea.Placeholder)
+	}
+	if !m.discardThoughts || !m.trustedTools["calc"] || len(m.trustedTools) != 1 || m.maxToolCalls != 3 {
+		t.Errorf("settings not applied: discardThoughts=%v trustedTools=%v maxToolCalls=%d", m.discardThoughts, m.trustedTools, m.maxToolCalls)
+	}
+}
+
 func TestNewChatModelWithConversation(t *testing.T) {
 	mockSession := &mockChatSession{}
 	mockStore := &mockHistoryStoreForModel{}
@@ -1383,6 +1607,129 @@ func TestModel_AutoSaveOnResponse(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("stores empty thoughts when thoughts storage is disabled", func(t *testing.T) {
+		mockStore := &mockHistoryStoreForModel{}
+
+		ta := textarea.New()
+		ta.SetWidth(80)
+
+		m := Model{
+			ready:           true,
+			loading:         true,
+			messages:        []chatMessage{{role: "user", content: "test"}},
+			conversation:    &history.Conversation{ID: "conv-123"},
+			historyStore:    mockStore,
+			session:         &mockChatSessionWithMetadata{cid: "new-cid"},
+			textarea:        ta,
+			viewport:        viewport.New(80, 20),
+			discardThoughts: true,
+		}
+
+		output := &models.ModelOutput{
+			Candidates: []models.Candidate{{Text: "response text", Thoughts: "private reasoning"}},
+			Chosen:     0,
+		}
+		updatedModel, _ := m.Update(responseMsg{output: output})
+		typedModel := updatedModel.(Model)
+
+		if len(mockStore.addMessageCalls) != 1 {
+			t.Fatalf("expected 1 addMessage call, got %d", len(mockStore.addMessageCalls))
+		}
+		call := mockStore.addMessageCalls[0]
+		if call.content != "response text" {
+			t.Errorf("expected content 'response text', got '%s'", call.content)
+		}
+		if call.thoughts != "" {
+			t.Errorf("expected empty thoughts in history, got '%s'", call.thoughts)
+		}
+
+		last := typedModel.messages[len(typedModel.messages)-1]
+		if last.thoughts != "private reasoning" {
+			t.Errorf("in-session message thoughts = %q, want %q", last.thoughts, "private reasoning")
+		}
+		if !strings.Contains(typedModel.viewport.View(), "private reasoning") {
+			t.Error("thoughts should still be displayed in the session")
+		}
+	})
+
+	t.Run("stores the tidied text shown for a reply with tool calls", func(t *testing.T) {
+		mockStore := &mockHistoryStoreForModel{}
+
+		ta := textarea.New()
+		ta.SetWidth(80)
+
+		m := Model{
+			ready:        true,
+			loading:      true,
+			messages:     []chatMessage{{role: "user", content: "test"}},
+			conversation: &history.Conversation{ID: "conv-123"},
+			historyStore: mockStore,
+			session:      &mockChatSessionWithMetadata{cid: "new-cid"},
+			textarea:     ta,
+			viewport:     viewport.New(80, 20),
+		}
+
+		block := "```tool\n" + `{"name": "unknown_tool", "args": {}}` + "\n```"
+		text := "\n" + block + "\n\nLet me look.\n\n\n" + block + "\n \n\nBack soon.\n" + block + "\n\n"
+		output := &models.ModelOutput{Candidates: []models.Candidate{{Text: text}}}
+		updatedModel, _ := m.Update(responseMsg{output: output})
+		typedModel := updatedModel.(Model)
+
+		want := "Let me look.\n\nBack soon."
+		last := typedModel.messages[len(typedModel.messages)-1]
+		if last.content != want {
+			t.Errorf("displayed content = %q, want %q", last.content, want)
+		}
+		if len(mockStore.addMessageCalls) != 1 {
+			t.Fatalf("expected 1 addMessage call, got %d", len(mockStore.addMessageCalls))
+		}
+		if call := mockStore.addMessageCalls[0]; call.content != last.content {
+			t.Errorf("stored content = %q, want the displayed %q", call.content, last.content)
+		}
+	})
+}
+
+func TestModel_AutoSaveUpdatedReplyAsDisplayed(t *testing.T) {
+	block := "```tool\n" + `{"name": "unknown_tool", "args": {}}` + "\n```"
+	tests := []struct {
+		name string
+		msg  responseMsg
+	}{
+		{"continuation", responseMsg{continuation: true}},
+		{"regeneration", responseMsg{regenerated: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := history.NewStore(t.TempDir())
+			if err != nil {
+				t.Fatalf("NewStore() error = %v", err)
+			}
+			conv, _ := store.CreateConversation("test-model")
+			_ = store.AddMessage(conv.ID, "user", "test", "")
+			_ = store.AddMessage(conv.ID, "assistant", "Once upon", "")
+
+			m := newTestModel(withSession(&mockChatSession{}), withMessages(
+				chatMessage{role: "user", content: "test"},
+				chatMessage{role: "assistant", content: "Once upon"},
+			))
+			m.conversation = conv
+			m.historyStore = store
+
+			msg := tt.msg
+			msg.output = &models.ModelOutput{Candidates: []models.Candidate{{Text: block + "\n\n\na time.\n" + block}}}
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+
+			stored, _ := store.GetConversation(conv.ID)
+			if len(stored.Messages) != 2 {
+				t.Fatalf("stored %d messages, want the reply updated in place", len(stored.Messages))
+			}
+			if got, want := stored.Messages[1].Content, m.messages[1].content; got != want {
+				t.Errorf("stored content = %q, want the displayed %q", got, want)
+			}
+		})
+	}
 }
 
 func TestModel_AutoSaveOnSend(t *testing.T) {
@@ -1638,6 +1985,7 @@ type mockFullHistoryStore struct {
 	listErr            error
 	getErr             error
 	createErr          error
+	deletedIDs         []string
 }
 
 func (m *mockFullHistoryStore) ListConversations() ([]*history.Conversation, error) {
@@ -1653,6 +2001,7 @@ func (m *mockFullHistoryStore) CreateConversation(model string) (*history.Conver
 }
 
 func (m *mockFullHistoryStore) DeleteConversation(id string) error {
+	m.deletedIDs = append(m.deletedIDs, id)
 	return nil
 }
 
@@ -1681,6 +2030,55 @@ func TestFullHistoryStoreInterface(t *testing.T) {
 	var _ FullHistoryStore = &mockFullHistoryStore{}
 }
 
+func TestNarrowHistoryStore_GatedCommands(t *testing.T) {
+	const want = "history browsing requires a full store; message persistence is active"
+	exportPath := filepath.Join(t.TempDir(), "chat.md")
+
+	commands := []string{
+		"/history",
+		"/manage",
+		"/favorite",
+		"/delete",
+		"/diff conv-1 conv-2",
+		"/export " + exportPath,
+	}
+	for _, command := range commands {
+		t.Run(command, func(t *testing.T) {
+			store := &mockHistoryStoreForModel{}
+			m := NewChatModelWithConversation(nil, &mockChatSession{}, "gemini-2.5-flash",
+				&history.Conversation{ID: "conv-1", Title: "Chat"}, store)
+			if m.fullHistoryStore != nil {
+				t.Fatal("a narrow store should not be used as a full store")
+			}
+
+			m = runCommand(m, command)
+			if m.err == nil || m.err.Error() != want {
+				t.Errorf("err = %v, want %q", m.err, want)
+			}
+			if m.selectingHistory {
+				t.Error("the history selector should not open")
+			}
+		})
+	}
+
+	t.Run("messages are still saved", func(t *testing.T) {
+		store := &mockHistoryStoreForModel{}
+		m := NewChatModelWithConversation(nil, &mockChatSession{}, "gemini-2.5-flash",
+			&history.Conversation{ID: "conv-1"}, store)
+		m.saveMessageToHistory("user", "hello", "")
+		if len(store.addMessageCalls) != 1 {
+			t.Errorf("AddMessage calls = %d, want 1", len(store.addMessageCalls))
+		}
+	})
+
+	t.Run("no store keeps the generic message", func(t *testing.T) {
+		m := runCommand(Model{textarea: textarea.New()}, "/history")
+		if m.err == nil || m.err.Error() != "history not available" {
+			t.Errorf("err = %v, want history not available", m.err)
+		}
+	})
+}
+
 func TestFormatTimeAgo(t *testing.T) {
 	now := time.Now()
 
@@ -2036,6 +2434,53 @@ func TestModel_SwitchConversation(t *testing.T) {
 	}
 }
 
+func TestModel_SwitchConversation_RestoresGem(t *testing.T) {
+	newModel := func(session *mockChatSession) Model {
+		return Model{
+			ready:         true,
+			session:       session,
+			activeGemName: "Previous Gem",
+			textarea:      textarea.New(),
+			viewport:      viewport.New(80, 20),
+			width:         100,
+			height:        40,
+		}
+	}
+
+	t.Run("reapplies the conversation's gem", func(t *testing.T) {
+		session := &mockChatSession{gemID: "old-gem"}
+		conv := &history.Conversation{ID: "c1", GemID: "gem-42", GemName: "Coder"}
+
+		updatedModel, _ := newModel(session).switchConversation(conv)
+		typed := updatedModel.(Model)
+
+		if session.gemID != "gem-42" {
+			t.Errorf("session gem = %q, want gem-42", session.gemID)
+		}
+		if typed.activeGemName != "Coder" {
+			t.Errorf("activeGemName = %q, want Coder", typed.activeGemName)
+		}
+		if !strings.Contains(typed.View(), "Coder") {
+			t.Error("header should show the restored gem")
+		}
+	})
+
+	t.Run("clears gem for conversation without one", func(t *testing.T) {
+		session := &mockChatSession{gemID: "old-gem"}
+		conv := &history.Conversation{ID: "c2"}
+
+		updatedModel, _ := newModel(session).switchConversation(conv)
+		typed := updatedModel.(Model)
+
+		if session.gemID != "" {
+			t.Errorf("session gem = %q, want empty", session.gemID)
+		}
+		if typed.activeGemName != "" {
+			t.Errorf("activeGemName = %q, want empty", typed.activeGemName)
+		}
+	})
+}
+
 func TestModel_StartNewConversation(t *testing.T) {
 	newConv := &history.Conversation{
 		ID:    "new-conv",
@@ -2087,6 +2532,31 @@ func TestModel_StartNewConversation(t *testing.T) {
 	}
 }
 
+func TestModel_StartNewConversation_CarriesActiveGem(t *testing.T) {
+	newConv := &history.Conversation{ID: "new-conv"}
+	store := &mockHistoryStoreWithGem{}
+
+	m := newTestModel(withSession(&mockChatSession{gemID: "gem-1"}), func(m *Model) {
+		m.fullHistoryStore = &mockFullHistoryStore{createConversation: newConv}
+		m.historyStore = store
+		m.activeGemName = "Coder"
+	})
+
+	updatedModel, _ := m.startNewConversation()
+	typed := updatedModel.(Model)
+
+	if len(store.updateGemCalls) != 1 {
+		t.Fatalf("expected 1 UpdateGem call, got %d", len(store.updateGemCalls))
+	}
+	call := store.updateGemCalls[0]
+	if call.id != "new-conv" || call.gemID != "gem-1" || call.gemName != "Coder" {
+		t.Errorf("unexpected UpdateGem call: %+v", call)
+	}
+	if typed.conversation.GemID != "gem-1" {
+		t.Errorf("conversation GemID = %q, want gem-1", typed.conversation.GemID)
+	}
+}
+
 func TestModel_RenderHistorySelector(t *testing.T) {
 	convs := []*history.Conversation{
 		{ID: "1", Title: "Chat 1", Model: "gemini-2.5-flash", UpdatedAt: time.Now()},
@@ -2890,13 +3360,14 @@ type mockGeminiClientWithUpload struct {
 	fetchGemsErr     error
 }
 
-func (m *mockGeminiClientWithUpload) Init() error                 { return nil }
-func (m *mockGeminiClientWithUpload) Close()                      {}
-func (m *mockGeminiClientWithUpload) GetAccessToken() string      { return "" }
-func (m *mockGeminiClientWithUpload) GetCookies() *config.Cookies { return nil }
-func (m *mockGeminiClientWithUpload) GetModel() models.Model      { return models.Model{} }
-func (m *mockGeminiClientWithUpload) SetModel(model models.Model) {}
-func (m *mockGeminiClientWithUpload) IsClosed() bool              { return false }
+func (m *mockGeminiClientWithUpload) Init() error                    { return nil }
+func (m *mockGeminiClientWithUpload) Ping(ctx context.Context) error { return nil }
+func (m *mockGeminiClientWithUpload) Close()                         {}
+func (m *mockGeminiClientWithUpload) GetAccessToken() string         { return "" }
+func (m *mockGeminiClientWithUpload) GetCookies() *config.Cookies    { return nil }
+func (m *mockGeminiClientWithUpload) GetModel() models.Model         { return models.Model{} }
+func (m *mockGeminiClientWithUpload) SetModel(model models.Model)    {}
+func (m *mockGeminiClientWithUpload) IsClosed() bool                 { return false }
 func (m *mockGeminiClientWithUpload) StartChat(model ...models.Model) *api.ChatSession {
 	return nil
 }
@@ -2909,7 +3380,7 @@ func (m *mockGeminiClientWithUpload) GenerateContent(prompt string, opts *api.Ge
 func (m *mockGeminiClientWithUpload) UploadImage(filePath string) (*api.UploadedImage, error) {
 	return nil, nil
 }
-func (m *mockGeminiClientWithUpload) UploadFile(filePath string) (*api.UploadedFile, error) {
+func (m *mockGeminiClientWithUpload) UploadFile(filePath string, opts ...api.UploadOption) (*api.UploadedFile, error) {
 	m.uploadFileCalled = true
 	m.uploadFilePath = filePath
 	return m.uploadFileResult, m.uploadFileErr
@@ -3449,6 +3920,38 @@ func TestRenderImageLinks(t *testing.T) {
 	})
 }
 
+func TestModel_ResponseMsgWithInlineData(t *testing.T) {
+	text := "Chart:\n![chart](data:image/png;base64,AQID)\nDone."
+	m := Model{
+		textarea: createTextarea(),
+		session:  &mockChatSession{},
+		ready:    true,
+		viewport: viewport.New(96, 20),
+		loading:  true,
+	}
+	output := &models.ModelOutput{Candidates: []models.Candidate{{
+		Text:  text,
+		Parts: models.ParseParts(text, nil),
+	}}}
+
+	newM, _ := m.Update(responseMsg{output: output})
+	updated := newM.(Model)
+
+	if len(updated.messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(updated.messages))
+	}
+	want := "Chart:\n`[inline image/png, 3 B]`\nDone."
+	if got := updated.messages[0].content; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+
+	// Responses without inline data keep their text unchanged
+	plain := &models.ModelOutput{Candidates: []models.Candidate{{Text: "  plain  "}}}
+	if got := responseDisplayText(plain); got != "  plain  " {
+		t.Errorf("responseDisplayText() = %q, want unchanged", got)
+	}
+}
+
 func TestModel_ResponseMsgWithImages(t *testing.T) {
 	t.Run("extracts images from response", func(t *testing.T) {
 		ta := createTextarea()
@@ -3653,6 +4156,243 @@ func TestModel_UpdateViewportWithImages(t *testing.T) {
 	})
 }
 
+func TestModel_UpdateViewportWithSources(t *testing.T) {
+	newModel := func(sources []models.Source) Model {
+		return Model{
+			textarea: createTextarea(),
+			spinner:  spinner.New(),
+			ready:    true,
+			width:    100,
+			height:   40,
+			viewport: viewport.New(96, 30),
+			messages: []chatMessage{
+				{role: "assistant", content: "Go 1.24 is out", sources: sources},
+			},
+		}
+	}
+
+	t.Run("renders sources section when present", func(t *testing.T) {
+		m := newModel([]models.Source{
+			{Title: "Go 1.24 is released", URL: "https://go.dev/blog/go1.24", Snippet: "The Go team is happy"},
+			{URL: "https://go.dev/doc/go1.24"},
+		})
+
+		m.updateViewport()
+		content := m.viewport.View()
+
+		for _, want := range []string{"Sources (2)", "Go 1.24 is released", "https://go.dev/blog/go1.24", "The Go team is happy", "Source 2"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("viewport should contain %q", want)
+			}
+		}
+	})
+
+	t.Run("omits sources section when there are none", func(t *testing.T) {
+		m := newModel(nil)
+
+		m.updateViewport()
+		if strings.Contains(m.viewport.View(), "Sources") {
+			t.Error("viewport should not show a sources section without sources")
+		}
+	})
+
+	t.Run("response message carries sources", func(t *testing.T) {
+		m := newModel(nil)
+		m.messages = nil
+		output := &models.ModelOutput{Candidates: []models.Candidate{{
+			Text:    "cited answer",
+			Sources: []models.Source{{Title: "Ref", URL: "https://example.com/ref"}},
+		}}}
+
+		updated, _ := m.Update(responseMsg{output: output})
+		m = updated.(Model)
+
+		if len(m.messages) != 1 || len(m.messages[0].sources) != 1 {
+			t.Fatalf("expected assistant message with one source, got %+v", m.messages)
+		}
+		if !strings.Contains(m.viewport.View(), "https://example.com/ref") {
+			t.Error("viewport should render the response's sources")
+		}
+	})
+}
+
+func TestModel_UpdateViewportWithTimestamps(t *testing.T) {
+	sent := time.Now().Add(-5 * time.Minute)
+	newModel := func(show bool) Model {
+		return Model{
+			ready:          true,
+			textarea:       textarea.New(),
+			viewport:       viewport.New(96, 30),
+			width:          100,
+			height:         40,
+			showTimestamps: show,
+			messages: []chatMessage{
+				{role: "user", content: "hello", timestamp: sent},
+				{role: "assistant", content: "hi there", timestamp: time.Now().Add(-2 * time.Hour)},
+				{role: "tool", content: "Tool: bash"},
+			},
+		}
+	}
+
+	t.Run("shows relative time when enabled", func(t *testing.T) {
+		m := newModel(true)
+		m.updateViewport()
+		content := m.viewport.View()
+
+		for _, want := range []string{formatTimeAgo(sent), "5m ago", "2h ago"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("viewport should contain %q", want)
+			}
+		}
+	})
+
+	t.Run("hidden when disabled", func(t *testing.T) {
+		m := newModel(false)
+		m.updateViewport()
+		if strings.Contains(m.viewport.View(), "ago") {
+			t.Error("viewport should not show timestamps when disabled")
+		}
+	})
+
+	t.Run("messages without a timestamp have a plain label", func(t *testing.T) {
+		m := newModel(true)
+		label := m.messageLabel("Tool", m.messages[2])
+		if label != "Tool" {
+			t.Errorf("messageLabel() = %q, want plain label", label)
+		}
+	})
+
+	t.Run("loaded history uses stored time", func(t *testing.T) {
+		stored := time.Now().Add(-3 * 24 * time.Hour)
+		conv := &history.Conversation{ID: "c1", Messages: []history.Message{
+			{Role: "user", Content: "old question", Timestamp: stored},
+		}}
+
+		updated, _ := newModel(true).switchConversation(conv)
+		m := updated.(Model)
+
+		if !m.messages[0].timestamp.Equal(stored) {
+			t.Errorf("timestamp = %v, want stored %v", m.messages[0].timestamp, stored)
+		}
+		if !strings.Contains(m.viewport.View(), "3d ago") {
+			t.Error("viewport should show the stored message time")
+		}
+	})
+
+	t.Run("appended messages are stamped", func(t *testing.T) {
+		m := newModel(true)
+		m.messages = nil
+		before := time.Now()
+		updated, _ := m.Update(responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: "reply"}}}})
+		m = updated.(Model)
+
+		if len(m.messages) != 1 || m.messages[0].timestamp.Before(before) {
+			t.Errorf("expected a freshly stamped message, got %+v", m.messages)
+		}
+	})
+}
+
+func TestModel_TimestampsCommand(t *testing.T) {
+	m := Model{textarea: textarea.New(), viewport: viewport.New(80, 20)}
+
+	updated, _ := m.handleTimestampsCommand("")
+	m = updated.(Model)
+	if !m.showTimestamps {
+		t.Error("/timestamps should toggle timestamps on")
+	}
+
+	updated, _ = m.handleTimestampsCommand("off")
+	m = updated.(Model)
+	if m.showTimestamps {
+		t.Error("/timestamps off should hide timestamps")
+	}
+
+	updated, _ = m.handleTimestampsCommand("sometimes")
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.err.Error(), "usage") {
+		t.Errorf("expected usage error, got %v", m.err)
+	}
+}
+
+func TestModel_UpdateViewportCompact(t *testing.T) {
+	newModel := func(compact bool) Model {
+		return Model{
+			ready:           true,
+			textarea:        textarea.New(),
+			viewport:        viewport.New(60, 40),
+			compactMessages: compact,
+			messages: []chatMessage{
+				{role: "user", content: strings.Repeat("ab ", 60)},
+				{role: "assistant", content: "hi there"},
+				{role: "tool", content: "Tool: bash"},
+			},
+		}
+	}
+	// widestText is the widest line of message text, ignoring padding and
+	// borders
+	widestText := func(m Model) int {
+		widest := 0
+		for _, line := range strings.Split(m.viewport.View(), "\n") {
+			line = strings.Trim(line, " │")
+			if !strings.Contains(line, "ab ab") {
+				continue
+			}
+			if w := lipgloss.Width(line); w > widest {
+				widest = w
+			}
+		}
+		return widest
+	}
+
+	bordered := newModel(false)
+	bordered.updateViewport()
+	if !strings.ContainsAny(bordered.viewport.View(), "╭╰│─") {
+		t.Fatal("bordered messages should contain border characters")
+	}
+
+	compact := newModel(true)
+	compact.updateViewport()
+	content := compact.viewport.View()
+	if strings.ContainsAny(content, "╭╮╰╯│─┌┐└┘") {
+		t.Errorf("compact messages should not contain border characters:\n%s", content)
+	}
+	for _, want := range []string{"You", "Gemini", "Tool: bash"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("compact viewport should contain %q", want)
+		}
+	}
+
+	if got, limit := widestText(compact), 60-compactIndent-len("ab "); got < limit {
+		t.Errorf("compact text spans %d columns, want at least %d", got, limit)
+	}
+	if widestText(compact) <= widestText(bordered) {
+		t.Errorf("compact text (%d columns) should be wider than bordered text (%d columns)",
+			widestText(compact), widestText(bordered))
+	}
+}
+
+func TestModel_CompactCommand(t *testing.T) {
+	m := Model{textarea: textarea.New(), viewport: viewport.New(80, 20)}
+
+	updated, _ := m.handleCompactCommand("")
+	m = updated.(Model)
+	if !m.compactMessages {
+		t.Error("/compact should toggle compact messages on")
+	}
+
+	updated, _ = m.handleCompactCommand("off")
+	m = updated.(Model)
+	if m.compactMessages {
+		t.Error("/compact off should restore bubbles")
+	}
+
+	updated, _ = m.handleCompactCommand("tiny")
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.err.Error(), "usage") {
+		t.Errorf("expected usage error, got %v", m.err)
+	}
+}
+
 // ==================== Export Command Tests ====================
 
 func TestParseExportArgs(t *testing.T) {
@@ -3724,7 +4464,7 @@ func TestParseExportArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path, format, err := parseExportArgs(tt.args)
+			path, format, _, err := parseExportArgs(tt.args)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("parseExportArgs() error = %v, wantErr %v", err, tt.wantErr)
@@ -4091,6 +4831,46 @@ func TestModel_Update_CtrlG(t *testing.T) {
 	})
 }
 
+// TestModel_Update_CtrlL tests the Ctrl+L shortcut to clear feedback
+func TestModel_Update_CtrlL(t *testing.T) {
+	for _, loading := range []bool{false, true} {
+		t.Run(fmt.Sprintf("loading=%v", loading), func(t *testing.T) {
+			ta := textarea.New()
+			ta.SetValue("draft")
+
+			m := Model{
+				ready:    true,
+				textarea: ta,
+				viewport: viewport.New(80, 20),
+				width:    100,
+				height:   40,
+				loading:  loading,
+				messages: []chatMessage{{role: "user", content: "Hello"}},
+				err:      fmt.Errorf("something failed"),
+			}
+
+			updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+			typedModel := updatedModel.(Model)
+
+			if typedModel.err != nil {
+				t.Errorf("err = %v, want nil", typedModel.err)
+			}
+			if cmd == nil {
+				t.Error("expected a redraw command")
+			}
+			if typedModel.loading != loading {
+				t.Errorf("loading = %v, want %v", typedModel.loading, loading)
+			}
+			if typedModel.textarea.Value() != "draft" {
+				t.Errorf("textarea = %q, want %q", typedModel.textarea.Value(), "draft")
+			}
+			if len(typedModel.messages) != 1 {
+				t.Errorf("messages = %d, want 1", len(typedModel.messages))
+			}
+		})
+	}
+}
+
 // TestModel_Update_CtrlE tests the Ctrl+E shortcut to export conversation
 func TestModel_Update_CtrlE(t *testing.T) {
 	t.Run("exports conversation with default filename", func(t *testing.T) {
@@ -4212,6 +4992,60 @@ func TestModel_FormatError(t *testing.T) {
 }
 
 // TestModel_UpdateGemSelection tests the updateGemSelection function
+func TestModel_GemSelection_PersistsToConversation(t *testing.T) {
+	gems := []*models.Gem{
+		{ID: "gem-1", Name: "First Gem"},
+		{ID: "gem-2", Name: "Second Gem"},
+	}
+	store := &mockHistoryStoreWithGem{}
+	session := &mockChatSession{}
+	conv := &history.Conversation{ID: "conv-1"}
+
+	m := Model{
+		session:      session,
+		conversation: conv,
+		historyStore: store,
+		selectingGem: true,
+		gemsList:     gems,
+		gemsCursor:   1,
+	}
+
+	updatedModel, _ := m.updateGemSelection(tea.KeyMsg{Type: tea.KeyEnter})
+	typed := updatedModel.(Model)
+
+	if session.gemID != "gem-2" {
+		t.Errorf("session gem = %q, want gem-2", session.gemID)
+	}
+	if len(store.updateGemCalls) != 1 {
+		t.Fatalf("expected 1 UpdateGem call, got %d", len(store.updateGemCalls))
+	}
+	call := store.updateGemCalls[0]
+	if call.id != "conv-1" || call.gemID != "gem-2" || call.gemName != "Second Gem" {
+		t.Errorf("unexpected UpdateGem call: %+v", call)
+	}
+	if typed.conversation.GemID != "gem-2" || typed.conversation.GemName != "Second Gem" {
+		t.Errorf("conversation gem not updated: %q/%q", typed.conversation.GemID, typed.conversation.GemName)
+	}
+}
+
+func TestModel_GemSelection_ReportsSaveFailure(t *testing.T) {
+	store := &mockHistoryStoreWithGem{updateGemErr: errors.New("disk full")}
+	m := Model{
+		session:      &mockChatSession{},
+		conversation: &history.Conversation{ID: "conv-1"},
+		historyStore: store,
+		selectingGem: true,
+		gemsList:     []*models.Gem{{ID: "gem-1", Name: "First Gem"}},
+	}
+
+	updatedModel, _ := m.updateGemSelection(tea.KeyMsg{Type: tea.KeyEnter})
+	typed := updatedModel.(Model)
+
+	if typed.err == nil || !strings.Contains(typed.err.Error(), "failed to save gem: disk full") {
+		t.Errorf("err = %v, want gem save failure", typed.err)
+	}
+}
+
 func TestModel_UpdateGemSelection(t *testing.T) {
 	// Create mock gems
 	gems := []*models.Gem{
@@ -4446,7 +5280,7 @@ func TestModel_ExportFromMemory(t *testing.T) {
 		tmpFile := "/tmp/test_export_md_" + fmt.Sprintf("%d", time.Now().UnixNano()) + ".md"
 		defer func() { _ = os.Remove(tmpFile) }()
 
-		cmd := exportFromMemory(messages, "Test Chat", "markdown", tmpFile)
+		cmd := exportFromMemory(messages, "Test Chat", "markdown", tmpFile, "")
 		result := cmd()
 
 		if msg, ok := result.(exportResultMsg); ok {
@@ -4468,7 +5302,7 @@ func TestModel_ExportFromMemory(t *testing.T) {
 		tmpFile := "/tmp/test_export_json_" + fmt.Sprintf("%d", time.Now().UnixNano()) + ".json"
 		defer func() { _ = os.Remove(tmpFile) }()
 
-		cmd := exportFromMemory(messages, "Test Chat", "json", tmpFile)
+		cmd := exportFromMemory(messages, "Test Chat", "json", tmpFile, "")
 		result := cmd()
 
 		if msg, ok := result.(exportResultMsg); ok {
@@ -4489,7 +5323,7 @@ func TestModel_ExportFromMemory(t *testing.T) {
 		_ = os.WriteFile(tmpFile, []byte("existing"), 0644)
 		defer func() { _ = os.Remove(tmpFile) }()
 
-		cmd := exportFromMemory(messages, "Test Chat", "markdown", tmpFile)
+		cmd := exportFromMemory(messages, "Test Chat", "markdown", tmpFile, "")
 		result := cmd()
 
 		if msg, ok := result.(exportResultMsg); ok {
@@ -4517,6 +5351,161 @@ func TestJsonMarshalIndent(t *testing.T) {
 	}
 }
 
+// TestModel_CopyAllCommand tests the /copy-all command
+func TestModel_CopyAllCommand(t *testing.T) {
+	messages := []chatMessage{
+		{role: "user", content: "Hello"},
+		{role: "assistant", content: "Hi there!", thoughts: "Greeting"},
+		{role: "tool", content: "Tool: bash"},
+	}
+
+	stubClipboard := func(t *testing.T) *string {
+		t.Helper()
+		var copied string
+		orig := writeClipboard
+		writeClipboard = func(text string) error {
+			copied = text
+			return nil
+		}
+		t.Cleanup(func() { writeClipboard = orig })
+		return &copied
+	}
+
+	t.Run("copies markdown matching the export format", func(t *testing.T) {
+		copied := stubClipboard(t)
+		tmpFile := filepath.Join(t.TempDir(), "export.md")
+
+		result := exportFromMemory(messages, "Conversation", "markdown", tmpFile, "")()
+		if msg := result.(exportResultMsg); msg.err != nil {
+			t.Fatalf("export failed: %v", msg.err)
+		}
+		exported, err := os.ReadFile(tmpFile)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+
+		m := Model{messages: messages, textarea: textarea.New()}
+		updated, _ := m.handleCopyAllCommand("")
+		typed := updated.(Model)
+
+		if *copied != string(exported) {
+			t.Errorf("copied markdown differs from export:\ncopied:\n%s\nexported:\n%s", *copied, exported)
+		}
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "Copied 3 message(s)") {
+			t.Errorf("expected success feedback, got %v", typed.err)
+		}
+		if strings.Contains(*copied, "Greeting") {
+			t.Error("thoughts should not be included without --thoughts")
+		}
+	})
+
+	t.Run("includes thoughts with flag", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{messages: messages, textarea: textarea.New()}
+		_, _ = m.handleCopyAllCommand("--thoughts")
+
+		if !strings.Contains(*copied, "<summary>💭 Thinking</summary>") || !strings.Contains(*copied, "Greeting") {
+			t.Errorf("expected thoughts block, got:\n%s", *copied)
+		}
+	})
+
+	t.Run("uses conversation title", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{
+			messages:     messages,
+			conversation: &history.Conversation{ID: "c1", Title: "My Chat"},
+			textarea:     textarea.New(),
+		}
+		_, _ = m.handleCopyAllCommand("")
+
+		if !strings.HasPrefix(*copied, "# My Chat\n") {
+			t.Errorf("expected conversation title header, got:\n%s", *copied)
+		}
+	})
+
+	t.Run("nothing to copy", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{textarea: textarea.New()}
+		updated, _ := m.handleCopyAllCommand("")
+		typed := updated.(Model)
+
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "nothing to copy") {
+			t.Errorf("expected nothing-to-copy error, got %v", typed.err)
+		}
+		if *copied != "" {
+			t.Error("clipboard should not be written")
+		}
+	})
+
+	t.Run("clipboard failure", func(t *testing.T) {
+		orig := writeClipboard
+		writeClipboard = func(string) error { return fmt.Errorf("no display") }
+		defer func() { writeClipboard = orig }()
+
+		m := Model{messages: messages, textarea: textarea.New()}
+		updated, _ := m.handleCopyAllCommand("")
+		typed := updated.(Model)
+
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "failed to copy") {
+			t.Errorf("expected failure feedback, got %v", typed.err)
+		}
+	})
+
+	t.Run("rejects unknown flags", func(t *testing.T) {
+		_ = stubClipboard(t)
+		m := Model{messages: messages, textarea: textarea.New()}
+		updated, _ := m.handleCopyAllCommand("--bogus")
+		typed := updated.(Model)
+
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "usage") {
+			t.Errorf("expected usage error, got %v", typed.err)
+		}
+	})
+
+	t.Run("copies plain text with flag", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{
+			messages: []chatMessage{
+				{role: "user", content: "Show me"},
+				{role: "assistant", content: "## Steps\n\n* **first**\n* second\n\n```sh\necho *hi*\n```"},
+			},
+			textarea: textarea.New(),
+		}
+		updated, _ := m.handleCopyAllCommand("--plain")
+		typed := updated.(Model)
+
+		want := "Conversation\n\nUser:\n\nShow me\n\nGemini:\n\nSteps\n\n- first\n- second\n\necho *hi*"
+		if *copied != want {
+			t.Errorf("copied plain text =\n%q\nwant\n%q", *copied, want)
+		}
+		if typed.err == nil || !strings.Contains(typed.err.Error(), "as plain text") {
+			t.Errorf("expected plain text feedback, got %v", typed.err)
+		}
+	})
+
+	t.Run("alt+shift+c copies plain text", func(t *testing.T) {
+		copied := stubClipboard(t)
+		m := Model{messages: messages, textarea: textarea.New(), ready: true}
+		_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}, Alt: true})
+
+		if *copied == "" || strings.Contains(*copied, "**User:**") {
+			t.Errorf("expected plain text copy, got:\n%s", *copied)
+		}
+	})
+
+	t.Run("command is routed", func(t *testing.T) {
+		copied := stubClipboard(t)
+		ta := textarea.New()
+		ta.SetValue("/copy-all")
+		m := Model{messages: messages, textarea: ta, ready: true}
+		_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+		if *copied == "" {
+			t.Error("expected /copy-all to write to the clipboard")
+		}
+	})
+}
+
 // TestNewChatModel_WithClient tests the NewChatModel constructor with a real client
 func TestNewChatModel_WithClient(t *testing.T) {
 	client := &mockGeminiClientWithUpload{}
@@ -4741,6 +5730,37 @@ func TestModel_DownloadSelectedImages(t *testing.T) {
 			t.Errorf("unexpected result: err=%v, count=%d", msg.err, msg.count)
 		}
 	})
+
+	t.Run("reports skipped unsupported images", func(t *testing.T) {
+		images := []models.WebImage{{URL: "https://example.com/1.jpg"}, {URL: "https://example.com/2.heic"}}
+		mockClient := &mockGeminiClientWithDownload{}
+		mockClient.downloadFunc = func(output *models.ModelOutput, indices []int, opts api.ImageDownloadOptions) ([]string, error) {
+			if !opts.SkipUnsupported {
+				t.Error("expected SkipUnsupported to be set")
+			}
+			return []string{"/tmp/1.jpg"}, &api.SkippedImagesError{Skipped: []api.SkippedImage{
+				{URL: images[1].URL, ContentType: "image/heic", Reason: "unsupported image type image/heic"},
+			}}
+		}
+
+		m := Model{client: mockClient, lastOutput: &models.ModelOutput{
+			Candidates: []models.Candidate{{WebImages: images}},
+		}}
+		msg, ok := m.downloadSelectedImages([]int{0, 1}, "/tmp")().(downloadImagesResultMsg)
+		if !ok {
+			t.Fatal("expected downloadImagesResultMsg")
+		}
+		if msg.err != nil || msg.count != 1 || len(msg.skipped) != 1 {
+			t.Fatalf("unexpected result: err=%v, count=%d, skipped=%v", msg.err, msg.count, msg.skipped)
+		}
+
+		updated, _ := m.Update(msg)
+		feedback := updated.(Model).err
+		if feedback == nil || !strings.Contains(feedback.Error(), "✓ Downloaded 1 image(s)") ||
+			!strings.Contains(feedback.Error(), "1 skipped: unsupported image type image/heic") {
+			t.Errorf("unexpected feedback: %v", feedback)
+		}
+	})
 }
 
 func TestModel_UploadFile(t *testing.T) {
@@ -4861,7 +5881,7 @@ func TestModel_ExportCommand_Extended(t *testing.T) {
 			},
 		}
 
-		cmd := exportCommand(mockStore, "conv-123", "markdown", tmpFile)
+		cmd := exportCommand(mockStore, "conv-123", "markdown", tmpFile, "")
 		result := cmd()
 
 		msg, ok := result.(exportResultMsg)
@@ -4885,7 +5905,7 @@ func TestModel_ExportCommand_Extended(t *testing.T) {
 			},
 		}
 
-		cmd := exportCommand(mockStore, "conv-123", "markdown", tmpFile)
+		cmd := exportCommand(mockStore, "conv-123", "markdown", tmpFile, "")
 		result := cmd()
 
 		msg, ok := result.(exportResultMsg)
@@ -5183,6 +6203,7 @@ type mockGeminiClientWithDownload struct {
 }
 
 func (m *mockGeminiClientWithDownload) Init() error                                      { return nil }
+func (m *mockGeminiClientWithDownload) Ping(ctx context.Context) error                   { return nil }
 func (m *mockGeminiClientWithDownload) Close()                                           {}
 func (m *mockGeminiClientWithDownload) GetAccessToken() string                           { return "" }
 func (m *mockGeminiClientWithDownload) GetCookies() *config.Cookies                      { return nil }
@@ -5199,7 +6220,7 @@ func (m *mockGeminiClientWithDownload) GenerateContent(prompt string, opts *api.
 func (m *mockGeminiClientWithDownload) UploadImage(filePath string) (*api.UploadedImage, error) {
 	return nil, nil
 }
-func (m *mockGeminiClientWithDownload) UploadFile(filePath string) (*api.UploadedFile, error) {
+func (m *mockGeminiClientWithDownload) UploadFile(filePath string, opts ...api.UploadOption) (*api.UploadedFile, error) {
 	return nil, nil
 }
 func (m *mockGeminiClientWithDownload) UploadText(content string, fileName string) (*api.UploadedFile, error) {
@@ -5966,3 +6987,138 @@ func TestModel_HandleFileCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestModel_PromptPrefixSuffix(t *testing.T) {
+	t.Run("wraps typed prompt but displays the original", func(t *testing.T) {
+		var sent string
+		session := &mockChatSession{
+			sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+				sent = prompt
+				return &models.ModelOutput{Candidates: []models.Candidate{{Text: "ok"}}}, nil
+			},
+		}
+		ta := textarea.New()
+		ta.SetValue("What changed in Go 1.24?")
+
+		m := Model{
+			ready:        true,
+			session:      session,
+			textarea:     ta,
+			viewport:     viewport.New(80, 20),
+			width:        100,
+			height:       40,
+			promptPrefix: "Always cite sources.",
+			promptSuffix: "Answer briefly.",
+		}
+
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+
+		batch, ok := cmd().(tea.BatchMsg)
+		if !ok || len(batch) == 0 {
+			t.Fatal("expected batched send command")
+		}
+		if _, ok := batch[0]().(responseMsg); !ok {
+			t.Fatal("expected the first command to send the message")
+		}
+
+		want := "Always cite sources.\n\nWhat changed in Go 1.24?\n\nAnswer briefly."
+		if sent != want {
+			t.Errorf("sent prompt = %q, want %q", sent, want)
+		}
+		if len(m.messages) != 1 || m.messages[0].content != "What changed in Go 1.24?" {
+			t.Errorf("displayed message = %+v, want original input", m.messages)
+		}
+	})
+
+	t.Run("applied after persona formatting", func(t *testing.T) {
+		m := Model{
+			persona:      &config.Persona{Name: "coder", SystemPrompt: "You are a coder."},
+			promptPrefix: "PREFIX",
+		}
+		var sent string
+		m.session = &mockChatSession{
+			sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+				sent = prompt
+				return &models.ModelOutput{}, nil
+			},
+		}
+		m.sendMessageWithAttachments("hello")()
+
+		if !strings.HasPrefix(sent, "PREFIX\n\n") || !strings.Contains(sent, "You are a coder.") {
+			t.Errorf("sent prompt = %q, want prefix before persona-formatted prompt", sent)
+		}
+	})
+
+	t.Run("tool results are not wrapped", func(t *testing.T) {
+		var executed []string
+		var sent string
+		m := newToolPlanTestModel(t, &executed, &sent)
+		m.toolPlanReview = false
+		m.autoApproveTools = true
+		m.promptPrefix = "PREFIX"
+		m.promptSuffix = "SUFFIX"
+
+		updated, cmd := m.Update(toolCallsResponse())
+		m = updated.(Model)
+		runToolCommands(t, m, cmd)
+
+		if sent == "" {
+			t.Fatal("expected tool results to be sent")
+		}
+		if strings.Contains(sent, "PREFIX") || strings.Contains(sent, "SUFFIX") {
+			t.Errorf("tool result payload should not be wrapped: %q", sent)
+		}
+	})
+}
+
+func TestModel_MaxContentWidth(t *testing.T) {
+	newModel := func(maxWidth int) Model {
+		m := Model{
+			textarea:        textarea.New(),
+			maxContentWidth: maxWidth,
+			messages:        []chatMessage{{role: "user", content: "hello"}},
+		}
+		updated, _ := m.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+		return updated.(Model)
+	}
+
+	t.Run("caps and centers on wide terminals", func(t *testing.T) {
+		m := newModel(100)
+		if got := m.contentWidth(); got != 100 {
+			t.Errorf("contentWidth() = %d, want 100", got)
+		}
+		if m.viewport.Width != 100 {
+			t.Errorf("viewport width = %d, want 100", m.viewport.Width)
+		}
+
+		// Panels are 100 columns plus borders, centered in 200 columns
+		lines := strings.Split(ansiPattern.ReplaceAllString(m.View(), ""), "\n")
+		for _, line := range lines {
+			trimmed := strings.TrimLeft(line, " ")
+			if trimmed == "" {
+				continue
+			}
+			if indent := len(line) - len(trimmed); indent < 40 {
+				t.Fatalf("line is not centered (indent %d): %q", indent, line)
+			}
+		}
+	})
+
+	t.Run("zero uses full width", func(t *testing.T) {
+		m := newModel(0)
+		if got := m.contentWidth(); got != 196 {
+			t.Errorf("contentWidth() = %d, want 196", got)
+		}
+		if m.viewport.Width != 196 {
+			t.Errorf("viewport width = %d, want 196", m.viewport.Width)
+		}
+	})
+
+	t.Run("narrow terminals are not affected", func(t *testing.T) {
+		m := newModel(300)
+		if got := m.contentWidth(); got != 196 {
+			t.Errorf("contentWidth() = %d, want 196", got)
+		}
+	})
+}
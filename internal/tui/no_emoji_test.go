@@ -0,0 +1,114 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// emojiRunes returns the emoji and pictographic symbols in s
+func emojiRunes(s string) []rune {
+	var found []rune
+	for _, r := range s {
+		if r >= 0x1F000 || (r >= 0x2600 && r <= 0x27BF) || r == '⬤' {
+			found = append(found, r)
+		}
+	}
+	return found
+}
+
+func enableNoEmoji(t *testing.T) {
+	t.Helper()
+	SetNoEmoji(true)
+	t.Cleanup(func() { SetNoEmoji(false) })
+}
+
+func newNoEmojiTestModel() Model {
+	m := newTestModel(withViewport(96, 30), withMessages(
+		chatMessage{role: "user", content: "draw a cat"},
+		chatMessage{role: "assistant", content: "Here it is", thoughts: "The user wants a cat",
+			images:  []models.WebImage{{URL: "https://example.com/cat", Title: "cat"}},
+			sources: []models.Source{{URL: "https://example.com", Title: "example"}},
+		},
+	), func(m *Model) {
+		m.height = 50
+		m.modelName = "fast"
+		m.activeGemName = "Coder"
+		m.attachments = []*api.UploadedFile{{FileName: "notes.txt"}}
+	})
+	m.updateViewport()
+	return m
+}
+
+func TestNoEmoji_ChatView(t *testing.T) {
+	enableNoEmoji(t)
+	m := newNoEmojiTestModel()
+
+	view := ansiPattern.ReplaceAllString(m.View(), "")
+	for _, label := range []string{"[attach]", "[images]", "[thinking]", "[sources]", "[gem] Coder"} {
+		if !strings.Contains(view, label) {
+			t.Errorf("view is missing %q:\n%s", label, view)
+		}
+	}
+	if found := emojiRunes(view); len(found) > 0 {
+		t.Errorf("view contains emoji %q:\n%s", string(found), view)
+	}
+}
+
+func TestNoEmoji_WelcomeAndErrors(t *testing.T) {
+	enableNoEmoji(t)
+	m := newNoEmojiTestModel()
+	m.messages = nil
+
+	if found := emojiRunes(m.View()); len(found) > 0 {
+		t.Errorf("welcome view contains emoji %q", string(found))
+	}
+
+	formatted := m.formatError(errors.New("boom"))
+	if !strings.Contains(formatted, "[!] Error: boom") || len(emojiRunes(formatted)) > 0 {
+		t.Errorf("unexpected error rendering: %q", formatted)
+	}
+}
+
+func TestNoEmoji_Selectors(t *testing.T) {
+	enableNoEmoji(t)
+
+	gems := newNoEmojiTestModel()
+	gems.selectingGem = true
+	gems.gemsFilter = "cod"
+	gems.gemsList = []*models.Gem{{ID: "g1", Name: "Coder"}}
+	view := gems.View()
+	if !strings.Contains(view, "[gem] Select a Gem") || !strings.Contains(view, "[search]") {
+		t.Errorf("gem selector should use ASCII labels:\n%s", view)
+	}
+	if found := emojiRunes(view); len(found) > 0 {
+		t.Errorf("gem selector contains emoji %q", string(found))
+	}
+
+	hist := newNoEmojiTestModel()
+	hist.selectingHistory = true
+	hist.historyFilter = "cat"
+	hist.historyList = []*history.Conversation{{ID: "c1", Title: "Cats"}}
+	view = hist.View()
+	if !strings.Contains(view, "[history] Select Conversation") {
+		t.Errorf("history selector should use ASCII labels:\n%s", view)
+	}
+	if found := emojiRunes(view); len(found) > 0 {
+		t.Errorf("history selector contains emoji %q", string(found))
+	}
+}
+
+func TestNoEmoji_DisabledKeepsEmoji(t *testing.T) {
+	m := newNoEmojiTestModel()
+
+	view := m.View()
+	for _, e := range []string{"📎", "🖼", "💭"} {
+		if !strings.Contains(view, e) {
+			t.Errorf("view is missing %q with emoji enabled", e)
+		}
+	}
+}
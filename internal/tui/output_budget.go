@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// outputBudgetWarnPercent is how much of the output budget is used before
+// the status bar warns about it
+const outputBudgetWarnPercent = 80
+
+// trackOutput adds a reply's characters to the session's output usage
+func (m *Model) trackOutput(msg chatMessage) {
+	m.outputUsed += utf8.RuneCountInString(msg.content) + utf8.RuneCountInString(msg.thoughts)
+}
+
+// outputBudgetPercent returns how much of the output budget is used, or -1
+// when no budget is set
+func (m Model) outputBudgetPercent() int {
+	if m.outputBudget <= 0 {
+		return -1
+	}
+	return m.outputUsed * 100 / m.outputBudget
+}
+
+// outputBudgetExceeded reports whether sending needs confirmation because
+// the output budget is used up
+func (m Model) outputBudgetExceeded() bool {
+	return m.outputBudget > 0 && m.outputUsed >= m.outputBudget && !m.outputBudgetOverride
+}
+
+// outputBudgetWarning returns the status bar warning shown when the output
+// budget is nearly or fully used, or "" otherwise
+func (m Model) outputBudgetWarning() string {
+	percent := m.outputBudgetPercent()
+	if percent < outputBudgetWarnPercent {
+		return ""
+	}
+	return fmt.Sprintf("%s Output budget %d%% (%s/%s chars)", icon("⚠", "[!]"), percent,
+		formatCharCount(m.outputUsed), formatCharCount(m.outputBudget))
+}
+
+// formatCharCount formats a character count compactly, e.g. 8.5k
+func formatCharCount(n int) string {
+	if n < 1000 {
+		return fmt.Sprintf("%d", n)
+	}
+	return fmt.Sprintf("%.1fk", float64(n)/1000)
+}
+
+// beginSend runs send, which starts a request to the model, unless the
+// output budget is used up. Every path that sends a prompt - a typed
+// message, /retry, /continue, /try or tool results - goes through it, so
+// none of them get past the budget unconfirmed.
+func (m *Model) beginSend(send func(*Model) tea.Cmd) tea.Cmd {
+	if m.holdForOutputBudget(send) {
+		return nil
+	}
+	return send(m)
+}
+
+// holdForOutputBudget reports whether a send must wait because the output
+// budget is used up, and then keeps send to run once the user confirms
+// going over it
+func (m *Model) holdForOutputBudget(send func(*Model) tea.Cmd) bool {
+	if !m.outputBudgetExceeded() {
+		return false
+	}
+	m.heldSend = send
+	m.loading = false
+	m.err = fmt.Errorf("output budget of %s chars used up (%s so far) - send anyway? (y/n)",
+		formatCharCount(m.outputBudget), formatCharCount(m.outputUsed))
+	return true
+}
+
+// resendMsg returns a held send that handles msg again, for sends held
+// before they change anything, such as a typed message
+func resendMsg(msg tea.Msg) func(*Model) tea.Cmd {
+	return func(m *Model) tea.Cmd {
+		updated, cmd := m.Update(msg)
+		*m = updated.(Model)
+		return cmd
+	}
+}
+
+// updateOutputBudget handles the y/n answer to going over the output
+// budget. Confirming runs the held send and stops asking for the rest of
+// the session; declining drops it, leaving a typed message in the input.
+func (m Model) updateOutputBudget(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m.handleInterrupt()
+
+	case "y", "Y":
+		send := m.heldSend
+		m.heldSend = nil
+		m.outputBudgetOverride = true
+		m.err = nil
+		cmd := send(&m)
+		return m, cmd
+
+	case "n", "N", "esc":
+		m.heldSend = nil
+		m.err = fmt.Errorf("not sent - output budget used up")
+	}
+
+	return m, nil
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// sendChat types a prompt, presses enter and delivers the reply, if any
+func sendChat(t *testing.T, m Model, prompt string) Model {
+	t.Helper()
+	m.textarea.SetValue(prompt)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.loading || cmd == nil {
+		return m
+	}
+	return deliverReply(t, m, cmd)
+}
+
+// deliverReply runs the send command in a batch and applies its reply
+func deliverReply(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("expected a batch command")
+	}
+	updated, _ := m.Update(batch[0]())
+	return updated.(Model)
+}
+
+func TestOutputBudget_WarnsThenAsksToContinue(t *testing.T) {
+	var sent []string
+	m := newTestModel(withSession(replyingSession(strings.Repeat("x", 450), &sent)), func(m *Model) {
+		m.width = 200
+		m.outputBudget = 1000
+	})
+
+	m = sendChat(t, m, "first")
+	if m.outputUsed != 450 {
+		t.Fatalf("outputUsed = %d, want 450", m.outputUsed)
+	}
+	if warning := m.outputBudgetWarning(); warning != "" {
+		t.Errorf("no warning expected at 45%%, got %q", warning)
+	}
+
+	m = sendChat(t, m, "second")
+	bar := ansiPattern.ReplaceAllString(m.renderStatusBar(200), "")
+	if !strings.Contains(bar, "Output budget 90% (900/1.0k chars)") {
+		t.Errorf("status bar should warn near the budget:\n%s", bar)
+	}
+
+	m = sendChat(t, m, "third")
+	if m.outputUsed != 1350 || len(sent) != 3 {
+		t.Fatalf("outputUsed = %d after %d sends", m.outputUsed, len(sent))
+	}
+
+	// Over budget: the next send waits for confirmation
+	m = sendChat(t, m, "fourth")
+	if len(sent) != 3 || m.heldSend == nil {
+		t.Fatalf("send should be held, sent = %d, confirming = %v", len(sent), m.heldSend != nil)
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "send anyway? (y/n)") {
+		t.Errorf("expected a confirmation prompt, got %v", m.err)
+	}
+	if m.textarea.Value() != "fourth" || len(m.messages) != 6 {
+		t.Error("held message should stay in the input and out of the chat")
+	}
+
+	// Declining keeps the message unsent
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(Model)
+	if m.heldSend != nil || len(sent) != 3 || m.textarea.Value() != "fourth" {
+		t.Error("declining should keep the message in the input")
+	}
+
+	// Confirming sends it and stops asking
+	m = sendChat(t, m, "fourth")
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = deliverReply(t, updated.(Model), cmd)
+	if len(sent) != 4 || m.heldSend != nil || m.messages[len(m.messages)-2].content != "fourth" {
+		t.Fatalf("confirmed message should be sent, sent = %d", len(sent))
+	}
+	m = sendChat(t, m, "fifth")
+	if len(sent) != 5 || m.heldSend != nil {
+		t.Error("later sends should not ask again this session")
+	}
+}
+
+func TestOutputBudget_Disabled(t *testing.T) {
+	var sent []string
+	m := newTestModel(withSession(replyingSession(strings.Repeat("x", 5000), &sent)), func(m *Model) {
+		m.width = 200
+		m.outputBudget = 0
+	})
+	for _, prompt := range []string{"one", "two", "three"} {
+		m = sendChat(t, m, prompt)
+	}
+
+	if len(sent) != 3 || m.heldSend != nil {
+		t.Errorf("sends should never be held without a budget, sent = %d", len(sent))
+	}
+	if m.outputBudgetWarning() != "" || strings.Contains(m.renderStatusBar(200), "Output budget") {
+		t.Error("no warning expected without a budget")
+	}
+}
+
+func TestOutputBudget_HoldsEverySend(t *testing.T) {
+	var sent []string
+	m := newTestModel(withSession(replyingSession(strings.Repeat("x", 150), &sent)), func(m *Model) {
+		m.width = 200
+		m.outputBudget = 100
+	})
+	m = sendChat(t, m, "first")
+
+	// /retry is held like a typed message
+	m = sendChat(t, m, "/retry")
+	if len(sent) != 1 || m.heldSend == nil || m.loading {
+		t.Fatalf("/retry should be held, sent = %d", len(sent))
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = deliverReply(t, updated.(Model), cmd)
+	if len(sent) != 2 || len(m.messages) != 2 {
+		t.Fatalf("confirmed /retry should replace the reply, sent = %d, messages = %d", len(sent), len(m.messages))
+	}
+
+	// So are tool results going back to the model
+	m.outputBudgetOverride = false
+	call := toolexec.ToolCall{Name: "calc"}
+	if cmd := m.handleToolResult(call, toolexec.NewResult("calc", toolexec.NewOutput().WithData([]byte("3")), nil)); cmd != nil {
+		t.Fatal("tool results should be held over the budget")
+	}
+	if m.heldSend == nil || m.loading || len(m.toolResults) != 0 {
+		t.Fatal("the held send should own the results")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = updated.(Model)
+	if len(sent) != 2 || m.heldSend != nil || m.err == nil {
+		t.Errorf("declined tool results should not be sent, sent = %d", len(sent))
+	}
+}
@@ -0,0 +1,330 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pagerModel shows the content of a single message full screen, with its
+// own scrolling and search
+type pagerModel struct {
+	title    string
+	content  string
+	lines    []string // content wrapped to the viewport, without styling
+	viewport viewport.Model
+
+	// Search state: typing is set while the query is being entered, and
+	// matches are the lines containing the last confirmed query
+	typing   bool
+	input    string
+	query    string
+	matches  []int
+	matchIdx int
+}
+
+// newPagerModel wraps content to the pager's width for the given screen
+// size
+func newPagerModel(title, content string, width, height int) pagerModel {
+	p := pagerModel{title: title, content: content, viewport: viewport.New(1, 1)}
+	p.resize(width, height)
+	return p
+}
+
+// pagerSize returns the viewport size for a screen of width x height,
+// leaving room for the title and the hint lines
+func pagerSize(width, height int) (int, int) {
+	return max(width-2, 20), max(height-4, 3)
+}
+
+// resize wraps the content for a new screen size
+func (p *pagerModel) resize(width, height int) {
+	p.viewport.Width, p.viewport.Height = pagerSize(width, height)
+	wrapped := lipgloss.NewStyle().Width(p.viewport.Width).Render(p.content)
+	p.lines = strings.Split(wrapped, "\n")
+	for i, line := range p.lines {
+		p.lines[i] = strings.TrimRight(line, " ")
+	}
+	p.findMatches()
+	p.refresh()
+}
+
+// findMatches collects the lines containing the query, ignoring case
+func (p *pagerModel) findMatches() {
+	p.matches = nil
+	p.matchIdx = 0
+	if p.query == "" {
+		return
+	}
+	query := strings.ToLower(p.query)
+	for i, line := range p.lines {
+		if strings.Contains(strings.ToLower(line), query) {
+			p.matches = append(p.matches, i)
+		}
+	}
+}
+
+// refresh renders the lines into the viewport, highlighting the query
+func (p *pagerModel) refresh() {
+	if p.query == "" {
+		p.viewport.SetContent(strings.Join(p.lines, "\n"))
+		return
+	}
+	lines := make([]string, len(p.lines))
+	for i, line := range p.lines {
+		lines[i] = highlightMatches(line, p.query)
+	}
+	p.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// highlightMatches styles each case-insensitive occurrence of query in line
+func highlightMatches(line, query string) string {
+	lower := strings.ToLower(line)
+	query = strings.ToLower(query)
+	// Lowercasing may change byte lengths; highlight nothing rather than
+	// slice the line at the wrong offsets
+	if len(lower) != len(line) {
+		return line
+	}
+
+	var sb strings.Builder
+	last := 0
+	for {
+		idx := strings.Index(lower[last:], query)
+		if idx < 0 {
+			break
+		}
+		start := last + idx
+		end := start + len(query)
+		sb.WriteString(line[last:start])
+		sb.WriteString(pagerMatchStyle.Render(line[start:end]))
+		last = end
+	}
+	sb.WriteString(line[last:])
+	return sb.String()
+}
+
+// jumpToMatch scrolls to the next (direction > 0) or previous match,
+// wrapping around. The first search jumps to the first match at or below
+// the top of the view.
+func (p *pagerModel) jumpToMatch(direction int) {
+	if len(p.matches) == 0 {
+		return
+	}
+	p.matchIdx = (p.matchIdx + direction + len(p.matches)) % len(p.matches)
+	p.viewport.SetYOffset(p.matches[p.matchIdx])
+}
+
+// search confirms the typed query and jumps to its first match from the
+// current position
+func (p *pagerModel) search() {
+	p.query = p.input
+	p.findMatches()
+	p.refresh()
+	if len(p.matches) == 0 {
+		return
+	}
+	p.matchIdx = 0
+	for i, line := range p.matches {
+		if line >= p.viewport.YOffset {
+			p.matchIdx = i
+			break
+		}
+	}
+	p.viewport.SetYOffset(p.matches[p.matchIdx])
+}
+
+// Update handles a key, reporting whether the pager should close
+func (p pagerModel) Update(msg tea.KeyMsg) (pagerModel, bool) {
+	if p.typing {
+		switch msg.Type {
+		case tea.KeyEnter:
+			p.typing = false
+			p.search()
+		case tea.KeyEsc:
+			p.typing = false
+			p.input = p.query
+		case tea.KeyBackspace:
+			if runes := []rune(p.input); len(runes) > 0 {
+				p.input = string(runes[:len(runes)-1])
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			p.input += string(msg.Runes)
+		}
+		return p, false
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return p, true
+	case "/":
+		p.typing = true
+		p.input = ""
+	case "n":
+		p.jumpToMatch(1)
+	case "N":
+		p.jumpToMatch(-1)
+	case "g", "home":
+		p.viewport.GotoTop()
+	case "G", "end":
+		p.viewport.GotoBottom()
+	default:
+		p.viewport, _ = p.viewport.Update(msg)
+	}
+	return p, false
+}
+
+// status describes the search state for the hint line
+func (p pagerModel) status() string {
+	switch {
+	case p.typing:
+		return "/" + p.input
+	case p.query == "":
+		return ""
+	case len(p.matches) == 0:
+		return fmt.Sprintf("%q: no matches", p.query)
+	default:
+		return fmt.Sprintf("%q: match %d/%d", p.query, p.matchIdx+1, len(p.matches))
+	}
+}
+
+// View renders the pager full screen
+func (p pagerModel) View() string {
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(p.title))
+	sb.WriteString("\n\n")
+	sb.WriteString(p.viewport.View())
+	sb.WriteString("\n")
+
+	hint := fmt.Sprintf("↑/↓/pgup/pgdn: scroll • g/G: top/bottom • /: search • n/N: next/prev • esc/q: close • %3.f%%", p.viewport.ScrollPercent()*100)
+	if status := p.status(); status != "" {
+		hint = status + " • " + hint
+	}
+	sb.WriteString(hintStyle.Render(hint))
+	return sb.String()
+}
+
+// handleOpenPager starts selecting a message to open in the pager, with
+// the most recent message selected
+func (m Model) handleOpenPager() (tea.Model, tea.Cmd) {
+	if len(m.messages) == 0 {
+		m.err = fmt.Errorf("no messages to open")
+		return m, nil
+	}
+	m.selectingMessage = true
+	m.messageCursor = len(m.messages) - 1
+	m.err = nil
+	m.updateViewport()
+	m.scrollToSelectedMessage()
+	return m, nil
+}
+
+// scrollToSelectedMessage scrolls the viewport to the selected message
+func (m *Model) scrollToSelectedMessage() {
+	if m.messageCursor >= 0 && m.messageCursor < len(m.messageOffsets) {
+		m.viewport.SetYOffset(m.messageOffsets[m.messageCursor])
+	}
+}
+
+// updateMessageSelection handles input while a message is being selected
+// for the pager. Other messages (responses, tool results) go through the
+// regular Update so they are not lost.
+func (m Model) updateMessageSelection(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		m.selectingMessage = false
+		updated, cmd := m.Update(msg)
+		if um, ok := updated.(Model); ok {
+			um.selectingMessage = len(um.messages) > 0
+			um.messageCursor = max(0, min(um.messageCursor, len(um.messages)-1))
+			// Redraw the selection marker unless only an animation ticked
+			switch msg.(type) {
+			case spinner.TickMsg, animationTickMsg:
+			default:
+				um.updateViewport()
+			}
+			return um, cmd
+		}
+		return updated, cmd
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+c":
+		return m.handleInterrupt()
+	case "esc", "q", "ctrl+o":
+		m.selectingMessage = false
+		m.updateViewport()
+		return m, nil
+	case "up", "k":
+		if m.messageCursor > 0 {
+			m.messageCursor--
+		}
+	case "down", "j":
+		if m.messageCursor < len(m.messages)-1 {
+			m.messageCursor++
+		}
+	case "enter":
+		return m.openPager(m.messageCursor), nil
+	default:
+		return m, nil
+	}
+	m.updateViewport()
+	m.scrollToSelectedMessage()
+	return m, nil
+}
+
+// openPager shows message i in the pager
+func (m Model) openPager(i int) Model {
+	m.selectingMessage = false
+	m.updateViewport()
+	if i < 0 || i >= len(m.messages) {
+		return m
+	}
+
+	msg := m.messages[i]
+	title := fmt.Sprintf("Message %d of %d · %s", i+1, len(m.messages), msg.role)
+	if msg.role == "assistant" && msg.model != "" {
+		title += " (" + msg.model + ")"
+	}
+	m.pager = newPagerModel(title, msg.content, m.width, m.height)
+	m.showingPager = true
+	return m
+}
+
+// updatePager handles input while the pager is shown. Other messages go
+// through the regular Update so operations finishing meanwhile are kept.
+func (m Model) updatePager(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		offset := m.pager.viewport.YOffset
+		m.pager.resize(m.width, m.height)
+		m.pager.viewport.SetYOffset(offset)
+		return m, nil
+
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m.handleInterrupt()
+		}
+		var closed bool
+		m.pager, closed = m.pager.Update(msg)
+		if closed {
+			m.showingPager = false
+			m.pager = pagerModel{}
+		}
+		return m, nil
+	}
+
+	m.showingPager = false
+	updated, cmd := m.Update(msg)
+	if um, ok := updated.(Model); ok {
+		um.showingPager = true
+		return um, cmd
+	}
+	return updated, cmd
+}
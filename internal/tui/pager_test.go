@@ -0,0 +1,269 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// longPagerContent returns n numbered lines, with "needle" on every 25th
+func longPagerContent(n int) string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+		if i%25 == 0 {
+			lines[i] += " needle"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func pagerKeys(p pagerModel, keys ...tea.KeyMsg) (pagerModel, bool) {
+	var closed bool
+	for _, key := range keys {
+		p, closed = p.Update(key)
+	}
+	return p, closed
+}
+
+func runeKeys(s string) []tea.KeyMsg {
+	var keys []tea.KeyMsg
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+func TestPager_Scrolling(t *testing.T) {
+	p := newPagerModel("Message", longPagerContent(200), 80, 24)
+
+	if p.viewport.YOffset != 0 {
+		t.Fatalf("pager should open at the top, offset = %d", p.viewport.YOffset)
+	}
+	if !strings.Contains(p.View(), "line 0 needle") {
+		t.Error("first line should be visible")
+	}
+
+	p, _ = pagerKeys(p, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if p.viewport.YOffset != 2 {
+		t.Errorf("down twice: offset = %d, want 2", p.viewport.YOffset)
+	}
+
+	p, _ = pagerKeys(p, runeKeys("G")...)
+	if !p.viewport.AtBottom() || !strings.Contains(p.View(), "line 199") {
+		t.Errorf("G should scroll to the bottom, offset = %d", p.viewport.YOffset)
+	}
+
+	p, _ = pagerKeys(p, tea.KeyMsg{Type: tea.KeyPgUp})
+	if p.viewport.AtBottom() {
+		t.Error("pgup should scroll up")
+	}
+
+	p, _ = pagerKeys(p, runeKeys("g")...)
+	if p.viewport.YOffset != 0 {
+		t.Errorf("g should scroll to the top, offset = %d", p.viewport.YOffset)
+	}
+
+	if _, closed := pagerKeys(p, runeKeys("q")...); !closed {
+		t.Error("q should close the pager")
+	}
+}
+
+func TestPager_Search(t *testing.T) {
+	p := newPagerModel("Message", longPagerContent(200), 80, 24)
+
+	keys := append(runeKeys("/NEEDLE"), tea.KeyMsg{Type: tea.KeyEnter})
+	p, closed := pagerKeys(p, keys...)
+	if closed {
+		t.Fatal("searching should not close the pager")
+	}
+	if len(p.matches) != 8 {
+		t.Fatalf("matches = %v, want 8 case-insensitive matches", p.matches)
+	}
+	if p.viewport.YOffset != 0 || !strings.Contains(p.status(), "match 1/8") {
+		t.Errorf("first match: offset = %d, status = %q", p.viewport.YOffset, p.status())
+	}
+
+	p, _ = pagerKeys(p, runeKeys("nn")...)
+	if p.viewport.YOffset != 50 || !strings.Contains(p.status(), "match 3/8") {
+		t.Errorf("n twice: offset = %d, status = %q, want line 50", p.viewport.YOffset, p.status())
+	}
+
+	p, _ = pagerKeys(p, runeKeys("N")...)
+	if p.viewport.YOffset != 25 {
+		t.Errorf("N: offset = %d, want line 25", p.viewport.YOffset)
+	}
+
+	// Going back from the first match wraps to the last
+	p, _ = pagerKeys(p, runeKeys("NN")...)
+	if want := p.matches[7]; p.matchIdx != 7 || p.viewport.YOffset != min(want, p.viewport.TotalLineCount()-p.viewport.Height) {
+		t.Errorf("wrap around: match %d, offset = %d", p.matchIdx, p.viewport.YOffset)
+	}
+
+	view := ansiPattern.ReplaceAllString(p.View(), "")
+	if !strings.Contains(view, `"NEEDLE": match 8/8`) {
+		t.Errorf("view should show the search status:\n%s", view)
+	}
+}
+
+func TestPager_SearchFromCurrentPosition(t *testing.T) {
+	p := newPagerModel("Message", longPagerContent(200), 80, 24)
+	p.viewport.SetYOffset(60)
+
+	p, _ = pagerKeys(p, append(runeKeys("/needle"), tea.KeyMsg{Type: tea.KeyEnter})...)
+	if p.viewport.YOffset != 75 {
+		t.Errorf("search should start at the view, offset = %d, want 75", p.viewport.YOffset)
+	}
+}
+
+func TestPager_SearchNoMatchesAndCancel(t *testing.T) {
+	p := newPagerModel("Message", longPagerContent(50), 80, 24)
+
+	p, _ = pagerKeys(p, append(runeKeys("/missing"), tea.KeyMsg{Type: tea.KeyEnter})...)
+	if len(p.matches) != 0 || !strings.Contains(p.status(), "no matches") {
+		t.Errorf("status = %q, want no matches", p.status())
+	}
+
+	// Esc while typing cancels the search without closing the pager
+	p, closed := pagerKeys(p, append(runeKeys("/li"), tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyEsc})...)
+	if closed || p.typing || p.query != "missing" {
+		t.Errorf("esc while typing: closed = %v, typing = %v, query = %q", closed, p.typing, p.query)
+	}
+}
+
+func TestPager_ResizeRewraps(t *testing.T) {
+	content := strings.Repeat("word ", 100)
+	p := newPagerModel("Message", content, 80, 24)
+	narrow := len(p.lines)
+
+	p.resize(200, 24)
+	if len(p.lines) >= narrow {
+		t.Errorf("wider pager should wrap to fewer lines: %d, was %d", len(p.lines), narrow)
+	}
+}
+
+func newPagerTestModel() Model {
+	m := newTestModel(withViewport(96, 20), withMessages(
+		chatMessage{role: "user", content: "first question"},
+		chatMessage{role: "assistant", content: longPagerContent(300)},
+		chatMessage{role: "user", content: "second question"},
+		chatMessage{role: "assistant", content: "short answer"},
+	))
+	m.updateViewport()
+	return m
+}
+
+func TestPager_OpenFromChat(t *testing.T) {
+	m := newPagerTestModel()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = updated.(Model)
+	if !m.selectingMessage || m.messageCursor != 3 {
+		t.Fatalf("ctrl+o should select the last message, cursor = %d", m.messageCursor)
+	}
+	if view := m.View(); !strings.Contains(view, "Message 4/4") || !strings.Contains(view, "▸ selected") {
+		t.Errorf("view should show the selection:\n%s", view)
+	}
+
+	for range 2 {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+		m = updated.(Model)
+	}
+	if m.messageCursor != 1 {
+		t.Fatalf("cursor = %d, want 1", m.messageCursor)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if !m.showingPager || m.selectingMessage {
+		t.Fatalf("enter should open the pager (showing = %v, selecting = %v)", m.showingPager, m.selectingMessage)
+	}
+	view := m.View()
+	if !strings.Contains(view, "Message 2 of 4 · assistant") || !strings.Contains(view, "line 0 needle") {
+		t.Errorf("pager should show the selected message:\n%s", view)
+	}
+	if strings.Contains(view, "first question") {
+		t.Error("pager should show only the selected message")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.pager.viewport.YOffset != 1 {
+		t.Errorf("keys should scroll the pager, offset = %d", m.pager.viewport.YOffset)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.showingPager || m.selectingMessage {
+		t.Error("esc should return to the chat")
+	}
+	if strings.Contains(m.viewport.View(), "▸ selected") {
+		t.Error("the selection marker should be gone after closing")
+	}
+}
+
+func TestUpdateViewport_MessageOffsets(t *testing.T) {
+	m := newPagerTestModel()
+	labels := []string{"You", "Gemini", "You", "Gemini"}
+	if len(m.messageOffsets) != len(labels) || len(m.userMessageOffsets) != 2 {
+		t.Fatalf("offsets = %v, user offsets = %v", m.messageOffsets, m.userMessageOffsets)
+	}
+	// A one-line viewport can scroll to every line
+	m.viewport.Height = 1
+	for i, offset := range m.messageOffsets {
+		m.viewport.SetYOffset(offset)
+		first, _, _ := strings.Cut(ansiPattern.ReplaceAllString(m.viewport.View(), ""), "\n")
+		if !strings.Contains(first, labels[i]) {
+			t.Errorf("message %d should start at line %d, found %q", i, offset, first)
+		}
+	}
+	if m.userMessageOffsets[1] != m.messageOffsets[2] {
+		t.Errorf("user offsets %v should match message offsets %v", m.userMessageOffsets, m.messageOffsets)
+	}
+}
+
+func TestPager_CancelSelection(t *testing.T) {
+	m := newPagerTestModel()
+	m.textarea.SetValue("draft")
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+
+	if m.selectingMessage || m.showingPager {
+		t.Error("esc should cancel the selection")
+	}
+	if m.textarea.Value() != "draft" {
+		t.Errorf("selection should keep the draft, got %q", m.textarea.Value())
+	}
+}
+
+func TestPager_NoMessages(t *testing.T) {
+	m := newPagerTestModel()
+	m.messages = nil
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	m = updated.(Model)
+	if m.selectingMessage || m.err == nil {
+		t.Error("ctrl+o without messages should report an error")
+	}
+}
+
+func TestPager_KeepsResponsesWhileOpen(t *testing.T) {
+	m := newPagerTestModel()
+	m = m.openPager(1)
+	m.loading = true
+
+	updated, _ := m.Update(responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: "late reply"}}}})
+	m = updated.(Model)
+	if !m.showingPager {
+		t.Error("pager should stay open when a response arrives")
+	}
+	if last := m.messages[len(m.messages)-1]; last.content != "late reply" {
+		t.Errorf("response should be added behind the pager, last message = %q", last.content)
+	}
+}
@@ -836,13 +836,13 @@ func (m PersonaManagerModel) renderHeader(width int) string {
 	var title string
 	switch m.view {
 	case personaViewCreate:
-		title = configTitleStyle.Render("✦ Create New Persona")
+		title = configTitleStyle.Render(icon("✦", "*") + " Create New Persona")
 	case personaViewEdit:
-		title = configTitleStyle.Render("✦ Edit Persona")
+		title = configTitleStyle.Render(icon("✦", "*") + " Edit Persona")
 	case personaViewDelete:
-		title = configTitleStyle.Render("✦ Delete Persona")
+		title = configTitleStyle.Render(icon("✦", "*") + " Delete Persona")
 	default:
-		title = configTitleStyle.Render("✦ Persona Manager")
+		title = configTitleStyle.Render(icon("✦", "*") + " Persona Manager")
 	}
 
 	subtitle := ""
@@ -856,12 +856,12 @@ func (m PersonaManagerModel) renderHeader(width int) string {
 
 // renderListView renders the personas list view
 func (m PersonaManagerModel) renderListView(width int) string {
-	title := configSectionTitleStyle.Render("📦 Personas")
+	title := configSectionTitleStyle.Render(icon("📦", "[list]") + " Personas")
 
 	// Add search bar if searching or has search value
 	var contentSections []string
 	if m.searching || m.searchInput.Value() != "" {
-		searchLabel := inputLabelStyle.Render("🔍 ")
+		searchLabel := inputLabelStyle.Render(icon("🔍", "[search]") + " ")
 		searchContent := lipgloss.JoinHorizontal(lipgloss.Center, searchLabel, m.searchInput.View())
 		contentSections = append(contentSections, inputPanelStyle.Width(width).Render(searchContent))
 	}
@@ -944,7 +944,7 @@ func (m PersonaManagerModel) renderDetailsView(width int) string {
 	}
 
 	persona := m.selectedPersona
-	title := configSectionTitleStyle.Render("📋 Persona Details")
+	title := configSectionTitleStyle.Render(icon("📋", "[details]") + " Persona Details")
 
 	// Default indicator
 	defaultIndicator := ""
@@ -966,7 +966,7 @@ func (m PersonaManagerModel) renderDetailsView(width int) string {
 	// Prompt (rendered as markdown if possible)
 	if persona.SystemPrompt != "" {
 		details = append(details, "")
-		details = append(details, configSectionTitleStyle.Render("📝 System Prompt"))
+		details = append(details, configSectionTitleStyle.Render(icon("📝", "[prompt]")+" System Prompt"))
 
 		promptWidth := width - 8
 		if promptWidth < 40 {
@@ -989,7 +989,7 @@ func (m PersonaManagerModel) renderDetailsView(width int) string {
 
 // renderCreateView renders the create persona form
 func (m PersonaManagerModel) renderCreateView(width int) string {
-	return m.renderFormView(width, "✨ New Persona", "Fill in the details for your new persona:")
+	return m.renderFormView(width, icon("✨", "[new]")+" New Persona", "Fill in the details for your new persona:")
 }
 
 // renderEditView renders the edit persona form
@@ -998,7 +998,7 @@ func (m PersonaManagerModel) renderEditView(width int) string {
 	if m.selectedPersona != nil {
 		personaName = m.selectedPersona.Name
 	}
-	return m.renderFormView(width, "✏️  Edit Persona", fmt.Sprintf("Editing persona: %s", personaName))
+	return m.renderFormView(width, icon("✏️", "[edit]")+"  Edit Persona", fmt.Sprintf("Editing persona: %s", personaName))
 }
 
 // renderFormView renders the create/edit form
@@ -1062,7 +1062,7 @@ func (m PersonaManagerModel) renderDeleteView(width int) string {
 		return configPanelStyle.Width(width).Render("No persona selected")
 	}
 
-	title := configSectionTitleStyle.Render("⚠️  Confirm Deletion")
+	title := configSectionTitleStyle.Render(icon("⚠️", "[!]") + "  Confirm Deletion")
 	warning := errorStyle.Render("This action cannot be undone!")
 
 	var lines []string
@@ -1078,7 +1078,7 @@ func (m PersonaManagerModel) renderDeleteView(width int) string {
 
 // renderHelpView renders the help overlay
 func (m PersonaManagerModel) renderHelpView(width int) string {
-	title := configSectionTitleStyle.Render("❓ Keyboard Shortcuts")
+	title := configSectionTitleStyle.Render(icon("❓", "[help]") + " Keyboard Shortcuts")
 
 	var sections []string
 	sections = append(sections, title, "")
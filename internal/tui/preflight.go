@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// preflightTimeout bounds the startup connectivity check
+const preflightTimeout = 15 * time.Second
+
+// preflightMsg carries the result of the startup connectivity check
+type preflightMsg struct {
+	err error
+}
+
+// preflight creates a command that pings the client at startup, so an auth
+// or network problem is reported before the first prompt is sent
+func (m Model) preflight() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		defer cancel()
+		return preflightMsg{err: client.Ping(ctx)}
+	}
+}
+
+// handlePreflight shows a failed startup check; formatError adds the hint
+// for the error's category (auth, network, timeout)
+func (m *Model) handlePreflight(msg preflightMsg) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("connection check failed: %w", msg.err)
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	apierrors "github.com/diogo/geminiweb/internal/errors"
+)
+
+func TestPreflight_ReportsClassifiedErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pingErr error
+		hint    string
+	}{
+		{"auth", apierrors.NewAuthError("session rejected"), "auto-login"},
+		{"network", apierrors.NewNetworkError("ping", errors.New("connection refused")), "internet connection"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &api.MockGeminiClient{PingErr: tt.pingErr}
+			m := Model{client: client, textarea: textarea.New(), ready: true}
+
+			msg := m.preflight()()
+			if !client.PingCalled {
+				t.Fatal("preflight should ping the client")
+			}
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+			if m.err == nil || !strings.HasPrefix(m.err.Error(), "connection check failed:") {
+				t.Fatalf("expected a connection error, got %v", m.err)
+			}
+			if !errors.Is(m.err, tt.pingErr) {
+				t.Error("the ping error should be wrapped for classification")
+			}
+			if formatted := m.formatError(m.err); !strings.Contains(formatted, tt.hint) {
+				t.Errorf("formatted error should hint %q:\n%s", tt.hint, formatted)
+			}
+		})
+	}
+}
+
+func TestPreflight_SuccessKeepsQuiet(t *testing.T) {
+	m := Model{client: &api.MockGeminiClient{}, textarea: textarea.New(), ready: true}
+	updated, _ := m.Update(m.preflight()())
+	if err := updated.(Model).err; err != nil {
+		t.Errorf("a successful check should not show an error, got %v", err)
+	}
+}
+
+func TestPreflight_PassesDeadline(t *testing.T) {
+	var hasDeadline bool
+	client := &pingRecordingClient{MockGeminiClient: &api.MockGeminiClient{}, onPing: func(ctx context.Context) {
+		_, hasDeadline = ctx.Deadline()
+	}}
+	m := Model{client: client}
+	m.preflight()()
+	if !hasDeadline {
+		t.Error("preflight should bound the ping with a timeout")
+	}
+}
+
+// pingRecordingClient inspects the context passed to Ping
+type pingRecordingClient struct {
+	*api.MockGeminiClient
+	onPing func(ctx context.Context)
+}
+
+func (c *pingRecordingClient) Ping(ctx context.Context) error {
+	c.onPing(ctx)
+	return nil
+}
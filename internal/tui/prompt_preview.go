@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// handlePreviewCommand handles the /preview <message> command, which shows
+// the prompt that sending message would produce - with the persona system
+// prompt, prompt prefix and suffix and attachment note applied - in the
+// raw response overlay, without sending it.
+func (m Model) handlePreviewCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) == "" {
+		m.err = fmt.Errorf("usage: /preview <message>")
+		return m, nil
+	}
+
+	prompt := m.assemblePrompt(args, m.attachments)
+
+	var notes []string
+	if ext, found := models.DetectExtension(args); found {
+		notes = append(notes, "extension "+string(ext))
+	}
+	if len(m.attachments) > 0 {
+		notes = append(notes, fmt.Sprintf("%d attachment(s) uploaded separately", len(m.attachments)))
+	}
+
+	width, height := m.overlayViewportSize()
+	m.rawViewport = viewport.New(width, height)
+	m.rawViewport.SetContent(hardWrap(prompt, width))
+	m.rawTitle = fmt.Sprintf("Prompt preview (%d chars)", utf8.RuneCountInString(prompt))
+	if len(notes) > 0 {
+		m.rawTitle += " · " + strings.Join(notes, " · ")
+	}
+	m.showingRaw = true
+	m.err = nil
+	return m, nil
+}
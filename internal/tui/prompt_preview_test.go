@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+func newPreviewTestModel(session ChatSessionInterface) Model {
+	return newTestModel(withSession(session), func(m *Model) {
+		m.width = 120
+		m.persona = &config.Persona{Name: "reviewer", SystemPrompt: "Review code carefully."}
+		m.promptPrefix = "Answer in English."
+		m.promptSuffix = "Be brief."
+	})
+}
+
+func TestPreviewCommand_ShowsAssembledPrompt(t *testing.T) {
+	m := newPreviewTestModel(nil)
+
+	updated, cmd := m.handlePreviewCommand("@Gmail summarize my inbox")
+	m = updated.(Model)
+	if cmd != nil {
+		t.Error("/preview should not send anything")
+	}
+	if !m.showingRaw {
+		t.Fatal("/preview should open the overlay")
+	}
+
+	view := m.rawViewport.View()
+	for _, want := range []string{
+		"Answer in English.",
+		"[System Instructions]",
+		"Review code carefully.",
+		"[User Message]",
+		"@Gmail summarize my inbox",
+		"Be brief.",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Index(view, "Answer in English.") > strings.Index(view, "[System Instructions]") ||
+		strings.Index(view, "Be brief.") < strings.Index(view, "@Gmail") {
+		t.Errorf("prefix and suffix should wrap the persona prompt:\n%s", view)
+	}
+	if !strings.Contains(m.rawTitle, "Prompt preview") || !strings.Contains(m.rawTitle, "extension @Gmail") {
+		t.Errorf("title = %q, want the preview title with the detected extension", m.rawTitle)
+	}
+	if len(m.messages) != 0 {
+		t.Error("/preview should not add messages")
+	}
+}
+
+func TestPreviewCommand_MatchesSentPrompt(t *testing.T) {
+	var sent string
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			sent = prompt
+			return &models.ModelOutput{}, nil
+		},
+	}
+	m := newPreviewTestModel(session)
+	m.jobs = newJobRegistry()
+
+	const input = "What changed?"
+	m.sendMessageWithAttachments(input)()
+	if want := m.assemblePrompt(input, nil); sent != want {
+		t.Errorf("sent prompt = %q, preview = %q", sent, want)
+	}
+}
+
+func TestPreviewCommand_Usage(t *testing.T) {
+	m := newPreviewTestModel(nil)
+
+	updated, _ := m.handlePreviewCommand("  ")
+	m = updated.(Model)
+	if m.showingRaw || m.err == nil || !strings.Contains(m.err.Error(), "usage: /preview") {
+		t.Errorf("err = %v, showingRaw = %v", m.err, m.showingRaw)
+	}
+}
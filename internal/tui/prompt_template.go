@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Seams for tests
+var (
+	promptTemplateNow    = time.Now
+	promptTemplateGetwd  = os.Getwd
+	promptTemplateLookup = os.LookupEnv
+)
+
+// promptVarPattern matches {{name}} placeholders, allowing surrounding spaces
+var promptVarPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?)\s*\}\}`)
+
+// expandPromptTemplate expands template variables in an initial prompt file,
+// so sessions can be scripted with parameters. Supported variables:
+//
+//	{{date}}      current date (2006-01-02)
+//	{{time}}      current time (15:04)
+//	{{datetime}}  current date and time (RFC 3339)
+//	{{cwd}}       current working directory
+//	{{env.NAME}}  value of environment variable NAME
+//
+// Unknown variables and unset environment variables are left intact, so
+// prompts that contain literal braces (e.g. Go templates) are sent as-is.
+func expandPromptTemplate(prompt string) string {
+	if !strings.Contains(prompt, "{{") {
+		return prompt
+	}
+
+	now := promptTemplateNow()
+	return promptVarPattern.ReplaceAllStringFunc(prompt, func(match string) string {
+		name := promptVarPattern.FindStringSubmatch(match)[1]
+
+		switch name {
+		case "date":
+			return now.Format("2006-01-02")
+		case "time":
+			return now.Format("15:04")
+		case "datetime":
+			return now.Format(time.RFC3339)
+		case "cwd":
+			if cwd, err := promptTemplateGetwd(); err == nil {
+				return cwd
+			}
+			return match
+		}
+
+		if envName, ok := strings.CutPrefix(name, "env."); ok {
+			if value, found := promptTemplateLookup(envName); found {
+				return value
+			}
+		}
+		return match
+	})
+}
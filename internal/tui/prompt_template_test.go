@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func stubPromptTemplate(t *testing.T, env map[string]string) {
+	t.Helper()
+	origNow, origGetwd, origLookup := promptTemplateNow, promptTemplateGetwd, promptTemplateLookup
+	t.Cleanup(func() {
+		promptTemplateNow, promptTemplateGetwd, promptTemplateLookup = origNow, origGetwd, origLookup
+	})
+
+	promptTemplateNow = func() time.Time {
+		return time.Date(2026, 3, 14, 9, 26, 53, 0, time.UTC)
+	}
+	promptTemplateGetwd = func() (string, error) { return "/work/project", nil }
+	promptTemplateLookup = func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestExpandPromptTemplate(t *testing.T) {
+	stubPromptTemplate(t, map[string]string{"TICKET": "ABC-123", "EMPTY": ""})
+
+	tests := []struct {
+		name   string
+		prompt string
+		want   string
+	}{
+		{"plain prompt unchanged", "Summarize this repo", "Summarize this repo"},
+		{"date", "Report for {{date}}", "Report for 2026-03-14"},
+		{"time and datetime", "{{time}} / {{datetime}}", "09:26 / 2026-03-14T09:26:53Z"},
+		{"cwd", "Files in {{cwd}}", "Files in /work/project"},
+		{"spaces inside braces", "{{ date }}", "2026-03-14"},
+		{"env var", "Fix {{env.TICKET}}", "Fix ABC-123"},
+		{"empty env var", "[{{env.EMPTY}}]", "[]"},
+		{"unset env var left intact", "Fix {{env.MISSING}}", "Fix {{env.MISSING}}"},
+		{"unknown variable left intact", "Hello {{name}}", "Hello {{name}}"},
+		{"go template left intact", "{{ .Name }} and {{range .Items}}", "{{ .Name }} and {{range .Items}}"},
+		{"multiple variables", "{{date}} in {{cwd}}", "2026-03-14 in /work/project"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandPromptTemplate(tt.prompt); got != tt.want {
+				t.Errorf("expandPromptTemplate(%q) = %q, want %q", tt.prompt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandPromptTemplate_CwdError(t *testing.T) {
+	stubPromptTemplate(t, nil)
+	promptTemplateGetwd = func() (string, error) { return "", errors.New("no cwd") }
+
+	if got := expandPromptTemplate("in {{cwd}}"); got != "in {{cwd}}" {
+		t.Errorf("expandPromptTemplate() = %q, want placeholder left intact", got)
+	}
+}
+
+func TestSendInitialPrompt_ExpandsTemplate(t *testing.T) {
+	stubPromptTemplate(t, map[string]string{"TICKET": "ABC-123"})
+
+	m := &Model{initialPrompt: "Triage {{env.TICKET}} on {{date}}"}
+	msg, ok := m.sendInitialPrompt()().(initialPromptMsg)
+	if !ok {
+		t.Fatal("expected initialPromptMsg")
+	}
+	if msg.prompt != "Triage ABC-123 on 2026-03-14" {
+		t.Errorf("prompt = %q, want expanded prompt", msg.prompt)
+	}
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// savedBeforeQuitMsg signals that pending state was saved and the program
+// can exit
+type savedBeforeQuitMsg struct{}
+
+// handleInterrupt handles Ctrl+C. The first press saves pending history
+// and closes the client before quitting; a second press while saving
+// quits immediately.
+func (m Model) handleInterrupt() (tea.Model, tea.Cmd) {
+	if m.quitting {
+		return m, tea.Quit
+	}
+
+	m.quitting = true
+	m.err = fmt.Errorf("saving... press Ctrl+C again to force quit")
+	return m, m.saveBeforeQuit()
+}
+
+// saveBeforeQuit returns a command that flushes state not yet in the
+// history store - the output of a tool still streaming and the session
+// metadata - then closes the client, stopping its background cookie
+// rotation.
+func (m Model) saveBeforeQuit() tea.Cmd {
+	saved := m
+	partial := ""
+	if s := saved.activeToolStream(); s != nil {
+		partial = saved.messages[s.index].content
+	}
+
+	return func() tea.Msg {
+		if partial != "" {
+			saved.saveMessageToHistory("tool", partial+"\n(interrupted)", "")
+		}
+		saved.saveMetadataToHistory()
+		if saved.client != nil {
+			saved.client.Close()
+		}
+		return savedBeforeQuitMsg{}
+	}
+}
+
+// runChatProgram runs the chat TUI until it quits, then closes the tool
+// executor so middleware releases its resources.
+func runChatProgram(m Model) error {
+	p := tea.NewProgram(
+		m,
+		tea.WithAltScreen(),
+	)
+
+	final, err := p.Run()
+	if fm, ok := final.(Model); ok {
+		m = fm
+	}
+	if closeErr := m.closeTooling(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// closeTooling closes the tool executor, if any. It is safe to call more
+// than once.
+func (m Model) closeTooling() error {
+	if m.toolExecutor == nil {
+		return nil
+	}
+	if err := m.toolExecutor.Close(); err != nil {
+		return fmt.Errorf("failed to close tool executor: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+func newQuitTestModel() (Model, *mockHistoryStoreForModel, *api.MockGeminiClient) {
+	store := &mockHistoryStoreForModel{}
+	client := &api.MockGeminiClient{}
+	m := newTestModel(
+		withSession(&mockChatSessionWithMetadata{cid: "c", rid: "r", rcid: "rc"}),
+		withHistory(store, &history.Conversation{ID: "conv-1"}),
+		func(m *Model) { m.client = client },
+	)
+	return m, store, client
+}
+
+func TestCtrlC_SavesBeforeQuitting(t *testing.T) {
+	m, store, client := newQuitTestModel()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(Model)
+	if !m.quitting {
+		t.Fatal("first Ctrl+C should start saving")
+	}
+	if m.err == nil || !strings.Contains(m.err.Error(), "saving...") {
+		t.Errorf("expected a saving notice, got %v", m.err)
+	}
+	if cmd == nil {
+		t.Fatal("expected the save command")
+	}
+
+	msg := cmd()
+	if _, ok := msg.(savedBeforeQuitMsg); !ok {
+		t.Fatalf("save command returned %T, want savedBeforeQuitMsg", msg)
+	}
+	if len(store.updateMetadataCalls) != 1 || store.updateMetadataCalls[0].cid != "c" {
+		t.Errorf("metadata should be flushed, got %v", store.updateMetadataCalls)
+	}
+	if !client.CloseCalled {
+		t.Error("client should be closed before quitting")
+	}
+
+	_, cmd = m.Update(msg)
+	if cmd == nil {
+		t.Fatal("expected quit after saving")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("saving should end with tea.Quit")
+	}
+}
+
+func TestCtrlC_SecondPressForcesQuit(t *testing.T) {
+	m, store, client := newQuitTestModel()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(Model)
+
+	// The save has not finished when Ctrl+C is pressed again
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("expected quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("second Ctrl+C should quit immediately")
+	}
+	if len(store.updateMetadataCalls) != 0 || client.CloseCalled {
+		t.Error("force quit should not run the save")
+	}
+}
+
+func TestCtrlC_SavesInterruptedToolStream(t *testing.T) {
+	m, store, _ := newQuitTestModel()
+	m.messages = []chatMessage{{role: "tool", content: "bash: partial output", streaming: true}}
+	m.toolStream = &toolStreamState{index: 0}
+
+	_, cmd := m.handleInterrupt()
+	cmd()
+
+	if len(store.addMessageCalls) != 1 {
+		t.Fatalf("expected the partial tool output to be saved, got %v", store.addMessageCalls)
+	}
+	saved := store.addMessageCalls[0]
+	if saved.role != "tool" || !strings.HasPrefix(saved.content, "bash: partial output") || !strings.HasSuffix(saved.content, "(interrupted)") {
+		t.Errorf("unexpected saved message: %+v", saved)
+	}
+}
+
+func TestCtrlC_OverlaySavesBeforeQuitting(t *testing.T) {
+	m, store, _ := newQuitTestModel()
+	m.showingSettings = true
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(Model)
+	if !m.quitting || cmd == nil {
+		t.Fatal("Ctrl+C in an overlay should start saving")
+	}
+
+	// The overlay must not swallow the save result
+	_, cmd = m.Update(cmd())
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected quit after saving")
+	}
+	if len(store.updateMetadataCalls) != 1 {
+		t.Error("metadata should be flushed")
+	}
+}
+
+// closeableMiddleware is a pass-through tool middleware that counts Close calls
+type closeableMiddleware struct {
+	closed int
+}
+
+func (c *closeableMiddleware) Name() string                                  { return "closeable" }
+func (c *closeableMiddleware) Wrap(next toolexec.ToolFunc) toolexec.ToolFunc { return next }
+func (c *closeableMiddleware) Close() error                                  { c.closed++; return nil }
+
+func TestCloseTooling_ClosesExecutorMiddleware(t *testing.T) {
+	mw := &closeableMiddleware{}
+	m := Model{toolExecutor: toolexec.NewExecutor(defaultToolRegistry(config.DefaultConfig()), toolexec.WithMiddleware(mw))}
+
+	if err := m.closeTooling(); err != nil {
+		t.Fatalf("closeTooling() error = %v", err)
+	}
+	if err := m.closeTooling(); err != nil {
+		t.Fatalf("second closeTooling() error = %v", err)
+	}
+	if mw.closed != 1 {
+		t.Errorf("middleware closed %d times, want 1", mw.closed)
+	}
+
+	if err := (Model{}).closeTooling(); err != nil {
+		t.Errorf("closeTooling() without tooling = %v, want nil", err)
+	}
+}
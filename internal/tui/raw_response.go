@@ -0,0 +1,139 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// secretPatterns match credentials that may appear in a raw response body.
+// The first capture group, if any, is kept so the context stays readable.
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(__Secure-[0-9A-Za-z]+=)[^;\s"\\]+`), // Session cookies
+	regexp.MustCompile(`(?i)(bearer\s+)[0-9A-Za-z\-._~+/]+=*`),
+	regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`),  // Google API keys
+	regexp.MustCompile(`ya29\.[0-9A-Za-z_\-]+`),   // OAuth access tokens
+	regexp.MustCompile(`("SNlM0e"\s*:\s*")[^"]+`), // Access token in page data
+	regexp.MustCompile(`(?i)("?(?:access_?token|api_?key|secret)"?\s*[:=]\s*"?)[^"\s,&]+`),
+}
+
+const redactedText = "[REDACTED]"
+
+// redactSecrets masks obvious secrets in s: the given known values (such as
+// the session's access token and cookies) and common credential formats.
+func redactSecrets(s string, known ...string) string {
+	for _, secret := range known {
+		// Very short values would mask unrelated text
+		if len(secret) >= 8 {
+			s = strings.ReplaceAll(s, secret, redactedText)
+		}
+	}
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "${1}"+redactedText)
+	}
+	return s
+}
+
+// knownSecrets returns the client's credentials so they can be redacted
+func (m Model) knownSecrets() []string {
+	if m.client == nil {
+		return nil
+	}
+	secrets := []string{m.client.GetAccessToken()}
+	if cookies := m.client.GetCookies(); cookies != nil {
+		psid, psidts := cookies.Snapshot()
+		secrets = append(secrets, psid, psidts)
+	}
+	return secrets
+}
+
+// overlayViewportSize returns the width and height of scrollable overlay
+// viewports (raw response, long input, conversation diff)
+func (m Model) overlayViewportSize() (int, int) {
+	width := m.width - 12
+	if width < 40 {
+		width = 40
+	}
+	height := m.height - 10
+	if height < 5 {
+		height = 5
+	}
+	return width, height
+}
+
+// handleRawResponseCommand handles the /raw-response command, which shows
+// the raw API response body of the last turn in a scrollable overlay.
+func (m Model) handleRawResponseCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /raw-response")
+		return m, nil
+	}
+	if m.lastOutput == nil {
+		m.err = fmt.Errorf("no response yet - send a message first")
+		return m, nil
+	}
+	if m.lastOutput.RawResponse == "" {
+		m.err = fmt.Errorf("raw response was not retained for the last turn")
+		return m, nil
+	}
+
+	width, height := m.overlayViewportSize()
+	raw := redactSecrets(m.lastOutput.RawResponse, m.knownSecrets()...)
+
+	m.rawViewport = viewport.New(width, height)
+	m.rawViewport.SetContent(lipgloss.NewStyle().Width(width).Render(raw))
+	m.rawTitle = fmt.Sprintf("Raw response (%d bytes)", len(m.lastOutput.RawResponse))
+	m.showingRaw = true
+	m.err = nil
+	return m, nil
+}
+
+// updateRawResponse handles input while the raw response overlay is shown
+func (m Model) updateRawResponse(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.rawViewport.Width, m.rawViewport.Height = m.overlayViewportSize()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.handleInterrupt()
+		case "esc", "q":
+			m.showingRaw = false
+			m.rawViewport = viewport.Model{}
+			m.rawTitle = ""
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.rawViewport, cmd = m.rawViewport.Update(msg)
+	return m, cmd
+}
+
+// renderRawResponse renders the raw response overlay, which /expand also
+// uses to show a long input
+func (m Model) renderRawResponse() string {
+	var content strings.Builder
+
+	content.WriteString(titleStyle.Render(m.rawTitle))
+	content.WriteString("\n\n")
+	content.WriteString(m.rawViewport.View())
+	content.WriteString("\n\n")
+	content.WriteString(hintStyle.Render(fmt.Sprintf("↑/↓/pgup/pgdn: scroll • esc/q: close • %3.f%%", m.rawViewport.ScrollPercent()*100)))
+
+	panel := messagesAreaStyle.Width(m.rawViewport.Width + 4).Render(content.String())
+	if m.width > 0 && m.height > 0 {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/diogo/geminiweb/internal/render"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// renderMarkdown renders assistant markdown for the viewport.
+// It is a variable so tests can count or stub rendering.
+var renderMarkdown = render.MarkdownWithWidth
+
+// messageRenderKey identifies a rendered message body.
+// format is empty for assistant messages.
+type messageRenderKey struct {
+	content string
+	format  string
+	width   int
+}
+
+// messageRenderCache memoizes rendered message bodies so that
+// updateViewport only runs glamour for messages that are new or changed.
+//
+// The cache is held by pointer in Model so it survives Bubble Tea's value
+// copies. Entries not used during a pass are dropped at the end of it, which
+// keeps the cache bounded by the number of visible messages.
+type messageRenderCache struct {
+	width   int
+	entries map[messageRenderKey]string
+	used    map[messageRenderKey]string
+}
+
+// newMessageRenderCache creates an empty render cache.
+func newMessageRenderCache() *messageRenderCache {
+	return &messageRenderCache{
+		entries: make(map[messageRenderKey]string),
+		used:    make(map[messageRenderKey]string),
+	}
+}
+
+// begin starts a render pass for the given width.
+// A width change invalidates every cached entry.
+func (c *messageRenderCache) begin(width int) {
+	if width != c.width {
+		c.width = width
+		c.entries = make(map[messageRenderKey]string)
+	}
+	c.used = make(map[messageRenderKey]string, len(c.entries))
+}
+
+// end finishes a render pass, keeping only the entries used during it.
+func (c *messageRenderCache) end() {
+	c.entries = c.used
+	c.used = make(map[messageRenderKey]string)
+}
+
+// assistantBody returns the rendered body of an assistant message,
+// rendering it only if it is not already cached.
+func (c *messageRenderCache) assistantBody(content string, width int) string {
+	key := messageRenderKey{content: content, width: width}
+	if rendered, ok := c.used[key]; ok {
+		return rendered
+	}
+	rendered, ok := c.entries[key]
+	if !ok {
+		rendered = renderAssistantBody(content, width)
+	}
+	c.used[key] = rendered
+	return rendered
+}
+
+// toolBody returns the rendered body of a tool message whose output has
+// the given format, rendering it only if it is not already cached.
+func (c *messageRenderCache) toolBody(content, format string, width int) string {
+	key := messageRenderKey{content: content, format: format, width: width}
+	if rendered, ok := c.used[key]; ok {
+		return rendered
+	}
+	rendered, ok := c.entries[key]
+	if !ok {
+		rendered = renderToolBody(content, format, width)
+	}
+	c.used[key] = rendered
+	return rendered
+}
+
+// renderAssistantBody renders an assistant message body without caching.
+// Pure JSON responses are pretty-printed instead of rendered as markdown.
+func renderAssistantBody(content string, width int) string {
+	if rendered, ok := renderJSONMessage(content, width); ok {
+		return rendered
+	}
+
+	rendered, err := renderMarkdown(content, width)
+	if err != nil {
+		return content
+	}
+	// Trim trailing newlines from glamour
+	return strings.TrimRight(rendered, "\n")
+}
+
+// toolOutputHeader separates the call description of a tool message from
+// the tool's output (see formatToolMessage).
+const toolOutputHeader = "\nOutput:\n"
+
+// renderToolBody renders the output section of a tool message according to
+// the format the tool declared: JSON is pretty-printed and markdown goes
+// through glamour. The call description and any other format stay plain
+// text, as does output that does not parse in its declared format.
+func renderToolBody(content, format string, width int) string {
+	idx := strings.Index(content, toolOutputHeader)
+	if idx < 0 {
+		return content
+	}
+	header, output := content[:idx+len(toolOutputHeader)], content[idx+len(toolOutputHeader):]
+
+	switch format {
+	case toolexec.OutputFormatJSON:
+		if rendered, ok := renderJSONMessage(output, width); ok {
+			return header + rendered
+		}
+	case toolexec.OutputFormatMarkdown:
+		if rendered, err := renderMarkdown(output, width); err == nil {
+			return header + strings.Trim(rendered, "\n")
+		}
+	}
+	return content
+}
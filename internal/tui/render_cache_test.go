@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// countingRenderer replaces renderMarkdown with a fake that counts calls
+// per content and restores the original when the test ends.
+func countingRenderer(tb testing.TB) map[string]int {
+	tb.Helper()
+	calls := make(map[string]int)
+	orig := renderMarkdown
+	renderMarkdown = func(content string, width int) (string, error) {
+		calls[content]++
+		return fmt.Sprintf("[%d] %s", width, content), nil
+	}
+	tb.Cleanup(func() { renderMarkdown = orig })
+	return calls
+}
+
+func TestUpdateViewport_CachesRenderedMessages(t *testing.T) {
+	calls := countingRenderer(t)
+	m := newTestModel(withViewport(80, 20), withMessages(
+		chatMessage{role: "user", content: "question"},
+		chatMessage{role: "assistant", content: "first answer"},
+	))
+
+	m.updateViewport()
+	m.updateViewport()
+	if calls["first answer"] != 1 {
+		t.Errorf("unchanged message rendered %d times, want 1", calls["first answer"])
+	}
+
+	// Appending a message only renders the new one
+	m.messages = append(m.messages, chatMessage{role: "assistant", content: "second answer"})
+	m.updateViewport()
+	if calls["first answer"] != 1 {
+		t.Errorf("existing message re-rendered after append: %d calls", calls["first answer"])
+	}
+	if calls["second answer"] != 1 {
+		t.Errorf("new message rendered %d times, want 1", calls["second answer"])
+	}
+	if calls["question"] != 0 {
+		t.Error("user messages should not go through the markdown renderer")
+	}
+}
+
+func TestUpdateViewport_RerendersChangedContent(t *testing.T) {
+	calls := countingRenderer(t)
+	m := newTestModel(withViewport(80, 20), withMessages(chatMessage{role: "assistant", content: "draft"}))
+
+	m.updateViewport()
+	m.messages[0].content = "final"
+	m.updateViewport()
+
+	if calls["draft"] != 1 || calls["final"] != 1 {
+		t.Errorf("unexpected render calls: %v", calls)
+	}
+}
+
+func TestUpdateViewport_WidthChangeInvalidatesCache(t *testing.T) {
+	calls := countingRenderer(t)
+	m := newTestModel(withViewport(80, 20), withMessages(chatMessage{role: "assistant", content: "answer"}))
+
+	m.updateViewport()
+	m.viewport.Width = 100
+	m.updateViewport()
+	if calls["answer"] != 2 {
+		t.Errorf("render calls after width change = %d, want 2", calls["answer"])
+	}
+
+	// Going back to the old width must not reuse stale entries
+	m.viewport.Width = 80
+	m.updateViewport()
+	if calls["answer"] != 3 {
+		t.Errorf("render calls after restoring width = %d, want 3", calls["answer"])
+	}
+}
+
+func TestUpdateViewport_DropsUnusedEntries(t *testing.T) {
+	countingRenderer(t)
+	m := newTestModel(withViewport(80, 20), withMessages(
+		chatMessage{role: "assistant", content: "one"},
+		chatMessage{role: "assistant", content: "two"},
+	))
+
+	m.updateViewport()
+	m.messages = m.messages[:1]
+	m.updateViewport()
+
+	if len(m.renderCache.entries) != 1 {
+		t.Errorf("cache entries = %d, want 1", len(m.renderCache.entries))
+	}
+}
+
+func benchmarkUpdateViewport(b *testing.B, cached bool) {
+	messages := make([]chatMessage, 0, 100)
+	for i := 0; i < 50; i++ {
+		messages = append(messages,
+			chatMessage{role: "user", content: fmt.Sprintf("Question %d", i)},
+			chatMessage{role: "assistant", content: fmt.Sprintf("## Answer %d\n\nSome **bold** text, a list:\n\n- one\n- two\n\n```go\nfmt.Println(%d)\n```\n", i, i)},
+		)
+	}
+	m := newTestModel(withViewport(100, 20), withMessages(messages...))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if !cached {
+			m.renderCache = nil
+		}
+		m.updateViewport()
+	}
+}
+
+func BenchmarkUpdateViewportNoCache(b *testing.B) {
+	benchmarkUpdateViewport(b, false)
+}
+
+func BenchmarkUpdateViewportWithCache(b *testing.B) {
+	benchmarkUpdateViewport(b, true)
+}
+
+func TestUpdateViewport_ToolResultFormats(t *testing.T) {
+	calls := countingRenderer(t)
+	render := func(msg chatMessage) string {
+		m := newTestModel(withViewport(80, 20), withMessages(msg))
+		m.updateViewport()
+		return ansiPattern.ReplaceAllString(m.viewport.View(), "")
+	}
+
+	t.Run("json is pretty-printed", func(t *testing.T) {
+		got := render(chatMessage{
+			role:    "tool",
+			content: "Tool: lookup\nOutput:\n{\"name\":\"alpha\",\"count\":2}",
+			format:  toolexec.OutputFormatJSON,
+		})
+		if !strings.Contains(got, `"name": "alpha",`) || !strings.Contains(got, `"count": 2`) {
+			t.Errorf("JSON tool output not pretty-printed:\n%s", got)
+		}
+		if !strings.Contains(got, "Tool: lookup") {
+			t.Errorf("tool header missing:\n%s", got)
+		}
+	})
+
+	t.Run("markdown is rendered", func(t *testing.T) {
+		got := render(chatMessage{
+			role:    "tool",
+			content: "Tool: docs\nOutput:\n# Heading",
+			format:  toolexec.OutputFormatMarkdown,
+		})
+		if calls["# Heading"] != 1 {
+			t.Errorf("markdown output rendered %d times, want 1", calls["# Heading"])
+		}
+		if !strings.Contains(got, "] # Heading") {
+			t.Errorf("markdown tool output not rendered:\n%s", got)
+		}
+		if calls["Tool: docs\nOutput:\n# Heading"] != 0 {
+			t.Error("tool header should not go through the markdown renderer")
+		}
+	})
+
+	t.Run("plain text is unchanged", func(t *testing.T) {
+		content := "Tool: echo\nOutput:\n{\"raw\":true}"
+		got := render(chatMessage{role: "tool", content: content})
+		if !strings.Contains(got, `{"raw":true}`) {
+			t.Errorf("plain tool output changed:\n%s", got)
+		}
+		if len(calls) != 1 {
+			t.Errorf("plain tool output should not be rendered, calls = %v", calls)
+		}
+	})
+
+	t.Run("invalid json falls back to text", func(t *testing.T) {
+		content := "Tool: lookup\nOutput:\n{broken\n[output truncated]"
+		if got := renderToolBody(content, toolexec.OutputFormatJSON, 60); got != content {
+			t.Errorf("renderToolBody() = %q, want unchanged", got)
+		}
+	})
+}
+
+func TestHandleToolResult_RecordsOutputFormat(t *testing.T) {
+	m := newTestModel(withViewport(80, 20))
+	output := toolexec.NewOutput().WithData([]byte(`{"ok":true}`))
+	output.Metadata[toolexec.OutputFormatKey] = toolexec.OutputFormatJSON
+	result := toolexec.NewResult("lookup", output, nil)
+
+	m.handleToolResult(toolexec.ToolCall{Name: "lookup"}, result)
+
+	if len(m.messages) != 1 || m.messages[0].format != toolexec.OutputFormatJSON {
+		t.Fatalf("tool message format not recorded: %+v", m.messages)
+	}
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+// MostRecentConversation returns the most recently updated conversation in
+// store, or nil when there are none. Archived conversations are skipped. The
+// list order is the user's arrangement, so it is not relied on.
+func MostRecentConversation(store FullHistoryStore) (*history.Conversation, error) {
+	conversations, err := store.ListConversations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list conversations: %w", err)
+	}
+
+	var latest *history.Conversation
+	for _, conv := range conversations {
+		if conv == nil || conv.Archived {
+			continue
+		}
+		if latest == nil || conv.UpdatedAt.After(latest.UpdatedAt) {
+			latest = conv
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/diogo/geminiweb/internal/history"
+)
+
+func TestMostRecentConversation(t *testing.T) {
+	now := time.Now()
+	store := &mockFullHistoryStore{conversations: []*history.Conversation{
+		{ID: "pinned-old", UpdatedAt: now.Add(-48 * time.Hour)},
+		{ID: "newest", UpdatedAt: now.Add(-time.Minute)},
+		{ID: "archived", UpdatedAt: now, Archived: true},
+		{ID: "middle", UpdatedAt: now.Add(-time.Hour)},
+	}}
+
+	conv, err := MostRecentConversation(store)
+	if err != nil {
+		t.Fatalf("MostRecentConversation() error = %v", err)
+	}
+	if conv == nil || conv.ID != "newest" {
+		t.Errorf("conversation = %+v, want newest", conv)
+	}
+
+	t.Run("empty history", func(t *testing.T) {
+		conv, err := MostRecentConversation(&mockFullHistoryStore{})
+		if err != nil || conv != nil {
+			t.Errorf("got %+v, %v; want nil, nil", conv, err)
+		}
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		_, err := MostRecentConversation(&mockFullHistoryStore{listErr: errors.New("disk full")})
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+// handleRetryCommand handles the /retry command, which re-sends the last
+// user message with its attachments and replaces the reply to it, on
+// screen and in history, with the regenerated one. With word diffs
+// enabled, the new reply shows what changed.
+func (m Model) handleRetryCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /retry")
+		return m, nil
+	}
+	if m.session == nil {
+		m.err = fmt.Errorf("no active session")
+		return m, nil
+	}
+	if m.isBusy() {
+		m.err = errBusy
+		return m, nil
+	}
+	last := len(m.messages) - 1
+	if last < 1 || m.messages[last].role != "assistant" || m.messages[last-1].role != "user" {
+		m.err = fmt.Errorf("nothing to retry - the last message is not a reply")
+		return m, nil
+	}
+
+	// Assemble the prompt as for the original send
+	user := m.messages[last-1]
+	prompt := m.assemblePrompt(user.content, user.attachments)
+
+	cmd := m.beginSend(func(m *Model) tea.Cmd {
+		m.loading = true
+		m.err = nil
+		m.animationFrame = 0
+		return tea.Batch(m.sendRetry(prompt, user.attachments), animationTick())
+	})
+	return m, cmd
+}
+
+// sendRetry re-sends a prompt and files; the reply replaces the last
+// assistant message
+func (m Model) sendRetry(prompt string, files []*api.UploadedFile) tea.Cmd {
+	session := m.session
+	jobs := m.jobs
+	return func() tea.Msg {
+		output, err := sendToModel(jobs, session, prompt, files)
+		if err != nil {
+			return errMsg{err: fmt.Errorf("retry: %w", err)}
+		}
+		return responseMsg{output: output, regenerated: true}
+	}
+}
+
+// replaceRegenerated replaces a reply with its regeneration, diffing it
+// against the old text when word diffs are enabled. An identical reply
+// gets no diff.
+func (m Model) replaceRegenerated(prev, next chatMessage) chatMessage {
+	if !m.retryWordDiff {
+		return next
+	}
+	changes := wordDiff(prev.content, next.content)
+	for _, seg := range changes {
+		if seg.Kind != SegmentEqual {
+			next.changes = changes
+			break
+		}
+	}
+	return next
+}
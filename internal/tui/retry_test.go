@@ -0,0 +1,151 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// retryReply runs /retry and delivers the regenerated reply
+func retryReply(t *testing.T, m Model) Model {
+	t.Helper()
+	updated, cmd := m.handleRetryCommand("")
+	m = updated.(Model)
+	if !m.loading || cmd == nil {
+		t.Fatalf("expected a retry request to be sent, err = %v", m.err)
+	}
+
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("expected a batch command")
+	}
+	msg, ok := batch[0]().(responseMsg)
+	if !ok || !msg.regenerated {
+		t.Fatalf("expected a regenerated response, got %#v", msg)
+	}
+	updated, _ = m.Update(msg)
+	return updated.(Model)
+}
+
+// newRetryTestModel returns a chat with one reply to retry, recording the
+// prompts sent in sent
+func newRetryTestModel(sent *[]string) Model {
+	return newTestModel(withSession(replyingSession("Revenue grew 12% last year.", sent)), withMessages(
+		chatMessage{role: "user", content: "summarize the report"},
+		chatMessage{role: "assistant", content: "Revenue grew 10% last year."},
+	))
+}
+
+func TestRetryCommand_ReplacesReply(t *testing.T) {
+	var sent []string
+	m := retryReply(t, newRetryTestModel(&sent))
+
+	if len(sent) != 1 || sent[0] != "summarize the report" {
+		t.Errorf("sent %q, want the last user message", sent)
+	}
+	if len(m.messages) != 2 || m.messages[1].content != "Revenue grew 12% last year." {
+		t.Fatalf("the reply should be replaced, got %+v", m.messages)
+	}
+	if m.messages[1].changes != nil {
+		t.Error("word diff should be off by default")
+	}
+	if strings.Contains(m.viewport.View(), "Changes from previous reply") {
+		t.Error("no diff should be rendered when disabled")
+	}
+}
+
+func TestRetryCommand_ShowsWordDiff(t *testing.T) {
+	var sent []string
+	m := newRetryTestModel(&sent)
+	m.retryWordDiff = true
+	m = retryReply(t, m)
+
+	view := ansiPattern.ReplaceAllString(m.viewport.View(), "")
+	for _, want := range []string{"Changes from previous reply:", "Revenue grew [-10%-]{+12%+} last year."} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// An identical regeneration has nothing to show
+	m = retryReply(t, m)
+	if m.messages[1].changes != nil || strings.Contains(m.viewport.View(), "Changes from previous reply") {
+		t.Error("identical replies should not show a diff")
+	}
+}
+
+func TestRetryCommand_ReplacesStoredReply(t *testing.T) {
+	store, err := history.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	conv, _ := store.CreateConversation("test-model")
+	_ = store.AddMessage(conv.ID, "user", "summarize the report", "")
+	_ = store.AddMessage(conv.ID, "assistant", "Revenue grew 10% last year.", "")
+
+	var sent []string
+	m := newRetryTestModel(&sent)
+	m.conversation = conv
+	m.historyStore = store
+	m = retryReply(t, m)
+
+	stored, _ := store.GetConversation(conv.ID)
+	if len(stored.Messages) != 2 || stored.Messages[1].Content != "Revenue grew 12% last year." {
+		t.Errorf("stored messages = %+v, want the old reply replaced", stored.Messages)
+	}
+}
+
+func TestRetryCommand_ResendsAttachments(t *testing.T) {
+	var sentFiles []*api.UploadedFile
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			sentFiles = files
+			return &models.ModelOutput{Candidates: []models.Candidate{{Text: "It shows a cat."}}}, nil
+		},
+	}
+	file := &api.UploadedFile{FileName: "photo.png"}
+	m := newTestModel(withSession(session), withMessages(
+		chatMessage{role: "user", content: "describe this", attachments: []*api.UploadedFile{file}},
+		chatMessage{role: "assistant", content: "It shows a dog."},
+	))
+	m = retryReply(t, m)
+
+	if len(sentFiles) != 1 || sentFiles[0] != file {
+		t.Errorf("retry sent files %v, want the original attachment", sentFiles)
+	}
+}
+
+func TestRetryCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *Model)
+		args  string
+		want  string
+	}{
+		{"arguments", nil, "now", "usage: /retry"},
+		{"no session", func(m *Model) { m.session = nil }, "", "no active session"},
+		{"loading", func(m *Model) { m.loading = true }, "", "wait for the current response"},
+		{"no reply", func(m *Model) { m.messages = m.messages[:1] }, "", "nothing to retry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent []string
+			m := newRetryTestModel(&sent)
+			if tt.setup != nil {
+				tt.setup(&m)
+			}
+			updated, cmd := m.handleRetryCommand(tt.args)
+			if cmd != nil {
+				t.Error("expected no request")
+			}
+			if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// searchSnippetMaxLen caps how much of each snippet a tool bubble shows
+const searchSnippetMaxLen = 200
+
+// formatSearchResults renders ranked search results as a numbered list,
+// one title line per result followed by its indented snippet
+func formatSearchResults(results []toolexec.SearchResult) string {
+	var sb strings.Builder
+	for i, result := range results {
+		title := result.Title
+		if title == "" {
+			title = result.URL
+		}
+		fmt.Fprintf(&sb, "%d. %s", i+1, title)
+		if result.Score > 0 {
+			sb.WriteString(" (score " + strconv.FormatFloat(result.Score, 'g', -1, 64) + ")")
+		}
+		sb.WriteString("\n")
+		if snippet := strings.TrimSpace(result.Snippet); snippet != "" {
+			if short := truncateRunes(snippet, searchSnippetMaxLen); short != snippet {
+				snippet = short + "…"
+			}
+			sb.WriteString("   " + snippet + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+func TestFormatSearchResults(t *testing.T) {
+	got := formatSearchResults([]toolexec.SearchResult{
+		{Title: "a.go:3", URL: "a.go", Snippet: "  func a()  ", Score: 2},
+		{URL: "b.go", Snippet: strings.Repeat("x", searchSnippetMaxLen+10)},
+	})
+	want := "1. a.go:3 (score 2)\n   func a()\n2. b.go\n   " + strings.Repeat("x", searchSnippetMaxLen) + "…"
+	if got != want {
+		t.Errorf("formatSearchResults() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFormatToolMessage_SearchResults(t *testing.T) {
+	output := toolexec.NewOutput().WithData([]byte("a.go:3:func a()\n"))
+	output.Result["results"] = []toolexec.SearchResult{
+		{Title: "a.go:3", URL: "a.go", Snippet: "func a()", Score: 1},
+	}
+	result := toolexec.NewSuccessResult("search", output)
+
+	msg := formatToolMessage(toolexec.ToolCall{Name: "search"}, result)
+	if !strings.Contains(msg, "Output:\n1. a.go:3 (score 1)\n   func a()") {
+		t.Errorf("search results should be rendered as a ranked list:\n%s", msg)
+	}
+
+	output.Result["results"] = []toolexec.SearchResult{}
+	output.Message = "no matches found"
+	output.Data = []byte(output.Message)
+	msg = formatToolMessage(toolexec.ToolCall{Name: "search"}, result)
+	if !strings.Contains(msg, "Output:\nno matches found") {
+		t.Errorf("empty results should fall back to the raw output:\n%s", msg)
+	}
+
+	// Other tools' output is not reformatted, even with a "results" field
+	output.Result["results"] = []toolexec.SearchResult{{Title: "row 1", Snippet: "x"}}
+	output.Data = []byte(`{"results":[{"title":"row 1"}]}`)
+	msg = formatToolMessage(toolexec.ToolCall{Name: "http_request"}, toolexec.NewSuccessResult("http_request", output))
+	if strings.Contains(msg, "1. row 1") || !strings.Contains(msg, `{"results":`) {
+		t.Errorf("non-search output should be shown as is:\n%s", msg)
+	}
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"errors"
+	"time"
+)
+
+// duplicateSendWindow is how soon after a send, or the reply to it, an
+// identical prompt to the same session is taken for a duplicate Enter and
+// ignored
+const duplicateSendWindow = 500 * time.Millisecond
+
+// errDuplicateSend reports an ignored duplicate send
+var errDuplicateSend = errors.New("duplicate message ignored")
+
+// lastSend records the last prompt sent from the input, to catch
+// duplicate sends
+type lastSend struct {
+	prompt  string
+	session ChatSessionInterface
+	at      time.Time
+}
+
+// isDuplicateSend reports whether sending prompt now would repeat the last
+// send to the same session within duplicateSendWindow. Sessions are
+// compared by identity.
+func (m Model) isDuplicateSend(prompt string, now time.Time) bool {
+	last := m.lastSend
+	return last.prompt == prompt &&
+		last.session == m.session &&
+		now.Sub(last.at) < duplicateSendWindow
+}
+
+// recordSend records prompt as the last prompt sent from the input
+func (m *Model) recordSend(prompt string, now time.Time) {
+	m.lastSend = lastSend{prompt: prompt, session: m.session, at: now}
+}
+
+// restartDuplicateWindow restarts the duplicate window when a reply or
+// error ends the wait for a send. Keys typed while waiting are dropped, so
+// a repeated submission can only get through once the input is back.
+func (m *Model) restartDuplicateWindow(now time.Time) {
+	if m.lastSend.prompt != "" {
+		m.lastSend.at = now
+	}
+}
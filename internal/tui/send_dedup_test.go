@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// submit types input and presses Enter like a terminal would, then
+// delivers the reply to a send that started
+func submit(t *testing.T, m Model, input string) Model {
+	t.Helper()
+	m, cmd := typeKeys(m, input)
+	if cmd == nil {
+		return m
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("expected a batch command")
+	}
+	updated, _ := m.Update(batch[0]()) // The send; the rest are animation ticks
+	return updated.(Model)
+}
+
+// typeKeys delivers input as key presses followed by Enter
+func typeKeys(m Model, input string) (Model, tea.Cmd) {
+	for _, r := range input {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	return updated.(Model), cmd
+}
+
+func TestSendDedup_DuplicateWhileWaitingIsDropped(t *testing.T) {
+	var sends []string
+	m := newTestModel(withSession(replyingSession("Hi!", &sends)), withInput(""))
+
+	m, cmd := typeKeys(m, "Hello")
+	if cmd == nil || !m.loading {
+		t.Fatal("the first submission should send")
+	}
+	// The duplicate arrives while the reply is pending
+	m, dup := typeKeys(m, "Hello")
+	if dup != nil {
+		t.Error("a duplicate while waiting should not send")
+	}
+	if m.textarea.Value() != "" {
+		t.Errorf("keys typed while waiting should be dropped, input = %q", m.textarea.Value())
+	}
+}
+
+func TestSendDedup_IgnoresDuplicateAfterReply(t *testing.T) {
+	var sends []string
+	m := newTestModel(withSession(replyingSession("Hi!", &sends)), withInput(""))
+
+	m, cmd := typeKeys(m, "Hello")
+	// The reply takes longer than the window
+	m.lastSend.at = m.lastSend.at.Add(-2 * duplicateSendWindow)
+	updated, _ := m.Update(cmd().(tea.BatchMsg)[0]())
+	m = updated.(Model)
+	if m.loading || len(m.messages) != 2 {
+		t.Fatalf("expected the reply to be in, loading = %v, messages = %d", m.loading, len(m.messages))
+	}
+	// The duplicate is delivered right after the reply
+	m = submit(t, m, "Hello")
+
+	if len(sends) != 1 {
+		t.Errorf("sends = %d, want 1", len(sends))
+	}
+	if len(m.messages) != 2 {
+		t.Errorf("messages = %d, want 2", len(m.messages))
+	}
+	if m.err != errDuplicateSend {
+		t.Errorf("err = %v, want %v", m.err, errDuplicateSend)
+	}
+	if m.textarea.Value() != "" {
+		t.Errorf("the duplicate should be cleared from the input, got %q", m.textarea.Value())
+	}
+
+	// A different prompt is sent right away
+	m = submit(t, m, "Hello again")
+	if len(sends) != 2 {
+		t.Errorf("sends = %d after a different prompt, want 2", len(sends))
+	}
+}
+
+func TestSendDedup_OutsideWindowOrOtherSession(t *testing.T) {
+	var sends []string
+	m := newTestModel(withSession(replyingSession("Hi!", &sends)), withInput(""))
+
+	m = submit(t, m, "Hello")
+	m.lastSend.at = m.lastSend.at.Add(-2 * duplicateSendWindow)
+	m = submit(t, m, "Hello")
+	if len(sends) != 2 {
+		t.Errorf("sends = %d, want the prompt resent after the window", len(sends))
+	}
+
+	session := m.session
+	m = newTestModel(withSession(replyingSession("Hi!", &sends)), withInput(""))
+	m.lastSend.prompt, m.lastSend.session, m.lastSend.at = "Hello", session, time.Now()
+	m = submit(t, m, "Hello")
+	if len(sends) != 3 {
+		t.Errorf("sends = %d, want the prompt sent to a new session", len(sends))
+	}
+}
+
+func TestIsDuplicateSend(t *testing.T) {
+	m := Model{session: &mockChatSession{}}
+	now := time.Now()
+	m.recordSend("Hello", now)
+
+	if !m.isDuplicateSend("Hello", now.Add(duplicateSendWindow/2)) {
+		t.Error("identical prompt within the window should be a duplicate")
+	}
+	if m.isDuplicateSend("Hello", now.Add(duplicateSendWindow)) {
+		t.Error("identical prompt after the window should not be a duplicate")
+	}
+	if m.isDuplicateSend("Hello!", now) {
+		t.Error("a different prompt should not be a duplicate")
+	}
+}
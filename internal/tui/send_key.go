@@ -0,0 +1,53 @@
+package tui
+
+import "github.com/diogo/geminiweb/internal/config"
+
+// inputNewlineKey is the action inputKey maps Enter to when Ctrl+Enter sends
+const inputNewlineKey = "newline"
+
+// ctrlEnterKeys send a message when Ctrl+Enter sends. Most terminals report
+// Ctrl+Enter as Ctrl+J; Alt+Enter works in those that do not.
+var ctrlEnterKeys = map[string]bool{
+	"ctrl+j":    true,
+	"alt+enter": true,
+}
+
+// inputKey maps a key pressed in the chat input to the key handled by
+// Update. When Ctrl+Enter sends, Enter becomes inputNewlineKey and the
+// Ctrl+Enter keys become "enter"; otherwise keys are unchanged.
+func (m Model) inputKey(key string) string {
+	if !m.ctrlEnterSends {
+		return key
+	}
+	switch {
+	case key == "enter":
+		return inputNewlineKey
+	case ctrlEnterKeys[key]:
+		return "enter"
+	}
+	return key
+}
+
+// inputPlaceholder returns the chat input placeholder for a send key
+func inputPlaceholder(sendKey string) string {
+	if sendKey == config.SendKeyCtrlEnter {
+		return "Type your message... (Ctrl+Enter to send)"
+	}
+	return "Type your message... (\\ + Enter for newline)"
+}
+
+// sendKey returns the config.SendKey value for the current send key
+func (m Model) sendKey() string {
+	if m.ctrlEnterSends {
+		return config.SendKeyCtrlEnter
+	}
+	return config.SendKeyEnter
+}
+
+// sendKeyLabel formats a send key for display
+func sendKeyLabel(sendKey string) string {
+	if sendKey == config.SendKeyCtrlEnter {
+		return "Ctrl+Enter"
+	}
+	return "Enter"
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/config"
+)
+
+func TestSendKey_Enter(t *testing.T) {
+	t.Run("enter sends", func(t *testing.T) {
+		m := newTestModel(withSession(&mockChatSession{}), withInput("Hello"))
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+		if cmd == nil || !m.loading || len(m.messages) != 1 {
+			t.Fatalf("Enter should send: loading=%v messages=%d", m.loading, len(m.messages))
+		}
+	})
+
+	t.Run("backslash enter inserts a newline", func(t *testing.T) {
+		m := newTestModel(withSession(&mockChatSession{}), withInput(`line one\`))
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+		if m.loading || m.textarea.Value() != "line one\n" {
+			t.Errorf("\\+Enter should insert a newline, got %q (loading=%v)", m.textarea.Value(), m.loading)
+		}
+	})
+
+	t.Run("ctrl+j does not send", func(t *testing.T) {
+		m := newTestModel(withSession(&mockChatSession{}), withInput("Hello"))
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlJ})
+		m = updated.(Model)
+		if m.loading || len(m.messages) != 0 {
+			t.Error("Ctrl+Enter should not send when Enter sends")
+		}
+	})
+}
+
+func TestSendKey_CtrlEnter(t *testing.T) {
+	t.Run("enter inserts a newline", func(t *testing.T) {
+		m := newTestModel(withSession(&mockChatSession{}), withInput("line one"), func(m *Model) { m.ctrlEnterSends = true })
+		updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		m = updated.(Model)
+		if cmd != nil || m.loading || len(m.messages) != 0 {
+			t.Fatal("Enter should not send when Ctrl+Enter sends")
+		}
+		if got := m.textarea.Value(); got != "line one\n" {
+			t.Errorf("textarea = %q, want a newline inserted", got)
+		}
+	})
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyCtrlJ},
+		{Type: tea.KeyEnter, Alt: true},
+	} {
+		t.Run(key.String()+" sends", func(t *testing.T) {
+			m := newTestModel(withSession(&mockChatSession{}), withInput("first\nsecond\\"), func(m *Model) { m.ctrlEnterSends = true })
+			updated, cmd := m.Update(key)
+			m = updated.(Model)
+			if cmd == nil || !m.loading || len(m.messages) != 1 {
+				t.Fatalf("%s should send: loading=%v messages=%d", key, m.loading, len(m.messages))
+			}
+			if got := m.messages[0].content; got != "first\nsecond\\" {
+				t.Errorf("sent %q, want the input as typed", got)
+			}
+		})
+	}
+}
+
+func TestSendKey_Hints(t *testing.T) {
+	if p := inputPlaceholder(config.SendKeyCtrlEnter); !strings.Contains(p, "Ctrl+Enter to send") {
+		t.Errorf("ctrl+enter placeholder = %q", p)
+	}
+	if p := inputPlaceholder(""); !strings.Contains(p, "\\ + Enter for newline") {
+		t.Errorf("default placeholder = %q", p)
+	}
+
+	bar := newTestModel(withSession(&mockChatSession{}), withInput(""), func(m *Model) { m.ctrlEnterSends = true }).renderStatusBar(200)
+	if !strings.Contains(bar, "^Enter") || !strings.Contains(bar, "Send") {
+		t.Errorf("status bar should show ^Enter to send:\n%s", bar)
+	}
+	if strings.Contains(newTestModel(withSession(&mockChatSession{}), withInput("")).renderStatusBar(200), "^Enter") {
+		t.Error("status bar should show Enter to send by default")
+	}
+}
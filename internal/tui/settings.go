@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/render"
+)
+
+// chatSetting is a runtime option listed in the /settings overlay.
+// value reports the current value, toggle changes it in the running chat and
+// save copies it into the config persisted by config.SaveConfig.
+type chatSetting struct {
+	label  string
+	value  func(m Model) string
+	toggle func(m *Model)
+	save   func(m Model, cfg *config.Config)
+}
+
+// chatSettings are the options shown in the /settings overlay, in order
+var chatSettings = []chatSetting{
+	{
+		label:  "Message timestamps",
+		value:  func(m Model) string { return settingBool(m.showTimestamps) },
+		toggle: func(m *Model) { m.showTimestamps = !m.showTimestamps },
+		save:   func(m Model, cfg *config.Config) { cfg.ShowTimestamps = m.showTimestamps },
+	},
+	{
+		label:  "Auto-approve tools",
+		value:  func(m Model) string { return settingBool(m.autoApproveTools) },
+		toggle: func(m *Model) { m.autoApproveTools = !m.autoApproveTools },
+		save:   func(m Model, cfg *config.Config) { cfg.AutoApproveTools = m.autoApproveTools },
+	},
+	{
+		label:  "Tool plan review",
+		value:  func(m Model) string { return settingBool(m.toolPlanReview) },
+		toggle: func(m *Model) { m.toolPlanReview = !m.toolPlanReview },
+		save:   func(m Model, cfg *config.Config) { cfg.ToolPlanReview = m.toolPlanReview },
+	},
+	{
+		label:  "Attachment note",
+		value:  func(m Model) string { return settingBool(m.attachmentNote) },
+		toggle: func(m *Model) { m.attachmentNote = !m.attachmentNote },
+		save:   func(m Model, cfg *config.Config) { cfg.AttachmentNote = m.attachmentNote },
+	},
+	{
+		label:  "Store thoughts in history",
+		value:  func(m Model) string { return settingBool(!m.discardThoughts) },
+		toggle: func(m *Model) { m.discardThoughts = !m.discardThoughts },
+		save:   func(m Model, cfg *config.Config) { cfg.StoreThoughts = !m.discardThoughts },
+	},
+	{
+		label:  "Stream tool output",
+		value:  func(m Model) string { return settingBool(m.streamToolOutput) },
+		toggle: func(m *Model) { m.streamToolOutput = !m.streamToolOutput },
+		save:   func(m Model, cfg *config.Config) { cfg.StreamToolOutput = m.streamToolOutput },
+	},
+	{
+		label:  "Compact messages",
+		value:  func(m Model) string { return settingBool(m.compactMessages) },
+		toggle: func(m *Model) { m.compactMessages = !m.compactMessages },
+		save:   func(m Model, cfg *config.Config) { cfg.CompactMessages = m.compactMessages },
+	},
+	{
+		label:  "No-emoji mode",
+		value:  func(m Model) string { return settingBool(noEmoji) },
+		toggle: func(m *Model) { SetNoEmoji(!noEmoji) },
+		save:   func(m Model, cfg *config.Config) { cfg.NoEmoji = noEmoji },
+	},
+	{
+		label: "Send key",
+		value: func(m Model) string { return sendKeyLabel(m.sendKey()) },
+		toggle: func(m *Model) {
+			m.ctrlEnterSends = !m.ctrlEnterSends
+			m.textarea.Placeholder = inputPlaceholder(m.sendKey())
+		},
+		save: func(m Model, cfg *config.Config) { cfg.SendKey = m.sendKey() },
+	},
+	{
+		label:  "Theme",
+		value:  func(m Model) string { return render.GetTUITheme().Name },
+		toggle: func(m *Model) { cycleTUITheme() },
+		save:   func(m Model, cfg *config.Config) { cfg.TUITheme = render.GetTUITheme().Name },
+	},
+}
+
+// settingBool formats a boolean setting value
+func settingBool(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+// cycleTUITheme switches to the next TUI theme and restyles the chat
+func cycleTUITheme() {
+	names := render.TUIThemeNames()
+	current := render.GetTUITheme().Name
+	next := names[0]
+	for i, name := range names {
+		if name == current {
+			next = names[(i+1)%len(names)]
+			break
+		}
+	}
+	render.SetTUITheme(next)
+	UpdateTheme()
+}
+
+// handleSettingsCommand handles the /settings command, which opens the
+// settings overlay
+func (m Model) handleSettingsCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /settings")
+		return m, nil
+	}
+
+	m.showingSettings = true
+	m.settingsCursor = 0
+	m.settingsFeedback = ""
+	m.err = nil
+	return m, nil
+}
+
+// handleSaveConfigCommand handles the /save-config command, which persists
+// the current runtime settings without opening the overlay
+func (m Model) handleSaveConfigCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /save-config")
+		return m, nil
+	}
+
+	if err := m.saveSettings(); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = fmt.Errorf("✓ Settings saved to config")
+	return m, nil
+}
+
+// saveSettings merges the current runtime settings into the saved config
+func (m Model) saveSettings() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, s := range chatSettings {
+		s.save(m, &cfg)
+	}
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// updateSettings handles input while the settings overlay is shown
+func (m Model) updateSettings(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.handleInterrupt()
+
+		case "esc", "q":
+			m.showingSettings = false
+			m.settingsFeedback = ""
+			return m, nil
+
+		case "up", "k":
+			m.settingsCursor--
+			if m.settingsCursor < 0 {
+				m.settingsCursor = len(chatSettings) - 1
+			}
+
+		case "down", "j":
+			m.settingsCursor = (m.settingsCursor + 1) % len(chatSettings)
+
+		case " ", "enter":
+			setting := chatSettings[m.settingsCursor]
+			setting.toggle(&m)
+			m.updateViewport()
+			if err := m.saveSettings(); err != nil {
+				m.settingsFeedback = fmt.Sprintf("Error: %v", err)
+			} else {
+				m.settingsFeedback = fmt.Sprintf("%s: %s (saved)", setting.label, setting.value(m))
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// renderSettings renders the settings overlay
+func (m Model) renderSettings() string {
+	width := m.width - 8
+	if width < 40 {
+		width = 40
+	}
+
+	var content strings.Builder
+	content.WriteString(configTitleStyle.Render(icon("⚙", "[settings]") + " Settings"))
+	content.WriteString("\n\n")
+
+	for i, s := range chatSettings {
+		cursor := "  "
+		labelStyle := configMenuItemStyle
+		if i == m.settingsCursor {
+			cursor = configCursorStyle.Render("▸ ")
+			labelStyle = configMenuSelectedStyle
+		}
+
+		value := s.value(m)
+		var valueText string
+		switch value {
+		case "enabled":
+			valueText = configEnabledStyle.Render(value)
+		case "disabled":
+			valueText = configDisabledStyle.Render(value)
+		default:
+			valueText = configValueStyle.Render(value)
+		}
+
+		content.WriteString(cursor + labelStyle.Width(28).Render(s.label) + " " + valueText + "\n")
+	}
+
+	content.WriteString("\n")
+	if m.settingsFeedback != "" {
+		content.WriteString(hintStyle.Render(m.settingsFeedback))
+		content.WriteString("\n\n")
+	}
+
+	shortcuts := []string{
+		statusKeyStyle.Render("↑↓") + statusDescStyle.Render(" Navigate"),
+		statusKeyStyle.Render("Space") + statusDescStyle.Render(" Toggle"),
+		statusKeyStyle.Render("Esc") + statusDescStyle.Render(" Close"),
+	}
+	content.WriteString(strings.Join(shortcuts, "  │  "))
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(colorPrimary).
+		Padding(1, 2).
+		Width(width)
+
+	return boxStyle.Render(content.String())
+}
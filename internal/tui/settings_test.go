@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/render"
+)
+
+func newSettingsTestModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	theme := render.GetTUITheme().Name
+	t.Cleanup(func() {
+		render.SetTUITheme(theme)
+		UpdateTheme()
+	})
+
+	return newTestModel(func(m *Model) { m.showTimestamps = true })
+}
+
+func openSettings(t *testing.T, m Model) Model {
+	t.Helper()
+	updated, _ := m.handleSettingsCommand("")
+	m = updated.(Model)
+	if !m.showingSettings {
+		t.Fatal("expected settings overlay to be shown")
+	}
+	return m
+}
+
+func settingLine(view, label string) string {
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, label) {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestSettingsOverlay_RendersCurrentValues(t *testing.T) {
+	m := openSettings(t, newSettingsTestModel(t))
+	m.discardThoughts = true
+
+	view := ansiPattern.ReplaceAllString(m.View(), "")
+	tests := []struct {
+		label string
+		value string
+	}{
+		{"Message timestamps", "enabled"},
+		{"Auto-approve tools", "disabled"},
+		{"Store thoughts in history", "disabled"},
+		{"Theme", render.GetTUITheme().Name},
+	}
+	for _, tt := range tests {
+		line := settingLine(view, tt.label)
+		if line == "" {
+			t.Errorf("overlay is missing %q", tt.label)
+			continue
+		}
+		if !strings.Contains(line, tt.value) {
+			t.Errorf("%s line = %q, want value %q", tt.label, line, tt.value)
+		}
+	}
+	if !strings.Contains(settingLine(view, "Message timestamps"), "▸") {
+		t.Error("cursor should start on the first setting")
+	}
+}
+
+func TestSettingsOverlay_TogglePersistsConfig(t *testing.T) {
+	m := newSettingsTestModel(t)
+
+	// Settings not shown in the overlay must survive the save
+	existing := config.DefaultConfig()
+	existing.DefaultModel = "fast"
+	existing.PromptPrefix = "be brief"
+	if err := config.SaveConfig(existing); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	m = openSettings(t, m)
+
+	// Move to "Auto-approve tools" and toggle it
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(Model)
+
+	if !m.autoApproveTools {
+		t.Error("toggle should enable auto-approve in the running chat")
+	}
+	if !strings.Contains(m.settingsFeedback, "saved") {
+		t.Errorf("unexpected feedback: %q", m.settingsFeedback)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	want := existing
+	want.AutoApproveTools = true
+	want.ShowTimestamps = true
+	want.StoreThoughts = true
+	want.TUITheme = render.GetTUITheme().Name
+	if cfg.AutoApproveTools != want.AutoApproveTools ||
+		cfg.ShowTimestamps != want.ShowTimestamps ||
+		cfg.StoreThoughts != want.StoreThoughts ||
+		cfg.ToolPlanReview != want.ToolPlanReview ||
+		cfg.AttachmentNote != want.AttachmentNote ||
+		cfg.TUITheme != want.TUITheme ||
+		cfg.DefaultModel != want.DefaultModel ||
+		cfg.PromptPrefix != want.PromptPrefix {
+		t.Errorf("saved config = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestSettingsOverlay_CycleTheme(t *testing.T) {
+	m := openSettings(t, newSettingsTestModel(t))
+	before := render.GetTUITheme().Name
+
+	m.settingsCursor = len(chatSettings) - 1
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	after := render.GetTUITheme().Name
+	if after == before {
+		t.Fatalf("theme should change from %q", before)
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.TUITheme != after {
+		t.Errorf("saved theme = %q, want %q", cfg.TUITheme, after)
+	}
+}
+
+func TestSettingsOverlay_Close(t *testing.T) {
+	m := openSettings(t, newSettingsTestModel(t))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if updated.(Model).showingSettings {
+		t.Error("esc should close the settings overlay")
+	}
+}
+
+func TestHandleSaveConfigCommand(t *testing.T) {
+	m := newSettingsTestModel(t)
+	m.toolPlanReview = true
+
+	updated, _ := m.handleSaveConfigCommand("")
+	if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "✓") {
+		t.Errorf("unexpected feedback: %v", err)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if !cfg.ToolPlanReview || !cfg.ShowTimestamps {
+		t.Errorf("saved config = %+v, want tool plan review and timestamps enabled", cfg)
+	}
+}
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/config"
+)
+
+const snippetUsage = "usage: /snippet <name> [selection] | /snippet list | /snippet save <name> <text>"
+
+// handleSnippetCommand handles /snippet. "/snippet <name>" puts the named
+// snippet into the input for editing before it is sent; text after the name
+// fills its {{selection}} placeholder.
+func (m Model) handleSnippetCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	name, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rest = strings.TrimSpace(rest)
+	switch name {
+	case "":
+		m.err = fmt.Errorf("%s", snippetUsage)
+		return m, nil
+
+	case "list":
+		if rest != "" {
+			m.err = fmt.Errorf("usage: /snippet list")
+			return m, nil
+		}
+		m.err = fmt.Errorf("%s", m.snippetList())
+		return m, nil
+
+	case "save":
+		snippetName, text, _ := strings.Cut(rest, " ")
+		text = strings.TrimSpace(text)
+		if snippetName == "" || text == "" {
+			m.err = fmt.Errorf("usage: /snippet save <name> <text>")
+			return m, nil
+		}
+		if snippetName == "list" || snippetName == "save" {
+			m.err = fmt.Errorf("%q is reserved and cannot be used as a snippet name", snippetName)
+			return m, nil
+		}
+		if err := saveSnippet(snippetName, text); err != nil {
+			m.err = err
+			return m, nil
+		}
+		snippets := maps.Clone(m.snippets)
+		if snippets == nil {
+			snippets = make(map[string]string)
+		}
+		snippets[snippetName] = text
+		m.snippets = snippets
+		m.err = fmt.Errorf("✓ Saved snippet %q", snippetName)
+		return m, nil
+	}
+
+	text, ok := m.snippets[name]
+	if !ok {
+		m.err = fmt.Errorf("unknown snippet %q (see /snippet list)", name)
+		return m, nil
+	}
+	m.textarea.SetValue(expandSnippet(text, rest))
+	m.err = nil
+	return m, nil
+}
+
+// snippetList describes the saved snippets, sorted by name
+func (m Model) snippetList() string {
+	if len(m.snippets) == 0 {
+		return "No snippets saved (add one with /snippet save <name> <text>)"
+	}
+	names := slices.Sorted(maps.Keys(m.snippets))
+	return fmt.Sprintf("Snippets (%d): %s", len(names), strings.Join(names, ", "))
+}
+
+// expandSnippet fills a snippet's placeholders. {{clipboard}} is the
+// clipboard content and {{selection}} the text given after the snippet name;
+// the prompt template variables such as {{date}} are expanded too.
+// Placeholders without a value are left intact for the user to fill in.
+func expandSnippet(text, selection string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	// Template variables first, so clipboard content is inserted verbatim
+	text = expandPromptTemplate(text)
+	return promptVarPattern.ReplaceAllStringFunc(text, func(match string) string {
+		switch promptVarPattern.FindStringSubmatch(match)[1] {
+		case "clipboard":
+			if content, err := readClipboard(); err == nil {
+				return content
+			}
+		case "selection":
+			if selection != "" {
+				return selection
+			}
+		}
+		return match
+	})
+}
+
+// saveSnippet adds a snippet to the saved config
+func saveSnippet(name, text string) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Snippets == nil {
+		cfg.Snippets = make(map[string]string)
+	}
+	cfg.Snippets[name] = text
+	if err := config.SaveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
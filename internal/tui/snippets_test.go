@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/diogo/geminiweb/internal/config"
+)
+
+func stubClipboard(t *testing.T, content string, err error) {
+	t.Helper()
+	orig := readClipboard
+	readClipboard = func() (string, error) { return content, err }
+	t.Cleanup(func() { readClipboard = orig })
+}
+
+// testSnippets are the snippets configured in the snippet tests
+var testSnippets = map[string]string{
+	"review":  "Review this code:\n{{selection}}",
+	"explain": "Explain {{clipboard}} as of {{date}}",
+	"plain":   "Summarize the thread",
+}
+
+func TestSnippetCommand_InsertsWithPlaceholders(t *testing.T) {
+	stubClipboard(t, "the {{date}} error", nil)
+	orig := promptTemplateNow
+	promptTemplateNow = func() time.Time { return time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { promptTemplateNow = orig })
+
+	m := newTestModel(func(m *Model) { m.snippets = testSnippets })
+
+	updated, _ := m.handleSnippetCommand("explain")
+	m = updated.(Model)
+	if got := m.textarea.Value(); got != "Explain the {{date}} error as of 2024-03-01" {
+		t.Errorf("textarea = %q", got)
+	}
+	if m.err != nil {
+		t.Errorf("unexpected error: %v", m.err)
+	}
+
+	updated, _ = m.handleSnippetCommand("review func main() {}")
+	m = updated.(Model)
+	if got := m.textarea.Value(); got != "Review this code:\nfunc main() {}" {
+		t.Errorf("textarea = %q", got)
+	}
+
+	updated, _ = m.handleSnippetCommand("plain")
+	if got := updated.(Model).textarea.Value(); got != "Summarize the thread" {
+		t.Errorf("textarea = %q", got)
+	}
+}
+
+func TestSnippetCommand_UnfilledPlaceholdersKept(t *testing.T) {
+	stubClipboard(t, "", errors.New("no clipboard"))
+	m := newTestModel(func(m *Model) { m.snippets = testSnippets })
+
+	updated, _ := m.handleSnippetCommand("review")
+	if got := updated.(Model).textarea.Value(); got != "Review this code:\n{{selection}}" {
+		t.Errorf("textarea = %q", got)
+	}
+	if got := expandSnippet("Explain {{clipboard}}", ""); got != "Explain {{clipboard}}" {
+		t.Errorf("expandSnippet() = %q", got)
+	}
+}
+
+func TestSnippetCommand_List(t *testing.T) {
+	m := newTestModel(func(m *Model) { m.snippets = testSnippets })
+	updated, _ := m.handleSnippetCommand("list")
+	if err := updated.(Model).err; err == nil || err.Error() != "Snippets (3): explain, plain, review" {
+		t.Errorf("unexpected list: %v", err)
+	}
+
+	m.snippets = nil
+	updated, _ = m.handleSnippetCommand("list")
+	if err := updated.(Model).err; err == nil || err.Error() != "No snippets saved (add one with /snippet save <name> <text>)" {
+		t.Errorf("unexpected empty list: %v", err)
+	}
+}
+
+func TestSnippetCommand_Errors(t *testing.T) {
+	m := newTestModel(func(m *Model) { m.snippets = testSnippets })
+	for args, want := range map[string]string{
+		"":              snippetUsage,
+		"missing":       `unknown snippet "missing" (see /snippet list)`,
+		"list extra":    "usage: /snippet list",
+		"save onlyname": "usage: /snippet save <name> <text>",
+		"save list hi":  `"list" is reserved and cannot be used as a snippet name`,
+	} {
+		updated, _ := m.handleSnippetCommand(args)
+		if err := updated.(Model).err; err == nil || err.Error() != want {
+			t.Errorf("args %q: got %v, want %q", args, err, want)
+		}
+	}
+}
+
+func TestSnippetCommand_SavePersists(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m := newTestModel(func(m *Model) { m.snippets = testSnippets })
+	updated, _ := m.handleSnippetCommand("save standup What did I do {{date}}?")
+	m = updated.(Model)
+	if m.err == nil || m.err.Error() != `✓ Saved snippet "standup"` {
+		t.Fatalf("unexpected feedback: %v", m.err)
+	}
+	if m.snippets["standup"] != "What did I do {{date}}?" {
+		t.Errorf("snippet not available in the session: %v", m.snippets)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Snippets["standup"] != "What did I do {{date}}?" {
+		t.Errorf("snippet not persisted: %v", cfg.Snippets)
+	}
+	if path, _ := config.GetConfigPath(); filepath.Dir(filepath.Dir(path)) != home {
+		t.Errorf("config written outside the test home: %s", path)
+	}
+}
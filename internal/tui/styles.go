@@ -68,12 +68,30 @@ var (
 	// Thoughts panel style
 	thoughtsStyle lipgloss.Style
 
+	// Message timestamp style
+	timestampStyle lipgloss.Style
+
+	// Conversation diff styles
+	diffAddStyle    lipgloss.Style
+	diffRemoveStyle lipgloss.Style
+	diffChangeStyle lipgloss.Style
+
+	// Search match highlight in the message pager
+	pagerMatchStyle lipgloss.Style
+
 	// Image section styles
 	imageSectionStyle       lipgloss.Style
 	imageSectionHeaderStyle lipgloss.Style
 	imageLinkStyle          lipgloss.Style
 	imageTitleStyle         lipgloss.Style
 
+	// JSON syntax highlighting styles
+	jsonKeyStyle     lipgloss.Style
+	jsonStringStyle  lipgloss.Style
+	jsonNumberStyle  lipgloss.Style
+	jsonLiteralStyle lipgloss.Style
+	jsonPunctStyle   lipgloss.Style
+
 	// Input area panel
 	inputPanelStyle lipgloss.Style
 
@@ -87,6 +105,7 @@ var (
 	statusBarStyle  lipgloss.Style
 	statusKeyStyle  lipgloss.Style
 	statusDescStyle lipgloss.Style
+	statusWarnStyle lipgloss.Style
 
 	// Error style
 	errorStyle lipgloss.Style
@@ -126,6 +145,36 @@ var gradientColors = []lipgloss.Color{
 	lipgloss.Color("#1dd1a1"), // Green
 }
 
+// noEmoji replaces the emoji used in the UI with ASCII labels, for screen
+// readers and terminals without emoji fonts (config no_emoji)
+var noEmoji bool
+
+// SetNoEmoji enables or disables the no-emoji mode
+func SetNoEmoji(enabled bool) {
+	noEmoji = enabled
+}
+
+// icon returns emoji, or its ASCII label when the no-emoji mode is enabled
+func icon(emoji, label string) string {
+	if noEmoji {
+		return label
+	}
+	return emoji
+}
+
+// compactIndent is how far message text is indented in compact mode
+const compactIndent = 2
+
+// compactBubble strips the border, margins and padding from a message
+// bubble style, keeping its colors and indenting the text instead
+func compactBubble(style lipgloss.Style) lipgloss.Style {
+	return style.
+		UnsetBorderStyle().
+		UnsetMargins().
+		UnsetPadding().
+		PaddingLeft(compactIndent)
+}
+
 // init loads the default theme on package initialization
 func init() {
 	UpdateTheme()
@@ -234,6 +283,23 @@ func rebuildStyles() {
 		MarginLeft(1).
 		Italic(true)
 
+	// Message timestamp style
+	timestampStyle = lipgloss.NewStyle().
+		Foreground(colorTextMute)
+
+	// Conversation diff styles
+	diffAddStyle = lipgloss.NewStyle().
+		Foreground(colorSecondary)
+	diffRemoveStyle = lipgloss.NewStyle().
+		Foreground(colorError)
+	diffChangeStyle = lipgloss.NewStyle().
+		Foreground(colorAccent)
+
+	// Search match highlight in the message pager
+	pagerMatchStyle = lipgloss.NewStyle().
+		Foreground(colorSurface).
+		Background(colorAccent)
+
 	// Image section styles
 	imageSectionStyle = lipgloss.NewStyle().
 		MarginTop(1).
@@ -252,6 +318,23 @@ func rebuildStyles() {
 		Foreground(colorText).
 		Italic(true)
 
+	// JSON syntax highlighting styles
+	jsonKeyStyle = lipgloss.NewStyle().
+		Foreground(colorSecondary)
+
+	jsonStringStyle = lipgloss.NewStyle().
+		Foreground(colorAccent)
+
+	jsonNumberStyle = lipgloss.NewStyle().
+		Foreground(colorWarning)
+
+	jsonLiteralStyle = lipgloss.NewStyle().
+		Foreground(colorPrimary).
+		Bold(true)
+
+	jsonPunctStyle = lipgloss.NewStyle().
+		Foreground(colorTextDim)
+
 	// Input area panel
 	inputPanelStyle = lipgloss.NewStyle().
 		BorderStyle(lipgloss.RoundedBorder()).
@@ -282,6 +365,10 @@ func rebuildStyles() {
 	statusDescStyle = lipgloss.NewStyle().
 		Foreground(colorTextMute)
 
+	statusWarnStyle = lipgloss.NewStyle().
+		Foreground(colorWarning).
+		Bold(true)
+
 	// Error style
 	errorStyle = lipgloss.NewStyle().
 		Foreground(colorError).
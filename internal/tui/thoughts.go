@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+// thoughtsSession is implemented by chat sessions that can request or
+// suppress the model's thinking output
+type thoughtsSession interface {
+	SetIncludeThoughts(include *bool)
+}
+
+// handleThoughtsCommand handles the /thoughts [on|off] command. Off asks
+// supporting models not to think aloud, and thoughts are neither shown nor
+// saved; without an argument it toggles.
+func (m Model) handleThoughtsCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		m.thoughtsOff = !m.thoughtsOff
+	case "on":
+		m.thoughtsOff = false
+	case "off":
+		m.thoughtsOff = true
+	default:
+		m.err = fmt.Errorf("usage: /thoughts [on|off]")
+		return m, nil
+	}
+
+	include := !m.thoughtsOff
+	if session, ok := m.session.(thoughtsSession); ok {
+		session.SetIncludeThoughts(&include)
+	}
+
+	m.updateViewport()
+	state := "on"
+	if m.thoughtsOff {
+		state = "off"
+	}
+	note := ""
+	switch {
+	case m.session != nil && !m.session.GetModel().SupportsThoughts:
+		note = " (this model does not support requesting them)"
+	case !api.ThoughtsFieldEnabled():
+		note = " (display only; set " + api.ThoughtsFieldEnvVar + "=1 to also ask the model)"
+	}
+	m.err = fmt.Errorf("✓ Thoughts %s%s", state, note)
+	return m, nil
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/history"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+func newThoughtsTestModel(model models.Model) (Model, *api.ChatSession) {
+	session := (&api.MockGeminiClient{Model: model}).StartChat()
+	m := newTestModel(withSession(session), withViewport(96, 30), withMessages(
+		chatMessage{role: "user", content: "why?"},
+		chatMessage{role: "assistant", content: "Because.", thoughts: "Considering the question"},
+	))
+	m.updateViewport()
+	return m, session
+}
+
+func TestThoughtsCommand_FlipsSessionDefault(t *testing.T) {
+	m, session := newThoughtsTestModel(models.ModelThinking)
+
+	updated, _ := m.handleThoughtsCommand("off")
+	m = updated.(Model)
+	if !m.thoughtsOff {
+		t.Fatal("expected thoughts to be off")
+	}
+	if got := session.IncludeThoughts(); got == nil || *got {
+		t.Errorf("session IncludeThoughts = %v, want false", got)
+	}
+	if m.err == nil || !strings.HasPrefix(m.err.Error(), "✓ Thoughts off") {
+		t.Errorf("unexpected feedback: %v", m.err)
+	}
+
+	updated, _ = m.handleThoughtsCommand("")
+	m = updated.(Model)
+	if m.thoughtsOff {
+		t.Fatal("toggle should turn thoughts back on")
+	}
+	if got := session.IncludeThoughts(); got == nil || !*got {
+		t.Errorf("session IncludeThoughts = %v, want true", got)
+	}
+}
+
+func TestThoughtsCommand_Rendering(t *testing.T) {
+	m, _ := newThoughtsTestModel(models.ModelThinking)
+	if !strings.Contains(m.viewport.View(), "Considering the question") {
+		t.Fatal("thoughts should be rendered by default")
+	}
+
+	updated, _ := m.handleThoughtsCommand("off")
+	m = updated.(Model)
+	view := m.viewport.View()
+	if strings.Contains(view, "Considering the question") {
+		t.Error("thoughts should be hidden when off")
+	}
+	if !strings.Contains(view, "Because.") {
+		t.Error("the reply should still be rendered")
+	}
+}
+
+func TestThoughtsCommand_NotStoredWhenOff(t *testing.T) {
+	m, _ := newThoughtsTestModel(models.ModelThinking)
+	store := &mockHistoryStoreForModel{}
+	m.historyStore = store
+	m.conversation = &history.Conversation{ID: "conv-1"}
+
+	updated, _ := m.handleThoughtsCommand("off")
+	m = updated.(Model)
+
+	output := &models.ModelOutput{
+		Candidates: []models.Candidate{{Text: "Answer", Thoughts: "Hidden reasoning"}},
+	}
+	updated, _ = m.Update(responseMsg{output: output})
+	m = updated.(Model)
+
+	last := m.messages[len(m.messages)-1]
+	if last.content != "Answer" || last.thoughts != "" {
+		t.Errorf("unexpected message: %+v", last)
+	}
+	if len(store.addMessageCalls) != 1 || store.addMessageCalls[0].thoughts != "" {
+		t.Errorf("thoughts should not be stored, got %+v", store.addMessageCalls)
+	}
+}
+
+func TestThoughtsCommand_UnsupportedModelAndUsage(t *testing.T) {
+	m, _ := newThoughtsTestModel(models.ModelFast)
+
+	updated, _ := m.handleThoughtsCommand("off")
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.err.Error(), "does not support") {
+		t.Errorf("expected a note about the model, got %v", m.err)
+	}
+
+	updated, _ = m.handleThoughtsCommand("maybe")
+	m = updated.(Model)
+	if m.err == nil || m.err.Error() != "usage: /thoughts [on|off]" {
+		t.Errorf("expected usage error, got %v", m.err)
+	}
+}
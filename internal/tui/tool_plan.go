@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// startToolPlanReview opens the plan overlay for a batch of tool calls.
+// All calls start selected; nothing runs until the plan is confirmed.
+func (m *Model) startToolPlanReview(calls []toolexec.ToolCall) {
+	m.reviewingPlan = true
+	m.planCalls = calls
+	m.planSelected = make([]bool, len(calls))
+	for i := range m.planSelected {
+		m.planSelected[i] = true
+	}
+	m.planCursor = 0
+	m.loading = false
+}
+
+// shouldReviewToolPlan reports whether a batch of tool calls should be
+// reviewed as a plan instead of confirmed one at a time.
+func (m Model) shouldReviewToolPlan(calls []toolexec.ToolCall) bool {
+	return m.toolPlanReview && !m.autoApproveTools && len(calls) > 1
+}
+
+// finishToolPlanReview closes the overlay and queues the plan's calls.
+// Selected calls run without further confirmation; the others are denied.
+func (m Model) finishToolPlanReview(approve bool) (tea.Model, tea.Cmd) {
+	decisions := make([]bool, len(m.planCalls))
+	if approve {
+		copy(decisions, m.planSelected)
+	}
+
+	m.pendingToolCalls = m.planCalls
+	m.planDecisions = decisions
+	m.toolResults = nil
+
+	m.reviewingPlan = false
+	m.planCalls = nil
+	m.planSelected = nil
+	m.planCursor = 0
+
+	cmd := m.startNextToolCall()
+	if cmd != nil && m.loading {
+		return m, tea.Batch(cmd, animationTick())
+	}
+	return m, cmd
+}
+
+// updateToolPlan handles input while reviewing a tool plan
+func (m Model) updateToolPlan(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m.handleInterrupt()
+
+		case "up", "k":
+			if len(m.planCalls) > 0 {
+				m.planCursor--
+				if m.planCursor < 0 {
+					m.planCursor = len(m.planCalls) - 1
+				}
+			}
+
+		case "down", "j":
+			if len(m.planCalls) > 0 {
+				m.planCursor++
+				if m.planCursor >= len(m.planCalls) {
+					m.planCursor = 0
+				}
+			}
+
+		case " ", "x":
+			if m.planCursor < len(m.planSelected) {
+				m.planSelected[m.planCursor] = !m.planSelected[m.planCursor]
+			}
+
+		case "a":
+			// Select all, or clear all if everything is already selected
+			all := true
+			for _, selected := range m.planSelected {
+				all = all && selected
+			}
+			for i := range m.planSelected {
+				m.planSelected[i] = !all
+			}
+
+		case "enter", "y", "Y":
+			return m.finishToolPlanReview(true)
+
+		case "n", "N", "esc":
+			return m.finishToolPlanReview(false)
+		}
+	}
+
+	return m, nil
+}
+
+// renderToolPlan renders the plan review overlay
+func (m Model) renderToolPlan() string {
+	width := m.width - 8
+	if width < 40 {
+		width = 40
+	}
+
+	selected := 0
+	for _, s := range m.planSelected {
+		if s {
+			selected++
+		}
+	}
+
+	var content strings.Builder
+	content.WriteString(titleStyle.Render(fmt.Sprintf("Tool execution plan (%d calls)", len(m.planCalls))))
+	content.WriteString("\n\n")
+
+	for i, call := range m.planCalls {
+		cursor := "  "
+		if i == m.planCursor {
+			cursor = configCursorStyle.Render("> ")
+		}
+
+		check := configDisabledStyle.Render("[ ]")
+		if i < len(m.planSelected) && m.planSelected[i] {
+			check = configEnabledStyle.Render("[x]")
+		}
+
+		line := fmt.Sprintf("%d. %s", i+1, call.Name)
+		if i == m.planCursor {
+			line = configCursorStyle.Bold(true).Render(line)
+		}
+		content.WriteString(cursor + check + " " + line)
+
+		if call.Reason != "" {
+			content.WriteString(hintStyle.Render(" - " + call.Reason))
+		}
+		content.WriteString("\n")
+
+		if len(call.Args) > 0 {
+			if data, err := json.Marshal(call.Args); err == nil {
+				args := string(data)
+				if maxLen := width - 12; maxLen > 3 && len(args) > maxLen {
+					args = args[:maxLen-3] + "..."
+				}
+				content.WriteString("       " + configValueStyle.Render(args) + "\n")
+			}
+		}
+	}
+
+	content.WriteString("\n")
+	content.WriteString(fmt.Sprintf("%d of %d selected\n", selected, len(m.planCalls)))
+	content.WriteString(hintStyle.Render("space: toggle • a: all • enter/y: run selected • n/esc: deny all"))
+
+	panel := messagesAreaStyle.Width(width).Render(content.String())
+	if m.width > 0 && m.height > 0 {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, panel)
+	}
+	return panel
+}
+
+// handlePlanCommand handles the /plan [on|off] command, which toggles
+// reviewing multiple tool calls together as a plan.
+func (m Model) handlePlanCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		m.toolPlanReview = !m.toolPlanReview
+	case "on":
+		m.toolPlanReview = true
+	case "off":
+		m.toolPlanReview = false
+	default:
+		m.err = fmt.Errorf("usage: /plan [on|off]")
+		return m, nil
+	}
+
+	if m.toolPlanReview {
+		m.err = fmt.Errorf("✓ Tool plan review enabled")
+	} else {
+		m.err = fmt.Errorf("✓ Tool plan review disabled")
+	}
+	return m, nil
+}
@@ -0,0 +1,281 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// recordingTool is a tool that records its executions.
+type recordingTool struct {
+	name     string
+	executed *[]string
+}
+
+func (t *recordingTool) Name() string        { return t.name }
+func (t *recordingTool) Description() string { return "records executions" }
+func (t *recordingTool) RequiresConfirmation(args map[string]any) bool {
+	return true
+}
+func (t *recordingTool) Execute(ctx context.Context, input *toolexec.Input) (*toolexec.Output, error) {
+	*t.executed = append(*t.executed, t.name)
+	return toolexec.NewOutput().WithMessage(t.name + " done"), nil
+}
+
+func newToolPlanTestModel(t *testing.T, executed *[]string, sent *string) Model {
+	t.Helper()
+	var tools []toolexec.Tool
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		tools = append(tools, &recordingTool{name: name, executed: executed})
+	}
+
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			*sent = prompt
+			return &models.ModelOutput{Candidates: []models.Candidate{{Text: "ok"}}}, nil
+		},
+	}
+
+	return newTestModel(withSession(session), withTools(t, tools...), func(m *Model) { m.toolPlanReview = true })
+}
+
+func toolCallsResponse() responseMsg {
+	text := "I'll run three tools.\n" +
+		"```tool\n{\"name\": \"alpha\", \"args\": {\"n\": 1}, \"reason\": \"first step\"}\n```\n" +
+		"```tool\n{\"name\": \"beta\", \"args\": {\"n\": 2}}\n```\n" +
+		"```tool\n{\"name\": \"gamma\", \"args\": {\"n\": 3}}\n```"
+	return responseMsg{output: &models.ModelOutput{Candidates: []models.Candidate{{Text: text}}}}
+}
+
+// runToolCommands drives tool execution messages through Update until the
+// follow-up message is sent back to the model.
+func runToolCommands(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	for i := 0; cmd != nil && i < 10; i++ {
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			// The first command carries the tool work; the rest are animation ticks
+			msg = batch[0]()
+		}
+		switch msg.(type) {
+		case toolExecutionMsg:
+			var model tea.Model
+			model, cmd = m.Update(msg)
+			m = model.(Model)
+		default:
+			return m
+		}
+	}
+	return m
+}
+
+func TestToolPlan_ListsAllCalls(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+
+	updated, cmd := m.Update(toolCallsResponse())
+	m = updated.(Model)
+
+	if !m.reviewingPlan {
+		t.Fatal("expected plan review overlay for multiple tool calls")
+	}
+	if len(m.planCalls) != 3 {
+		t.Fatalf("plan has %d calls, want 3", len(m.planCalls))
+	}
+	if cmd != nil {
+		if _, ok := cmd().(toolExecutionMsg); ok {
+			t.Error("no tool should run before the plan is confirmed")
+		}
+	}
+
+	view := m.View()
+	for _, want := range []string{"Tool execution plan (3 calls)", "1. alpha", "2. beta", "3. gamma", "first step", "3 of 3 selected"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("plan view missing %q", want)
+		}
+	}
+}
+
+func TestToolPlan_RespectsToggles(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+
+	updated, _ := m.Update(toolCallsResponse())
+	m = updated.(Model)
+
+	// Deselect the second call
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	m = updated.(Model)
+
+	if m.planSelected[1] {
+		t.Fatal("second call should be deselected")
+	}
+	if !strings.Contains(m.View(), "2 of 3 selected") {
+		t.Error("view should reflect the toggle")
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.reviewingPlan {
+		t.Fatal("overlay should close after confirming")
+	}
+
+	m = runToolCommands(t, m, cmd)
+
+	if strings.Join(executed, ",") != "alpha,gamma" {
+		t.Errorf("executed = %v, want [alpha gamma]", executed)
+	}
+	if m.confirmingTool {
+		t.Error("approved plan calls should not ask for individual confirmation")
+	}
+
+	var denied int
+	for _, msg := range m.messages {
+		if msg.role == "tool" && strings.Contains(msg.content, "Tool: beta") {
+			denied++
+			if !strings.Contains(msg.content, "denied") {
+				t.Errorf("beta should be reported as denied, got:\n%s", msg.content)
+			}
+		}
+	}
+	if denied != 1 {
+		t.Errorf("expected one tool message for beta, got %d", denied)
+	}
+}
+
+func TestToolPlan_DenyAll(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+
+	updated, _ := m.Update(toolCallsResponse())
+	m = updated.(Model)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	m = runToolCommands(t, m, cmd)
+
+	if len(executed) != 0 {
+		t.Errorf("no tools should run when the plan is denied, got %v", executed)
+	}
+	if len(m.pendingToolCalls) != 0 || len(m.planDecisions) != 0 {
+		t.Error("plan state should be drained")
+	}
+}
+
+func TestToolPlan_DisabledUsesPerCallConfirmation(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+	m.toolPlanReview = false
+
+	updated, _ := m.Update(toolCallsResponse())
+	m = updated.(Model)
+
+	if m.reviewingPlan {
+		t.Error("plan review should not open when disabled")
+	}
+	if !m.confirmingTool || m.toolConfirmCall == nil || m.toolConfirmCall.Name != "alpha" {
+		t.Error("expected individual confirmation for the first call")
+	}
+}
+
+func TestModel_PlanCommand(t *testing.T) {
+	m := Model{textarea: textarea.New()}
+
+	updated, _ := m.handlePlanCommand("on")
+	m = updated.(Model)
+	if !m.toolPlanReview {
+		t.Error("/plan on should enable plan review")
+	}
+
+	updated, _ = m.handlePlanCommand("")
+	m = updated.(Model)
+	if m.toolPlanReview {
+		t.Error("/plan should toggle plan review off")
+	}
+
+	updated, _ = m.handlePlanCommand("maybe")
+	m = updated.(Model)
+	if m.err == nil || !strings.Contains(m.err.Error(), "usage") {
+		t.Errorf("expected usage error, got %v", m.err)
+	}
+}
+
+func TestStartNextToolCall_TrustedTools(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+	m.toolPlanReview = false
+	m.trustedTools = trustedToolSet([]string{"alpha", " "})
+
+	m.pendingToolCalls = []toolexec.ToolCall{{Name: "alpha"}, {Name: "beta"}}
+
+	cmd := m.startNextToolCall()
+	if m.confirmingTool {
+		t.Fatal("trusted tool should not enter confirmation mode")
+	}
+	m = runToolCommands(t, m, cmd)
+
+	if strings.Join(executed, ",") != "alpha" {
+		t.Errorf("executed = %v, want [alpha]", executed)
+	}
+	if !m.confirmingTool || m.toolConfirmCall == nil || m.toolConfirmCall.Name != "beta" {
+		t.Error("untrusted tool should still prompt for confirmation")
+	}
+}
+
+func TestMaxToolCallsPerTurn(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+	m.toolPlanReview = false
+	m.autoApproveTools = true
+	m.maxToolCalls = 2
+
+	updated, cmd := m.Update(toolCallsResponse())
+	m = updated.(Model)
+	m = runToolCommands(t, m, cmd)
+
+	if strings.Join(executed, ",") != "alpha,beta" {
+		t.Errorf("executed = %v, want [alpha beta]", executed)
+	}
+
+	var notice string
+	for _, msg := range m.messages {
+		if msg.role == "tool" && strings.HasPrefix(msg.content, "Skipped") {
+			notice = msg.content
+		}
+	}
+	if !strings.Contains(notice, "Skipped 1 tool call(s) over the limit of 2 per turn: gamma") {
+		t.Errorf("expected skipped-call notice, got %q", notice)
+	}
+	if !strings.Contains(m.viewport.View(), "Skipped 1 tool call(s)") {
+		t.Error("notice should be shown in the conversation")
+	}
+}
+
+func TestMaxToolCallsPerTurn_CapsPlan(t *testing.T) {
+	var executed []string
+	var sent string
+	m := newToolPlanTestModel(t, &executed, &sent)
+	m.maxToolCalls = 2
+
+	updated, _ := m.Update(toolCallsResponse())
+	m = updated.(Model)
+
+	if !m.reviewingPlan || len(m.planCalls) != 2 {
+		t.Fatalf("plan should list only the first 2 calls, got %d", len(m.planCalls))
+	}
+}
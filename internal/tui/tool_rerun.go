@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleRerunToolCommand handles the /rerun-tool command (Alt+R), which
+// runs the last executed tool call again and appends the fresh result.
+// The result is only shown; it is not sent back to the model.
+func (m Model) handleRerunToolCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /rerun-tool")
+		return m, nil
+	}
+	if m.lastToolCall == nil {
+		m.err = fmt.Errorf("no tool call to re-run")
+		return m, nil
+	}
+	if m.loading || len(m.pendingToolCalls) > 0 {
+		m.err = fmt.Errorf("wait for the current response to finish before re-running a tool")
+		return m, nil
+	}
+
+	call := *m.lastToolCall
+	if m.toolRegistry == nil || m.toolExecutor == nil {
+		m.err = fmt.Errorf("cannot re-run %s: tool executor not configured", call.Name)
+		return m, nil
+	}
+	tool, err := m.toolRegistry.Get(call.Name)
+	if err != nil {
+		m.err = fmt.Errorf("cannot re-run %s: %w", call.Name, err)
+		return m, nil
+	}
+
+	m.err = nil
+	m.rerunningTool = true
+	if !m.trustedTools[call.Name] && tool.RequiresConfirmation(call.Args) && !m.autoApproveTools {
+		m.confirmingTool = true
+		m.toolConfirmCall = &call
+		return m, nil
+	}
+
+	m.loading = true
+	m.animationFrame = 0
+	return m, tea.Batch(m.executeToolCall(call), animationTick())
+}
@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/models"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+func newRerunTestModel(t *testing.T) Model {
+	t.Helper()
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			t.Errorf("a re-run should not be sent to the model, got %q", prompt)
+			return &models.ModelOutput{}, nil
+		},
+	}
+	return newTestModel(withSession(session), func(m *Model) {
+		m.toolRegistry = defaultToolRegistry(config.DefaultConfig())
+		m.toolExecutor = defaultToolExecutor(m.toolRegistry)
+	})
+}
+
+func TestRerunTool_TracksLastCall(t *testing.T) {
+	m := newRerunTestModel(t)
+
+	call := toolexec.ToolCall{Name: "calc", Args: map[string]any{"expression": "1 + 2"}}
+	m.handleToolResult(call, toolexec.NewResult("calc", toolexec.NewOutput().WithData([]byte("3")), nil))
+	if m.lastToolCall == nil || m.lastToolCall.Args["expression"] != "1 + 2" {
+		t.Fatalf("lastToolCall = %+v, want the executed call", m.lastToolCall)
+	}
+
+	denied := toolexec.ToolCall{Name: "bash", Args: map[string]any{"command": "rm -rf build"}}
+	m.handleToolResult(denied, toolexec.NewErrorResult("bash", toolexec.NewUserDeniedError("bash")))
+	if m.lastToolCall.Name != "calc" {
+		t.Errorf("a denied call was never executed and should not replace the last call, got %s", m.lastToolCall.Name)
+	}
+}
+
+func TestRerunTool_AppendsFreshResult(t *testing.T) {
+	m := newRerunTestModel(t)
+	m.lastToolCall = &toolexec.ToolCall{Name: "calc", Args: map[string]any{"expression": "6 * 7"}}
+
+	result, cmd := m.handleRerunToolCommand("")
+	m = result.(Model)
+	if cmd == nil || !m.loading || !m.rerunningTool {
+		t.Fatalf("rerun should start the tool: cmd=%v loading=%v rerunning=%v", cmd != nil, m.loading, m.rerunningTool)
+	}
+
+	var exec toolExecutionMsg
+	for _, c := range cmd().(tea.BatchMsg) {
+		if msg, ok := c().(toolExecutionMsg); ok {
+			exec = msg
+			break
+		}
+	}
+	if exec.result == nil || exec.call.Name != "calc" {
+		t.Fatalf("rerun should execute the last call, got %+v", exec)
+	}
+
+	if next := m.handleToolResult(exec.call, exec.result); next != nil {
+		t.Error("a re-run result should not start another model round-trip")
+	}
+	if len(m.messages) != 1 || m.messages[0].role != "tool" || !strings.Contains(m.messages[0].content, "42") {
+		t.Fatalf("rerun should append a fresh tool message, got %+v", m.messages)
+	}
+	if m.loading || m.rerunningTool || len(m.toolResults) != 0 {
+		t.Errorf("rerun state not cleared: loading=%v rerunning=%v results=%d", m.loading, m.rerunningTool, len(m.toolResults))
+	}
+}
+
+func TestRerunTool_Confirmation(t *testing.T) {
+	m := newRerunTestModel(t)
+	m.lastToolCall = &toolexec.ToolCall{Name: "bash", Args: map[string]any{"command": "echo hi"}}
+
+	result, cmd := m.handleRerunToolCommand("")
+	m = result.(Model)
+	if cmd != nil || !m.confirmingTool || m.toolConfirmCall == nil || m.toolConfirmCall.Name != "bash" {
+		t.Fatalf("a tool that requires confirmation should prompt first: confirming=%v", m.confirmingTool)
+	}
+
+	result, cmd = m.updateToolConfirmation(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = result.(Model)
+	msg := cmd().(toolExecutionMsg)
+	m.handleToolResult(msg.call, msg.result)
+	if len(m.messages) != 1 || m.rerunningTool {
+		t.Errorf("denying a rerun should append the denial and end the rerun, got %+v", m.messages)
+	}
+	if m.lastToolCall.Name != "bash" {
+		t.Errorf("lastToolCall = %+v, want it kept after a denied rerun", m.lastToolCall)
+	}
+}
+
+func TestRerunTool_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *Model)
+		args  string
+		want  string
+	}{
+		{name: "no previous call", want: "no tool call"},
+		{name: "extra args", args: "now", want: "usage: /rerun-tool"},
+		{
+			name: "busy",
+			setup: func(m *Model) {
+				m.lastToolCall = &toolexec.ToolCall{Name: "calc"}
+				m.loading = true
+			},
+			want: "wait for the current response",
+		},
+		{
+			name: "unknown tool",
+			setup: func(m *Model) {
+				m.lastToolCall = &toolexec.ToolCall{Name: "missing"}
+			},
+			want: "cannot re-run missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRerunTestModel(t)
+			if tt.setup != nil {
+				tt.setup(&m)
+			}
+			result, cmd := m.handleRerunToolCommand(tt.args)
+			m = result.(Model)
+			if cmd != nil || m.err == nil || !strings.Contains(m.err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", m.err, tt.want)
+			}
+			if m.rerunningTool {
+				t.Error("a rejected rerun should not be marked as running")
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// sendToolResults runs two tool results through handleToolResult and
+// returns the prompt re-sent to the model
+func sendToolResults(t *testing.T, structured bool) string {
+	t.Helper()
+	var sent string
+	session := &mockChatSession{
+		sendMessageFunc: func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+			sent = prompt
+			return &models.ModelOutput{}, nil
+		},
+	}
+	m := &Model{viewport: viewport.New(80, 20), session: session, jsonToolResults: structured}
+
+	lsCall := toolexec.ToolCall{Name: "bash", Args: map[string]any{"command": "ls"}}
+	lsOutput := toolexec.NewOutput().WithData([]byte("a.txt\nb.txt\n"))
+	lsOutput.Metadata[toolexec.ExitCodeKey] = "0"
+	readCall := toolexec.ToolCall{Name: "file_read", Args: map[string]any{"path": "missing.txt"}}
+
+	// The first result of the turn is already collected
+	m.pendingToolCalls = []toolexec.ToolCall{readCall}
+	_ = m.handleToolResult(lsCall, toolexec.NewResult("bash", lsOutput, nil))
+	m.pendingToolCalls = nil
+
+	cmd := m.handleToolResult(readCall, toolexec.NewErrorResult("file_read", errors.New("file not found")))
+	if cmd == nil {
+		t.Fatal("the last result should re-send the results")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("expected a batch command")
+	}
+	batch[0]()
+	if len(m.toolResults) != 0 {
+		t.Error("results should be cleared once sent")
+	}
+	return sent
+}
+
+func TestToolResults_StructuredPayload(t *testing.T) {
+	sent := sendToolResults(t, true)
+
+	body, ok := strings.CutPrefix(sent, "```results\n")
+	if !ok || !strings.HasSuffix(body, "\n```") {
+		t.Fatalf("payload should be one results block:\n%s", sent)
+	}
+	var results []map[string]any
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(body, "\n```")), &results); err != nil {
+		t.Fatalf("payload is not a JSON array: %v\n%s", err, sent)
+	}
+	if len(results) != 2 {
+		t.Fatalf("results = %v, want 2", results)
+	}
+
+	ls, read := results[0], results[1]
+	if ls["tool_name"] != "bash" || ls["output"] != "a.txt\nb.txt\n" || ls["exit_code"] != float64(0) || ls["success"] != true {
+		t.Errorf("first result = %v", ls)
+	}
+	if args, _ := ls["args"].(map[string]any); args["command"] != "ls" {
+		t.Errorf("first result args = %v", ls["args"])
+	}
+	if read["tool_name"] != "file_read" || read["success"] != false || !strings.Contains(read["error"].(string), "file not found") {
+		t.Errorf("second result = %v", read)
+	}
+	if args, _ := read["args"].(map[string]any); args["path"] != "missing.txt" {
+		t.Errorf("second result args = %v", read["args"])
+	}
+	if _, ok := read["exit_code"]; ok {
+		t.Errorf("a result without an exit code should omit it: %v", read)
+	}
+}
+
+func TestToolResults_TextPayloadUnchanged(t *testing.T) {
+	sent := sendToolResults(t, false)
+
+	blocks := strings.Split(sent, "\n```\n")
+	if len(blocks) != 2 || !strings.HasPrefix(blocks[0], "```result\n") || !strings.HasPrefix(blocks[1], "```result\n") {
+		t.Fatalf("text mode should send one result block per call:\n%s", sent)
+	}
+	if strings.Contains(sent, `"args"`) || strings.Contains(sent, `"exit_code"`) {
+		t.Errorf("text mode should not include the structured fields:\n%s", sent)
+	}
+	want := `{"tool_name":"bash","success":true,"output":"a.txt\nb.txt\n"}`
+	if !strings.Contains(sent, want) {
+		t.Errorf("text payload = %s, want it to contain %s", sent, want)
+	}
+}
+
+func TestExportFromMemory_ToolInteractions(t *testing.T) {
+	m := &Model{viewport: viewport.New(80, 20)}
+	m.messages = []chatMessage{{role: "user", content: "list files"}}
+
+	call := toolexec.ToolCall{Name: "bash", Args: map[string]any{"command": "ls"}}
+	result := toolexec.NewResult("bash", toolexec.NewOutput().WithData([]byte("a.txt\n")), nil)
+	result.Duration = 1500 * time.Millisecond
+	_ = m.handleToolResult(call, result)
+	failed := toolexec.ToolCall{Name: "file_read", Args: map[string]any{"path": "missing.txt"}}
+	_ = m.handleToolResult(failed, toolexec.NewErrorResult("file_read", errors.New("file not found")))
+
+	path := filepath.Join(t.TempDir(), "chat.json")
+	if msg := exportFromMemory(m.messages, "Tools", "json", path, "")().(exportResultMsg); msg.err != nil {
+		t.Fatalf("export error: %v", msg.err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var export struct {
+		Messages []struct {
+			Role     string         `json:"role"`
+			ToolCall map[string]any `json:"tool_call"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &export); err != nil {
+		t.Fatalf("export is not valid JSON: %v\n%s", err, data)
+	}
+	if len(export.Messages) != 3 {
+		t.Fatalf("got %d messages, want 3:\n%s", len(export.Messages), data)
+	}
+	if export.Messages[0].ToolCall != nil {
+		t.Errorf("user message should omit tool_call: %v", export.Messages[0].ToolCall)
+	}
+
+	ls := export.Messages[1].ToolCall
+	if export.Messages[1].Role != "tool" || ls == nil {
+		t.Fatalf("second message should be a tool message with tool_call:\n%s", data)
+	}
+	if ls["tool_name"] != "bash" || ls["success"] != true || ls["output"] != "a.txt\n" || ls["execution_time_ms"] != float64(1500) {
+		t.Errorf("bash tool_call = %v", ls)
+	}
+	if args, _ := ls["args"].(map[string]any); args["command"] != "ls" {
+		t.Errorf("bash tool_call args = %v", ls["args"])
+	}
+
+	read := export.Messages[2].ToolCall
+	if read == nil || read["success"] != false || !strings.Contains(fmt.Sprint(read["error"]), "file not found") {
+		t.Errorf("file_read tool_call = %v", read)
+	}
+}
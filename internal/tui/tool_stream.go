@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// toolOutputChunkMsg carries output produced by a running streaming tool.
+// stream delivers the next chunk or, finally, the toolExecutionMsg.
+type toolOutputChunkMsg struct {
+	call   toolexec.ToolCall
+	chunk  []byte
+	stream <-chan tea.Msg
+}
+
+// toolStreamState tracks the tool message that streamed output is
+// appended to
+type toolStreamState struct {
+	index  int    // Position of the tool message in m.messages
+	header string // Tool name, reason and args
+	output string // Last toolexec.DefaultMaxOutputSize bytes of output
+	elided bool   // Whether earlier output was dropped to stay in the cap
+}
+
+// isStreamingTool reports whether the named tool can stream its output
+func (m Model) isStreamingTool(name string) bool {
+	if m.toolRegistry == nil {
+		return false
+	}
+	tool, err := m.toolRegistry.Get(name)
+	if err != nil {
+		return false
+	}
+	_, ok := tool.(toolexec.StreamingTool)
+	return ok
+}
+
+// executeToolCallStreaming runs a streaming tool, delivering each output
+// chunk as a toolOutputChunkMsg followed by the final toolExecutionMsg.
+func (m Model) executeToolCallStreaming(call toolexec.ToolCall) tea.Cmd {
+	executor := m.toolExecutor
+	jobs := m.jobs
+
+	return func() tea.Msg {
+		stream := make(chan tea.Msg, 64)
+		go func() {
+			defer close(stream)
+			ctx, done := jobs.start(jobLabel("Tool", call.Name))
+			defer done()
+			ctx = toolexec.WithOutputStream(ctx, func(chunk []byte) {
+				stream <- toolOutputChunkMsg{call: call, chunk: chunk, stream: stream}
+			})
+
+			start := time.Now()
+			output, err := executor.Execute(ctx, call.Name, call.ToInput())
+			end := time.Now()
+
+			result := toolexec.NewResult(call.Name, output, err).WithTiming(start, end)
+			stream <- toolExecutionMsg{call: call, result: result}
+		}()
+		return <-stream
+	}
+}
+
+// waitForToolOutput returns a command that waits for the next message from
+// a streaming tool
+func waitForToolOutput(stream <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-stream
+	}
+}
+
+// handleToolOutputChunk appends streamed output to the running tool's
+// message, creating the message on the first chunk, and waits for more.
+// The viewport is re-rendered by the caller with requestViewportUpdate.
+func (m *Model) handleToolOutputChunk(msg toolOutputChunkMsg) tea.Cmd {
+	if m.activeToolStream() == nil {
+		header := formatToolMessage(msg.call, nil)
+		m.messages = append(m.messages, chatMessage{
+			role:      "tool",
+			content:   header,
+			streaming: true,
+			timestamp: time.Now(),
+		})
+		m.toolStream = &toolStreamState{index: len(m.messages) - 1, header: header}
+	}
+
+	m.toolStream.append(msg.chunk)
+	output := strings.TrimRight(m.toolStream.output, "\n")
+	if m.toolStream.elided {
+		output = "…\n" + output
+	}
+	m.messages[m.toolStream.index].content = m.toolStream.header + "\nOutput:\n" + output
+
+	return waitForToolOutput(msg.stream)
+}
+
+// append adds a chunk to the streamed output, keeping only the last
+// toolexec.DefaultMaxOutputSize bytes as the final result does with
+// toolexec.TruncateTail. The cut is moved forward to a rune boundary.
+func (s *toolStreamState) append(chunk []byte) {
+	s.output += string(chunk)
+	if len(s.output) <= toolexec.DefaultMaxOutputSize {
+		return
+	}
+	cut := len(s.output) - toolexec.DefaultMaxOutputSize
+	for cut < len(s.output) && !utf8.RuneStart(s.output[cut]) {
+		cut++
+	}
+	s.output = s.output[cut:]
+	s.elided = true
+}
+
+// activeToolStream returns the tool stream state if its message is still
+// in the conversation
+func (m *Model) activeToolStream() *toolStreamState {
+	s := m.toolStream
+	if s == nil || s.index >= len(m.messages) || !m.messages[s.index].streaming {
+		m.toolStream = nil
+		return nil
+	}
+	return s
+}
+
+// finishToolStream replaces the streamed tool message with the final tool
+// message, its duration and the structured call. It reports false if no
+// output was streamed.
+func (m *Model) finishToolStream(toolMessage string, result *toolexec.Result, structured *toolexec.ToolCallResult) bool {
+	s := m.activeToolStream()
+	if s == nil {
+		return false
+	}
+	m.toolStream = nil
+
+	msg := &m.messages[s.index]
+	msg.content = toolMessage
+	msg.format = toolOutputFormat(result)
+	msg.streaming = false
+	msg.duration = result.Duration
+	msg.toolCall = structured
+	return true
+}
+
+// toolStatusLabel describes a tool message's run state for its label
+func toolStatusLabel(msg chatMessage) string {
+	switch {
+	case msg.streaming:
+		return " running…"
+	case msg.duration > 0:
+		return fmt.Sprintf(" · %s", formatToolDuration(msg.duration))
+	default:
+		return ""
+	}
+}
+
+// formatToolDuration formats a tool run time for display
+func formatToolDuration(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// chunkedTool is a streaming tool that emits each of its chunks in turn
+type chunkedTool struct {
+	chunks []string
+}
+
+func (t *chunkedTool) Name() string                                  { return "chunked" }
+func (t *chunkedTool) Description() string                           { return "emits output in chunks" }
+func (t *chunkedTool) RequiresConfirmation(args map[string]any) bool { return false }
+func (t *chunkedTool) Execute(ctx context.Context, input *toolexec.Input) (*toolexec.Output, error) {
+	return t.ExecuteStream(ctx, input, nil)
+}
+func (t *chunkedTool) ExecuteStream(ctx context.Context, input *toolexec.Input, emit func(chunk []byte)) (*toolexec.Output, error) {
+	var all strings.Builder
+	for _, chunk := range t.chunks {
+		if emit != nil {
+			emit([]byte(chunk))
+		}
+		all.WriteString(chunk)
+	}
+	return toolexec.NewOutput().WithData([]byte(all.String())), nil
+}
+
+func TestToolOutputStreaming_GrowsAndFinalizes(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{}), func(m *Model) { m.streamToolOutput = true })
+	call := toolexec.ToolCall{Name: "chunked", Args: map[string]any{"n": 2}}
+	stream := make(chan tea.Msg)
+
+	updated, cmd := m.Update(toolOutputChunkMsg{call: call, chunk: []byte("line 1\n"), stream: stream})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected a command waiting for more output")
+	}
+	if len(m.messages) != 1 || !m.messages[0].streaming {
+		t.Fatalf("expected one streaming tool message, got %+v", m.messages)
+	}
+	if !strings.Contains(m.messages[0].content, "Tool: chunked") || !strings.HasSuffix(m.messages[0].content, "Output:\nline 1") {
+		t.Errorf("unexpected content after first chunk: %q", m.messages[0].content)
+	}
+	if view := ansiPattern.ReplaceAllString(m.viewport.View(), ""); !strings.Contains(view, "running") {
+		t.Errorf("running tool should be marked in its label:\n%s", view)
+	}
+
+	updated, _ = m.Update(toolOutputChunkMsg{call: call, chunk: []byte("line 2\n"), stream: stream})
+	m = updated.(Model)
+	if len(m.messages) != 1 {
+		t.Fatalf("chunks should update the message in place, got %d messages", len(m.messages))
+	}
+	if !strings.HasSuffix(m.messages[0].content, "Output:\nline 1\nline 2") {
+		t.Errorf("unexpected content after second chunk: %q", m.messages[0].content)
+	}
+
+	start := time.Now()
+	output := toolexec.NewOutput().WithData([]byte("line 1\nline 2\n"))
+	result := toolexec.NewResult(call.Name, output, nil).WithTiming(start, start.Add(1500*time.Millisecond))
+	updated, _ = m.Update(toolExecutionMsg{call: call, result: result})
+	m = updated.(Model)
+
+	if len(m.messages) != 1 {
+		t.Fatalf("final result should replace the streamed message, got %d messages", len(m.messages))
+	}
+	final := m.messages[0]
+	if final.streaming {
+		t.Error("message should no longer be streaming")
+	}
+	if final.content != formatToolMessage(call, result) {
+		t.Errorf("final content = %q, want %q", final.content, formatToolMessage(call, result))
+	}
+	if final.duration != 1500*time.Millisecond {
+		t.Errorf("duration = %v, want 1.5s", final.duration)
+	}
+	if m.toolStream != nil {
+		t.Error("tool stream state should be cleared")
+	}
+	view := ansiPattern.ReplaceAllString(m.viewport.View(), "")
+	if !strings.Contains(view, "Tool · 1.5s") || strings.Contains(view, "running") {
+		t.Errorf("finished tool label should show its duration:\n%s", view)
+	}
+}
+
+func TestToolOutputStreaming_CapsDisplayedOutput(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{}), func(m *Model) { m.streamToolOutput = true })
+	call := toolexec.ToolCall{Name: "chunked"}
+	stream := make(chan tea.Msg)
+
+	chunk := []byte(strings.Repeat("x", 1023) + "\n")
+	for i := 0; i < toolexec.DefaultMaxOutputSize/len(chunk)+8; i++ {
+		m.handleToolOutputChunk(toolOutputChunkMsg{call: call, chunk: chunk, stream: stream})
+	}
+	m.handleToolOutputChunk(toolOutputChunkMsg{call: call, chunk: []byte("last line\n"), stream: stream})
+
+	if got := len(m.toolStream.output); got > toolexec.DefaultMaxOutputSize {
+		t.Errorf("streamed output = %d bytes, want at most %d", got, toolexec.DefaultMaxOutputSize)
+	}
+	content := m.messages[0].content
+	if !strings.Contains(content, "Output:\n…\n") {
+		t.Error("capped output should start with an elision marker")
+	}
+	if !strings.HasSuffix(content, "x\nlast line") {
+		t.Errorf("capped output should keep the tail, got suffix %q", content[len(content)-20:])
+	}
+}
+
+func TestToolOutputStreaming_TagsStreamedMessage(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{}), func(m *Model) { m.streamToolOutput = true })
+	call := toolexec.ToolCall{Name: "chunked", Args: map[string]any{"n": 1}}
+
+	updated, _ := m.Update(toolOutputChunkMsg{call: call, chunk: []byte("line 1\n"), stream: make(chan tea.Msg)})
+	m = updated.(Model)
+	// A note added while the tool runs ends up after the streamed message
+	m.messages = append(m.messages, chatMessage{role: "system", content: "note"})
+
+	result := toolexec.NewResult(call.Name, toolexec.NewOutput().WithData([]byte("line 1\n")), nil)
+	updated, _ = m.Update(toolExecutionMsg{call: call, result: result})
+	m = updated.(Model)
+
+	if m.messages[0].toolCall == nil || m.messages[0].toolCall.ToolName != "chunked" {
+		t.Errorf("streamed message should carry the structured call, got %+v", m.messages[0].toolCall)
+	}
+	if m.messages[1].toolCall != nil {
+		t.Error("the later message should not be tagged")
+	}
+}
+
+func TestExecuteToolCall_StreamsChunks(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{chunks: []string{"a\n", "b\n", "c\n"}}), func(m *Model) { m.streamToolOutput = true })
+	call := toolexec.ToolCall{Name: "chunked"}
+
+	var sizes []int
+	cmd := m.executeToolCall(call)
+	for cmd != nil {
+		switch msg := cmd().(type) {
+		case toolOutputChunkMsg:
+			cmd = m.handleToolOutputChunk(msg)
+			sizes = append(sizes, len(m.messages[0].content))
+		case toolExecutionMsg:
+			if msg.result.Error != nil {
+				t.Fatalf("tool failed: %v", msg.result.Error)
+			}
+			m.handleToolResult(msg.call, msg.result)
+			cmd = nil
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+
+	if len(sizes) != 3 || sizes[0] >= sizes[1] || sizes[1] >= sizes[2] {
+		t.Errorf("tool bubble should grow with each chunk, sizes = %v", sizes)
+	}
+	if len(m.messages) != 1 || !strings.HasSuffix(m.messages[0].content, "Output:\na\nb\nc") {
+		t.Errorf("unexpected final messages: %+v", m.messages)
+	}
+}
+
+func TestExecuteToolCall_StreamingDisabled(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{chunks: []string{"a\n"}}), func(m *Model) { m.streamToolOutput = true })
+	m.streamToolOutput = false
+
+	if _, ok := m.executeToolCall(toolexec.ToolCall{Name: "chunked"})().(toolExecutionMsg); !ok {
+		t.Error("without streaming the tool should report only its final result")
+	}
+}
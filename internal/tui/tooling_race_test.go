@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/models"
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// toolNames returns the sorted names of the tools in a registry
+func toolNames(registry toolexec.Registry) []string {
+	var names []string
+	for _, info := range registry.List() {
+		names = append(names, info.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TestToolingRace runs tool calls from several Model copies at once, as
+// Bubble Tea does with commands, while the copies handle replies asking for
+// tools and read the registry. Run with -race to detect unsynchronized
+// access.
+func TestToolingRace(t *testing.T) {
+	registry := defaultToolRegistry(config.DefaultConfig())
+	executor := defaultToolExecutor(registry)
+	m := newTestModel(func(m *Model) {
+		m.toolRegistry = registry
+		m.toolExecutor = executor
+	})
+	want := toolNames(registry)
+	reply := &models.ModelOutput{Candidates: []models.Candidate{{
+		Text: "```tool\n" + `{"name": "calc", "args": {"expression": "1 + 1"}}` + "\n```",
+	}}}
+
+	const numGoroutines = 20
+	const callsPerGoroutine = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, numGoroutines*callsPerGoroutine)
+
+	wg.Add(numGoroutines * 2)
+	for i := 0; i < numGoroutines; i++ {
+		go func(id int) {
+			defer wg.Done()
+			for j := 0; j < callsPerGoroutine; j++ {
+				// Handling a reply with a tool call must keep the tooling
+				copied := m
+				copied.loading = true
+				updated, _ := copied.Update(responseMsg{output: reply})
+				copied = updated.(Model)
+				if copied.toolRegistry != registry || copied.toolExecutor != executor {
+					errs <- fmt.Errorf("goroutine %d: Update replaced the tooling", id)
+					return
+				}
+
+				call := toolexec.ToolCall{
+					Name: "calc",
+					Args: map[string]any{"expression": fmt.Sprintf("%d + %d", id, j)},
+				}
+				msg, ok := copied.executeToolCall(call)().(toolExecutionMsg)
+				if !ok || msg.result == nil || msg.result.Error != nil {
+					errs <- fmt.Errorf("goroutine %d: tool call failed: %+v", id, msg.result)
+					continue
+				}
+				if got := string(msg.result.Output.Data); got != fmt.Sprint(id+j) {
+					errs <- fmt.Errorf("goroutine %d: %s = %s, want %d", id, call.Args["expression"], got, id+j)
+				}
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < callsPerGoroutine; j++ {
+				_, _ = registry.Get("calc")
+				_ = registry.List()
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if got := toolNames(registry); !reflect.DeepEqual(got, want) {
+		t.Errorf("registry tools = %v, want %v", got, want)
+	}
+}
+
+func TestStartNextToolCall_WithoutTooling(t *testing.T) {
+	m := newTestModel(func(m *Model) { m.pendingToolCalls = []toolexec.ToolCall{{Name: "calc"}} })
+
+	msg, ok := m.startNextToolCall()().(toolExecutionMsg)
+	if !ok || msg.result.Error == nil || !strings.Contains(msg.result.Error.Error(), "not configured") {
+		t.Fatalf("expected a not configured error, got %+v", msg)
+	}
+	if m.toolRegistry != nil || m.toolExecutor != nil {
+		t.Error("tool calls should never create tooling")
+	}
+}
+
+func TestDefaultToolRegistry_ListTools(t *testing.T) {
+	cfg := config.DefaultConfig()
+	if defaultToolRegistry(cfg).Has("list_tools") {
+		t.Error("list_tools should not be offered by default")
+	}
+	cfg.ListTools = true
+	if !defaultToolRegistry(cfg).Has("list_tools") {
+		t.Error("list_tools should be offered when the config enables it")
+	}
+}
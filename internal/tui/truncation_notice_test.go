@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+func TestFormatToolMessage_TruncationNotice(t *testing.T) {
+	data := []byte(strings.Repeat("line of output\n", 3000)) // 45000 bytes
+	output := toolexec.NewOutput().WithTruncatedData(data, 1500)
+	result := toolexec.NewSuccessResult("bash", output)
+
+	msg := formatToolMessage(toolexec.ToolCall{Name: "bash"}, result)
+	if !strings.HasSuffix(msg, "\n[output truncated: 42KB / 2900 lines omitted]") {
+		t.Errorf("expected notice with dropped size and lines:\n%s", msg[len(msg)-80:])
+	}
+
+	// Tools that only flag truncation keep the bare marker
+	flagged := toolexec.NewOutput().WithData([]byte("partial"))
+	flagged.Truncated = true
+	msg = formatToolMessage(toolexec.ToolCall{Name: "sqlite_query"}, toolexec.NewSuccessResult("sqlite_query", flagged))
+	if !strings.HasSuffix(msg, "partial\n[output truncated]") {
+		t.Errorf("expected bare marker:\n%s", msg)
+	}
+}
+
+func TestTruncationNotice(t *testing.T) {
+	tests := []struct {
+		bytes, lines string
+		want         string
+	}{
+		{"512", "0", "[output truncated: 512B omitted]"},
+		{"2048", "7", "[output truncated: 2KB / 7 lines omitted]"},
+		{"3145728", "1", "[output truncated: 3.0MB / 1 line omitted]"},
+		{"", "", "[output truncated]"},
+		{"bogus", "3", "[output truncated]"},
+	}
+	for _, tt := range tests {
+		output := toolexec.NewOutput().
+			WithMetadata(toolexec.DroppedBytesKey, tt.bytes).
+			WithMetadata(toolexec.DroppedLinesKey, tt.lines)
+		if got := truncationNotice(output); got != tt.want {
+			t.Errorf("truncationNotice(%s, %s) = %q, want %q", tt.bytes, tt.lines, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/config"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// handleTryCommand handles the /try <model> [--keep] command, which re-sends
+// the last user message to another model and appends its reply, labeled
+// with the model name. The session switches back to its original model
+// afterwards unless --keep is given.
+func (m Model) handleTryCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	fields := strings.Fields(args)
+	keep := false
+	if len(fields) == 2 && fields[1] == "--keep" {
+		keep = true
+		fields = fields[:1]
+	}
+	if len(fields) != 1 {
+		m.err = fmt.Errorf("usage: /try <model> [--keep]")
+		return m, nil
+	}
+	if m.session == nil {
+		m.err = fmt.Errorf("no active session")
+		return m, nil
+	}
+	if m.isBusy() {
+		m.err = errBusy
+		return m, nil
+	}
+
+	model, ok := models.Lookup(fields[0])
+	if !ok {
+		names := make([]string, 0, len(models.AllModels()))
+		for _, known := range models.AllModels() {
+			names = append(names, known.Name)
+		}
+		m.err = fmt.Errorf("unknown model %q (available: %s)", fields[0], strings.Join(names, ", "))
+		return m, nil
+	}
+
+	prompt := ""
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if m.messages[i].role == "user" {
+			prompt = m.messages[i].content
+			break
+		}
+	}
+	if prompt == "" {
+		m.err = fmt.Errorf("no previous message to resend")
+		return m, nil
+	}
+
+	// Apply persona system prompt if set, as for the original send
+	if m.persona != nil && m.persona.SystemPrompt != "" {
+		prompt = config.FormatSystemPrompt(m.persona, prompt)
+	}
+	prompt = m.wrapPrompt(prompt)
+
+	cmd := m.beginSend(func(m *Model) tea.Cmd {
+		if keep {
+			m.modelName = model.Name
+		}
+		m.loading = true
+		m.err = nil
+		m.animationFrame = 0
+		return tea.Batch(m.sendPromptWithModel(prompt, model, keep), animationTick())
+	})
+	return m, cmd
+}
+
+// sendPromptWithModel sends a prompt using the given model and restores the
+// session's previous model afterwards unless keep is set.
+func (m Model) sendPromptWithModel(prompt string, model models.Model, keep bool) tea.Cmd {
+	session := m.session
+	jobs := m.jobs
+	return func() tea.Msg {
+		original := session.GetModel()
+		session.SetModel(model)
+		output, err := sendToModel(jobs, session, prompt, nil)
+		if !keep {
+			session.SetModel(original)
+		}
+		if err != nil {
+			return errMsg{err: fmt.Errorf("try %s: %w", model.Name, err)}
+		}
+		return responseMsg{output: output, model: model.Name}
+	}
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+	"github.com/diogo/geminiweb/internal/models"
+)
+
+// newTryTestModel returns a model with one exchange whose session records
+// the model and prompt of each send.
+func newTryTestModel(sentModels *[]string, sentPrompts *[]string) (Model, *modelTrackingSession) {
+	session := &modelTrackingSession{model: models.ModelPro}
+	session.sendMessageFunc = func(prompt string, files []*api.UploadedFile) (*models.ModelOutput, error) {
+		*sentModels = append(*sentModels, session.model.Name)
+		*sentPrompts = append(*sentPrompts, prompt)
+		return &models.ModelOutput{Candidates: []models.Candidate{{Text: "alternate reply"}}}, nil
+	}
+
+	m := newTestModel(withSession(session), func(m *Model) { m.modelName = session.model.Name })
+	m.messages = []chatMessage{
+		{role: "user", content: "explain goroutines"},
+		{role: "assistant", content: "original reply"},
+	}
+	return m, session
+}
+
+// runTry runs a /try command and feeds its reply back through Update
+func runTry(t *testing.T, m Model, args string) Model {
+	t.Helper()
+	updated, cmd := m.handleTryCommand(args)
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatalf("expected a send command, err = %v", m.err)
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) == 0 {
+		t.Fatal("expected a batch command")
+	}
+	updated, _ = m.Update(batch[0]())
+	return updated.(Model)
+}
+
+func TestTryCommand(t *testing.T) {
+	t.Run("resends last prompt with alternate model and restores", func(t *testing.T) {
+		var sentModels, sentPrompts []string
+		m, session := newTryTestModel(&sentModels, &sentPrompts)
+
+		m = runTry(t, m, "thinking")
+
+		if len(sentModels) != 1 || sentModels[0] != models.ModelThinking.Name {
+			t.Fatalf("sent with models %v, want [thinking]", sentModels)
+		}
+		if sentPrompts[0] != "explain goroutines" {
+			t.Errorf("resent prompt = %q", sentPrompts[0])
+		}
+		if session.model.Name != models.ModelPro.Name {
+			t.Errorf("session model = %q, want original restored", session.model.Name)
+		}
+		if m.modelName != models.ModelPro.Name {
+			t.Errorf("modelName = %q, want unchanged", m.modelName)
+		}
+
+		if len(m.messages) != 3 {
+			t.Fatalf("messages = %d, want 3", len(m.messages))
+		}
+		reply := m.messages[2]
+		if reply.role != "assistant" || reply.content != "alternate reply" || reply.model != models.ModelThinking.Name {
+			t.Errorf("unexpected alternate reply: %+v", reply)
+		}
+		if m.messages[1].content != "original reply" {
+			t.Error("original reply should be kept")
+		}
+		if !strings.Contains(m.viewport.View(), "Gemini (thinking)") {
+			t.Error("alternate reply should be labeled with the model name")
+		}
+	})
+
+	t.Run("keep switches the session model", func(t *testing.T) {
+		var sentModels, sentPrompts []string
+		m, session := newTryTestModel(&sentModels, &sentPrompts)
+
+		m = runTry(t, m, "fast --keep")
+
+		if len(sentModels) != 1 || sentModels[0] != models.ModelFast.Name {
+			t.Fatalf("sent with models %v, want [fast]", sentModels)
+		}
+		if session.model.Name != models.ModelFast.Name {
+			t.Errorf("session model = %q, want fast", session.model.Name)
+		}
+		if m.modelName != models.ModelFast.Name {
+			t.Errorf("modelName = %q, want fast", m.modelName)
+		}
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		var sentModels, sentPrompts []string
+		m, _ := newTryTestModel(&sentModels, &sentPrompts)
+
+		for args, want := range map[string]string{
+			"":             "usage: /try",
+			"fast extra":   "usage: /try",
+			"gemini-ultra": "unknown model",
+		} {
+			updated, cmd := m.handleTryCommand(args)
+			if err := updated.(Model).err; cmd != nil || err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("/try %q: err = %v, want %q", args, err, want)
+			}
+		}
+
+		m.messages = nil
+		updated, cmd := m.handleTryCommand("fast")
+		if err := updated.(Model).err; cmd != nil || err == nil || !strings.Contains(err.Error(), "no previous message") {
+			t.Errorf("expected no previous message error, got %v", err)
+		}
+		if len(sentModels) != 0 {
+			t.Error("nothing should be sent for rejected input")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// viewportDebounce is the window in which rapid viewport updates (such as
+// streamed tool output) are coalesced into one render
+const viewportDebounce = 30 * time.Millisecond
+
+// viewportTickMsg ends a viewport debounce window
+type viewportTickMsg struct{}
+
+// requestViewportUpdate re-renders the viewport for a rapid update. The
+// first update renders immediately and opens a debounce window; updates
+// arriving within the window only mark the viewport dirty, and a single
+// trailing render happens when the window ends.
+func (m *Model) requestViewportUpdate() tea.Cmd {
+	if m.viewportTickPending {
+		m.viewportDirty = true
+		return nil
+	}
+	m.renderViewport()
+	m.viewportTickPending = true
+	return tea.Tick(viewportDebounce, func(time.Time) tea.Msg {
+		return viewportTickMsg{}
+	})
+}
+
+// flushViewportUpdate closes the debounce window, rendering the updates
+// that arrived during it. A full updateViewport in the meantime already
+// cleared the dirty flag, so nothing is rendered twice.
+func (m *Model) flushViewportUpdate() {
+	m.viewportTickPending = false
+	if m.viewportDirty {
+		m.renderViewport()
+	}
+}
+
+// renderViewport renders the conversation and follows its bottom
+func (m *Model) renderViewport() {
+	m.viewportDirty = false
+	if m.viewportRenderer != nil {
+		m.viewportRenderer(m)
+		return
+	}
+	m.updateViewport()
+	m.viewport.GotoBottom()
+}
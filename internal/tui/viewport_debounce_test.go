@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/pkg/toolexec"
+)
+
+// countingViewportRenderer counts debounced viewport renders
+type countingViewportRenderer struct {
+	renders int
+}
+
+func (r *countingViewportRenderer) render(m *Model) {
+	r.renders++
+	m.updateViewport()
+}
+
+func TestRequestViewportUpdate_CoalescesRapidUpdates(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{}), func(m *Model) { m.streamToolOutput = true })
+	renderer := &countingViewportRenderer{}
+	m.viewportRenderer = renderer.render
+
+	call := toolexec.ToolCall{Name: "chunked"}
+	stream := make(chan tea.Msg)
+	var ticks int
+	for i := 0; i < 5; i++ {
+		updated, cmd := m.Update(toolOutputChunkMsg{call: call, chunk: []byte("line\n"), stream: stream})
+		m = updated.(Model)
+		if cmd != nil {
+			ticks++
+		}
+	}
+
+	if renderer.renders != 1 {
+		t.Errorf("5 rapid updates rendered %d times, want 1", renderer.renders)
+	}
+	if !m.viewportDirty || !m.viewportTickPending {
+		t.Fatal("later updates should wait for the trailing render")
+	}
+
+	updated, _ := m.Update(viewportTickMsg{})
+	m = updated.(Model)
+	if renderer.renders != 2 {
+		t.Errorf("expected a trailing render, got %d renders", renderer.renders)
+	}
+	if strings.Count(m.viewport.View(), "line") < 5 {
+		t.Errorf("trailing render should show all output:\n%s", m.viewport.View())
+	}
+
+	// A tick with nothing pending renders nothing
+	updated, _ = m.Update(viewportTickMsg{})
+	m = updated.(Model)
+	if renderer.renders != 2 {
+		t.Errorf("idle tick rendered, got %d renders", renderer.renders)
+	}
+
+	// The next update after the window renders immediately again
+	updated, cmd := m.Update(toolOutputChunkMsg{call: call, chunk: []byte("more\n"), stream: stream})
+	m = updated.(Model)
+	if renderer.renders != 3 || cmd == nil {
+		t.Errorf("update after the window should render and schedule a tick, got %d renders", renderer.renders)
+	}
+}
+
+func TestRequestViewportUpdate_FullRenderClearsPending(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{}), func(m *Model) { m.streamToolOutput = true })
+	renderer := &countingViewportRenderer{}
+	m.viewportRenderer = renderer.render
+
+	m.requestViewportUpdate()
+	m.requestViewportUpdate()
+	m.updateViewport()
+
+	m.flushViewportUpdate()
+	if renderer.renders != 1 {
+		t.Errorf("a full render should satisfy the pending update, got %d renders", renderer.renders)
+	}
+	if m.viewportTickPending {
+		t.Error("the debounce window should be closed")
+	}
+}
+
+func TestViewportTick_HandledDuringOverlay(t *testing.T) {
+	m := newTestModel(withSession(replyingSession("ok", nil)), withTools(t, &chunkedTool{}), func(m *Model) { m.streamToolOutput = true })
+	renderer := &countingViewportRenderer{}
+	m.viewportRenderer = renderer.render
+
+	m.requestViewportUpdate()
+	m.requestViewportUpdate()
+	m.showingRaw = true
+
+	updated, _ := m.Update(viewportTickMsg{})
+	m = updated.(Model)
+	if m.viewportTickPending || renderer.renders != 2 {
+		t.Errorf("tick should close the window under an overlay (pending=%v, renders=%d)",
+			m.viewportTickPending, renderer.renders)
+	}
+}
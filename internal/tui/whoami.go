@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+// ClientInfoProvider is implemented by clients that can describe their
+// active credentials and refresh settings (for /whoami).
+type ClientInfoProvider interface {
+	ClientInfo() api.ClientInfo
+}
+
+// formatClientInfo renders client info as a one-line summary. The PSID in
+// info is already masked by the client.
+func formatClientInfo(info api.ClientInfo) string {
+	psid := "not set"
+	if info.PSID != "" {
+		psid = info.PSID
+	}
+
+	parts := []string{
+		"PSID " + psid,
+		"PSIDTS " + presence(info.HasPSIDTS),
+		"token " + presence(info.HasAccessToken),
+	}
+
+	if info.AutoRefresh {
+		refresh := "auto-refresh on"
+		if info.RefreshInterval > 0 {
+			refresh += fmt.Sprintf(" (every %s)", info.RefreshInterval)
+		}
+		parts = append(parts, refresh)
+	} else {
+		parts = append(parts, "auto-refresh off")
+	}
+	parts = append(parts, "last refresh "+formatOptionalTime(info.LastRefresh))
+
+	if info.BrowserRefresh {
+		browser := info.BrowserRefreshType
+		if browser == "" {
+			browser = "auto"
+		}
+		parts = append(parts, fmt.Sprintf("browser refresh %s (last %s)", browser, formatOptionalTime(info.LastBrowserRefresh)))
+	} else {
+		parts = append(parts, "browser refresh off")
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+// presence describes whether a credential is set without revealing it
+func presence(set bool) string {
+	if set {
+		return "set"
+	}
+	return "missing"
+}
+
+// formatOptionalTime formats t relative to now, or "never" if zero
+func formatOptionalTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return formatTimeAgo(t)
+}
+
+// handleWhoamiCommand handles the /whoami command, which shows which
+// account credentials are active and how they are refreshed.
+func (m Model) handleWhoamiCommand(args string) (tea.Model, tea.Cmd) {
+	m.textarea.Reset()
+
+	if strings.TrimSpace(args) != "" {
+		m.err = fmt.Errorf("usage: /whoami")
+		return m, nil
+	}
+
+	provider, ok := m.client.(ClientInfoProvider)
+	if !ok {
+		m.err = fmt.Errorf("client info not available")
+		return m, nil
+	}
+
+	m.err = fmt.Errorf("%s %s", icon("👤", "[user]"), formatClientInfo(provider.ClientInfo()))
+	return m, nil
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textarea"
+
+	"github.com/diogo/geminiweb/internal/api"
+)
+
+func TestWhoamiCommand(t *testing.T) {
+	t.Run("renders client info fields", func(t *testing.T) {
+		client := &api.MockGeminiClient{
+			AccessToken: "SECRET_ACCESS_TOKEN",
+			ClientInfoVal: api.ClientInfo{
+				PSID:               "g.a0…9876",
+				HasPSIDTS:          true,
+				HasAccessToken:     true,
+				AutoRefresh:        true,
+				RefreshInterval:    9 * time.Minute,
+				LastRefresh:        time.Now().Add(-2 * time.Minute),
+				BrowserRefresh:     true,
+				BrowserRefreshType: "firefox",
+			},
+		}
+		m := Model{client: client, textarea: textarea.New()}
+
+		updated, _ := m.handleWhoamiCommand("")
+		m = updated.(Model)
+		if m.err == nil {
+			t.Fatal("expected client info in status")
+		}
+		got := m.err.Error()
+		for _, want := range []string{
+			"PSID g.a0…9876",
+			"PSIDTS set",
+			"token set",
+			"auto-refresh on (every 9m0s)",
+			"last refresh 2m ago",
+			"browser refresh firefox (last never)",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("status %q missing %q", got, want)
+			}
+		}
+		if strings.Contains(got, "SECRET_ACCESS_TOKEN") {
+			t.Error("access token should never be shown")
+		}
+	})
+
+	t.Run("reports missing credentials", func(t *testing.T) {
+		m := Model{client: &api.MockGeminiClient{}, textarea: textarea.New()}
+
+		updated, _ := m.handleWhoamiCommand("")
+		got := updated.(Model).err.Error()
+		for _, want := range []string{"PSID not set", "token missing", "auto-refresh off", "last refresh never", "browser refresh off"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("status %q missing %q", got, want)
+			}
+		}
+	})
+
+	t.Run("rejects arguments", func(t *testing.T) {
+		m := Model{client: &api.MockGeminiClient{}, textarea: textarea.New()}
+
+		updated, _ := m.handleWhoamiCommand("extra")
+		if err := updated.(Model).err; err == nil || !strings.Contains(err.Error(), "usage: /whoami") {
+			t.Errorf("expected usage error, got %v", err)
+		}
+	})
+}
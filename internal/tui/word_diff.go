@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// wordDiffMaxCells caps the size of the table used to align two texts;
+// beyond it the differing middle is shown as one deletion and one insertion
+const wordDiffMaxCells = 1 << 20
+
+// wordDiffTokenRe splits text into words and the whitespace between them
+var wordDiffTokenRe = regexp.MustCompile(`\s+|\S+`)
+
+// SegmentKind tells whether a word diff segment is shared by both texts or
+// only in one of them
+type SegmentKind int
+
+const (
+	// SegmentEqual is text present in both the old and the new version
+	SegmentEqual SegmentKind = iota
+	// SegmentInsert is text only in the new version
+	SegmentInsert
+	// SegmentDelete is text only in the old version
+	SegmentDelete
+)
+
+// Segment is a run of text in a word diff
+type Segment struct {
+	Kind SegmentKind
+	Text string
+}
+
+// wordDiff compares two texts word by word, returning the runs of text
+// that are unchanged, inserted or deleted going from old to new.
+// Whitespace is kept, so the equal and inserted segments join to new and
+// the equal and deleted segments join to old.
+func wordDiff(old, new string) []Segment {
+	a := wordDiffTokenRe.FindAllString(old, -1)
+	b := wordDiffTokenRe.FindAllString(new, -1)
+
+	// Common prefix and suffix need no alignment
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var segments []Segment
+	add := func(kind SegmentKind, text string) {
+		if text == "" {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].Kind == kind {
+			segments[n-1].Text += text
+			return
+		}
+		segments = append(segments, Segment{Kind: kind, Text: text})
+	}
+
+	add(SegmentEqual, strings.Join(a[:prefix], ""))
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if (len(midA)+1)*(len(midB)+1) > wordDiffMaxCells {
+		add(SegmentDelete, strings.Join(midA, ""))
+		add(SegmentInsert, strings.Join(midB, ""))
+	} else {
+		for _, op := range alignTokens(midA, midB) {
+			add(op.kind, op.text)
+		}
+	}
+	add(SegmentEqual, strings.Join(a[len(a)-suffix:], ""))
+	return segments
+}
+
+// tokenOp is one token of an alignment
+type tokenOp struct {
+	kind SegmentKind
+	text string
+}
+
+// alignTokens aligns two token lists along their longest common
+// subsequence, listing deletions before insertions within a change
+func alignTokens(a, b []string) []tokenOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]tokenOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, tokenOp{SegmentEqual, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, tokenOp{SegmentDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, tokenOp{SegmentInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, tokenOp{SegmentDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, tokenOp{SegmentInsert, b[j]})
+	}
+	return ops
+}
+
+// renderWordDiff renders a word diff in the style of git's --word-diff:
+// deletions as [-text-] and insertions as {+text+}, colored when the
+// terminal supports it, wrapped to width
+func renderWordDiff(segments []Segment, width int) string {
+	var sb strings.Builder
+	for _, seg := range segments {
+		switch seg.Kind {
+		case SegmentInsert:
+			sb.WriteString(diffAddStyle.Render("{+" + seg.Text + "+}"))
+		case SegmentDelete:
+			sb.WriteString(diffRemoveStyle.Strikethrough(true).Render("[-" + seg.Text + "-]"))
+		default:
+			sb.WriteString(seg.Text)
+		}
+	}
+	return lipgloss.NewStyle().Width(width).Render(sb.String())
+}
@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []Segment
+	}{
+		{
+			name: "unchanged",
+			old:  "the quick fox",
+			new:  "the quick fox",
+			want: []Segment{{SegmentEqual, "the quick fox"}},
+		},
+		{
+			name: "insertion",
+			old:  "the fox",
+			new:  "the quick brown fox",
+			want: []Segment{{SegmentEqual, "the "}, {SegmentInsert, "quick brown "}, {SegmentEqual, "fox"}},
+		},
+		{
+			name: "deletion",
+			old:  "the quick brown fox",
+			new:  "the fox",
+			want: []Segment{{SegmentEqual, "the "}, {SegmentDelete, "quick brown "}, {SegmentEqual, "fox"}},
+		},
+		{
+			name: "replacement",
+			old:  "revenue grew 10% last year",
+			new:  "revenue grew 12% last year",
+			want: []Segment{{SegmentEqual, "revenue grew "}, {SegmentDelete, "10%"}, {SegmentInsert, "12%"}, {SegmentEqual, " last year"}},
+		},
+		{
+			name: "unchanged runs between changes",
+			old:  "a b c d e",
+			new:  "a x c d y",
+			want: []Segment{
+				{SegmentEqual, "a "}, {SegmentDelete, "b"}, {SegmentInsert, "x"},
+				{SegmentEqual, " c d "}, {SegmentDelete, "e"}, {SegmentInsert, "y"},
+			},
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "hello world",
+			want: []Segment{{SegmentInsert, "hello world"}},
+		},
+		{
+			name: "to empty",
+			old:  "hello world",
+			new:  "",
+			want: []Segment{{SegmentDelete, "hello world"}},
+		},
+		{
+			name: "both empty",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wordDiff(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("wordDiff(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWordDiff_RebuildsBothTexts(t *testing.T) {
+	old := "Go is a statically typed,\ncompiled language designed at Google."
+	new := "Go is a statically typed, compiled programming language\ndesigned at Google in 2007."
+
+	var before, after strings.Builder
+	for _, seg := range wordDiff(old, new) {
+		if seg.Kind != SegmentInsert {
+			before.WriteString(seg.Text)
+		}
+		if seg.Kind != SegmentDelete {
+			after.WriteString(seg.Text)
+		}
+	}
+	if before.String() != old || after.String() != new {
+		t.Errorf("segments rebuild %q and %q", before.String(), after.String())
+	}
+}
+
+func TestWordDiff_LargeInputFallsBack(t *testing.T) {
+	old := strings.TrimSpace(strings.Repeat("a ", 1200))
+	new := strings.TrimSpace(strings.Repeat("b ", 1200))
+
+	got := wordDiff("start "+old+" end", "start "+new+" end")
+	want := []Segment{{SegmentEqual, "start "}, {SegmentDelete, old}, {SegmentInsert, new}, {SegmentEqual, " end"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected one deletion and one insertion for an oversized change, got %d segments", len(got))
+	}
+}
+
+func TestRenderWordDiff(t *testing.T) {
+	segments := []Segment{{SegmentEqual, "grew "}, {SegmentDelete, "10%"}, {SegmentInsert, "12%"}}
+	got := ansiPattern.ReplaceAllString(renderWordDiff(segments, 40), "")
+	if strings.TrimRight(got, " ") != "grew [-10%-]{+12%+}" {
+		t.Errorf("renderWordDiff() = %q", got)
+	}
+}
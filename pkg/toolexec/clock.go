@@ -0,0 +1,74 @@
+package toolexec
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Clock is the source of time used by the executor for timeouts and result
+// timing. Inject a fake implementation with WithClock to make timing
+// deterministic in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RealClock returns the Clock backed by the system time. It is the default
+// clock of an executor.
+func RealClock() Clock {
+	return realClock{}
+}
+
+// clockTimeoutContext is a context cancelled when its clock reaches the
+// deadline. Err reports context.DeadlineExceeded once the deadline passed,
+// like a context created by context.WithTimeout.
+type clockTimeoutContext struct {
+	context.Context
+	deadline time.Time
+}
+
+// Deadline returns the deadline measured by the clock.
+func (c *clockTimeoutContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+// Err reports context.DeadlineExceeded when the clock reached the deadline.
+func (c *clockTimeoutContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && errors.Is(context.Cause(c.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// withClockTimeout returns a copy of ctx that times out after d as measured
+// by clock. The real clock uses context.WithTimeout directly.
+func withClockTimeout(ctx context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(ctx, d)
+	}
+
+	deadline := clock.Now().Add(d)
+	inner, cancel := context.WithCancelCause(ctx)
+	timer := clock.After(d)
+	go func() {
+		select {
+		case <-timer:
+			cancel(context.DeadlineExceeded)
+		case <-inner.Done():
+		}
+	}()
+
+	return &clockTimeoutContext{Context: inner, deadline: deadline}, func() { cancel(context.Canceled) }
+}
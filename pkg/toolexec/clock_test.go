@@ -0,0 +1,156 @@
+package toolexec
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when advanced
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []fakeTimer
+	added  chan struct{}
+}
+
+type fakeTimer struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		added: make(chan struct{}, 16),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	ch := make(chan time.Time, 1)
+	c.timers = append(c.timers, fakeTimer{at: c.now.Add(d), ch: ch})
+	c.mu.Unlock()
+	c.added <- struct{}{}
+	return ch
+}
+
+// Advance moves the clock forward, firing the timers that are due
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, timer := range c.timers {
+		if !timer.at.After(c.now) {
+			timer.ch <- c.now
+			continue
+		}
+		pending = append(pending, timer)
+	}
+	c.timers = pending
+}
+
+// waitForTimer blocks until a timer has been registered with the clock
+func (c *fakeClock) waitForTimer(t *testing.T) {
+	t.Helper()
+	select {
+	case <-c.added:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no timer was registered with the clock")
+	}
+}
+
+func TestExecutor_WithClock_TimeoutAtConfiguredDuration(t *testing.T) {
+	registry := NewRegistry()
+	tool := NewMockTool("blocking", "Blocks until cancelled").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+
+	clock := newFakeClock()
+	exec := NewExecutor(registry, WithTimeout(5*time.Second), WithClock(clock))
+	resultCh := exec.ExecuteAsync(context.Background(), "blocking", NewInput())
+	clock.waitForTimer(t)
+
+	clock.Advance(5*time.Second - time.Nanosecond)
+	select {
+	case result := <-resultCh:
+		t.Fatalf("execution finished before the timeout: %v", result.Error)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	clock.Advance(time.Nanosecond)
+	var result *Result
+	select {
+	case result = <-resultCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("execution did not time out")
+	}
+
+	var timeoutErr *TimeoutError
+	if !errors.As(result.Error, &timeoutErr) {
+		t.Fatalf("expected TimeoutError, got %v", result.Error)
+	}
+	if timeoutErr.Timeout != 5*time.Second {
+		t.Errorf("Timeout = %v, want 5s", timeoutErr.Timeout)
+	}
+	if result.Duration != 5*time.Second {
+		t.Errorf("Duration = %v, want 5s", result.Duration)
+	}
+}
+
+func TestExecutor_WithClock_DeterministicDurations(t *testing.T) {
+	clock := newFakeClock()
+	start := clock.Now()
+
+	registry := NewRegistry()
+	tool := NewMockTool("work", "Takes 1.5s of fake time").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			clock.Advance(1500 * time.Millisecond)
+			return NewOutput(), nil
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+
+	exec := NewExecutor(registry, WithNoTimeout(), WithClock(clock))
+	results, err := exec.ExecuteMany(context.Background(), []ToolExecution{
+		{ToolName: "work", Input: NewInput()},
+		{ToolName: "work", Input: NewInput()},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteMany() error = %v", err)
+	}
+
+	for i, result := range results {
+		wantStart := start.Add(time.Duration(i) * 1500 * time.Millisecond)
+		if !result.StartTime.Equal(wantStart) {
+			t.Errorf("result %d StartTime = %v, want %v", i, result.StartTime, wantStart)
+		}
+		if result.Duration != 1500*time.Millisecond {
+			t.Errorf("result %d Duration = %v, want 1.5s", i, result.Duration)
+		}
+	}
+}
+
+func TestWithClock_NilUsesRealClock(t *testing.T) {
+	exec := NewExecutor(NewRegistry(), WithClock(newFakeClock()), WithClock(nil))
+	if _, ok := exec.config.clock.(realClock); !ok {
+		t.Errorf("clock = %T, want the real clock", exec.config.clock)
+	}
+}
@@ -5,6 +5,7 @@ package toolexec
 
 import (
 	"context"
+	"time"
 )
 
 // ConfirmationHandler defines the interface for requesting user confirmation
@@ -22,6 +23,15 @@ type ConfirmationHandler interface {
 	RequestConfirmation(ctx context.Context, tool Tool, args map[string]any) (bool, error)
 }
 
+// ConfirmationSummarizer is an optional interface for tools that can
+// describe what a call will do, for display in a confirmation prompt
+// (e.g. the list of files a batch write will touch).
+type ConfirmationSummarizer interface {
+	// ConfirmationSummary returns a short, human-readable description of
+	// the effects of running the tool with args.
+	ConfirmationSummary(args map[string]any) string
+}
+
 // AutoApproveHandler is a ConfirmationHandler that automatically approves
 // all execution requests. Use this for non-interactive or trusted environments.
 type AutoApproveHandler struct{}
@@ -86,10 +96,70 @@ func (h *CallbackConfirmationHandler) RequestConfirmation(ctx context.Context, t
 	return approved, err
 }
 
+// TimeWindowHandler is a ConfirmationHandler that only delegates confirmation
+// requests while the current time is inside an allowed window. Outside the
+// window every request is denied with ErrUserDenied without consulting the
+// inner handler. This is useful for scheduled or unattended runs where
+// destructive tools must not execute outside business hours.
+type TimeWindowHandler struct {
+	// Allow reports whether confirmation requests may be delegated at t.
+	// If nil, all times are allowed.
+	Allow func(t time.Time) bool
+
+	// Handler is the confirmation handler used inside the window.
+	// If nil, requests inside the window are approved.
+	Handler ConfirmationHandler
+
+	// Now returns the current time. If nil, time.Now is used.
+	// Override it in tests to use a fake clock.
+	Now func() time.Time
+}
+
+// WithTimeWindow wraps inner so that confirmation requests are auto-denied
+// whenever allow returns false for the current time.
+//
+// Example:
+//
+//	handler := WithTimeWindow(func(t time.Time) bool {
+//	    return t.Weekday() != time.Saturday && t.Weekday() != time.Sunday &&
+//	        t.Hour() >= 9 && t.Hour() < 18
+//	}, tuiHandler)
+func WithTimeWindow(allow func(time.Time) bool, inner ConfirmationHandler) *TimeWindowHandler {
+	return &TimeWindowHandler{
+		Allow:   allow,
+		Handler: inner,
+	}
+}
+
+// RequestConfirmation implements ConfirmationHandler.
+// Returns (false, *UserDeniedError) outside the allowed window.
+func (h *TimeWindowHandler) RequestConfirmation(ctx context.Context, tool Tool, args map[string]any) (bool, error) {
+	now := time.Now
+	if h.Now != nil {
+		now = h.Now
+	}
+
+	if h.Allow != nil && !h.Allow(now()) {
+		toolName := ""
+		if tool != nil {
+			toolName = tool.Name()
+		}
+		return false, NewUserDeniedError(toolName)
+	}
+
+	if h.Handler == nil {
+		return true, nil
+	}
+	return h.Handler.RequestConfirmation(ctx, tool, args)
+}
+
 // Ensure all handlers implement ConfirmationHandler.
 var (
 	_ ConfirmationHandler = (*AutoApproveHandler)(nil)
 	_ ConfirmationHandler = (*AutoDenyHandler)(nil)
 	_ ConfirmationHandler = ConfirmationFunc(nil)
 	_ ConfirmationHandler = (*CallbackConfirmationHandler)(nil)
+	_ ConfirmationHandler = (*TimeWindowHandler)(nil)
+
+	_ ConfirmationSummarizer = (*MultiFileWriteTool)(nil)
 )
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 )
 
 // TestAutoApproveHandler tests the AutoApproveHandler.
@@ -278,6 +279,109 @@ func TestUserDeniedError(t *testing.T) {
 	})
 }
 
+// TestTimeWindowHandler tests the WithTimeWindow confirmation decorator.
+func TestTimeWindowHandler(t *testing.T) {
+	businessHours := func(t time.Time) bool {
+		return t.Hour() >= 9 && t.Hour() < 18
+	}
+	fakeClock := func(hour int) func() time.Time {
+		return func() time.Time {
+			return time.Date(2024, 3, 4, hour, 30, 0, 0, time.UTC)
+		}
+	}
+	tool := NewMockTool("bash", "A test tool")
+
+	t.Run("delegates inside the window", func(t *testing.T) {
+		innerCalled := false
+		inner := ConfirmationFunc(func(ctx context.Context, tool Tool, args map[string]any) (bool, error) {
+			innerCalled = true
+			return true, nil
+		})
+
+		handler := WithTimeWindow(businessHours, inner)
+		handler.Now = fakeClock(10)
+
+		approved, err := handler.RequestConfirmation(context.Background(), tool, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if !approved {
+			t.Error("expected approval from inner handler")
+		}
+		if !innerCalled {
+			t.Error("inner handler should be called inside the window")
+		}
+	})
+
+	t.Run("propagates inner denial inside the window", func(t *testing.T) {
+		handler := WithTimeWindow(businessHours, &AutoDenyHandler{})
+		handler.Now = fakeClock(12)
+
+		approved, err := handler.RequestConfirmation(context.Background(), tool, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if approved {
+			t.Error("expected inner denial to be returned")
+		}
+	})
+
+	t.Run("denies outside the window", func(t *testing.T) {
+		innerCalled := false
+		inner := ConfirmationFunc(func(ctx context.Context, tool Tool, args map[string]any) (bool, error) {
+			innerCalled = true
+			return true, nil
+		})
+
+		handler := WithTimeWindow(businessHours, inner)
+		handler.Now = fakeClock(22)
+
+		approved, err := handler.RequestConfirmation(context.Background(), tool, nil)
+		if approved {
+			t.Error("should deny outside the window")
+		}
+		if !errors.Is(err, ErrUserDenied) {
+			t.Errorf("expected ErrUserDenied, got %v", err)
+		}
+		if innerCalled {
+			t.Error("inner handler should not be called outside the window")
+		}
+	})
+
+	t.Run("executor reports denial", func(t *testing.T) {
+		registry := NewRegistry()
+		executed := false
+		mock := NewMockTool("dangerous", "Needs confirmation").
+			WithRequiresConfirmation(true).
+			WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+				executed = true
+				return NewOutput(), nil
+			})
+		if err := registry.Register(mock); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+
+		handler := WithTimeWindow(businessHours, &AutoApproveHandler{})
+		handler.Now = fakeClock(3)
+
+		exec := NewExecutor(registry, WithConfirmationHandler(handler))
+		_, err := exec.Execute(context.Background(), "dangerous", NewInput())
+		if !IsUserDeniedError(err) {
+			t.Errorf("expected UserDeniedError, got %v", err)
+		}
+		if executed {
+			t.Error("tool should not execute outside the window")
+		}
+	})
+
+	t.Run("nil allow and handler approve", func(t *testing.T) {
+		approved, err := WithTimeWindow(nil, nil).RequestConfirmation(context.Background(), tool, nil)
+		if err != nil || !approved {
+			t.Errorf("expected approval, got approved=%v err=%v", approved, err)
+		}
+	})
+}
+
 // TestConfirmationHandlerInterface verifies interface implementations.
 func TestConfirmationHandlerInterface(t *testing.T) {
 	// This test verifies compile-time interface satisfaction
@@ -285,4 +389,5 @@ func TestConfirmationHandlerInterface(t *testing.T) {
 	var _ ConfirmationHandler = (*AutoDenyHandler)(nil)
 	var _ ConfirmationHandler = ConfirmationFunc(nil)
 	var _ ConfirmationHandler = (*CallbackConfirmationHandler)(nil)
+	var _ ConfirmationHandler = (*TimeWindowHandler)(nil)
 }
@@ -178,6 +178,11 @@
 //	    }
 //	})
 //
+// Middleware that holds resources, such as an open log file, can implement
+// io.Closer; Executor.Close closes it when the executor is done:
+//
+//	defer executor.Close()
+//
 // # Error Handling
 //
 // The package provides structured error types for different failure modes:
@@ -296,6 +301,7 @@
 //   - AutoApproveHandler: Automatically approves all requests (non-interactive)
 //   - AutoDenyHandler: Automatically denies all requests (highly restricted)
 //   - ConfirmationFunc: Adapter for using functions as handlers
+//   - WithTimeWindow: Auto-denies requests outside an allowed time window
 //
 // Example:
 //
@@ -5,7 +5,9 @@ package toolexec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -24,13 +26,31 @@ type Executor interface {
 
 	// ExecuteAsync runs a tool asynchronously and returns a channel for the result.
 	// The result channel will receive exactly one Result and then close.
-	// The caller should read from the channel to get the result.
+	// The channel is buffered, so the result is delivered even if the caller
+	// never reads it; abandoning the channel does not leak the goroutine.
 	ExecuteAsync(ctx context.Context, toolName string, input *Input) <-chan *Result
 
+	// ExecuteAsyncCtx is like ExecuteAsync, but if ctx is cancelled before the
+	// tool finishes, the tool's context is cancelled and a cancellation Result
+	// is delivered immediately instead of waiting for the tool to return.
+	ExecuteAsyncCtx(ctx context.Context, toolName string, input *Input) <-chan *Result
+
 	// ExecuteMany runs multiple tools concurrently and returns all results.
-	// Execution uses fail-fast behavior: the first error cancels remaining executions.
+	// By default every tool runs and the first error is returned; with
+	// WithFailFast(true) the first error cancels remaining executions.
 	// Partial results are returned even on error.
 	ExecuteMany(ctx context.Context, executions []ToolExecution) ([]*Result, error)
+
+	// Preflight checks each execution without running it: the tool must be
+	// registered, its input must pass validation, and it must pass security
+	// validation. Results are returned in the same order as executions.
+	Preflight(ctx context.Context, executions []ToolExecution) []PreflightResult
+
+	// Close releases resources held by the executor's middleware, security
+	// policy and confirmation handler, closing each that implements
+	// io.Closer. Close is idempotent: later calls do nothing and return the
+	// first call's error. The executor should not be used after Close.
+	Close() error
 }
 
 // ToolExecution represents a single tool execution request for batch operations.
@@ -40,6 +60,34 @@ type ToolExecution struct {
 
 	// Input is the input data for the tool.
 	Input *Input
+
+	// Metadata is caller context for this execution, such as a row ID.
+	// ExecuteMany merges it into Input.Metadata before running the tool,
+	// so middleware and the tool can read it. Entries here override input
+	// metadata with the same key; the caller's Input is not modified.
+	Metadata map[string]string
+}
+
+// executionInput returns the input to run for exec, with exec.Metadata
+// merged into a copy of its Input.
+func executionInput(exec ToolExecution) *Input {
+	if len(exec.Metadata) == 0 {
+		return exec.Input
+	}
+
+	input := NewInput()
+	if exec.Input != nil {
+		merged := *exec.Input
+		merged.Metadata = make(map[string]string, len(exec.Input.Metadata)+len(exec.Metadata))
+		for k, v := range exec.Input.Metadata {
+			merged.Metadata[k] = v
+		}
+		input = &merged
+	}
+	for k, v := range exec.Metadata {
+		input.Metadata[k] = v
+	}
+	return input
 }
 
 // executorConfig holds the configuration for an executor.
@@ -53,10 +101,18 @@ type executorConfig struct {
 	// Zero or negative means unlimited.
 	maxConcurrent int
 
+	// toolConcurrency limits concurrent executions per tool name in batch
+	// operations, in addition to maxConcurrent.
+	toolConcurrency map[string]int
+
 	// recoverPanics determines whether to recover from panics in tool execution.
 	// When true, panics are converted to PanicError.
 	recoverPanics bool
 
+	// panicInput attaches a sanitized copy of the input to recovered
+	// PanicErrors.
+	panicInput bool
+
 	// middlewareChain is the chain of middlewares to apply to tool execution.
 	// Middlewares are applied in order, with the first middleware being the
 	// outermost wrapper.
@@ -69,6 +125,25 @@ type executorConfig struct {
 	// confirmHandler is the handler for requesting user confirmation.
 	// If nil, no confirmation is requested even if the tool requires it.
 	confirmHandler ConfirmationHandler
+
+	// trustedTools lists tools that never request confirmation, regardless
+	// of their RequiresConfirmation result.
+	trustedTools map[string]bool
+
+	// filesystemRoot confines the path arguments of filesystem tools.
+	// If nil, paths are passed to tools unchanged.
+	filesystemRoot *filesystemRoot
+
+	// failFast makes ExecuteMany cancel the remaining executions on the
+	// first error instead of running every tool.
+	failFast bool
+
+	// clock is the source of time for timeouts and result timing.
+	clock Clock
+
+	// outputTransformers rewrite outputs before Execute returns them, in
+	// registration order.
+	outputTransformers []OutputTransformer
 }
 
 // defaultConfig returns the default executor configuration.
@@ -77,6 +152,7 @@ func defaultConfig() *executorConfig {
 		timeout:       30 * time.Second, // Default 30 second timeout per spec
 		maxConcurrent: 1,                // Conservative default for safety
 		recoverPanics: true,             // Recover panics by default for stability
+		clock:         realClock{},
 	}
 }
 
@@ -86,6 +162,12 @@ func defaultConfig() *executorConfig {
 type executor struct {
 	registry Registry
 	config   *executorConfig
+
+	// toolSlots holds a semaphore per tool with a concurrency limit
+	toolSlots map[string]chan struct{}
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // NewExecutor creates a new Executor with the given registry and options.
@@ -114,10 +196,17 @@ func NewExecutor(registry Registry, opts ...ExecutorOption) *executor {
 	config := defaultConfig()
 	applyOptions(config, opts...)
 
-	return &executor{
+	e := &executor{
 		registry: registry,
 		config:   config,
 	}
+	for name, n := range config.toolConcurrency {
+		if e.toolSlots == nil {
+			e.toolSlots = make(map[string]chan struct{}, len(config.toolConcurrency))
+		}
+		e.toolSlots[name] = make(chan struct{}, n)
+	}
+	return e
 }
 
 // Execute runs a tool synchronously with the given input.
@@ -125,11 +214,13 @@ func NewExecutor(registry Registry, opts ...ExecutorOption) *executor {
 //  1. Look up the tool in the registry
 //  2. Apply timeout if configured
 //  3. Check context before execution
-//  4. Validate against security policy (if configured)
-//  5. Request confirmation if tool requires it (if handler configured)
-//  6. Apply middleware chain (if configured)
-//  7. Execute the tool with panic recovery
-//  8. Return the output or error
+//  4. Confine filesystem paths to the root (if configured)
+//  5. Validate against security policy (if configured)
+//  6. Request confirmation if tool requires it (if handler configured)
+//  7. Apply middleware chain (if configured)
+//  8. Execute the tool with panic recovery
+//  9. Apply output transformers to a successful output
+//  10. Return the output or error
 //
 // The context is used for cancellation and can have a timeout applied.
 // If the executor has a default timeout configured and the context has no
@@ -151,7 +242,7 @@ func (e *executor) Execute(ctx context.Context, toolName string, input *Input) (
 	if e.config.timeout > 0 {
 		if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(ctx, e.config.timeout)
+			ctx, cancel = withClockTimeout(ctx, e.config.clock, e.config.timeout)
 			defer cancel()
 		}
 	}
@@ -163,20 +254,15 @@ func (e *executor) Execute(ctx context.Context, toolName string, input *Input) (
 	default:
 	}
 
-	// Step 4: Validate against security policy if configured
-	if e.config.securityPolicy != nil {
-		// Convert input params to args for security validation
-		args := make(map[string]any)
-		if input != nil && input.Params != nil {
-			args = input.Params
-		}
-		if err := e.config.securityPolicy.Validate(ctx, toolName, args); err != nil {
-			return nil, fmt.Errorf("security validation failed: %w", err)
-		}
+	// Steps 4-5: Confine filesystem paths and validate against the
+	// security policy
+	input, err = e.validateSecurity(ctx, toolName, input)
+	if err != nil {
+		return nil, err
 	}
 
-	// Step 5: Request confirmation if tool requires it and handler is configured
-	if e.config.confirmHandler != nil {
+	// Step 6: Request confirmation if tool requires it and handler is configured
+	if e.config.confirmHandler != nil && !e.config.trustedTools[toolName] {
 		// Convert input params to args for confirmation check
 		args := make(map[string]any)
 		if input != nil && input.Params != nil {
@@ -193,27 +279,85 @@ func (e *executor) Execute(ctx context.Context, toolName string, input *Input) (
 		}
 	}
 
-	// Step 6: Create the base execution function
+	// Step 7: Create the base execution function
 	// This function performs the actual tool execution with error wrapping
 	baseFn := func(ctx context.Context, toolName string, input *Input) (*Output, error) {
 		return e.executeToolDirectly(ctx, tool, toolName, input)
 	}
 
-	// Step 7: Apply middleware chain if configured
+	// Step 8: Apply middleware chain if configured
 	execFn := baseFn
 	if e.config.middlewareChain != nil && e.config.middlewareChain.Len() > 0 {
 		execFn = e.config.middlewareChain.Wrap(baseFn)
 	}
 
-	// Step 8: Execute with optional panic recovery
+	// Step 9: Execute with optional panic recovery
 	// Note: If middleware chain includes RecoveryMiddleware, this provides
 	// a second layer of protection. The executor's panic recovery is always
 	// the outermost layer when enabled.
+	var output *Output
 	if e.config.recoverPanics {
-		return e.executeWithRecovery(ctx, execFn, toolName, input)
+		output, err = e.executeWithRecovery(ctx, execFn, toolName, input)
+	} else {
+		output, err = execFn(ctx, toolName, input)
+	}
+
+	// Step 10: Transform the output, including output returned with an
+	// error (e.g. a command that exited non-zero), which is shown too
+	return e.transformOutput(toolName, output), err
+}
+
+// transformOutput passes output through the configured transformers in
+// order. A transformer returning nil leaves the output unchanged.
+func (e *executor) transformOutput(toolName string, output *Output) *Output {
+	if output == nil {
+		return nil
+	}
+	for _, transform := range e.config.outputTransformers {
+		if transformed := transform(toolName, output); transformed != nil {
+			output = transformed
+		}
+	}
+	return output
+}
+
+// transformStream wraps emit so streamed chunks pass through the output
+// transformers like the final output does. Each chunk is transformed on
+// its own, as the Data of an otherwise empty Output.
+func (e *executor) transformStream(toolName string, emit func(chunk []byte)) func(chunk []byte) {
+	if len(e.config.outputTransformers) == 0 {
+		return emit
+	}
+	return func(chunk []byte) {
+		emit(e.transformOutput(toolName, NewOutput().WithData(chunk)).Data)
+	}
+}
+
+// validateSecurity confines filesystem paths to the root (if configured)
+// and validates the execution against the security policy (if configured).
+// Resolved paths replace the originals in the returned input so policy,
+// confirmation and the tool itself all see the same jailed location.
+func (e *executor) validateSecurity(ctx context.Context, toolName string, input *Input) (*Input, error) {
+	if e.config.filesystemRoot != nil {
+		confined, err := e.config.filesystemRoot.confine(toolName, input)
+		if err != nil {
+			return nil, fmt.Errorf("security validation failed: %w", err)
+		}
+		input = confined
+	}
+
+	if e.config.securityPolicy != nil {
+		// Convert input params to args for security validation
+		args := make(map[string]any)
+		if input != nil && input.Params != nil {
+			args = input.Params
+		}
+		if err := e.config.securityPolicy.Validate(ctx, toolName, args); err != nil {
+			return nil, fmt.Errorf("security validation failed: %w", err)
+		}
 	}
 
-	return execFn(ctx, toolName, input)
+	return input, nil
 }
 
 // executeWithRecovery executes a ToolFunc with panic recovery.
@@ -223,7 +367,11 @@ func (e *executor) executeWithRecovery(ctx context.Context, fn ToolFunc, toolNam
 	defer func() {
 		if r := recover(); r != nil {
 			stack := string(debug.Stack())
-			err = NewPanicErrorWithStack(toolName, r, stack)
+			panicErr := NewPanicErrorWithStack(toolName, r, stack)
+			if e.config.panicInput {
+				panicErr.Input = newPanicInput(input)
+			}
+			err = panicErr
 			output = nil
 		}
 	}()
@@ -234,15 +382,26 @@ func (e *executor) executeWithRecovery(ctx context.Context, fn ToolFunc, toolNam
 // executeToolDirectly executes a tool without panic recovery or middleware.
 // It wraps any errors from the tool execution.
 // This is the innermost execution function that actually calls the tool.
+// StreamingTools run with ExecuteStream when ctx carries an output stream
+// (see WithOutputStream).
 func (e *executor) executeToolDirectly(ctx context.Context, tool Tool, toolName string, input *Input) (*Output, error) {
-	output, err := tool.Execute(ctx, input)
+	var output *Output
+	var err error
+	streaming, ok := tool.(StreamingTool)
+	if emit := OutputStreamFromContext(ctx); ok && emit != nil {
+		output, err = streaming.ExecuteStream(ctx, input, e.transformStream(toolName, emit))
+	} else {
+		output, err = tool.Execute(ctx, input)
+	}
+	// Output returned with an error (e.g. a command's output before it
+	// failed) is kept so it can be shown, after transformOutput
 	if err != nil {
 		// Check if this was a context error
 		if ctx.Err() != nil {
-			return nil, e.wrapContextError(ctx, toolName)
+			return output, e.wrapContextError(ctx, toolName)
 		}
 		// Wrap the execution error
-		return nil, NewExecutionErrorWithCause(toolName, err)
+		return output, NewExecutionErrorWithCause(toolName, err)
 	}
 
 	return output, nil
@@ -257,8 +416,8 @@ func (e *executor) wrapContextError(ctx context.Context, toolName string) error
 		// Determine the timeout duration if available
 		if deadline, ok := ctx.Deadline(); ok {
 			// Calculate approximate timeout from deadline
-			timeout := time.Until(deadline)
-			if timeout < 0 {
+			timeout := deadline.Sub(e.config.clock.Now())
+			if timeout <= 0 {
 				// Deadline has passed, use config timeout as approximation
 				timeout = e.config.timeout
 			}
@@ -320,12 +479,41 @@ func (e *executor) HasMiddleware() bool {
 	return e.config.middlewareChain != nil && e.config.middlewareChain.Len() > 0
 }
 
+// Close closes every middleware, then the security policy and confirmation
+// handler, that implements io.Closer. All of them are closed even if some
+// fail; their errors are joined.
+func (e *executor) Close() error {
+	e.closeOnce.Do(func() {
+		var components []any
+		if e.config.middlewareChain != nil {
+			for _, mw := range e.config.middlewareChain.Middlewares() {
+				components = append(components, mw)
+			}
+		}
+		components = append(components, e.config.securityPolicy, e.config.confirmHandler)
+
+		var errs []error
+		for _, component := range components {
+			closer, ok := component.(io.Closer)
+			if !ok {
+				continue
+			}
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		e.closeErr = errors.Join(errs...)
+	})
+	return e.closeErr
+}
+
 // ExecuteAsync runs a tool asynchronously and returns a channel for the result.
 // The result channel will receive exactly one Result and then close.
 // This allows callers to start execution and retrieve results when needed.
 //
 // The implementation:
-//   - Uses a buffered channel (size 1) to prevent goroutine leaks
+//   - Uses a buffered channel (size 1) so the producer never blocks, even if
+//     the caller abandons the channel without reading it
 //   - Closes the channel when done to signal completion
 //   - Includes timing information in the result (start, end, duration)
 //   - Respects context cancellation through the underlying Execute call
@@ -343,9 +531,9 @@ func (e *executor) ExecuteAsync(ctx context.Context, toolName string, input *Inp
 	go func() {
 		defer close(resultCh)
 
-		start := time.Now()
+		start := e.config.clock.Now()
 		output, err := e.Execute(ctx, toolName, input)
-		end := time.Now()
+		end := e.config.clock.Now()
 
 		result := &Result{
 			ToolName:  toolName,
@@ -362,12 +550,63 @@ func (e *executor) ExecuteAsync(ctx context.Context, toolName string, input *Inp
 	return resultCh
 }
 
+// ExecuteAsyncCtx runs a tool asynchronously and stops waiting for it as soon
+// as ctx is cancelled. The result channel will receive exactly one Result and
+// then close.
+//
+// Unlike ExecuteAsync, cancellation does not depend on the tool honouring its
+// context: when ctx is done before the tool returns, a Result carrying the
+// cancellation (or timeout) error is delivered right away and the tool's
+// context is cancelled so it can stop its work. Any late output from the tool
+// is discarded.
+//
+// Both the result channel and the internal completion channel are buffered,
+// so no goroutine is left blocked if the caller abandons the channel. A tool
+// that ignores cancellation keeps running until it returns on its own.
+func (e *executor) ExecuteAsyncCtx(ctx context.Context, toolName string, input *Input) <-chan *Result {
+	resultCh := make(chan *Result, 1)
+	execCtx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		defer close(resultCh)
+		defer cancel()
+
+		start := e.config.clock.Now()
+
+		done := make(chan *Result, 1)
+		go func() {
+			output, err := e.Execute(execCtx, toolName, input)
+			done <- &Result{ToolName: toolName, Output: output, Error: err}
+		}()
+
+		var result *Result
+		select {
+		case result = <-done:
+		case <-ctx.Done():
+			cancel()
+			result = &Result{ToolName: toolName, Error: e.wrapContextError(ctx, toolName)}
+		}
+
+		end := e.config.clock.Now()
+		result.StartTime = start
+		result.EndTime = end
+		result.Duration = end.Sub(start)
+
+		resultCh <- result
+	}()
+
+	return resultCh
+}
+
 // ExecuteMany runs multiple tools concurrently and returns all results.
-// It uses errgroup for coordinated concurrent execution with fail-fast behavior.
+// It uses errgroup for coordinated concurrent execution.
 //
 // Behavior:
 //   - Executes tools concurrently up to the configured maxConcurrent limit
-//   - Fail-fast: the first error cancels all remaining executions via context
+//     and the per-tool limits set with WithToolConcurrency
+//   - By default every tool runs, even after another one fails
+//   - With WithFailFast(true), the first error cancels the shared context:
+//     running tools see the cancellation and no new tools are launched
 //   - Partial results are always returned, even when an error occurs
 //   - Each result includes timing information (start, end, duration)
 //   - Results are returned in the same order as the input executions
@@ -381,7 +620,7 @@ func (e *executor) ExecuteAsync(ctx context.Context, toolName string, input *Inp
 //
 //	executions := []ToolExecution{
 //	    {ToolName: "tool1", Input: input1},
-//	    {ToolName: "tool2", Input: input2},
+//	    {ToolName: "tool2", Input: input2, Metadata: map[string]string{"row": "42"}},
 //	}
 //	results, err := executor.ExecuteMany(ctx, executions)
 //	// results[0] corresponds to tool1, results[1] to tool2
@@ -399,16 +638,22 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 	// could theoretically race on some architectures)
 	var mu sync.Mutex
 
-	// Create errgroup with context for coordinated cancellation
-	// When one goroutine returns an error, gctx is cancelled,
-	// which signals all other goroutines to stop
-	g, gctx := errgroup.WithContext(ctx)
+	// In fail-fast mode, create errgroup with context for coordinated
+	// cancellation: when one goroutine returns an error, gctx is cancelled,
+	// which signals all other goroutines to stop. Otherwise a plain group
+	// still reports the first error but lets every execution finish.
+	g, gctx := &errgroup.Group{}, ctx
+	if e.config.failFast {
+		g, gctx = errgroup.WithContext(ctx)
+	}
 
-	// Apply concurrency limit if configured
-	// SetLimit(n) limits the number of active goroutines to n
-	// SetLimit(0) or negative means unlimited
+	// Slots are always taken in the same order, the global slot (in input
+	// order, here) and then the tool's own slot, and released in reverse by
+	// the execution's deferred calls, even if it panics. A nil channel
+	// means unlimited concurrency.
+	var globalSlots chan struct{}
 	if e.config.maxConcurrent > 0 {
-		g.SetLimit(e.config.maxConcurrent)
+		globalSlots = make(chan struct{}, e.config.maxConcurrent)
 	}
 
 	// Launch all executions
@@ -417,7 +662,16 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 		// In Go 1.22+ this is handled automatically, but we support older versions
 		i, exec := i, exec
 
+		// Wait for a global slot, and stop launching new executions once
+		// the group has been cancelled
+		releaseGlobal, err := acquireSlot(gctx, globalSlots)
+		if err != nil {
+			break
+		}
+
 		g.Go(func() error {
+			defer releaseGlobal()
+
 			// Check if context is already cancelled before starting
 			select {
 			case <-gctx.Done():
@@ -428,8 +682,8 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 					ToolName:  exec.ToolName,
 					Output:    nil,
 					Error:     e.wrapContextError(gctx, exec.ToolName),
-					StartTime: time.Now(),
-					EndTime:   time.Now(),
+					StartTime: e.config.clock.Now(),
+					EndTime:   e.config.clock.Now(),
 					Duration:  0,
 				}
 				mu.Unlock()
@@ -437,10 +691,27 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 			default:
 			}
 
+			// Wait for a slot if the tool has its own concurrency limit
+			releaseTool, err := acquireSlot(gctx, e.toolSlots[exec.ToolName])
+			if err != nil {
+				mu.Lock()
+				results[i] = &Result{
+					ToolName:  exec.ToolName,
+					Output:    nil,
+					Error:     e.wrapContextError(gctx, exec.ToolName),
+					StartTime: e.config.clock.Now(),
+					EndTime:   e.config.clock.Now(),
+					Duration:  0,
+				}
+				mu.Unlock()
+				return nil
+			}
+			defer releaseTool()
+
 			// Execute the tool
-			start := time.Now()
-			output, err := e.Execute(gctx, exec.ToolName, exec.Input)
-			end := time.Now()
+			start := e.config.clock.Now()
+			output, err := e.Execute(gctx, exec.ToolName, executionInput(exec))
+			end := e.config.clock.Now()
 
 			// Record the result
 			mu.Lock()
@@ -454,8 +725,8 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 			}
 			mu.Unlock()
 
-			// Return error for fail-fast behavior
-			// This will cancel gctx and stop other executions
+			// Return error so Wait reports it; in fail-fast mode this
+			// also cancels gctx and stops other executions
 			if err != nil {
 				return err
 			}
@@ -469,13 +740,14 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 	err := g.Wait()
 
 	// Fill in any nil results with cancelled errors
-	// This handles the case where goroutines were never started due to limit
+	// This handles the case where goroutines were never started due to
+	// the limit or a fail-fast cancellation
 	for i, result := range results {
 		if result == nil {
 			results[i] = &Result{
 				ToolName:  executions[i].ToolName,
 				Output:    nil,
-				Error:     e.wrapContextError(ctx, executions[i].ToolName),
+				Error:     e.wrapContextError(gctx, executions[i].ToolName),
 				StartTime: time.Time{},
 				EndTime:   time.Time{},
 				Duration:  0,
@@ -491,5 +763,21 @@ func (e *executor) ExecuteMany(ctx context.Context, executions []ToolExecution)
 	return results, nil
 }
 
+// acquireSlot waits for a free slot in the semaphore slots and returns
+// the function releasing it. A nil semaphore (no limit) returns
+// immediately. It fails if ctx is done first.
+func acquireSlot(ctx context.Context, slots chan struct{}) (func(), error) {
+	if slots == nil {
+		return func() {}, ctx.Err()
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // Ensure executor implements the Executor interface.
 var _ Executor = (*executor)(nil)
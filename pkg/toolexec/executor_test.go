@@ -3,6 +3,9 @@ package toolexec
 import (
 	"context"
 	"errors"
+	"fmt"
+	"runtime"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -616,6 +619,131 @@ func TestExecutor_ExecuteAsync(t *testing.T) {
 	})
 }
 
+// waitForGoroutines polls until the goroutine count drops to at most want,
+// failing the test if it does not happen within a few seconds.
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if runtime.NumGoroutine() <= want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("goroutine leak: have %d goroutines, want at most %d", runtime.NumGoroutine(), want)
+}
+
+// TestExecutor_ExecuteAsyncCtx tests the ExecuteAsyncCtx method.
+func TestExecutor_ExecuteAsyncCtx(t *testing.T) {
+	t.Run("delivers result like ExecuteAsync", func(t *testing.T) {
+		registry := NewRegistry()
+		tool := NewMockTool("test-tool", "A test tool").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				return NewOutput().WithMessage("done"), nil
+			},
+		)
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("Failed to register tool: %v", err)
+		}
+
+		exec := NewExecutor(registry)
+		result := <-exec.ExecuteAsyncCtx(context.Background(), "test-tool", NewInput())
+		if result.Error != nil {
+			t.Fatalf("ExecuteAsyncCtx() unexpected error: %v", result.Error)
+		}
+		if result.Output == nil || result.Output.Message != "done" {
+			t.Errorf("unexpected output: %+v", result.Output)
+		}
+		if result.Duration == 0 {
+			t.Error("Result.Duration should be non-zero")
+		}
+	})
+
+	t.Run("returns immediately when cancelled even if tool ignores context", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		registry := NewRegistry()
+		tool := NewMockTool("stubborn", "Ignores cancellation").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				<-release
+				return NewOutput(), nil
+			},
+		)
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("Failed to register tool: %v", err)
+		}
+
+		exec := NewExecutor(registry)
+		ctx, cancel := context.WithCancel(context.Background())
+		resultCh := exec.ExecuteAsyncCtx(ctx, "stubborn", NewInput())
+		cancel()
+
+		select {
+		case result := <-resultCh:
+			if !errors.Is(result.Error, ErrContextCancelled) {
+				t.Errorf("expected ErrContextCancelled, got %v", result.Error)
+			}
+			if result.Output != nil {
+				t.Error("cancelled result should have nil output")
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("ExecuteAsyncCtx() did not return after cancellation")
+		}
+	})
+
+	t.Run("no goroutine leak when channel is abandoned after cancellation", func(t *testing.T) {
+		registry := NewRegistry()
+		tool := NewMockTool("blocking", "Blocks until cancelled").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		)
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("Failed to register tool: %v", err)
+		}
+
+		exec := NewExecutor(registry, WithTimeout(0))
+		before := runtime.NumGoroutine()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		for i := 0; i < 20; i++ {
+			// Channels are deliberately never read
+			_ = exec.ExecuteAsyncCtx(ctx, "blocking", NewInput())
+		}
+		cancel()
+
+		waitForGoroutines(t, before)
+	})
+}
+
+// TestExecutor_ExecuteAsync_AbandonedChannel verifies that the producer
+// goroutine exits even if nobody reads the result.
+func TestExecutor_ExecuteAsync_AbandonedChannel(t *testing.T) {
+	registry := NewRegistry()
+	tool := NewMockTool("blocking", "Blocks until cancelled").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+
+	exec := NewExecutor(registry, WithTimeout(0))
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < 20; i++ {
+		_ = exec.ExecuteAsync(ctx, "blocking", NewInput())
+	}
+	cancel()
+
+	waitForGoroutines(t, before)
+}
+
 // TestExecutor_ExecuteMany tests the ExecuteMany method.
 func TestExecutor_ExecuteMany(t *testing.T) {
 	t.Run("empty executions", func(t *testing.T) {
@@ -718,6 +846,122 @@ func TestExecutor_ExecuteMany(t *testing.T) {
 		}
 	})
 
+	t.Run("fail-fast cancels later tools", func(t *testing.T) {
+		for _, failFast := range []bool{true, false} {
+			t.Run(fmt.Sprintf("failFast=%v", failFast), func(t *testing.T) {
+				registry := NewRegistry()
+				var ran atomic.Int32
+				tools := []Tool{
+					NewMockTool("first", "First").WithExecuteFunc(
+						func(ctx context.Context, input *Input) (*Output, error) {
+							ran.Add(1)
+							return NewOutput(), nil
+						},
+					),
+					NewMockTool("failing", "Failing").WithExecuteFunc(
+						func(ctx context.Context, input *Input) (*Output, error) {
+							return nil, errors.New("critical failure")
+						},
+					),
+					NewMockTool("later", "Later").WithExecuteFunc(
+						func(ctx context.Context, input *Input) (*Output, error) {
+							ran.Add(1)
+							return NewOutput(), nil
+						},
+					),
+				}
+				for _, tool := range tools {
+					if err := registry.Register(tool); err != nil {
+						t.Fatalf("Failed to register tool: %v", err)
+					}
+				}
+
+				exec := NewExecutor(registry, WithMaxConcurrent(1), WithFailFast(failFast))
+
+				executions := []ToolExecution{
+					{ToolName: "first", Input: NewInput()},
+					{ToolName: "failing", Input: NewInput()},
+					{ToolName: "later", Input: NewInput()},
+					{ToolName: "later", Input: NewInput()},
+				}
+
+				results, err := exec.ExecuteMany(context.Background(), executions)
+
+				if err == nil || !strings.Contains(err.Error(), "critical failure") {
+					t.Errorf("ExecuteMany() error = %v, want triggering error", err)
+				}
+				if len(results) != len(executions) {
+					t.Fatalf("ExecuteMany() returned %d results, want %d", len(results), len(executions))
+				}
+				if results[0].Error != nil {
+					t.Errorf("Result[0] unexpected error: %v", results[0].Error)
+				}
+
+				for i := 2; i < len(results); i++ {
+					if failFast {
+						if !errors.Is(results[i].Error, ErrContextCancelled) {
+							t.Errorf("Result[%d].Error = %v, want cancellation", i, results[i].Error)
+						}
+					} else if results[i].Error != nil {
+						t.Errorf("Result[%d] unexpected error: %v", i, results[i].Error)
+					}
+				}
+
+				wantRan := int32(3)
+				if failFast {
+					wantRan = 1
+				}
+				if got := ran.Load(); got != wantRan {
+					t.Errorf("tools ran = %d, want %d", got, wantRan)
+				}
+			})
+		}
+	})
+
+	t.Run("fail-fast cancels running tools", func(t *testing.T) {
+		registry := NewRegistry()
+		slow := NewMockTool("slow", "Slow").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-time.After(5 * time.Second):
+					return NewOutput(), nil
+				}
+			},
+		)
+		failing := NewMockTool("failing", "Failing").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				time.Sleep(10 * time.Millisecond)
+				return nil, errors.New("critical failure")
+			},
+		)
+		if err := registry.Register(slow); err != nil {
+			t.Fatalf("Failed to register slow: %v", err)
+		}
+		if err := registry.Register(failing); err != nil {
+			t.Fatalf("Failed to register failing: %v", err)
+		}
+
+		exec := NewExecutor(registry, WithMaxConcurrent(2), WithFailFast(true))
+
+		start := time.Now()
+		results, err := exec.ExecuteMany(context.Background(), []ToolExecution{
+			{ToolName: "slow", Input: NewInput()},
+			{ToolName: "failing", Input: NewInput()},
+		})
+
+		if err == nil {
+			t.Error("ExecuteMany() expected error but got none")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("ExecuteMany() took %v, running tool was not cancelled", elapsed)
+		}
+		if results[0].Error == nil {
+			t.Error("Result[0] should report cancellation")
+		}
+	})
+
 	t.Run("results in order", func(t *testing.T) {
 		registry := NewRegistry()
 		for i := 0; i < 5; i++ {
@@ -808,6 +1052,267 @@ func TestExecutor_ExecuteMany(t *testing.T) {
 	})
 }
 
+func TestExecutor_ExecuteMany_Metadata(t *testing.T) {
+	registry := NewRegistry()
+	var mu sync.Mutex
+	seen := make(map[string]map[string]string)
+	tool := NewMockTool("record", "Records its input metadata").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			seen[input.Name] = input.Metadata
+			return NewOutput(), nil
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+
+	type auditEntry struct {
+		toolName string
+		rowID    string
+		source   string
+	}
+	var audit []auditEntry
+	auditMW := NewLoggingMiddleware(func(toolName string, input *Input) {
+		mu.Lock()
+		defer mu.Unlock()
+		audit = append(audit, auditEntry{toolName, input.Metadata["row_id"], input.Metadata["source"]})
+	}, nil)
+
+	exec := NewExecutor(registry, WithMaxConcurrent(1), WithMiddleware(auditMW))
+	shared := NewInput().WithName("first").WithMetadata("source", "import").WithMetadata("row_id", "input")
+	results, err := exec.ExecuteMany(context.Background(), []ToolExecution{
+		{ToolName: "record", Input: shared, Metadata: map[string]string{"row_id": "1"}},
+		{ToolName: "record", Input: NewInput().WithName("second"), Metadata: map[string]string{"row_id": "2"}},
+		{ToolName: "record", Input: NewInput().WithName("third")},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteMany() error = %v", err)
+	}
+	for i, result := range results {
+		if result.Error != nil {
+			t.Errorf("result %d error = %v", i, result.Error)
+		}
+	}
+
+	if got := seen["first"]; got["row_id"] != "1" || got["source"] != "import" {
+		t.Errorf("first input metadata = %v, want row_id=1 source=import", got)
+	}
+	if got := seen["second"]; got["row_id"] != "2" {
+		t.Errorf("second input metadata = %v, want row_id=2", got)
+	}
+	if got := seen["third"]; len(got) != 0 {
+		t.Errorf("third input metadata = %v, want none", got)
+	}
+	if shared.Metadata["row_id"] != "input" {
+		t.Errorf("caller input was modified: %v", shared.Metadata)
+	}
+
+	want := []auditEntry{{"record", "1", "import"}, {"record", "2", ""}, {"record", "", ""}}
+	if len(audit) != len(want) {
+		t.Fatalf("audit entries = %v, want %v", audit, want)
+	}
+	for i := range want {
+		if audit[i] != want[i] {
+			t.Errorf("audit entry %d = %+v, want %+v", i, audit[i], want[i])
+		}
+	}
+}
+
+func TestExecutor_ExecuteMany_MetadataWithNilInput(t *testing.T) {
+	registry := NewRegistry()
+	var got map[string]string
+	tool := NewMockTool("record", "Records its input metadata").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			got = input.Metadata
+			return NewOutput(), nil
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+
+	exec := NewExecutor(registry)
+	if _, err := exec.ExecuteMany(context.Background(), []ToolExecution{
+		{ToolName: "record", Metadata: map[string]string{"row_id": "7"}},
+	}); err != nil {
+		t.Fatalf("ExecuteMany() error = %v", err)
+	}
+	if got["row_id"] != "7" {
+		t.Errorf("metadata = %v, want row_id=7", got)
+	}
+}
+
+// countingTool returns a tool that records the peak number of its
+// executions running at once.
+func countingTool(name string, peak *int32) *MockTool {
+	var running int32
+	return NewMockTool(name, "Counts concurrent runs").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				old := atomic.LoadInt32(peak)
+				if current <= old || atomic.CompareAndSwapInt32(peak, old, current) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			return NewOutput(), nil
+		},
+	)
+}
+
+// TestExecutor_ToolConcurrency tests per-tool concurrency limits in ExecuteMany.
+func TestExecutor_ToolConcurrency(t *testing.T) {
+	t.Run("limits a single tool", func(t *testing.T) {
+		var bashPeak, otherPeak int32
+		registry := NewRegistryWithOptions(WithTools(
+			countingTool("bash", &bashPeak),
+			countingTool("other", &otherPeak),
+		))
+
+		exec := NewExecutor(registry,
+			WithUnlimitedConcurrency(),
+			WithToolConcurrency(map[string]int{"bash": 2}),
+		)
+
+		var executions []ToolExecution
+		for i := 0; i < 10; i++ {
+			executions = append(executions,
+				ToolExecution{ToolName: "bash", Input: NewInput()},
+				ToolExecution{ToolName: "other", Input: NewInput()},
+			)
+		}
+
+		results, err := exec.ExecuteMany(context.Background(), executions)
+		if err != nil {
+			t.Fatalf("ExecuteMany() unexpected error: %v", err)
+		}
+		for i, result := range results {
+			if result.Error != nil {
+				t.Errorf("Result[%d] error: %v", i, result.Error)
+			}
+		}
+
+		if bashPeak > 2 {
+			t.Errorf("bash peak concurrency = %d, want <= 2", bashPeak)
+		}
+		if otherPeak <= 2 {
+			t.Errorf("other peak concurrency = %d, want unlimited tool to exceed 2", otherPeak)
+		}
+	})
+
+	t.Run("global cap still applies", func(t *testing.T) {
+		var peak int32
+		registry := NewRegistryWithOptions(WithTools(countingTool("bash", &peak)))
+		exec := NewExecutor(registry,
+			WithMaxConcurrent(1),
+			WithToolConcurrency(map[string]int{"bash": 5}),
+		)
+
+		executions := make([]ToolExecution, 5)
+		for i := range executions {
+			executions[i] = ToolExecution{ToolName: "bash", Input: NewInput()}
+		}
+		if _, err := exec.ExecuteMany(context.Background(), executions); err != nil {
+			t.Fatalf("ExecuteMany() unexpected error: %v", err)
+		}
+		if peak != 1 {
+			t.Errorf("peak concurrency = %d, want 1", peak)
+		}
+	})
+
+	t.Run("cancelled while waiting for a slot", func(t *testing.T) {
+		release := make(chan struct{})
+		started := make(chan struct{}, 2)
+		tool := NewMockTool("slow", "Blocks").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				started <- struct{}{}
+				<-release
+				return NewOutput(), nil
+			},
+		)
+		registry := NewRegistryWithOptions(WithTools(tool))
+		exec := NewExecutor(registry,
+			WithUnlimitedConcurrency(),
+			WithNoTimeout(),
+			WithToolConcurrency(map[string]int{"slow": 1}),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan []*Result)
+		go func() {
+			results, _ := exec.ExecuteMany(ctx, []ToolExecution{
+				{ToolName: "slow", Input: NewInput()},
+				{ToolName: "slow", Input: NewInput()},
+			})
+			done <- results
+		}()
+
+		<-started
+		cancel()
+		close(release)
+		results := <-done
+
+		if len(started) != 0 {
+			t.Error("second execution should not start after cancellation")
+		}
+		failed := 0
+		for _, result := range results {
+			if result.Error != nil {
+				failed++
+			}
+		}
+		if failed != 1 {
+			t.Errorf("expected the waiting execution to fail, got %d failures", failed)
+		}
+	})
+
+	t.Run("slots released after a panic", func(t *testing.T) {
+		tool := NewMockTool("boom", "Panics").WithExecuteFunc(
+			func(ctx context.Context, input *Input) (*Output, error) {
+				panic("boom")
+			},
+		)
+		registry := NewRegistryWithOptions(WithTools(tool))
+		exec := NewExecutor(registry,
+			WithMaxConcurrent(1),
+			WithToolConcurrency(map[string]int{"boom": 1}),
+		)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 3; i++ {
+				_, _ = exec.ExecuteMany(context.Background(), []ToolExecution{
+					{ToolName: "boom", Input: NewInput()},
+					{ToolName: "boom", Input: NewInput()},
+				})
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("ExecuteMany blocked: a panicking execution leaked its slots")
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		exec := NewExecutor(NewRegistry(),
+			WithToolConcurrency(map[string]int{"bash": 2, "search": 3}),
+			WithToolConcurrency(map[string]int{"search": 0}),
+		)
+		got := exec.Config().ToolConcurrency
+		if len(got) != 1 || got["bash"] != 2 {
+			t.Errorf("Config.ToolConcurrency = %v, want map[bash:2]", got)
+		}
+	})
+}
+
 // TestExecutor_ConcurrentAccess tests concurrent access to executor methods.
 func TestExecutor_ConcurrentAccess(t *testing.T) {
 	registry := NewRegistry()
@@ -853,6 +1358,7 @@ func TestExecutor_Config(t *testing.T) {
 		WithMaxConcurrent(4),
 		WithRecoverPanics(false),
 		WithDefaultMiddleware(),
+		WithFailFast(true),
 	)
 
 	config := exec.Config()
@@ -869,6 +1375,10 @@ func TestExecutor_Config(t *testing.T) {
 		t.Error("Config.RecoverPanics = true, want false")
 	}
 
+	if !config.FailFast {
+		t.Error("Config.FailFast = false, want true")
+	}
+
 	if !config.HasMiddleware {
 		t.Error("Config.HasMiddleware = false, want true")
 	}
@@ -924,6 +1434,97 @@ func TestExecutor_GetMiddlewareChain(t *testing.T) {
 	})
 }
 
+// closeableMiddleware is a pass-through middleware that records Close calls.
+type closeableMiddleware struct {
+	name   string
+	err    error
+	closed int
+}
+
+func (m *closeableMiddleware) Name() string                { return m.name }
+func (m *closeableMiddleware) Wrap(next ToolFunc) ToolFunc { return next }
+
+func (m *closeableMiddleware) Close() error {
+	m.closed++
+	return m.err
+}
+
+// closeablePolicy is an allow-all security policy that records Close calls.
+type closeablePolicy struct {
+	closed int
+}
+
+func (p *closeablePolicy) Validate(ctx context.Context, toolName string, args map[string]any) error {
+	return nil
+}
+
+func (p *closeablePolicy) Close() error {
+	p.closed++
+	return nil
+}
+
+// TestExecutor_Close tests that Close propagates to closeable components.
+func TestExecutor_Close(t *testing.T) {
+	t.Run("closes closeable middleware and policy", func(t *testing.T) {
+		audit := &closeableMiddleware{name: "audit"}
+		cache := &closeableMiddleware{name: "cache"}
+		policy := &closeablePolicy{}
+		exec := NewExecutor(NewRegistry(),
+			WithMiddleware(audit),
+			WithMiddleware(NewTimingMiddleware()),
+			WithMiddleware(cache),
+			WithSecurityPolicy(policy),
+		)
+
+		if err := exec.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		if audit.closed != 1 || cache.closed != 1 || policy.closed != 1 {
+			t.Errorf("closed counts = %d, %d, %d, want 1 each", audit.closed, cache.closed, policy.closed)
+		}
+	})
+
+	t.Run("safe to call twice", func(t *testing.T) {
+		mw := &closeableMiddleware{name: "audit"}
+		exec := NewExecutor(NewRegistry(), WithMiddleware(mw))
+
+		if err := exec.Close(); err != nil {
+			t.Fatalf("first Close() error = %v", err)
+		}
+		if err := exec.Close(); err != nil {
+			t.Fatalf("second Close() error = %v", err)
+		}
+		if mw.closed != 1 {
+			t.Errorf("middleware closed %d times, want 1", mw.closed)
+		}
+	})
+
+	t.Run("closes all and joins errors", func(t *testing.T) {
+		errA, errB := errors.New("flush failed"), errors.New("connection lost")
+		a := &closeableMiddleware{name: "a", err: errA}
+		b := &closeableMiddleware{name: "b", err: errB}
+		exec := NewExecutor(NewRegistry(), WithMiddleware(a), WithMiddleware(b))
+
+		err := exec.Close()
+		if !errors.Is(err, errA) || !errors.Is(err, errB) {
+			t.Errorf("Close() error = %v, want both errors", err)
+		}
+		if b.closed != 1 {
+			t.Error("a failing Close should not stop the others")
+		}
+		if again := exec.Close(); again != err {
+			t.Errorf("second Close() = %v, want the first error %v", again, err)
+		}
+	})
+
+	t.Run("no closeable components", func(t *testing.T) {
+		exec := NewExecutor(NewRegistry(), WithDefaultMiddleware())
+		if err := exec.Close(); err != nil {
+			t.Errorf("Close() error = %v, want nil", err)
+		}
+	})
+}
+
 // TestExecutorOption_CombineOptions tests the CombineOptions function.
 func TestExecutorOption_CombineOptions(t *testing.T) {
 	defaults := DefaultExecutorOptions()
@@ -1278,3 +1879,194 @@ func TestNilConfirmationHandler(t *testing.T) {
 		}
 	})
 }
+
+// TestTrustedTools tests that trusted tools skip the confirmation handler.
+func TestTrustedTools(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"list_dir", "bash"} {
+		tool := NewMockTool(name, "A tool").
+			WithRequiresConfirmation(true).
+			WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+				return NewOutput().WithMessage("executed"), nil
+			})
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("Failed to register tool: %v", err)
+		}
+	}
+
+	exec := NewExecutor(registry,
+		WithConfirmationHandler(&AutoDenyHandler{}),
+		WithTrustedTools("list_dir"),
+	)
+
+	t.Run("trusted tool runs without confirmation", func(t *testing.T) {
+		output, err := exec.Execute(context.Background(), "list_dir", NewInput())
+		if err != nil {
+			t.Fatalf("Execute() unexpected error: %v", err)
+		}
+		if output == nil || output.Message != "executed" {
+			t.Errorf("Execute() output = %+v, want executed", output)
+		}
+	})
+
+	t.Run("untrusted tool still asks for confirmation", func(t *testing.T) {
+		_, err := exec.Execute(context.Background(), "bash", NewInput())
+		if !errors.Is(err, ErrUserDenied) {
+			t.Errorf("Execute() error = %v, want ErrUserDenied", err)
+		}
+	})
+
+	t.Run("config lists trusted tools", func(t *testing.T) {
+		config := exec.Config()
+		if len(config.TrustedTools) != 1 || config.TrustedTools[0] != "list_dir" {
+			t.Errorf("Config.TrustedTools = %v, want [list_dir]", config.TrustedTools)
+		}
+	})
+}
+
+// TestExecutor_OutputTransformer tests output transformers applied by the
+// executor after execution.
+func TestExecutor_OutputTransformer(t *testing.T) {
+	const secret = "ghp_abcdef123456"
+	redact := func(toolName string, out *Output) *Output {
+		out.Data = []byte(strings.ReplaceAll(string(out.Data), secret, EnvRedacted))
+		out.Message = strings.ReplaceAll(out.Message, secret, EnvRedacted)
+		return out
+	}
+
+	registry := NewRegistry()
+	tools := []*MockTool{
+		NewMockTool("cat", "Prints a file").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+			return NewOutput().WithData([]byte("token=" + secret + "\n")), nil
+		}),
+		NewMockTool("env", "Describes the environment").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+			return NewOutput().WithMessage("GITHUB_TOKEN is " + secret), nil
+		}),
+		NewMockTool("fail", "Always fails").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+			return nil, errors.New("failed with " + secret)
+		}),
+		NewMockTool("exit1", "Fails with output").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+			return NewOutput().WithData([]byte("token=" + secret + "\n")), errors.New("exit status 1")
+		}),
+	}
+	for _, tool := range tools {
+		if err := registry.Register(tool); err != nil {
+			t.Fatalf("Failed to register tool: %v", err)
+		}
+	}
+
+	t.Run("redacts output of every tool", func(t *testing.T) {
+		exec := NewExecutor(registry, WithOutputTransformer(redact))
+
+		out, err := exec.Execute(context.Background(), "cat", NewInput())
+		if err != nil {
+			t.Fatalf("Execute(cat) error = %v", err)
+		}
+		if string(out.Data) != "token="+EnvRedacted+"\n" {
+			t.Errorf("cat data = %q", out.Data)
+		}
+
+		out, err = exec.Execute(context.Background(), "env", NewInput())
+		if err != nil {
+			t.Fatalf("Execute(env) error = %v", err)
+		}
+		if out.Message != "GITHUB_TOKEN is "+EnvRedacted {
+			t.Errorf("env message = %q", out.Message)
+		}
+	})
+
+	t.Run("transformers chain in registration order", func(t *testing.T) {
+		var order []string
+		exec := NewExecutor(registry,
+			WithOutputTransformer(redact),
+			WithOutputTransformer(func(toolName string, out *Output) *Output {
+				order = append(order, toolName)
+				if strings.Contains(string(out.Data), secret) {
+					t.Error("second transformer should see redacted data")
+				}
+				return NewOutput().WithData(append([]byte("["+toolName+"] "), out.Data...))
+			}),
+			WithOutputTransformer(nil),
+			WithOutputTransformer(func(toolName string, out *Output) *Output {
+				return nil // keeps the previous output
+			}),
+		)
+
+		out, err := exec.Execute(context.Background(), "cat", NewInput())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if string(out.Data) != "[cat] token="+EnvRedacted+"\n" {
+			t.Errorf("data = %q", out.Data)
+		}
+		if len(order) != 1 || order[0] != "cat" {
+			t.Errorf("transformer calls = %v", order)
+		}
+		if n := exec.Config().OutputTransformerCount; n != 3 {
+			t.Errorf("OutputTransformerCount = %d, want 3", n)
+		}
+	})
+
+	t.Run("applies after truncation", func(t *testing.T) {
+		reg := NewRegistry()
+		_ = reg.Register(NewMockTool("big", "Large output").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+			return NewOutput().WithTruncatedData([]byte(secret+strings.Repeat("x", 100)), len(secret)), nil
+		}))
+		var sawTruncated bool
+		exec := NewExecutor(reg, WithOutputTransformer(func(toolName string, out *Output) *Output {
+			sawTruncated = out.Truncated
+			return redact(toolName, out)
+		}))
+
+		out, err := exec.Execute(context.Background(), "big", NewInput())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !sawTruncated || string(out.Data) != EnvRedacted {
+			t.Errorf("truncated = %v, data = %q", sawTruncated, out.Data)
+		}
+	})
+
+	t.Run("not applied without output", func(t *testing.T) {
+		called := false
+		exec := NewExecutor(registry, WithOutputTransformer(func(toolName string, out *Output) *Output {
+			called = true
+			return out
+		}))
+
+		if _, err := exec.Execute(context.Background(), "fail", NewInput()); err == nil {
+			t.Fatal("expected error")
+		}
+		if called {
+			t.Error("transformer should not run for failed executions without output")
+		}
+	})
+
+	t.Run("redacts output returned with an error", func(t *testing.T) {
+		exec := NewExecutor(registry, WithOutputTransformer(redact))
+
+		out, err := exec.Execute(context.Background(), "exit1", NewInput())
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if out == nil || string(out.Data) != "token="+EnvRedacted+"\n" {
+			t.Errorf("failing command output not redacted: %+v", out)
+		}
+	})
+
+	t.Run("applies to batch executions", func(t *testing.T) {
+		exec := NewExecutor(registry, WithOutputTransformer(redact))
+		results, err := exec.ExecuteMany(context.Background(), []ToolExecution{
+			{ToolName: "cat", Input: NewInput()},
+			{ToolName: "env", Input: NewInput()},
+		})
+		if err != nil {
+			t.Fatalf("ExecuteMany() error = %v", err)
+		}
+		for _, result := range results {
+			if strings.Contains(string(result.Output.Data)+result.Output.Message, secret) {
+				t.Errorf("%s output not redacted: %+v", result.ToolName, result.Output)
+			}
+		}
+	})
+}
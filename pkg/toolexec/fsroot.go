@@ -0,0 +1,241 @@
+// Package toolexec provides a modular, extensible tool executor architecture.
+// This file implements the filesystem root ("jail") used by the executor to
+// confine the path arguments of filesystem tools to a single directory tree.
+package toolexec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// filesystemToolSpec describes how a filesystem tool receives paths.
+type filesystemToolSpec struct {
+	// pathParams are the parameter names that carry filesystem paths.
+	pathParams []string
+
+	// defaultToRoot injects the root when the path parameter is absent,
+	// for tools whose path is optional (e.g. search defaults to ".").
+	defaultToRoot bool
+
+	// listParams maps array parameters whose elements are objects to the
+	// element keys that carry paths (e.g. multi_file_write's files[].path).
+	listParams map[string][]string
+}
+
+// filesystemTools lists the built-in tools whose path arguments are confined
+// when a filesystem root is configured.
+var filesystemTools = map[string]filesystemToolSpec{
+	"file_read":        {pathParams: []string{"path"}},
+	"file_write":       {pathParams: []string{"path"}},
+	"file_edit":        {pathParams: []string{"path"}},
+	"multi_file_write": {listParams: map[string][]string{"files": {"path"}}},
+	"search":           {pathParams: []string{"path"}, defaultToRoot: true},
+}
+
+// filesystemRoot confines filesystem tool paths to a directory tree.
+// Relative paths are resolved against the root; absolute paths must already
+// be inside it. Symlinks are followed and must not point outside the root.
+type filesystemRoot struct {
+	path string
+}
+
+// newFilesystemRoot creates a filesystemRoot for the given directory.
+// Returns nil if path is empty, which disables confinement.
+func newFilesystemRoot(path string) *filesystemRoot {
+	if strings.TrimSpace(path) == "" {
+		return nil
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return &filesystemRoot{path: filepath.Clean(path)}
+}
+
+// confine returns an Input whose path parameters and file references have
+// been resolved inside the root. The original input is never modified; a
+// shallow copy with new Params and Files is returned when any path is
+// rewritten. File references are confined for every tool, path parameters
+// only for filesystem tools.
+func (r *filesystemRoot) confine(toolName string, input *Input) (*Input, error) {
+	files, err := r.confineFiles(toolName, input)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, ok := filesystemTools[toolName]
+	if !ok {
+		if files == nil {
+			return input, nil
+		}
+		confined := *input
+		confined.Files = files
+		return &confined, nil
+	}
+
+	params := make(map[string]any)
+	if input != nil {
+		for k, v := range input.Params {
+			params[k] = v
+		}
+	}
+
+	for _, key := range spec.pathParams {
+		raw, exists := params[key]
+		path, isString := raw.(string)
+		if !exists || (isString && strings.TrimSpace(path) == "") {
+			if spec.defaultToRoot {
+				params[key] = r.path
+			}
+			continue
+		}
+		if !isString {
+			// Wrong type - let the tool's own validation report it
+			continue
+		}
+
+		resolved, err := r.resolve(toolName, path)
+		if err != nil {
+			return nil, err
+		}
+		params[key] = resolved
+	}
+
+	for key, elemKeys := range spec.listParams {
+		items, ok := params[key].([]any)
+		if !ok {
+			// Missing or wrong type - let the tool's own validation report it
+			continue
+		}
+
+		confinedItems := make([]any, len(items))
+		for i, item := range items {
+			obj, isObj := item.(map[string]any)
+			if !isObj {
+				confinedItems[i] = item
+				continue
+			}
+			copied := make(map[string]any, len(obj))
+			for k, v := range obj {
+				copied[k] = v
+			}
+			for _, elemKey := range elemKeys {
+				path, isString := copied[elemKey].(string)
+				if !isString || strings.TrimSpace(path) == "" {
+					continue
+				}
+				resolved, err := r.resolve(toolName, path)
+				if err != nil {
+					return nil, err
+				}
+				copied[elemKey] = resolved
+			}
+			confinedItems[i] = copied
+		}
+		params[key] = confinedItems
+	}
+
+	confined := &Input{Params: params}
+	if input != nil {
+		confined.Name = input.Name
+		confined.Data = input.Data
+		confined.Metadata = input.Metadata
+		confined.Files = input.Files
+	}
+	if files != nil {
+		confined.Files = files
+	}
+	return confined, nil
+}
+
+// confineFiles resolves the Path of each file reference inside the root.
+// It returns nil if the input has no file references with a path.
+func (r *filesystemRoot) confineFiles(toolName string, input *Input) ([]FileRef, error) {
+	if input == nil {
+		return nil, nil
+	}
+	var files []FileRef
+	for i, file := range input.Files {
+		if strings.TrimSpace(file.Path) == "" {
+			continue
+		}
+		resolved, err := r.resolve(toolName, file.Path)
+		if err != nil {
+			return nil, err
+		}
+		if files == nil {
+			files = append([]FileRef(nil), input.Files...)
+		}
+		files[i].Path = resolved
+	}
+	return files, nil
+}
+
+// resolve converts path to an absolute path inside the root.
+// Returns a SecurityViolationError if the path, or the target of any symlink
+// along it, lies outside the root.
+func (r *filesystemRoot) resolve(toolName, path string) (string, error) {
+	realRoot := r.path
+	if evaluated, err := filepath.EvalSymlinks(r.path); err == nil {
+		realRoot = evaluated
+	}
+
+	target := path
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(r.path, target)
+	}
+	target = filepath.Clean(target)
+
+	if !pathWithin(r.path, target) && !pathWithin(realRoot, target) {
+		return "", NewSecurityViolationErrorWithPath(
+			toolName,
+			"path is outside the filesystem root",
+			path,
+		)
+	}
+
+	if !pathWithin(realRoot, evalExistingSymlinks(target)) {
+		return "", NewSecurityViolationErrorWithPath(
+			toolName,
+			"symlink escapes the filesystem root",
+			path,
+		)
+	}
+
+	return target, nil
+}
+
+// pathWithin reports whether target is root or a descendant of root.
+// Both paths must be absolute and clean.
+func pathWithin(root, target string) bool {
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// evalExistingSymlinks resolves symlinks in the longest existing prefix of
+// path and appends the non-existent remainder unchanged. This allows
+// validating destinations that do not exist yet (e.g. file_write targets).
+func evalExistingSymlinks(path string) string {
+	existing := path
+	var rest []string
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path
+		}
+		rest = append([]string{filepath.Base(existing)}, rest...)
+		existing = parent
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return path
+	}
+	return filepath.Join(append([]string{resolved}, rest...)...)
+}
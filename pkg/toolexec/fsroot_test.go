@@ -0,0 +1,245 @@
+package toolexec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newRootedExecutor(t *testing.T, root string) *executor {
+	t.Helper()
+	registry := NewRegistryWithOptions(WithTools(
+		NewFileReadTool(),
+		NewFileWriteTool(),
+		NewSearchTool(),
+	))
+	return NewExecutor(registry, WithFilesystemRoot(root))
+}
+
+func TestWithFilesystemRoot_AllowsPathsInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "a.txt"), []byte("inside\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	exec := newRootedExecutor(t, root)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"relative path", "sub/a.txt"},
+		{"relative path with inner dotdot", "sub/../sub/a.txt"},
+		{"absolute path inside root", filepath.Join(root, "sub", "a.txt")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := exec.Execute(ctx, "file_read", NewInput().WithParam("path", tt.path))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if string(output.Data) != "inside\n" {
+				t.Errorf("unexpected output: %q", string(output.Data))
+			}
+		})
+	}
+}
+
+func TestWithFilesystemRoot_WriteResolvesRelativeToRoot(t *testing.T) {
+	root := t.TempDir()
+	exec := newRootedExecutor(t, root)
+
+	input := NewInput().WithParam("path", "new/out.txt").WithParam("content", "data")
+	if _, err := exec.Execute(context.Background(), "file_write", input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, "new", "out.txt"))
+	if err != nil {
+		t.Fatalf("expected file inside root: %v", err)
+	}
+	if string(data) != "data" {
+		t.Errorf("unexpected content: %q", string(data))
+	}
+	if input.GetParamString("path") != "new/out.txt" {
+		t.Error("caller's input should not be modified")
+	}
+}
+
+func TestWithFilesystemRoot_RejectsEscapes(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	outside := filepath.Join(parent, "outside.txt")
+	if err := os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	exec := newRootedExecutor(t, root)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		toolName string
+		input    *Input
+	}{
+		{"dotdot escape", "file_read", NewInput().WithParam("path", "../outside.txt")},
+		{"nested dotdot escape", "file_read", NewInput().WithParam("path", "a/../../outside.txt")},
+		{"absolute path outside root", "file_read", NewInput().WithParam("path", outside)},
+		{"write outside root", "file_write", NewInput().WithParam("path", "../evil.txt").WithParam("content", "x")},
+		{"search outside root", "search", NewInput().WithParam("pattern", "secret").WithParam("path", parent)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := exec.Execute(ctx, tt.toolName, tt.input)
+			if !IsSecurityViolationError(err) {
+				t.Fatalf("expected SecurityViolationError, got %v", err)
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(parent, "evil.txt")); !os.IsNotExist(err) {
+		t.Error("file outside root should not have been written")
+	}
+}
+
+func TestWithFilesystemRoot_RejectsSymlinkEscape(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	outsideDir := filepath.Join(parent, "outside")
+	for _, dir := range []string{root, outsideDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("MkdirAll() error = %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outsideDir, "secret.txt"), []byte("secret"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.Symlink(outsideDir, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	exec := newRootedExecutor(t, root)
+	ctx := context.Background()
+
+	_, err := exec.Execute(ctx, "file_read", NewInput().WithParam("path", "link/secret.txt"))
+	if !IsSecurityViolationError(err) {
+		t.Fatalf("expected SecurityViolationError for symlinked read, got %v", err)
+	}
+
+	input := NewInput().WithParam("path", "link/new.txt").WithParam("content", "x")
+	_, err = exec.Execute(ctx, "file_write", input)
+	if !IsSecurityViolationError(err) {
+		t.Fatalf("expected SecurityViolationError for symlinked write, got %v", err)
+	}
+}
+
+func TestWithFilesystemRoot_SearchDefaultsToRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("needle\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	exec := newRootedExecutor(t, root)
+	output, err := exec.Execute(context.Background(), "search", NewInput().WithParam("pattern", "needle"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(string(output.Data), "a.txt:1:needle") {
+		t.Errorf("unexpected output: %q", string(output.Data))
+	}
+}
+
+func TestWithFilesystemRoot_IgnoresOtherTools(t *testing.T) {
+	registry := NewRegistry()
+	var gotPath string
+	mock := NewMockTool("custom", "custom tool").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+		gotPath = input.GetParamString("path")
+		return NewOutput(), nil
+	})
+	if err := registry.Register(mock); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	exec := NewExecutor(registry, WithFilesystemRoot(t.TempDir()))
+	if _, err := exec.Execute(context.Background(), "custom", NewInput().WithParam("path", "/etc/hosts")); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if gotPath != "/etc/hosts" {
+		t.Errorf("non-filesystem tool params should be untouched, got %q", gotPath)
+	}
+}
+
+func TestWithFilesystemRoot_ConfinesFiles(t *testing.T) {
+	root := t.TempDir()
+	registry := NewRegistry()
+	var gotFiles []FileRef
+	mock := NewMockTool("custom", "custom tool").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+		gotFiles = input.GetFiles()
+		return NewOutput(), nil
+	})
+	if err := registry.Register(mock); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	exec := NewExecutor(registry, WithFilesystemRoot(root))
+	ctx := context.Background()
+
+	input := NewInput().WithFiles(
+		FileRef{Name: "notes.txt", Path: "sub/notes.txt"},
+		FileRef{Name: "upload", ID: "file-1"},
+	)
+	if _, err := exec.Execute(ctx, "custom", input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(gotFiles) != 2 {
+		t.Fatalf("files should reach the tool, got %+v", gotFiles)
+	}
+	if want := filepath.Join(root, "sub", "notes.txt"); gotFiles[0].Path != want {
+		t.Errorf("file path = %q, want %q", gotFiles[0].Path, want)
+	}
+	if gotFiles[1].ID != "file-1" || gotFiles[1].Path != "" {
+		t.Errorf("a file without a path should pass unchanged, got %+v", gotFiles[1])
+	}
+	if input.Files[0].Path != "sub/notes.txt" {
+		t.Error("the caller's input should not be modified")
+	}
+
+	// Filesystem tools keep their files too
+	read := NewInput().WithParam("path", "a.txt").WithFiles(FileRef{Name: "ctx"})
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	confined, err := exec.config.filesystemRoot.confine("file_read", read)
+	if err != nil || len(confined.Files) != 1 {
+		t.Errorf("confine() = %+v, %v; files should be kept", confined, err)
+	}
+
+	escape := NewInput().WithFiles(FileRef{Name: "passwd", Path: "/etc/passwd"})
+	_, err = exec.Execute(ctx, "custom", escape)
+	if !IsSecurityViolationError(err) {
+		t.Errorf("a file outside the root should be rejected, got %v", err)
+	}
+}
+
+func TestWithFilesystemRoot_Config(t *testing.T) {
+	root := t.TempDir()
+	exec := NewExecutor(NewRegistry(), WithFilesystemRoot(root))
+	if exec.Config().FilesystemRoot != root {
+		t.Errorf("FilesystemRoot = %q, want %q", exec.Config().FilesystemRoot, root)
+	}
+
+	disabled := NewExecutor(NewRegistry(), WithFilesystemRoot(""))
+	if disabled.Config().FilesystemRoot != "" {
+		t.Error("empty root should disable confinement")
+	}
+}
@@ -3,7 +3,10 @@
 // allowing flexible, backward-compatible configuration of executor behavior.
 package toolexec
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // ExecutorOption is a function that configures an executorConfig.
 // Use these options with NewExecutor to customize executor behavior.
@@ -52,6 +55,36 @@ func WithMaxConcurrent(n int) ExecutorOption {
 	}
 }
 
+// WithToolConcurrency sets per-tool limits on concurrent executions in
+// batch operations (ExecuteMany), keyed by tool name. Each limited tool gets
+// its own semaphore, shared by all batches run on the executor, in addition
+// to the global WithMaxConcurrent cap. Limits of zero or less are ignored,
+// leaving the tool bound only by the global cap.
+//
+// Multiple WithToolConcurrency options are merged; a later limit for the
+// same tool replaces the earlier one.
+//
+// Example:
+//
+//	executor := NewExecutor(registry,
+//	    WithUnlimitedConcurrency(),
+//	    WithToolConcurrency(map[string]int{"bash": 2}),
+//	)
+func WithToolConcurrency(limits map[string]int) ExecutorOption {
+	return func(c *executorConfig) {
+		for name, n := range limits {
+			if c.toolConcurrency == nil {
+				c.toolConcurrency = make(map[string]int, len(limits))
+			}
+			if n <= 0 {
+				delete(c.toolConcurrency, name)
+				continue
+			}
+			c.toolConcurrency[name] = n
+		}
+	}
+}
+
 // WithRecoverPanics sets whether the executor should recover from panics
 // during tool execution. When enabled, panics are converted to PanicError
 // with stack traces instead of propagating up the call stack.
@@ -68,6 +101,24 @@ func WithRecoverPanics(enabled bool) ExecutorOption {
 	}
 }
 
+// WithPanicInput sets whether recovered panics carry the input that
+// triggered them. When enabled, the PanicError returned for a panicking
+// tool has Input set to a sanitized copy of the tool input: params and
+// metadata with secret-looking values redacted, and the size of the raw
+// data instead of its content. It has no effect when panic recovery is
+// disabled.
+//
+// Default: false
+//
+// Example:
+//
+//	executor := NewExecutor(registry, WithPanicInput(true))
+func WithPanicInput(enabled bool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.panicInput = enabled
+	}
+}
+
 // WithMiddleware adds a middleware to the executor's middleware chain.
 // Middlewares are applied in the order they are added, with the first
 // middleware being the outermost wrapper (executed first for pre-processing,
@@ -193,6 +244,30 @@ func WithConfirmationHandler(handler ConfirmationHandler) ExecutorOption {
 	}
 }
 
+// WithTrustedTools marks tools that never request confirmation, even when
+// a confirmation handler is configured and the tool's RequiresConfirmation
+// returns true. This suits read-only tools such as list_dir.
+//
+// Example:
+//
+//	executor := NewExecutor(registry,
+//	    WithConfirmationHandler(handler),
+//	    WithTrustedTools("list_dir", "file_read"),
+//	)
+func WithTrustedTools(names ...string) ExecutorOption {
+	return func(c *executorConfig) {
+		if len(names) == 0 {
+			return
+		}
+		if c.trustedTools == nil {
+			c.trustedTools = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			c.trustedTools[name] = true
+		}
+	}
+}
+
 // WithDefaultSecurityPolicy sets the executor to use the default security
 // policy which includes blacklist and path validation.
 //
@@ -205,6 +280,86 @@ func WithDefaultSecurityPolicy() ExecutorOption {
 	}
 }
 
+// WithFilesystemRoot confines the filesystem tools (file_read, file_write,
+// file_edit, multi_file_write and search) to the given directory tree.
+// Relative path arguments are resolved against the root, and any path that
+// escapes it - via "..", an absolute path elsewhere, or a symlink pointing
+// outside - is rejected with a SecurityViolationError before the tool runs.
+//
+// An empty path disables confinement.
+//
+// Example:
+//
+//	executor := NewExecutor(registry, WithFilesystemRoot("/srv/workspace"))
+func WithFilesystemRoot(path string) ExecutorOption {
+	return func(c *executorConfig) {
+		c.filesystemRoot = newFilesystemRoot(path)
+	}
+}
+
+// WithFailFast sets whether ExecuteMany stops at the first error. When
+// enabled, the first failing tool cancels the context shared by the batch:
+// tools already running observe the cancellation, tools not yet started are
+// skipped, and ExecuteMany returns the collected results together with the
+// triggering error.
+//
+// Default: false (every tool in the batch runs)
+//
+// Example:
+//
+//	executor := NewExecutor(registry, WithFailFast(true))
+func WithFailFast(enabled bool) ExecutorOption {
+	return func(c *executorConfig) {
+		c.failFast = enabled
+	}
+}
+
+// WithClock sets the clock the executor reads for timeouts and result
+// timing. A nil clock restores the real clock.
+//
+// Default: RealClock()
+//
+// Example:
+//
+//	executor := NewExecutor(registry, WithClock(fakeClock))
+func WithClock(clock Clock) ExecutorOption {
+	return func(c *executorConfig) {
+		if clock == nil {
+			clock = realClock{}
+		}
+		c.clock = clock
+	}
+}
+
+// OutputTransformer rewrites the output of a tool execution, e.g. to
+// scrub secrets from it. It may modify out in place or return a
+// replacement; returning nil keeps out.
+type OutputTransformer func(toolName string, out *Output) *Output
+
+// WithOutputTransformer adds a transformer applied to every output after
+// the tool (and any truncation it performs), middleware and panic recovery
+// have run, just before Execute returns. That includes output returned
+// along with an error, such as a failing command's; executions that return
+// no output are not transformed. Transformers run
+// in registration order, each receiving the previous one's result. A nil
+// transformer is ignored.
+//
+// Example:
+//
+//	executor := NewExecutor(registry, WithOutputTransformer(
+//	    func(toolName string, out *Output) *Output {
+//	        out.Data = tokenPattern.ReplaceAll(out.Data, []byte(EnvRedacted))
+//	        return out
+//	    },
+//	))
+func WithOutputTransformer(fn OutputTransformer) ExecutorOption {
+	return func(c *executorConfig) {
+		if fn != nil {
+			c.outputTransformers = append(c.outputTransformers, fn)
+		}
+	}
+}
+
 // applyOptions applies all options to the config.
 // This is an internal helper function.
 func applyOptions(config *executorConfig, opts ...ExecutorOption) {
@@ -227,6 +382,10 @@ type ExecutorConfig struct {
 	// RecoverPanics indicates whether panics are recovered.
 	RecoverPanics bool
 
+	// PanicInput indicates whether recovered panics carry the sanitized
+	// input.
+	PanicInput bool
+
 	// HasMiddleware indicates whether middleware is configured.
 	HasMiddleware bool
 
@@ -238,6 +397,24 @@ type ExecutorConfig struct {
 
 	// HasConfirmationHandler indicates whether a confirmation handler is configured.
 	HasConfirmationHandler bool
+
+	// TrustedTools lists the tools that skip confirmation, sorted by name.
+	TrustedTools []string
+
+	// FilesystemRoot is the directory filesystem tools are confined to.
+	// Empty when no root is configured.
+	FilesystemRoot string
+
+	// FailFast indicates whether ExecuteMany cancels the batch on the
+	// first error.
+	FailFast bool
+
+	// ToolConcurrency maps tool names to their concurrent execution limit.
+	// Nil when no per-tool limits are configured.
+	ToolConcurrency map[string]int
+
+	// OutputTransformerCount is the number of output transformers.
+	OutputTransformerCount int
 }
 
 // Config returns the executor's configuration for inspection.
@@ -247,8 +424,27 @@ func (e *executor) Config() ExecutorConfig {
 		Timeout:                e.config.timeout,
 		MaxConcurrent:          e.config.maxConcurrent,
 		RecoverPanics:          e.config.recoverPanics,
+		PanicInput:             e.config.panicInput,
 		HasSecurityPolicy:      e.config.securityPolicy != nil,
 		HasConfirmationHandler: e.config.confirmHandler != nil,
+		FailFast:               e.config.failFast,
+		OutputTransformerCount: len(e.config.outputTransformers),
+	}
+
+	for name := range e.config.trustedTools {
+		config.TrustedTools = append(config.TrustedTools, name)
+	}
+	sort.Strings(config.TrustedTools)
+
+	for name, n := range e.config.toolConcurrency {
+		if config.ToolConcurrency == nil {
+			config.ToolConcurrency = make(map[string]int, len(e.config.toolConcurrency))
+		}
+		config.ToolConcurrency[name] = n
+	}
+
+	if e.config.filesystemRoot != nil {
+		config.FilesystemRoot = e.config.filesystemRoot.path
 	}
 
 	if e.config.middlewareChain != nil {
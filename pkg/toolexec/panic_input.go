@@ -0,0 +1,76 @@
+package toolexec
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// newPanicInput returns a sanitized copy of input for a PanicError.
+// Params and metadata are deep-copied with values that look like secrets
+// (by key or by content) redacted; raw data is reduced to its size.
+func newPanicInput(input *Input) *PanicInput {
+	sanitized := &PanicInput{
+		Params:   make(map[string]any),
+		Metadata: make(map[string]string),
+	}
+	if input == nil {
+		return sanitized
+	}
+
+	sanitized.Name = input.Name
+	sanitized.DataSize = len(input.Data)
+	for key, value := range input.Params {
+		sanitized.Params[key] = redactPanicValue(key, value)
+	}
+	for key, value := range input.Metadata {
+		if isSecretEnv(key, value) {
+			value = EnvRedacted
+		}
+		sanitized.Metadata[key] = value
+	}
+	return sanitized
+}
+
+// redactPanicValue copies a param value, redacting it when its key names a
+// secret or a string inside it looks like one. Maps with string keys and
+// slices of any element type (map[string]string, []map[string]any, ...) are
+// copied recursively so keys deeper in the value are checked too. Other
+// values are checked in their formatted form.
+func redactPanicValue(key string, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return fmt.Sprintf("<%d bytes>", len(v))
+	case string:
+		if isSecretEnv(key, v) {
+			return EnvRedacted
+		}
+		return v
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			copied := make(map[string]any, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				k := iter.Key().String()
+				copied[k] = redactPanicValue(k, iter.Value().Interface())
+			}
+			return copied
+		}
+	case reflect.Slice, reflect.Array:
+		copied := make([]any, rv.Len())
+		for i := range copied {
+			copied[i] = redactPanicValue(key, rv.Index(i).Interface())
+		}
+		return copied
+	}
+
+	if isSecretEnv(key, fmt.Sprintf("%+v", value)) {
+		return EnvRedacted
+	}
+	return value
+}
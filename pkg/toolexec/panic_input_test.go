@@ -0,0 +1,141 @@
+package toolexec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newPanickingExecutor(t *testing.T, opts ...ExecutorOption) *executor {
+	t.Helper()
+	registry := NewRegistry()
+	tool := NewMockTool("panic-tool", "A tool that panics").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			panic("boom")
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+	return NewExecutor(registry, opts...)
+}
+
+func TestWithPanicInput_AttachesSanitizedInput(t *testing.T) {
+	exec := newPanickingExecutor(t, WithPanicInput(true))
+	input := NewInput().
+		WithName("repro").
+		WithParam("path", "/tmp/report.txt").
+		WithParam("api_key", "hunter2").
+		WithParam("count", 3).
+		WithParam("headers", map[string]any{"Authorization": "Bearer ya29.abcdefghij", "Accept": "text/plain"}).
+		WithParam("args", []any{"--verbose", "ghp_" + strings.Repeat("a", 36)}).
+		WithData([]byte("raw secret payload")).
+		WithMetadata("request_id", "r-1").
+		WithMetadata("session_token", "abc")
+
+	_, err := exec.Execute(context.Background(), "panic-tool", input)
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected PanicError, got %T: %v", err, err)
+	}
+	if panicErr.ToolName != "panic-tool" {
+		t.Errorf("ToolName = %q, want panic-tool", panicErr.ToolName)
+	}
+	got := panicErr.Input
+	if got == nil {
+		t.Fatal("PanicError.Input should be set")
+	}
+
+	if got.Name != "repro" || got.DataSize != len("raw secret payload") {
+		t.Errorf("Name = %q, DataSize = %d", got.Name, got.DataSize)
+	}
+	for _, key := range []string{"path", "api_key", "count", "headers", "args"} {
+		if _, ok := got.Params[key]; !ok {
+			t.Errorf("param %q is missing from %v", key, got.Params)
+		}
+	}
+	if got.Params["path"] != "/tmp/report.txt" || got.Params["count"] != 3 {
+		t.Errorf("plain params should be kept: %v", got.Params)
+	}
+	if got.Params["api_key"] != EnvRedacted {
+		t.Errorf("api_key = %v, want redacted", got.Params["api_key"])
+	}
+	headers := got.Params["headers"].(map[string]any)
+	if headers["Authorization"] != EnvRedacted || headers["Accept"] != "text/plain" {
+		t.Errorf("headers = %v", headers)
+	}
+	args := got.Params["args"].([]any)
+	if args[0] != "--verbose" || args[1] != EnvRedacted {
+		t.Errorf("args = %v", args)
+	}
+	if got.Metadata["request_id"] != "r-1" || got.Metadata["session_token"] != EnvRedacted {
+		t.Errorf("metadata = %v", got.Metadata)
+	}
+
+	msg := err.Error()
+	for _, secret := range []string{"hunter2", "ya29.", "ghp_", "raw secret payload"} {
+		if strings.Contains(msg, secret) {
+			t.Errorf("error message leaks %q:\n%s", secret, msg)
+		}
+	}
+	if !strings.Contains(msg, "Input: name=\"repro\"") || !strings.Contains(msg, "data=18 bytes") {
+		t.Errorf("error message should describe the input:\n%s", msg)
+	}
+
+	// The caller's input is not modified
+	if input.Params["api_key"] != "hunter2" {
+		t.Error("sanitizing should not modify the original input")
+	}
+}
+
+func TestWithPanicInput_DisabledByDefault(t *testing.T) {
+	exec := newPanickingExecutor(t)
+	_, err := exec.Execute(context.Background(), "panic-tool", NewInput().WithParam("api_key", "hunter2"))
+
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected PanicError, got %v", err)
+	}
+	if panicErr.Input != nil {
+		t.Errorf("Input = %v, want nil without WithPanicInput", panicErr.Input)
+	}
+	if exec.Config().PanicInput {
+		t.Error("Config().PanicInput should be false by default")
+	}
+}
+
+func TestNewPanicInput_Nil(t *testing.T) {
+	got := newPanicInput(nil)
+	if got.Params == nil || got.Metadata == nil || got.DataSize != 0 {
+		t.Errorf("unexpected sanitized nil input: %+v", got)
+	}
+}
+
+func TestRedactPanicValue_TypedContainers(t *testing.T) {
+	type credentials struct{ User, Token string }
+	value := map[string]any{
+		"env":     map[string]string{"GITHUB_TOKEN": "abc", "HOME": "/home/u"},
+		"headers": []map[string]string{{"Authorization": "Bearer ya29.abcdefghij"}},
+		"creds":   credentials{User: "u", Token: "ghp_" + strings.Repeat("a", 36)},
+		"ports":   []int{80, 443},
+	}
+
+	got := redactPanicValue("params", value).(map[string]any)
+
+	env := got["env"].(map[string]any)
+	if env["GITHUB_TOKEN"] != EnvRedacted || env["HOME"] != "/home/u" {
+		t.Errorf("env = %v", env)
+	}
+	headers := got["headers"].([]any)[0].(map[string]any)
+	if headers["Authorization"] != EnvRedacted {
+		t.Errorf("headers = %v", headers)
+	}
+	if got["creds"] != EnvRedacted {
+		t.Errorf("struct with a secret should be redacted, got %v", got["creds"])
+	}
+	if ports := got["ports"].([]any); ports[0] != 80 || ports[1] != 443 {
+		t.Errorf("ports = %v", ports)
+	}
+}
@@ -0,0 +1,58 @@
+package toolexec
+
+import (
+	"context"
+	"fmt"
+)
+
+// PreflightResult reports whether a tool execution would be accepted.
+type PreflightResult struct {
+	// Index is the position of the execution in the checked batch.
+	Index int
+
+	// OK is true if the execution passed every check.
+	OK bool
+
+	// Reason describes why the execution was rejected. Empty when OK.
+	Reason string
+}
+
+// Preflight checks a batch of tool executions without running any of them.
+// For each execution it performs the registry lookup, the input validation
+// middleware, and security validation (filesystem root and security policy),
+// stopping at the first failure. Confirmation is not requested and no
+// middleware other than input validation runs.
+func (e *executor) Preflight(ctx context.Context, executions []ToolExecution) []PreflightResult {
+	results := make([]PreflightResult, len(executions))
+	for i, exec := range executions {
+		results[i] = PreflightResult{Index: i, OK: true}
+		if err := e.preflight(ctx, exec); err != nil {
+			results[i].OK = false
+			results[i].Reason = err.Error()
+		}
+	}
+	return results
+}
+
+// preflight runs the pre-execution checks for a single execution, on the
+// input ExecuteMany would run (with exec.Metadata merged in).
+func (e *executor) preflight(ctx context.Context, exec ToolExecution) error {
+	if err := ctx.Err(); err != nil {
+		return e.wrapContextError(ctx, exec.ToolName)
+	}
+
+	if _, err := e.registry.Get(exec.ToolName); err != nil {
+		return fmt.Errorf("failed to get tool '%s': %w", exec.ToolName, err)
+	}
+
+	input := executionInput(exec)
+	validate := NewInputValidationMiddleware().Wrap(func(ctx context.Context, toolName string, input *Input) (*Output, error) {
+		return nil, nil
+	})
+	if _, err := validate(ctx, exec.ToolName, input); err != nil {
+		return err
+	}
+
+	_, err := e.validateSecurity(ctx, exec.ToolName, input)
+	return err
+}
@@ -0,0 +1,80 @@
+package toolexec
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestExecutor_Preflight(t *testing.T) {
+	registry := NewRegistry()
+	executed := false
+	tool := NewMockTool("bash", "A bash tool").WithExecuteFunc(func(ctx context.Context, input *Input) (*Output, error) {
+		executed = true
+		return NewOutput(), nil
+	})
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+	exec := NewExecutor(registry, WithSecurityPolicy(DefaultBlacklistValidator()))
+
+	results := exec.Preflight(context.Background(), []ToolExecution{
+		{ToolName: "bash", Input: NewInput().WithParam("command", "ls -la")},
+		{ToolName: "missing", Input: NewInput()},
+		{ToolName: "bash", Input: NewInput().WithParam("command", "rm -rf /")},
+		{ToolName: "bash", Input: nil},
+		{ToolName: "bash", Input: nil, Metadata: map[string]string{"row": "1"}},
+	})
+
+	if executed {
+		t.Error("Preflight() must not execute tools")
+	}
+	if len(results) != 5 {
+		t.Fatalf("Preflight() returned %d results, want 5", len(results))
+	}
+
+	tests := []struct {
+		name   string
+		ok     bool
+		reason string
+	}{
+		{"valid call", true, ""},
+		{"unknown tool", false, "failed to get tool 'missing'"},
+		{"security violation", false, "security validation failed"},
+		{"nil input", false, "input cannot be nil"},
+		{"nil input with metadata", true, ""},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := results[i]
+			if got.Index != i {
+				t.Errorf("Index = %d, want %d", got.Index, i)
+			}
+			if got.OK != tt.ok {
+				t.Errorf("OK = %v, want %v (reason %q)", got.OK, tt.ok, got.Reason)
+			}
+			if tt.reason == "" && got.Reason != "" {
+				t.Errorf("Reason = %q, want empty", got.Reason)
+			}
+			if !strings.Contains(got.Reason, tt.reason) {
+				t.Errorf("Reason = %q, want it to contain %q", got.Reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestExecutor_Preflight_CancelledContext(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register(NewMockTool("echo", "An echo tool")); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+	exec := NewExecutor(registry)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := exec.Preflight(ctx, []ToolExecution{{ToolName: "echo", Input: NewInput()}})
+	if len(results) != 1 || results[0].OK {
+		t.Errorf("Preflight() with cancelled context = %+v, want rejection", results)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -140,7 +141,9 @@ func ParseToolCallsLenient(text string) []ToolCall {
 
 // ExtractToolCallsLenient extracts tool calls and returns the cleaned text.
 // Tool blocks that parse successfully are removed from the returned text.
-// Invalid tool blocks are preserved in the text.
+// Invalid tool blocks are preserved in the text. The text around a removed
+// block is joined with a single blank line, so no stray whitespace or
+// blank runs are left where blocks were, and the result is trimmed.
 func ExtractToolCallsLenient(text string) ([]ToolCall, string) {
 	matches := toolBlockRegex.FindAllStringSubmatchIndex(text, -1)
 	if len(matches) == 0 {
@@ -148,7 +151,8 @@ func ExtractToolCallsLenient(text string) ([]ToolCall, string) {
 	}
 
 	calls := make([]ToolCall, 0, len(matches))
-	var clean strings.Builder
+	var pieces []string
+	var kept strings.Builder
 	last := 0
 
 	for _, match := range matches {
@@ -164,19 +168,40 @@ func ExtractToolCallsLenient(text string) ([]ToolCall, string) {
 		if err := json.Unmarshal([]byte(jsonContent), &call); err == nil {
 			if err := call.Validate(); err == nil {
 				calls = append(calls, call)
-				clean.WriteString(text[last:start])
+				kept.WriteString(text[last:start])
+				pieces = append(pieces, kept.String())
+				kept.Reset()
 				last = end
 				continue
 			}
 		}
 
 		// Keep invalid block in the cleaned text.
-		clean.WriteString(text[last:end])
+		kept.WriteString(text[last:end])
 		last = end
 	}
 
-	clean.WriteString(text[last:])
-	return calls, strings.TrimSpace(clean.String())
+	kept.WriteString(text[last:])
+	pieces = append(pieces, kept.String())
+	return calls, joinAroundRemovedBlocks(pieces)
+}
+
+// joinAroundRemovedBlocks joins the text left between removed tool blocks.
+// Each piece loses its blank edges (keeping the indentation of its first
+// line), and non-empty pieces are separated by one blank line.
+func joinAroundRemovedBlocks(pieces []string) string {
+	parts := make([]string, 0, len(pieces))
+	for _, piece := range pieces {
+		piece = strings.TrimRight(piece, " \t\r\n")
+		// Drop leading blank lines, but not the first line's indentation
+		if i := strings.LastIndexByte(piece[:len(piece)-len(strings.TrimLeft(piece, " \t\r\n"))], '\n'); i >= 0 {
+			piece = piece[i+1:]
+		}
+		if strings.TrimSpace(piece) != "" {
+			parts = append(parts, piece)
+		}
+	}
+	return strings.TrimSpace(strings.Join(parts, "\n\n"))
 }
 
 // HasToolCall checks if the text contains at least one tool call block.
@@ -211,6 +236,12 @@ type ToolCallResult struct {
 
 	// ExecutionTimeMs is the execution time in milliseconds.
 	ExecutionTimeMs int64 `json:"execution_time_ms,omitempty"`
+
+	// Args are the arguments the tool was called with, and ExitCode the
+	// exit status of the command it ran, if any. Only structured results
+	// (see NewStructuredToolCallResult) carry them.
+	Args     map[string]any `json:"args,omitempty"`
+	ExitCode *int           `json:"exit_code,omitempty"`
 }
 
 // NewToolCallResult creates a ToolCallResult from a Result.
@@ -237,6 +268,20 @@ func NewToolCallResult(result *Result) *ToolCallResult {
 	return tcr
 }
 
+// NewStructuredToolCallResult creates a ToolCallResult for call that also
+// records the call's arguments and, when the tool reported one, the exit
+// code of the command it ran.
+func NewStructuredToolCallResult(call ToolCall, result *Result) *ToolCallResult {
+	tcr := NewToolCallResult(result)
+	tcr.Args = call.Args
+	if result.Output != nil {
+		if code, err := strconv.Atoi(result.Output.Metadata[ExitCodeKey]); err == nil {
+			tcr.ExitCode = &code
+		}
+	}
+	return tcr
+}
+
 // ToJSON returns the result as a JSON string.
 func (r *ToolCallResult) ToJSON() (string, error) {
 	data, err := json.Marshal(r)
@@ -254,3 +299,17 @@ func (r *ToolCallResult) FormatAsBlock() string {
 	}
 	return fmt.Sprintf("```result\n%s\n```", jsonStr)
 }
+
+// FormatResultsAsJSON formats results as a single ```results block holding
+// a JSON array, one object per result in order, so a model can parse all
+// the results of a turn at once.
+func FormatResultsAsJSON(results []*ToolCallResult) string {
+	if results == nil {
+		results = []*ToolCallResult{}
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("```results\n[{\"error\": \"failed to format results: %s\"}]\n```", err.Error())
+	}
+	return fmt.Sprintf("```results\n%s\n```", data)
+}
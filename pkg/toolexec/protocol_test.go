@@ -3,6 +3,8 @@
 package toolexec
 
 import (
+	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -301,6 +303,28 @@ func TestExtractToolCallsLenient(t *testing.T) {
 			t.Fatalf("expected invalid block preserved, got: %q", clean)
 		}
 	})
+
+	block := "```tool\n" + `{"name": "bash", "args": {"command": "ls"}}` + "\n```"
+	tidy := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"leading block", block + "\n\n\n  \nHere is the listing.\n", "Here is the listing."},
+		{"trailing block", "Let me check.  \n\n\t\n" + block + "\n\n", "Let me check."},
+		{"embedded block", "Before.\n\n\n" + block + "\n \n\n\nAfter.", "Before.\n\nAfter."},
+		{"adjacent blocks", "Before.\n" + block + "\n\n" + block + "\nAfter.", "Before.\n\nAfter."},
+		{"only blocks", "\n" + block + "\n" + block + "\n", ""},
+		{"keeps indentation and inner blank lines", "Intro\n" + block + "\n\n    code line\n\n    more", "Intro\n\n    code line\n\n    more"},
+	}
+	for _, tt := range tidy {
+		t.Run("tidies "+tt.name, func(t *testing.T) {
+			_, clean := ExtractToolCallsLenient(tt.input)
+			if clean != tt.want {
+				t.Errorf("cleaned text = %q, want %q", clean, tt.want)
+			}
+		})
+	}
 }
 
 // TestHasToolCall tests the HasToolCall function.
@@ -558,3 +582,52 @@ func TestParseToolCalls_ComplexScenarios(t *testing.T) {
 		}
 	})
 }
+
+func TestNewStructuredToolCallResult(t *testing.T) {
+	call := ToolCall{Name: "bash", Args: map[string]any{"command": "false"}}
+	output := NewOutput().WithData([]byte("oops"))
+	output.Metadata[ExitCodeKey] = "1"
+
+	tcr := NewStructuredToolCallResult(call, NewResult("bash", output, errors.New("exit status 1")))
+	if tcr.Args["command"] != "false" || tcr.ExitCode == nil || *tcr.ExitCode != 1 {
+		t.Errorf("structured result = %+v, want args and exit code 1", tcr)
+	}
+	if tcr.Success || tcr.Output != "oops" || tcr.Error != "exit status 1" {
+		t.Errorf("structured result = %+v", tcr)
+	}
+
+	// The text result of the same call carries neither
+	plain, err := NewToolCallResult(NewResult("bash", output, nil)).ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	if strings.Contains(plain, "args") || strings.Contains(plain, "exit_code") {
+		t.Errorf("plain result should not include structured fields: %s", plain)
+	}
+}
+
+func TestFormatResultsAsJSON(t *testing.T) {
+	code := 0
+	results := []*ToolCallResult{
+		{ToolName: "bash", Success: true, Output: "ok", Args: map[string]any{"command": "true"}, ExitCode: &code},
+		{ToolName: "file_read", Error: "not found"},
+	}
+
+	block := FormatResultsAsJSON(results)
+	body, ok := strings.CutPrefix(block, "```results\n")
+	if !ok || !strings.HasSuffix(body, "\n```") {
+		t.Fatalf("unexpected block:\n%s", block)
+	}
+
+	var decoded []ToolCallResult
+	if err := json.Unmarshal([]byte(strings.TrimSuffix(body, "\n```")), &decoded); err != nil {
+		t.Fatalf("block is not a JSON array: %v", err)
+	}
+	if len(decoded) != 2 || decoded[0].ToolName != "bash" || *decoded[0].ExitCode != 0 || decoded[1].Error != "not found" {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	if empty := FormatResultsAsJSON(nil); empty != "```results\n[]\n```" {
+		t.Errorf("FormatResultsAsJSON(nil) = %q", empty)
+	}
+}
@@ -37,6 +37,11 @@ type Registry interface {
 
 	// Clear removes all tools from the registry.
 	Clear()
+
+	// Clone returns an independent copy of the registry.
+	// The copy shares the same Tool instances but has its own tool map,
+	// so registering or unregistering on one does not affect the other.
+	Clone() Registry
 }
 
 // registry is the default thread-safe implementation of Registry.
@@ -157,6 +162,27 @@ func (r *registry) Clear() {
 	r.tools = make(map[string]Tool)
 }
 
+// Clone returns an independent copy of the registry sharing the same tools.
+// This is useful for forking a registry (e.g. the default registry) to add
+// request-scoped tools without affecting the original. A ListToolsTool
+// bound to this registry is rebound to the clone, so it lists the clone's
+// tools.
+// This method is thread-safe.
+func (r *registry) Clone() Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	clone := &registry{tools: make(map[string]Tool, len(r.tools))}
+	for name, tool := range r.tools {
+		if lt, ok := tool.(*ListToolsTool); ok && lt.registry == Registry(r) {
+			tool = NewListToolsTool(clone)
+		}
+		clone.tools[name] = tool
+	}
+
+	return clone
+}
+
 // defaultRegistry is the package-level global registry.
 // It is initialized lazily on first access for safe use in init() functions.
 var (
@@ -257,6 +283,14 @@ func WithTools(tools ...Tool) RegistryOption {
 	}
 }
 
+// WithListToolsTool registers a ListToolsTool for the registry itself, so
+// models can discover the other tools registered in it.
+func WithListToolsTool() RegistryOption {
+	return func(r *registry) {
+		WithTools(NewListToolsTool(r))(r)
+	}
+}
+
 // RegistrySnapshot represents a point-in-time snapshot of registry contents.
 // This is useful for safely iterating over tools without holding locks.
 type RegistrySnapshot struct {
@@ -151,3 +151,40 @@ func TestNewRegistryWithOptions(t *testing.T) {
 		t.Error("WithTools failed")
 	}
 }
+
+func TestRegistryClone(t *testing.T) {
+	shared := NewMockTool("shared", "shared")
+	original := NewRegistryWithOptions(WithTools(shared))
+
+	clone := original.Clone()
+	if err := clone.Register(NewMockTool("scoped", "scoped")); err != nil {
+		t.Fatalf("Register on clone failed: %v", err)
+	}
+
+	// Adding to the clone leaves the original unchanged
+	if original.Has("scoped") || original.Count() != 1 {
+		t.Errorf("original changed after registering on clone: %v", original.List())
+	}
+
+	// Both see the originally shared tool instance
+	for name, r := range map[string]Registry{"original": original, "clone": clone} {
+		got, err := r.Get("shared")
+		if err != nil || got != shared {
+			t.Errorf("%s: Get(shared) = %v, %v; want the shared instance", name, got, err)
+		}
+	}
+
+	// Removing from the clone leaves the original unchanged
+	if err := clone.Unregister("shared"); err != nil {
+		t.Fatalf("Unregister on clone failed: %v", err)
+	}
+	if !original.Has("shared") {
+		t.Error("original lost a tool unregistered from the clone")
+	}
+
+	// Changes to the original don't leak into the clone
+	original.Clear()
+	if !clone.Has("scoped") {
+		t.Error("clone changed after clearing the original")
+	}
+}
@@ -386,6 +386,28 @@ type PanicError struct {
 	PanicValue any
 	// Stack is the stack trace at the time of panic (optional).
 	Stack string
+	// Input is a sanitized copy of the input that triggered the panic.
+	// It is only set when the executor runs with WithPanicInput.
+	Input *PanicInput
+}
+
+// PanicInput is a sanitized copy of a tool Input attached to a PanicError.
+// Values that look like secrets are replaced with EnvRedacted, and raw
+// data is reduced to its size.
+type PanicInput struct {
+	// Name is the input name.
+	Name string
+	// Params holds the input parameters with secrets redacted.
+	Params map[string]any
+	// Metadata holds the input metadata with secrets redacted.
+	Metadata map[string]string
+	// DataSize is the length of Input.Data in bytes.
+	DataSize int
+}
+
+// String formats the input for error messages.
+func (p *PanicInput) String() string {
+	return fmt.Sprintf("name=%q params=%v metadata=%v data=%d bytes", p.Name, p.Params, p.Metadata, p.DataSize)
 }
 
 // NewPanicError creates a new PanicError.
@@ -409,11 +431,14 @@ func NewPanicErrorWithStack(toolName string, panicValue any, stack string) *Pani
 
 // Error implements the error interface.
 func (e *PanicError) Error() string {
+	msg := fmt.Sprintf("panic recovered in tool '%s': %v", e.ToolName, e.PanicValue)
+	if e.Input != nil {
+		msg += fmt.Sprintf("\nInput: %s", e.Input)
+	}
 	if e.Stack != "" {
-		return fmt.Sprintf("panic recovered in tool '%s': %v\nStack:\n%s",
-			e.ToolName, e.PanicValue, e.Stack)
+		msg += fmt.Sprintf("\nStack:\n%s", e.Stack)
 	}
-	return fmt.Sprintf("panic recovered in tool '%s': %v", e.ToolName, e.PanicValue)
+	return msg
 }
 
 // Is allows comparison with sentinel errors.
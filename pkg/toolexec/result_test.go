@@ -1,6 +1,7 @@
 package toolexec
 
 import (
+	"bytes"
 	"errors"
 	"testing"
 )
@@ -156,6 +157,139 @@ func TestOutputTruncation(t *testing.T) {
 	})
 }
 
+// TestOutputTruncateModes tests head, tail and middle truncation.
+func TestOutputTruncateModes(t *testing.T) {
+	data := []byte("0123456789abcdefghij") // 20 bytes
+
+	t.Run("head keeps the beginning", func(t *testing.T) {
+		output := NewOutput().WithTruncateMode(TruncateHead).WithTruncatedData(data, 8)
+
+		if string(output.Data) != "01234567" {
+			t.Errorf("Data = %q, want %q", output.Data, "01234567")
+		}
+		if !output.Truncated {
+			t.Error("Truncated should be true")
+		}
+		if output.Metadata[TruncationNoticeKey] != "12 bytes elided (head)" {
+			t.Errorf("notice = %q", output.Metadata[TruncationNoticeKey])
+		}
+	})
+
+	t.Run("tail keeps the end", func(t *testing.T) {
+		output := NewOutput().WithTruncateMode(TruncateTail).WithTruncatedData(data, 8)
+
+		if string(output.Data) != "cdefghij" {
+			t.Errorf("Data = %q, want %q", output.Data, "cdefghij")
+		}
+		if !output.Truncated {
+			t.Error("Truncated should be true")
+		}
+		if output.Metadata[TruncationNoticeKey] != "12 bytes elided (tail)" {
+			t.Errorf("notice = %q", output.Metadata[TruncationNoticeKey])
+		}
+	})
+
+	t.Run("middle keeps head and tail with marker", func(t *testing.T) {
+		output := NewOutput().WithTruncateMode(TruncateMiddle).WithTruncatedData(data, 9)
+
+		want := "0123" + "\n... [11 bytes elided] ...\n" + "fghij"
+		if string(output.Data) != want {
+			t.Errorf("Data = %q, want %q", output.Data, want)
+		}
+		if !output.Truncated {
+			t.Error("Truncated should be true")
+		}
+		if output.Metadata[TruncationNoticeKey] != "11 bytes elided (middle)" {
+			t.Errorf("notice = %q", output.Metadata[TruncationNoticeKey])
+		}
+	})
+
+	t.Run("TruncateDefault respects mode", func(t *testing.T) {
+		large := make([]byte, DefaultMaxOutputSize+10)
+		large[len(large)-1] = 'z'
+		output := NewOutput().WithData(large).WithTruncateMode(TruncateTail).TruncateDefault()
+
+		if len(output.Data) != DefaultMaxOutputSize {
+			t.Errorf("Data length = %d, want %d", len(output.Data), DefaultMaxOutputSize)
+		}
+		if output.Data[len(output.Data)-1] != 'z' {
+			t.Error("tail mode should keep the last byte")
+		}
+	})
+
+	t.Run("no notice when not truncated", func(t *testing.T) {
+		for _, mode := range []TruncateMode{TruncateHead, TruncateTail, TruncateMiddle} {
+			output := NewOutput().WithTruncateMode(mode).WithTruncatedData(data, len(data))
+			if output.Truncated || output.Metadata[TruncationNoticeKey] != "" {
+				t.Errorf("%s: should not truncate data at the limit", mode)
+			}
+		}
+	})
+
+	t.Run("notice initializes nil metadata", func(t *testing.T) {
+		output := &Output{Data: data, TruncateMode: TruncateTail}
+		output.Truncate(4)
+
+		if output.Metadata[TruncationNoticeKey] == "" {
+			t.Error("expected notice in metadata")
+		}
+	})
+}
+
+// TestOutputTruncationDroppedCounts tests the dropped byte and line counts
+// recorded in metadata.
+func TestOutputTruncationDroppedCounts(t *testing.T) {
+	lines := []byte("one\ntwo\nthree\nfour\nfive\n") // 24 bytes, 5 lines
+
+	tests := []struct {
+		name      string
+		data      []byte
+		mode      TruncateMode
+		maxSize   int
+		wantBytes string
+		wantLines string
+	}{
+		{"head drops trailing lines", lines, TruncateHead, 8, "16", "3"},
+		{"tail drops leading lines", lines, TruncateTail, 5, "19", "4"},
+		{"middle drops inner lines", lines, TruncateMiddle, 8, "16", "3"},
+		{"partial line without newline", []byte("abcdefghij"), TruncateHead, 4, "6", "0"},
+		{"one byte over the limit", lines, TruncateHead, 23, "1", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := NewOutput().WithTruncateMode(tt.mode).WithTruncatedData(tt.data, tt.maxSize)
+
+			if got := output.Metadata[DroppedBytesKey]; got != tt.wantBytes {
+				t.Errorf("dropped bytes = %q, want %q", got, tt.wantBytes)
+			}
+			if got := output.Metadata[DroppedLinesKey]; got != tt.wantLines {
+				t.Errorf("dropped lines = %q, want %q", got, tt.wantLines)
+			}
+		})
+	}
+
+	t.Run("TruncateDefault records counts", func(t *testing.T) {
+		data := bytes.Repeat([]byte("0123456789abcde\n"), DefaultMaxOutputSize/16+100)
+		output := NewOutput().WithData(data).TruncateDefault()
+
+		if output.Metadata[DroppedBytesKey] != "1600" || output.Metadata[DroppedLinesKey] != "100" {
+			t.Errorf("metadata = %v", output.Metadata)
+		}
+	})
+
+	t.Run("no counts when not truncated", func(t *testing.T) {
+		for _, maxSize := range []int{0, len(lines), len(lines) + 1} {
+			output := NewOutput().WithTruncatedData(lines, maxSize)
+			if _, ok := output.Metadata[DroppedBytesKey]; ok {
+				t.Errorf("maxSize %d: unexpected %s", maxSize, DroppedBytesKey)
+			}
+			if _, ok := output.Metadata[DroppedLinesKey]; ok {
+				t.Errorf("maxSize %d: unexpected %s", maxSize, DroppedLinesKey)
+			}
+		}
+	})
+}
+
 // TestErrorWrapping tests that errors wrap correctly with %w.
 func TestErrorWrapping(t *testing.T) {
 	t.Run("ToolNotFoundError wraps correctly", func(t *testing.T) {
@@ -100,7 +100,8 @@ type PathValidator struct {
 	blockedPaths []string
 
 	// toolNames are the tool names this validator applies to.
-	// If empty, it applies to "file_read" and "file_write" by default.
+	// By default it applies to "file_read", "file_write", "file_edit" and
+	// "multi_file_write".
 	toolNames []string
 }
 
@@ -109,7 +110,7 @@ type PathValidator struct {
 func NewPathValidator(paths ...string) *PathValidator {
 	return &PathValidator{
 		blockedPaths: paths,
-		toolNames:    []string{"file_read", "file_write"},
+		toolNames:    []string{"file_read", "file_write", "file_edit", "multi_file_write"},
 	}
 }
 
@@ -161,13 +162,29 @@ func (v *PathValidator) Validate(ctx context.Context, toolName string, args map[
 		return nil
 	}
 
-	// Get the path argument
-	path, ok := args["path"].(string)
-	if !ok {
-		// No path argument or wrong type - let other validators handle this
-		return nil
+	// Validate the path argument and any files[].path entries (batch
+	// writes), so one blocked path rejects the whole call
+	if path, ok := args["path"].(string); ok {
+		if err := v.validatePath(toolName, path); err != nil {
+			return err
+		}
 	}
+	if files, ok := args["files"].([]any); ok {
+		for _, item := range files {
+			entry, _ := item.(map[string]any)
+			if path, ok := entry["path"].(string); ok {
+				if err := v.validatePath(toolName, path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
 
+// validatePath checks a single path against the blocked patterns.
+func (v *PathValidator) validatePath(toolName, path string) error {
 	// Clean the path for consistent matching
 	cleanPath := filepath.Clean(path)
 	baseName := filepath.Base(cleanPath)
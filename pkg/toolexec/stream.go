@@ -0,0 +1,62 @@
+package toolexec
+
+import (
+	"context"
+	"sync"
+)
+
+// StreamingTool is a Tool that can report its output incrementally while
+// it runs, e.g. a shell command whose output should be shown live.
+type StreamingTool interface {
+	Tool
+
+	// ExecuteStream runs the tool like Execute, calling emit with each chunk
+	// of output as it is produced. emit may be nil. The returned Output holds
+	// the complete (possibly truncated) output, as Execute would return.
+	ExecuteStream(ctx context.Context, input *Input, emit func(chunk []byte)) (*Output, error)
+}
+
+// outputStreamKey is the context key for the output stream handler.
+type outputStreamKey struct{}
+
+// WithOutputStream returns a context that asks the executor to run
+// StreamingTools with ExecuteStream, passing each output chunk to emit.
+// Tools that do not implement StreamingTool run normally and emit nothing.
+// emit is called from the goroutine running the tool.
+func WithOutputStream(ctx context.Context, emit func(chunk []byte)) context.Context {
+	return context.WithValue(ctx, outputStreamKey{}, emit)
+}
+
+// OutputStreamFromContext returns the output stream handler set by
+// WithOutputStream, or nil if there is none.
+func OutputStreamFromContext(ctx context.Context) func(chunk []byte) {
+	emit, _ := ctx.Value(outputStreamKey{}).(func(chunk []byte))
+	return emit
+}
+
+// streamWriter collects everything written to it and forwards a copy of
+// each write to emit.
+type streamWriter struct {
+	mu   sync.Mutex
+	data []byte
+	emit func(chunk []byte)
+}
+
+// Write records p and emits a copy of it.
+func (w *streamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.data = append(w.data, p...)
+	if w.emit != nil && len(p) > 0 {
+		w.emit(append([]byte(nil), p...))
+	}
+	return len(p), nil
+}
+
+// Bytes returns everything written so far.
+func (w *streamWriter) Bytes() []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.data
+}
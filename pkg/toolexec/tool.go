@@ -5,7 +5,10 @@
 package toolexec
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"strconv"
 )
 
 // Tool defines the interface that all executable tools must implement.
@@ -51,6 +54,26 @@ type Input struct {
 
 	// Metadata holds additional context information (e.g., request ID, user info).
 	Metadata map[string]string
+
+	// Files holds references to files passed to the tool (e.g., chat
+	// attachments), so file-aware tools need not parse them from Params.
+	Files []FileRef
+}
+
+// FileRef references a file passed to a tool, either on the local
+// filesystem (Path) or uploaded elsewhere (ID).
+type FileRef struct {
+	// Name is the display name of the file.
+	Name string
+
+	// MIME is the MIME type of the file, if known.
+	MIME string
+
+	// Path is the local filesystem path, if the file is on disk.
+	Path string
+
+	// ID identifies an uploaded file (e.g., a resource ID), if any.
+	ID string
 }
 
 // NewInput creates a new Input with initialized maps.
@@ -58,6 +81,7 @@ func NewInput() *Input {
 	return &Input{
 		Params:   make(map[string]any),
 		Metadata: make(map[string]string),
+		Files:    []FileRef{},
 	}
 }
 
@@ -91,6 +115,21 @@ func (i *Input) WithMetadata(key, value string) *Input {
 	return i
 }
 
+// WithFiles appends file references and returns the Input for chaining.
+func (i *Input) WithFiles(files ...FileRef) *Input {
+	i.Files = append(i.Files, files...)
+	return i
+}
+
+// GetFiles returns the file references passed to the tool.
+// Returns an empty slice if there are none.
+func (i *Input) GetFiles() []FileRef {
+	if i.Files == nil {
+		return []FileRef{}
+	}
+	return i.Files
+}
+
 // GetParam retrieves a parameter by key.
 // Returns nil if the parameter does not exist.
 func (i *Input) GetParam(key string) any {
@@ -133,6 +172,63 @@ func (i *Input) GetParamBool(key string) bool {
 // DefaultMaxOutputSize is the default maximum output size before truncation (100KB).
 const DefaultMaxOutputSize = 100 * 1024
 
+// TruncateMode selects which part of oversized output data is kept.
+type TruncateMode int
+
+const (
+	// TruncateHead keeps the beginning of the data and drops the end (default).
+	TruncateHead TruncateMode = iota
+
+	// TruncateTail keeps the end of the data and drops the beginning.
+	// This is useful for logs, where the last lines are usually the most relevant.
+	TruncateTail
+
+	// TruncateMiddle keeps the beginning and the end of the data and replaces
+	// the middle with an elision marker.
+	TruncateMiddle
+)
+
+// String returns the mode name.
+func (m TruncateMode) String() string {
+	switch m {
+	case TruncateTail:
+		return "tail"
+	case TruncateMiddle:
+		return "middle"
+	default:
+		return "head"
+	}
+}
+
+// TruncationNoticeKey is the Output.Metadata key describing how much data
+// was elided when an output is truncated.
+const TruncationNoticeKey = "truncation_notice"
+
+// DroppedBytesKey and DroppedLinesKey are the Output.Metadata keys holding
+// how many bytes, and how many line breaks within them, were dropped when
+// an output is truncated. Outputs that were not truncated carry neither.
+const (
+	DroppedBytesKey = "dropped_bytes"
+	DroppedLinesKey = "dropped_lines"
+)
+
+// ExitCodeKey is the Output.Metadata key holding the exit status of a
+// command run by a tool such as BashTool. Commands that were killed carry
+// none.
+const ExitCodeKey = "exit_code"
+
+// OutputFormatKey is the Output.Metadata key naming the format of the
+// output data, so clients can choose how to display it. Outputs without it
+// are plain text.
+const OutputFormatKey = "format"
+
+// Output formats for OutputFormatKey.
+const (
+	OutputFormatText     = "text"
+	OutputFormatJSON     = "json"
+	OutputFormatMarkdown = "markdown"
+)
+
 // Output represents the result of a tool execution.
 // It provides a flexible structure for returning data and metadata.
 type Output struct {
@@ -154,6 +250,10 @@ type Output struct {
 	// Truncated indicates whether the output data was truncated due to size limits.
 	// When true, the Data field contains partial output up to the configured limit.
 	Truncated bool
+
+	// TruncateMode selects which part of the data Truncate keeps.
+	// The zero value is TruncateHead.
+	TruncateMode TruncateMode
 }
 
 // NewOutput creates a new Output with initialized maps and Success set to true.
@@ -224,8 +324,29 @@ func (o *Output) GetResultString(key string) string {
 	return ""
 }
 
+// WithTruncateMode sets the truncation mode and returns the Output for chaining.
+// The mode applies to subsequent calls to Truncate, TruncateDefault and
+// WithTruncatedData.
+//
+// Example:
+//
+//	output := NewOutput().WithTruncateMode(TruncateTail).WithTruncatedData(logs, 10*1024)
+func (o *Output) WithTruncateMode(mode TruncateMode) *Output {
+	o.TruncateMode = mode
+	return o
+}
+
 // Truncate truncates the output data to the specified maximum size.
 // If the data is already smaller than maxSize, no truncation occurs.
+// Which bytes are kept depends on TruncateMode:
+//   - TruncateHead keeps the first maxSize bytes
+//   - TruncateTail keeps the last maxSize bytes
+//   - TruncateMiddle keeps maxSize bytes split between the beginning and the
+//     end, joined by an elision marker (the marker is not counted in maxSize)
+//
+// When truncation occurs, Truncated is set, Metadata[TruncationNoticeKey]
+// records how many bytes were elided, and Metadata[DroppedBytesKey] and
+// Metadata[DroppedLinesKey] hold the number of dropped bytes and line breaks.
 // Returns the Output for chaining.
 //
 // Example:
@@ -235,8 +356,35 @@ func (o *Output) Truncate(maxSize int) *Output {
 	if maxSize <= 0 || len(o.Data) <= maxSize {
 		return o
 	}
-	o.Data = o.Data[:maxSize]
+
+	elided := len(o.Data) - maxSize
+	var dropped []byte
+	switch o.TruncateMode {
+	case TruncateTail:
+		dropped = o.Data[:elided]
+		o.Data = o.Data[elided:]
+	case TruncateMiddle:
+		headSize := maxSize / 2
+		tailSize := maxSize - headSize
+		dropped = o.Data[headSize : len(o.Data)-tailSize]
+		marker := fmt.Sprintf("\n... [%d bytes elided] ...\n", elided)
+		data := make([]byte, 0, maxSize+len(marker))
+		data = append(data, o.Data[:headSize]...)
+		data = append(data, marker...)
+		data = append(data, o.Data[len(o.Data)-tailSize:]...)
+		o.Data = data
+	default:
+		dropped = o.Data[maxSize:]
+		o.Data = o.Data[:maxSize]
+	}
+
 	o.Truncated = true
+	if o.Metadata == nil {
+		o.Metadata = make(map[string]string)
+	}
+	o.Metadata[TruncationNoticeKey] = fmt.Sprintf("%d bytes elided (%s)", elided, o.TruncateMode)
+	o.Metadata[DroppedBytesKey] = strconv.Itoa(elided)
+	o.Metadata[DroppedLinesKey] = strconv.Itoa(bytes.Count(dropped, []byte{'\n'}))
 	return o
 }
 
@@ -275,16 +423,32 @@ func TruncateOutput(output *Output, maxSize int) *Output {
 // This is used for tool discovery and documentation.
 type ToolInfo struct {
 	// Name is the unique identifier for the tool.
-	Name string
+	Name string `json:"name"`
 
 	// Description is a human-readable description of the tool.
-	Description string
+	Description string `json:"description"`
+
+	// Tags are the tool's tags, if it is a TaggedTool.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// TaggedTool is implemented by tools that declare tags describing what
+// they do, such as "filesystem" or "read-only".
+type TaggedTool interface {
+	Tool
+
+	// Tags returns the tool's tags.
+	Tags() []string
 }
 
 // ToolInfoFromTool creates a ToolInfo from a Tool interface.
 func ToolInfoFromTool(t Tool) ToolInfo {
-	return ToolInfo{
+	info := ToolInfo{
 		Name:        t.Name(),
 		Description: t.Description(),
 	}
+	if tagged, ok := t.(TaggedTool); ok {
+		info.Tags = tagged.Tags()
+	}
+	return info
 }
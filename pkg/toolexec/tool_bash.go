@@ -2,16 +2,27 @@ package toolexec
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
 )
 
+// bashKillGrace is how long a timed-out command gets to exit after SIGTERM
+// before it is killed.
+const bashKillGrace = 2 * time.Second
+
 // BashTool executes shell commands via bash -c.
 type BashTool struct {
 	shell         string
 	workingDir    string
 	env           []string
 	maxOutputSize int
+	truncateMode  TruncateMode
+	timeout       time.Duration
+	killGrace     time.Duration
 }
 
 // BashToolOption configures a BashTool.
@@ -22,6 +33,7 @@ func NewBashTool(opts ...BashToolOption) *BashTool {
 	tool := &BashTool{
 		shell:         "bash",
 		maxOutputSize: DefaultMaxOutputSize,
+		killGrace:     bashKillGrace,
 	}
 	for _, opt := range opts {
 		if opt != nil {
@@ -67,6 +79,25 @@ func WithBashToolMaxOutputSize(limit int) BashToolOption {
 	}
 }
 
+// WithBashToolTruncateMode sets which part of oversized output is kept.
+// TruncateTail is useful for commands whose last lines matter most (e.g. builds).
+func WithBashToolTruncateMode(mode TruncateMode) BashToolOption {
+	return func(t *BashTool) {
+		t.truncateMode = mode
+	}
+}
+
+// WithCommandTimeout sets a deadline for each command. When it expires the
+// shell and the processes it started are sent SIGTERM and, if any of them
+// is still running after a grace period, killed; the output written so far
+// is returned with a TimeoutError. A zero or negative timeout disables the
+// deadline.
+func WithCommandTimeout(d time.Duration) BashToolOption {
+	return func(t *BashTool) {
+		t.timeout = d
+	}
+}
+
 // Name returns the tool name.
 func (t *BashTool) Name() string {
 	return "bash"
@@ -84,13 +115,34 @@ func (t *BashTool) RequiresConfirmation(args map[string]any) bool {
 
 // Execute runs the bash command and returns combined output.
 func (t *BashTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	return t.ExecuteStream(ctx, input, nil)
+}
+
+// ExecuteStream runs the bash command, emitting combined stdout and stderr
+// as it is written, and returns the combined output.
+func (t *BashTool) ExecuteStream(ctx context.Context, input *Input, emit func(chunk []byte)) (*Output, error) {
 	args := argsFromInput(input)
 	command, err := requireStringArg(t.Name(), args, "command")
 	if err != nil {
 		return nil, err
 	}
 
-	cmd := exec.CommandContext(ctx, t.shell, "-c", command)
+	runCtx := ctx
+	if t.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, t.shell, "-c", command)
+	// On timeout or cancellation, ask the shell and everything it started
+	// to stop first; WaitDelay closes the pipes if any of them is still
+	// running after the grace period, and the group is then killed below
+	startProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return signalProcessGroup(cmd, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = t.killGrace
 	if t.workingDir != "" {
 		cmd.Dir = t.workingDir
 	}
@@ -98,15 +150,40 @@ func (t *BashTool) Execute(ctx context.Context, input *Input) (*Output, error) {
 		cmd.Env = append(os.Environ(), t.env...)
 	}
 
-	data, err := cmd.CombinedOutput()
-	output := NewOutput().WithTruncatedData(data, t.maxOutputSize)
+	// A single writer for both streams keeps them interleaved as written,
+	// like CombinedOutput
+	w := &streamWriter{emit: emit}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	err = cmd.Run()
+	switch {
+	case runCtx.Err() != nil:
+		// Children that ignored SIGTERM (or were left running after the
+		// shell exited) must not outlive the command
+		_ = signalProcessGroup(cmd, syscall.SIGKILL)
+	case errors.Is(err, exec.ErrWaitDelay):
+		// The shell succeeded but left a background job holding the
+		// output open; what it writes later is not captured
+		err = nil
+	}
+	output := NewOutput().WithTruncateMode(t.truncateMode).WithTruncatedData(w.Bytes(), t.maxOutputSize)
+	if code := cmd.ProcessState.ExitCode(); code >= 0 {
+		output.Metadata[ExitCodeKey] = strconv.Itoa(code)
+	}
 	if err != nil {
 		output.Success = false
 		if ctx.Err() != nil {
 			return output, ctx.Err()
 		}
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return output, NewTimeoutError(t.Name(), t.timeout)
+		}
 		return output, NewExecutionErrorWithCause(t.Name(), err)
 	}
 
 	return output, nil
 }
+
+// Compile-time verification that BashTool implements StreamingTool.
+var _ StreamingTool = (*BashTool)(nil)
@@ -0,0 +1,23 @@
+//go:build !unix
+
+package toolexec
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startProcessGroup is a no-op where process groups are not available.
+func startProcessGroup(cmd *exec.Cmd) {}
+
+// signalProcessGroup signals only the shell where process groups are not
+// available; SIGKILL is the only signal that can be delivered everywhere.
+func signalProcessGroup(cmd *exec.Cmd, sig syscall.Signal) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	if sig == syscall.SIGKILL {
+		return cmd.Process.Kill()
+	}
+	return cmd.Process.Signal(sig)
+}
@@ -2,9 +2,11 @@ package toolexec
 
 import (
 	"context"
+	"errors"
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBashTool_Execute(t *testing.T) {
@@ -39,3 +41,198 @@ func TestBashTool_RequiresConfirmation(t *testing.T) {
 		t.Fatal("RequiresConfirmation() = false, want true")
 	}
 }
+
+func TestBashTool_TruncateTail(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	tool := NewBashTool(WithBashToolMaxOutputSize(4), WithBashToolTruncateMode(TruncateTail))
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("command", "printf 'abcdefgh'"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "efgh" || !output.Truncated {
+		t.Fatalf("unexpected output: %q (truncated=%v)", string(output.Data), output.Truncated)
+	}
+}
+
+func TestBashTool_ExecuteStream(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	var chunks []string
+	tool := NewBashTool()
+	output, err := tool.ExecuteStream(context.Background(),
+		NewInput().WithParam("command", "echo one; sleep 0.05; echo two >&2"),
+		func(chunk []byte) { chunks = append(chunks, string(chunk)) })
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected output in separate chunks, got %q", chunks)
+	}
+	if got := strings.Join(chunks, ""); got != "one\ntwo\n" {
+		t.Errorf("streamed output = %q, want %q", got, "one\ntwo\n")
+	}
+	if string(output.Data) != "one\ntwo\n" {
+		t.Errorf("output data = %q, want combined stdout and stderr", string(output.Data))
+	}
+}
+
+func TestExecutor_StreamsToolOutput(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	registry := NewRegistry()
+	if err := registry.Register(NewBashTool()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	exec := NewExecutor(registry, WithDefaultMiddleware())
+
+	var streamed strings.Builder
+	ctx := WithOutputStream(context.Background(), func(chunk []byte) { streamed.Write(chunk) })
+	output, err := exec.Execute(ctx, "bash", NewInput().WithParam("command", "echo live"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if streamed.String() != "live\n" || string(output.Data) != "live\n" {
+		t.Errorf("streamed = %q, output = %q, want both %q", streamed.String(), string(output.Data), "live\n")
+	}
+}
+
+func TestBashTool_CommandTimeout(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	tool := NewBashTool(WithCommandTimeout(200 * time.Millisecond))
+	tool.killGrace = 200 * time.Millisecond
+
+	start := time.Now()
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("command", "echo started; sleep 5; echo finished"))
+	elapsed := time.Since(start)
+
+	if !IsTimeoutError(err) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 200*time.Millisecond {
+		t.Errorf("timeout error = %#v, want a 200ms TimeoutError", err)
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("command was not killed, took %v", elapsed)
+	}
+	if output == nil || output.Success {
+		t.Fatalf("expected a failed output with partial data, got %+v", output)
+	}
+	if got := string(output.Data); got != "started\n" {
+		t.Errorf("partial output = %q, want %q", got, "started\n")
+	}
+}
+
+func TestBashTool_CommandTimeoutEscalatesToKill(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	tool := NewBashTool(WithCommandTimeout(200 * time.Millisecond))
+	tool.killGrace = 200 * time.Millisecond
+
+	start := time.Now()
+	output, err := tool.Execute(context.Background(),
+		NewInput().WithParam("command", "trap '' TERM; echo ignoring; while :; do sleep 0.05; done"))
+	elapsed := time.Since(start)
+
+	if !IsTimeoutError(err) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("command ignoring SIGTERM was not killed, took %v", elapsed)
+	}
+	if got := string(output.Data); got != "ignoring\n" {
+		t.Errorf("partial output = %q, want %q", got, "ignoring\n")
+	}
+}
+
+func TestBashTool_CommandTimeoutNotReached(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	tool := NewBashTool(WithCommandTimeout(5 * time.Second))
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("command", "echo quick"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "quick\n" {
+		t.Errorf("output = %q, want %q", string(output.Data), "quick\n")
+	}
+}
+
+func TestBashTool_RecordsExitCode(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	tool := NewBashTool()
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("command", "true"))
+	if err != nil || output.Metadata[ExitCodeKey] != "0" {
+		t.Errorf("success: exit code = %q, err = %v", output.Metadata[ExitCodeKey], err)
+	}
+
+	output, err = tool.Execute(context.Background(), NewInput().WithParam("command", "exit 3"))
+	if err == nil || output.Metadata[ExitCodeKey] != "3" {
+		t.Errorf("failure: exit code = %q, err = %v", output.Metadata[ExitCodeKey], err)
+	}
+}
+
+func TestExecutor_RedactsFailingCommandOutput(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	const secret = "ghp_abcdef123456"
+	registry := NewRegistryWithOptions(WithTools(NewBashTool()))
+	exec := NewExecutor(registry, WithOutputTransformer(func(toolName string, out *Output) *Output {
+		out.Data = []byte(strings.ReplaceAll(string(out.Data), secret, EnvRedacted))
+		return out
+	}))
+
+	var streamed strings.Builder
+	ctx := WithOutputStream(context.Background(), func(chunk []byte) { streamed.Write(chunk) })
+	output, err := exec.Execute(ctx, "bash", NewInput().WithParam("command", "echo token="+secret+"; exit 1"))
+	if err == nil {
+		t.Fatal("expected the command to fail")
+	}
+	if output == nil || output.Metadata[ExitCodeKey] != "1" {
+		t.Fatalf("failing command output should be returned, got %+v", output)
+	}
+	want := "token=" + EnvRedacted + "\n"
+	if string(output.Data) != want || streamed.String() != want {
+		t.Errorf("output = %q, streamed = %q, want both %q", output.Data, streamed.String(), want)
+	}
+}
+
+func TestBashTool_BackgroundJobAfterSuccess(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	tool := NewBashTool()
+	tool.killGrace = 200 * time.Millisecond
+
+	start := time.Now()
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("command", "echo done; sleep 5 &"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Error("a background job should not hold the command open")
+	}
+	if string(output.Data) != "done\n" {
+		t.Errorf("output = %q, want %q", output.Data, "done\n")
+	}
+}
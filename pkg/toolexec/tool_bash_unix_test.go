@@ -0,0 +1,66 @@
+//go:build unix
+
+package toolexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestBashTool_CancelKillsChildren(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not available in PATH")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	tool := NewBashTool(WithCommandTimeout(300 * time.Millisecond))
+	tool.killGrace = 200 * time.Millisecond
+
+	// The background child ignores SIGTERM, so only the group SIGKILL stops it
+	command := fmt.Sprintf("(trap '' TERM; exec sleep 30) & echo $! > %s; wait", pidFile)
+	_, err := tool.Execute(context.Background(), NewInput().WithParam("command", command))
+	if !IsTimeoutError(err) {
+		t.Fatalf("expected TimeoutError, got %v", err)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("invalid pid %q", data)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for processRunning(pid) {
+		if time.Now().After(deadline) {
+			_ = syscall.Kill(pid, syscall.SIGKILL)
+			t.Fatalf("background child %d is still running after cancel", pid)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// processRunning reports whether pid is alive. A killed process whose
+// parent has exited stays a zombie until init reaps it, which counts as
+// gone.
+func processRunning(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return true // no procfs; trust the signal check
+	}
+	// The state follows the parenthesized command name
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
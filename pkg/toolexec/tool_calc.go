@@ -0,0 +1,354 @@
+package toolexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Limits on the expressions accepted by CalcTool.
+const (
+	calcMaxExpressionLen = 1000
+	calcMaxDepth         = 100
+)
+
+// calcFunctions are the functions CalcTool supports, by name and arity.
+var calcFunctions = map[string]struct {
+	arity int
+	fn    func(args []float64) (float64, error)
+}{
+	"sqrt": {1, func(args []float64) (float64, error) {
+		if args[0] < 0 {
+			return 0, errors.New("sqrt of a negative number")
+		}
+		return math.Sqrt(args[0]), nil
+	}},
+	"pow": {2, func(args []float64) (float64, error) { return math.Pow(args[0], args[1]), nil }},
+	"abs": {1, func(args []float64) (float64, error) { return math.Abs(args[0]), nil }},
+}
+
+// calcConstants are the named constants CalcTool supports.
+var calcConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// CalcTool evaluates arithmetic expressions.
+//
+// The "expression" param is parsed with a small fixed grammar: numbers,
+// the operators + - * / % ^ (also × ÷ −), parentheses, the functions sqrt,
+// pow and abs, and the constants pi and e. Nothing else is accepted, so an
+// expression can never run code. The result is returned as a float64 in
+// Output.Result["value"].
+type CalcTool struct{}
+
+// NewCalcTool creates a CalcTool.
+func NewCalcTool() *CalcTool {
+	return &CalcTool{}
+}
+
+// Name returns the tool name.
+func (t *CalcTool) Name() string {
+	return "calc"
+}
+
+// Description returns a human-readable description.
+func (t *CalcTool) Description() string {
+	return "Evaluates an arithmetic expression (+ - * / % ^, parentheses, sqrt, pow, abs)"
+}
+
+// RequiresConfirmation returns false; evaluating an expression has no side
+// effects.
+func (t *CalcTool) RequiresConfirmation(args map[string]any) bool {
+	return false
+}
+
+// Execute evaluates the "expression" param.
+func (t *CalcTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	expression, err := requireStringArg(t.Name(), argsFromInput(input), "expression")
+	if err != nil {
+		return nil, err
+	}
+	if len(expression) > calcMaxExpressionLen {
+		return nil, NewValidationErrorForField(t.Name(), "expression",
+			fmt.Sprintf("longer than %d characters", calcMaxExpressionLen))
+	}
+
+	value, err := evalExpression(expression)
+	if err != nil {
+		return nil, NewValidationErrorForField(t.Name(), "expression", err.Error())
+	}
+
+	output := NewOutput()
+	output.Result["value"] = value
+	return output.WithData([]byte(strconv.FormatFloat(value, 'g', -1, 64))), nil
+}
+
+// evalExpression evaluates an arithmetic expression using the CalcTool
+// grammar. It returns an error for input outside the grammar, division by
+// zero and results that are not finite.
+func evalExpression(expression string) (float64, error) {
+	p := &calcParser{input: []rune(expression)}
+	value, err := p.parseExpr(0)
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return 0, p.errorf("unexpected %q", string(p.input[p.pos]))
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errors.New("result is not a finite number")
+	}
+	return value, nil
+}
+
+// calcParser is a recursive descent parser that evaluates as it parses:
+//
+//	expr    = term { ("+" | "-") term }
+//	term    = unary { ("*" | "/" | "%") unary }
+//	unary   = ("+" | "-") unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | constant | function "(" expr { "," expr } ")" | "(" expr ")"
+type calcParser struct {
+	input []rune
+	pos   int
+}
+
+func (p *calcParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *calcParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+// peekOp returns the next operator, normalizing the Unicode spellings.
+func (p *calcParser) peekOp() rune {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	switch r := p.input[p.pos]; r {
+	case '×':
+		return '*'
+	case '÷':
+		return '/'
+	case '−':
+		return '-'
+	default:
+		return r
+	}
+}
+
+func (p *calcParser) parseExpr(depth int) (float64, error) {
+	if depth > calcMaxDepth {
+		return 0, p.errorf("expression nested too deeply")
+	}
+	left, err := p.parseTerm(depth)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peekOp()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm(depth)
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			left += right
+		} else {
+			left -= right
+		}
+	}
+}
+
+func (p *calcParser) parseTerm(depth int) (float64, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peekOp()
+		if op != '*' && op != '/' && op != '%' {
+			return left, nil
+		}
+		opPos := p.pos
+		p.pos++
+		right, err := p.parseUnary(depth)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			left *= right
+		case '/', '%':
+			if right == 0 {
+				p.pos = opPos
+				return 0, p.errorf("division by zero")
+			}
+			if op == '/' {
+				left /= right
+			} else {
+				left = math.Mod(left, right)
+			}
+		}
+	}
+}
+
+func (p *calcParser) parseUnary(depth int) (float64, error) {
+	if depth > calcMaxDepth {
+		return 0, p.errorf("expression nested too deeply")
+	}
+	switch p.peekOp() {
+	case '+':
+		p.pos++
+		return p.parseUnary(depth + 1)
+	case '-':
+		p.pos++
+		value, err := p.parseUnary(depth + 1)
+		return -value, err
+	}
+	return p.parsePower(depth)
+}
+
+func (p *calcParser) parsePower(depth int) (float64, error) {
+	base, err := p.parsePrimary(depth)
+	if err != nil {
+		return 0, err
+	}
+	if p.peekOp() != '^' {
+		return base, nil
+	}
+	p.pos++
+	// Right associative: 2^3^2 is 2^(3^2)
+	exponent, err := p.parseUnary(depth + 1)
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exponent), nil
+}
+
+func (p *calcParser) parsePrimary(depth int) (float64, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0, p.errorf("unexpected end of expression")
+	}
+
+	r := p.input[p.pos]
+	switch {
+	case r == '(':
+		p.pos++
+		value, err := p.parseExpr(depth + 1)
+		if err != nil {
+			return 0, err
+		}
+		if err := p.expect(')'); err != nil {
+			return 0, err
+		}
+		return value, nil
+	case r == '.' || ('0' <= r && r <= '9'):
+		return p.parseNumber()
+	case unicode.IsLetter(r):
+		return p.parseName(depth)
+	default:
+		return 0, p.errorf("unexpected %q", string(r))
+	}
+}
+
+func (p *calcParser) parseNumber() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] == '.' || ('0' <= p.input[p.pos] && p.input[p.pos] <= '9')) {
+		p.pos++
+	}
+	// Optional exponent, e.g. 1.5e3
+	if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		next := p.pos + 1
+		if next < len(p.input) && (p.input[next] == '+' || p.input[next] == '-') {
+			next++
+		}
+		if next < len(p.input) && '0' <= p.input[next] && p.input[next] <= '9' {
+			p.pos = next
+			for p.pos < len(p.input) && '0' <= p.input[p.pos] && p.input[p.pos] <= '9' {
+				p.pos++
+			}
+		}
+	}
+
+	text := string(p.input[start:p.pos])
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid number %q", text)
+	}
+	return value, nil
+}
+
+func (p *calcParser) parseName(depth int) (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(p.input[p.pos]) || unicode.IsDigit(p.input[p.pos])) {
+		p.pos++
+	}
+	name := strings.ToLower(string(p.input[start:p.pos]))
+
+	if value, ok := calcConstants[name]; ok {
+		return value, nil
+	}
+	fn, ok := calcFunctions[name]
+	if !ok {
+		p.pos = start
+		return 0, p.errorf("unknown name %q", name)
+	}
+
+	if err := p.expect('('); err != nil {
+		return 0, err
+	}
+	var args []float64
+	if p.peekOp() != ')' {
+		for {
+			value, err := p.parseExpr(depth + 1)
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, value)
+			if p.peekOp() != ',' {
+				break
+			}
+			p.pos++
+		}
+	}
+	if err := p.expect(')'); err != nil {
+		return 0, err
+	}
+	if len(args) != fn.arity {
+		return 0, fmt.Errorf("%s takes %d argument(s), got %d", name, fn.arity, len(args))
+	}
+	value, err := fn.fn(args)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return value, nil
+}
+
+// expect consumes the rune want or reports what was found instead.
+func (p *calcParser) expect(want rune) error {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return p.errorf("expected %q, got end of expression", string(want))
+	}
+	if p.input[p.pos] != want {
+		return p.errorf("expected %q, got %q", string(want), string(p.input[p.pos]))
+	}
+	p.pos++
+	return nil
+}
@@ -0,0 +1,105 @@
+package toolexec
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCalcTool_Evaluate(t *testing.T) {
+	tool := NewCalcTool()
+
+	tests := []struct {
+		expression string
+		want       float64
+	}{
+		{"1 + 2", 3},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 - 4 - 3", 3},
+		{"100 / 10 / 2", 5},
+		{"7 % 4", 3},
+		{"2 ^ 3 ^ 2", 512},
+		{"-2 ^ 2", -4},
+		{"(-2) ^ 2", 4},
+		{"--3", 3},
+		{"6 × 7", 42},
+		{"9 ÷ 3 − 1", 2},
+		{"1.5e3 + .5", 1500.5},
+		{"sqrt(16) + abs(-3)", 7},
+		{"pow(2, 10)", 1024},
+		{"SQRT(pow(3, 2) + pow(4, 2))", 5},
+		{"2 * pi", 2 * math.Pi},
+		{"e", math.E},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			output, err := tool.Execute(context.Background(), NewInput().WithParam("expression", tt.expression))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			got, ok := output.Result["value"].(float64)
+			if !ok || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("value = %v, want %v", output.Result["value"], tt.want)
+			}
+		})
+	}
+}
+
+func TestCalcTool_Rejects(t *testing.T) {
+	tool := NewCalcTool()
+
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"1 / 0", "division by zero"},
+		{"5 % (2 - 2)", "division by zero"},
+		{"sqrt(-1)", "sqrt of a negative number"},
+		{"pow(10, 400)", "not a finite number"},
+		{"os.exit(1)", "unknown name"},
+		{"exec(1)", "unknown name"},
+		{"1 +", "unexpected end of expression"},
+		{"(1 + 2", "expected \")\""},
+		{"1 + 2)", "unexpected \")\""},
+		{"2 3", "unexpected \"3\""},
+		{"1; 2", "unexpected \";\""},
+		{"sqrt(1, 2)", "sqrt takes 1 argument(s), got 2"},
+		{"pow 2", "expected \"(\""},
+		{"1..2", "invalid number"},
+		{"'1' + 1", "unexpected \"'\""},
+		{strings.Repeat("(", 200) + "1" + strings.Repeat(")", 200), "nested too deeply"},
+		{strings.Repeat("1+", 600) + "1", "longer than"},
+		{"   ", "cannot be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := tool.Execute(context.Background(), NewInput().WithParam("expression", tt.expression))
+			if !IsValidationError(err) {
+				t.Fatalf("expected a validation error, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCalcTool_Properties(t *testing.T) {
+	tool := NewCalcTool()
+	if tool.Name() != "calc" {
+		t.Errorf("Name() = %q, want calc", tool.Name())
+	}
+	if tool.RequiresConfirmation(map[string]any{"expression": "1+1"}) {
+		t.Error("calc should not require confirmation")
+	}
+
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("expression", "1/4"))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "0.25" {
+		t.Errorf("Data = %q, want 0.25", output.Data)
+	}
+}
@@ -0,0 +1,148 @@
+package toolexec
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// EnvRedacted replaces the value of environment variables that look like
+// secrets in EnvTool output.
+const EnvRedacted = "[REDACTED]"
+
+// envSecretNamePattern matches variable names that usually hold credentials.
+var envSecretNamePattern = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|credential|api_?key|private_?key|access_?key)`)
+
+// envSecretValuePatterns match values shaped like well-known credentials.
+var envSecretValuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`AIza[0-9A-Za-z_\-]{35}`),                                 // Google API keys
+	regexp.MustCompile(`ya29\.[0-9A-Za-z_\-]+`),                                  // Google OAuth tokens
+	regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),                            // AWS access keys
+	regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36,}\b`),                         // GitHub tokens
+	regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z\-]{10,}`),                        // Slack tokens
+	regexp.MustCompile(`\bsk-[0-9A-Za-z_\-]{20,}`),                               // API secret keys
+	regexp.MustCompile(`\beyJ[0-9A-Za-z_\-]+\.[0-9A-Za-z_\-]+\.[0-9A-Za-z_\-]+`), // JWTs
+	regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`),
+	regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`), // Credentials in URLs
+}
+
+// EnvTool returns the values of allowlisted environment variables.
+//
+// Only variables named in the allowlist can be read; the rest of the
+// environment is never exposed. The optional "names" param narrows the
+// lookup to a subset of the allowlist. Names that are not allowlisted or not
+// set are omitted from the output. Values whose name or content looks like a
+// credential are replaced with EnvRedacted even when allowlisted.
+type EnvTool struct {
+	allowed map[string]bool
+}
+
+// NewEnvTool creates an EnvTool that can read the given variables.
+func NewEnvTool(allowed ...string) *EnvTool {
+	tool := &EnvTool{allowed: make(map[string]bool, len(allowed))}
+	for _, name := range allowed {
+		if name = strings.TrimSpace(name); name != "" {
+			tool.allowed[name] = true
+		}
+	}
+	return tool
+}
+
+// Name returns the tool name.
+func (t *EnvTool) Name() string {
+	return "env"
+}
+
+// Description returns a human-readable description.
+func (t *EnvTool) Description() string {
+	return "Reads allowlisted environment variables"
+}
+
+// RequiresConfirmation returns false; only allowlisted values are exposed.
+func (t *EnvTool) RequiresConfirmation(args map[string]any) bool {
+	return false
+}
+
+// Allowed returns the allowlisted variable names, sorted.
+func (t *EnvTool) Allowed() []string {
+	names := make([]string, 0, len(t.allowed))
+	for name := range t.allowed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute looks up the requested variables.
+func (t *EnvTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	names, err := t.requestedNames(argsFromInput(input))
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	output := NewOutput()
+	for _, name := range names {
+		if !t.allowed[name] {
+			continue
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if isSecretEnv(name, value) {
+			value = EnvRedacted
+		}
+		values[name] = value
+		output.Result[name] = value
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+	output.Metadata[OutputFormatKey] = OutputFormatJSON
+	return output.WithData(data), nil
+}
+
+// requestedNames returns the "names" param, or the whole allowlist if it
+// is absent.
+func (t *EnvTool) requestedNames(args map[string]any) ([]string, error) {
+	switch v := args["names"].(type) {
+	case nil:
+		return t.Allowed(), nil
+	case []string:
+		return v, nil
+	case []any:
+		names := make([]string, 0, len(v))
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				return nil, NewValidationErrorForField(t.Name(), "names", "must be an array of strings")
+			}
+			names = append(names, name)
+		}
+		return names, nil
+	case string:
+		return []string{v}, nil
+	default:
+		return nil, NewValidationErrorForField(t.Name(), "names", "must be an array of strings")
+	}
+}
+
+// isSecretEnv reports whether a variable's name or value looks like a
+// credential.
+func isSecretEnv(name, value string) bool {
+	if envSecretNamePattern.MatchString(name) {
+		return true
+	}
+	for _, pattern := range envSecretValuePatterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
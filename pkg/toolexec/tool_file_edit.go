@@ -0,0 +1,199 @@
+package toolexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/diogo/geminiweb/internal/textdiff"
+)
+
+// editDiffContextLines is the number of unchanged lines shown around each
+// change in the diff returned by EditTool.
+const editDiffContextLines = 3
+
+// EditTool applies search-and-replace substitutions to a file, like sed,
+// and returns a unified diff of what changed.
+//
+// The "search" param is matched literally unless "type" is "regex", in
+// which case it is a Go regular expression and "replace" may reference its
+// groups ($1, ${name}). The optional "count" limits how many matches are
+// replaced, from the start of the file; 0 replaces all of them. With
+// "dry_run" the diff is returned without writing the file.
+type EditTool struct {
+	maxBytes int64
+}
+
+// EditToolOption configures an EditTool.
+type EditToolOption func(*EditTool)
+
+// NewEditTool creates an EditTool with optional configuration.
+func NewEditTool(opts ...EditToolOption) *EditTool {
+	tool := &EditTool{maxBytes: defaultMaxFileBytes}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(tool)
+		}
+	}
+	tool.maxBytes = normalizeMaxFileBytes(tool.maxBytes)
+	return tool
+}
+
+// WithEditMaxBytes sets the maximum file size allowed, before and after
+// the substitutions.
+func WithEditMaxBytes(limit int64) EditToolOption {
+	return func(t *EditTool) {
+		t.maxBytes = limit
+	}
+}
+
+// Name returns the tool name.
+func (t *EditTool) Name() string {
+	return "file_edit"
+}
+
+// Description returns a human-readable description.
+func (t *EditTool) Description() string {
+	return "Replaces text in a file and returns a diff of the changes"
+}
+
+// RequiresConfirmation always returns true for edits, including dry runs.
+func (t *EditTool) RequiresConfirmation(args map[string]any) bool {
+	return true
+}
+
+// Execute applies the substitutions and writes the file unless dry_run is set.
+func (t *EditTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	args := argsFromInput(input)
+	path, err := requireStringArg(t.Name(), args, "path")
+	if err != nil {
+		return nil, err
+	}
+	search, ok := args["search"].(string)
+	if !ok || search == "" {
+		return nil, NewValidationErrorForField(t.Name(), "search", "must be a non-empty string")
+	}
+	replace, ok := args["replace"].(string)
+	if !ok {
+		return nil, NewValidationErrorForField(t.Name(), "replace", "must be a string")
+	}
+	count, _, err := parseIntArg(args["count"])
+	if err != nil {
+		return nil, NewValidationErrorForField(t.Name(), "count", err.Error())
+	}
+	if count < 0 {
+		return nil, NewValidationErrorForField(t.Name(), "count", "must not be negative")
+	}
+	dryRun := false
+	if raw, exists := args["dry_run"]; exists && raw != nil {
+		if dryRun, ok = raw.(bool); !ok {
+			return nil, NewValidationErrorForField(t.Name(), "dry_run", "must be a boolean")
+		}
+	}
+
+	matchType := "literal"
+	if rawType, ok := optionalStringArg(args, "type"); ok {
+		matchType = strings.ToLower(rawType)
+	}
+	var substitute func(content string) (string, int)
+	switch matchType {
+	case "literal":
+		substitute = func(content string) (string, int) {
+			return replaceLiteral(content, search, replace, count)
+		}
+	case "regex", "regexp":
+		re, err := regexp.Compile(search)
+		if err != nil {
+			return nil, NewValidationErrorForField(t.Name(), "search", err.Error())
+		}
+		substitute = func(content string) (string, int) {
+			return replaceRegex(content, re, replace, count)
+		}
+	default:
+		return nil, NewValidationErrorForField(t.Name(), "type", "must be 'literal' or 'regex'")
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+	if info.IsDir() {
+		return nil, NewValidationErrorForField(t.Name(), "path", "path is a directory")
+	}
+	if info.Size() > t.maxBytes {
+		return nil, NewValidationErrorForField(t.Name(), "path", "file exceeds size limit")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+
+	original := string(data)
+	edited, replaced := substitute(original)
+	if int64(len(edited)) > t.maxBytes {
+		return nil, NewValidationErrorForField(t.Name(), "replace", "edited file exceeds size limit")
+	}
+
+	diff, err := textdiff.Unified(path, path, strings.Split(original, "\n"), strings.Split(edited, "\n"), editDiffContextLines)
+	if err != nil {
+		// The edit still applies; only the diff is left out
+		diff = "file too large to diff"
+	}
+	output := NewOutput().WithData([]byte(diff))
+	output.Result["replacements"] = replaced
+	output.Result["dry_run"] = dryRun
+
+	switch {
+	case replaced == 0:
+		output.Message = "no matches found"
+		output.Data = []byte(output.Message)
+		return output, nil
+	case dryRun:
+		output.Message = fmt.Sprintf("would replace %d occurrence(s) in %s", replaced, path)
+		return output, nil
+	}
+
+	if err := os.WriteFile(path, []byte(edited), info.Mode().Perm()); err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+	output.Message = fmt.Sprintf("replaced %d occurrence(s) in %s", replaced, path)
+	return output, nil
+}
+
+// replaceLiteral replaces up to limit occurrences of search (all if limit
+// is 0) and reports how many were replaced.
+func replaceLiteral(content, search, replace string, limit int) (string, int) {
+	n := strings.Count(content, search)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	return strings.Replace(content, search, replace, n), n
+}
+
+// replaceRegex replaces up to limit matches of re (all if limit is 0),
+// expanding group references in replace, and reports how many were replaced.
+func replaceRegex(content string, re *regexp.Regexp, replace string, limit int) (string, int) {
+	if limit == 0 {
+		limit = -1
+	}
+	matches := re.FindAllStringSubmatchIndex(content, limit)
+	if len(matches) == 0 {
+		return content, 0
+	}
+
+	var result []byte
+	last := 0
+	for _, match := range matches {
+		result = append(result, content[last:match[0]]...)
+		result = re.ExpandString(result, replace, content, match)
+		last = match[1]
+	}
+	result = append(result, content[last:]...)
+	return string(result), len(matches)
+}
@@ -0,0 +1,294 @@
+package toolexec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/diogo/geminiweb/internal/textdiff"
+)
+
+// writeEditFixture creates a file with content in a temp dir and returns its path
+func writeEditFixture(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func readEditFixture(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	return string(data)
+}
+
+func TestEditTool_LiteralReplace(t *testing.T) {
+	path := writeEditFixture(t, "a := 1\nb := a + a\nc := 3\n")
+
+	output, err := NewEditTool().Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "a").
+			WithParam("replace", "x"),
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if got, want := readEditFixture(t, path), "x := 1\nb := x + x\nc := 3\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+	if output.Result["replacements"] != 3 {
+		t.Errorf("replacements = %v, want 3", output.Result["replacements"])
+	}
+	if !strings.Contains(output.Message, "replaced 3") {
+		t.Errorf("message = %q", output.Message)
+	}
+	diff := string(output.Data)
+	for _, want := range []string{"--- " + path, "+++ " + path, "-a := 1", "+x := 1", "-b := a + a", "+b := x + x", " c := 3"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+}
+
+func TestEditTool_RegexReplace(t *testing.T) {
+	path := writeEditFixture(t, "foo(1)\nbar(2)\nfoo(3)\n")
+
+	output, err := NewEditTool().Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", `foo\((\d+)\)`).
+			WithParam("replace", "baz($1, true)").
+			WithParam("type", "regex"),
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if got, want := readEditFixture(t, path), "baz(1, true)\nbar(2)\nbaz(3, true)\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+	if output.Result["replacements"] != 2 {
+		t.Errorf("replacements = %v, want 2", output.Result["replacements"])
+	}
+
+	_, err = NewEditTool().Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "foo(").
+			WithParam("replace", "x").
+			WithParam("type", "regex"),
+	)
+	if !IsValidationError(err) {
+		t.Errorf("invalid regex: expected validation error, got %v", err)
+	}
+}
+
+func TestEditTool_Count(t *testing.T) {
+	tests := []struct {
+		name  string
+		typ   string
+		count any
+		want  string
+	}{
+		{name: "literal", typ: "literal", count: 2, want: "y y x x\n"},
+		{name: "regex", typ: "regex", count: float64(1), want: "y x x x\n"},
+		{name: "zero replaces all", typ: "literal", count: 0, want: "y y y y\n"},
+		{name: "count above matches", typ: "literal", count: "10", want: "y y y y\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeEditFixture(t, "x x x x\n")
+			_, err := NewEditTool().Execute(context.Background(),
+				NewInput().
+					WithParam("path", path).
+					WithParam("search", "x").
+					WithParam("replace", "y").
+					WithParam("type", tt.typ).
+					WithParam("count", tt.count),
+			)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := readEditFixture(t, path); got != tt.want {
+				t.Errorf("file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditTool_DryRun(t *testing.T) {
+	original := "hello world\n"
+	path := writeEditFixture(t, original)
+
+	output, err := NewEditTool().Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "world").
+			WithParam("replace", "there").
+			WithParam("dry_run", true),
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if got := readEditFixture(t, path); got != original {
+		t.Errorf("dry run modified the file: %q", got)
+	}
+	if !strings.Contains(string(output.Data), "+hello there") {
+		t.Errorf("dry run should return the diff, got:\n%s", output.Data)
+	}
+	if output.Result["dry_run"] != true || !strings.Contains(output.Message, "would replace 1") {
+		t.Errorf("unexpected dry run output: %+v", output)
+	}
+}
+
+func TestEditTool_NoMatches(t *testing.T) {
+	path := writeEditFixture(t, "hello\n")
+
+	output, err := NewEditTool().Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "absent").
+			WithParam("replace", "x"),
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if output.Message != "no matches found" || output.Result["replacements"] != 0 {
+		t.Errorf("unexpected output: %+v", output)
+	}
+}
+
+func TestEditTool_Validation(t *testing.T) {
+	path := writeEditFixture(t, "hello\n")
+
+	tests := []struct {
+		name   string
+		params map[string]any
+	}{
+		{name: "missing path", params: map[string]any{"search": "a", "replace": "b"}},
+		{name: "empty search", params: map[string]any{"path": path, "search": "", "replace": "b"}},
+		{name: "missing replace", params: map[string]any{"path": path, "search": "a"}},
+		{name: "negative count", params: map[string]any{"path": path, "search": "a", "replace": "b", "count": -1}},
+		{name: "bad dry_run", params: map[string]any{"path": path, "search": "a", "replace": "b", "dry_run": "yes"}},
+		{name: "bad type", params: map[string]any{"path": path, "search": "a", "replace": "b", "type": "glob"}},
+		{name: "directory", params: map[string]any{"path": filepath.Dir(path), "search": "a", "replace": "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEditTool().Execute(context.Background(), &Input{Params: tt.params})
+			if !IsValidationError(err) {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEditTool_SizeLimit(t *testing.T) {
+	path := writeEditFixture(t, "abc")
+
+	_, err := NewEditTool(WithEditMaxBytes(5)).Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "b").
+			WithParam("replace", "long replacement"),
+	)
+	if !IsValidationError(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if got := readEditFixture(t, path); got != "abc" {
+		t.Errorf("file should be unchanged, got %q", got)
+	}
+}
+
+func TestEditTool_PathValidatorRejects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("SECRET=1\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	registry := NewRegistryWithOptions(WithTools(NewEditTool()))
+	exec := NewExecutor(registry, WithSecurityPolicy(DefaultPathValidator()))
+
+	_, err := exec.Execute(context.Background(), "file_edit",
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "1").
+			WithParam("replace", "2"),
+	)
+	if !IsSecurityViolationError(err) {
+		t.Fatalf("expected SecurityViolationError, got %v", err)
+	}
+	if got := readEditFixture(t, path); got != "SECRET=1\n" {
+		t.Errorf("blocked file should be unchanged, got %q", got)
+	}
+}
+
+func TestEditTool_FilesystemRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("old\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	registry := NewRegistryWithOptions(WithTools(NewEditTool()))
+	exec := NewExecutor(registry, WithFilesystemRoot(root))
+	ctx := context.Background()
+
+	input := NewInput().WithParam("path", "a.txt").WithParam("search", "old").WithParam("replace", "new")
+	if _, err := exec.Execute(ctx, "file_edit", input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got := readEditFixture(t, filepath.Join(root, "a.txt")); got != "new\n" {
+		t.Errorf("file = %q, want edited inside root", got)
+	}
+
+	input = NewInput().WithParam("path", "../outside.txt").WithParam("search", "a").WithParam("replace", "b")
+	if _, err := exec.Execute(ctx, "file_edit", input); !IsSecurityViolationError(err) {
+		t.Fatalf("expected SecurityViolationError, got %v", err)
+	}
+}
+
+func TestEditTool_RequiresConfirmation(t *testing.T) {
+	tool := NewEditTool()
+	if !tool.RequiresConfirmation(map[string]any{"dry_run": true}) {
+		t.Fatal("RequiresConfirmation() = false, want true")
+	}
+}
+
+func TestEditTool_TooLargeToDiff(t *testing.T) {
+	var content strings.Builder
+	for i := 0; i < textdiff.MaxLines; i++ {
+		fmt.Fprintf(&content, "line %d\n", i)
+	}
+	path := writeEditFixture(t, content.String())
+
+	output, err := NewEditTool().Execute(context.Background(),
+		NewInput().
+			WithParam("path", path).
+			WithParam("search", "line").
+			WithParam("replace", "row"),
+	)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "file too large to diff" {
+		t.Errorf("Data = %.80q, want the too large notice", output.Data)
+	}
+	if got := readEditFixture(t, path); !strings.HasPrefix(got, "row 0\n") {
+		t.Errorf("file should still be edited, got %.20q", got)
+	}
+}
@@ -6,9 +6,16 @@ import (
 	"context"
 	"io"
 	"os"
+	"strconv"
 )
 
 // FileReadTool reads file contents from disk.
+//
+// Large files can be paged through with the optional 1-based, inclusive
+// "start_line" and "end_line" params. Ranged reads stream the file, so they
+// are not subject to the max file size; the output reports the file's
+// "total_bytes" and "total_lines" in its metadata so the caller can request
+// the next span.
 type FileReadTool struct {
 	maxBytes      int64
 	maxOutputSize int
@@ -78,6 +85,25 @@ func (t *FileReadTool) Execute(ctx context.Context, input *Input) (*Output, erro
 		return nil, NewValidationErrorForField(t.Name(), "lines", "must be >= 0")
 	}
 
+	startLine, hasStart, err := parseIntArg(args["start_line"])
+	if err != nil {
+		return nil, NewValidationErrorForField(t.Name(), "start_line", err.Error())
+	}
+	if hasStart && startLine < 1 {
+		return nil, NewValidationErrorForField(t.Name(), "start_line", "must be >= 1")
+	}
+	endLine, hasEnd, err := parseIntArg(args["end_line"])
+	if err != nil {
+		return nil, NewValidationErrorForField(t.Name(), "end_line", err.Error())
+	}
+	if hasEnd && endLine < 1 {
+		return nil, NewValidationErrorForField(t.Name(), "end_line", "must be >= 1")
+	}
+	if hasStart && hasEnd && endLine < startLine {
+		return nil, NewValidationErrorForField(t.Name(), "end_line", "must be >= start_line")
+	}
+	ranged := hasStart || hasEnd
+
 	info, err := os.Stat(path)
 	if err != nil {
 		return nil, NewExecutionErrorWithCause(t.Name(), err)
@@ -85,10 +111,24 @@ func (t *FileReadTool) Execute(ctx context.Context, input *Input) (*Output, erro
 	if info.IsDir() {
 		return nil, NewValidationErrorForField(t.Name(), "path", "path is a directory")
 	}
-	if info.Size() > t.maxBytes {
+	if !ranged && info.Size() > t.maxBytes {
 		return nil, NewValidationErrorForField(t.Name(), "path", "file exceeds size limit")
 	}
 
+	if ranged {
+		if !hasStart {
+			startLine = 1
+		}
+		if !hasEnd {
+			// "lines" limits the span when no end is given
+			endLine = 0
+			if hasLines && lines > 0 {
+				endLine = startLine + lines - 1
+			}
+		}
+		return t.readRange(ctx, path, info.Size(), startLine, endLine)
+	}
+
 	if hasLines && lines > 0 {
 		return t.readLines(ctx, path, lines)
 	}
@@ -98,7 +138,9 @@ func (t *FileReadTool) Execute(ctx context.Context, input *Input) (*Output, erro
 		return nil, NewExecutionErrorWithCause(t.Name(), err)
 	}
 
-	return NewOutput().WithTruncatedData(data, t.maxOutputSize), nil
+	return NewOutput().
+		WithTruncatedData(data, t.maxOutputSize).
+		WithMetadata("total_bytes", strconv.FormatInt(info.Size(), 10)), nil
 }
 
 func (t *FileReadTool) readLines(ctx context.Context, path string, maxLines int) (*Output, error) {
@@ -135,3 +177,56 @@ func (t *FileReadTool) readLines(ctx context.Context, path string, maxLines int)
 	output.Truncated = truncated
 	return output, nil
 }
+
+// readRange returns lines start..end (1-based, inclusive) of the file.
+// An end of 0 reads to the end of the file. Requests past the end of the
+// file are clamped; a start beyond the last line yields empty data. The
+// whole file is scanned so the total line count can be reported.
+func (t *FileReadTool) readRange(ctx context.Context, path string, size int64, start, end int) (*Output, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	var buf bytes.Buffer
+	truncated := false
+	totalLines := 0
+	lastLine := 0
+
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		chunk, err := reader.ReadBytes('\n')
+		if len(chunk) > 0 {
+			totalLines++
+			inRange := totalLines >= start && (end == 0 || totalLines <= end)
+			if inRange && !truncated {
+				if appendBytesWithLimit(&buf, chunk, t.maxOutputSize) {
+					truncated = true
+				} else {
+					lastLine = totalLines
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, NewExecutionErrorWithCause(t.Name(), err)
+		}
+	}
+
+	output := NewOutput().
+		WithData(buf.Bytes()).
+		WithMetadata("total_bytes", strconv.FormatInt(size, 10)).
+		WithMetadata("total_lines", strconv.Itoa(totalLines))
+	if lastLine > 0 {
+		output.WithMetadata("start_line", strconv.Itoa(start)).
+			WithMetadata("end_line", strconv.Itoa(lastLine))
+	}
+	output.Truncated = truncated
+	return output, nil
+}
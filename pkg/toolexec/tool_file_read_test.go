@@ -2,8 +2,11 @@ package toolexec
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -72,3 +75,152 @@ func TestFileReadTool_InvalidLines(t *testing.T) {
 		t.Fatalf("expected validation error, got %v", err)
 	}
 }
+
+func writeNumberedLines(t *testing.T, n int) string {
+	t.Helper()
+	var sb strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "line%d\n", i)
+	}
+	path := filepath.Join(t.TempDir(), "log.txt")
+	if err := os.WriteFile(path, []byte(sb.String()), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestFileReadTool_ReadRange(t *testing.T) {
+	path := writeNumberedLines(t, 10)
+	tool := NewFileReadTool()
+
+	tests := []struct {
+		name      string
+		params    map[string]any
+		want      string
+		wantStart string
+		wantEnd   string
+	}{
+		{"middle span", map[string]any{"start_line": 3, "end_line": 5}, "line3\nline4\nline5\n", "3", "5"},
+		{"start only reads to end", map[string]any{"start_line": 9}, "line9\nline10\n", "9", "10"},
+		{"end only reads from start", map[string]any{"end_line": 2}, "line1\nline2\n", "1", "2"},
+		{"start with lines count", map[string]any{"start_line": 4, "lines": 2}, "line4\nline5\n", "4", "5"},
+		{"float params from JSON", map[string]any{"start_line": 2.0, "end_line": 2.0}, "line2\n", "2", "2"},
+		{"end past EOF is clamped", map[string]any{"start_line": 8, "end_line": 100}, "line8\nline9\nline10\n", "8", "10"},
+		{"start past EOF is empty", map[string]any{"start_line": 50, "end_line": 60}, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := NewInput().WithParam("path", path)
+			for k, v := range tt.params {
+				input.WithParam(k, v)
+			}
+			output, err := tool.Execute(context.Background(), input)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if string(output.Data) != tt.want {
+				t.Errorf("Data = %q, want %q", output.Data, tt.want)
+			}
+			if output.Metadata["total_lines"] != "10" {
+				t.Errorf("total_lines = %q, want 10", output.Metadata["total_lines"])
+			}
+			if output.Metadata["total_bytes"] != "61" {
+				t.Errorf("total_bytes = %q, want 61", output.Metadata["total_bytes"])
+			}
+			if output.Metadata["start_line"] != tt.wantStart || output.Metadata["end_line"] != tt.wantEnd {
+				t.Errorf("span = %q-%q, want %q-%q", output.Metadata["start_line"], output.Metadata["end_line"], tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFileReadTool_ReadRange_WholeFileWithoutRange(t *testing.T) {
+	path := writeNumberedLines(t, 3)
+
+	output, err := NewFileReadTool().Execute(context.Background(), NewInput().WithParam("path", path))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "line1\nline2\nline3\n" {
+		t.Errorf("Data = %q, want whole file", output.Data)
+	}
+	if output.Metadata["total_bytes"] != "18" {
+		t.Errorf("total_bytes = %q, want 18", output.Metadata["total_bytes"])
+	}
+}
+
+func TestFileReadTool_ReadRange_IgnoresMaxBytes(t *testing.T) {
+	path := writeNumberedLines(t, 10)
+
+	tool := NewFileReadTool(WithFileReadMaxBytes(10))
+	input := NewInput().WithParam("path", path).WithParam("start_line", 10)
+	output, err := tool.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "line10\n" {
+		t.Errorf("Data = %q, want line10", output.Data)
+	}
+}
+
+func TestFileReadTool_ReadRange_Invalid(t *testing.T) {
+	path := writeNumberedLines(t, 3)
+	tool := NewFileReadTool()
+
+	for _, params := range []map[string]any{
+		{"start_line": 0},
+		{"end_line": -1},
+		{"start_line": 3, "end_line": 2},
+		{"start_line": 1.5},
+	} {
+		input := NewInput().WithParam("path", path)
+		for k, v := range params {
+			input.WithParam(k, v)
+		}
+		if _, err := tool.Execute(context.Background(), input); !IsValidationError(err) {
+			t.Errorf("params %v: expected validation error, got %v", params, err)
+		}
+	}
+}
+
+func TestFileReadTool_ReadRange_ContextCancelled(t *testing.T) {
+	path := writeNumberedLines(t, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	input := NewInput().WithParam("path", path).WithParam("start_line", 1)
+	if _, err := NewFileReadTool().Execute(ctx, input); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFileReadTool_ReadRange_FilesystemRoot(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "in.txt"), []byte("a\nb\nc\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	outside := writeNumberedLines(t, 3)
+
+	registry := NewRegistry()
+	if err := registry.Register(NewFileReadTool()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	exec := NewExecutor(registry, WithFilesystemRoot(root))
+
+	output, err := exec.Execute(context.Background(), "file_read",
+		NewInput().WithParam("path", "in.txt").WithParam("start_line", 2).WithParam("end_line", 2))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if string(output.Data) != "b\n" {
+		t.Errorf("Data = %q, want b", output.Data)
+	}
+
+	_, err = exec.Execute(context.Background(), "file_read",
+		NewInput().WithParam("path", outside).WithParam("start_line", 1))
+	if !IsSecurityViolationError(err) {
+		t.Errorf("expected security violation for path outside root, got %v", err)
+	}
+}
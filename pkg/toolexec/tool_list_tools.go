@@ -0,0 +1,63 @@
+package toolexec
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ListToolsTool lists the tools available in a registry, so a model can
+// discover what it can call.
+//
+// The tools are returned in Output.Result["tools"] as a []ToolInfo (name,
+// description and any tags), sorted by name, and as a JSON array in the
+// output data. The tool leaves itself out of the list.
+type ListToolsTool struct {
+	registry Registry
+}
+
+// NewListToolsTool creates a ListToolsTool for registry.
+// If registry is nil, the default global registry is used.
+func NewListToolsTool(registry Registry) *ListToolsTool {
+	if registry == nil {
+		registry = DefaultRegistry()
+	}
+	return &ListToolsTool{registry: registry}
+}
+
+// Name returns the tool name.
+func (t *ListToolsTool) Name() string {
+	return "list_tools"
+}
+
+// Description returns a human-readable description.
+func (t *ListToolsTool) Description() string {
+	return "Lists the available tools with their descriptions"
+}
+
+// RequiresConfirmation returns false; listing tools has no side effects.
+func (t *ListToolsTool) RequiresConfirmation(args map[string]any) bool {
+	return false
+}
+
+// Execute lists the registered tools other than itself.
+func (t *ListToolsTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	tools := make([]ToolInfo, 0)
+	for _, info := range t.registry.List() {
+		if info.Name != t.Name() {
+			tools = append(tools, info)
+		}
+	}
+
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+	output := NewOutput().WithData(data)
+	output.Result["tools"] = tools
+	output.Metadata[OutputFormatKey] = OutputFormatJSON
+	return output, nil
+}
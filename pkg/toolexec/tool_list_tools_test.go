@@ -0,0 +1,119 @@
+package toolexec
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// taggedMockTool is a MockTool that declares tags.
+type taggedMockTool struct {
+	*MockTool
+	tags []string
+}
+
+func (t *taggedMockTool) Tags() []string {
+	return t.tags
+}
+
+func TestListToolsTool_ListsOtherTools(t *testing.T) {
+	registry := NewRegistryWithOptions(
+		WithTools(
+			NewCalcTool(),
+			&taggedMockTool{MockTool: NewMockTool("reader", "Reads things"), tags: []string{"filesystem", "read-only"}},
+		),
+		WithListToolsTool(),
+	)
+	if !registry.Has("list_tools") {
+		t.Fatal("WithListToolsTool() should register list_tools")
+	}
+
+	output, err := NewExecutor(registry).Execute(context.Background(), "list_tools", NewInput())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	tools, ok := output.Result["tools"].([]ToolInfo)
+	if !ok {
+		t.Fatalf("Result[\"tools\"] = %T, want []ToolInfo", output.Result["tools"])
+	}
+	want := []ToolInfo{
+		{Name: "calc", Description: NewCalcTool().Description()},
+		{Name: "reader", Description: "Reads things", Tags: []string{"filesystem", "read-only"}},
+	}
+	if !reflect.DeepEqual(tools, want) {
+		t.Errorf("tools = %+v, want %+v (without list_tools)", tools, want)
+	}
+
+	var fromData []map[string]any
+	if err := json.Unmarshal(output.Data, &fromData); err != nil {
+		t.Fatalf("output data is not JSON: %v\n%s", err, output.Data)
+	}
+	if len(fromData) != 2 || fromData[0]["name"] != "calc" || fromData[1]["name"] != "reader" {
+		t.Errorf("output data = %s", output.Data)
+	}
+	if _, ok := fromData[0]["tags"]; ok {
+		t.Errorf("untagged tools should omit tags: %s", output.Data)
+	}
+	if output.Metadata[OutputFormatKey] != OutputFormatJSON {
+		t.Errorf("format = %q, want json", output.Metadata[OutputFormatKey])
+	}
+}
+
+func TestListToolsTool_ReflectsRegistryChanges(t *testing.T) {
+	registry := NewRegistry()
+	tool := NewListToolsTool(registry)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	output, err := tool.Execute(context.Background(), NewInput())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if tools := output.Result["tools"].([]ToolInfo); len(tools) != 0 {
+		t.Errorf("tools = %+v, want none besides itself", tools)
+	}
+	if string(output.Data) != "[]" {
+		t.Errorf("data = %s, want []", output.Data)
+	}
+
+	if err := registry.Register(NewCalcTool()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	output, _ = tool.Execute(context.Background(), NewInput())
+	if tools := output.Result["tools"].([]ToolInfo); len(tools) != 1 || tools[0].Name != "calc" {
+		t.Errorf("tools = %+v, want the newly registered calc", tools)
+	}
+	if tool.RequiresConfirmation(nil) {
+		t.Error("RequiresConfirmation() = true, want false")
+	}
+}
+
+func TestListToolsTool_ClonedRegistryListsItsOwnTools(t *testing.T) {
+	registry := NewRegistryWithOptions(WithTools(NewCalcTool()), WithListToolsTool())
+	clone := registry.Clone()
+	if err := clone.Register(NewMockTool("scoped", "Request-scoped tool")); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	names := func(r Registry) []string {
+		output, err := NewExecutor(r).Execute(context.Background(), "list_tools", NewInput())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		var names []string
+		for _, info := range output.Result["tools"].([]ToolInfo) {
+			names = append(names, info.Name)
+		}
+		return names
+	}
+
+	if got := names(clone); !reflect.DeepEqual(got, []string{"calc", "scoped"}) {
+		t.Errorf("clone lists %v, want [calc scoped]", got)
+	}
+	if got := names(registry); !reflect.DeepEqual(got, []string{"calc"}) {
+		t.Errorf("original lists %v, want [calc]", got)
+	}
+}
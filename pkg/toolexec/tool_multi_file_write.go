@@ -0,0 +1,287 @@
+package toolexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MultiFileWriteTool writes several files in one step, e.g. to scaffold a
+// project. Each entry of the "files" param is {path, content, append}.
+//
+// All entries are validated before anything is written. If a write fails,
+// the files already written in the batch are restored to their previous
+// contents (or removed if they were created) along with any directories
+// created for them, so the batch is applied all-or-nothing as far as the
+// filesystem allows.
+type MultiFileWriteTool struct {
+	writer *FileWriteTool
+}
+
+// fileWriteEntry is a single validated entry of a multi-file write.
+type fileWriteEntry struct {
+	path   string
+	data   []byte
+	append bool
+}
+
+// fileBackup records the state of a path before the batch touched it.
+type fileBackup struct {
+	path        string
+	existed     bool
+	data        []byte
+	mode        os.FileMode
+	opened      bool     // The target was opened for writing
+	createdDirs []string // Deepest first
+}
+
+// NewMultiFileWriteTool creates a MultiFileWriteTool. It accepts the same
+// options as NewFileWriteTool; limits apply to each file.
+func NewMultiFileWriteTool(opts ...FileWriteToolOption) *MultiFileWriteTool {
+	return &MultiFileWriteTool{writer: NewFileWriteTool(opts...)}
+}
+
+// Name returns the tool name.
+func (t *MultiFileWriteTool) Name() string {
+	return "multi_file_write"
+}
+
+// Description returns a human-readable description.
+func (t *MultiFileWriteTool) Description() string {
+	return "Writes several files at once, rolling back on failure"
+}
+
+// RequiresConfirmation always returns true for writes.
+func (t *MultiFileWriteTool) RequiresConfirmation(args map[string]any) bool {
+	return true
+}
+
+// ConfirmationSummary lists every path the batch will write.
+func (t *MultiFileWriteTool) ConfirmationSummary(args map[string]any) string {
+	items, _ := args["files"].([]any)
+	if items == nil {
+		if maps, ok := args["files"].([]map[string]any); ok {
+			for _, m := range maps {
+				items = append(items, m)
+			}
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Write %d file(s):", len(items))
+	for _, item := range items {
+		entry, _ := item.(map[string]any)
+		path, _ := entry["path"].(string)
+		if path == "" {
+			path = "(missing path)"
+		}
+		mode := "write"
+		if appendMode, _ := entry["append"].(bool); appendMode {
+			mode = "append"
+		}
+		fmt.Fprintf(&sb, "\n  - %s (%s)", path, mode)
+	}
+	return sb.String()
+}
+
+// Execute validates and writes all files, rolling back on failure.
+func (t *MultiFileWriteTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	entries, err := t.parseEntries(argsFromInput(input))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []fileBackup
+	total := 0
+	for i, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, t.rollback(backups, ctx.Err())
+		}
+
+		backup, err := t.writeEntry(entry)
+		if err != nil {
+			cause := fmt.Errorf("files[%d] %s: %w", i, entry.path, err)
+			return nil, t.rollback(append(backups, backup), cause)
+		}
+		backups = append(backups, backup)
+		total += len(entry.data)
+	}
+
+	paths := make([]string, len(entries))
+	for i, entry := range entries {
+		paths[i] = entry.path
+	}
+
+	return NewOutput().
+		WithMessage(fmt.Sprintf("wrote %d bytes to %d files", total, len(entries))).
+		WithResult("paths", paths), nil
+}
+
+// parseEntries validates the "files" param before anything is written.
+func (t *MultiFileWriteTool) parseEntries(args map[string]any) ([]fileWriteEntry, error) {
+	var items []any
+	switch v := args["files"].(type) {
+	case []any:
+		items = v
+	case []map[string]any:
+		for _, m := range v {
+			items = append(items, m)
+		}
+	case nil:
+		return nil, NewValidationErrorForField(t.Name(), "files", "missing required field")
+	default:
+		return nil, NewValidationErrorForField(t.Name(), "files", "must be an array of {path, content, append}")
+	}
+	if len(items) == 0 {
+		return nil, NewValidationErrorForField(t.Name(), "files", "must not be empty")
+	}
+
+	entries := make([]fileWriteEntry, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		field := fmt.Sprintf("files[%d]", i)
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, NewValidationErrorForField(t.Name(), field, "must be an object")
+		}
+
+		path, err := requireStringArg(t.Name(), obj, "path")
+		if err != nil {
+			return nil, NewValidationErrorForField(t.Name(), field+".path", "must be a non-empty string")
+		}
+		content, ok := obj["content"].(string)
+		if !ok {
+			return nil, NewValidationErrorForField(t.Name(), field+".content", "must be a string")
+		}
+		appendMode := false
+		if raw, exists := obj["append"]; exists && raw != nil {
+			if appendMode, ok = raw.(bool); !ok {
+				return nil, NewValidationErrorForField(t.Name(), field+".append", "must be a boolean")
+			}
+		}
+
+		if int64(len(content)) > t.writer.maxBytes {
+			return nil, NewValidationErrorForField(t.Name(), field+".content", "content exceeds size limit")
+		}
+		if info, statErr := os.Stat(path); statErr == nil && info.IsDir() {
+			return nil, NewValidationErrorForField(t.Name(), field+".path", "path is a directory")
+		}
+
+		clean := filepath.Clean(path)
+		if seen[clean] {
+			return nil, NewValidationErrorForField(t.Name(), field+".path", "duplicate path in batch")
+		}
+		seen[clean] = true
+
+		entries = append(entries, fileWriteEntry{path: path, data: []byte(content), append: appendMode})
+	}
+	return entries, nil
+}
+
+// writeEntry backs up the target and writes one entry. The returned backup
+// is valid even on error so a partial write can be undone.
+func (t *MultiFileWriteTool) writeEntry(entry fileWriteEntry) (fileBackup, error) {
+	backup := fileBackup{path: entry.path}
+
+	if info, err := os.Stat(entry.path); err == nil {
+		data, err := os.ReadFile(entry.path)
+		if err != nil {
+			return backup, err
+		}
+		backup.existed, backup.data, backup.mode = true, data, info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return backup, err
+	}
+
+	if t.writer.createDirs {
+		created, err := mkdirAllTracked(filepath.Dir(entry.path), t.writer.dirPerm)
+		backup.createdDirs = created
+		if err != nil {
+			return backup, err
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if entry.append {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	file, err := os.OpenFile(entry.path, flags, t.writer.filePerm)
+	if err != nil {
+		return backup, err
+	}
+	backup.opened = true
+	if _, err := file.Write(entry.data); err != nil {
+		_ = file.Close()
+		return backup, err
+	}
+	return backup, file.Close()
+}
+
+// rollback undoes the given writes in reverse order and returns an
+// ExecutionError describing the failure (and any rollback problems).
+func (t *MultiFileWriteTool) rollback(backups []fileBackup, cause error) error {
+	var problems []string
+	for i := len(backups) - 1; i >= 0; i-- {
+		b := backups[i]
+		var err error
+		switch {
+		case !b.opened:
+			// Nothing was written to this path
+		case b.existed:
+			err = os.WriteFile(b.path, b.data, b.mode)
+		default:
+			if removeErr := os.Remove(b.path); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
+				err = removeErr
+			}
+		}
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+		for _, dir := range b.createdDirs {
+			_ = os.Remove(dir) // Only succeeds if still empty
+		}
+	}
+
+	if len(problems) > 0 {
+		cause = fmt.Errorf("%w (rollback incomplete: %s)", cause, strings.Join(problems, "; "))
+	} else {
+		cause = fmt.Errorf("%w (rolled back)", cause)
+	}
+	return NewExecutionErrorWithCause(t.Name(), cause)
+}
+
+// mkdirAllTracked is like os.MkdirAll but returns the directories it
+// created, deepest first, so they can be removed again.
+func mkdirAllTracked(dir string, perm os.FileMode) ([]string, error) {
+	if dir == "." || dir == "" {
+		return nil, nil
+	}
+
+	var missing []string
+	for current := dir; ; {
+		if _, err := os.Stat(current); err == nil {
+			break
+		}
+		missing = append(missing, current)
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+		current = parent
+	}
+
+	if err := os.MkdirAll(dir, perm); err != nil {
+		// Report only the directories that were actually created
+		var created []string
+		for _, d := range missing {
+			if info, statErr := os.Stat(d); statErr == nil && info.IsDir() {
+				created = append(created, d)
+			}
+		}
+		return created, err
+	}
+	return missing, nil
+}
@@ -0,0 +1,216 @@
+package toolexec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fileEntry(path, content string, appendMode bool) map[string]any {
+	return map[string]any{"path": path, "content": content, "append": appendMode}
+}
+
+func TestMultiFileWriteTool_Write(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "log.txt")
+	if err := os.WriteFile(existing, []byte("first\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tool := NewMultiFileWriteTool()
+	output, err := tool.Execute(context.Background(), NewInput().WithParam("files", []any{
+		fileEntry(filepath.Join(dir, "cmd", "app", "main.go"), "package main\n", false),
+		fileEntry(filepath.Join(dir, "README.md"), "# app\n", false),
+		fileEntry(existing, "second\n", true),
+	}))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(output.Message, "to 3 files") {
+		t.Errorf("unexpected output message: %q", output.Message)
+	}
+	if paths, _ := output.Result["paths"].([]string); len(paths) != 3 {
+		t.Errorf("paths result = %v, want 3 paths", output.Result["paths"])
+	}
+
+	want := map[string]string{
+		filepath.Join(dir, "cmd", "app", "main.go"): "package main\n",
+		filepath.Join(dir, "README.md"):             "# app\n",
+		existing:                                    "first\nsecond\n",
+	}
+	for path, content := range want {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) error = %v", path, err)
+		}
+		if string(data) != content {
+			t.Errorf("%s content = %q, want %q", path, string(data), content)
+		}
+	}
+}
+
+func TestMultiFileWriteTool_RollbackOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(existing, []byte("original"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	// A regular file where a directory is needed makes the last write fail
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, []byte("x"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	created := filepath.Join(dir, "new", "deep", "file.txt")
+	tool := NewMultiFileWriteTool()
+	_, err := tool.Execute(context.Background(), NewInput().WithParam("files", []any{
+		fileEntry(created, "new content", false),
+		fileEntry(existing, "overwritten", false),
+		fileEntry(filepath.Join(blocker, "sub", "fail.txt"), "never", false),
+	}))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !IsExecutionError(err) || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected rolled back execution error, got %v", err)
+	}
+
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Error("created file should have been removed")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new")); !os.IsNotExist(err) {
+		t.Error("created directories should have been removed")
+	}
+	data, err := os.ReadFile(existing)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "original" {
+		t.Errorf("existing file = %q, want restored %q", string(data), "original")
+	}
+}
+
+func TestMultiFileWriteTool_PathValidatorAbortsBatch(t *testing.T) {
+	dir := t.TempDir()
+	registry := NewRegistryWithOptions(WithTools(NewMultiFileWriteTool()))
+	exec := NewExecutor(registry, WithSecurityPolicy(DefaultPathValidator()))
+
+	first := filepath.Join(dir, "main.go")
+	_, err := exec.Execute(context.Background(), "multi_file_write", NewInput().WithParam("files", []any{
+		fileEntry(first, "package main\n", false),
+		fileEntry(filepath.Join(dir, ".env"), "SECRET=1", false),
+	}))
+	if !IsSecurityViolationError(err) {
+		t.Fatalf("expected SecurityViolationError, got %v", err)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Error("no file should be written when a path is blocked")
+	}
+}
+
+func TestMultiFileWriteTool_FilesystemRoot(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	registry := NewRegistryWithOptions(WithTools(NewMultiFileWriteTool()))
+	exec := NewExecutor(registry, WithFilesystemRoot(root))
+	ctx := context.Background()
+
+	files := []any{fileEntry("pkg/a.go", "package pkg\n", false)}
+	if _, err := exec.Execute(ctx, "multi_file_write", NewInput().WithParam("files", files)); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "pkg", "a.go")); err != nil {
+		t.Errorf("expected file inside root: %v", err)
+	}
+	if files[0].(map[string]any)["path"] != "pkg/a.go" {
+		t.Error("caller's input should not be modified")
+	}
+
+	_, err := exec.Execute(ctx, "multi_file_write", NewInput().WithParam("files", []any{
+		fileEntry("ok.txt", "ok", false),
+		fileEntry("../evil.txt", "x", false),
+	}))
+	if !IsSecurityViolationError(err) {
+		t.Fatalf("expected SecurityViolationError, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "ok.txt")); !os.IsNotExist(err) {
+		t.Error("no file should be written when a path escapes the root")
+	}
+}
+
+func TestMultiFileWriteTool_Validation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+
+	tests := []struct {
+		name  string
+		files any
+	}{
+		{"missing files", nil},
+		{"not an array", "a.txt"},
+		{"empty array", []any{}},
+		{"entry not an object", []any{"a.txt"}},
+		{"missing path", []any{map[string]any{"content": "x"}}},
+		{"missing content", []any{map[string]any{"path": path}}},
+		{"append not a bool", []any{map[string]any{"path": path, "content": "x", "append": "yes"}}},
+		{"directory target", []any{fileEntry(dir, "x", false)}},
+		{"duplicate path", []any{fileEntry(path, "x", false), fileEntry(path, "y", true)}},
+	}
+
+	tool := NewMultiFileWriteTool()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := NewInput()
+			if tt.files != nil {
+				input.WithParam("files", tt.files)
+			}
+			_, err := tool.Execute(context.Background(), input)
+			if !IsValidationError(err) {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("no file should be written on validation failure")
+	}
+}
+
+func TestMultiFileWriteTool_ContentLimit(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "small.txt")
+
+	tool := NewMultiFileWriteTool(WithFileWriteMaxBytes(3))
+	_, err := tool.Execute(context.Background(), NewInput().WithParam("files", []any{
+		fileEntry(first, "ok", false),
+		fileEntry(filepath.Join(dir, "big.txt"), "too large", false),
+	}))
+	if !IsValidationError(err) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Error("no file should be written when an entry is too large")
+	}
+}
+
+func TestMultiFileWriteTool_ConfirmationSummary(t *testing.T) {
+	tool := NewMultiFileWriteTool()
+	if !tool.RequiresConfirmation(nil) {
+		t.Fatal("RequiresConfirmation() = false, want true")
+	}
+
+	summary := tool.ConfirmationSummary(map[string]any{"files": []any{
+		fileEntry("cmd/main.go", "package main", false),
+		fileEntry("CHANGELOG.md", "- entry", true),
+	}})
+	for _, want := range []string{"Write 2 file(s)", "cmd/main.go (write)", "CHANGELOG.md (append)"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary missing %q:\n%s", want, summary)
+		}
+	}
+}
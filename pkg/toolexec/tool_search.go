@@ -4,16 +4,120 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// SearchToolName is the name the SearchTool is registered under.
+const SearchToolName = "search"
+
+// SearchResult is a single ranked match reported by the SearchTool in
+// Output.Result["results"].
+type SearchResult struct {
+	// Title identifies the match, as "path:line" for file searches.
+	Title string `json:"title"`
+
+	// URL locates the matched document; for file searches it is the path.
+	URL string `json:"url"`
+
+	// Snippet is the matching text.
+	Snippet string `json:"snippet"`
+
+	// Score ranks the match; higher scores are more relevant.
+	Score float64 `json:"score"`
+}
+
+// ParseSearchResults extracts the structured results from a SearchTool
+// output. It accepts both the in-process []SearchResult value and the
+// generic form produced by decoding the output from JSON.
+func ParseSearchResults(output *Output) ([]SearchResult, error) {
+	if output == nil || output.Result == nil {
+		return nil, errors.New("search output has no results")
+	}
+	raw, ok := output.Result["results"]
+	if !ok {
+		return nil, errors.New("search output has no results")
+	}
+
+	switch results := raw.(type) {
+	case []SearchResult:
+		return results, nil
+	case []any:
+		parsed := make([]SearchResult, 0, len(results))
+		for i, item := range results {
+			fields, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("search result %d: expected an object, got %T", i, item)
+			}
+			result, err := parseSearchResult(fields)
+			if err != nil {
+				return nil, fmt.Errorf("search result %d: %w", i, err)
+			}
+			parsed = append(parsed, result)
+		}
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("search results: expected a list, got %T", raw)
+	}
+}
+
+func parseSearchResult(fields map[string]any) (SearchResult, error) {
+	var result SearchResult
+	stringFields := []struct {
+		key string
+		dst *string
+	}{
+		{"title", &result.Title},
+		{"url", &result.URL},
+		{"snippet", &result.Snippet},
+	}
+	for _, field := range stringFields {
+		value, ok := fields[field.key]
+		if !ok {
+			continue
+		}
+		str, ok := value.(string)
+		if !ok {
+			return SearchResult{}, fmt.Errorf("%s: expected a string, got %T", field.key, value)
+		}
+		*field.dst = str
+	}
+	if result.Title == "" && result.URL == "" {
+		return SearchResult{}, errors.New("missing title and url")
+	}
+
+	switch score := fields["score"].(type) {
+	case nil:
+	case float64:
+		result.Score = score
+	case int:
+		result.Score = float64(score)
+	case json.Number:
+		value, err := score.Float64()
+		if err != nil {
+			return SearchResult{}, fmt.Errorf("score: %w", err)
+		}
+		result.Score = value
+	default:
+		return SearchResult{}, fmt.Errorf("score: expected a number, got %T", score)
+	}
+	return result, nil
+}
+
 // SearchTool searches for a pattern in files.
+//
+// Matching lines are returned as "path:line:text" in Output.Data and as
+// ranked SearchResult values in Output.Result["results"]. A line scores one
+// point per occurrence of the pattern, and results with equal scores keep
+// the order in which they were found.
 type SearchTool struct {
 	maxFileBytes  int64
 	maxOutputSize int
@@ -66,7 +170,7 @@ func WithSearchDefaultPath(path string) SearchToolOption {
 
 // Name returns the tool name.
 func (t *SearchTool) Name() string {
-	return "search"
+	return SearchToolName
 }
 
 // Description returns a human-readable description.
@@ -100,18 +204,24 @@ func (t *SearchTool) Execute(ctx context.Context, input *Input) (*Output, error)
 		matchType = strings.ToLower(rawType)
 	}
 
-	var matcher func(string) bool
+	// matcher returns the number of occurrences of the pattern in a line
+	var matcher func(string) int
 	switch matchType {
 	case "literal":
-		matcher = func(line string) bool {
-			return strings.Contains(line, pattern)
+		matcher = func(line string) int {
+			if pattern == "" {
+				return 1
+			}
+			return strings.Count(line, pattern)
 		}
 	case "regex", "regexp":
 		re, err := regexp.Compile(pattern)
 		if err != nil {
 			return nil, NewValidationErrorForField(t.Name(), "pattern", err.Error())
 		}
-		matcher = re.MatchString
+		matcher = func(line string) int {
+			return len(re.FindAllStringIndex(line, -1))
+		}
 	default:
 		return nil, NewValidationErrorForField(t.Name(), "type", "must be 'literal' or 'regex'")
 	}
@@ -122,21 +232,31 @@ func (t *SearchTool) Execute(ctx context.Context, input *Input) (*Output, error)
 	}
 
 	var buf bytes.Buffer
+	var results []SearchResult
 	truncated := false
 	matches := 0
 	files := 0
 	skipped := 0
 
-	appendLine := func(line string) bool {
-		data := []byte(line)
-		return appendBytesWithLimit(&buf, data, t.maxOutputSize)
+	appendMatch := func(path string, lineNum int, line string, score int) bool {
+		location := path + ":" + strconv.Itoa(lineNum)
+		if appendBytesWithLimit(&buf, []byte(location+":"+line+"\n"), t.maxOutputSize) {
+			return true
+		}
+		results = append(results, SearchResult{
+			Title:   location,
+			URL:     path,
+			Snippet: strings.TrimSpace(line),
+			Score:   float64(score),
+		})
+		return false
 	}
 
 	if !info.IsDir() {
 		if info.Size() > t.maxFileBytes {
 			return nil, NewValidationErrorForField(t.Name(), "path", "file exceeds size limit")
 		}
-		fileMatches, err := t.searchFile(ctx, path, matcher, appendLine)
+		fileMatches, err := t.searchFile(ctx, path, matcher, appendMatch)
 		if err != nil {
 			if errors.Is(err, errSearchTruncated) {
 				truncated = true
@@ -148,7 +268,7 @@ func (t *SearchTool) Execute(ctx context.Context, input *Input) (*Output, error)
 			matches += fileMatches
 			files++
 		}
-		return buildSearchOutput(&buf, truncated, matches, files, skipped), nil
+		return buildSearchOutput(&buf, results, truncated, matches, files, skipped), nil
 	}
 
 	err = filepath.WalkDir(path, func(current string, entry os.DirEntry, walkErr error) error {
@@ -171,7 +291,7 @@ func (t *SearchTool) Execute(ctx context.Context, input *Input) (*Output, error)
 			return nil
 		}
 
-		fileMatches, err := t.searchFile(ctx, current, matcher, appendLine)
+		fileMatches, err := t.searchFile(ctx, current, matcher, appendMatch)
 		if err != nil {
 			if errors.Is(err, errSearchTruncated) {
 				truncated = true
@@ -199,7 +319,7 @@ func (t *SearchTool) Execute(ctx context.Context, input *Input) (*Output, error)
 		truncated = true
 	}
 
-	return buildSearchOutput(&buf, truncated, matches, files, skipped), nil
+	return buildSearchOutput(&buf, results, truncated, matches, files, skipped), nil
 }
 
 var errSearchTruncated = errors.New("search output truncated")
@@ -207,8 +327,8 @@ var errSearchTruncated = errors.New("search output truncated")
 func (t *SearchTool) searchFile(
 	ctx context.Context,
 	path string,
-	matcher func(string) bool,
-	appendLine func(string) bool,
+	matcher func(string) int,
+	appendMatch func(path string, lineNum int, line string, score int) bool,
 ) (int, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -232,10 +352,10 @@ func (t *SearchTool) searchFile(
 			break
 		}
 		lineNum++
-		if matcher(line) {
+		trimmed := strings.TrimRight(line, "\r\n")
+		if score := matcher(trimmed); score > 0 {
 			matches++
-			trimmed := strings.TrimRight(line, "\r\n")
-			if appendLine(filepath.Clean(path) + ":" + strconv.Itoa(lineNum) + ":" + trimmed + "\n") {
+			if appendMatch(filepath.Clean(path), lineNum, trimmed, score) {
 				return matches, errSearchTruncated
 			}
 		}
@@ -247,9 +367,17 @@ func (t *SearchTool) searchFile(
 	return matches, nil
 }
 
-func buildSearchOutput(buf *bytes.Buffer, truncated bool, matches, files, skipped int) *Output {
+func buildSearchOutput(buf *bytes.Buffer, results []SearchResult, truncated bool, matches, files, skipped int) *Output {
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if results == nil {
+		results = []SearchResult{}
+	}
+
 	output := NewOutput().WithData(buf.Bytes())
 	output.Truncated = truncated
+	output.Result["results"] = results
 	output.Result["matches"] = matches
 	output.Result["files"] = files
 	if skipped > 0 {
@@ -2,6 +2,7 @@ package toolexec
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -73,3 +74,106 @@ func TestSearchTool_InvalidType(t *testing.T) {
 		t.Fatalf("expected validation error, got %v", err)
 	}
 }
+
+func TestSearchTool_RankedResults(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(path, []byte("one needle\nneedle needle needle\nno match\n  needle twice needle\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tool := NewSearchTool()
+	output, err := tool.Execute(context.Background(), NewInput().
+		WithParam("pattern", "needle").
+		WithParam("path", dir))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	results, err := ParseSearchResults(output)
+	if err != nil {
+		t.Fatalf("ParseSearchResults() error = %v", err)
+	}
+	want := []SearchResult{
+		{Title: filepath.Clean(path) + ":2", URL: filepath.Clean(path), Snippet: "needle needle needle", Score: 3},
+		{Title: filepath.Clean(path) + ":4", URL: filepath.Clean(path), Snippet: "needle twice needle", Score: 2},
+		{Title: filepath.Clean(path) + ":1", URL: filepath.Clean(path), Snippet: "one needle", Score: 1},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+}
+
+func TestSearchTool_NoMatchesHasEmptyResults(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("alpha\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	output, err := NewSearchTool().Execute(context.Background(), NewInput().
+		WithParam("pattern", "needle").
+		WithParam("path", dir))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	results, err := ParseSearchResults(output)
+	if err != nil || len(results) != 0 {
+		t.Errorf("ParseSearchResults() = %v, %v; want no results", results, err)
+	}
+}
+
+func TestParseSearchResults_JSONRoundTrip(t *testing.T) {
+	output := NewOutput()
+	output.Result["results"] = []SearchResult{
+		{Title: "a.go:3", URL: "a.go", Snippet: "func a()", Score: 2},
+	}
+	data, err := json.Marshal(output.Result)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	decoded := NewOutput()
+	if err := json.Unmarshal(data, &decoded.Result); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	results, err := ParseSearchResults(decoded)
+	if err != nil {
+		t.Fatalf("ParseSearchResults() error = %v", err)
+	}
+	if len(results) != 1 || results[0] != output.Result["results"].([]SearchResult)[0] {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestParseSearchResults_Malformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		output *Output
+	}{
+		{"nil output", nil},
+		{"missing results", NewOutput()},
+		{"not a list", &Output{Result: map[string]any{"results": "a.go:1"}}},
+		{"not an object", &Output{Result: map[string]any{"results": []any{"a.go:1"}}}},
+		{"bad title", &Output{Result: map[string]any{"results": []any{
+			map[string]any{"title": 42},
+		}}}},
+		{"bad score", &Output{Result: map[string]any{"results": []any{
+			map[string]any{"title": "a.go:1", "score": "high"},
+		}}}},
+		{"no title or url", &Output{Result: map[string]any{"results": []any{
+			map[string]any{"snippet": "text"},
+		}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if results, err := ParseSearchResults(tt.output); err == nil {
+				t.Errorf("expected an error, got %+v", results)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package toolexec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// DefaultSQLiteMaxRows is the maximum number of rows returned by a
+// SQLiteQueryTool query; further rows are dropped and the output is marked
+// as truncated.
+const DefaultSQLiteMaxRows = 1000
+
+// sqliteReadStatements are the statements accepted in read-only mode.
+var sqliteReadStatements = map[string]bool{
+	"SELECT": true,
+	"VALUES": true,
+}
+
+// sqliteMainKeywords start the statement that follows the common table
+// expressions of a WITH clause.
+var sqliteMainKeywords = map[string]bool{
+	"SELECT":  true,
+	"VALUES":  true,
+	"INSERT":  true,
+	"UPDATE":  true,
+	"DELETE":  true,
+	"REPLACE": true,
+}
+
+// SQLiteQueryTool runs a SQL query against a local SQLite database file.
+//
+// Queries run through the sqlite3 command-line shell (version 3.37 or
+// later, for -safe), which must be on the PATH, in safe mode, so statements
+// cannot reach outside the database. In read-only mode only a single
+// SELECT or VALUES statement (optionally after WITH) is accepted, and the
+// database is also opened read-only so writes hidden in a query still fail.
+//
+// Rows are returned as Result["rows"], a list of objects keyed by column
+// name, with the column names in order as Result["columns"].
+type SQLiteQueryTool struct {
+	dbPath   string
+	readOnly bool
+	binary   string
+	maxRows  int
+}
+
+// NewSQLiteQueryTool creates a SQLiteQueryTool for the database at dbPath.
+func NewSQLiteQueryTool(dbPath string, readOnly bool) *SQLiteQueryTool {
+	return &SQLiteQueryTool{
+		dbPath:   dbPath,
+		readOnly: readOnly,
+		binary:   "sqlite3",
+		maxRows:  DefaultSQLiteMaxRows,
+	}
+}
+
+// Name returns the tool name.
+func (t *SQLiteQueryTool) Name() string {
+	return "sqlite_query"
+}
+
+// Description returns a human-readable description.
+func (t *SQLiteQueryTool) Description() string {
+	if t.readOnly {
+		return "Runs a read-only SQL query against a local SQLite database"
+	}
+	return "Runs a SQL query against a local SQLite database"
+}
+
+// RequiresConfirmation returns true for statements that may write to the
+// database.
+func (t *SQLiteQueryTool) RequiresConfirmation(args map[string]any) bool {
+	query, _ := args["query"].(string)
+	return !isSingleSQLStatement(query) || !isSQLiteReadStatement(query)
+}
+
+// Execute runs the "query" param and returns the resulting rows.
+func (t *SQLiteQueryTool) Execute(ctx context.Context, input *Input) (*Output, error) {
+	query, err := requireStringArg(t.Name(), argsFromInput(input), "query")
+	if err != nil {
+		return nil, err
+	}
+	query = strings.TrimSpace(query)
+
+	// A leading dot would run a sqlite3 shell command instead of SQL
+	if strings.HasPrefix(query, ".") {
+		return nil, NewValidationErrorForField(t.Name(), "query", "must be a SQL statement")
+	}
+	if !isSingleSQLStatement(query) {
+		return nil, NewValidationErrorForField(t.Name(), "query", "must be a single statement")
+	}
+	if t.readOnly && !isSQLiteReadStatement(query) {
+		return nil, NewValidationErrorForField(t.Name(), "query", "only SELECT statements are allowed in read-only mode")
+	}
+
+	binary, err := exec.LookPath(t.binary)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), fmt.Errorf("sqlite3 shell not found: %w", err))
+	}
+
+	args := []string{"-safe", "-bail", "-json"}
+	if t.readOnly {
+		args = append(args, "-readonly")
+	}
+	args = append(args, t.dbPath, query)
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+
+	columns, rows, err := parseSQLiteRows(stdout.Bytes())
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+
+	output := NewOutput()
+	if len(rows) > t.maxRows {
+		rows = rows[:t.maxRows]
+		output.Truncated = true
+	}
+
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil, NewExecutionErrorWithCause(t.Name(), err)
+	}
+	output.Result["columns"] = columns
+	output.Result["rows"] = rows
+	output.Result["row_count"] = len(rows)
+	output.Metadata[OutputFormatKey] = OutputFormatJSON
+	return output.WithData(data), nil
+}
+
+// isSQLiteReadStatement reports whether query is a statement accepted in
+// read-only mode: a SELECT or VALUES, possibly after a WITH clause. Only
+// the statement keyword decides, so column names, function calls and
+// string literals such as replace(...) or 'delete' do not matter; the
+// database is opened read-only as well, so this is not the only guard.
+func isSQLiteReadStatement(query string) bool {
+	words, _ := scanSQL(query)
+	if len(words) == 0 {
+		return false
+	}
+	keyword := words[0]
+	if keyword == "WITH" {
+		// The common table expressions are parenthesized, so the first
+		// statement keyword outside them is the main statement
+		keyword = ""
+		for _, word := range words[1:] {
+			if sqliteMainKeywords[word] {
+				keyword = word
+				break
+			}
+		}
+	}
+	return sqliteReadStatements[keyword]
+}
+
+// isSingleSQLStatement reports whether query holds at most one statement,
+// ignoring semicolons inside quotes or comments and a trailing semicolon.
+func isSingleSQLStatement(query string) bool {
+	_, single := scanSQL(query)
+	return single
+}
+
+// scanSQL returns the upper-cased words of the first statement in query
+// that are outside parentheses, quotes and comments, and whether anything
+// but whitespace, comments and semicolons follows that statement.
+func scanSQL(query string) (words []string, single bool) {
+	single = true
+	depth := 0
+	ended := false
+	var word strings.Builder
+	endWord := func() {
+		if word.Len() > 0 {
+			words = append(words, strings.ToUpper(word.String()))
+			word.Reset()
+		}
+	}
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		isWordChar := 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c == '$' ||
+			'0' <= c && c <= '9'
+		if !isWordChar {
+			endWord()
+		}
+
+		switch {
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return words, single
+			}
+			i += end
+			continue
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return words, single
+			}
+			i += end + 3
+			continue
+		case strings.ContainsRune(" \t\r\n", rune(c)):
+			continue
+		case ended && c != ';':
+			return words, false
+		}
+
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(query[i+1:], closing)
+			if end < 0 {
+				return words, single
+			}
+			i += end + 1
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ';':
+			ended = true
+		case isWordChar && depth == 0:
+			word.WriteByte(c)
+		}
+	}
+	endWord()
+	return words, single
+}
+
+// parseSQLiteRows decodes the JSON output of the sqlite3 shell, keeping the
+// column order of the result set. Empty output means no rows.
+func parseSQLiteRows(data []byte) ([]string, []map[string]any, error) {
+	columns := []string{}
+	rows := []map[string]any{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return columns, rows, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if _, err := dec.Token(); err != nil { // [
+		return nil, nil, fmt.Errorf("invalid sqlite3 output: %w", err)
+	}
+	for dec.More() {
+		if _, err := dec.Token(); err != nil { // {
+			return nil, nil, fmt.Errorf("invalid sqlite3 output: %w", err)
+		}
+		row := make(map[string]any)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid sqlite3 output: %w", err)
+			}
+			name, _ := tok.(string)
+			var value any
+			if err := dec.Decode(&value); err != nil {
+				return nil, nil, fmt.Errorf("invalid sqlite3 output: %w", err)
+			}
+			if len(rows) == 0 {
+				columns = append(columns, name)
+			}
+			row[name] = value
+		}
+		if _, err := dec.Token(); err != nil { // }
+			return nil, nil, fmt.Errorf("invalid sqlite3 output: %w", err)
+		}
+		rows = append(rows, row)
+	}
+	if _, err := dec.Token(); err != nil && !errors.Is(err, io.EOF) { // ]
+		return nil, nil, fmt.Errorf("invalid sqlite3 output: %w", err)
+	}
+	return columns, rows, nil
+}
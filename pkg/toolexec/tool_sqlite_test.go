@@ -0,0 +1,202 @@
+package toolexec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newTestSQLiteDB creates a database with a small "users" table, skipping
+// the test when the sqlite3 shell is not installed.
+func newTestSQLiteDB(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("sqlite3 shell not installed")
+	}
+
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	setup := "CREATE TABLE users (id INTEGER, name TEXT, score REAL);" +
+		"INSERT INTO users VALUES (1, 'ada', 9.5), (2, 'linus', NULL);"
+	if out, err := exec.Command("sqlite3", dbPath, setup).CombinedOutput(); err != nil {
+		t.Fatalf("creating test database: %v: %s", err, out)
+	}
+	return dbPath
+}
+
+func sqliteQuery(t *testing.T, tool *SQLiteQueryTool, query string) (*Output, error) {
+	t.Helper()
+	return tool.Execute(context.Background(), NewInput().WithParam("query", query))
+}
+
+func TestSQLiteQueryTool_Select(t *testing.T) {
+	tool := NewSQLiteQueryTool(newTestSQLiteDB(t), true)
+
+	output, err := sqliteQuery(t, tool, "SELECT id, name, score FROM users ORDER BY id;")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	columns, ok := output.Result["columns"].([]string)
+	if !ok || len(columns) != 3 || columns[0] != "id" || columns[1] != "name" || columns[2] != "score" {
+		t.Errorf("columns = %v, want [id name score]", output.Result["columns"])
+	}
+	rows, ok := output.Result["rows"].([]map[string]any)
+	if !ok || len(rows) != 2 {
+		t.Fatalf("rows = %v, want 2 rows", output.Result["rows"])
+	}
+	if rows[0]["name"] != "ada" || rows[0]["id"] != json.Number("1") {
+		t.Errorf("first row = %v", rows[0])
+	}
+	if rows[1]["score"] != nil {
+		t.Errorf("NULL should decode as nil, got %v", rows[1]["score"])
+	}
+	if output.Result["row_count"] != 2 || output.Metadata[OutputFormatKey] != OutputFormatJSON {
+		t.Errorf("unexpected output: %+v", output)
+	}
+}
+
+func TestSQLiteQueryTool_EmptyResult(t *testing.T) {
+	tool := NewSQLiteQueryTool(newTestSQLiteDB(t), true)
+
+	output, err := sqliteQuery(t, tool, "SELECT * FROM users WHERE id = 42")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if rows := output.Result["rows"].([]map[string]any); len(rows) != 0 {
+		t.Errorf("expected no rows, got %v", rows)
+	}
+}
+
+func TestSQLiteQueryTool_ReadOnlyRejectsWrites(t *testing.T) {
+	dbPath := newTestSQLiteDB(t)
+	tool := NewSQLiteQueryTool(dbPath, true)
+
+	tests := []string{
+		"UPDATE users SET name = 'x'",
+		"-- comment\nDELETE FROM users",
+		"WITH x AS (SELECT 1) UPDATE users SET name = 'x'",
+		"SELECT 1; DROP TABLE users",
+		"SELECT ';'; DROP TABLE users",
+		".shell echo hi",
+	}
+	for _, query := range tests {
+		_, err := sqliteQuery(t, tool, query)
+		var valErr *ValidationError
+		if !errors.As(err, &valErr) {
+			t.Errorf("query %q: expected ValidationError, got %v", query, err)
+		}
+	}
+
+	output, err := sqliteQuery(t, tool, "SELECT replace(name, 'x', 'y') AS name FROM users WHERE name <> 'delete' AND id = 1")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if rows := output.Result["rows"].([]map[string]any); len(rows) != 1 || rows[0]["name"] != "ada" {
+		t.Errorf("database should be unchanged, got %v", rows)
+	}
+}
+
+// TestSQLiteQueryTool_ShellArgs runs against a stand-in for the sqlite3
+// shell, so the way it is invoked is tested even where sqlite3 is not
+// installed.
+func TestSQLiteQueryTool_ShellArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stand-in shell is a POSIX script")
+	}
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + argsFile + "\n" +
+		`echo '[{"id":1,"name":"ada"}]'` + "\n"
+	fake := filepath.Join(dir, "sqlite3")
+	if err := os.WriteFile(fake, []byte(script), 0o755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	for _, readOnly := range []bool{true, false} {
+		tool := NewSQLiteQueryTool("app.db", readOnly)
+		tool.binary = fake
+
+		output, err := sqliteQuery(t, tool, "SELECT id, name FROM users")
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if rows := output.Result["rows"].([]map[string]any); len(rows) != 1 || rows[0]["name"] != "ada" {
+			t.Errorf("rows = %v", rows)
+		}
+
+		data, err := os.ReadFile(argsFile)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		args := strings.Split(strings.TrimSpace(string(data)), "\n")
+		if !slices.Contains(args, "-safe") || args[len(args)-2] != "app.db" || args[len(args)-1] != "SELECT id, name FROM users" {
+			t.Errorf("readOnly=%v: args = %q", readOnly, args)
+		}
+		if got := slices.Contains(args, "-readonly"); got != readOnly {
+			t.Errorf("readOnly=%v: -readonly passed = %v", readOnly, got)
+		}
+	}
+}
+
+func TestSQLiteQueryTool_WritableMode(t *testing.T) {
+	tool := NewSQLiteQueryTool(newTestSQLiteDB(t), false)
+
+	if _, err := sqliteQuery(t, tool, "UPDATE users SET name = 'grace' WHERE id = 1"); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	output, err := sqliteQuery(t, tool, "SELECT name FROM users WHERE id = 1")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if rows := output.Result["rows"].([]map[string]any); rows[0]["name"] != "grace" {
+		t.Errorf("update not applied, got %v", rows)
+	}
+}
+
+func TestSQLiteQueryTool_RequiresConfirmation(t *testing.T) {
+	tool := NewSQLiteQueryTool("unused.db", false)
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT * FROM users", false},
+		{"  with t as (select 1) select * from t", false},
+		{"UPDATE users SET name = 'x'", true},
+		{"WITH t AS (SELECT 1) DELETE FROM users", true},
+		{"INSERT INTO users VALUES (3, 'x', 1)", true},
+		{"", true},
+		// Write keywords outside the statement keyword are reads
+		{"SELECT last_update, deleted FROM users", false},
+		{"SELECT replace(name, 'a', 'b') FROM users", false},
+		{"SELECT * FROM log WHERE action = 'delete'", false},
+		{"WITH updates AS (SELECT * FROM log) SELECT count(*) FROM updates", false},
+		{"WITH RECURSIVE n(x) AS (SELECT 1 UNION SELECT x + 1 FROM n WHERE x < 3) SELECT x FROM n", false},
+		{"/* UPDATE */ SELECT 1; -- done", false},
+		{"VALUES (1), (2)", false},
+		{"SELECT 1; DELETE FROM users", true},
+		{"REPLACE INTO users VALUES (1, 'x', 1)", true},
+	}
+	for _, tt := range tests {
+		if got := tool.RequiresConfirmation(map[string]any{"query": tt.query}); got != tt.want {
+			t.Errorf("RequiresConfirmation(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSQLiteQueryTool_InvalidSQL(t *testing.T) {
+	tool := NewSQLiteQueryTool(newTestSQLiteDB(t), true)
+
+	_, err := sqliteQuery(t, tool, "SELECT * FROM missing_table")
+	var execErr *ExecutionError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("expected ExecutionError, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package toolexec
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewInput_DefaultsToNoFiles(t *testing.T) {
+	input := NewInput()
+	if input.Files == nil || len(input.Files) != 0 {
+		t.Errorf("Files = %#v, want an empty slice", input.Files)
+	}
+	if files := input.GetFiles(); files == nil || len(files) != 0 {
+		t.Errorf("GetFiles() = %#v, want an empty slice", files)
+	}
+}
+
+func TestInput_WithFiles(t *testing.T) {
+	report := FileRef{Name: "report.pdf", MIME: "application/pdf", Path: "/tmp/report.pdf"}
+	upload := FileRef{Name: "photo.png", MIME: "image/png", ID: "/contrib_service/ttl_1d/abc"}
+
+	input := NewInput().
+		WithParam("path", "/tmp/legacy.txt").
+		WithFiles(report).
+		WithFiles(upload)
+
+	files := input.GetFiles()
+	if len(files) != 2 || files[0] != report || files[1] != upload {
+		t.Errorf("GetFiles() = %+v, want [%+v %+v]", files, report, upload)
+	}
+	// Params keep working alongside files
+	if input.GetParamString("path") != "/tmp/legacy.txt" {
+		t.Errorf("path param = %q", input.GetParamString("path"))
+	}
+}
+
+func TestInput_GetFiles_ZeroValue(t *testing.T) {
+	input := &Input{}
+	if files := input.GetFiles(); files == nil || len(files) != 0 {
+		t.Errorf("GetFiles() = %#v, want an empty slice", files)
+	}
+
+	input.WithFiles(FileRef{Name: "a.txt"})
+	if len(input.GetFiles()) != 1 {
+		t.Errorf("WithFiles on a zero Input should add the file, got %+v", input.Files)
+	}
+}
+
+func TestExecutor_PassesFilesToTool(t *testing.T) {
+	registry := NewRegistry()
+	var got []FileRef
+	tool := NewMockTool("files", "Records its files").WithExecuteFunc(
+		func(ctx context.Context, input *Input) (*Output, error) {
+			got = input.GetFiles()
+			return NewOutput(), nil
+		},
+	)
+	if err := registry.Register(tool); err != nil {
+		t.Fatalf("Failed to register tool: %v", err)
+	}
+
+	ref := FileRef{Name: "notes.txt", MIME: "text/plain", Path: "/tmp/notes.txt"}
+	exec := NewExecutor(registry)
+	if _, err := exec.Execute(context.Background(), "files", NewInput().WithFiles(ref)); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != ref {
+		t.Errorf("tool received files %+v, want [%+v]", got, ref)
+	}
+}